@@ -1,3 +1,7 @@
 package api
 
+// Сгенерированный api.gen.go импортирует runtime; пустой импорт удерживает
+// его версию в go.mod.
+import _ "github.com/oapi-codegen/runtime"
+
 //go:generate go tool oapi-codegen -package api -generate types,gin -o ./api.gen.go ./openapi.yaml
@@ -0,0 +1,3 @@
+package storev1
+
+//go:generate protoc -I ../.. --go_out=../.. --go_opt=paths=source_relative --go-grpc_out=../.. --go-grpc_opt=paths=source_relative store/v1/product.proto store/v1/order.proto
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"backend-store/internal/app"
+	"backend-store/internal/bulk"
+	"backend-store/internal/models"
+	"backend-store/internal/service"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+)
+
+const usage = `usage:
+  store                                 start HTTP server
+  store import products -file <path> [-format csv|ndjson] [-dry-run] [-batch-size n]
+  store export products -out <path> [-format csv|ndjson|xlsx]
+  store export orders -out <path> [-format csv|ndjson|xlsx] [-from date] [-to date] [-status s]
+  store stock reconcile
+  store cart purge
+  store audit purge
+  store stream token [-customer id] [-ttl duration]`
+
+// runCommand выполняет подкоманду CLI вместо запуска HTTP-сервера.
+func runCommand(application *app.App, args []string) error {
+	if len(args) < 2 {
+		return errors.New(usage)
+	}
+
+	switch args[0] + " " + args[1] {
+	case "import products":
+		return importProducts(application, args[2:])
+	case "export products", "export orders":
+		return export(application, args[1], args[2:])
+	case "stock reconcile":
+		return reconcileStock(application)
+	case "cart purge":
+		return purgeCarts(application)
+	case "audit purge":
+		return purgeAudit(application)
+	case "stream token":
+		return issueStreamToken(application, args[2:])
+	default:
+		return fmt.Errorf("unknown command %q\n%s", args[0]+" "+args[1], usage)
+	}
+}
+
+func importProducts(application *app.App, args []string) error {
+	fs := flag.NewFlagSet("import products", flag.ContinueOnError)
+	path := fs.String("file", "", "path to CSV or NDJSON file")
+	formatName := fs.String("format", "", "csv or ndjson (detected from extension by default)")
+	dryRun := fs.Bool("dry-run", false, "validate rows without writing")
+	batchSize := fs.Int("batch-size", 100, "rows per transaction")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *path == "" {
+		return errors.New("-file is required")
+	}
+
+	format, err := resolveFormat(*formatName, *path)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(*path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader, err := bulk.NewProductReader(file, format)
+	if err != nil {
+		return err
+	}
+
+	report, err := application.Services.ProductService.ImportProducts(cliContext(), reader, service.ImportOptions{
+		DryRun:    *dryRun,
+		BatchSize: *batchSize,
+	})
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
+
+func export(application *app.App, entity string, args []string) error {
+	fs := flag.NewFlagSet("export "+entity, flag.ContinueOnError)
+	path := fs.String("out", "", "output file path")
+	formatName := fs.String("format", "", "csv, ndjson or xlsx (detected from extension by default)")
+	status := fs.String("status", "", "order status (orders only)")
+	from := fs.String("from", "", "created at or after, RFC3339 or YYYY-MM-DD (orders only)")
+	to := fs.String("to", "", "created before, RFC3339 or YYYY-MM-DD inclusive (orders only)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *path == "" {
+		return errors.New("-out is required")
+	}
+
+	format, err := resolveFormat(*formatName, *path)
+	if err != nil {
+		return err
+	}
+
+	filter, err := models.ParseOrderFilter(*status, *from, *to)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(*path)
+	if err != nil {
+		return err
+	}
+
+	ctx := cliContext()
+	if entity == "orders" {
+		err = application.Services.OrderService.ExportOrders(ctx, filter, format, file)
+	} else {
+		err = application.Services.ProductService.ExportProducts(ctx, format, file)
+	}
+	if err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// reconcileStock печатает отчет о сверке остатков с журналом движения и
+// завершается с ошибкой, если найдены расхождения.
+func reconcileStock(application *app.App) error {
+	report, err := application.Services.WarehouseService.ReconcileStock(cliContext())
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return err
+	}
+
+	if len(report.Drifts) > 0 {
+		return fmt.Errorf("stock drift detected: %d discrepancies", len(report.Drifts))
+	}
+	return nil
+}
+
+// purgeCarts удаляет просроченные корзины; рассчитана на запуск по расписанию.
+func purgeCarts(application *app.App) error {
+	deleted, err := application.Services.CartService.PurgeExpiredCarts(cliContext())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("deleted %d expired carts\n", deleted)
+	return nil
+}
+
+// purgeAudit удаляет записи журнала аудита старше AUDIT_RETENTION;
+// рассчитана на запуск по расписанию.
+func purgeAudit(application *app.App) error {
+	deleted, err := application.Services.AuditService.PurgeAuditLog(cliContext())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("deleted %d audit entries\n", deleted)
+	return nil
+}
+
+// issueStreamToken печатает токен потока заказов: без -customer - токен
+// сотрудника, видящего все заказы.
+func issueStreamToken(application *app.App, args []string) error {
+	fs := flag.NewFlagSet("stream token", flag.ContinueOnError)
+	customerID := fs.Int("customer", 0, "customer ID to restrict the token to")
+	ttl := fs.Duration("ttl", 24*time.Hour, "token lifetime")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	// Случайный секрет INSECURE_DEV_MODE живет только в процессе сервера;
+	// токен с ним сервер не примет.
+	if os.Getenv("ORDER_STREAM_SECRET") == "" {
+		return errors.New("ORDER_STREAM_SECRET is not set")
+	}
+	if *customerID < 0 || *ttl <= 0 {
+		return errors.New("-customer must not be negative and -ttl must be positive")
+	}
+
+	token, err := service.IssueStreamToken(application.Config.OrderStreamSecret,
+		models.StreamAccess{CustomerID: *customerID}, time.Now().Add(*ttl))
+	if err != nil {
+		return err
+	}
+	fmt.Println(token)
+	return nil
+}
+
+// cliContext помечает изменения, сделанные из командной строки, в журнале
+// движения товара.
+func cliContext() context.Context {
+	return service.WithActor(context.Background(), "cli")
+}
+
+func resolveFormat(name, path string) (bulk.Format, error) {
+	if name != "" {
+		return bulk.ParseFormat(name)
+	}
+	return bulk.DetectFormat(path)
+}
@@ -3,13 +3,20 @@ package main
 import (
 	"backend-store/config"
 	"backend-store/internal/app"
+	"backend-store/internal/handlers"
+	"backend-store/internal/ratelimit"
+	"backend-store/internal/rpc"
 	"backend-store/pkg/logger"
 	"context"
+	"expvar"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -36,9 +43,26 @@ func main() {
 	}
 	defer application.Close()
 
-	router := setupRouter(application.Handlers)
+	if len(os.Args) > 1 {
+		if err := runCommand(application, os.Args[1:]); err != nil {
+			application.Close()
+			log.Fatal(err)
+		}
+		return
+	}
+
+	router := setupRouter(application.Handlers, application.RateLimiter)
+	if err := router.SetTrustedProxies(splitList(cfg.TrustedProxies)); err != nil {
+		log.Fatal("Invalid TRUSTED_PROXIES:", err)
+	}
+
+	monitorCtx, stopMonitor := context.WithCancel(context.Background())
+	defer stopMonitor()
+	go application.Services.LowStockMonitor.Run(monitorCtx)
+	go application.Services.OutboxDispatcher.Run(monitorCtx)
+	go application.Services.WebhookWorker.Run(monitorCtx)
 
-	startServer(cfg, router)
+	startServer(cfg, router, application.GRPC)
 }
 
 func setupLogging(cfg *config.Config) {
@@ -49,40 +73,220 @@ func setupLogging(cfg *config.Config) {
 	}
 }
 
-func setupRouter(handlers *app.Handlers) *gin.Engine {
+// storefront отклоняет X-Actor на маршрутах покупателей. Объявлен на уровне
+// пакета, потому что в setupRouter имя handlers занято параметром.
+var storefront = handlers.Storefront()
+
+func setupRouter(handlers *app.Handlers, limiter *ratelimit.Limiter) *gin.Engine {
 	router := gin.Default()
 
 	router.Use(gin.Logger())
 	router.Use(gin.Recovery())
+	setupMiddleware(router, limiter)
 
 	setupSwagger(router)
 
 	router.GET("/health", healthCheck)
+	router.GET("/metrics", gin.WrapH(expvar.Handler()))
+
+	router.GET("/graphql", handlers.GraphQL.Serve)
+	router.POST("/graphql", handlers.GraphQL.Serve)
+	if handlers.GraphiQL != nil {
+		router.GET("/graphiql", handlers.GraphiQL)
+	}
 
 	api := router.Group("/api")
 	{
 		order := api.Group("/order")
 		{
-			order.POST("/", handlers.OrderHandler.CreateOrder)
+			order.POST("/", storefront, handlers.OrderHandler.CreateOrder)
 			order.GET("/", handlers.OrderHandler.GetAllOrders)
+			order.GET("/export", handlers.OrderHandler.ExportOrders)
+			order.GET("/stream", handlers.StreamHandler.StreamOrders)
 			order.GET("/:id", handlers.OrderHandler.GetOrderByID)
 			order.PUT("/:id", handlers.OrderHandler.UpdateOrder)
 			order.DELETE("/:id", handlers.OrderHandler.DeleteOrder)
+			order.POST("/:id/payments", storefront, handlers.PaymentHandler.PayOrder)
+			order.GET("/:id/payments", handlers.PaymentHandler.GetOrderPayments)
+			order.POST("/:id/returns", storefront, handlers.ReturnHandler.CreateReturn)
+			order.GET("/:id/returns", handlers.ReturnHandler.GetOrderReturns)
+			order.POST("/:id/shipments", handlers.ShipmentHandler.CreateShipment)
+			order.GET("/:id/shipments", handlers.ShipmentHandler.GetOrderShipments)
+			order.GET("/:id/stream", handlers.StreamHandler.StreamOrder)
 		}
 
 		product := api.Group("/product")
 		{
 			product.POST("/", handlers.ProductHandler.CreateProduct)
+			product.POST("/import", handlers.ProductHandler.ImportProducts)
 			product.GET("/", handlers.ProductHandler.GetAllProducts)
+			product.GET("/export", handlers.ProductHandler.ExportProducts)
+			product.GET("/tags", handlers.ProductHandler.GetTags)
+			product.GET("/search", handlers.ProductHandler.SearchProducts)
+			product.GET("/low-stock", handlers.ProductHandler.GetLowStock)
 			product.GET("/:id", handlers.ProductHandler.GetProductByID)
 			product.PUT("/:id", handlers.ProductHandler.UpdateProduct)
 			product.DELETE("/:id", handlers.ProductHandler.DeleteProduct)
+			product.GET("/:id/movements", handlers.ProductHandler.GetStockMovements)
+			product.GET("/:id/variants", handlers.ProductHandler.GetVariants)
+			product.POST("/:id/variants", handlers.ProductHandler.CreateVariant)
+			product.PUT("/:id/variants/:variantId", handlers.ProductHandler.UpdateVariant)
+			product.DELETE("/:id/variants/:variantId", handlers.ProductHandler.DeleteVariant)
+		}
+
+		customer := api.Group("/customer")
+		{
+			customer.POST("/", storefront, handlers.CustomerHandler.CreateCustomer)
+			customer.GET("/", handlers.CustomerHandler.GetAllCustomers)
+			customer.GET("/:id", handlers.CustomerHandler.GetCustomerByID)
+			customer.PUT("/:id", handlers.CustomerHandler.UpdateCustomer)
+			customer.DELETE("/:id", handlers.CustomerHandler.DeleteCustomer)
+			customer.GET("/:id/orders", handlers.CustomerHandler.GetCustomerOrders)
+		}
+
+		payment := api.Group("/payment")
+		{
+			payment.POST("/webhook", storefront, handlers.PaymentHandler.Webhook)
+			payment.POST("/:id/void", handlers.PaymentHandler.VoidPayment)
+			payment.POST("/:id/refund", handlers.PaymentHandler.RefundPayment)
+			payment.POST("/fake/:reference/confirm", storefront, handlers.PaymentHandler.ConfirmFake)
+		}
+
+		ret := api.Group("/return")
+		{
+			ret.GET("/:id", handlers.ReturnHandler.GetReturn)
+			ret.POST("/:id/approve", handlers.ReturnHandler.ApproveReturn)
+			ret.POST("/:id/reject", handlers.ReturnHandler.RejectReturn)
+			ret.POST("/:id/receive", handlers.ReturnHandler.ReceiveReturn)
+			ret.POST("/:id/refund", handlers.ReturnHandler.RefundReturn)
+		}
+
+		shipment := api.Group("/shipment")
+		{
+			shipment.GET("/:id", handlers.ShipmentHandler.GetShipment)
+			shipment.PUT("/:id", handlers.ShipmentHandler.UpdateShipment)
+		}
+
+		cart := api.Group("/cart", storefront)
+		{
+			cart.POST("/", handlers.CartHandler.CreateCart)
+			cart.GET("/:id", handlers.CartHandler.GetCart)
+			cart.POST("/:id/items", handlers.CartHandler.AddItem)
+			cart.PUT("/:id/items/:itemId", handlers.CartHandler.UpdateItem)
+			cart.DELETE("/:id/items/:itemId", handlers.CartHandler.RemoveItem)
+			cart.POST("/:id/merge", handlers.CartHandler.MergeCart)
+			cart.POST("/:id/checkout", handlers.CartHandler.Checkout)
+			cart.POST("/:id/promotions/preview", handlers.PromotionHandler.PreviewCart)
+			cart.POST("/:id/shipping/quotes", handlers.ShippingHandler.QuoteCart)
+		}
+
+		promotion := api.Group("/promotion")
+		{
+			promotion.POST("/", handlers.PromotionHandler.CreatePromotion)
+			promotion.GET("/", handlers.PromotionHandler.GetAllPromotions)
+			promotion.GET("/:id", handlers.PromotionHandler.GetPromotionByID)
+			promotion.PUT("/:id", handlers.PromotionHandler.UpdatePromotion)
+			promotion.DELETE("/:id", handlers.PromotionHandler.DeletePromotion)
+		}
+
+		category := api.Group("/category")
+		{
+			category.POST("/", handlers.CategoryHandler.CreateCategory)
+			category.GET("/", handlers.CategoryHandler.GetCategoryTree)
+			category.GET("/:id", handlers.CategoryHandler.GetCategoryByID)
+			category.PUT("/:id", handlers.CategoryHandler.UpdateCategory)
+			category.POST("/:id/move", handlers.CategoryHandler.MoveCategory)
+			category.DELETE("/:id", handlers.CategoryHandler.DeleteCategory)
+		}
+
+		warehouse := api.Group("/warehouse")
+		{
+			warehouse.POST("/", handlers.WarehouseHandler.CreateWarehouse)
+			warehouse.GET("/", handlers.WarehouseHandler.GetAllWarehouses)
+			warehouse.GET("/:id", handlers.WarehouseHandler.GetWarehouseByID)
+			warehouse.PUT("/:id", handlers.WarehouseHandler.UpdateWarehouse)
+			warehouse.DELETE("/:id", handlers.WarehouseHandler.DeleteWarehouse)
+			warehouse.GET("/:id/stock", handlers.WarehouseHandler.GetWarehouseStock)
+			warehouse.PUT("/:id/stock/:variantId", handlers.WarehouseHandler.SetWarehouseStock)
+		}
+
+		transfer := api.Group("/transfer")
+		{
+			transfer.POST("/", handlers.WarehouseHandler.CreateTransfer)
+			transfer.GET("/", handlers.WarehouseHandler.GetAllTransfers)
+			transfer.GET("/:id", handlers.WarehouseHandler.GetTransferByID)
+			transfer.POST("/:id/ship", handlers.WarehouseHandler.ShipTransfer)
+			transfer.POST("/:id/receive", handlers.WarehouseHandler.ReceiveTransfer)
+			transfer.POST("/:id/cancel", handlers.WarehouseHandler.CancelTransfer)
+		}
+
+		supplier := api.Group("/supplier")
+		{
+			supplier.POST("/", handlers.PurchaseHandler.CreateSupplier)
+			supplier.GET("/", handlers.PurchaseHandler.GetAllSuppliers)
+			supplier.GET("/:id", handlers.PurchaseHandler.GetSupplierByID)
+			supplier.PUT("/:id", handlers.PurchaseHandler.UpdateSupplier)
+			supplier.DELETE("/:id", handlers.PurchaseHandler.DeleteSupplier)
+		}
+
+		purchaseOrder := api.Group("/purchase-order")
+		{
+			purchaseOrder.POST("/", handlers.PurchaseHandler.CreatePurchaseOrder)
+			purchaseOrder.GET("/", handlers.PurchaseHandler.GetAllPurchaseOrders)
+			purchaseOrder.GET("/:id", handlers.PurchaseHandler.GetPurchaseOrderByID)
+			purchaseOrder.PUT("/:id", handlers.PurchaseHandler.UpdatePurchaseOrder)
+			purchaseOrder.POST("/:id/send", handlers.PurchaseHandler.SendPurchaseOrder)
+			purchaseOrder.POST("/:id/receive", handlers.PurchaseHandler.ReceivePurchaseOrder)
+			purchaseOrder.POST("/:id/cancel", handlers.PurchaseHandler.CancelPurchaseOrder)
+		}
+
+		reports := api.Group("/reports")
+		{
+			reports.GET("/sales", handlers.ReportHandler.Sales)
+			reports.GET("/top-products", handlers.ReportHandler.TopProducts)
+			reports.GET("/order-value", handlers.ReportHandler.OrderValue)
+			reports.GET("/order-statuses", handlers.ReportHandler.OrderStatuses)
+			reports.GET("/stock-valuation", handlers.ReportHandler.StockValuation)
+		}
+
+		outbox := api.Group("/admin/outbox")
+		{
+			outbox.GET("/", handlers.OutboxHandler.GetOutboxEvents)
+			outbox.GET("/:id", handlers.OutboxHandler.GetOutboxEventByID)
+			outbox.POST("/:id/replay", handlers.OutboxHandler.ReplayOutboxEvent)
+		}
+
+		webhooks := api.Group("/admin/webhooks")
+		{
+			webhooks.POST("/", handlers.WebhookHandler.CreateWebhook)
+			webhooks.GET("/", handlers.WebhookHandler.GetWebhooks)
+			webhooks.GET("/:id", handlers.WebhookHandler.GetWebhookByID)
+			webhooks.PUT("/:id", handlers.WebhookHandler.UpdateWebhook)
+			webhooks.DELETE("/:id", handlers.WebhookHandler.DeleteWebhook)
+			webhooks.GET("/:id/deliveries", handlers.WebhookHandler.GetWebhookDeliveries)
+		}
+
+		deliveries := api.Group("/admin/webhook-deliveries")
+		{
+			deliveries.GET("/:id", handlers.WebhookHandler.GetWebhookDeliveryByID)
+			deliveries.POST("/:id/redeliver", handlers.WebhookHandler.RedeliverWebhook)
 		}
+
+		api.GET("/audit", handlers.AuditHandler.GetAuditLog)
 	}
 
 	return router
 }
 
+// setupMiddleware подключает middleware API, не зависящие от обработчиков.
+func setupMiddleware(router *gin.Engine, limiter *ratelimit.Limiter) {
+	router.Use(handlers.Actor())
+	router.Use(handlers.RequestID())
+	if limiter != nil {
+		router.Use(handlers.RateLimit(limiter))
+	}
+}
+
 func setupSwagger(router *gin.Engine) {
 	router.GET("/openapi.yaml", func(c *gin.Context) {
 		openAPIPath := filepath.Join("api", "openapi.yaml")
@@ -108,7 +312,9 @@ func healthCheck(c *gin.Context) {
 	})
 }
 
-func startServer(cfg *config.Config, router *gin.Engine) {
+// startServer запускает HTTP и, если он настроен, gRPC сервер и по сигналу
+// останавливает оба, давая текущим запросам и потокам ShutdownTimeout.
+func startServer(cfg *config.Config, router *gin.Engine, grpcServer *rpc.Server) {
 	srv := &http.Server{
 		Addr:         cfg.ServerHost + ":" + cfg.ServerPort,
 		Handler:      router,
@@ -123,6 +329,19 @@ func startServer(cfg *config.Config, router *gin.Engine) {
 		}
 	}()
 
+	if grpcServer != nil {
+		lis, err := net.Listen("tcp", cfg.ServerHost+":"+cfg.GRPCPort)
+		if err != nil {
+			log.Fatal("Failed to listen for gRPC:", err)
+		}
+		go func() {
+			log.Info("gRPC server starting on", "address", lis.Addr().String())
+			if err := grpcServer.Serve(lis); err != nil {
+				log.Fatal("Failed to start gRPC server:", err)
+			}
+		}()
+	}
+
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
@@ -131,9 +350,33 @@ func startServer(cfg *config.Config, router *gin.Engine) {
 	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
 	defer cancel()
 
-	if err := srv.Shutdown(ctx); err != nil {
+	var wg sync.WaitGroup
+	if grpcServer != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := grpcServer.Shutdown(ctx); err != nil {
+				log.Error("gRPC server forced to shutdown:", err)
+			}
+		}()
+	}
+
+	err := srv.Shutdown(ctx)
+	wg.Wait()
+	if err != nil {
 		log.Fatal("Server forced to shutdown:", err)
 	}
 
 	log.Info("Server exited")
 }
+
+// splitList разбирает список значений через запятую, пропуская пустые.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -10,6 +10,8 @@ type Config struct {
 	// Настройки сервера
 	ServerHost string
 	ServerPort string
+	// Порт gRPC API; пустое значение отключает gRPC
+	GRPCPort string
 
 	// Настройки базы данных
 	DatabaseURL     string
@@ -21,9 +23,78 @@ type Config struct {
 	Environment string
 	Debug       bool
 
+	// Режим разработки без секретов: тестовый платежный шлюз и потоки
+	// заказов получают случайные секреты. В production запрещен.
+	InsecureDevMode bool
+
 	// Настройки логирования
 	LogLevel string
 
+	// Стратегия распределения заказа по складам: priority или nearest
+	StockAllocation string
+
+	// Контроль заканчивающихся товаров: период фоновой проверки и
+	// необязательный вебхук для уведомлений
+	LowStockInterval   time.Duration
+	LowStockWebhookURL string
+
+	// Срок жизни корзины с последнего изменения
+	CartTTL time.Duration
+
+	// Платежный шлюз: провайдер, общий секрет уведомлений (обязателен, кроме
+	// тестового шлюза в InsecureDevMode) и внешний адрес сервиса, на который
+	// провайдер отправляет уведомления
+	PaymentProvider      string
+	PaymentWebhookSecret string
+	PaymentCallbackURL   string
+
+	// Налог: ставки по юрисдикциям из CSV-файла и/или строки конфигурации
+	// ("US-CA,standard,7.25;DE,standard,19") и признак цен с налогом
+	TaxRatesFile     string
+	TaxRates         string
+	TaxPricesInclude bool
+
+	// Способы доставки: JSON-файл со списком способов (см. shipping.Method)
+	ShippingMethodsFile string
+
+	// Срок хранения отчетов в кэше; 0 отключает кэш
+	ReportCacheTTL time.Duration
+
+	// Доставка доменных событий из outbox: период опроса и число попыток,
+	// после которого событие отбрасывается
+	OutboxInterval    time.Duration
+	OutboxMaxAttempts int
+
+	// Вебхуки: период опроса, таймаут запроса (меньше половины аренды
+	// пачки, см. service.ValidateWebhookTimeout), число попыток доставки и
+	// число неудач подряд, после которого вебхук отключается
+	WebhookInterval     time.Duration
+	WebhookTimeout      time.Duration
+	WebhookMaxAttempts  int
+	WebhookDisableAfter int
+
+	// Потоки заказов (SSE): секрет токенов доступа (обязателен, кроме
+	// InsecureDevMode), размер буфера для Last-Event-ID и период heartbeat
+	OrderStreamSecret    string
+	OrderStreamBuffer    int
+	OrderStreamHeartbeat time.Duration
+
+	// GraphQL: предельные глубина и сложность запроса (см. gql.Limits);
+	// 0 снимает ограничение
+	GraphQLMaxDepth      int
+	GraphQLMaxComplexity int
+
+	// Ограничение частоты запросов: политики для маршрутов в формате
+	// ratelimit.ParsePolicies; пустая строка отключает ограничение
+	RateLimits string
+
+	// Адреса или подсети прокси через запятую, которым доверяется
+	// X-Forwarded-For при определении IP клиента; по умолчанию - никому
+	TrustedProxies string
+
+	// Срок хранения журнала аудита; 0 хранит журнал бессрочно
+	AuditRetention time.Duration
+
 	// Таймауты
 	ReadTimeout     time.Duration
 	WriteTimeout    time.Duration
@@ -31,9 +102,13 @@ type Config struct {
 }
 
 func Load() *Config {
+	host := getEnv("SERVER_HOST", "localhost")
+	port := getEnv("SERVER_PORT", "8080")
+
 	return &Config{
-		ServerHost: getEnv("SERVER_HOST", "localhost"),
-		ServerPort: getEnv("SERVER_PORT", "8080"),
+		ServerHost: host,
+		ServerPort: port,
+		GRPCPort:   getEnv("GRPC_PORT", "9090"),
 
 		DatabaseURL:     getEnv("DATABASE_URL", ""),
 		MaxOpenConns:    getEnvAsInt("DB_MAX_OPEN_CONNS", 25),
@@ -43,8 +118,49 @@ func Load() *Config {
 		Environment: getEnv("ENVIRONMENT", "development"),
 		Debug:       getEnvAsBool("DEBUG", true),
 
+		InsecureDevMode: getEnvAsBool("INSECURE_DEV_MODE", false),
+
 		LogLevel: getEnv("LOG_LEVEL", "info"),
 
+		StockAllocation: getEnv("STOCK_ALLOCATION", "priority"),
+
+		LowStockInterval:   getEnvAsDuration("LOW_STOCK_INTERVAL", 5*time.Minute),
+		LowStockWebhookURL: getEnv("LOW_STOCK_WEBHOOK_URL", ""),
+
+		CartTTL: getEnvAsDuration("CART_TTL", 7*24*time.Hour),
+
+		PaymentProvider:      getEnv("PAYMENT_PROVIDER", "fake"),
+		PaymentWebhookSecret: getEnv("PAYMENT_WEBHOOK_SECRET", ""),
+		PaymentCallbackURL:   getEnv("PAYMENT_CALLBACK_URL", "http://"+host+":"+port),
+
+		TaxRatesFile:     getEnv("TAX_RATES_FILE", ""),
+		TaxRates:         getEnv("TAX_RATES", ""),
+		TaxPricesInclude: getEnvAsBool("TAX_PRICES_INCLUDE_TAX", false),
+
+		ShippingMethodsFile: getEnv("SHIPPING_METHODS_FILE", ""),
+
+		ReportCacheTTL: getEnvAsDuration("REPORT_CACHE_TTL", 5*time.Minute),
+
+		OutboxInterval:    getEnvAsDuration("OUTBOX_INTERVAL", time.Second),
+		OutboxMaxAttempts: getEnvAsInt("OUTBOX_MAX_ATTEMPTS", 10),
+
+		WebhookInterval:     getEnvAsDuration("WEBHOOK_INTERVAL", time.Second),
+		WebhookTimeout:      getEnvAsDuration("WEBHOOK_TIMEOUT", 10*time.Second),
+		WebhookMaxAttempts:  getEnvAsInt("WEBHOOK_MAX_ATTEMPTS", 8),
+		WebhookDisableAfter: getEnvAsInt("WEBHOOK_DISABLE_AFTER", 20),
+
+		OrderStreamSecret:    getEnv("ORDER_STREAM_SECRET", ""),
+		OrderStreamBuffer:    getEnvAsInt("ORDER_STREAM_BUFFER", 1000),
+		OrderStreamHeartbeat: getEnvAsDuration("ORDER_STREAM_HEARTBEAT", 15*time.Second),
+
+		GraphQLMaxDepth:      getEnvAsInt("GRAPHQL_MAX_DEPTH", 10),
+		GraphQLMaxComplexity: getEnvAsInt("GRAPHQL_MAX_COMPLEXITY", 5000),
+
+		RateLimits:     getEnv("RATE_LIMITS", "POST /api/order/,30,1m,10"),
+		TrustedProxies: getEnv("TRUSTED_PROXIES", ""),
+
+		AuditRetention: getEnvAsDuration("AUDIT_RETENTION", 365*24*time.Hour),
+
 		ReadTimeout:     getEnvAsDuration("READ_TIMEOUT", 15*time.Second),
 		WriteTimeout:    getEnvAsDuration("WRITE_TIMEOUT", 15*time.Second),
 		ShutdownTimeout: getEnvAsDuration("SHUTDOWN_TIMEOUT", 10*time.Second),
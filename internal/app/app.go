@@ -2,10 +2,28 @@ package app
 
 import (
 	"backend-store/config"
+	"backend-store/internal/gql"
 	"backend-store/internal/handlers"
+	"backend-store/internal/models"
+	"backend-store/internal/notify"
+	"backend-store/internal/payment"
+	"backend-store/internal/ratelimit"
+	"backend-store/internal/rpc"
 	"backend-store/internal/service"
+	"backend-store/internal/shipping"
 	"backend-store/internal/storage"
+	"backend-store/internal/tax"
 	"backend-store/pkg/logger"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
 )
 
 type App struct {
@@ -13,17 +31,63 @@ type App struct {
 	Storage  storage.Storage
 	Services *Services
 	Handlers *Handlers
+	Gateway  payment.Gateway
 	log      logger.Log
+
+	// GRPC - gRPC API товаров и заказов; nil, если GRPCPort не задан.
+	GRPC *rpc.Server
+
+	// RateLimiter ограничивает частоту запросов HTTP API; nil, если
+	// политики не заданы.
+	RateLimiter *ratelimit.Limiter
 }
 
 type Services struct {
-	ProductService service.ProductService
-	OrderService   service.OrderService
+	ProductService   service.ProductService
+	OrderService     service.OrderService
+	CategoryService  service.CategoryService
+	WarehouseService service.WarehouseService
+	CartService      service.CartService
+	CustomerService  service.CustomerService
+	PaymentService   service.PaymentService
+	ReturnService    service.ReturnService
+	ShipmentService  service.ShipmentService
+	PromotionService service.PromotionService
+	ShippingService  service.ShippingService
+	PurchaseService  service.PurchaseService
+	ReportService    service.ReportService
+	OutboxService    service.OutboxService
+	WebhookService   service.WebhookService
+	OrderStream      service.OrderStreamService
+	AuditService     service.AuditService
+	LowStockMonitor  *service.LowStockMonitor
+	OutboxDispatcher *service.OutboxDispatcher
+	WebhookWorker    *service.WebhookWorker
 }
 
 type Handlers struct {
-	ProductHandler *handlers.ProductHandler
-	OrderHandler   *handlers.OrderHandler
+	ProductHandler   *handlers.ProductHandler
+	OrderHandler     *handlers.OrderHandler
+	CategoryHandler  *handlers.CategoryHandler
+	WarehouseHandler *handlers.WarehouseHandler
+	CartHandler      *handlers.CartHandler
+	CustomerHandler  *handlers.CustomerHandler
+	PaymentHandler   *handlers.PaymentHandler
+	ReturnHandler    *handlers.ReturnHandler
+	ShipmentHandler  *handlers.ShipmentHandler
+	PromotionHandler *handlers.PromotionHandler
+	ShippingHandler  *handlers.ShippingHandler
+	PurchaseHandler  *handlers.PurchaseHandler
+	ReportHandler    *handlers.ReportHandler
+	OutboxHandler    *handlers.OutboxHandler
+	WebhookHandler   *handlers.WebhookHandler
+	StreamHandler    *handlers.OrderStreamHandler
+	AuditHandler     *handlers.AuditHandler
+
+	// GraphQL - endpoint /graphql. GraphiQL - страница для отладки
+	// запросов; nil в production.
+	GraphQL  *gql.Handler
+	GraphiQL gin.HandlerFunc
 }
 
 func New(cfg *config.Config, log logger.Log) (*App, error) {
@@ -31,14 +95,47 @@ func New(cfg *config.Config, log logger.Log) (*App, error) {
 		Config: cfg,
 		log:    log,
 	}
+	if cfg.InsecureDevMode && cfg.Environment == "production" {
+		return nil, fmt.Errorf("INSECURE_DEV_MODE is not allowed in production")
+	}
+	if err := service.ValidateWebhookTimeout(cfg.WebhookTimeout); err != nil {
+		return nil, fmt.Errorf("invalid WEBHOOK_TIMEOUT: %w", err)
+	}
 
 	store, err := app.initStorage()
 	if err != nil {
 		return nil, err
 	}
 	app.Storage = store
-	app.Services = app.initServices()
+	app.Gateway, err = app.initGateway()
+	if err != nil {
+		return nil, err
+	}
+	app.Services, err = app.initServices()
+	if err != nil {
+		return nil, err
+	}
 	app.Handlers = app.initHandlers()
+	app.Handlers.GraphQL, err = gql.NewHandler(app.Services.ProductService, app.Services.OrderService, app.Services.CustomerService, app.Storage, gql.Limits{
+		MaxDepth:      cfg.GraphQLMaxDepth,
+		MaxComplexity: cfg.GraphQLMaxComplexity,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build graphql schema: %w", err)
+	}
+	if cfg.Environment != "production" {
+		app.Handlers.GraphiQL = gql.GraphiQL("/graphql")
+	}
+	policies, err := ratelimit.ParsePolicies(cfg.RateLimits)
+	if err != nil {
+		return nil, err
+	}
+	if len(policies) > 0 {
+		app.RateLimiter = ratelimit.NewLimiter(policies, ratelimit.NewMemoryStore())
+	}
+	if cfg.GRPCPort != "" {
+		app.GRPC = rpc.NewServer(app.Services.ProductService, app.Services.OrderService, log)
+	}
 
 	app.log.Info("Application initialized successfully")
 	return app, nil
@@ -68,17 +165,210 @@ func (a *App) initStorage() (storage.Storage, error) {
 	return memoryStore, nil
 }
 
-func (a *App) initServices() *Services {
+func (a *App) initServices() (*Services, error) {
+	strategy, err := models.ParseAllocationStrategy(a.Config.StockAllocation)
+	if err != nil {
+		return nil, err
+	}
+
+	taxes, err := a.initTaxCalculator()
+	if err != nil {
+		return nil, err
+	}
+	rates, err := a.initShippingRates()
+	if err != nil {
+		return nil, err
+	}
+
+	monitor := service.NewLowStockMonitor(a.Storage, a.initNotifier(), a.Config.LowStockInterval, a.log)
+	orderService := service.NewOrderService(a.Storage, strategy, taxes, rates, monitor)
+	paymentService := service.NewPaymentService(a.Storage, a.Gateway)
+	webhooks := service.NewWebhookWorker(a.Storage, notify.NewWebhookSender(&http.Client{Timeout: a.Config.WebhookTimeout}),
+		a.Config.WebhookInterval, a.Config.WebhookMaxAttempts, a.Config.WebhookDisableAfter, a.log)
+	stream := service.NewOrderStream(a.Config.OrderStreamBuffer)
+	dispatcher := a.initDispatcher(webhooks, stream)
+	if err := a.initOrderStreamSecret(); err != nil {
+		return nil, err
+	}
+
 	return &Services{
-		ProductService: service.NewProductService(a.Storage),
-		OrderService:   service.NewOrderService(a.Storage),
+		ProductService:   service.NewProductService(a.Storage, monitor),
+		OrderService:     orderService,
+		CategoryService:  service.NewCategoryService(a.Storage),
+		WarehouseService: service.NewWarehouseService(a.Storage, monitor),
+		CartService:      service.NewCartService(a.Storage, orderService, a.Config.CartTTL),
+		CustomerService:  service.NewCustomerService(a.Storage),
+		PaymentService:   paymentService,
+		ReturnService:    service.NewReturnService(a.Storage, paymentService, monitor),
+		ShipmentService:  service.NewShipmentService(a.Storage),
+		PromotionService: service.NewPromotionService(a.Storage),
+		ShippingService:  service.NewShippingService(a.Storage, rates),
+		PurchaseService:  service.NewPurchaseService(a.Storage, monitor),
+		ReportService:    service.NewReportService(a.Storage, a.Config.ReportCacheTTL),
+		OutboxService:    service.NewOutboxService(a.Storage, dispatcher),
+		WebhookService:   service.NewWebhookService(a.Storage, webhooks),
+		OrderStream:      service.NewOrderStreamService(a.Storage, stream, a.Config.OrderStreamSecret),
+		AuditService:     service.NewAuditService(a.Storage, a.Config.AuditRetention),
+		LowStockMonitor:  monitor,
+		OutboxDispatcher: dispatcher,
+		WebhookWorker:    webhooks,
+	}, nil
+}
+
+// initGateway создает платежный шлюз. Встроенный тестовый шлюз отправляет
+// уведомления на собственный вебхук сервиса. Без секрета уведомлений сервис
+// не запускается: только тестовый шлюз в InsecureDevMode получает случайный
+// секрет, известный лишь ему самому.
+func (a *App) initGateway() (payment.Gateway, error) {
+	if a.Config.PaymentWebhookSecret == "" {
+		if a.Config.PaymentProvider != payment.FakeProvider || !a.Config.InsecureDevMode {
+			return nil, fmt.Errorf("PAYMENT_WEBHOOK_SECRET is required (INSECURE_DEV_MODE=true allows the fake gateway without it)")
+		}
+		secret, err := randomSecret()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate payment webhook secret: %w", err)
+		}
+		a.Config.PaymentWebhookSecret = secret
+		a.log.Warn("PAYMENT_WEBHOOK_SECRET is not set, using a random secret for the fake gateway")
+	}
+
+	switch a.Config.PaymentProvider {
+	case payment.FakeProvider:
+		a.log.Warn("Using fake payment gateway")
+		base := strings.TrimRight(a.Config.PaymentCallbackURL, "/")
+		return payment.NewFake(base+"/api/payment/webhook", base+"/api/payment/fake/", a.Config.PaymentWebhookSecret, nil), nil
+	default:
+		return nil, fmt.Errorf("unknown payment provider %q", a.Config.PaymentProvider)
 	}
 }
 
+// initOrderStreamSecret требует секрет токенов потока заказов. В
+// InsecureDevMode без него создается случайный секрет, а в журнал пишется
+// токен сотрудника для отладки.
+func (a *App) initOrderStreamSecret() error {
+	if a.Config.OrderStreamSecret != "" {
+		return nil
+	}
+	if !a.Config.InsecureDevMode {
+		return fmt.Errorf("ORDER_STREAM_SECRET is required (INSECURE_DEV_MODE=true generates a random one)")
+	}
+	secret, err := randomSecret()
+	if err != nil {
+		return fmt.Errorf("failed to generate order stream secret: %w", err)
+	}
+	token, err := service.IssueStreamToken(secret, models.StreamAccess{}, time.Now().Add(24*time.Hour))
+	if err != nil {
+		return err
+	}
+	a.Config.OrderStreamSecret = secret
+	a.log.Warn("ORDER_STREAM_SECRET is not set, using a random secret; staff stream token: " + token)
+	return nil
+}
+
+// randomSecret возвращает случайный секрет для InsecureDevMode.
+func randomSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+// initTaxCalculator загружает ставки налога из файла и конфигурации; ставки
+// из конфигурации переопределяют ставки файла.
+func (a *App) initTaxCalculator() (tax.Calculator, error) {
+	var rates []tax.Rate
+	if a.Config.TaxRatesFile != "" {
+		file, err := os.Open(a.Config.TaxRatesFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open tax rates file: %w", err)
+		}
+		defer file.Close()
+		fileRates, err := tax.LoadCSV(file)
+		if err != nil {
+			return nil, err
+		}
+		rates = append(rates, fileRates...)
+	}
+	if a.Config.TaxRates != "" {
+		configRates, err := tax.ParseRates(a.Config.TaxRates)
+		if err != nil {
+			return nil, err
+		}
+		rates = append(rates, configRates...)
+	}
+
+	if len(rates) == 0 {
+		a.log.Warn("No tax rates configured, orders are not taxed")
+	}
+	return tax.NewTable(rates, a.Config.TaxPricesInclude), nil
+}
+
+// initShippingRates загружает способы доставки из файла. Без файла способов
+// нет и заказы оформляются без доставки.
+func (a *App) initShippingRates() (shipping.RateProvider, error) {
+	var methods []shipping.Method
+	if a.Config.ShippingMethodsFile != "" {
+		file, err := os.Open(a.Config.ShippingMethodsFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open shipping methods file: %w", err)
+		}
+		defer file.Close()
+		if methods, err = shipping.LoadJSON(file); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(methods) == 0 {
+		a.log.Warn("No shipping methods configured, orders are not charged for shipping")
+	}
+	return shipping.NewTable(methods), nil
+}
+
+func (a *App) initNotifier() notify.Notifier {
+	notifier := notify.NewLogNotifier(a.log)
+	if a.Config.LowStockWebhookURL != "" {
+		a.log.Info("Low stock notifications are sent to webhook")
+		notifier = notify.Multi(notifier, notify.NewWebhookNotifier(a.Config.LowStockWebhookURL, nil))
+	}
+	return notifier
+}
+
+// initDispatcher создает диспетчер доменных событий. Обработчики побочных
+// эффектов регистрируются здесь; журнал получает все события.
+func (a *App) initDispatcher(webhooks *service.WebhookWorker, stream *service.OrderStream) *service.OutboxDispatcher {
+	dispatcher := service.NewOutboxDispatcher(a.Storage, a.Config.OutboxInterval, a.Config.OutboxMaxAttempts, a.log)
+	dispatcher.Register("log", service.EventHandlerFunc(func(ctx context.Context, event models.OutboxEvent) error {
+		a.log.Debug("Domain event %d: %s %s:%d", event.ID, event.Type, event.AggregateType, event.AggregateID)
+		return nil
+	}))
+	dispatcher.Register("webhooks", webhooks, models.EventTypes...)
+	dispatcher.Register("order-stream", stream, models.EventOrderCreated, models.EventOrderStatusChanged)
+	return dispatcher
+}
+
 func (a *App) initHandlers() *Handlers {
+	// Подтверждение блокировок доступно только у тестового шлюза.
+	fake, _ := a.Gateway.(*payment.Fake)
+
 	return &Handlers{
-		ProductHandler: handlers.NewProductHandler(a.Services.ProductService),
-		OrderHandler:   handlers.NewOrderHandler(a.Services.OrderService),
+		ProductHandler:   handlers.NewProductHandler(a.Services.ProductService),
+		OrderHandler:     handlers.NewOrderHandler(a.Services.OrderService),
+		CategoryHandler:  handlers.NewCategoryHandler(a.Services.CategoryService),
+		WarehouseHandler: handlers.NewWarehouseHandler(a.Services.WarehouseService),
+		CartHandler:      handlers.NewCartHandler(a.Services.CartService),
+		CustomerHandler:  handlers.NewCustomerHandler(a.Services.CustomerService),
+		PaymentHandler:   handlers.NewPaymentHandler(a.Services.PaymentService, a.Config.PaymentWebhookSecret, fake),
+		ReturnHandler:    handlers.NewReturnHandler(a.Services.ReturnService),
+		ShipmentHandler:  handlers.NewShipmentHandler(a.Services.ShipmentService),
+		PromotionHandler: handlers.NewPromotionHandler(a.Services.PromotionService),
+		ShippingHandler:  handlers.NewShippingHandler(a.Services.ShippingService),
+		PurchaseHandler:  handlers.NewPurchaseHandler(a.Services.PurchaseService),
+		ReportHandler:    handlers.NewReportHandler(a.Services.ReportService, a.Config.ReportCacheTTL),
+		OutboxHandler:    handlers.NewOutboxHandler(a.Services.OutboxService),
+		WebhookHandler:   handlers.NewWebhookHandler(a.Services.WebhookService),
+		StreamHandler:    handlers.NewOrderStreamHandler(a.Services.OrderStream, a.Config.OrderStreamHeartbeat),
+		AuditHandler:     handlers.NewAuditHandler(a.Services.AuditService),
 	}
 }
 
@@ -0,0 +1,155 @@
+package bulk
+
+import (
+	"backend-store/internal/models"
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ProductRow - строка импорта. Err содержит ошибку разбора конкретной строки;
+// такие строки попадают в отчет как failed и не прерывают импорт.
+type ProductRow struct {
+	Line    int
+	Product *models.Product
+	Err     error
+}
+
+// ProductReader построчно читает товары из входного потока.
+// Next возвращает io.EOF после последней строки.
+type ProductReader interface {
+	Next() (*ProductRow, error)
+}
+
+func NewProductReader(r io.Reader, format Format) (ProductReader, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVProductReader(r)
+	case FormatNDJSON:
+		return newNDJSONProductReader(r), nil
+	default:
+		return nil, fmt.Errorf("unsupported import format %q", format)
+	}
+}
+
+type csvProductReader struct {
+	r       *csv.Reader
+	columns map[string]int
+}
+
+var requiredCSVColumns = []string{"sku", "name", "price"}
+
+func newCSVProductReader(r io.Reader) (*csvProductReader, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, errors.New("csv file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read csv header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		columns[name] = i
+	}
+	for _, name := range requiredCSVColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("csv header is missing required column %q", name)
+		}
+	}
+
+	return &csvProductReader{r: cr, columns: columns}, nil
+}
+
+func (c *csvProductReader) Next() (*ProductRow, error) {
+	record, err := c.r.Read()
+	if err == io.EOF {
+		return nil, io.EOF
+	}
+	line, _ := c.r.FieldPos(0)
+	row := &ProductRow{Line: line}
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			row.Line = parseErr.Line
+			row.Err = parseErr.Err
+			return row, nil
+		}
+		return nil, err
+	}
+
+	field := func(name string) string {
+		if i, ok := c.columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	product := &models.Product{
+		SKU:         field("sku"),
+		Name:        field("name"),
+		Description: field("description"),
+	}
+	row.Product = product
+
+	if product.Price, err = strconv.ParseFloat(field("price"), 64); err != nil {
+		row.Err = fmt.Errorf("invalid price %q", field("price"))
+		return row, nil
+	}
+	if q := field("quantity"); q != "" {
+		if product.Quantity, err = strconv.Atoi(q); err != nil {
+			row.Err = fmt.Errorf("invalid quantity %q", q)
+			return row, nil
+		}
+	}
+
+	return row, nil
+}
+
+type ndjsonProductReader struct {
+	s    *bufio.Scanner
+	line int
+}
+
+const maxNDJSONLine = 1 << 20
+
+func newNDJSONProductReader(r io.Reader) *ndjsonProductReader {
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, 64*1024), maxNDJSONLine)
+	return &ndjsonProductReader{s: s}
+}
+
+func (n *ndjsonProductReader) Next() (*ProductRow, error) {
+	for n.s.Scan() {
+		n.line++
+		data := bytes.TrimSpace(n.s.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		row := &ProductRow{Line: n.line}
+		var product models.Product
+		if err := json.Unmarshal(data, &product); err != nil {
+			row.Err = fmt.Errorf("invalid json: %w", err)
+			return row, nil
+		}
+		product.ID = 0
+		row.Product = &product
+		return row, nil
+	}
+	if err := n.s.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
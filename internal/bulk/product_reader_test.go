@@ -0,0 +1,109 @@
+package bulk
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readAll(t *testing.T, reader ProductReader) []*ProductRow {
+	var rows []*ProductRow
+	for {
+		row, err := reader.Next()
+		if err == io.EOF {
+			return rows
+		}
+		require.NoError(t, err)
+		rows = append(rows, row)
+	}
+}
+
+func TestCSVProductReader(t *testing.T) {
+	// Arrange
+	data := "SKU,Name,Description,Price,Quantity\n" +
+		"A-1,Laptop,\"Fast, light\",999.5,3\n" +
+		"A-2,Mouse,,abc,1\n" +
+		"A-3,Cable,,5,\n"
+
+	// Act
+	reader, err := NewProductReader(strings.NewReader(data), FormatCSV)
+	require.NoError(t, err)
+	rows := readAll(t, reader)
+
+	// Assert
+	require.Len(t, rows, 3)
+
+	assert.Equal(t, 2, rows[0].Line)
+	assert.NoError(t, rows[0].Err)
+	assert.Equal(t, "A-1", rows[0].Product.SKU)
+	assert.Equal(t, "Fast, light", rows[0].Product.Description)
+	assert.Equal(t, 999.5, rows[0].Product.Price)
+	assert.Equal(t, 3, rows[0].Product.Quantity)
+
+	assert.Equal(t, 3, rows[1].Line)
+	assert.EqualError(t, rows[1].Err, `invalid price "abc"`)
+
+	assert.NoError(t, rows[2].Err)
+	assert.Equal(t, 0, rows[2].Product.Quantity) // пустое количество допустимо
+}
+
+func TestCSVProductReader_MissingColumn(t *testing.T) {
+	_, err := NewProductReader(strings.NewReader("name,price\nLaptop,10\n"), FormatCSV)
+
+	assert.EqualError(t, err, `csv header is missing required column "sku"`)
+}
+
+func TestNDJSONProductReader(t *testing.T) {
+	// Arrange
+	data := `{"sku":"A-1","name":"Laptop","price":999.5,"quantity":3}` + "\n" +
+		"\n" +
+		`{"sku":"A-2",` + "\n" +
+		`{"id":42,"sku":"A-3","name":"Cable","price":5}` + "\n"
+
+	// Act
+	reader, err := NewProductReader(strings.NewReader(data), FormatNDJSON)
+	require.NoError(t, err)
+	rows := readAll(t, reader)
+
+	// Assert
+	require.Len(t, rows, 3)
+
+	assert.Equal(t, 1, rows[0].Line)
+	assert.Equal(t, "Laptop", rows[0].Product.Name)
+
+	assert.Equal(t, 3, rows[1].Line)
+	assert.Error(t, rows[1].Err)
+
+	assert.Equal(t, 4, rows[2].Line)
+	assert.Equal(t, 0, rows[2].Product.ID) // ID из файла игнорируется
+}
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		filename string
+		format   Format
+		wantErr  bool
+	}{
+		{filename: "products.csv", format: FormatCSV},
+		{filename: "products.CSV", format: FormatCSV},
+		{filename: "products.ndjson", format: FormatNDJSON},
+		{filename: "products.jsonl", format: FormatNDJSON},
+		{filename: "products.xml", wantErr: true},
+		{filename: "products", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			format, err := DetectFormat(tt.filename)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.format, format)
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package bulk
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testColumns = []string{"id", "name", "price", "created_at", "item_id"}
+
+func testRows() [][]interface{} {
+	created := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	return [][]interface{}{
+		{1, "Laptop, 15\"", 999.5, created, 7},
+		{2, "<Cable & Co>", 5.0, created, nil},
+	}
+}
+
+func writeAll(t *testing.T, format Format) []byte {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, format, "Orders", testColumns)
+	require.NoError(t, err)
+	for _, row := range testRows() {
+		require.NoError(t, w.Write(row))
+	}
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestCSVWriter(t *testing.T) {
+	out := writeAll(t, FormatCSV)
+
+	expected := "id,name,price,created_at,item_id\n" +
+		"1,\"Laptop, 15\"\"\",999.5,2024-01-15T10:00:00Z,7\n" +
+		"2,<Cable & Co>,5,2024-01-15T10:00:00Z,\n"
+	assert.Equal(t, expected, string(out))
+}
+
+func TestNDJSONWriter(t *testing.T) {
+	out := writeAll(t, FormatNDJSON)
+
+	expected := `{"id":1,"name":"Laptop, 15\"","price":999.5,"created_at":"2024-01-15T10:00:00Z","item_id":7}` + "\n" +
+		`{"id":2,"name":"\u003cCable \u0026 Co\u003e","price":5,"created_at":"2024-01-15T10:00:00Z","item_id":null}` + "\n"
+	assert.Equal(t, expected, string(out))
+}
+
+func TestXLSXWriter(t *testing.T) {
+	out := writeAll(t, FormatXLSX)
+
+	zr, err := zip.NewReader(bytes.NewReader(out), int64(len(out)))
+	require.NoError(t, err)
+
+	parts := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		parts[f.Name] = string(data)
+	}
+
+	assert.Contains(t, parts, "[Content_Types].xml")
+	assert.Contains(t, parts["xl/workbook.xml"], `<sheet name="Orders"`)
+
+	sheet := parts["xl/worksheets/sheet1.xml"]
+	assert.Contains(t, sheet, `<row r="1"><c t="inlineStr"><is><t xml:space="preserve">id</t></is></c>`)
+	assert.Contains(t, sheet, `<row r="2"><c><v>1</v></c>`)
+	assert.Contains(t, sheet, `<c><v>999.5</v></c>`)
+	assert.Contains(t, sheet, `&lt;Cable &amp; Co&gt;`)
+	assert.Contains(t, sheet, `<c/></row></sheetData>`)
+}
+
+func TestNewWriter_UnsupportedFormat(t *testing.T) {
+	_, err := NewWriter(io.Discard, Format("pdf"), "", testColumns)
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,159 @@
+package gql
+
+import (
+	"backend-store/internal/service"
+	"backend-store/internal/storage"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// Request - тело запроса GraphQL.
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Handler выполняет запросы GraphQL к каталогу и заказам.
+type Handler struct {
+	schema  graphql.Schema
+	storage storage.Storage
+	limits  Limits
+}
+
+func NewHandler(productService service.ProductService, orderService service.OrderService, customerService service.CustomerService, storage storage.Storage, limits Limits) (*Handler, error) {
+	schema, err := newSchema(&resolver{
+		productService:  productService,
+		orderService:    orderService,
+		customerService: customerService,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Handler{schema: schema, storage: storage, limits: limits}, nil
+}
+
+// errRequest - ошибка самого запроса (разбор, валидация, лимиты), до
+// выполнения.
+var errRequest = errors.New("invalid graphql request")
+
+// Execute разбирает, проверяет и выполняет запрос. Ошибка errRequest
+// означает, что запрос не выполнялся; ее подробности - в result.Errors.
+// При readOnly мутации отклоняются.
+func (h *Handler) Execute(ctx context.Context, req Request, readOnly bool) (*graphql.Result, error) {
+	doc, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{Body: []byte(req.Query), Name: "GraphQL request"}),
+	})
+	if err != nil {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}, errRequest
+	}
+
+	validation := graphql.ValidateDocument(&h.schema, doc, nil)
+	if !validation.IsValid {
+		return &graphql.Result{Errors: validation.Errors}, errRequest
+	}
+	if readOnly && hasMutation(doc, req.OperationName) {
+		return requestError("mutations are not allowed in GET requests"), errRequest
+	}
+	if err := h.limits.check(&h.schema, doc, req.OperationName); err != nil {
+		return requestError(err.Error()), errRequest
+	}
+
+	return graphql.Execute(graphql.ExecuteParams{
+		Schema:        h.schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       withLoaders(ctx, newLoaders(h.storage)),
+	}), nil
+}
+
+// Serve обрабатывает POST с JSON-телом и GET с параметрами query,
+// operationName и variables (только чтение).
+func (h *Handler) Serve(c *gin.Context) {
+	var req Request
+	readOnly := c.Request.Method == http.MethodGet
+	if readOnly {
+		req.Query = c.Query("query")
+		req.OperationName = c.Query("operationName")
+		if variables := c.Query("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				c.JSON(http.StatusBadRequest, requestError("invalid variables: "+err.Error()))
+				return
+			}
+		}
+	} else if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, requestError("invalid request body: "+err.Error()))
+		return
+	}
+
+	if req.Query == "" {
+		c.JSON(http.StatusBadRequest, requestError("query is required"))
+		return
+	}
+
+	result, err := h.Execute(c.Request.Context(), req, readOnly)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, result)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// GraphiQL отдает страницу GraphiQL, работающую с endpoint.
+func GraphiQL(endpoint string) gin.HandlerFunc {
+	page := []byte(graphiqlPage(endpoint))
+	return func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/html; charset=utf-8", page)
+	}
+}
+
+func hasMutation(doc *ast.Document, operationName string) bool {
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok || op.Operation != ast.OperationTypeMutation {
+			continue
+		}
+		if operationName == "" || (op.Name != nil && op.Name.Value == operationName) {
+			return true
+		}
+	}
+	return false
+}
+
+func requestError(message string) *graphql.Result {
+	return &graphql.Result{Errors: []gqlerrors.FormattedError{gqlerrors.NewFormattedError(message)}}
+}
+
+func graphiqlPage(endpoint string) string {
+	return `<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <title>GraphiQL</title>
+  <link rel="stylesheet" href="https://unpkg.com/graphiql@3/graphiql.min.css">
+  <style>body { margin: 0; height: 100vh; } #graphiql { height: 100vh; }</style>
+</head>
+<body>
+  <div id="graphiql">Loading...</div>
+  <script crossorigin src="https://unpkg.com/react@18/umd/react.production.min.js"></script>
+  <script crossorigin src="https://unpkg.com/react-dom@18/umd/react-dom.production.min.js"></script>
+  <script crossorigin src="https://unpkg.com/graphiql@3/graphiql.min.js"></script>
+  <script>
+    const fetcher = GraphiQL.createFetcher({ url: '` + endpoint + `' });
+    ReactDOM.createRoot(document.getElementById('graphiql'))
+      .render(React.createElement(GraphiQL, { fetcher: fetcher }));
+  </script>
+</body>
+</html>
+`
+}
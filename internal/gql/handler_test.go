@@ -0,0 +1,343 @@
+package gql
+
+import (
+	"backend-store/internal/models"
+	"backend-store/internal/service"
+	"backend-store/internal/storage"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockProductService реализует интерфейс service.ProductService для тестов;
+// не используемые GraphQL методы не реализованы.
+type MockProductService struct {
+	mock.Mock
+	service.ProductService
+}
+
+func (m *MockProductService) CreateProduct(ctx context.Context, product *models.Product) error {
+	args := m.Called(ctx, product)
+	return args.Error(0)
+}
+
+func (m *MockProductService) GetAllProducts(ctx context.Context, filter models.ProductFilter) ([]*models.Product, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]*models.Product), args.Error(1)
+}
+
+func (m *MockProductService) GetProductByID(ctx context.Context, id int) (*models.Product, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Product), args.Error(1)
+}
+
+// MockOrderService реализует интерфейс service.OrderService для тестов;
+// не используемые GraphQL методы не реализованы.
+type MockOrderService struct {
+	mock.Mock
+	service.OrderService
+}
+
+func (m *MockOrderService) GetAllOrders(ctx context.Context) ([]*models.Order, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*models.Order), args.Error(1)
+}
+
+func (m *MockOrderService) UpdateOrder(ctx context.Context, order *models.Order) error {
+	args := m.Called(ctx, order)
+	return args.Error(0)
+}
+
+// MockCustomerService реализует интерфейс service.CustomerService для
+// тестов; не используемые GraphQL методы не реализованы.
+type MockCustomerService struct {
+	mock.Mock
+	service.CustomerService
+}
+
+// countingStorage считает пакетные запросы к хранилищу.
+type countingStorage struct {
+	*storage.MemoryStorage
+	calls map[string]int
+}
+
+func (s *countingStorage) GetProductsByIDs(ctx context.Context, ids []int) ([]*models.Product, error) {
+	s.calls["products"]++
+	return s.MemoryStorage.GetProductsByIDs(ctx, ids)
+}
+
+func (s *countingStorage) GetCustomersByIDs(ctx context.Context, ids []int) ([]*models.Customer, error) {
+	s.calls["customers"]++
+	return s.MemoryStorage.GetCustomersByIDs(ctx, ids)
+}
+
+func (s *countingStorage) GetCategoryIDsByProducts(ctx context.Context, productIDs []int) (map[int][]int, error) {
+	s.calls["categories"]++
+	return s.MemoryStorage.GetCategoryIDsByProducts(ctx, productIDs)
+}
+
+type testEnv struct {
+	router    *gin.Engine
+	store     *countingStorage
+	products  *MockProductService
+	orders    *MockOrderService
+	customers *MockCustomerService
+}
+
+func setupTest(t *testing.T, limits Limits) *testEnv {
+	gin.SetMode(gin.TestMode)
+	env := &testEnv{
+		store:     &countingStorage{MemoryStorage: storage.NewMemoryStorage(), calls: make(map[string]int)},
+		products:  new(MockProductService),
+		orders:    new(MockOrderService),
+		customers: new(MockCustomerService),
+	}
+	handler, err := NewHandler(env.products, env.orders, env.customers, env.store, limits)
+	require.NoError(t, err)
+
+	env.router = gin.New()
+	env.router.GET("/graphql", handler.Serve)
+	env.router.POST("/graphql", handler.Serve)
+	return env
+}
+
+type testResponse struct {
+	Data   map[string]interface{} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+func (env *testEnv) post(t *testing.T, query string, variables map[string]interface{}) (int, testResponse) {
+	body, _ := json.Marshal(Request{Query: query, Variables: variables})
+	req, _ := http.NewRequest("POST", "/graphql", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	env.router.ServeHTTP(w, req)
+
+	var resp testResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return w.Code, resp
+}
+
+// seedOrders создает трех покупателей, два товара в категории и по заказу
+// на каждого покупателя с обоими товарами.
+func (env *testEnv) seedOrders(t *testing.T) []*models.Order {
+	ctx := context.Background()
+	category := &models.Category{Name: "Tools", Slug: "tools"}
+	require.NoError(t, env.store.CreateCategory(ctx, category))
+
+	var productIDs []int
+	for _, name := range []string{"Hammer", "Saw"} {
+		product := &models.Product{Name: name, Price: 10, Quantity: 5}
+		require.NoError(t, env.store.CreateProduct(ctx, product))
+		require.NoError(t, env.store.SetProductCategories(ctx, product.ID, []int{category.ID}))
+		productIDs = append(productIDs, product.ID)
+	}
+
+	var orders []*models.Order
+	for _, email := range []string{"a@example.com", "b@example.com", "c@example.com"} {
+		customer := &models.Customer{Email: email, Name: email}
+		require.NoError(t, env.store.CreateCustomer(ctx, customer))
+		order := &models.Order{
+			CustomerID: customer.ID,
+			Status:     models.OrderStatusPending,
+			Products: []models.OrderItem{
+				{ProductID: productIDs[0], Quantity: 1, Price: 10},
+				{ProductID: productIDs[1], Quantity: 2, Price: 10},
+			},
+		}
+		require.NoError(t, env.store.CreateOrder(ctx, order))
+		orders = append(orders, order)
+	}
+	return orders
+}
+
+func TestGraphQL_NestedQueryIsBatched(t *testing.T) {
+	// Arrange
+	env := setupTest(t, Limits{})
+	orders := env.seedOrders(t)
+	env.orders.On("GetAllOrders", mock.Anything).Return(orders, nil)
+
+	// Act
+	code, resp := env.post(t, `{
+		orders {
+			id
+			customer { email }
+			products { quantity product { name categories { name } } }
+		}
+	}`, nil)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, code)
+	assert.Empty(t, resp.Errors)
+	list := resp.Data["orders"].([]interface{})
+	require.Len(t, list, 3)
+	first := list[0].(map[string]interface{})
+	assert.Equal(t, "a@example.com", first["customer"].(map[string]interface{})["email"])
+	item := first["products"].([]interface{})[1].(map[string]interface{})
+	product := item["product"].(map[string]interface{})
+	assert.Equal(t, "Saw", product["name"])
+	assert.Equal(t, "Tools", product["categories"].([]interface{})[0].(map[string]interface{})["name"])
+
+	assert.Equal(t, map[string]int{"products": 1, "customers": 1, "categories": 1}, env.store.calls)
+}
+
+func TestGraphQL_ProductNotFoundIsNull(t *testing.T) {
+	// Arrange
+	env := setupTest(t, Limits{})
+	env.products.On("GetProductByID", mock.Anything, 7).Return(nil, errors.New("product not found"))
+
+	// Act
+	code, resp := env.post(t, `query($id: Int!) { product(id: $id) { name } }`, map[string]interface{}{"id": 7})
+
+	// Assert
+	assert.Equal(t, http.StatusOK, code)
+	assert.Empty(t, resp.Errors)
+	assert.Nil(t, resp.Data["product"])
+}
+
+func TestGraphQL_CreateProduct(t *testing.T) {
+	// Arrange
+	env := setupTest(t, Limits{})
+	env.products.On("CreateProduct", mock.Anything, mock.MatchedBy(func(p *models.Product) bool {
+		return p.Name == "Drill" && p.Price == 99.5 && p.Quantity == 3 && p.CategoryIDs == nil && len(p.Tags) == 1
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.Product).ID = 42
+	}).Return(nil)
+
+	// Act
+	code, resp := env.post(t, `mutation {
+		createProduct(input: {name: "Drill", price: 99.5, quantity: 3, tags: ["power"]}) { id name }
+	}`, nil)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, code)
+	assert.Empty(t, resp.Errors)
+	assert.Equal(t, float64(42), resp.Data["createProduct"].(map[string]interface{})["id"])
+	env.products.AssertExpectations(t)
+}
+
+func TestGraphQL_UpdateOrderError(t *testing.T) {
+	// Arrange
+	env := setupTest(t, Limits{})
+	env.orders.On("UpdateOrder", mock.Anything, mock.MatchedBy(func(o *models.Order) bool {
+		return o.ID == 5 && o.CustomerID == 2 && len(o.Products) == 1 && o.Products[0].Quantity == 4
+	})).Return(errors.New("order not found"))
+
+	// Act
+	code, resp := env.post(t, `mutation {
+		updateOrder(id: 5, input: {customerId: 2, products: [{productId: 1, quantity: 4}]}) { id }
+	}`, nil)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, code)
+	require.Len(t, resp.Errors, 1)
+	assert.Equal(t, "order not found", resp.Errors[0].Message)
+	env.orders.AssertExpectations(t)
+}
+
+func TestGraphQL_DepthLimit(t *testing.T) {
+	// Arrange
+	env := setupTest(t, Limits{MaxDepth: 4})
+
+	// Act
+	code, resp := env.post(t, `{ orders { products { product { orders { id } } } } }`, nil)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, code)
+	require.Len(t, resp.Errors, 1)
+	assert.Contains(t, resp.Errors[0].Message, "query depth 5 exceeds the limit of 4")
+	env.orders.AssertNotCalled(t, "GetAllOrders", mock.Anything)
+}
+
+func TestGraphQL_ComplexityLimitWithFragments(t *testing.T) {
+	// Arrange
+	env := setupTest(t, Limits{MaxComplexity: 100})
+
+	// Act
+	code, resp := env.post(t, `
+		{ products { ...Details } }
+		fragment Details on Product { id name orders { id total } }
+	`, nil)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, code)
+	require.Len(t, resp.Errors, 1)
+	assert.Contains(t, resp.Errors[0].Message, "query complexity 231 exceeds the limit of 100")
+}
+
+func TestGraphQL_IntrospectionIgnoresLimits(t *testing.T) {
+	// Arrange
+	env := setupTest(t, Limits{MaxDepth: 2, MaxComplexity: 10})
+
+	// Act
+	code, resp := env.post(t, `{ __schema { types { name fields { name type { name ofType { name ofType { name } } } } } } }`, nil)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, code)
+	assert.Empty(t, resp.Errors)
+	assert.NotNil(t, resp.Data["__schema"])
+}
+
+func TestGraphQL_InvalidQuery(t *testing.T) {
+	// Arrange
+	env := setupTest(t, Limits{})
+
+	// Act
+	code, resp := env.post(t, `{ products { unknownField } }`, nil)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, code)
+	require.NotEmpty(t, resp.Errors)
+	assert.Contains(t, resp.Errors[0].Message, "unknownField")
+}
+
+func TestGraphQL_GetRejectsMutation(t *testing.T) {
+	// Arrange
+	env := setupTest(t, Limits{})
+	query := url.Values{"query": {`mutation { deleteProduct(id: 1) }`}}
+
+	// Act
+	req, _ := http.NewRequest("GET", "/graphql?"+query.Encode(), nil)
+	w := httptest.NewRecorder()
+	env.router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "mutations are not allowed in GET requests")
+}
+
+func TestGraphQL_GetQuery(t *testing.T) {
+	// Arrange
+	env := setupTest(t, Limits{})
+	env.products.On("GetAllProducts", mock.Anything, models.ProductFilter{Tag: "sale"}).
+		Return([]*models.Product{{ID: 1, Name: "Hammer", Price: 10}}, nil)
+	query := url.Values{
+		"query":     {`query($tag: String) { products(tag: $tag) { name } }`},
+		"variables": {`{"tag": "sale"}`},
+	}
+
+	// Act
+	req, _ := http.NewRequest("GET", "/graphql?"+query.Encode(), nil)
+	w := httptest.NewRecorder()
+	env.router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"data":{"products":[{"name":"Hammer"}]}}`, w.Body.String())
+	env.products.AssertExpectations(t)
+}
@@ -0,0 +1,135 @@
+package gql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// listFactor - во сколько раз сложность выборки под списочным полем
+// больше сложности одного элемента: размер списков заранее неизвестен.
+const listFactor = 10
+
+// Limits ограничивает запросы до выполнения: MaxDepth - вложенность полей,
+// MaxComplexity - оценка числа загружаемых значений (каждое поле стоит 1,
+// выборка под списком умножается на listFactor). 0 снимает ограничение.
+// Служебные поля интроспекции (__schema, __type и т.д.) не учитываются.
+type Limits struct {
+	MaxDepth      int
+	MaxComplexity int
+}
+
+// check оценивает операцию operationName документа doc. Документ должен
+// быть уже проверен валидатором: циклов фрагментов в нем нет.
+func (l Limits) check(schema *graphql.Schema, doc *ast.Document, operationName string) error {
+	if l.MaxDepth <= 0 && l.MaxComplexity <= 0 {
+		return nil
+	}
+
+	var operation *ast.OperationDefinition
+	fragments := make(map[string]*ast.FragmentDefinition)
+	for _, def := range doc.Definitions {
+		switch def := def.(type) {
+		case *ast.OperationDefinition:
+			if operationName == "" || (def.Name != nil && def.Name.Value == operationName) {
+				operation = def
+			}
+		case *ast.FragmentDefinition:
+			fragments[def.Name.Value] = def
+		}
+	}
+	if operation == nil {
+		return nil
+	}
+
+	root := schema.QueryType()
+	if operation.Operation == ast.OperationTypeMutation {
+		root = schema.MutationType()
+	}
+
+	cost := queryCost{schema: schema, fragments: fragments}
+	depth, complexity := cost.selectionSet(root, operation.SelectionSet)
+	if l.MaxDepth > 0 && depth > l.MaxDepth {
+		return fmt.Errorf("query depth %d exceeds the limit of %d", depth, l.MaxDepth)
+	}
+	if l.MaxComplexity > 0 && complexity > l.MaxComplexity {
+		return fmt.Errorf("query complexity %d exceeds the limit of %d", complexity, l.MaxComplexity)
+	}
+	return nil
+}
+
+type queryCost struct {
+	schema    *graphql.Schema
+	fragments map[string]*ast.FragmentDefinition
+}
+
+// selectionSet возвращает глубину и сложность выборки set у объекта parent.
+func (c queryCost) selectionSet(parent *graphql.Object, set *ast.SelectionSet) (depth, complexity int) {
+	if set == nil || parent == nil {
+		return 0, 0
+	}
+
+	for _, selection := range set.Selections {
+		var d, cx int
+		switch s := selection.(type) {
+		case *ast.Field:
+			d, cx = c.field(parent, s)
+		case *ast.InlineFragment:
+			d, cx = c.selectionSet(c.condition(parent, s.TypeCondition), s.SelectionSet)
+		case *ast.FragmentSpread:
+			if fragment, ok := c.fragments[s.Name.Value]; ok {
+				d, cx = c.selectionSet(c.condition(parent, fragment.TypeCondition), fragment.SelectionSet)
+			}
+		}
+		depth = max(depth, d)
+		complexity += cx
+	}
+	return depth, complexity
+}
+
+func (c queryCost) field(parent *graphql.Object, field *ast.Field) (depth, complexity int) {
+	if strings.HasPrefix(field.Name.Value, "__") {
+		return 0, 0
+	}
+	def, ok := parent.Fields()[field.Name.Value]
+	if !ok {
+		return 1, 1
+	}
+
+	fieldType, list := unwrapType(def.Type)
+	object, _ := fieldType.(*graphql.Object)
+	childDepth, childComplexity := c.selectionSet(object, field.SelectionSet)
+	if list {
+		childComplexity *= listFactor
+	}
+	return childDepth + 1, childComplexity + 1
+}
+
+func (c queryCost) condition(parent *graphql.Object, named *ast.Named) *graphql.Object {
+	if named == nil {
+		return parent
+	}
+	if object, ok := c.schema.Type(named.Name.Value).(*graphql.Object); ok {
+		return object
+	}
+	return parent
+}
+
+// unwrapType снимает с типа обертки NonNull и List и сообщает, был ли
+// среди них список.
+func unwrapType(t graphql.Type) (graphql.Type, bool) {
+	list := false
+	for {
+		switch wrapped := t.(type) {
+		case *graphql.NonNull:
+			t = wrapped.OfType
+		case *graphql.List:
+			list = true
+			t = wrapped.OfType
+		default:
+			return t, list
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package gql
+
+import (
+	"backend-store/internal/models"
+	"backend-store/internal/storage"
+	"context"
+	"sync"
+)
+
+// batchLoader собирает ключи, запрошенные резолверами, и загружает их
+// одним вызовом fetch при первом обращении к результату. graphql-go
+// вызывает отложенные функции резолверов (thunk) только после того, как
+// отработали резолверы всех соседних полей, поэтому к этому моменту ключи
+// всего уровня запроса уже собраны. Загруженные значения кэшируются до
+// конца запроса.
+type batchLoader struct {
+	mu      sync.Mutex
+	fetch   func(ctx context.Context, keys []int) (map[int]interface{}, error)
+	pending []int
+	queued  map[int]bool
+	results map[int]interface{}
+	errs    map[int]error
+}
+
+func newBatchLoader(fetch func(ctx context.Context, keys []int) (map[int]interface{}, error)) *batchLoader {
+	return &batchLoader{
+		fetch:   fetch,
+		queued:  make(map[int]bool),
+		results: make(map[int]interface{}),
+		errs:    make(map[int]error),
+	}
+}
+
+// load ставит key в очередь и возвращает thunk с результатом. Для
+// отсутствующего ключа результат - nil.
+func (l *batchLoader) load(ctx context.Context, key int) func() (interface{}, error) {
+	l.mu.Lock()
+	if !l.queued[key] {
+		l.queued[key] = true
+		l.pending = append(l.pending, key)
+	}
+	l.mu.Unlock()
+
+	return func() (interface{}, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		if _, done := l.results[key]; !done && l.errs[key] == nil {
+			l.flush(ctx)
+		}
+		return l.results[key], l.errs[key]
+	}
+}
+
+func (l *batchLoader) flush(ctx context.Context) {
+	keys := l.pending
+	l.pending = nil
+
+	found, err := l.fetch(ctx, keys)
+	for _, key := range keys {
+		if err != nil {
+			l.errs[key] = err
+			continue
+		}
+		l.results[key] = found[key]
+	}
+}
+
+// loaders - загрузчики одного запроса GraphQL.
+type loaders struct {
+	products       *batchLoader
+	customers      *batchLoader
+	categories     *batchLoader
+	tags           *batchLoader
+	stock          *batchLoader
+	productOrders  *batchLoader
+	customerOrders *batchLoader
+}
+
+type loadersKey struct{}
+
+func newLoaders(store storage.Storage) *loaders {
+	return &loaders{
+		products: newBatchLoader(func(ctx context.Context, ids []int) (map[int]interface{}, error) {
+			products, err := store.GetProductsByIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			result := make(map[int]interface{}, len(products))
+			for _, p := range products {
+				result[p.ID] = p
+			}
+			return result, nil
+		}),
+		customers: newBatchLoader(func(ctx context.Context, ids []int) (map[int]interface{}, error) {
+			customers, err := store.GetCustomersByIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			result := make(map[int]interface{}, len(customers))
+			for _, c := range customers {
+				result[c.ID] = c
+			}
+			return result, nil
+		}),
+		categories: newBatchLoader(func(ctx context.Context, productIDs []int) (map[int]interface{}, error) {
+			idsByProduct, err := store.GetCategoryIDsByProducts(ctx, productIDs)
+			if err != nil {
+				return nil, err
+			}
+			var ids []int
+			for _, categoryIDs := range idsByProduct {
+				ids = append(ids, categoryIDs...)
+			}
+			categories, err := store.GetCategoriesByIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			byID := make(map[int]*models.Category, len(categories))
+			for _, c := range categories {
+				byID[c.ID] = c
+			}
+
+			result := make(map[int]interface{}, len(productIDs))
+			for _, productID := range productIDs {
+				list := []*models.Category{}
+				for _, id := range idsByProduct[productID] {
+					if c, ok := byID[id]; ok {
+						list = append(list, c)
+					}
+				}
+				result[productID] = list
+			}
+			return result, nil
+		}),
+		tags: newBatchLoader(func(ctx context.Context, productIDs []int) (map[int]interface{}, error) {
+			tagsByProduct, err := store.GetTagsByProducts(ctx, productIDs)
+			if err != nil {
+				return nil, err
+			}
+			result := make(map[int]interface{}, len(productIDs))
+			for _, id := range productIDs {
+				tags := tagsByProduct[id]
+				if tags == nil {
+					tags = []string{}
+				}
+				result[id] = tags
+			}
+			return result, nil
+		}),
+		stock: newBatchLoader(func(ctx context.Context, productIDs []int) (map[int]interface{}, error) {
+			levels, err := store.GetStockLevelsByProducts(ctx, productIDs)
+			if err != nil {
+				return nil, err
+			}
+			byProduct := make(map[int][]models.StockLevel)
+			for _, l := range levels {
+				byProduct[l.ProductID] = append(byProduct[l.ProductID], l)
+			}
+			result := make(map[int]interface{}, len(productIDs))
+			for _, id := range productIDs {
+				list := byProduct[id]
+				if list == nil {
+					list = []models.StockLevel{}
+				}
+				result[id] = list
+			}
+			return result, nil
+		}),
+		productOrders: newBatchLoader(func(ctx context.Context, productIDs []int) (map[int]interface{}, error) {
+			idsByProduct, err := store.GetOrderIDsByProducts(ctx, productIDs)
+			if err != nil {
+				return nil, err
+			}
+			return groupOrders(ctx, store, productIDs, idsByProduct)
+		}),
+		customerOrders: newBatchLoader(func(ctx context.Context, customerIDs []int) (map[int]interface{}, error) {
+			idsByCustomer, err := store.GetOrderIDsByCustomers(ctx, customerIDs)
+			if err != nil {
+				return nil, err
+			}
+			return groupOrders(ctx, store, customerIDs, idsByCustomer)
+		}),
+	}
+}
+
+// groupOrders загружает заказы idsByKey одним запросом и раскладывает их
+// по ключам.
+func groupOrders(ctx context.Context, store storage.Storage, keys []int, idsByKey map[int][]int) (map[int]interface{}, error) {
+	var ids []int
+	for _, orderIDs := range idsByKey {
+		ids = append(ids, orderIDs...)
+	}
+	orders, err := store.GetOrdersByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int]*models.Order, len(orders))
+	for _, o := range orders {
+		byID[o.ID] = o
+	}
+
+	result := make(map[int]interface{}, len(keys))
+	for _, key := range keys {
+		list := []*models.Order{}
+		for _, id := range idsByKey[key] {
+			if o, ok := byID[id]; ok {
+				list = append(list, o)
+			}
+		}
+		result[key] = list
+	}
+	return result, nil
+}
+
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
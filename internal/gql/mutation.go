@@ -0,0 +1,196 @@
+package gql
+
+import (
+	"backend-store/internal/models"
+
+	"github.com/graphql-go/graphql"
+)
+
+var productInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "ProductInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"sku":         &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"name":        &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		"description": &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"price":       &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.Float)},
+		"quantity":    &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.Int)},
+		"taxClass":    &graphql.InputObjectFieldConfig{Type: graphql.String},
+		// Отсутствующие categoryIds и tags при обновлении не меняются.
+		"categoryIds": &graphql.InputObjectFieldConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.Int))},
+		"tags":        &graphql.InputObjectFieldConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+	},
+})
+
+var orderItemInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "OrderItemInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"productId": &graphql.InputObjectFieldConfig{Type: graphql.Int},
+		"variantId": &graphql.InputObjectFieldConfig{Type: graphql.Int},
+		"quantity":  &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.Int)},
+	},
+})
+
+var addressInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "AddressInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"line1":      &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"line2":      &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"city":       &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"region":     &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"postalCode": &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"country":    &graphql.InputObjectFieldConfig{Type: graphql.String},
+	},
+})
+
+var orderInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "OrderInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"customerId":      &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.Int)},
+		"status":          &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"products":        &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(orderItemInputType)))},
+		"shippingAddress": &graphql.InputObjectFieldConfig{Type: addressInputType},
+		"promoCode":       &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"shippingMethod":  &graphql.InputObjectFieldConfig{Type: graphql.String},
+	},
+})
+
+// mutation описывает изменения каталога и заказов. Проверки и побочные
+// эффекты (склад, события, вебхуки) остаются в сервисах.
+func (r *resolver) mutation(productType, orderType *graphql.Object) *graphql.Object {
+	idArg := &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)}
+
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createProduct": &graphql.Field{
+				Type: productType,
+				Args: graphql.FieldConfigArgument{
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(productInputType)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					product := productFromInput(p.Args["input"].(map[string]interface{}))
+					if err := r.productService.CreateProduct(p.Context, product); err != nil {
+						return nil, err
+					}
+					return product, nil
+				},
+			},
+			"updateProduct": &graphql.Field{
+				Type: productType,
+				Args: graphql.FieldConfigArgument{
+					"id":    idArg,
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(productInputType)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					product := productFromInput(p.Args["input"].(map[string]interface{}))
+					product.ID = p.Args["id"].(int)
+					if err := r.productService.UpdateProduct(p.Context, product); err != nil {
+						return nil, err
+					}
+					return product, nil
+				},
+			},
+			"deleteProduct": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Boolean),
+				Args: graphql.FieldConfigArgument{"id": idArg},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					if err := r.productService.DeleteProduct(p.Context, p.Args["id"].(int)); err != nil {
+						return nil, err
+					}
+					return true, nil
+				},
+			},
+			"createOrder": &graphql.Field{
+				Type: orderType,
+				Args: graphql.FieldConfigArgument{
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(orderInputType)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					order := orderFromInput(p.Args["input"].(map[string]interface{}))
+					if err := r.orderService.CreateOrder(p.Context, order); err != nil {
+						return nil, err
+					}
+					return order, nil
+				},
+			},
+			"updateOrder": &graphql.Field{
+				Type: orderType,
+				Args: graphql.FieldConfigArgument{
+					"id":    idArg,
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(orderInputType)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					order := orderFromInput(p.Args["input"].(map[string]interface{}))
+					order.ID = p.Args["id"].(int)
+					if err := r.orderService.UpdateOrder(p.Context, order); err != nil {
+						return nil, err
+					}
+					return order, nil
+				},
+			},
+			"deleteOrder": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Boolean),
+				Args: graphql.FieldConfigArgument{"id": idArg},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					if err := r.orderService.DeleteOrder(p.Context, p.Args["id"].(int)); err != nil {
+						return nil, err
+					}
+					return true, nil
+				},
+			},
+		},
+	})
+}
+
+func productFromInput(input map[string]interface{}) *models.Product {
+	product := &models.Product{}
+	product.SKU, _ = input["sku"].(string)
+	product.Name, _ = input["name"].(string)
+	product.Description, _ = input["description"].(string)
+	product.Price, _ = input["price"].(float64)
+	product.Quantity, _ = input["quantity"].(int)
+	product.TaxClass, _ = input["taxClass"].(string)
+
+	if ids, ok := input["categoryIds"].([]interface{}); ok {
+		product.CategoryIDs = make([]int, 0, len(ids))
+		for _, id := range ids {
+			product.CategoryIDs = append(product.CategoryIDs, id.(int))
+		}
+	}
+	if tags, ok := input["tags"].([]interface{}); ok {
+		product.Tags = make([]string, 0, len(tags))
+		for _, tag := range tags {
+			product.Tags = append(product.Tags, tag.(string))
+		}
+	}
+	return product
+}
+
+func orderFromInput(input map[string]interface{}) *models.Order {
+	order := &models.Order{}
+	order.CustomerID, _ = input["customerId"].(int)
+	order.Status, _ = input["status"].(string)
+	order.PromoCode, _ = input["promoCode"].(string)
+	order.ShippingMethod, _ = input["shippingMethod"].(string)
+
+	items, _ := input["products"].([]interface{})
+	for _, raw := range items {
+		item := raw.(map[string]interface{})
+		var oi models.OrderItem
+		oi.ProductID, _ = item["productId"].(int)
+		oi.VariantID, _ = item["variantId"].(int)
+		oi.Quantity, _ = item["quantity"].(int)
+		order.Products = append(order.Products, oi)
+	}
+
+	if address, ok := input["shippingAddress"].(map[string]interface{}); ok {
+		order.ShippingAddress = &models.Address{}
+		order.ShippingAddress.Line1, _ = address["line1"].(string)
+		order.ShippingAddress.Line2, _ = address["line2"].(string)
+		order.ShippingAddress.City, _ = address["city"].(string)
+		order.ShippingAddress.Region, _ = address["region"].(string)
+		order.ShippingAddress.PostalCode, _ = address["postalCode"].(string)
+		order.ShippingAddress.Country, _ = address["country"].(string)
+	}
+	return order
+}
@@ -0,0 +1,246 @@
+package gql
+
+import (
+	"backend-store/internal/models"
+	"backend-store/internal/service"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+)
+
+// resolver связывает корневые поля схемы с сервисами. Вложенные связи
+// (товары позиций заказа, покупатели, категории и т.д.) загружаются
+// пакетно через loaders, а не по одному запросу на объект.
+type resolver struct {
+	productService  service.ProductService
+	orderService    service.OrderService
+	customerService service.CustomerService
+}
+
+func newSchema(r *resolver) (graphql.Schema, error) {
+	categoryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Category",
+		Fields: graphql.Fields{
+			"id":        &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"parentId":  &graphql.Field{Type: graphql.Int},
+			"name":      &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"slug":      &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"position":  &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"createdAt": &graphql.Field{Type: graphql.DateTime},
+			"updatedAt": &graphql.Field{Type: graphql.DateTime},
+		},
+	})
+
+	stockLevelType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "StockLevel",
+		Fields: graphql.Fields{
+			"warehouseId": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"variantId":   &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"quantity":    &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"updatedAt":   &graphql.Field{Type: graphql.DateTime},
+		},
+	})
+
+	addressType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Address",
+		Fields: graphql.Fields{
+			"line1":      &graphql.Field{Type: graphql.String},
+			"line2":      &graphql.Field{Type: graphql.String},
+			"city":       &graphql.Field{Type: graphql.String},
+			"region":     &graphql.Field{Type: graphql.String},
+			"postalCode": &graphql.Field{Type: graphql.String},
+			"country":    &graphql.Field{Type: graphql.String},
+		},
+	})
+
+	var productType, orderType, customerType *graphql.Object
+
+	orderItemType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "OrderItem",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":        &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"productId": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"variantId": &graphql.Field{Type: graphql.Int},
+				"quantity":  &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"price":     &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"taxClass":  &graphql.Field{Type: graphql.String},
+				"tax":       &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"product": &graphql.Field{
+					Type: productType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						item := p.Source.(models.OrderItem)
+						return loadersFrom(p.Context).products.load(p.Context, item.ProductID), nil
+					},
+				},
+			}
+		}),
+	})
+
+	productType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Product",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":          &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"sku":         &graphql.Field{Type: graphql.String},
+				"name":        &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"description": &graphql.Field{Type: graphql.String},
+				"price":       &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+				"quantity":    &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"taxClass":    &graphql.Field{Type: graphql.String},
+				"createdAt":   &graphql.Field{Type: graphql.DateTime},
+				"updatedAt":   &graphql.Field{Type: graphql.DateTime},
+				"categories": &graphql.Field{
+					Type:    graphql.NewList(graphql.NewNonNull(categoryType)),
+					Resolve: productLoader(func(l *loaders) *batchLoader { return l.categories }),
+				},
+				"tags": &graphql.Field{
+					Type:    graphql.NewList(graphql.NewNonNull(graphql.String)),
+					Resolve: productLoader(func(l *loaders) *batchLoader { return l.tags }),
+				},
+				"stock": &graphql.Field{
+					Type:    graphql.NewList(graphql.NewNonNull(stockLevelType)),
+					Resolve: productLoader(func(l *loaders) *batchLoader { return l.stock }),
+				},
+				"orders": &graphql.Field{
+					Type:    graphql.NewList(graphql.NewNonNull(orderType)),
+					Resolve: productLoader(func(l *loaders) *batchLoader { return l.productOrders }),
+				},
+			}
+		}),
+	})
+
+	orderType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Order",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":              &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"status":          &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"customerId":      &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"products":        &graphql.Field{Type: graphql.NewList(graphql.NewNonNull(orderItemType))},
+				"shippingAddress": &graphql.Field{Type: addressType},
+				"promoCode":       &graphql.Field{Type: graphql.String},
+				"shippingMethod":  &graphql.Field{Type: graphql.String},
+				"subtotal":        &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"discount":        &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"shipping":        &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"tax":             &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"taxInclusive":    &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+				"total":           &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"createdAt":       &graphql.Field{Type: graphql.DateTime},
+				"updatedAt":       &graphql.Field{Type: graphql.DateTime},
+				"customer": &graphql.Field{
+					Type: customerType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						order := p.Source.(*models.Order)
+						return loadersFrom(p.Context).customers.load(p.Context, order.CustomerID), nil
+					},
+				},
+			}
+		}),
+	})
+
+	customerType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Customer",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":        &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"email":     &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"name":      &graphql.Field{Type: graphql.String},
+				"phone":     &graphql.Field{Type: graphql.String},
+				"createdAt": &graphql.Field{Type: graphql.DateTime},
+				"updatedAt": &graphql.Field{Type: graphql.DateTime},
+				"orders": &graphql.Field{
+					Type: graphql.NewList(graphql.NewNonNull(orderType)),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						customer := p.Source.(*models.Customer)
+						return loadersFrom(p.Context).customerOrders.load(p.Context, customer.ID), nil
+					},
+				},
+			}
+		}),
+	})
+
+	idArg := graphql.FieldConfigArgument{"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)}}
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"product": &graphql.Field{
+				Type: productType,
+				Args: idArg,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					product, err := r.productService.GetProductByID(p.Context, p.Args["id"].(int))
+					return nullIfNotFound(product, err)
+				},
+			},
+			"products": &graphql.Field{
+				Type: graphql.NewList(graphql.NewNonNull(productType)),
+				Args: graphql.FieldConfigArgument{
+					"categoryId": &graphql.ArgumentConfig{Type: graphql.Int},
+					"tag":        &graphql.ArgumentConfig{Type: graphql.String},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					filter := models.ProductFilter{}
+					filter.CategoryID, _ = p.Args["categoryId"].(int)
+					filter.Tag, _ = p.Args["tag"].(string)
+					return r.productService.GetAllProducts(p.Context, filter)
+				},
+			},
+			"order": &graphql.Field{
+				Type: orderType,
+				Args: idArg,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					order, err := r.orderService.GetOrderByID(p.Context, p.Args["id"].(int))
+					return nullIfNotFound(order, err)
+				},
+			},
+			"orders": &graphql.Field{
+				Type: graphql.NewList(graphql.NewNonNull(orderType)),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return r.orderService.GetAllOrders(p.Context)
+				},
+			},
+			"customer": &graphql.Field{
+				Type: customerType,
+				Args: idArg,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					customer, err := r.customerService.GetCustomerByID(p.Context, p.Args["id"].(int))
+					return nullIfNotFound(customer, err)
+				},
+			},
+			"customers": &graphql.Field{
+				Type: graphql.NewList(graphql.NewNonNull(customerType)),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return r.customerService.GetAllCustomers(p.Context)
+				},
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{
+		Query:    query,
+		Mutation: r.mutation(productType, orderType),
+	})
+}
+
+// productLoader возвращает резолвер поля товара, загружаемого пакетно по
+// ID товара.
+func productLoader(pick func(l *loaders) *batchLoader) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		product := p.Source.(*models.Product)
+		return pick(loadersFrom(p.Context)).load(p.Context, product.ID), nil
+	}
+}
+
+// nullIfNotFound превращает ошибку "not found" в null, как принято для
+// полей запроса по ID.
+func nullIfNotFound(value interface{}, err error) (interface{}, error) {
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return value, nil
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"backend-store/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ActorHeader - заголовок, которым сотрудник указывает инициатора изменений
+// для журнала движения товара и журнала аудита. Аутентификации в API нет,
+// поэтому имя не проверяется и записывается как неподтвержденное
+// (actor_verified = false).
+const ActorHeader = "X-Actor"
+
+const maxActorLength = 100
+
+// Actor переносит инициатора запроса в контекст. Без заголовка изменения
+// записываются от имени "api".
+func Actor() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor := c.GetHeader(ActorHeader)
+		if actor == "" {
+			c.Request = c.Request.WithContext(service.WithActor(c.Request.Context(), "api"))
+			c.Next()
+			return
+		}
+		if len(actor) > maxActorLength {
+			actor = actor[:maxActorLength]
+		}
+		c.Request = c.Request.WithContext(service.WithClaimedActor(c.Request.Context(), actor))
+		c.Next()
+	}
+}
+
+// Storefront отклоняет ActorHeader на маршрутах покупателей: от их имени
+// нельзя записать в журналы произвольного сотрудника.
+func Storefront() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader(ActorHeader) != "" {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": ActorHeader + " header is only accepted on staff routes"})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStorefront_RejectsActor(t *testing.T) {
+	// Arrange
+	router := setupRouter()
+	router.Use(Actor())
+	router.POST("/cart/", Storefront(), func(c *gin.Context) { c.Status(http.StatusCreated) })
+	send := func(actor string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("POST", "/cart/", nil)
+		if actor != "" {
+			req.Header.Set(ActorHeader, actor)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Act
+	anonymous := send("")
+	claimed := send("alice")
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, anonymous.Code)
+	assert.Equal(t, http.StatusBadRequest, claimed.Code)
+	assert.Contains(t, claimed.Body.String(), "staff routes")
+}
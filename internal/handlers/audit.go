@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"backend-store/internal/models"
+	"backend-store/internal/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditHandler struct {
+	auditService service.AuditService
+}
+
+func NewAuditHandler(auditService service.AuditService) *AuditHandler {
+	return &AuditHandler{auditService: auditService}
+}
+
+// GetAuditLog возвращает записи журнала аудита, например
+// /api/audit?entity=product&id=5.
+func (h *AuditHandler) GetAuditLog(c *gin.Context) {
+	filter := models.AuditFilter{EntityType: c.Query("entity")}
+	if id := c.Query("id"); id != "" {
+		n, err := strconv.Atoi(id)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid entity ID"})
+			return
+		}
+		filter.EntityID = n
+	}
+	if limit := c.Query("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+			return
+		}
+		filter.Limit = n
+	}
+
+	entries, err := h.auditService.GetAuditLog(c.Request.Context(), filter)
+	if err != nil {
+		if contains(err.Error(), "validate") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch audit log: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"entries": entries})
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"backend-store/internal/models"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAuditService реализует интерфейс service.AuditService для тестов
+type MockAuditService struct {
+	mock.Mock
+}
+
+func (m *MockAuditService) GetAuditLog(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.AuditEntry), args.Error(1)
+}
+
+func (m *MockAuditService) PurgeAuditLog(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+func setupAuditRouter(mockService *MockAuditService) *gin.Engine {
+	handler := NewAuditHandler(mockService)
+	router := setupRouter()
+	router.GET("/audit", handler.GetAuditLog)
+	return router
+}
+
+func TestAuditHandler_GetAuditLog(t *testing.T) {
+	// Arrange
+	mockService := new(MockAuditService)
+	router := setupAuditRouter(mockService)
+
+	filter := models.AuditFilter{EntityType: models.AggregateProduct, EntityID: 5, Limit: 20}
+	entries := []models.AuditEntry{{
+		ID: 3, Actor: "alice", Action: models.AuditUpdate, EntityType: models.AggregateProduct, EntityID: 5,
+		Changes: models.AuditChanges{"price": {Before: json.RawMessage("10"), After: json.RawMessage("12")}},
+	}}
+	mockService.On("GetAuditLog", mock.Anything, filter).Return(entries, nil)
+
+	// Act
+	req, _ := http.NewRequest("GET", "/audit?entity=product&id=5&limit=20", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Entries []models.AuditEntry `json:"entries"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Len(t, response.Entries, 1)
+	assert.Equal(t, "alice", response.Entries[0].Actor)
+	assert.JSONEq(t, "12", string(response.Entries[0].Changes["price"].After))
+	mockService.AssertExpectations(t)
+}
+
+func TestAuditHandler_GetAuditLog_InvalidQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{name: "id", query: "entity=product&id=abc"},
+		{name: "zero id", query: "entity=product&id=0"},
+		{name: "limit", query: "limit=-5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockService := new(MockAuditService)
+			router := setupAuditRouter(mockService)
+
+			// Act
+			req, _ := http.NewRequest("GET", "/audit?"+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			mockService.AssertNotCalled(t, "GetAuditLog", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestAuditHandler_GetAuditLog_ValidationError(t *testing.T) {
+	// Arrange
+	mockService := new(MockAuditService)
+	router := setupAuditRouter(mockService)
+
+	mockService.On("GetAuditLog", mock.Anything, models.AuditFilter{EntityID: 5}).
+		Return(nil, errors.New("validate: entity is required when id is set"))
+
+	// Act
+	req, _ := http.NewRequest("GET", "/audit?id=5", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "entity is required")
+}
+
+func TestRequestID(t *testing.T) {
+	// Arrange
+	router := setupRouter()
+	router.Use(RequestID())
+	router.GET("/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	// Act
+	generated := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/ping", nil)
+	router.ServeHTTP(generated, req)
+
+	provided := httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/ping", nil)
+	req.Header.Set(RequestIDHeader, "req-42")
+	router.ServeHTTP(provided, req)
+
+	// Assert
+	assert.Len(t, generated.Header().Get(RequestIDHeader), 32)
+	assert.Equal(t, "req-42", provided.Header().Get(RequestIDHeader))
+}
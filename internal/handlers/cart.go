@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"backend-store/internal/models"
+	"backend-store/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CartTokenHeader - заголовок с токеном корзины, выданным при ее создании.
+const CartTokenHeader = "X-Cart-Token"
+
+type CartHandler struct {
+	cartService service.CartService
+}
+
+func NewCartHandler(cartService service.CartService) *CartHandler {
+	return &CartHandler{cartService: cartService}
+}
+
+type createCartRequest struct {
+	UserID int `json:"user_id"`
+}
+
+type cartItemRequest struct {
+	ProductID int `json:"product_id"`
+	VariantID int `json:"variant_id"`
+	Quantity  int `json:"quantity"`
+}
+
+type mergeCartRequest struct {
+	UserID int `json:"user_id"`
+}
+
+type checkoutRequest struct {
+	ShippingAddress *models.Address `json:"shipping_address"`
+	PromoCode       string          `json:"promo_code"`
+	ShippingMethod  string          `json:"shipping_method"`
+}
+
+// CreateCart создает корзину. Тело необязательно: без user_id корзина
+// анонимная.
+func (h *CartHandler) CreateCart(c *gin.Context) {
+	var req createCartRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+			return
+		}
+	}
+
+	cart, err := h.cartService.CreateCart(c.Request.Context(), req.UserID)
+	if err != nil {
+		respondCartError(c, err, "Failed to create cart: ")
+		return
+	}
+
+	c.JSON(http.StatusCreated, cart)
+}
+
+func (h *CartHandler) GetCart(c *gin.Context) {
+	id, ok := parseID(c, "id", "Invalid cart ID")
+	if !ok {
+		return
+	}
+
+	cart, err := h.cartService.GetCart(c.Request.Context(), id, c.GetHeader(CartTokenHeader))
+	if err != nil {
+		respondCartError(c, err, "Failed to fetch cart: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, cart)
+}
+
+func (h *CartHandler) AddItem(c *gin.Context) {
+	id, ok := parseID(c, "id", "Invalid cart ID")
+	if !ok {
+		return
+	}
+
+	var req cartItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	item := models.CartItem{ProductID: req.ProductID, VariantID: req.VariantID, Quantity: req.Quantity}
+	cart, err := h.cartService.AddItem(c.Request.Context(), id, c.GetHeader(CartTokenHeader), item)
+	if err != nil {
+		respondCartError(c, err, "Failed to add cart item: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, cart)
+}
+
+func (h *CartHandler) UpdateItem(c *gin.Context) {
+	id, ok := parseID(c, "id", "Invalid cart ID")
+	if !ok {
+		return
+	}
+	itemID, ok := parseID(c, "itemId", "Invalid cart item ID")
+	if !ok {
+		return
+	}
+
+	var req stockLevelRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Quantity == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: quantity is required"})
+		return
+	}
+
+	cart, err := h.cartService.UpdateItem(c.Request.Context(), id, c.GetHeader(CartTokenHeader), itemID, *req.Quantity)
+	if err != nil {
+		respondCartError(c, err, "Failed to update cart item: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, cart)
+}
+
+func (h *CartHandler) RemoveItem(c *gin.Context) {
+	id, ok := parseID(c, "id", "Invalid cart ID")
+	if !ok {
+		return
+	}
+	itemID, ok := parseID(c, "itemId", "Invalid cart item ID")
+	if !ok {
+		return
+	}
+
+	cart, err := h.cartService.RemoveItem(c.Request.Context(), id, c.GetHeader(CartTokenHeader), itemID)
+	if err != nil {
+		respondCartError(c, err, "Failed to remove cart item: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, cart)
+}
+
+// MergeCart вызывается клиентом при входе пользователя: анонимная корзина
+// переносится в корзину пользователя.
+func (h *CartHandler) MergeCart(c *gin.Context) {
+	id, ok := parseID(c, "id", "Invalid cart ID")
+	if !ok {
+		return
+	}
+
+	var req mergeCartRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	cart, err := h.cartService.MergeCart(c.Request.Context(), id, c.GetHeader(CartTokenHeader), req.UserID)
+	if err != nil {
+		respondCartError(c, err, "Failed to merge cart: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, cart)
+}
+
+func (h *CartHandler) Checkout(c *gin.Context) {
+	id, ok := parseID(c, "id", "Invalid cart ID")
+	if !ok {
+		return
+	}
+
+	var req checkoutRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+			return
+		}
+	}
+
+	order, err := h.cartService.Checkout(c.Request.Context(), id, c.GetHeader(CartTokenHeader), req.ShippingAddress,
+		req.PromoCode, req.ShippingMethod)
+	if err != nil {
+		respondCartError(c, err, "Failed to check out cart: ")
+		return
+	}
+
+	c.JSON(http.StatusCreated, order)
+}
+
+func respondCartError(c *gin.Context, err error, prefix string) {
+	switch {
+	case contains(err.Error(), "validate"), contains(err.Error(), "invalid"),
+		contains(err.Error(), "address"), contains(err.Error(), "product") && contains(err.Error(), "not found"),
+		contains(err.Error(), "customer") && contains(err.Error(), "not found"):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case contains(err.Error(), "cart expired"):
+		c.JSON(http.StatusGone, gin.H{"error": err.Error()})
+	case contains(err.Error(), "not found"):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case contains(err.Error(), "cannot"), contains(err.Error(), "insufficient"),
+		contains(err.Error(), "already exists"):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": prefix + err.Error()})
+	}
+}
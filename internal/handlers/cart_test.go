@@ -0,0 +1,280 @@
+package handlers
+
+import (
+	"backend-store/internal/models"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockCartService реализует интерфейс service.CartService для тестов
+type MockCartService struct {
+	mock.Mock
+}
+
+func (m *MockCartService) cart(args mock.Arguments) (*models.Cart, error) {
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Cart), args.Error(1)
+}
+
+func (m *MockCartService) CreateCart(ctx context.Context, userID int) (*models.Cart, error) {
+	return m.cart(m.Called(ctx, userID))
+}
+
+func (m *MockCartService) GetCart(ctx context.Context, id int, token string) (*models.Cart, error) {
+	return m.cart(m.Called(ctx, id, token))
+}
+
+func (m *MockCartService) AddItem(ctx context.Context, id int, token string, item models.CartItem) (*models.Cart, error) {
+	return m.cart(m.Called(ctx, id, token, item))
+}
+
+func (m *MockCartService) UpdateItem(ctx context.Context, id int, token string, itemID, quantity int) (*models.Cart, error) {
+	return m.cart(m.Called(ctx, id, token, itemID, quantity))
+}
+
+func (m *MockCartService) RemoveItem(ctx context.Context, id int, token string, itemID int) (*models.Cart, error) {
+	return m.cart(m.Called(ctx, id, token, itemID))
+}
+
+func (m *MockCartService) MergeCart(ctx context.Context, id int, token string, userID int) (*models.Cart, error) {
+	return m.cart(m.Called(ctx, id, token, userID))
+}
+
+func (m *MockCartService) Checkout(ctx context.Context, id int, token string, address *models.Address, promoCode, shippingMethod string) (*models.Order, error) {
+	args := m.Called(ctx, id, token, address, promoCode, shippingMethod)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Order), args.Error(1)
+}
+
+func (m *MockCartService) PurgeExpiredCarts(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+func setupCartRouter(mockService *MockCartService) *gin.Engine {
+	handler := NewCartHandler(mockService)
+	router := setupRouter()
+	router.POST("/carts", handler.CreateCart)
+	router.GET("/carts/:id", handler.GetCart)
+	router.POST("/carts/:id/items", handler.AddItem)
+	router.PUT("/carts/:id/items/:itemId", handler.UpdateItem)
+	router.POST("/carts/:id/merge", handler.MergeCart)
+	router.POST("/carts/:id/checkout", handler.Checkout)
+	return router
+}
+
+func TestCartHandler_CreateCart_Anonymous(t *testing.T) {
+	// Arrange
+	mockService := new(MockCartService)
+	router := setupCartRouter(mockService)
+
+	cart := &models.Cart{ID: 1, Token: "secret", Status: models.CartActive, Items: []models.CartItem{}}
+	mockService.On("CreateCart", mock.Anything, 0).Return(cart, nil)
+
+	// Act
+	req, _ := http.NewRequest("POST", "/carts", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response models.Cart
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, "secret", response.Token)
+	mockService.AssertExpectations(t)
+}
+
+func TestCartHandler_GetCart_PassesToken(t *testing.T) {
+	// Arrange
+	mockService := new(MockCartService)
+	router := setupCartRouter(mockService)
+
+	mockService.On("GetCart", mock.Anything, 1, "wrong").Return(nil, errors.New("cart not found"))
+
+	// Act
+	req, _ := http.NewRequest("GET", "/carts/1", nil)
+	req.Header.Set(CartTokenHeader, "wrong")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestCartHandler_GetCart_Expired(t *testing.T) {
+	// Arrange
+	mockService := new(MockCartService)
+	router := setupCartRouter(mockService)
+
+	mockService.On("GetCart", mock.Anything, 1, "secret").Return(nil, errors.New("cart expired"))
+
+	// Act
+	req, _ := http.NewRequest("GET", "/carts/1", nil)
+	req.Header.Set(CartTokenHeader, "secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusGone, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestCartHandler_AddItem_InsufficientStock(t *testing.T) {
+	// Arrange
+	mockService := new(MockCartService)
+	router := setupCartRouter(mockService)
+
+	item := models.CartItem{ProductID: 3, Quantity: 5}
+	mockService.On("AddItem", mock.Anything, 1, "secret", item).
+		Return(nil, errors.New("insufficient stock: 2 available"))
+
+	body, _ := json.Marshal(gin.H{"product_id": 3, "quantity": 5})
+
+	// Act
+	req, _ := http.NewRequest("POST", "/carts/1/items", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(CartTokenHeader, "secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestCartHandler_UpdateItem_MissingQuantity(t *testing.T) {
+	// Arrange
+	mockService := new(MockCartService)
+	router := setupCartRouter(mockService)
+
+	// Act
+	req, _ := http.NewRequest("PUT", "/carts/1/items/2", bytes.NewBufferString(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "UpdateItem")
+}
+
+func TestCartHandler_MergeCart_Success(t *testing.T) {
+	// Arrange
+	mockService := new(MockCartService)
+	router := setupCartRouter(mockService)
+
+	merged := &models.Cart{ID: 4, UserID: 7, Status: models.CartActive}
+	mockService.On("MergeCart", mock.Anything, 1, "secret", 7).Return(merged, nil)
+
+	// Act
+	req, _ := http.NewRequest("POST", "/carts/1/merge", bytes.NewBufferString(`{"user_id": 7}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(CartTokenHeader, "secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.Cart
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, 4, response.ID)
+	mockService.AssertExpectations(t)
+}
+
+func TestCartHandler_Checkout_Success(t *testing.T) {
+	// Arrange
+	mockService := new(MockCartService)
+	router := setupCartRouter(mockService)
+
+	order := &models.Order{ID: 10, Status: "pending", Total: 33}
+	mockService.On("Checkout", mock.Anything, 1, "secret", (*models.Address)(nil), "", "").Return(order, nil)
+
+	// Act
+	req, _ := http.NewRequest("POST", "/carts/1/checkout", nil)
+	req.Header.Set(CartTokenHeader, "secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response models.Order
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, 10, response.ID)
+	mockService.AssertExpectations(t)
+}
+
+func TestCartHandler_Checkout_RejectedPromoCode(t *testing.T) {
+	// Arrange
+	mockService := new(MockCartService)
+	router := setupCartRouter(mockService)
+
+	mockService.On("Checkout", mock.Anything, 1, "secret", (*models.Address)(nil), "expired", "").
+		Return(nil, errors.New(`validate: promotion "EXPIRED" has expired`))
+
+	// Act
+	req, _ := http.NewRequest("POST", "/carts/1/checkout", bytes.NewBufferString(`{"promo_code":"expired"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(CartTokenHeader, "secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestCartHandler_Checkout_UnavailableShippingMethod(t *testing.T) {
+	// Arrange
+	mockService := new(MockCartService)
+	router := setupCartRouter(mockService)
+
+	mockService.On("Checkout", mock.Anything, 1, "secret", (*models.Address)(nil), "", "express").
+		Return(nil, errors.New(`validate: shipping method "express" is not available for this order`))
+
+	// Act
+	req, _ := http.NewRequest("POST", "/carts/1/checkout", bytes.NewBufferString(`{"shipping_method":"express"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(CartTokenHeader, "secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestCartHandler_Checkout_AnonymousCart(t *testing.T) {
+	// Arrange
+	mockService := new(MockCartService)
+	router := setupCartRouter(mockService)
+
+	mockService.On("Checkout", mock.Anything, 1, "secret", (*models.Address)(nil), "", "").
+		Return(nil, errors.New("cannot check out an anonymous cart: merge it into a user cart first"))
+
+	// Act
+	req, _ := http.NewRequest("POST", "/carts/1/checkout", nil)
+	req.Header.Set(CartTokenHeader, "secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockService.AssertExpectations(t)
+}
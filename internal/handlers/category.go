@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"backend-store/internal/models"
+	"backend-store/internal/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CategoryHandler struct {
+	categoryService service.CategoryService
+}
+
+func NewCategoryHandler(categoryService service.CategoryService) *CategoryHandler {
+	return &CategoryHandler{categoryService: categoryService}
+}
+
+// categoryRequest - тело создания категории; без position категория
+// добавляется последней среди соседей.
+type categoryRequest struct {
+	Name     string `json:"name"`
+	Slug     string `json:"slug"`
+	ParentID *int   `json:"parent_id"`
+	Position *int   `json:"position"`
+}
+
+type moveCategoryRequest struct {
+	ParentID *int `json:"parent_id"`
+	Position *int `json:"position"`
+}
+
+func (h *CategoryHandler) CreateCategory(c *gin.Context) {
+	var req categoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	category := models.Category{
+		Name:     req.Name,
+		Slug:     req.Slug,
+		ParentID: req.ParentID,
+		Position: models.CategoryPositionEnd,
+	}
+	if req.Position != nil {
+		category.Position = *req.Position
+	}
+
+	if err := h.categoryService.CreateCategory(c.Request.Context(), &category); err != nil {
+		respondCategoryError(c, err, "Failed to create category: ")
+		return
+	}
+
+	c.JSON(http.StatusCreated, category)
+}
+
+func (h *CategoryHandler) GetCategoryTree(c *gin.Context) {
+	categories, err := h.categoryService.GetCategoryTree(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch categories: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"categories": categories})
+}
+
+func (h *CategoryHandler) GetCategoryByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+		return
+	}
+
+	category, err := h.categoryService.GetCategoryByID(c.Request.Context(), id)
+	if err != nil {
+		if contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch category: " + err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, category)
+}
+
+func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+		return
+	}
+
+	var req categoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	category := models.Category{ID: id, Name: req.Name, Slug: req.Slug}
+	if err := h.categoryService.UpdateCategory(c.Request.Context(), &category); err != nil {
+		respondCategoryError(c, err, "Failed to update category: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, category)
+}
+
+func (h *CategoryHandler) MoveCategory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+		return
+	}
+
+	var req moveCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	position := models.CategoryPositionEnd
+	if req.Position != nil {
+		position = *req.Position
+	}
+
+	category, err := h.categoryService.MoveCategory(c.Request.Context(), id, req.ParentID, position)
+	if err != nil {
+		respondCategoryError(c, err, "Failed to move category: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, category)
+}
+
+func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+		return
+	}
+
+	reassignTo := 0
+	if v := c.Query("reassign_to"); v != "" {
+		reassignTo, err = strconv.Atoi(v)
+		if err != nil || reassignTo <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid reassign_to category ID"})
+			return
+		}
+	}
+
+	if err := h.categoryService.DeleteCategory(c.Request.Context(), id, reassignTo); err != nil {
+		respondCategoryError(c, err, "Failed to delete category: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Category deleted successfully"})
+}
+
+func respondCategoryError(c *gin.Context, err error, prefix string) {
+	switch {
+	case contains(err.Error(), "parent category not found"), contains(err.Error(), "target category not found"):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case contains(err.Error(), "not found"):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+	case contains(err.Error(), "already exists"), contains(err.Error(), "cannot delete"):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case contains(err.Error(), "category"), contains(err.Error(), "cannot"):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": prefix + err.Error()})
+	}
+}
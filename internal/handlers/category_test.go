@@ -0,0 +1,248 @@
+package handlers
+
+import (
+	"backend-store/internal/models"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockCategoryService реализует интерфейс service.CategoryService для тестов
+type MockCategoryService struct {
+	mock.Mock
+}
+
+func (m *MockCategoryService) CreateCategory(ctx context.Context, category *models.Category) error {
+	args := m.Called(ctx, category)
+	return args.Error(0)
+}
+
+func (m *MockCategoryService) GetCategoryTree(ctx context.Context) ([]*models.Category, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*models.Category), args.Error(1)
+}
+
+func (m *MockCategoryService) GetCategoryByID(ctx context.Context, id int) (*models.Category, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Category), args.Error(1)
+}
+
+func (m *MockCategoryService) UpdateCategory(ctx context.Context, category *models.Category) error {
+	args := m.Called(ctx, category)
+	return args.Error(0)
+}
+
+func (m *MockCategoryService) MoveCategory(ctx context.Context, id int, parentID *int, position int) (*models.Category, error) {
+	args := m.Called(ctx, id, parentID, position)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Category), args.Error(1)
+}
+
+func (m *MockCategoryService) DeleteCategory(ctx context.Context, id, reassignTo int) error {
+	args := m.Called(ctx, id, reassignTo)
+	return args.Error(0)
+}
+
+func TestCategoryHandler_CreateCategory_AppendsByDefault(t *testing.T) {
+	// Arrange
+	mockService := new(MockCategoryService)
+	handler := NewCategoryHandler(mockService)
+	router := setupRouter()
+	router.POST("/categories", handler.CreateCategory)
+
+	mockService.On("CreateCategory", mock.Anything, mock.MatchedBy(func(c *models.Category) bool {
+		return c.Name == "Laptops" && c.Position == models.CategoryPositionEnd && *c.ParentID == 1
+	})).Return(nil).Run(func(args mock.Arguments) {
+		c := args.Get(1).(*models.Category)
+		c.ID = 2
+		c.Slug = "laptops"
+		c.Position = 0
+	})
+
+	// Act
+	req, _ := http.NewRequest("POST", "/categories", bytes.NewBufferString(`{"name":"Laptops","parent_id":1}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response models.Category
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, 2, response.ID)
+	assert.Equal(t, "laptops", response.Slug)
+	mockService.AssertExpectations(t)
+}
+
+func TestCategoryHandler_CreateCategory_DuplicateSlug(t *testing.T) {
+	// Arrange
+	mockService := new(MockCategoryService)
+	handler := NewCategoryHandler(mockService)
+	router := setupRouter()
+	router.POST("/categories", handler.CreateCategory)
+
+	mockService.On("CreateCategory", mock.Anything, mock.AnythingOfType("*models.Category")).
+		Return(errors.New(`failed to create category: category with slug "laptops" already exists`))
+
+	// Act
+	req, _ := http.NewRequest("POST", "/categories", bytes.NewBufferString(`{"name":"Laptops"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestCategoryHandler_GetCategoryTree(t *testing.T) {
+	// Arrange
+	mockService := new(MockCategoryService)
+	handler := NewCategoryHandler(mockService)
+	router := setupRouter()
+	router.GET("/categories", handler.GetCategoryTree)
+
+	parentID := 1
+	tree := []*models.Category{{
+		ID:   1,
+		Name: "Electronics",
+		Slug: "electronics",
+		Children: []*models.Category{
+			{ID: 2, ParentID: &parentID, Name: "Laptops", Slug: "laptops"},
+		},
+	}}
+	mockService.On("GetCategoryTree", mock.Anything).Return(tree, nil)
+
+	// Act
+	req, _ := http.NewRequest("GET", "/categories", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Categories []*models.Category `json:"categories"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Len(t, response.Categories, 1)
+	assert.Len(t, response.Categories[0].Children, 1)
+	assert.Equal(t, "laptops", response.Categories[0].Children[0].Slug)
+	mockService.AssertExpectations(t)
+}
+
+func TestCategoryHandler_GetCategoryByID_NotFound(t *testing.T) {
+	// Arrange
+	mockService := new(MockCategoryService)
+	handler := NewCategoryHandler(mockService)
+	router := setupRouter()
+	router.GET("/categories/:id", handler.GetCategoryByID)
+
+	mockService.On("GetCategoryByID", mock.Anything, 42).Return(nil, errors.New("category not found"))
+
+	// Act
+	req, _ := http.NewRequest("GET", "/categories/42", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestCategoryHandler_MoveCategory_Success(t *testing.T) {
+	// Arrange
+	mockService := new(MockCategoryService)
+	handler := NewCategoryHandler(mockService)
+	router := setupRouter()
+	router.POST("/categories/:id/move", handler.MoveCategory)
+
+	parentID := 3
+	moved := &models.Category{ID: 2, ParentID: &parentID, Name: "Laptops", Slug: "laptops", Position: 1}
+	mockService.On("MoveCategory", mock.Anything, 2, &parentID, 1).Return(moved, nil)
+
+	// Act
+	req, _ := http.NewRequest("POST", "/categories/2/move", bytes.NewBufferString(`{"parent_id":3,"position":1}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"position":1`)
+	mockService.AssertExpectations(t)
+}
+
+func TestCategoryHandler_MoveCategory_IntoSubcategory(t *testing.T) {
+	// Arrange
+	mockService := new(MockCategoryService)
+	handler := NewCategoryHandler(mockService)
+	router := setupRouter()
+	router.POST("/categories/:id/move", handler.MoveCategory)
+
+	mockService.On("MoveCategory", mock.Anything, 1, mock.Anything, models.CategoryPositionEnd).
+		Return(nil, errors.New("cannot move category into itself or its subcategory"))
+
+	// Act
+	req, _ := http.NewRequest("POST", "/categories/1/move", bytes.NewBufferString(`{"parent_id":2}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "cannot move category")
+	mockService.AssertExpectations(t)
+}
+
+func TestCategoryHandler_DeleteCategory_HasProducts(t *testing.T) {
+	// Arrange
+	mockService := new(MockCategoryService)
+	handler := NewCategoryHandler(mockService)
+	router := setupRouter()
+	router.DELETE("/categories/:id", handler.DeleteCategory)
+
+	mockService.On("DeleteCategory", mock.Anything, 1, 0).
+		Return(errors.New("cannot delete category with 3 product(s): reassign them first"))
+
+	// Act
+	req, _ := http.NewRequest("DELETE", "/categories/1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestCategoryHandler_DeleteCategory_Reassign(t *testing.T) {
+	// Arrange
+	mockService := new(MockCategoryService)
+	handler := NewCategoryHandler(mockService)
+	router := setupRouter()
+	router.DELETE("/categories/:id", handler.DeleteCategory)
+
+	mockService.On("DeleteCategory", mock.Anything, 1, 5).Return(nil)
+
+	// Act
+	req, _ := http.NewRequest("DELETE", "/categories/1?reassign_to=5", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"backend-store/internal/models"
+	"backend-store/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CustomerHandler struct {
+	customerService service.CustomerService
+}
+
+func NewCustomerHandler(customerService service.CustomerService) *CustomerHandler {
+	return &CustomerHandler{customerService: customerService}
+}
+
+// customerRequest - тело создания и изменения покупателя. Переданный список
+// адресов заменяет прежний целиком.
+type customerRequest struct {
+	Email     string                   `json:"email"`
+	Name      string                   `json:"name"`
+	Phone     string                   `json:"phone"`
+	Addresses []customerAddressRequest `json:"addresses"`
+}
+
+type customerAddressRequest struct {
+	Kind      string         `json:"kind"`
+	IsDefault bool           `json:"is_default"`
+	Address   models.Address `json:"address"`
+}
+
+func (r customerRequest) customer(id int) models.Customer {
+	customer := models.Customer{
+		ID:        id,
+		Email:     r.Email,
+		Name:      r.Name,
+		Phone:     r.Phone,
+		Addresses: []models.CustomerAddress{},
+	}
+	for _, a := range r.Addresses {
+		customer.Addresses = append(customer.Addresses, models.CustomerAddress{
+			Kind:      a.Kind,
+			IsDefault: a.IsDefault,
+			Address:   a.Address,
+		})
+	}
+	return customer
+}
+
+func (h *CustomerHandler) CreateCustomer(c *gin.Context) {
+	var req customerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	customer := req.customer(0)
+	if err := h.customerService.CreateCustomer(c.Request.Context(), &customer); err != nil {
+		respondCustomerError(c, err, "Failed to create customer: ")
+		return
+	}
+
+	c.JSON(http.StatusCreated, customer)
+}
+
+func (h *CustomerHandler) GetAllCustomers(c *gin.Context) {
+	customers, err := h.customerService.GetAllCustomers(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch customers: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"customers": customers})
+}
+
+func (h *CustomerHandler) GetCustomerByID(c *gin.Context) {
+	id, ok := parseID(c, "id", "Invalid customer ID")
+	if !ok {
+		return
+	}
+
+	customer, err := h.customerService.GetCustomerByID(c.Request.Context(), id)
+	if err != nil {
+		respondCustomerError(c, err, "Failed to fetch customer: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, customer)
+}
+
+func (h *CustomerHandler) UpdateCustomer(c *gin.Context) {
+	id, ok := parseID(c, "id", "Invalid customer ID")
+	if !ok {
+		return
+	}
+
+	var req customerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	customer := req.customer(id)
+	if err := h.customerService.UpdateCustomer(c.Request.Context(), &customer); err != nil {
+		respondCustomerError(c, err, "Failed to update customer: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, customer)
+}
+
+func (h *CustomerHandler) DeleteCustomer(c *gin.Context) {
+	id, ok := parseID(c, "id", "Invalid customer ID")
+	if !ok {
+		return
+	}
+
+	if err := h.customerService.DeleteCustomer(c.Request.Context(), id); err != nil {
+		respondCustomerError(c, err, "Failed to delete customer: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Customer deleted successfully"})
+}
+
+func (h *CustomerHandler) GetCustomerOrders(c *gin.Context) {
+	id, ok := parseID(c, "id", "Invalid customer ID")
+	if !ok {
+		return
+	}
+
+	orders, err := h.customerService.GetCustomerOrders(c.Request.Context(), id)
+	if err != nil {
+		respondCustomerError(c, err, "Failed to fetch customer orders: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"orders": orders})
+}
+
+func respondCustomerError(c *gin.Context, err error, prefix string) {
+	switch {
+	case contains(err.Error(), "validate"), contains(err.Error(), "invalid"):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case contains(err.Error(), "not found"):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Customer not found"})
+	case contains(err.Error(), "already exists"), contains(err.Error(), "cannot delete"):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": prefix + err.Error()})
+	}
+}
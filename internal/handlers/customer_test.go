@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"backend-store/internal/models"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockCustomerService реализует интерфейс service.CustomerService для тестов
+type MockCustomerService struct {
+	mock.Mock
+}
+
+func (m *MockCustomerService) CreateCustomer(ctx context.Context, customer *models.Customer) error {
+	args := m.Called(ctx, customer)
+	return args.Error(0)
+}
+
+func (m *MockCustomerService) GetAllCustomers(ctx context.Context) ([]*models.Customer, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*models.Customer), args.Error(1)
+}
+
+func (m *MockCustomerService) GetCustomerByID(ctx context.Context, id int) (*models.Customer, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Customer), args.Error(1)
+}
+
+func (m *MockCustomerService) UpdateCustomer(ctx context.Context, customer *models.Customer) error {
+	args := m.Called(ctx, customer)
+	return args.Error(0)
+}
+
+func (m *MockCustomerService) DeleteCustomer(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCustomerService) GetCustomerOrders(ctx context.Context, id int) ([]*models.Order, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Order), args.Error(1)
+}
+
+func setupCustomerRouter(mockService *MockCustomerService) *gin.Engine {
+	handler := NewCustomerHandler(mockService)
+	router := setupRouter()
+	router.POST("/customers", handler.CreateCustomer)
+	router.PUT("/customers/:id", handler.UpdateCustomer)
+	router.DELETE("/customers/:id", handler.DeleteCustomer)
+	router.GET("/customers/:id/orders", handler.GetCustomerOrders)
+	return router
+}
+
+func TestCustomerHandler_CreateCustomer_Success(t *testing.T) {
+	// Arrange
+	mockService := new(MockCustomerService)
+	router := setupCustomerRouter(mockService)
+
+	mockService.On("CreateCustomer", mock.Anything, mock.MatchedBy(func(c *models.Customer) bool {
+		return c.Email == "ann@example.com" && len(c.Addresses) == 1 &&
+			c.Addresses[0].Kind == models.AddressShipping && c.Addresses[0].Address.City == "Moscow"
+	})).Return(nil).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.Customer).ID = 1
+	})
+
+	// Act
+	body := `{"email": "ann@example.com", "name": "Ann",
+		"addresses": [{"kind": "shipping", "is_default": true, "address": {"city": "Moscow"}}]}`
+	req, _ := http.NewRequest("POST", "/customers", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response models.Customer
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, 1, response.ID)
+	mockService.AssertExpectations(t)
+}
+
+func TestCustomerHandler_CreateCustomer_DuplicateEmail(t *testing.T) {
+	// Arrange
+	mockService := new(MockCustomerService)
+	router := setupCustomerRouter(mockService)
+
+	mockService.On("CreateCustomer", mock.Anything, mock.AnythingOfType("*models.Customer")).
+		Return(errors.New(`failed to create customer: customer with email "ann@example.com" already exists`))
+
+	// Act
+	req, _ := http.NewRequest("POST", "/customers", bytes.NewBufferString(`{"email": "ann@example.com"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestCustomerHandler_UpdateCustomer_InvalidEmail(t *testing.T) {
+	// Arrange
+	mockService := new(MockCustomerService)
+	router := setupCustomerRouter(mockService)
+
+	mockService.On("UpdateCustomer", mock.Anything, mock.MatchedBy(func(c *models.Customer) bool { return c.ID == 3 })).
+		Return(errors.New(`validate: customer email "ann" is invalid`))
+
+	// Act
+	req, _ := http.NewRequest("PUT", "/customers/3", bytes.NewBufferString(`{"email": "ann"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestCustomerHandler_DeleteCustomer_WithOrders(t *testing.T) {
+	// Arrange
+	mockService := new(MockCustomerService)
+	router := setupCustomerRouter(mockService)
+
+	mockService.On("DeleteCustomer", mock.Anything, 3).
+		Return(errors.New("failed to delete customer: cannot delete customer with orders"))
+
+	// Act
+	req, _ := http.NewRequest("DELETE", "/customers/3", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestCustomerHandler_GetCustomerOrders(t *testing.T) {
+	// Arrange
+	mockService := new(MockCustomerService)
+	router := setupCustomerRouter(mockService)
+
+	orders := []*models.Order{{ID: 5, CustomerID: 3, Status: "pending"}}
+	mockService.On("GetCustomerOrders", mock.Anything, 3).Return(orders, nil)
+	mockService.On("GetCustomerOrders", mock.Anything, 4).Return(nil, errors.New("customer not found"))
+
+	// Act
+	req, _ := http.NewRequest("GET", "/customers/3/orders", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	missingReq, _ := http.NewRequest("GET", "/customers/4/orders", nil)
+	missing := httptest.NewRecorder()
+	router.ServeHTTP(missing, missingReq)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Orders []models.Order `json:"orders"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Len(t, response.Orders, 1)
+	assert.Equal(t, 3, response.Orders[0].CustomerID)
+
+	assert.Equal(t, http.StatusNotFound, missing.Code)
+	mockService.AssertExpectations(t)
+}
@@ -1,10 +1,13 @@
 package handlers
 
 import (
+	"backend-store/internal/bulk"
 	"backend-store/internal/models"
 	"backend-store/internal/service"
+	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -26,7 +29,8 @@ func (h *OrderHandler) CreateOrder(c *gin.Context) {
 
 	if err := h.orderService.CreateOrder(c.Request.Context(), &order); err != nil {
 		switch {
-		case contains(err.Error(), "product") && contains(err.Error(), "not found"):
+		case contains(err.Error(), "product") && contains(err.Error(), "not found"),
+			contains(err.Error(), "customer") && contains(err.Error(), "not found"):
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		case contains(err.Error(), "insufficient quantity"):
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -117,9 +121,12 @@ func (h *OrderHandler) UpdateOrder(c *gin.Context) {
 	order.ID = id
 
 	if err := h.orderService.UpdateOrder(c.Request.Context(), &order); err != nil {
-		if contains(err.Error(), "not found") {
+		if contains(err.Error(), "product") && contains(err.Error(), "not found") ||
+			contains(err.Error(), "customer") && contains(err.Error(), "not found") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else if contains(err.Error(), "not found") {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
-		} else if contains(err.Error(), "validate") {
+		} else if contains(err.Error(), "validate") || contains(err.Error(), "insufficient quantity") {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		} else {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update order: " + err.Error()})
@@ -140,6 +147,8 @@ func (h *OrderHandler) DeleteOrder(c *gin.Context) {
 	if err := h.orderService.DeleteOrder(c.Request.Context(), id); err != nil {
 		if contains(err.Error(), "not found") {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
+		} else if contains(err.Error(), "cannot delete") {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
 		} else {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete order: " + err.Error()})
 		}
@@ -149,6 +158,48 @@ func (h *OrderHandler) DeleteOrder(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Order deleted successfully"})
 }
 
+func (h *OrderHandler) ExportOrders(c *gin.Context) {
+	format, err := bulk.ParseFormat(c.DefaultQuery("format", "csv"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	filter, err := models.ParseOrderFilter(c.Query("status"), c.Query("from"), c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	setExportHeaders(c, "orders", format)
+	if err := h.orderService.ExportOrders(c.Request.Context(), filter, format, c.Writer); err != nil {
+		abortExport(c, "Failed to export orders: "+err.Error())
+	}
+}
+
+// setExportHeaders начинает потоковую выгрузку. Большой файл передается
+// дольше WriteTimeout сервера, поэтому срок записи снимается, иначе
+// соединение оборвется посреди файла после статуса 200.
+func setExportHeaders(c *gin.Context, name string, format bulk.Format) {
+	http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	c.Header("Content-Type", format.ContentType())
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, name, format))
+	c.Status(http.StatusOK)
+}
+
+// abortExport сообщает об ошибке, если ответ еще не начал передаваться.
+// После начала потока статус изменить нельзя, и клиент получит обрезанный файл.
+func abortExport(c *gin.Context, message string) {
+	if c.Writer.Written() {
+		c.Error(fmt.Errorf("%s", message))
+		return
+	}
+	c.Writer.Header().Del("Content-Type")
+	c.Writer.Header().Del("Content-Disposition")
+	c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+}
+
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(s) > len(substr) && (s[:len(substr)] == substr || contains(s[1:], substr)))
 }
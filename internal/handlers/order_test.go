@@ -1,11 +1,13 @@
 package handlers
 
 import (
+	"backend-store/internal/bulk"
 	"backend-store/internal/models"
 	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -48,6 +50,11 @@ func (m *MockOrderService) DeleteOrder(ctx context.Context, id int) error {
 	return args.Error(0)
 }
 
+func (m *MockOrderService) ExportOrders(ctx context.Context, filter models.OrderFilter, format bulk.Format, w io.Writer) error {
+	args := m.Called(ctx, filter, format, w)
+	return args.Error(0)
+}
+
 func setupRouter() *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	return gin.New()
@@ -60,9 +67,9 @@ func TestOrderHandler_CreateOrder_Success(t *testing.T) {
 	router.POST("/orders", handler.CreateOrder)
 
 	orderRequest := map[string]interface{}{
-		"user_id": 1,
-		"total":   9999,
-		"status":  "pending",
+		"customer_id": 1,
+		"total":       9999,
+		"status":      "pending",
 		"products": []map[string]interface{}{
 			{
 				"product_id": 1,
@@ -125,9 +132,9 @@ func TestOrderHandler_CreateOrder_ProductNotFound(t *testing.T) {
 	router.POST("/orders", handler.CreateOrder)
 
 	orderRequest := map[string]interface{}{
-		"user_id": 1,
-		"total":   9999,
-		"status":  "pending",
+		"customer_id": 1,
+		"total":       9999,
+		"status":      "pending",
 		"products": []map[string]interface{}{
 			{
 				"product_id": 1,
@@ -327,9 +334,9 @@ func TestOrderHandler_UpdateOrder_Success(t *testing.T) {
 	router.PUT("/orders/:id", handler.UpdateOrder)
 
 	orderRequest := map[string]interface{}{
-		"user_id": 1,
-		"total":   14999,
-		"status":  "completed",
+		"customer_id": 1,
+		"total":       14999,
+		"status":      "completed",
 		"products": []map[string]interface{}{
 			{
 				"product_id": 1,
@@ -388,3 +395,90 @@ func TestContains(t *testing.T) {
 	assert.False(t, contains("some error", "product"))
 	assert.False(t, contains("", "product"))
 }
+
+func TestOrderHandler_ExportOrders_Success(t *testing.T) {
+	// Arrange
+	mockService := new(MockOrderService)
+	handler := NewOrderHandler(mockService)
+	router := setupRouter()
+	router.GET("/orders/export", handler.ExportOrders)
+
+	expectedFilter := models.OrderFilter{
+		Status: "completed",
+		From:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:     time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), // to включает весь день 31 января
+	}
+
+	mockService.On("ExportOrders", mock.Anything, expectedFilter, bulk.FormatNDJSON, mock.Anything).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			w := args.Get(3).(io.Writer)
+			w.Write([]byte(`{"order_id":1}` + "\n"))
+		})
+
+	// Act
+	req, _ := http.NewRequest("GET", "/orders/export?format=ndjson&status=completed&from=2024-01-01&to=2024-01-31", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "orders.ndjson")
+	assert.Equal(t, `{"order_id":1}`+"\n", w.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+func TestOrderHandler_ExportOrders_InvalidParams(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		error string
+	}{
+		{name: "unsupported format", query: "format=pdf", error: "unsupported format"},
+		{name: "invalid from", query: "from=yesterday", error: "invalid from"},
+		{name: "invalid to", query: "to=01.02.2024", error: "invalid to"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockService := new(MockOrderService)
+			handler := NewOrderHandler(mockService)
+			router := setupRouter()
+			router.GET("/orders/export", handler.ExportOrders)
+
+			// Act
+			req, _ := http.NewRequest("GET", "/orders/export?"+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Body.String(), tt.error)
+		})
+	}
+}
+
+func TestOrderHandler_ExportOrders_ServiceError(t *testing.T) {
+	// Arrange
+	mockService := new(MockOrderService)
+	handler := NewOrderHandler(mockService)
+	router := setupRouter()
+	router.GET("/orders/export", handler.ExportOrders)
+
+	mockService.On("ExportOrders", mock.Anything, models.OrderFilter{}, bulk.FormatCSV, mock.Anything).
+		Return(errors.New("database error"))
+
+	// Act
+	req, _ := http.NewRequest("GET", "/orders/export", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+	assert.Empty(t, w.Header().Get("Content-Disposition"))
+	assert.Contains(t, w.Body.String(), "Failed to export orders")
+	mockService.AssertExpectations(t)
+}
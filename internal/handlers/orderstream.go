@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"backend-store/internal/models"
+	"backend-store/internal/service"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+)
+
+// StreamTokenQuery - параметр с токеном потока для клиентов EventSource,
+// которые не могут передать заголовок Authorization.
+const StreamTokenQuery = "access_token"
+
+const defaultStreamHeartbeat = 15 * time.Second
+
+type OrderStreamHandler struct {
+	streamService service.OrderStreamService
+	heartbeat     time.Duration
+}
+
+// NewOrderStreamHandler создает обработчик потоков заказов. Пока событий
+// нет, клиенту каждые heartbeat отправляется комментарий, чтобы прокси не
+// закрывали соединение.
+func NewOrderStreamHandler(streamService service.OrderStreamService, heartbeat time.Duration) *OrderStreamHandler {
+	if heartbeat <= 0 {
+		heartbeat = defaultStreamHeartbeat
+	}
+	return &OrderStreamHandler{streamService: streamService, heartbeat: heartbeat}
+}
+
+// StreamOrders передает изменения всех доступных клиенту заказов.
+func (h *OrderStreamHandler) StreamOrders(c *gin.Context) {
+	filter, err := models.ParseOrderStreamFilter(c.Query("status"), c.Query("customer_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.stream(c, filter)
+}
+
+// StreamOrder передает изменения одного заказа, начиная с его текущего
+// состояния.
+func (h *OrderStreamHandler) StreamOrder(c *gin.Context) {
+	id, ok := parseID(c, "id", "Invalid order ID")
+	if !ok {
+		return
+	}
+	h.stream(c, models.OrderStreamFilter{OrderID: id})
+}
+
+func (h *OrderStreamHandler) stream(c *gin.Context, filter models.OrderStreamFilter) {
+	access, err := h.streamService.Authorize(streamToken(c))
+	if err != nil {
+		respondStreamError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	sub, err := h.streamService.Subscribe(ctx, access, filter, c.GetHeader("Last-Event-ID"))
+	if err != nil {
+		respondStreamError(c, err)
+		return
+	}
+	defer sub.Close()
+
+	// Поток живет дольше WriteTimeout сервера.
+	http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	c.Header("Content-Type", sse.ContentType)
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	if sub.Reset {
+		c.Render(-1, sse.Event{Event: "reset", Data: gin.H{"reason": "Last-Event-ID is no longer buffered, reload orders"}})
+	}
+	if sub.Snapshot != nil {
+		c.Render(-1, sse.Event{Event: "snapshot", Data: sub.Snapshot})
+	}
+	for _, event := range sub.Replay {
+		renderOrderEvent(c, event)
+	}
+	c.Writer.Flush()
+
+	ticker := time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-sub.Events:
+			if !ok {
+				return
+			}
+			renderOrderEvent(c, event)
+		case <-ticker.C:
+			c.Writer.WriteString(": heartbeat\n\n")
+		}
+		c.Writer.Flush()
+	}
+}
+
+func renderOrderEvent(c *gin.Context, event models.OrderStreamEvent) {
+	c.Render(-1, sse.Event{Id: event.EventID(), Event: event.Type, Data: event})
+}
+
+// streamToken берет токен из заголовка Authorization: Bearer или из
+// параметра StreamTokenQuery.
+func streamToken(c *gin.Context) string {
+	if token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer "); ok {
+		return strings.TrimSpace(token)
+	}
+	return c.Query(StreamTokenQuery)
+}
+
+func respondStreamError(c *gin.Context, err error) {
+	switch {
+	case contains(err.Error(), "unauthorized"):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case contains(err.Error(), "forbidden"):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case contains(err.Error(), "not found"):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to open order stream: " + err.Error()})
+	}
+}
@@ -0,0 +1,244 @@
+package handlers
+
+import (
+	"backend-store/internal/models"
+	"backend-store/internal/service"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockOrderStreamService реализует интерфейс service.OrderStreamService для тестов
+type MockOrderStreamService struct {
+	mock.Mock
+}
+
+func (m *MockOrderStreamService) Authorize(token string) (models.StreamAccess, error) {
+	args := m.Called(token)
+	return args.Get(0).(models.StreamAccess), args.Error(1)
+}
+
+func (m *MockOrderStreamService) Subscribe(ctx context.Context, access models.StreamAccess, filter models.OrderStreamFilter, lastEventID string) (*service.OrderSubscription, error) {
+	args := m.Called(ctx, access, filter, lastEventID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*service.OrderSubscription), args.Error(1)
+}
+
+func setupOrderStreamRouter(mockService *MockOrderStreamService, heartbeat time.Duration) *gin.Engine {
+	handler := NewOrderStreamHandler(mockService, heartbeat)
+	router := setupRouter()
+	router.GET("/order/stream", handler.StreamOrders)
+	router.GET("/order/:id/stream", handler.StreamOrder)
+	return router
+}
+
+// closedSubscription возвращает подписку, поток которой завершится после
+// events.
+func closedSubscription(events ...models.OrderStreamEvent) *service.OrderSubscription {
+	ch := make(chan models.OrderStreamEvent, len(events))
+	for _, e := range events {
+		ch <- e
+	}
+	close(ch)
+	return &service.OrderSubscription{Events: ch}
+}
+
+func TestOrderStreamHandler_StreamOrders_Events(t *testing.T) {
+	// Arrange
+	mockService := new(MockOrderStreamService)
+	router := setupOrderStreamRouter(mockService, time.Minute)
+
+	access := models.StreamAccess{}
+	filter := models.OrderStreamFilter{CustomerID: 2, Statuses: []string{"processing"}}
+	sub := closedSubscription(models.OrderStreamEvent{
+		ID: 11, Type: models.EventOrderStatusChanged, OrderID: 5, CustomerID: 2,
+		Status: models.OrderStatusProcessing, PreviousStatus: models.OrderStatusPending,
+	})
+	mockService.On("Authorize", "staff-token").Return(access, nil)
+	mockService.On("Subscribe", mock.Anything, access, filter, "").Return(sub, nil)
+
+	// Act
+	req, _ := http.NewRequest("GET", "/order/stream?status=processing&customer_id=2", nil)
+	req.Header.Set("Authorization", "Bearer staff-token")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/event-stream")
+	body := w.Body.String()
+	assert.Contains(t, body, "id:11\n")
+	assert.Contains(t, body, "event:order.status_changed\n")
+	assert.Contains(t, body, `"previous_status":"pending"`)
+	mockService.AssertExpectations(t)
+}
+
+func TestOrderStreamHandler_StreamOrders_ResumeWithReplay(t *testing.T) {
+	// Arrange
+	mockService := new(MockOrderStreamService)
+	router := setupOrderStreamRouter(mockService, time.Minute)
+
+	access := models.StreamAccess{CustomerID: 2}
+	sub := closedSubscription()
+	sub.Replay = []models.OrderStreamEvent{{ID: 12, Type: models.EventOrderCreated, OrderID: 6, CustomerID: 2}}
+	mockService.On("Authorize", "customer-token").Return(access, nil)
+	mockService.On("Subscribe", mock.Anything, access, models.OrderStreamFilter{}, "11").Return(sub, nil)
+
+	// Act
+	req, _ := http.NewRequest("GET", "/order/stream?access_token=customer-token", nil)
+	req.Header.Set("Last-Event-ID", "11")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "id:12\nevent:order.created\n")
+	mockService.AssertExpectations(t)
+}
+
+func TestOrderStreamHandler_StreamOrders_Reset(t *testing.T) {
+	// Arrange
+	mockService := new(MockOrderStreamService)
+	router := setupOrderStreamRouter(mockService, time.Minute)
+
+	sub := closedSubscription()
+	sub.Reset = true
+	mockService.On("Authorize", "").Return(models.StreamAccess{}, nil)
+	mockService.On("Subscribe", mock.Anything, models.StreamAccess{}, models.OrderStreamFilter{}, "3").Return(sub, nil)
+
+	// Act
+	req, _ := http.NewRequest("GET", "/order/stream", nil)
+	req.Header.Set("Last-Event-ID", "3")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "event:reset\n")
+	mockService.AssertExpectations(t)
+}
+
+func TestOrderStreamHandler_StreamOrder_Snapshot(t *testing.T) {
+	// Arrange
+	mockService := new(MockOrderStreamService)
+	router := setupOrderStreamRouter(mockService, time.Minute)
+
+	sub := closedSubscription()
+	sub.Snapshot = &models.Order{ID: 5, CustomerID: 2, Status: models.OrderStatusPending}
+	mockService.On("Authorize", "").Return(models.StreamAccess{}, nil)
+	mockService.On("Subscribe", mock.Anything, models.StreamAccess{}, models.OrderStreamFilter{OrderID: 5}, "").Return(sub, nil)
+
+	// Act
+	req, _ := http.NewRequest("GET", "/order/5/stream", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "event:snapshot\n")
+	assert.Contains(t, w.Body.String(), `"status":"pending"`)
+	mockService.AssertExpectations(t)
+}
+
+func TestOrderStreamHandler_Heartbeat(t *testing.T) {
+	// Arrange
+	mockService := new(MockOrderStreamService)
+	router := setupOrderStreamRouter(mockService, 10*time.Millisecond)
+
+	events := make(chan models.OrderStreamEvent)
+	mockService.On("Authorize", "").Return(models.StreamAccess{}, nil)
+	mockService.On("Subscribe", mock.Anything, mock.Anything, mock.Anything, "").Return(&service.OrderSubscription{Events: events}, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// Act
+	req, _ := http.NewRequestWithContext(ctx, "GET", "/order/stream", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), ": heartbeat\n\n")
+}
+
+func TestOrderStreamHandler_Unauthorized(t *testing.T) {
+	// Arrange
+	mockService := new(MockOrderStreamService)
+	router := setupOrderStreamRouter(mockService, time.Minute)
+
+	mockService.On("Authorize", "").Return(models.StreamAccess{}, errors.New("unauthorized: stream token is required"))
+
+	// Act
+	req, _ := http.NewRequest("GET", "/order/stream", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	mockService.AssertNotCalled(t, "Subscribe", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestOrderStreamHandler_ForbiddenCustomer(t *testing.T) {
+	// Arrange
+	mockService := new(MockOrderStreamService)
+	router := setupOrderStreamRouter(mockService, time.Minute)
+
+	access := models.StreamAccess{CustomerID: 2}
+	mockService.On("Authorize", "customer-token").Return(access, nil)
+	mockService.On("Subscribe", mock.Anything, access, models.OrderStreamFilter{CustomerID: 3}, "").
+		Return(nil, errors.New("forbidden: cannot stream orders of another customer"))
+
+	// Act
+	req, _ := http.NewRequest("GET", "/order/stream?customer_id=3", nil)
+	req.Header.Set("Authorization", "Bearer customer-token")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestOrderStreamHandler_StreamOrder_NotFound(t *testing.T) {
+	// Arrange
+	mockService := new(MockOrderStreamService)
+	router := setupOrderStreamRouter(mockService, time.Minute)
+
+	mockService.On("Authorize", "").Return(models.StreamAccess{CustomerID: 2}, nil)
+	mockService.On("Subscribe", mock.Anything, models.StreamAccess{CustomerID: 2}, models.OrderStreamFilter{OrderID: 9}, "").
+		Return(nil, errors.New("order 9 not found"))
+
+	// Act
+	req, _ := http.NewRequest("GET", "/order/9/stream", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestOrderStreamHandler_InvalidFilter(t *testing.T) {
+	// Arrange
+	mockService := new(MockOrderStreamService)
+	router := setupOrderStreamRouter(mockService, time.Minute)
+
+	// Act
+	req, _ := http.NewRequest("GET", "/order/stream?customer_id=abc", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "Authorize", mock.Anything)
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"backend-store/internal/models"
+	"backend-store/internal/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OutboxHandler struct {
+	outboxService service.OutboxService
+}
+
+func NewOutboxHandler(outboxService service.OutboxService) *OutboxHandler {
+	return &OutboxHandler{outboxService: outboxService}
+}
+
+func (h *OutboxHandler) GetOutboxEvents(c *gin.Context) {
+	filter := models.OutboxFilter{Status: c.Query("status"), Type: c.Query("type")}
+	if limit := c.Query("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+			return
+		}
+		filter.Limit = n
+	}
+
+	events, err := h.outboxService.GetOutboxEvents(c.Request.Context(), filter)
+	if err != nil {
+		respondOutboxError(c, err, "Failed to fetch outbox events: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"events": events})
+}
+
+func (h *OutboxHandler) GetOutboxEventByID(c *gin.Context) {
+	id, ok := parseID(c, "id", "Invalid outbox event ID")
+	if !ok {
+		return
+	}
+
+	event, err := h.outboxService.GetOutboxEventByID(c.Request.Context(), id)
+	if err != nil {
+		respondOutboxError(c, err, "Failed to fetch outbox event: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, event)
+}
+
+func (h *OutboxHandler) ReplayOutboxEvent(c *gin.Context) {
+	id, ok := parseID(c, "id", "Invalid outbox event ID")
+	if !ok {
+		return
+	}
+
+	event, err := h.outboxService.ReplayOutboxEvent(c.Request.Context(), id)
+	if err != nil {
+		respondOutboxError(c, err, "Failed to replay outbox event: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, event)
+}
+
+func respondOutboxError(c *gin.Context, err error, prefix string) {
+	switch {
+	case contains(err.Error(), "validate"):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case contains(err.Error(), "not found"):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case contains(err.Error(), "cannot"):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": prefix + err.Error()})
+	}
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"backend-store/internal/models"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockOutboxService реализует интерфейс service.OutboxService для тестов
+type MockOutboxService struct {
+	mock.Mock
+}
+
+func (m *MockOutboxService) GetOutboxEvents(ctx context.Context, filter models.OutboxFilter) ([]models.OutboxEvent, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.OutboxEvent), args.Error(1)
+}
+
+func (m *MockOutboxService) GetOutboxEventByID(ctx context.Context, id int) (*models.OutboxEvent, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.OutboxEvent), args.Error(1)
+}
+
+func (m *MockOutboxService) ReplayOutboxEvent(ctx context.Context, id int) (*models.OutboxEvent, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.OutboxEvent), args.Error(1)
+}
+
+func setupOutboxRouter(mockService *MockOutboxService) *gin.Engine {
+	handler := NewOutboxHandler(mockService)
+	router := setupRouter()
+	router.GET("/outbox", handler.GetOutboxEvents)
+	router.GET("/outbox/:id", handler.GetOutboxEventByID)
+	router.POST("/outbox/:id/replay", handler.ReplayOutboxEvent)
+	return router
+}
+
+func TestOutboxHandler_GetOutboxEvents_Filter(t *testing.T) {
+	// Arrange
+	mockService := new(MockOutboxService)
+	router := setupOutboxRouter(mockService)
+
+	filter := models.OutboxFilter{Status: models.OutboxDead, Type: models.EventOrderCreated, Limit: 20}
+	events := []models.OutboxEvent{{ID: 7, Type: models.EventOrderCreated, Status: models.OutboxDead, Attempts: 10}}
+	mockService.On("GetOutboxEvents", mock.Anything, filter).Return(events, nil)
+
+	// Act
+	req, _ := http.NewRequest("GET", "/outbox?status=dead&type=order.created&limit=20", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Events []models.OutboxEvent `json:"events"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Len(t, response.Events, 1)
+	assert.Equal(t, 10, response.Events[0].Attempts)
+	mockService.AssertExpectations(t)
+}
+
+func TestOutboxHandler_GetOutboxEvents_InvalidStatus(t *testing.T) {
+	// Arrange
+	mockService := new(MockOutboxService)
+	router := setupOutboxRouter(mockService)
+
+	mockService.On("GetOutboxEvents", mock.Anything, mock.Anything).Return(nil, errors.New(`validate: invalid status "lost"`))
+
+	// Act
+	req, _ := http.NewRequest("GET", "/outbox?status=lost", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestOutboxHandler_GetOutboxEventByID_NotFound(t *testing.T) {
+	// Arrange
+	mockService := new(MockOutboxService)
+	router := setupOutboxRouter(mockService)
+
+	mockService.On("GetOutboxEventByID", mock.Anything, 99).Return(nil, errors.New("outbox event not found"))
+
+	// Act
+	req, _ := http.NewRequest("GET", "/outbox/99", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestOutboxHandler_ReplayOutboxEvent_Success(t *testing.T) {
+	// Arrange
+	mockService := new(MockOutboxService)
+	router := setupOutboxRouter(mockService)
+
+	event := &models.OutboxEvent{ID: 7, Type: models.EventOrderCreated, Status: models.OutboxPending}
+	mockService.On("ReplayOutboxEvent", mock.Anything, 7).Return(event, nil)
+
+	// Act
+	req, _ := http.NewRequest("POST", "/outbox/7/replay", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.OutboxEvent
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, models.OutboxPending, response.Status)
+	mockService.AssertExpectations(t)
+}
+
+func TestOutboxHandler_ReplayOutboxEvent_AlreadyPending(t *testing.T) {
+	// Arrange
+	mockService := new(MockOutboxService)
+	router := setupOutboxRouter(mockService)
+
+	mockService.On("ReplayOutboxEvent", mock.Anything, 7).Return(nil, errors.New("cannot replay event 7: already pending"))
+
+	// Act
+	req, _ := http.NewRequest("POST", "/outbox/7/replay", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"backend-store/internal/models"
+	"backend-store/internal/payment"
+	"backend-store/internal/service"
+	"crypto/subtle"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PaymentHandler struct {
+	paymentService service.PaymentService
+	webhookSecret  string
+	fake           *payment.Fake
+}
+
+// NewPaymentHandler создает обработчик оплаты. С пустым webhookSecret
+// уведомления не принимаются; fake задается только для тестового шлюза.
+func NewPaymentHandler(paymentService service.PaymentService, webhookSecret string, fake *payment.Fake) *PaymentHandler {
+	return &PaymentHandler{paymentService: paymentService, webhookSecret: webhookSecret, fake: fake}
+}
+
+type payOrderRequest struct {
+	PaymentMethod string `json:"payment_method"`
+}
+
+type refundRequest struct {
+	Amount int `json:"amount"`
+}
+
+// PayOrder отвечает по итогу попытки: 201 - оплачено, 202 - нужно
+// подтверждение по action_url, 402 - отказ шлюза.
+func (h *PaymentHandler) PayOrder(c *gin.Context) {
+	id, ok := parseID(c, "id", "Invalid order ID")
+	if !ok {
+		return
+	}
+
+	var req payOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	p, err := h.paymentService.PayOrder(c.Request.Context(), id, req.PaymentMethod)
+	if err != nil {
+		respondPaymentError(c, err, "Failed to pay order: ")
+		return
+	}
+
+	switch p.Status {
+	case models.PaymentCaptured:
+		c.JSON(http.StatusCreated, p)
+	case models.PaymentRequiresAction:
+		c.JSON(http.StatusAccepted, p)
+	default:
+		c.JSON(http.StatusPaymentRequired, p)
+	}
+}
+
+func (h *PaymentHandler) GetOrderPayments(c *gin.Context) {
+	id, ok := parseID(c, "id", "Invalid order ID")
+	if !ok {
+		return
+	}
+
+	payments, err := h.paymentService.GetOrderPayments(c.Request.Context(), id)
+	if err != nil {
+		respondPaymentError(c, err, "Failed to fetch payments: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"payments": payments})
+}
+
+func (h *PaymentHandler) VoidPayment(c *gin.Context) {
+	id, ok := parseID(c, "id", "Invalid payment ID")
+	if !ok {
+		return
+	}
+
+	p, err := h.paymentService.VoidPayment(c.Request.Context(), id)
+	if err != nil {
+		respondPaymentError(c, err, "Failed to void payment: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, p)
+}
+
+// RefundPayment без тела возвращает всю оставшуюся сумму.
+func (h *PaymentHandler) RefundPayment(c *gin.Context) {
+	id, ok := parseID(c, "id", "Invalid payment ID")
+	if !ok {
+		return
+	}
+
+	var req refundRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+			return
+		}
+	}
+
+	p, err := h.paymentService.RefundPayment(c.Request.Context(), id, req.Amount)
+	if err != nil {
+		respondPaymentError(c, err, "Failed to refund payment: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, p)
+}
+
+// Webhook принимает уведомления провайдера об итоге подтверждения.
+func (h *PaymentHandler) Webhook(c *gin.Context) {
+	secret := c.GetHeader(payment.WebhookSecretHeader)
+	if h.webhookSecret == "" || subtle.ConstantTimeCompare([]byte(secret), []byte(h.webhookSecret)) != 1 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid webhook secret"})
+		return
+	}
+
+	var event payment.Event
+	if err := c.ShouldBindJSON(&event); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	p, err := h.paymentService.HandleEvent(c.Request.Context(), event)
+	if err != nil {
+		respondPaymentError(c, err, "Failed to handle payment event: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, p)
+}
+
+// ConfirmFake имитирует прохождение покупателем подтверждения у тестового
+// шлюза: ?approved=false завершает его отказом.
+func (h *PaymentHandler) ConfirmFake(c *gin.Context) {
+	if h.fake == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Fake payment gateway is not enabled"})
+		return
+	}
+
+	approved := true
+	if value := c.Query("approved"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid approved value"})
+			return
+		}
+		approved = parsed
+	}
+
+	if err := h.fake.Confirm(c.Request.Context(), c.Param("reference"), approved); err != nil {
+		respondPaymentError(c, err, "Failed to confirm payment: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Payment confirmation sent"})
+}
+
+func respondPaymentError(c *gin.Context, err error, prefix string) {
+	switch {
+	case contains(err.Error(), "validate"), contains(err.Error(), "invalid"):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case contains(err.Error(), "not found"):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case contains(err.Error(), "cannot"), contains(err.Error(), "already has an active payment"):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case contains(err.Error(), "failed:"):
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": prefix + err.Error()})
+	}
+}
@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"backend-store/internal/models"
+	"backend-store/internal/payment"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPaymentService реализует интерфейс service.PaymentService для тестов
+type MockPaymentService struct {
+	mock.Mock
+}
+
+func (m *MockPaymentService) PayOrder(ctx context.Context, orderID int, method string) (*models.Payment, error) {
+	args := m.Called(ctx, orderID, method)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Payment), args.Error(1)
+}
+
+func (m *MockPaymentService) GetOrderPayments(ctx context.Context, orderID int) ([]models.Payment, error) {
+	args := m.Called(ctx, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Payment), args.Error(1)
+}
+
+func (m *MockPaymentService) HandleEvent(ctx context.Context, event payment.Event) (*models.Payment, error) {
+	args := m.Called(ctx, event)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Payment), args.Error(1)
+}
+
+func (m *MockPaymentService) VoidPayment(ctx context.Context, id int) (*models.Payment, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Payment), args.Error(1)
+}
+
+func (m *MockPaymentService) RefundPayment(ctx context.Context, id, amount int) (*models.Payment, error) {
+	args := m.Called(ctx, id, amount)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Payment), args.Error(1)
+}
+
+func setupPaymentRouter(mockService *MockPaymentService) *gin.Engine {
+	handler := NewPaymentHandler(mockService, "s3cret", nil)
+	router := setupRouter()
+	router.POST("/orders/:id/payments", handler.PayOrder)
+	router.POST("/payments/webhook", handler.Webhook)
+	router.POST("/payments/:id/refund", handler.RefundPayment)
+	router.POST("/payments/fake/:reference/confirm", handler.ConfirmFake)
+	return router
+}
+
+func TestPaymentHandler_PayOrder_StatusCodes(t *testing.T) {
+	tests := []struct {
+		name   string
+		status string
+		want   int
+	}{
+		{"captured", models.PaymentCaptured, http.StatusCreated},
+		{"requires action", models.PaymentRequiresAction, http.StatusAccepted},
+		{"failed", models.PaymentFailed, http.StatusPaymentRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockService := new(MockPaymentService)
+			router := setupPaymentRouter(mockService)
+			mockService.On("PayOrder", mock.Anything, 1, "tok_visa").
+				Return(&models.Payment{ID: 1, OrderID: 1, Status: tt.status}, nil)
+
+			body, _ := json.Marshal(map[string]string{"payment_method": "tok_visa"})
+			req, _ := http.NewRequest(http.MethodPost, "/orders/1/payments", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.want, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPaymentHandler_PayOrder_ActivePaymentConflict(t *testing.T) {
+	// Arrange
+	mockService := new(MockPaymentService)
+	router := setupPaymentRouter(mockService)
+	mockService.On("PayOrder", mock.Anything, 1, "tok_visa").
+		Return(nil, errors.New("order 1 already has an active payment"))
+
+	body, _ := json.Marshal(map[string]string{"payment_method": "tok_visa"})
+	req, _ := http.NewRequest(http.MethodPost, "/orders/1/payments", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestPaymentHandler_Webhook_RejectsWrongSecret(t *testing.T) {
+	// Arrange
+	mockService := new(MockPaymentService)
+	router := setupPaymentRouter(mockService)
+
+	body, _ := json.Marshal(payment.Event{Reference: "fake_auth_1", Status: payment.EventAuthorized})
+	req, _ := http.NewRequest(http.MethodPost, "/payments/webhook", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(payment.WebhookSecretHeader, "wrong")
+	w := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	mockService.AssertNotCalled(t, "HandleEvent", mock.Anything, mock.Anything)
+}
+
+func TestPaymentHandler_Webhook_RejectsWithoutConfiguredSecret(t *testing.T) {
+	// Arrange
+	mockService := new(MockPaymentService)
+	handler := NewPaymentHandler(mockService, "", nil)
+	router := setupRouter()
+	router.POST("/payments/webhook", handler.Webhook)
+
+	body, _ := json.Marshal(payment.Event{Reference: "fake_auth_1", Status: payment.EventAuthorized})
+	req, _ := http.NewRequest(http.MethodPost, "/payments/webhook", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	mockService.AssertNotCalled(t, "HandleEvent", mock.Anything, mock.Anything)
+}
+
+func TestPaymentHandler_Webhook_Success(t *testing.T) {
+	// Arrange
+	mockService := new(MockPaymentService)
+	router := setupPaymentRouter(mockService)
+	event := payment.Event{Reference: "fake_auth_1", Status: payment.EventAuthorized}
+	mockService.On("HandleEvent", mock.Anything, event).
+		Return(&models.Payment{ID: 1, Status: models.PaymentCaptured}, nil)
+
+	body, _ := json.Marshal(event)
+	req, _ := http.NewRequest(http.MethodPost, "/payments/webhook", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(payment.WebhookSecretHeader, "s3cret")
+	w := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestPaymentHandler_RefundPayment_WithoutBody(t *testing.T) {
+	// Arrange
+	mockService := new(MockPaymentService)
+	router := setupPaymentRouter(mockService)
+	mockService.On("RefundPayment", mock.Anything, 3, 0).
+		Return(&models.Payment{ID: 3, Status: models.PaymentRefunded}, nil)
+
+	req, _ := http.NewRequest(http.MethodPost, "/payments/3/refund", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestPaymentHandler_ConfirmFake_Disabled(t *testing.T) {
+	// Arrange
+	mockService := new(MockPaymentService)
+	router := setupPaymentRouter(mockService)
+
+	req, _ := http.NewRequest(http.MethodPost, "/payments/fake/fake_auth_1/confirm", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"backend-store/internal/bulk"
 	"backend-store/internal/models"
 	"backend-store/internal/service"
 	"net/http"
@@ -49,9 +50,24 @@ func (h *ProductHandler) GetAllProducts(c *gin.Context) {
 		limit = 10
 	}
 
-	products, err := h.productService.GetAllProducts(c.Request.Context())
+	var filter models.ProductFilter
+	if category := c.Query("category"); category != "" {
+		id, err := strconv.Atoi(category)
+		if err != nil || id <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+			return
+		}
+		filter.CategoryID = id
+	}
+	filter.Tag = c.Query("tag")
+
+	products, err := h.productService.GetAllProducts(c.Request.Context(), filter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch products: " + err.Error()})
+		if contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch products: " + err.Error()})
+		}
 		return
 	}
 
@@ -79,6 +95,61 @@ func (h *ProductHandler) GetAllProducts(c *gin.Context) {
 	})
 }
 
+func (h *ProductHandler) SearchProducts(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	query := models.ProductSearch{
+		Query:  c.Query("q"),
+		Limit:  limit,
+		Offset: (page - 1) * limit,
+	}
+	if query.Query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Query parameter q is required"})
+		return
+	}
+	if category := c.Query("category"); category != "" {
+		id, err := strconv.Atoi(category)
+		if err != nil || id <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+			return
+		}
+		query.CategoryID = id
+	}
+	query.InStock, _ = strconv.ParseBool(c.DefaultQuery("in_stock", "false"))
+
+	result, err := h.productService.SearchProducts(c.Request.Context(), query)
+	if err != nil {
+		switch {
+		case contains(err.Error(), "not found"):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+		case contains(err.Error(), "search query"):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search products: " + err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"products": result.Hits,
+		"facets":   result.Facets,
+		"pagination": gin.H{
+			"page":  page,
+			"limit": limit,
+			"total": result.Total,
+			"pages": (result.Total + limit - 1) / limit,
+		},
+	})
+}
+
 func (h *ProductHandler) GetProductByID(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil || id <= 0 {
@@ -147,3 +218,229 @@ func (h *ProductHandler) DeleteProduct(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Product deleted successfully"})
 }
+
+func (h *ProductHandler) ImportProducts(c *gin.Context) {
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "File is required: " + err.Error()})
+		return
+	}
+	defer file.Close()
+
+	var format bulk.Format
+	if f := c.Query("format"); f != "" {
+		format, err = bulk.ParseFormat(f)
+	} else {
+		format, err = bulk.DetectFormat(header.Filename)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	dryRun, _ := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+	batchSize, _ := strconv.Atoi(c.DefaultQuery("batch_size", "0"))
+
+	reader, err := bulk.NewProductReader(file, format)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid import file: " + err.Error()})
+		return
+	}
+
+	report, err := h.productService.ImportProducts(c.Request.Context(), reader, service.ImportOptions{
+		DryRun:    dryRun,
+		BatchSize: batchSize,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to import products: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+func (h *ProductHandler) ExportProducts(c *gin.Context) {
+	format, err := bulk.ParseFormat(c.DefaultQuery("format", "csv"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	setExportHeaders(c, "products", format)
+	if err := h.productService.ExportProducts(c.Request.Context(), format, c.Writer); err != nil {
+		abortExport(c, "Failed to export products: "+err.Error())
+	}
+}
+
+func (h *ProductHandler) GetTags(c *gin.Context) {
+	tags, err := h.productService.GetTags(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tags: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tags": tags})
+}
+
+func (h *ProductHandler) GetLowStock(c *gin.Context) {
+	items, err := h.productService.GetLowStock(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch low stock products: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"products": items})
+}
+
+func (h *ProductHandler) GetVariants(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || productID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	variants, err := h.productService.GetVariants(c.Request.Context(), productID)
+	if err != nil {
+		if contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch variants: " + err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"variants": variants})
+}
+
+// GetStockMovements возвращает журнал движения товара в хронологическом
+// порядке с постраничной выдачей.
+func (h *ProductHandler) GetStockMovements(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || productID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 200 {
+		limit = 50
+	}
+
+	movements, err := h.productService.GetStockMovements(c.Request.Context(), productID)
+	if err != nil {
+		if contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch stock movements: " + err.Error()})
+		}
+		return
+	}
+
+	start := (page - 1) * limit
+	end := start + limit
+	paginated := []models.StockMovement{}
+	if start < len(movements) {
+		if end > len(movements) {
+			end = len(movements)
+		}
+		paginated = movements[start:end]
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"movements": paginated,
+		"pagination": gin.H{
+			"page":  page,
+			"limit": limit,
+			"total": len(movements),
+			"pages": (len(movements) + limit - 1) / limit,
+		},
+	})
+}
+
+func (h *ProductHandler) CreateVariant(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || productID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	var variant models.ProductVariant
+	if err := c.ShouldBindJSON(&variant); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	variant.ID = 0
+	variant.ProductID = productID
+
+	if err := h.productService.CreateVariant(c.Request.Context(), &variant); err != nil {
+		respondVariantError(c, err, "Failed to create variant: ")
+		return
+	}
+
+	c.JSON(http.StatusCreated, variant)
+}
+
+func (h *ProductHandler) UpdateVariant(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || productID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+	variantID, err := strconv.Atoi(c.Param("variantId"))
+	if err != nil || variantID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid variant ID"})
+		return
+	}
+
+	var variant models.ProductVariant
+	if err := c.ShouldBindJSON(&variant); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	variant.ID = variantID
+	variant.ProductID = productID
+
+	if err := h.productService.UpdateVariant(c.Request.Context(), &variant); err != nil {
+		respondVariantError(c, err, "Failed to update variant: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, variant)
+}
+
+func (h *ProductHandler) DeleteVariant(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || productID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+	variantID, err := strconv.Atoi(c.Param("variantId"))
+	if err != nil || variantID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid variant ID"})
+		return
+	}
+
+	if err := h.productService.DeleteVariant(c.Request.Context(), productID, variantID); err != nil {
+		respondVariantError(c, err, "Failed to delete variant: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Variant deleted successfully"})
+}
+
+func respondVariantError(c *gin.Context, err error, prefix string) {
+	switch {
+	case contains(err.Error(), "not found"):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case contains(err.Error(), "already exists"), contains(err.Error(), "cannot delete"):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case contains(err.Error(), "validate"), contains(err.Error(), "variant"):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": prefix + err.Error()})
+	}
+}
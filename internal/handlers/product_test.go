@@ -1,11 +1,15 @@
 package handlers
 
 import (
+	"backend-store/internal/bulk"
 	"backend-store/internal/models"
+	"backend-store/internal/service"
 	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -25,11 +29,19 @@ func (m *MockProductService) CreateProduct(ctx context.Context, product *models.
 	return args.Error(0)
 }
 
-func (m *MockProductService) GetAllProducts(ctx context.Context) ([]*models.Product, error) {
-	args := m.Called(ctx)
+func (m *MockProductService) GetAllProducts(ctx context.Context, filter models.ProductFilter) ([]*models.Product, error) {
+	args := m.Called(ctx, filter)
 	return args.Get(0).([]*models.Product), args.Error(1)
 }
 
+func (m *MockProductService) SearchProducts(ctx context.Context, query models.ProductSearch) (*models.SearchResult, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SearchResult), args.Error(1)
+}
+
 func (m *MockProductService) GetProductByID(ctx context.Context, id int) (*models.Product, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -48,6 +60,63 @@ func (m *MockProductService) DeleteProduct(ctx context.Context, id int) error {
 	return args.Error(0)
 }
 
+func (m *MockProductService) ImportProducts(ctx context.Context, reader bulk.ProductReader, opts service.ImportOptions) (*models.ImportReport, error) {
+	args := m.Called(ctx, reader, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ImportReport), args.Error(1)
+}
+
+func (m *MockProductService) ExportProducts(ctx context.Context, format bulk.Format, w io.Writer) error {
+	args := m.Called(ctx, format, w)
+	return args.Error(0)
+}
+
+func (m *MockProductService) GetVariants(ctx context.Context, productID int) ([]*models.ProductVariant, error) {
+	args := m.Called(ctx, productID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.ProductVariant), args.Error(1)
+}
+
+func (m *MockProductService) CreateVariant(ctx context.Context, variant *models.ProductVariant) error {
+	args := m.Called(ctx, variant)
+	return args.Error(0)
+}
+
+func (m *MockProductService) UpdateVariant(ctx context.Context, variant *models.ProductVariant) error {
+	args := m.Called(ctx, variant)
+	return args.Error(0)
+}
+
+func (m *MockProductService) DeleteVariant(ctx context.Context, productID, variantID int) error {
+	args := m.Called(ctx, productID, variantID)
+	return args.Error(0)
+}
+
+func (m *MockProductService) GetTags(ctx context.Context) ([]models.TagCount, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.TagCount), args.Error(1)
+}
+
+func (m *MockProductService) GetStockMovements(ctx context.Context, productID int) ([]models.StockMovement, error) {
+	args := m.Called(ctx, productID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.StockMovement), args.Error(1)
+}
+
+func (m *MockProductService) GetLowStock(ctx context.Context) ([]models.LowStockItem, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.LowStockItem), args.Error(1)
+}
+
 func TestProductHandler_CreateProduct_Success(t *testing.T) {
 	// Arrange
 	mockService := new(MockProductService)
@@ -229,7 +298,7 @@ func TestProductHandler_GetAllProducts_Success(t *testing.T) {
 		},
 	}
 
-	mockService.On("GetAllProducts", mock.Anything).Return(expectedProducts, nil)
+	mockService.On("GetAllProducts", mock.Anything, models.ProductFilter{}).Return(expectedProducts, nil)
 
 	// Act
 	req, _ := http.NewRequest("GET", "/products?page=1&limit=10", nil)
@@ -270,7 +339,7 @@ func TestProductHandler_GetAllProducts_Pagination(t *testing.T) {
 		}
 	}
 
-	mockService.On("GetAllProducts", mock.Anything).Return(products, nil)
+	mockService.On("GetAllProducts", mock.Anything, models.ProductFilter{}).Return(products, nil)
 
 	// Act
 	req, _ := http.NewRequest("GET", "/products?page=2&limit=5", nil)
@@ -301,7 +370,7 @@ func TestProductHandler_GetAllProducts_ServiceError(t *testing.T) {
 	router := setupRouter()
 	router.GET("/products", handler.GetAllProducts)
 
-	mockService.On("GetAllProducts", mock.Anything).Return(([]*models.Product)(nil), errors.New("database error"))
+	mockService.On("GetAllProducts", mock.Anything, models.ProductFilter{}).Return(([]*models.Product)(nil), errors.New("database error"))
 
 	// Act
 	req, _ := http.NewRequest("GET", "/products", nil)
@@ -314,6 +383,125 @@ func TestProductHandler_GetAllProducts_ServiceError(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestProductHandler_GetAllProducts_CategoryFilter(t *testing.T) {
+	// Arrange
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+	router := setupRouter()
+	router.GET("/products", handler.GetAllProducts)
+
+	products := []*models.Product{{ID: 3, Name: "Laptop", Price: 999, Quantity: 1}}
+	mockService.On("GetAllProducts", mock.Anything, models.ProductFilter{CategoryID: 2, Tag: "sale"}).Return(products, nil)
+
+	// Act
+	req, _ := http.NewRequest("GET", "/products?category=2&tag=sale", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "Laptop")
+	mockService.AssertExpectations(t)
+}
+
+func TestProductHandler_GetAllProducts_InvalidCategory(t *testing.T) {
+	// Arrange
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+	router := setupRouter()
+	router.GET("/products", handler.GetAllProducts)
+
+	// Act
+	req, _ := http.NewRequest("GET", "/products?category=abc", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "GetAllProducts", mock.Anything, mock.Anything)
+}
+
+func TestProductHandler_GetAllProducts_UnknownCategory(t *testing.T) {
+	// Arrange
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+	router := setupRouter()
+	router.GET("/products", handler.GetAllProducts)
+
+	mockService.On("GetAllProducts", mock.Anything, models.ProductFilter{CategoryID: 99}).
+		Return(([]*models.Product)(nil), errors.New("category not found"))
+
+	// Act
+	req, _ := http.NewRequest("GET", "/products?category=99", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestProductHandler_SearchProducts_Success(t *testing.T) {
+	// Arrange
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+	router := setupRouter()
+	router.GET("/products/search", handler.SearchProducts)
+
+	result := &models.SearchResult{
+		Hits:  []models.SearchHit{{Product: &models.Product{ID: 1, Name: "Gaming Laptop", Price: 999, Quantity: 3}, Score: 1.2}},
+		Total: 6,
+		Facets: models.SearchFacets{
+			Categories: []models.CategoryFacet{{ID: 2, Name: "Laptops", Count: 6}},
+			InStock:    5,
+			OutOfStock: 1,
+		},
+	}
+	expected := models.ProductSearch{Query: "laptop", CategoryID: 2, InStock: true, Limit: 5, Offset: 5}
+	mockService.On("SearchProducts", mock.Anything, expected).Return(result, nil)
+
+	// Act
+	req, _ := http.NewRequest("GET", "/products/search?q=laptop&category=2&in_stock=true&page=2&limit=5", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+
+	products := response["products"].([]interface{})
+	assert.Len(t, products, 1)
+	assert.Equal(t, "Gaming Laptop", products[0].(map[string]interface{})["name"])
+	assert.Equal(t, 1.2, products[0].(map[string]interface{})["score"])
+
+	pagination := response["pagination"].(map[string]interface{})
+	assert.Equal(t, float64(6), pagination["total"])
+	assert.Equal(t, float64(2), pagination["pages"])
+
+	facets := response["facets"].(map[string]interface{})
+	assert.Equal(t, float64(5), facets["in_stock"])
+	mockService.AssertExpectations(t)
+}
+
+func TestProductHandler_SearchProducts_MissingQuery(t *testing.T) {
+	// Arrange
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+	router := setupRouter()
+	router.GET("/products/search", handler.SearchProducts)
+
+	// Act
+	req, _ := http.NewRequest("GET", "/products/search", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "SearchProducts", mock.Anything, mock.Anything)
+}
+
 func TestProductHandler_GetProductByID_Success(t *testing.T) {
 	// Arrange
 	mockService := new(MockProductService)
@@ -545,3 +733,377 @@ func TestProductHandler_DeleteProduct_CannotDelete(t *testing.T) {
 	assert.Contains(t, w.Body.String(), "cannot delete")
 	mockService.AssertExpectations(t)
 }
+
+func newImportRequest(t *testing.T, url, filename, content string) *http.Request {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("file", filename)
+	assert.NoError(t, err)
+	part.Write([]byte(content))
+	writer.Close()
+
+	req, _ := http.NewRequest("POST", url, body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestProductHandler_ImportProducts_Success(t *testing.T) {
+	// Arrange
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+	router := setupRouter()
+	router.POST("/products/import", handler.ImportProducts)
+
+	report := &models.ImportReport{
+		DryRun:  true,
+		Total:   2,
+		Created: 1,
+		Failed:  1,
+		Rows: []models.ImportRowResult{
+			{Row: 2, SKU: "SKU-1", Status: models.ImportRowCreated},
+			{Row: 3, SKU: "SKU-2", Status: models.ImportRowFailed, Error: "product price must be positive"},
+		},
+	}
+
+	mockService.On("ImportProducts", mock.Anything, mock.Anything, service.ImportOptions{DryRun: true}).
+		Return(report, nil)
+
+	// Act
+	csv := "sku,name,price,quantity\nSKU-1,Product,10,5\nSKU-2,Product,0,5\n"
+	req := newImportRequest(t, "/products/import?dry_run=true", "products.csv", csv)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.ImportReport
+	json.Unmarshal(w.Body.Bytes(), &response)
+
+	assert.True(t, response.DryRun)
+	assert.Equal(t, 1, response.Created)
+	assert.Equal(t, 1, response.Failed)
+	assert.Len(t, response.Rows, 2)
+	assert.Equal(t, "product price must be positive", response.Rows[1].Error)
+	mockService.AssertExpectations(t)
+}
+
+func TestProductHandler_ImportProducts_MissingFile(t *testing.T) {
+	// Arrange
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+	router := setupRouter()
+	router.POST("/products/import", handler.ImportProducts)
+
+	// Act
+	req, _ := http.NewRequest("POST", "/products/import", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "File is required")
+}
+
+func TestProductHandler_ImportProducts_UnsupportedFormat(t *testing.T) {
+	// Arrange
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+	router := setupRouter()
+	router.POST("/products/import", handler.ImportProducts)
+
+	// Act
+	req := newImportRequest(t, "/products/import", "products.xml", "<products/>")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "unsupported format")
+}
+
+func TestProductHandler_ImportProducts_MissingColumn(t *testing.T) {
+	// Arrange
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+	router := setupRouter()
+	router.POST("/products/import", handler.ImportProducts)
+
+	// Act
+	req := newImportRequest(t, "/products/import", "products.csv", "name,price\nProduct,10\n")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "missing required column")
+	mockService.AssertNotCalled(t, "ImportProducts", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestProductHandler_ExportProducts_Success(t *testing.T) {
+	// Arrange
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+	router := setupRouter()
+	router.GET("/products/export", handler.ExportProducts)
+
+	mockService.On("ExportProducts", mock.Anything, bulk.FormatXLSX, mock.Anything).Return(nil)
+
+	// Act
+	req, _ := http.NewRequest("GET", "/products/export?format=xlsx", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, bulk.FormatXLSX.ContentType(), w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "products.xlsx")
+	mockService.AssertExpectations(t)
+}
+
+func TestProductHandler_GetVariants_Success(t *testing.T) {
+	// Arrange
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+	router := setupRouter()
+	router.GET("/products/:id/variants", handler.GetVariants)
+
+	variants := []*models.ProductVariant{
+		{ID: 1, ProductID: 1, SKU: "TS-S-RED", Options: models.VariantOptions{"size": "S", "color": "red"}, Price: 19.99, Quantity: 5},
+		{ID: 2, ProductID: 1, SKU: "TS-M-RED", Options: models.VariantOptions{"size": "M", "color": "red"}, Price: 21.99, Quantity: 3},
+	}
+	mockService.On("GetVariants", mock.Anything, 1).Return(variants, nil)
+
+	// Act
+	req, _ := http.NewRequest("GET", "/products/1/variants", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Variants []models.ProductVariant `json:"variants"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+
+	assert.Len(t, response.Variants, 2)
+	assert.Equal(t, "M", response.Variants[1].Options["size"])
+	mockService.AssertExpectations(t)
+}
+
+func TestProductHandler_CreateVariant_Success(t *testing.T) {
+	// Arrange
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+	router := setupRouter()
+	router.POST("/products/:id/variants", handler.CreateVariant)
+
+	variantRequest := map[string]interface{}{
+		"sku":      "TS-L-BLUE",
+		"options":  map[string]string{"size": "L", "color": "blue"},
+		"price":    23.5,
+		"quantity": 7,
+	}
+
+	mockService.On("CreateVariant", mock.Anything, mock.MatchedBy(func(v *models.ProductVariant) bool {
+		return v.ProductID == 3 && v.SKU == "TS-L-BLUE" && v.Options["color"] == "blue"
+	})).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			args.Get(1).(*models.ProductVariant).ID = 9
+		})
+
+	// Act
+	body, _ := json.Marshal(variantRequest)
+	req, _ := http.NewRequest("POST", "/products/3/variants", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response models.ProductVariant
+	json.Unmarshal(w.Body.Bytes(), &response)
+
+	assert.Equal(t, 9, response.ID)
+	assert.Equal(t, 3, response.ProductID)
+	mockService.AssertExpectations(t)
+}
+
+func TestProductHandler_CreateVariant_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		statusCode int
+	}{
+		{name: "Product not found", err: errors.New("product not found"), statusCode: http.StatusNotFound},
+		{name: "Duplicate options", err: errors.New(`variant with options "size=M" already exists`), statusCode: http.StatusConflict},
+		{name: "Invalid option value", err: errors.New(`validate: value "XL" is not allowed for option "size"`), statusCode: http.StatusBadRequest},
+		{name: "Storage error", err: errors.New("database error"), statusCode: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockService := new(MockProductService)
+			handler := NewProductHandler(mockService)
+			router := setupRouter()
+			router.POST("/products/:id/variants", handler.CreateVariant)
+
+			mockService.On("CreateVariant", mock.Anything, mock.AnythingOfType("*models.ProductVariant")).Return(tt.err)
+
+			// Act
+			body := bytes.NewBufferString(`{"sku":"X","price":10,"options":{"size":"M"}}`)
+			req, _ := http.NewRequest("POST", "/products/1/variants", body)
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.statusCode, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProductHandler_DeleteVariant_LastVariant(t *testing.T) {
+	// Arrange
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+	router := setupRouter()
+	router.DELETE("/products/:id/variants/:variantId", handler.DeleteVariant)
+
+	mockService.On("DeleteVariant", mock.Anything, 1, 2).Return(errors.New("cannot delete the last variant of a product"))
+
+	// Act
+	req, _ := http.NewRequest("DELETE", "/products/1/variants/2", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "last variant")
+	mockService.AssertExpectations(t)
+}
+
+func TestProductHandler_UpdateVariant_InvalidID(t *testing.T) {
+	// Arrange
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+	router := setupRouter()
+	router.PUT("/products/:id/variants/:variantId", handler.UpdateVariant)
+
+	// Act
+	req, _ := http.NewRequest("PUT", "/products/1/variants/abc", bytes.NewBufferString(`{}`))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "Invalid variant ID")
+}
+
+func TestProductHandler_GetStockMovements_Paginates(t *testing.T) {
+	// Arrange
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+	router := setupRouter()
+	router.GET("/products/:id/movements", handler.GetStockMovements)
+
+	movements := []models.StockMovement{
+		{ID: 1, WarehouseID: 1, VariantID: 3, ProductID: 1, Delta: 10, BalanceAfter: 10, Reason: models.MovementImport, Actor: "cli"},
+		{ID: 2, WarehouseID: 1, VariantID: 3, ProductID: 1, Delta: -2, BalanceAfter: 8, Reason: models.MovementOrder, Actor: "api", Reference: "order:5"},
+		{ID: 3, WarehouseID: 1, VariantID: 3, ProductID: 1, Delta: 2, BalanceAfter: 10, Reason: models.MovementCancellation, Actor: "api", Reference: "order:5"},
+	}
+	mockService.On("GetStockMovements", mock.Anything, 1).Return(movements, nil)
+
+	// Act
+	req, _ := http.NewRequest("GET", "/products/1/movements?page=2&limit=2", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Movements  []models.StockMovement `json:"movements"`
+		Pagination map[string]int         `json:"pagination"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Len(t, response.Movements, 1)
+	assert.Equal(t, models.MovementCancellation, response.Movements[0].Reason)
+	assert.Equal(t, 3, response.Pagination["total"])
+	assert.Equal(t, 2, response.Pagination["pages"])
+	mockService.AssertExpectations(t)
+}
+
+func TestProductHandler_GetStockMovements_NotFound(t *testing.T) {
+	// Arrange
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+	router := setupRouter()
+	router.GET("/products/:id/movements", handler.GetStockMovements)
+
+	mockService.On("GetStockMovements", mock.Anything, 9).
+		Return(nil, errors.New("product not found: product not found"))
+
+	// Act
+	req, _ := http.NewRequest("GET", "/products/9/movements", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestProductHandler_GetLowStock_Success(t *testing.T) {
+	// Arrange
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+	router := setupRouter()
+	router.GET("/products/low-stock", handler.GetLowStock)
+
+	alertedAt := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	items := []models.LowStockItem{
+		{ProductID: 1, SKU: "TSHIRT", Name: "T-Shirt", Quantity: 2, ReorderPoint: 5, ReorderQuantity: 20, AlertedAt: &alertedAt},
+		{ProductID: 4, Name: "Mug", Quantity: 0, ReorderPoint: 3, ReorderQuantity: 10},
+	}
+	mockService.On("GetLowStock", mock.Anything).Return(items, nil)
+
+	// Act
+	req, _ := http.NewRequest("GET", "/products/low-stock", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Products []models.LowStockItem `json:"products"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, items, response.Products)
+	mockService.AssertExpectations(t)
+}
+
+func TestProductHandler_GetLowStock_ServiceError(t *testing.T) {
+	// Arrange
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+	router := setupRouter()
+	router.GET("/products/low-stock", handler.GetLowStock)
+
+	mockService.On("GetLowStock", mock.Anything).Return(nil, errors.New("database error"))
+
+	// Act
+	req, _ := http.NewRequest("GET", "/products/low-stock", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	mockService.AssertExpectations(t)
+}
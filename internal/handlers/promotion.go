@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"backend-store/internal/models"
+	"backend-store/internal/service"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PromotionHandler struct {
+	promotionService service.PromotionService
+}
+
+func NewPromotionHandler(promotionService service.PromotionService) *PromotionHandler {
+	return &PromotionHandler{promotionService: promotionService}
+}
+
+// promotionRequest - тело создания и изменения акции. Без active акция
+// создается включенной.
+type promotionRequest struct {
+	Code             string     `json:"code"`
+	Name             string     `json:"name"`
+	Type             string     `json:"type"`
+	Value            int        `json:"value"`
+	BuyQuantity      int        `json:"buy_quantity"`
+	GetQuantity      int        `json:"get_quantity"`
+	ProductIDs       []int      `json:"product_ids"`
+	MinSubtotal      int        `json:"min_subtotal"`
+	UsageLimit       int        `json:"usage_limit"`
+	PerCustomerLimit int        `json:"per_customer_limit"`
+	StartsAt         *time.Time `json:"starts_at"`
+	EndsAt           *time.Time `json:"ends_at"`
+	Active           *bool      `json:"active"`
+}
+
+type previewPromotionRequest struct {
+	Code string `json:"code"`
+}
+
+func (r promotionRequest) promotion(id int) models.Promotion {
+	promotion := models.Promotion{
+		ID:               id,
+		Code:             r.Code,
+		Name:             r.Name,
+		Type:             r.Type,
+		Value:            r.Value,
+		BuyQuantity:      r.BuyQuantity,
+		GetQuantity:      r.GetQuantity,
+		ProductIDs:       models.IDList(r.ProductIDs),
+		MinSubtotal:      r.MinSubtotal,
+		UsageLimit:       r.UsageLimit,
+		PerCustomerLimit: r.PerCustomerLimit,
+		StartsAt:         r.StartsAt,
+		EndsAt:           r.EndsAt,
+		Active:           true,
+	}
+	if r.Active != nil {
+		promotion.Active = *r.Active
+	}
+	return promotion
+}
+
+func (h *PromotionHandler) CreatePromotion(c *gin.Context) {
+	var req promotionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	promotion := req.promotion(0)
+	if err := h.promotionService.CreatePromotion(c.Request.Context(), &promotion); err != nil {
+		respondPromotionError(c, err, "Failed to create promotion: ")
+		return
+	}
+
+	c.JSON(http.StatusCreated, promotion)
+}
+
+func (h *PromotionHandler) GetAllPromotions(c *gin.Context) {
+	promotions, err := h.promotionService.GetAllPromotions(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch promotions: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"promotions": promotions})
+}
+
+func (h *PromotionHandler) GetPromotionByID(c *gin.Context) {
+	id, ok := parseID(c, "id", "Invalid promotion ID")
+	if !ok {
+		return
+	}
+
+	promotion, err := h.promotionService.GetPromotion(c.Request.Context(), id)
+	if err != nil {
+		respondPromotionError(c, err, "Failed to fetch promotion: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, promotion)
+}
+
+func (h *PromotionHandler) UpdatePromotion(c *gin.Context) {
+	id, ok := parseID(c, "id", "Invalid promotion ID")
+	if !ok {
+		return
+	}
+
+	var req promotionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	promotion := req.promotion(id)
+	if err := h.promotionService.UpdatePromotion(c.Request.Context(), &promotion); err != nil {
+		respondPromotionError(c, err, "Failed to update promotion: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, promotion)
+}
+
+func (h *PromotionHandler) DeletePromotion(c *gin.Context) {
+	id, ok := parseID(c, "id", "Invalid promotion ID")
+	if !ok {
+		return
+	}
+
+	if err := h.promotionService.DeletePromotion(c.Request.Context(), id); err != nil {
+		respondPromotionError(c, err, "Failed to delete promotion: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Promotion deleted successfully"})
+}
+
+// PreviewCart рассчитывает скидки корзины с необязательным кодом. Корзина
+// не изменяется, использование акций не засчитывается.
+func (h *PromotionHandler) PreviewCart(c *gin.Context) {
+	id, ok := parseID(c, "id", "Invalid cart ID")
+	if !ok {
+		return
+	}
+
+	var req previewPromotionRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+			return
+		}
+	}
+
+	pricing, err := h.promotionService.PreviewCart(c.Request.Context(), id, c.GetHeader(CartTokenHeader), req.Code)
+	if err != nil {
+		respondCartError(c, err, "Failed to preview promotions: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, pricing)
+}
+
+func respondPromotionError(c *gin.Context, err error, prefix string) {
+	switch {
+	case contains(err.Error(), "validate"), contains(err.Error(), "invalid"):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case contains(err.Error(), "not found"):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Promotion not found"})
+	case contains(err.Error(), "already exists"), contains(err.Error(), "cannot delete"):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": prefix + err.Error()})
+	}
+}
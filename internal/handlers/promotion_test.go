@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"backend-store/internal/models"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPromotionService реализует интерфейс service.PromotionService для тестов
+type MockPromotionService struct {
+	mock.Mock
+}
+
+func (m *MockPromotionService) CreatePromotion(ctx context.Context, promotion *models.Promotion) error {
+	args := m.Called(ctx, promotion)
+	return args.Error(0)
+}
+
+func (m *MockPromotionService) GetAllPromotions(ctx context.Context) ([]*models.Promotion, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Promotion), args.Error(1)
+}
+
+func (m *MockPromotionService) GetPromotion(ctx context.Context, id int) (*models.Promotion, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Promotion), args.Error(1)
+}
+
+func (m *MockPromotionService) UpdatePromotion(ctx context.Context, promotion *models.Promotion) error {
+	args := m.Called(ctx, promotion)
+	return args.Error(0)
+}
+
+func (m *MockPromotionService) DeletePromotion(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockPromotionService) PreviewCart(ctx context.Context, cartID int, token, code string) (*models.Pricing, error) {
+	args := m.Called(ctx, cartID, token, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Pricing), args.Error(1)
+}
+
+func setupPromotionRouter(mockService *MockPromotionService) *gin.Engine {
+	handler := NewPromotionHandler(mockService)
+	router := setupRouter()
+	router.POST("/promotions", handler.CreatePromotion)
+	router.PUT("/promotions/:id", handler.UpdatePromotion)
+	router.DELETE("/promotions/:id", handler.DeletePromotion)
+	router.POST("/carts/:id/promotions/preview", handler.PreviewCart)
+	return router
+}
+
+func TestPromotionHandler_CreatePromotion_ActiveByDefault(t *testing.T) {
+	// Arrange
+	mockService := new(MockPromotionService)
+	router := setupPromotionRouter(mockService)
+
+	mockService.On("CreatePromotion", mock.Anything, mock.MatchedBy(func(p *models.Promotion) bool {
+		return p.Code == "save10" && p.Type == models.PromotionPercentage && p.Value == 10 && p.Active
+	})).Return(nil).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.Promotion).ID = 1
+	})
+
+	body := []byte(`{"code":"save10","name":"Ten off","type":"percentage","value":10}`)
+	req, _ := http.NewRequest(http.MethodPost, "/promotions", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var response models.Promotion
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, 1, response.ID)
+	mockService.AssertExpectations(t)
+}
+
+func TestPromotionHandler_CreatePromotion_DuplicateCode(t *testing.T) {
+	// Arrange
+	mockService := new(MockPromotionService)
+	router := setupPromotionRouter(mockService)
+	mockService.On("CreatePromotion", mock.Anything, mock.Anything).
+		Return(errors.New(`failed to create promotion: promotion with code "SAVE10" already exists`))
+
+	body := []byte(`{"code":"save10","name":"Ten off","type":"percentage","value":10}`)
+	req, _ := http.NewRequest(http.MethodPost, "/promotions", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestPromotionHandler_UpdatePromotion_Deactivate(t *testing.T) {
+	// Arrange
+	mockService := new(MockPromotionService)
+	router := setupPromotionRouter(mockService)
+	mockService.On("UpdatePromotion", mock.Anything, mock.MatchedBy(func(p *models.Promotion) bool {
+		return p.ID == 3 && !p.Active
+	})).Return(nil)
+
+	body := []byte(`{"name":"Ship","type":"free_shipping","active":false}`)
+	req, _ := http.NewRequest(http.MethodPut, "/promotions/3", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestPromotionHandler_DeletePromotion_Used(t *testing.T) {
+	// Arrange
+	mockService := new(MockPromotionService)
+	router := setupPromotionRouter(mockService)
+	mockService.On("DeletePromotion", mock.Anything, 3).
+		Return(errors.New("failed to delete promotion: cannot delete promotion with redemptions"))
+
+	req, _ := http.NewRequest(http.MethodDelete, "/promotions/3", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestPromotionHandler_PreviewCart_Success(t *testing.T) {
+	// Arrange
+	mockService := new(MockPromotionService)
+	router := setupPromotionRouter(mockService)
+	pricing := &models.Pricing{Subtotal: 200, Discount: 20, Total: 180, Adjustments: []models.OrderAdjustment{
+		{PromotionID: 1, Code: "SAVE10", Type: models.PromotionPercentage, Amount: 20},
+	}}
+	mockService.On("PreviewCart", mock.Anything, 5, "secret", "save10").Return(pricing, nil)
+
+	body := []byte(`{"code":"save10"}`)
+	req, _ := http.NewRequest(http.MethodPost, "/carts/5/promotions/preview", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(CartTokenHeader, "secret")
+	w := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response models.Pricing
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, 180, response.Total)
+	mockService.AssertExpectations(t)
+}
+
+func TestPromotionHandler_PreviewCart_WrongToken(t *testing.T) {
+	// Arrange
+	mockService := new(MockPromotionService)
+	router := setupPromotionRouter(mockService)
+	mockService.On("PreviewCart", mock.Anything, 5, "", "").Return(nil, errors.New("cart not found"))
+
+	req, _ := http.NewRequest(http.MethodPost, "/carts/5/promotions/preview", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockService.AssertExpectations(t)
+}
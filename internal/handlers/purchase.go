@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"backend-store/internal/models"
+	"backend-store/internal/service"
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PurchaseHandler struct {
+	purchaseService service.PurchaseService
+}
+
+func NewPurchaseHandler(purchaseService service.PurchaseService) *PurchaseHandler {
+	return &PurchaseHandler{purchaseService: purchaseService}
+}
+
+// receiveRequest - тело приемки товара. Без позиций принимается весь
+// ожидаемый товар.
+type receiveRequest struct {
+	Items []models.GoodsReceiptItem `json:"items"`
+}
+
+func (h *PurchaseHandler) CreateSupplier(c *gin.Context) {
+	var supplier models.Supplier
+	if err := c.ShouldBindJSON(&supplier); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	if err := h.purchaseService.CreateSupplier(c.Request.Context(), &supplier); err != nil {
+		respondPurchaseError(c, err, "Failed to create supplier: ")
+		return
+	}
+
+	c.JSON(http.StatusCreated, supplier)
+}
+
+func (h *PurchaseHandler) GetAllSuppliers(c *gin.Context) {
+	suppliers, err := h.purchaseService.GetAllSuppliers(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch suppliers: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"suppliers": suppliers})
+}
+
+func (h *PurchaseHandler) GetSupplierByID(c *gin.Context) {
+	id, ok := parseID(c, "id", "Invalid supplier ID")
+	if !ok {
+		return
+	}
+
+	supplier, err := h.purchaseService.GetSupplierByID(c.Request.Context(), id)
+	if err != nil {
+		respondPurchaseError(c, err, "Failed to fetch supplier: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, supplier)
+}
+
+func (h *PurchaseHandler) UpdateSupplier(c *gin.Context) {
+	id, ok := parseID(c, "id", "Invalid supplier ID")
+	if !ok {
+		return
+	}
+
+	var supplier models.Supplier
+	if err := c.ShouldBindJSON(&supplier); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	supplier.ID = id
+
+	if err := h.purchaseService.UpdateSupplier(c.Request.Context(), &supplier); err != nil {
+		respondPurchaseError(c, err, "Failed to update supplier: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, supplier)
+}
+
+func (h *PurchaseHandler) DeleteSupplier(c *gin.Context) {
+	id, ok := parseID(c, "id", "Invalid supplier ID")
+	if !ok {
+		return
+	}
+
+	if err := h.purchaseService.DeleteSupplier(c.Request.Context(), id); err != nil {
+		respondPurchaseError(c, err, "Failed to delete supplier: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Supplier deleted successfully"})
+}
+
+func (h *PurchaseHandler) CreatePurchaseOrder(c *gin.Context) {
+	var order models.PurchaseOrder
+	if err := c.ShouldBindJSON(&order); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	if err := h.purchaseService.CreatePurchaseOrder(c.Request.Context(), &order); err != nil {
+		respondPurchaseError(c, err, "Failed to create purchase order: ")
+		return
+	}
+
+	c.JSON(http.StatusCreated, order)
+}
+
+func (h *PurchaseHandler) GetAllPurchaseOrders(c *gin.Context) {
+	orders, err := h.purchaseService.GetAllPurchaseOrders(c.Request.Context(), c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch purchase orders: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"purchase_orders": orders})
+}
+
+func (h *PurchaseHandler) GetPurchaseOrderByID(c *gin.Context) {
+	id, ok := parseID(c, "id", "Invalid purchase order ID")
+	if !ok {
+		return
+	}
+
+	order, err := h.purchaseService.GetPurchaseOrderByID(c.Request.Context(), id)
+	if err != nil {
+		respondPurchaseError(c, err, "Failed to fetch purchase order: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+func (h *PurchaseHandler) UpdatePurchaseOrder(c *gin.Context) {
+	id, ok := parseID(c, "id", "Invalid purchase order ID")
+	if !ok {
+		return
+	}
+
+	var order models.PurchaseOrder
+	if err := c.ShouldBindJSON(&order); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	order.ID = id
+
+	if err := h.purchaseService.UpdatePurchaseOrder(c.Request.Context(), &order); err != nil {
+		respondPurchaseError(c, err, "Failed to update purchase order: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+func (h *PurchaseHandler) SendPurchaseOrder(c *gin.Context) {
+	h.transitionPurchaseOrder(c, h.purchaseService.SendPurchaseOrder, "Failed to send purchase order: ")
+}
+
+func (h *PurchaseHandler) CancelPurchaseOrder(c *gin.Context) {
+	h.transitionPurchaseOrder(c, h.purchaseService.CancelPurchaseOrder, "Failed to cancel purchase order: ")
+}
+
+func (h *PurchaseHandler) ReceivePurchaseOrder(c *gin.Context) {
+	id, ok := parseID(c, "id", "Invalid purchase order ID")
+	if !ok {
+		return
+	}
+
+	var req receiveRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+			return
+		}
+	}
+
+	order, err := h.purchaseService.ReceivePurchaseOrder(c.Request.Context(), id, req.Items)
+	if err != nil {
+		respondPurchaseError(c, err, "Failed to receive purchase order: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+func (h *PurchaseHandler) transitionPurchaseOrder(c *gin.Context, transition func(ctx context.Context, id int) (*models.PurchaseOrder, error), prefix string) {
+	id, ok := parseID(c, "id", "Invalid purchase order ID")
+	if !ok {
+		return
+	}
+
+	order, err := transition(c.Request.Context(), id)
+	if err != nil {
+		respondPurchaseError(c, err, prefix)
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+func respondPurchaseError(c *gin.Context, err error, prefix string) {
+	switch {
+	case contains(err.Error(), "validate"):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case contains(err.Error(), "not found"):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case contains(err.Error(), "cannot"), contains(err.Error(), "insufficient stock"):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": prefix + err.Error()})
+	}
+}
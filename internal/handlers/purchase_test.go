@@ -0,0 +1,255 @@
+package handlers
+
+import (
+	"backend-store/internal/models"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPurchaseService реализует интерфейс service.PurchaseService для тестов
+type MockPurchaseService struct {
+	mock.Mock
+}
+
+func (m *MockPurchaseService) purchaseOrder(args mock.Arguments) (*models.PurchaseOrder, error) {
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PurchaseOrder), args.Error(1)
+}
+
+func (m *MockPurchaseService) CreateSupplier(ctx context.Context, supplier *models.Supplier) error {
+	args := m.Called(ctx, supplier)
+	return args.Error(0)
+}
+
+func (m *MockPurchaseService) GetAllSuppliers(ctx context.Context) ([]*models.Supplier, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Supplier), args.Error(1)
+}
+
+func (m *MockPurchaseService) GetSupplierByID(ctx context.Context, id int) (*models.Supplier, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Supplier), args.Error(1)
+}
+
+func (m *MockPurchaseService) UpdateSupplier(ctx context.Context, supplier *models.Supplier) error {
+	args := m.Called(ctx, supplier)
+	return args.Error(0)
+}
+
+func (m *MockPurchaseService) DeleteSupplier(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockPurchaseService) CreatePurchaseOrder(ctx context.Context, order *models.PurchaseOrder) error {
+	args := m.Called(ctx, order)
+	return args.Error(0)
+}
+
+func (m *MockPurchaseService) GetAllPurchaseOrders(ctx context.Context, status string) ([]*models.PurchaseOrder, error) {
+	args := m.Called(ctx, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.PurchaseOrder), args.Error(1)
+}
+
+func (m *MockPurchaseService) GetPurchaseOrderByID(ctx context.Context, id int) (*models.PurchaseOrder, error) {
+	return m.purchaseOrder(m.Called(ctx, id))
+}
+
+func (m *MockPurchaseService) UpdatePurchaseOrder(ctx context.Context, order *models.PurchaseOrder) error {
+	args := m.Called(ctx, order)
+	return args.Error(0)
+}
+
+func (m *MockPurchaseService) SendPurchaseOrder(ctx context.Context, id int) (*models.PurchaseOrder, error) {
+	return m.purchaseOrder(m.Called(ctx, id))
+}
+
+func (m *MockPurchaseService) ReceivePurchaseOrder(ctx context.Context, id int, items []models.GoodsReceiptItem) (*models.PurchaseOrder, error) {
+	return m.purchaseOrder(m.Called(ctx, id, items))
+}
+
+func (m *MockPurchaseService) CancelPurchaseOrder(ctx context.Context, id int) (*models.PurchaseOrder, error) {
+	return m.purchaseOrder(m.Called(ctx, id))
+}
+
+func setupPurchaseRouter(mockService *MockPurchaseService) *gin.Engine {
+	handler := NewPurchaseHandler(mockService)
+	router := setupRouter()
+	router.POST("/suppliers", handler.CreateSupplier)
+	router.DELETE("/suppliers/:id", handler.DeleteSupplier)
+	router.POST("/purchase-orders", handler.CreatePurchaseOrder)
+	router.PUT("/purchase-orders/:id", handler.UpdatePurchaseOrder)
+	router.POST("/purchase-orders/:id/send", handler.SendPurchaseOrder)
+	router.POST("/purchase-orders/:id/receive", handler.ReceivePurchaseOrder)
+	return router
+}
+
+func TestPurchaseHandler_CreateSupplier_InvalidEmail(t *testing.T) {
+	// Arrange
+	mockService := new(MockPurchaseService)
+	router := setupPurchaseRouter(mockService)
+
+	mockService.On("CreateSupplier", mock.Anything, mock.AnythingOfType("*models.Supplier")).
+		Return(errors.New("validate: invalid supplier email"))
+
+	// Act
+	req, _ := http.NewRequest("POST", "/suppliers", bytes.NewBufferString(`{"name":"Acme","email":"nope"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestPurchaseHandler_DeleteSupplier_WithOrders(t *testing.T) {
+	// Arrange
+	mockService := new(MockPurchaseService)
+	router := setupPurchaseRouter(mockService)
+
+	mockService.On("DeleteSupplier", mock.Anything, 2).
+		Return(errors.New("failed to delete supplier: cannot delete supplier with purchase orders"))
+
+	// Act
+	req, _ := http.NewRequest("DELETE", "/suppliers/2", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestPurchaseHandler_CreatePurchaseOrder_Success(t *testing.T) {
+	// Arrange
+	mockService := new(MockPurchaseService)
+	router := setupPurchaseRouter(mockService)
+
+	mockService.On("CreatePurchaseOrder", mock.Anything, mock.MatchedBy(func(po *models.PurchaseOrder) bool {
+		return po.SupplierID == 1 && len(po.Items) == 1 &&
+			po.Items[0].VariantID == 5 && po.Items[0].Quantity == 10 && po.Items[0].UnitCost == 300
+	})).Return(nil).Run(func(args mock.Arguments) {
+		po := args.Get(1).(*models.PurchaseOrder)
+		po.ID = 4
+		po.Status = models.PurchaseOrderDraft
+		po.Total = 3000
+	})
+
+	body := []byte(`{"supplier_id":1,"items":[{"variant_id":5,"quantity":10,"unit_cost":300}]}`)
+
+	// Act
+	req, _ := http.NewRequest("POST", "/purchase-orders", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response models.PurchaseOrder
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, 4, response.ID)
+	assert.Equal(t, 3000, response.Total)
+	mockService.AssertExpectations(t)
+}
+
+func TestPurchaseHandler_UpdatePurchaseOrder_NotDraft(t *testing.T) {
+	// Arrange
+	mockService := new(MockPurchaseService)
+	router := setupPurchaseRouter(mockService)
+
+	mockService.On("UpdatePurchaseOrder", mock.Anything, mock.MatchedBy(func(po *models.PurchaseOrder) bool { return po.ID == 4 })).
+		Return(errors.New("cannot edit purchase order in status sent"))
+
+	body := []byte(`{"supplier_id":1,"items":[{"variant_id":5,"quantity":10,"unit_cost":300}]}`)
+
+	// Act
+	req, _ := http.NewRequest("PUT", "/purchase-orders/4", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestPurchaseHandler_SendPurchaseOrder_NotFound(t *testing.T) {
+	// Arrange
+	mockService := new(MockPurchaseService)
+	router := setupPurchaseRouter(mockService)
+
+	mockService.On("SendPurchaseOrder", mock.Anything, 9).Return(nil, errors.New("purchase order not found"))
+
+	// Act
+	req, _ := http.NewRequest("POST", "/purchase-orders/9/send", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestPurchaseHandler_ReceivePurchaseOrder_Partial(t *testing.T) {
+	// Arrange
+	mockService := new(MockPurchaseService)
+	router := setupPurchaseRouter(mockService)
+
+	items := []models.GoodsReceiptItem{{ItemID: 7, Quantity: 4}}
+	order := &models.PurchaseOrder{ID: 4, Status: models.PurchaseOrderPartiallyReceived}
+	mockService.On("ReceivePurchaseOrder", mock.Anything, 4, items).Return(order, nil)
+
+	// Act
+	req, _ := http.NewRequest("POST", "/purchase-orders/4/receive", bytes.NewBufferString(`{"items":[{"item_id":7,"quantity":4}]}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.PurchaseOrder
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, models.PurchaseOrderPartiallyReceived, response.Status)
+	mockService.AssertExpectations(t)
+}
+
+func TestPurchaseHandler_ReceivePurchaseOrder_OverReceipt(t *testing.T) {
+	// Arrange
+	mockService := new(MockPurchaseService)
+	router := setupPurchaseRouter(mockService)
+
+	mockService.On("ReceivePurchaseOrder", mock.Anything, 4, []models.GoodsReceiptItem(nil)).
+		Return(nil, errors.New("validate: cannot receive 12 of item 7: 10 outstanding"))
+
+	// Act
+	req, _ := http.NewRequest("POST", "/purchase-orders/4/receive", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"backend-store/internal/ratelimit"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimit ограничивает частоту запросов по политикам limiter. Ответы
+// на ограниченные маршруты содержат заголовки RateLimit-*, отклоненные
+// запросы получают 429 и Retry-After.
+func RateLimit(limiter *ratelimit.Limiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.FullPath()
+		if route == "" {
+			route = c.Request.URL.Path
+		}
+
+		result, policy, _ := limiter.Allow(c.Request.Context(), c.Request.Method, route, rateLimitKey(c))
+		if policy == nil || result.Limit == 0 {
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(ratelimit.Seconds(result.Reset)))
+		c.Header("RateLimit-Policy", strconv.Itoa(policy.Limit.Requests)+";w="+
+			strconv.Itoa(ratelimit.Seconds(policy.Limit.Period))+";burst="+strconv.Itoa(result.Limit))
+
+		if !result.Allowed {
+			c.Header("Retry-After", strconv.Itoa(ratelimit.Seconds(result.RetryAfter)))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "Rate limit exceeded, retry later"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// rateLimitKey считает лимиты по IP-адресу клиента. Заголовки X-API-Key
+// и ActorHeader не проверяются, и клиент, меняющий их в каждом запросе,
+// получал бы новое ведро.
+func rateLimitKey(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
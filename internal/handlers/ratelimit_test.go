@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"backend-store/internal/ratelimit"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupRateLimitRouter(policies []ratelimit.Policy) *gin.Engine {
+	router := setupRouter()
+	router.Use(RateLimit(ratelimit.NewLimiter(policies, ratelimit.NewMemoryStore())))
+	router.POST("/order/", func(c *gin.Context) { c.Status(http.StatusCreated) })
+	router.GET("/order/", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return router
+}
+
+func TestRateLimit_Throttles(t *testing.T) {
+	// Arrange
+	router := setupRateLimitRouter([]ratelimit.Policy{
+		{Method: "POST", Route: "/order/", Limit: ratelimit.Limit{Requests: 2, Period: time.Minute, Burst: 1}},
+	})
+	send := func() *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("POST", "/order/", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Act
+	first := send()
+	second := send()
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, first.Code)
+	assert.Equal(t, "1", first.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "0", first.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "30", first.Header().Get("RateLimit-Reset"))
+	assert.Equal(t, "2;w=60;burst=1", first.Header().Get("RateLimit-Policy"))
+	assert.Empty(t, first.Header().Get("Retry-After"))
+
+	assert.Equal(t, http.StatusTooManyRequests, second.Code)
+	assert.Equal(t, "30", second.Header().Get("Retry-After"))
+	assert.Contains(t, second.Body.String(), "Rate limit exceeded")
+}
+
+func TestRateLimit_Keys(t *testing.T) {
+	// Arrange
+	router := setupRateLimitRouter([]ratelimit.Policy{
+		{Method: "POST", Route: "/order/", Limit: ratelimit.Limit{Requests: 1, Period: time.Hour}},
+	})
+	send := func(ip, header, value string) int {
+		req, _ := http.NewRequest("POST", "/order/", nil)
+		req.RemoteAddr = ip + ":1234"
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Act & Assert
+	assert.Equal(t, http.StatusCreated, send("10.0.0.1", "", ""))
+	assert.Equal(t, http.StatusTooManyRequests, send("10.0.0.1", "", ""))
+	assert.Equal(t, http.StatusCreated, send("10.0.0.2", "", ""))
+}
+
+func TestRateLimit_RotatingHeadersKeepBucket(t *testing.T) {
+	// Arrange
+	router := setupRateLimitRouter([]ratelimit.Policy{
+		{Method: "POST", Route: "/order/", Limit: ratelimit.Limit{Requests: 1, Period: time.Hour}},
+	})
+	send := func(header, value string) int {
+		req, _ := http.NewRequest("POST", "/order/", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		req.Header.Set(header, value)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Act & Assert
+	assert.Equal(t, http.StatusCreated, send("X-API-Key", "key-1"))
+	assert.Equal(t, http.StatusTooManyRequests, send("X-API-Key", "key-2"))
+	assert.Equal(t, http.StatusTooManyRequests, send("X-API-Key", "key-3"))
+	assert.Equal(t, http.StatusTooManyRequests, send(ActorHeader, "alice"))
+	assert.Equal(t, http.StatusTooManyRequests, send(ActorHeader, "bob"))
+}
+
+func TestRateLimit_UnlimitedRoute(t *testing.T) {
+	// Arrange
+	router := setupRateLimitRouter([]ratelimit.Policy{
+		{Method: "POST", Route: "/order/", Limit: ratelimit.Limit{Requests: 1, Period: time.Hour}},
+	})
+
+	// Act
+	req, _ := http.NewRequest("GET", "/order/", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("RateLimit-Limit"))
+}
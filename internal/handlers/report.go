@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"backend-store/internal/bulk"
+	"backend-store/internal/models"
+	"backend-store/internal/service"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReportHandler struct {
+	reportService service.ReportService
+	maxAge        time.Duration
+}
+
+// NewReportHandler создает обработчик отчетов. maxAge - срок, на который
+// клиенту разрешено кэшировать ответ; обычно равен сроку кэша сервиса.
+func NewReportHandler(reportService service.ReportService, maxAge time.Duration) *ReportHandler {
+	return &ReportHandler{reportService: reportService, maxAge: maxAge}
+}
+
+func (h *ReportHandler) Sales(c *gin.Context) {
+	h.report(c, service.ReportSales, func(filter models.ReportFilter) (interface{}, error) {
+		points, err := h.reportService.Sales(c.Request.Context(), filter)
+		return gin.H{"sales": points}, err
+	})
+}
+
+func (h *ReportHandler) TopProducts(c *gin.Context) {
+	h.report(c, service.ReportTopProducts, func(filter models.ReportFilter) (interface{}, error) {
+		products, err := h.reportService.TopProducts(c.Request.Context(), filter)
+		return gin.H{"products": products}, err
+	})
+}
+
+func (h *ReportHandler) OrderValue(c *gin.Context) {
+	h.report(c, service.ReportOrderValue, func(filter models.ReportFilter) (interface{}, error) {
+		return h.reportService.OrderValue(c.Request.Context(), filter)
+	})
+}
+
+func (h *ReportHandler) OrderStatuses(c *gin.Context) {
+	h.report(c, service.ReportOrderStatuses, func(filter models.ReportFilter) (interface{}, error) {
+		counts, err := h.reportService.OrderStatuses(c.Request.Context(), filter)
+		return gin.H{"statuses": counts}, err
+	})
+}
+
+func (h *ReportHandler) StockValuation(c *gin.Context) {
+	h.report(c, service.ReportStockValuation, func(filter models.ReportFilter) (interface{}, error) {
+		valuation, err := h.reportService.StockValuation(c.Request.Context(), filter)
+		return gin.H{"stock": valuation}, err
+	})
+}
+
+// report разбирает общие параметры отчета и отдает его в JSON или, если
+// указан format, файлом выгрузки.
+func (h *ReportHandler) report(c *gin.Context, name string, build func(models.ReportFilter) (interface{}, error)) {
+	filter, err := models.ParseReportFilter(c.Query("from"), c.Query("to"), c.Query("tz"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	filter.Interval = c.Query("interval")
+	filter.SortBy = c.Query("by")
+	if limit := c.Query("limit"); limit != "" {
+		if filter.Limit, err = strconv.Atoi(limit); err != nil || filter.Limit <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+			return
+		}
+	}
+
+	h.setCacheHeaders(c)
+
+	if f := c.Query("format"); f != "" {
+		format, err := bulk.ParseFormat(f)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		// Отчет строится до начала ответа, чтобы ошибка проверки фильтра
+		// вернулась обычным JSON, а не обрезанным файлом.
+		if _, err := build(filter); err != nil {
+			respondReportError(c, err)
+			return
+		}
+		setExportHeaders(c, name, format)
+		if err := h.reportService.ExportReport(c.Request.Context(), name, filter, format, c.Writer); err != nil {
+			abortExport(c, "Failed to export report: "+err.Error())
+		}
+		return
+	}
+
+	result, err := build(filter)
+	if err != nil {
+		respondReportError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *ReportHandler) setCacheHeaders(c *gin.Context) {
+	if h.maxAge <= 0 {
+		c.Header("Cache-Control", "no-cache")
+		return
+	}
+	c.Header("Cache-Control", fmt.Sprintf("private, max-age=%d", int(h.maxAge.Seconds())))
+}
+
+func respondReportError(c *gin.Context, err error) {
+	if contains(err.Error(), "validate") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build report: " + err.Error()})
+}
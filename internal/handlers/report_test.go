@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"backend-store/internal/bulk"
+	"backend-store/internal/models"
+	"backend-store/internal/service"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockReportService реализует интерфейс service.ReportService для тестов
+type MockReportService struct {
+	mock.Mock
+}
+
+func (m *MockReportService) Sales(ctx context.Context, filter models.ReportFilter) ([]models.SalesPoint, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.SalesPoint), args.Error(1)
+}
+
+func (m *MockReportService) TopProducts(ctx context.Context, filter models.ReportFilter) ([]models.ProductSales, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ProductSales), args.Error(1)
+}
+
+func (m *MockReportService) OrderValue(ctx context.Context, filter models.ReportFilter) (*models.OrderValue, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.OrderValue), args.Error(1)
+}
+
+func (m *MockReportService) OrderStatuses(ctx context.Context, filter models.ReportFilter) ([]models.StatusCount, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.StatusCount), args.Error(1)
+}
+
+func (m *MockReportService) StockValuation(ctx context.Context, filter models.ReportFilter) ([]models.StockValuation, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.StockValuation), args.Error(1)
+}
+
+func (m *MockReportService) ExportReport(ctx context.Context, report string, filter models.ReportFilter, format bulk.Format, w io.Writer) error {
+	args := m.Called(ctx, report, filter, format, w)
+	return args.Error(0)
+}
+
+func setupReportRouter(mockService *MockReportService) *gin.Engine {
+	handler := NewReportHandler(mockService, 5*time.Minute)
+	router := setupRouter()
+	router.GET("/reports/sales", handler.Sales)
+	router.GET("/reports/top-products", handler.TopProducts)
+	router.GET("/reports/order-value", handler.OrderValue)
+	return router
+}
+
+func TestReportHandler_Sales_Success(t *testing.T) {
+	// Arrange
+	mockService := new(MockReportService)
+	router := setupReportRouter(mockService)
+
+	moscow, _ := time.LoadLocation("Europe/Moscow")
+	points := []models.SalesPoint{{Period: time.Date(2024, 3, 1, 0, 0, 0, 0, moscow), Orders: 3, Revenue: 4500}}
+	mockService.On("Sales", mock.Anything, mock.MatchedBy(func(f models.ReportFilter) bool {
+		return f.Location.String() == "Europe/Moscow" && f.Interval == "month" &&
+			f.From.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, moscow))
+	})).Return(points, nil)
+
+	// Act
+	req, _ := http.NewRequest("GET", "/reports/sales?from=2024-03-01&to=2024-03-31&tz=Europe/Moscow&interval=month", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "private, max-age=300", w.Header().Get("Cache-Control"))
+
+	var response struct {
+		Sales []models.SalesPoint `json:"sales"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Len(t, response.Sales, 1)
+	assert.Equal(t, 4500, response.Sales[0].Revenue)
+	mockService.AssertExpectations(t)
+}
+
+func TestReportHandler_Sales_InvalidTimeZone(t *testing.T) {
+	// Arrange
+	mockService := new(MockReportService)
+	router := setupReportRouter(mockService)
+
+	// Act
+	req, _ := http.NewRequest("GET", "/reports/sales?tz=Mars/Olympus", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "Sales", mock.Anything, mock.Anything)
+}
+
+func TestReportHandler_Sales_InvalidInterval(t *testing.T) {
+	// Arrange
+	mockService := new(MockReportService)
+	router := setupReportRouter(mockService)
+
+	mockService.On("Sales", mock.Anything, mock.Anything).
+		Return(nil, errors.New(`validate: invalid interval "year": expected day, week or month`))
+
+	// Act
+	req, _ := http.NewRequest("GET", "/reports/sales?interval=year", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestReportHandler_TopProducts_InvalidLimit(t *testing.T) {
+	// Arrange
+	mockService := new(MockReportService)
+	router := setupReportRouter(mockService)
+
+	// Act
+	req, _ := http.NewRequest("GET", "/reports/top-products?limit=abc", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "TopProducts", mock.Anything, mock.Anything)
+}
+
+func TestReportHandler_TopProducts_ExportCSV(t *testing.T) {
+	// Arrange
+	mockService := new(MockReportService)
+	router := setupReportRouter(mockService)
+
+	matchFilter := mock.MatchedBy(func(f models.ReportFilter) bool { return f.SortBy == "revenue" && f.Limit == 5 })
+	mockService.On("TopProducts", mock.Anything, matchFilter).Return([]models.ProductSales{}, nil)
+	mockService.On("ExportReport", mock.Anything, service.ReportTopProducts, matchFilter, bulk.FormatCSV, mock.Anything).
+		Return(nil).Run(func(args mock.Arguments) {
+		args.Get(4).(io.Writer).Write([]byte("product_id,name,units,revenue,cost,margin\n"))
+	})
+
+	// Act
+	req, _ := http.NewRequest("GET", "/reports/top-products?by=revenue&limit=5&format=csv", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `attachment; filename="top-products.csv"`, w.Header().Get("Content-Disposition"))
+	assert.Contains(t, w.Body.String(), "product_id,name,units")
+	mockService.AssertExpectations(t)
+}
+
+func TestReportHandler_OrderValue_ServiceError(t *testing.T) {
+	// Arrange
+	mockService := new(MockReportService)
+	router := setupReportRouter(mockService)
+
+	mockService.On("OrderValue", mock.Anything, mock.Anything).Return(nil, errors.New("failed to build order-value report: connection refused"))
+
+	// Act
+	req, _ := http.NewRequest("GET", "/reports/order-value", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	mockService.AssertExpectations(t)
+}
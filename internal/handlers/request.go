@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"backend-store/internal/service"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader - заголовок с ID запроса. Если клиент его не передал,
+// ID генерируется; в обоих случаях он возвращается в ответе.
+const RequestIDHeader = "X-Request-ID"
+
+const maxRequestIDLength = 128
+
+// RequestID переносит ID запроса и IP-адрес клиента в контекст для
+// журнала аудита.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if id == "" || len(id) > maxRequestIDLength {
+			id = newRequestID()
+		}
+		c.Header(RequestIDHeader, id)
+		c.Request = c.Request.WithContext(service.WithRequest(c.Request.Context(), id, c.ClientIP()))
+		c.Next()
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"backend-store/internal/models"
+	"backend-store/internal/service"
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReturnHandler struct {
+	returnService service.ReturnService
+}
+
+func NewReturnHandler(returnService service.ReturnService) *ReturnHandler {
+	return &ReturnHandler{returnService: returnService}
+}
+
+type createReturnRequest struct {
+	Note  string `json:"note"`
+	Items []struct {
+		OrderItemID int    `json:"order_item_id"`
+		Quantity    int    `json:"quantity"`
+		Reason      string `json:"reason"`
+	} `json:"items"`
+}
+
+type resolveReturnRequest struct {
+	Note string `json:"note"`
+}
+
+type receiveReturnRequest struct {
+	Items []struct {
+		ReturnItemID int    `json:"return_item_id"`
+		Disposition  string `json:"disposition"`
+	} `json:"items"`
+}
+
+type refundReturnRequest struct {
+	Amount int `json:"amount"`
+}
+
+func (h *ReturnHandler) CreateReturn(c *gin.Context) {
+	orderID, ok := parseID(c, "id", "Invalid order ID")
+	if !ok {
+		return
+	}
+
+	var req createReturnRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	ret := models.ReturnRequest{OrderID: orderID, Note: req.Note}
+	for _, item := range req.Items {
+		ret.Items = append(ret.Items, models.ReturnItem{
+			OrderItemID: item.OrderItemID,
+			Quantity:    item.Quantity,
+			Reason:      item.Reason,
+		})
+	}
+
+	if err := h.returnService.CreateReturn(c.Request.Context(), &ret); err != nil {
+		respondReturnError(c, err, "Failed to create return: ")
+		return
+	}
+
+	c.JSON(http.StatusCreated, ret)
+}
+
+func (h *ReturnHandler) GetOrderReturns(c *gin.Context) {
+	orderID, ok := parseID(c, "id", "Invalid order ID")
+	if !ok {
+		return
+	}
+
+	returns, err := h.returnService.GetOrderReturns(c.Request.Context(), orderID)
+	if err != nil {
+		respondReturnError(c, err, "Failed to fetch returns: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"returns": returns})
+}
+
+func (h *ReturnHandler) GetReturn(c *gin.Context) {
+	id, ok := parseID(c, "id", "Invalid return ID")
+	if !ok {
+		return
+	}
+
+	ret, err := h.returnService.GetReturn(c.Request.Context(), id)
+	if err != nil {
+		respondReturnError(c, err, "Failed to fetch return: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, ret)
+}
+
+func (h *ReturnHandler) ApproveReturn(c *gin.Context) {
+	h.resolve(c, h.returnService.ApproveReturn, "Failed to approve return: ")
+}
+
+func (h *ReturnHandler) RejectReturn(c *gin.Context) {
+	h.resolve(c, h.returnService.RejectReturn, "Failed to reject return: ")
+}
+
+func (h *ReturnHandler) resolve(c *gin.Context, action func(ctx context.Context, id int, note string) (*models.ReturnRequest, error), prefix string) {
+	id, ok := parseID(c, "id", "Invalid return ID")
+	if !ok {
+		return
+	}
+
+	var req resolveReturnRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+			return
+		}
+	}
+
+	ret, err := action(c.Request.Context(), id, req.Note)
+	if err != nil {
+		respondReturnError(c, err, prefix)
+		return
+	}
+
+	c.JSON(http.StatusOK, ret)
+}
+
+// ReceiveReturn без тела возвращает все позиции на склад.
+func (h *ReturnHandler) ReceiveReturn(c *gin.Context) {
+	id, ok := parseID(c, "id", "Invalid return ID")
+	if !ok {
+		return
+	}
+
+	var req receiveReturnRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+			return
+		}
+	}
+
+	dispositions := make(map[int]string, len(req.Items))
+	for _, item := range req.Items {
+		dispositions[item.ReturnItemID] = item.Disposition
+	}
+
+	ret, err := h.returnService.ReceiveReturn(c.Request.Context(), id, dispositions)
+	if err != nil {
+		respondReturnError(c, err, "Failed to receive return: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, ret)
+}
+
+// RefundReturn без тела возвращает полную стоимость позиций возврата.
+func (h *ReturnHandler) RefundReturn(c *gin.Context) {
+	id, ok := parseID(c, "id", "Invalid return ID")
+	if !ok {
+		return
+	}
+
+	var req refundReturnRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+			return
+		}
+	}
+
+	ret, err := h.returnService.RefundReturn(c.Request.Context(), id, req.Amount)
+	if err != nil {
+		respondReturnError(c, err, "Failed to refund return: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, ret)
+}
+
+func respondReturnError(c *gin.Context, err error, prefix string) {
+	switch {
+	case contains(err.Error(), "validate"), contains(err.Error(), "invalid"):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case contains(err.Error(), "not found"):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case contains(err.Error(), "cannot"):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case contains(err.Error(), "refund failed"):
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": prefix + err.Error()})
+	}
+}
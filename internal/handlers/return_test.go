@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"backend-store/internal/models"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockReturnService реализует интерфейс service.ReturnService для тестов
+type MockReturnService struct {
+	mock.Mock
+}
+
+func (m *MockReturnService) CreateReturn(ctx context.Context, ret *models.ReturnRequest) error {
+	args := m.Called(ctx, ret)
+	return args.Error(0)
+}
+
+func (m *MockReturnService) GetReturn(ctx context.Context, id int) (*models.ReturnRequest, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ReturnRequest), args.Error(1)
+}
+
+func (m *MockReturnService) GetOrderReturns(ctx context.Context, orderID int) ([]models.ReturnRequest, error) {
+	args := m.Called(ctx, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ReturnRequest), args.Error(1)
+}
+
+func (m *MockReturnService) ApproveReturn(ctx context.Context, id int, note string) (*models.ReturnRequest, error) {
+	args := m.Called(ctx, id, note)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ReturnRequest), args.Error(1)
+}
+
+func (m *MockReturnService) RejectReturn(ctx context.Context, id int, note string) (*models.ReturnRequest, error) {
+	args := m.Called(ctx, id, note)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ReturnRequest), args.Error(1)
+}
+
+func (m *MockReturnService) ReceiveReturn(ctx context.Context, id int, dispositions map[int]string) (*models.ReturnRequest, error) {
+	args := m.Called(ctx, id, dispositions)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ReturnRequest), args.Error(1)
+}
+
+func (m *MockReturnService) RefundReturn(ctx context.Context, id, amount int) (*models.ReturnRequest, error) {
+	args := m.Called(ctx, id, amount)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ReturnRequest), args.Error(1)
+}
+
+func setupReturnRouter(mockService *MockReturnService) *gin.Engine {
+	handler := NewReturnHandler(mockService)
+	router := setupRouter()
+	router.POST("/orders/:id/returns", handler.CreateReturn)
+	router.POST("/returns/:id/reject", handler.RejectReturn)
+	router.POST("/returns/:id/receive", handler.ReceiveReturn)
+	router.POST("/returns/:id/refund", handler.RefundReturn)
+	return router
+}
+
+func TestReturnHandler_CreateReturn_Success(t *testing.T) {
+	// Arrange
+	mockService := new(MockReturnService)
+	router := setupReturnRouter(mockService)
+
+	mockService.On("CreateReturn", mock.Anything, mock.MatchedBy(func(r *models.ReturnRequest) bool {
+		return r.OrderID == 4 && len(r.Items) == 1 && r.Items[0].OrderItemID == 9 &&
+			r.Items[0].Quantity == 2 && r.Items[0].Reason == models.ReturnReasonDefective
+	})).Return(nil).Run(func(args mock.Arguments) {
+		r := args.Get(1).(*models.ReturnRequest)
+		r.ID = 1
+		r.Status = models.ReturnRequested
+	})
+
+	body := []byte(`{"note":"broken","items":[{"order_item_id":9,"quantity":2,"reason":"defective"}]}`)
+	req, _ := http.NewRequest(http.MethodPost, "/orders/4/returns", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var response models.ReturnRequest
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, models.ReturnRequested, response.Status)
+	mockService.AssertExpectations(t)
+}
+
+func TestReturnHandler_CreateReturn_TooMany(t *testing.T) {
+	// Arrange
+	mockService := new(MockReturnService)
+	router := setupReturnRouter(mockService)
+	mockService.On("CreateReturn", mock.Anything, mock.Anything).
+		Return(errors.New("validate: only 1 of order item 9 can be returned"))
+
+	body := []byte(`{"items":[{"order_item_id":9,"quantity":5,"reason":"other"}]}`)
+	req, _ := http.NewRequest(http.MethodPost, "/orders/4/returns", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestReturnHandler_RejectReturn_AlreadyResolved(t *testing.T) {
+	// Arrange
+	mockService := new(MockReturnService)
+	router := setupReturnRouter(mockService)
+	mockService.On("RejectReturn", mock.Anything, 2, "late").
+		Return(nil, errors.New(`cannot resolve return in status "approved"`))
+
+	body := []byte(`{"note":"late"}`)
+	req, _ := http.NewRequest(http.MethodPost, "/returns/2/reject", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestReturnHandler_ReceiveReturn_Dispositions(t *testing.T) {
+	// Arrange
+	mockService := new(MockReturnService)
+	router := setupReturnRouter(mockService)
+	dispositions := map[int]string{5: models.DispositionWriteOff}
+	mockService.On("ReceiveReturn", mock.Anything, 2, dispositions).
+		Return(&models.ReturnRequest{ID: 2, Status: models.ReturnReceived}, nil)
+
+	body := []byte(`{"items":[{"return_item_id":5,"disposition":"write_off"}]}`)
+	req, _ := http.NewRequest(http.MethodPost, "/returns/2/receive", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestReturnHandler_RefundReturn_WithoutBody(t *testing.T) {
+	// Arrange
+	mockService := new(MockReturnService)
+	router := setupReturnRouter(mockService)
+	mockService.On("RefundReturn", mock.Anything, 2, 0).
+		Return(&models.ReturnRequest{ID: 2, Status: models.ReturnRefunded, RefundAmount: 300}, nil)
+
+	req, _ := http.NewRequest(http.MethodPost, "/returns/2/refund", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"backend-store/internal/models"
+	"backend-store/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ShipmentHandler struct {
+	shipmentService service.ShipmentService
+}
+
+func NewShipmentHandler(shipmentService service.ShipmentService) *ShipmentHandler {
+	return &ShipmentHandler{shipmentService: shipmentService}
+}
+
+type createShipmentRequest struct {
+	Status         string `json:"status"`
+	Carrier        string `json:"carrier"`
+	TrackingNumber string `json:"tracking_number"`
+	Items          []struct {
+		OrderItemID int `json:"order_item_id"`
+		Quantity    int `json:"quantity"`
+	} `json:"items"`
+}
+
+type updateShipmentRequest struct {
+	Status         string `json:"status"`
+	Carrier        string `json:"carrier"`
+	TrackingNumber string `json:"tracking_number"`
+}
+
+func (h *ShipmentHandler) CreateShipment(c *gin.Context) {
+	orderID, ok := parseID(c, "id", "Invalid order ID")
+	if !ok {
+		return
+	}
+
+	var req createShipmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	shipment := models.Shipment{
+		OrderID:        orderID,
+		Status:         req.Status,
+		Carrier:        req.Carrier,
+		TrackingNumber: req.TrackingNumber,
+	}
+	for _, item := range req.Items {
+		shipment.Items = append(shipment.Items, models.ShipmentItem{
+			OrderItemID: item.OrderItemID,
+			Quantity:    item.Quantity,
+		})
+	}
+
+	if err := h.shipmentService.CreateShipment(c.Request.Context(), &shipment); err != nil {
+		respondShipmentError(c, err, "Failed to create shipment: ")
+		return
+	}
+
+	c.JSON(http.StatusCreated, shipment)
+}
+
+func (h *ShipmentHandler) GetOrderShipments(c *gin.Context) {
+	orderID, ok := parseID(c, "id", "Invalid order ID")
+	if !ok {
+		return
+	}
+
+	shipments, err := h.shipmentService.GetOrderShipments(c.Request.Context(), orderID)
+	if err != nil {
+		respondShipmentError(c, err, "Failed to fetch shipments: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"shipments": shipments})
+}
+
+func (h *ShipmentHandler) GetShipment(c *gin.Context) {
+	id, ok := parseID(c, "id", "Invalid shipment ID")
+	if !ok {
+		return
+	}
+
+	shipment, err := h.shipmentService.GetShipment(c.Request.Context(), id)
+	if err != nil {
+		respondShipmentError(c, err, "Failed to fetch shipment: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, shipment)
+}
+
+// UpdateShipment меняет только переданные поля.
+func (h *ShipmentHandler) UpdateShipment(c *gin.Context) {
+	id, ok := parseID(c, "id", "Invalid shipment ID")
+	if !ok {
+		return
+	}
+
+	var req updateShipmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	shipment, err := h.shipmentService.UpdateShipment(c.Request.Context(), id, req.Status, req.Carrier, req.TrackingNumber)
+	if err != nil {
+		respondShipmentError(c, err, "Failed to update shipment: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, shipment)
+}
+
+func respondShipmentError(c *gin.Context, err error, prefix string) {
+	switch {
+	case contains(err.Error(), "validate"), contains(err.Error(), "invalid"):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case contains(err.Error(), "not found"):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case contains(err.Error(), "cannot"):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": prefix + err.Error()})
+	}
+}
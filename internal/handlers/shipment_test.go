@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"backend-store/internal/models"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockShipmentService реализует интерфейс service.ShipmentService для тестов
+type MockShipmentService struct {
+	mock.Mock
+}
+
+func (m *MockShipmentService) CreateShipment(ctx context.Context, shipment *models.Shipment) error {
+	args := m.Called(ctx, shipment)
+	return args.Error(0)
+}
+
+func (m *MockShipmentService) GetShipment(ctx context.Context, id int) (*models.Shipment, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Shipment), args.Error(1)
+}
+
+func (m *MockShipmentService) GetOrderShipments(ctx context.Context, orderID int) ([]models.Shipment, error) {
+	args := m.Called(ctx, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Shipment), args.Error(1)
+}
+
+func (m *MockShipmentService) UpdateShipment(ctx context.Context, id int, status, carrier, trackingNumber string) (*models.Shipment, error) {
+	args := m.Called(ctx, id, status, carrier, trackingNumber)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Shipment), args.Error(1)
+}
+
+func setupShipmentRouter(mockService *MockShipmentService) *gin.Engine {
+	handler := NewShipmentHandler(mockService)
+	router := setupRouter()
+	router.POST("/orders/:id/shipments", handler.CreateShipment)
+	router.GET("/orders/:id/shipments", handler.GetOrderShipments)
+	router.PUT("/shipments/:id", handler.UpdateShipment)
+	return router
+}
+
+func TestShipmentHandler_CreateShipment_Success(t *testing.T) {
+	// Arrange
+	mockService := new(MockShipmentService)
+	router := setupShipmentRouter(mockService)
+
+	mockService.On("CreateShipment", mock.Anything, mock.MatchedBy(func(s *models.Shipment) bool {
+		return s.OrderID == 3 && s.Carrier == "DHL" && len(s.Items) == 1 &&
+			s.Items[0].OrderItemID == 7 && s.Items[0].Quantity == 2
+	})).Return(nil).Run(func(args mock.Arguments) {
+		s := args.Get(1).(*models.Shipment)
+		s.ID = 1
+		s.Status = models.ShipmentLabelCreated
+	})
+
+	body := []byte(`{"carrier":"DHL","items":[{"order_item_id":7,"quantity":2}]}`)
+	req, _ := http.NewRequest(http.MethodPost, "/orders/3/shipments", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var response models.Shipment
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, 1, response.ID)
+	assert.Equal(t, models.ShipmentLabelCreated, response.Status)
+	mockService.AssertExpectations(t)
+}
+
+func TestShipmentHandler_CreateShipment_PendingOrder(t *testing.T) {
+	// Arrange
+	mockService := new(MockShipmentService)
+	router := setupShipmentRouter(mockService)
+	mockService.On("CreateShipment", mock.Anything, mock.Anything).
+		Return(errors.New(`cannot ship order in status "pending"`))
+
+	body := []byte(`{"items":[{"order_item_id":7,"quantity":1}]}`)
+	req, _ := http.NewRequest(http.MethodPost, "/orders/3/shipments", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestShipmentHandler_GetOrderShipments_OrderNotFound(t *testing.T) {
+	// Arrange
+	mockService := new(MockShipmentService)
+	router := setupShipmentRouter(mockService)
+	mockService.On("GetOrderShipments", mock.Anything, 9).Return(nil, errors.New("order not found"))
+
+	req, _ := http.NewRequest(http.MethodGet, "/orders/9/shipments", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestShipmentHandler_UpdateShipment_InvalidTransition(t *testing.T) {
+	// Arrange
+	mockService := new(MockShipmentService)
+	router := setupShipmentRouter(mockService)
+	mockService.On("UpdateShipment", mock.Anything, 1, "label_created", "", "").
+		Return(nil, errors.New(`validate: shipment cannot move from "shipped" to "label_created"`))
+
+	req, _ := http.NewRequest(http.MethodPut, "/shipments/1", bytes.NewBufferString(`{"status":"label_created"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"backend-store/internal/models"
+	"backend-store/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ShippingHandler struct {
+	shippingService service.ShippingService
+}
+
+func NewShippingHandler(shippingService service.ShippingService) *ShippingHandler {
+	return &ShippingHandler{shippingService: shippingService}
+}
+
+type quoteShippingRequest struct {
+	ShippingAddress *models.Address `json:"shipping_address"`
+	PromoCode       string          `json:"promo_code"`
+}
+
+// QuoteCart возвращает способы доставки корзины и их стоимость. Без адреса
+// используется адрес доставки покупателя по умолчанию.
+func (h *ShippingHandler) QuoteCart(c *gin.Context) {
+	id, ok := parseID(c, "id", "Invalid cart ID")
+	if !ok {
+		return
+	}
+
+	var req quoteShippingRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+			return
+		}
+	}
+
+	quotes, err := h.shippingService.QuoteCart(c.Request.Context(), id, c.GetHeader(CartTokenHeader), req.ShippingAddress, req.PromoCode)
+	if err != nil {
+		respondCartError(c, err, "Failed to quote shipping: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, quotes)
+}
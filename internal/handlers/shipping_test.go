@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"backend-store/internal/models"
+	"backend-store/internal/shipping"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockShippingService реализует интерфейс service.ShippingService для тестов
+type MockShippingService struct {
+	mock.Mock
+}
+
+func (m *MockShippingService) QuoteCart(ctx context.Context, cartID int, token string, address *models.Address, promoCode string) ([]shipping.Quote, error) {
+	args := m.Called(ctx, cartID, token, address, promoCode)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]shipping.Quote), args.Error(1)
+}
+
+func setupShippingRouter(mockService *MockShippingService) *gin.Engine {
+	handler := NewShippingHandler(mockService)
+	router := setupRouter()
+	router.POST("/carts/:id/shipping/quotes", handler.QuoteCart)
+	return router
+}
+
+func TestShippingHandler_QuoteCart_Success(t *testing.T) {
+	// Arrange
+	mockService := new(MockShippingService)
+	router := setupShippingRouter(mockService)
+	quotes := []shipping.Quote{
+		{Method: "standard", Name: "Standard", Cost: 500},
+		{Method: "express", Name: "Express", Cost: 1500},
+	}
+	mockService.On("QuoteCart", mock.Anything, 5, "secret", mock.MatchedBy(func(a *models.Address) bool {
+		return a != nil && a.Country == "DE"
+	}), "").Return(quotes, nil)
+
+	body := []byte(`{"shipping_address":{"country":"DE","city":"Berlin"}}`)
+	req, _ := http.NewRequest(http.MethodPost, "/carts/5/shipping/quotes", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(CartTokenHeader, "secret")
+	w := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response []shipping.Quote
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, quotes, response)
+	mockService.AssertExpectations(t)
+}
+
+func TestShippingHandler_QuoteCart_CartNotFound(t *testing.T) {
+	// Arrange
+	mockService := new(MockShippingService)
+	router := setupShippingRouter(mockService)
+	mockService.On("QuoteCart", mock.Anything, 5, "", (*models.Address)(nil), "").Return(nil, errors.New("cart not found"))
+
+	req, _ := http.NewRequest(http.MethodPost, "/carts/5/shipping/quotes", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockService.AssertExpectations(t)
+}
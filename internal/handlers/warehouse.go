@@ -0,0 +1,256 @@
+package handlers
+
+import (
+	"backend-store/internal/models"
+	"backend-store/internal/service"
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WarehouseHandler struct {
+	warehouseService service.WarehouseService
+}
+
+func NewWarehouseHandler(warehouseService service.WarehouseService) *WarehouseHandler {
+	return &WarehouseHandler{warehouseService: warehouseService}
+}
+
+// warehouseRequest - тело создания и изменения склада; без active склад
+// создается активным.
+type warehouseRequest struct {
+	Code     string         `json:"code"`
+	Name     string         `json:"name"`
+	Address  models.Address `json:"address"`
+	Priority int            `json:"priority"`
+	Active   *bool          `json:"active"`
+}
+
+func (r warehouseRequest) warehouse(id int) models.Warehouse {
+	warehouse := models.Warehouse{
+		ID:       id,
+		Code:     r.Code,
+		Name:     r.Name,
+		Address:  r.Address,
+		Priority: r.Priority,
+		Active:   true,
+	}
+	if r.Active != nil {
+		warehouse.Active = *r.Active
+	}
+	return warehouse
+}
+
+type stockLevelRequest struct {
+	Quantity *int `json:"quantity"`
+}
+
+func (h *WarehouseHandler) CreateWarehouse(c *gin.Context) {
+	var req warehouseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	warehouse := req.warehouse(0)
+	if err := h.warehouseService.CreateWarehouse(c.Request.Context(), &warehouse); err != nil {
+		respondWarehouseError(c, err, "Failed to create warehouse: ")
+		return
+	}
+
+	c.JSON(http.StatusCreated, warehouse)
+}
+
+func (h *WarehouseHandler) GetAllWarehouses(c *gin.Context) {
+	warehouses, err := h.warehouseService.GetAllWarehouses(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch warehouses: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"warehouses": warehouses})
+}
+
+func (h *WarehouseHandler) GetWarehouseByID(c *gin.Context) {
+	id, ok := parseID(c, "id", "Invalid warehouse ID")
+	if !ok {
+		return
+	}
+
+	warehouse, err := h.warehouseService.GetWarehouseByID(c.Request.Context(), id)
+	if err != nil {
+		respondWarehouseError(c, err, "Failed to fetch warehouse: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, warehouse)
+}
+
+func (h *WarehouseHandler) UpdateWarehouse(c *gin.Context) {
+	id, ok := parseID(c, "id", "Invalid warehouse ID")
+	if !ok {
+		return
+	}
+
+	var req warehouseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	warehouse := req.warehouse(id)
+	if err := h.warehouseService.UpdateWarehouse(c.Request.Context(), &warehouse); err != nil {
+		respondWarehouseError(c, err, "Failed to update warehouse: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, warehouse)
+}
+
+func (h *WarehouseHandler) DeleteWarehouse(c *gin.Context) {
+	id, ok := parseID(c, "id", "Invalid warehouse ID")
+	if !ok {
+		return
+	}
+
+	if err := h.warehouseService.DeleteWarehouse(c.Request.Context(), id); err != nil {
+		respondWarehouseError(c, err, "Failed to delete warehouse: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Warehouse deleted successfully"})
+}
+
+func (h *WarehouseHandler) GetWarehouseStock(c *gin.Context) {
+	id, ok := parseID(c, "id", "Invalid warehouse ID")
+	if !ok {
+		return
+	}
+
+	levels, err := h.warehouseService.GetWarehouseStock(c.Request.Context(), id)
+	if err != nil {
+		respondWarehouseError(c, err, "Failed to fetch stock: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"stock": levels})
+}
+
+func (h *WarehouseHandler) SetWarehouseStock(c *gin.Context) {
+	id, ok := parseID(c, "id", "Invalid warehouse ID")
+	if !ok {
+		return
+	}
+	variantID, ok := parseID(c, "variantId", "Invalid variant ID")
+	if !ok {
+		return
+	}
+
+	var req stockLevelRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Quantity == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: quantity is required"})
+		return
+	}
+
+	level, err := h.warehouseService.SetWarehouseStock(c.Request.Context(), id, variantID, *req.Quantity)
+	if err != nil {
+		respondWarehouseError(c, err, "Failed to set stock: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, level)
+}
+
+func (h *WarehouseHandler) CreateTransfer(c *gin.Context) {
+	var transfer models.StockTransfer
+	if err := c.ShouldBindJSON(&transfer); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	if err := h.warehouseService.CreateTransfer(c.Request.Context(), &transfer); err != nil {
+		respondWarehouseError(c, err, "Failed to create transfer: ")
+		return
+	}
+
+	c.JSON(http.StatusCreated, transfer)
+}
+
+func (h *WarehouseHandler) GetAllTransfers(c *gin.Context) {
+	transfers, err := h.warehouseService.GetAllTransfers(c.Request.Context(), c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch transfers: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"transfers": transfers})
+}
+
+func (h *WarehouseHandler) GetTransferByID(c *gin.Context) {
+	id, ok := parseID(c, "id", "Invalid transfer ID")
+	if !ok {
+		return
+	}
+
+	transfer, err := h.warehouseService.GetTransferByID(c.Request.Context(), id)
+	if err != nil {
+		respondWarehouseError(c, err, "Failed to fetch transfer: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, transfer)
+}
+
+func (h *WarehouseHandler) ShipTransfer(c *gin.Context) {
+	h.transitionTransfer(c, h.warehouseService.ShipTransfer, "Failed to ship transfer: ")
+}
+
+func (h *WarehouseHandler) ReceiveTransfer(c *gin.Context) {
+	h.transitionTransfer(c, h.warehouseService.ReceiveTransfer, "Failed to receive transfer: ")
+}
+
+func (h *WarehouseHandler) CancelTransfer(c *gin.Context) {
+	h.transitionTransfer(c, h.warehouseService.CancelTransfer, "Failed to cancel transfer: ")
+}
+
+func (h *WarehouseHandler) transitionTransfer(c *gin.Context, transition func(ctx context.Context, id int) (*models.StockTransfer, error), prefix string) {
+	id, ok := parseID(c, "id", "Invalid transfer ID")
+	if !ok {
+		return
+	}
+
+	transfer, err := transition(c.Request.Context(), id)
+	if err != nil {
+		respondWarehouseError(c, err, prefix)
+		return
+	}
+
+	c.JSON(http.StatusOK, transfer)
+}
+
+func parseID(c *gin.Context, param, message string) (int, bool) {
+	id, err := strconv.Atoi(c.Param(param))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": message})
+		return 0, false
+	}
+	return id, true
+}
+
+func respondWarehouseError(c *gin.Context, err error, prefix string) {
+	switch {
+	case contains(err.Error(), "validate"), contains(err.Error(), "warehouse code"),
+		contains(err.Error(), "warehouse name"), contains(err.Error(), "warehouse priority"),
+		contains(err.Error(), "address"):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case contains(err.Error(), "not found"):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case contains(err.Error(), "already exists"), contains(err.Error(), "cannot"),
+		contains(err.Error(), "insufficient stock"):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": prefix + err.Error()})
+	}
+}
@@ -0,0 +1,312 @@
+package handlers
+
+import (
+	"backend-store/internal/models"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockWarehouseService реализует интерфейс service.WarehouseService для тестов
+type MockWarehouseService struct {
+	mock.Mock
+}
+
+func (m *MockWarehouseService) CreateWarehouse(ctx context.Context, warehouse *models.Warehouse) error {
+	args := m.Called(ctx, warehouse)
+	return args.Error(0)
+}
+
+func (m *MockWarehouseService) GetAllWarehouses(ctx context.Context) ([]*models.Warehouse, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*models.Warehouse), args.Error(1)
+}
+
+func (m *MockWarehouseService) GetWarehouseByID(ctx context.Context, id int) (*models.Warehouse, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Warehouse), args.Error(1)
+}
+
+func (m *MockWarehouseService) UpdateWarehouse(ctx context.Context, warehouse *models.Warehouse) error {
+	args := m.Called(ctx, warehouse)
+	return args.Error(0)
+}
+
+func (m *MockWarehouseService) DeleteWarehouse(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockWarehouseService) GetWarehouseStock(ctx context.Context, id int) ([]models.StockLevel, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.StockLevel), args.Error(1)
+}
+
+func (m *MockWarehouseService) SetWarehouseStock(ctx context.Context, warehouseID, variantID, quantity int) (*models.StockLevel, error) {
+	args := m.Called(ctx, warehouseID, variantID, quantity)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StockLevel), args.Error(1)
+}
+
+func (m *MockWarehouseService) CreateTransfer(ctx context.Context, transfer *models.StockTransfer) error {
+	args := m.Called(ctx, transfer)
+	return args.Error(0)
+}
+
+func (m *MockWarehouseService) GetAllTransfers(ctx context.Context, status string) ([]*models.StockTransfer, error) {
+	args := m.Called(ctx, status)
+	return args.Get(0).([]*models.StockTransfer), args.Error(1)
+}
+
+func (m *MockWarehouseService) GetTransferByID(ctx context.Context, id int) (*models.StockTransfer, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StockTransfer), args.Error(1)
+}
+
+func (m *MockWarehouseService) ShipTransfer(ctx context.Context, id int) (*models.StockTransfer, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StockTransfer), args.Error(1)
+}
+
+func (m *MockWarehouseService) ReceiveTransfer(ctx context.Context, id int) (*models.StockTransfer, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StockTransfer), args.Error(1)
+}
+
+func (m *MockWarehouseService) CancelTransfer(ctx context.Context, id int) (*models.StockTransfer, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StockTransfer), args.Error(1)
+}
+
+func (m *MockWarehouseService) ReconcileStock(ctx context.Context) (*models.StockReconciliation, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StockReconciliation), args.Error(1)
+}
+
+func TestWarehouseHandler_CreateWarehouse_ActiveByDefault(t *testing.T) {
+	// Arrange
+	mockService := new(MockWarehouseService)
+	handler := NewWarehouseHandler(mockService)
+	router := setupRouter()
+	router.POST("/warehouses", handler.CreateWarehouse)
+
+	mockService.On("CreateWarehouse", mock.Anything, mock.MatchedBy(func(w *models.Warehouse) bool {
+		return w.Code == "SPB" && w.Active && w.Address.City == "Saint Petersburg"
+	})).Return(nil).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.Warehouse).ID = 2
+	})
+
+	// Act
+	body := `{"code":"SPB","name":"North","address":{"city":"Saint Petersburg","country":"RU"}}`
+	req, _ := http.NewRequest("POST", "/warehouses", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response models.Warehouse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, 2, response.ID)
+	assert.True(t, response.Active)
+	mockService.AssertExpectations(t)
+}
+
+func TestWarehouseHandler_CreateWarehouse_DuplicateCode(t *testing.T) {
+	// Arrange
+	mockService := new(MockWarehouseService)
+	handler := NewWarehouseHandler(mockService)
+	router := setupRouter()
+	router.POST("/warehouses", handler.CreateWarehouse)
+
+	mockService.On("CreateWarehouse", mock.Anything, mock.Anything).
+		Return(errors.New(`failed to create warehouse: warehouse with code "MAIN" already exists`))
+
+	// Act
+	req, _ := http.NewRequest("POST", "/warehouses", bytes.NewBufferString(`{"code":"MAIN","name":"Main"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestWarehouseHandler_DeleteWarehouse_WithStock(t *testing.T) {
+	// Arrange
+	mockService := new(MockWarehouseService)
+	handler := NewWarehouseHandler(mockService)
+	router := setupRouter()
+	router.DELETE("/warehouses/:id", handler.DeleteWarehouse)
+
+	mockService.On("DeleteWarehouse", mock.Anything, 2).
+		Return(errors.New("cannot delete warehouse with stock on hand: transfer it first"))
+
+	// Act
+	req, _ := http.NewRequest("DELETE", "/warehouses/2", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestWarehouseHandler_SetWarehouseStock(t *testing.T) {
+	// Arrange
+	mockService := new(MockWarehouseService)
+	handler := NewWarehouseHandler(mockService)
+	router := setupRouter()
+	router.PUT("/warehouses/:id/stock/:variantId", handler.SetWarehouseStock)
+
+	mockService.On("SetWarehouseStock", mock.Anything, 2, 7, 15).
+		Return(&models.StockLevel{WarehouseID: 2, VariantID: 7, ProductID: 3, Quantity: 15}, nil)
+
+	// Act
+	req, _ := http.NewRequest("PUT", "/warehouses/2/stock/7", bytes.NewBufferString(`{"quantity":15}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.StockLevel
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, 15, response.Quantity)
+	mockService.AssertExpectations(t)
+}
+
+func TestWarehouseHandler_SetWarehouseStock_MissingQuantity(t *testing.T) {
+	// Arrange
+	mockService := new(MockWarehouseService)
+	handler := NewWarehouseHandler(mockService)
+	router := setupRouter()
+	router.PUT("/warehouses/:id/stock/:variantId", handler.SetWarehouseStock)
+
+	// Act
+	req, _ := http.NewRequest("PUT", "/warehouses/2/stock/7", bytes.NewBufferString(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "SetWarehouseStock")
+}
+
+func TestWarehouseHandler_CreateTransfer_InvalidItems(t *testing.T) {
+	// Arrange
+	mockService := new(MockWarehouseService)
+	handler := NewWarehouseHandler(mockService)
+	router := setupRouter()
+	router.POST("/transfers", handler.CreateTransfer)
+
+	mockService.On("CreateTransfer", mock.Anything, mock.Anything).
+		Return(errors.New("validate: transfer must contain at least one item"))
+
+	// Act
+	req, _ := http.NewRequest("POST", "/transfers", bytes.NewBufferString(`{"from_warehouse_id":1,"to_warehouse_id":2}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestWarehouseHandler_ShipTransfer_InsufficientStock(t *testing.T) {
+	// Arrange
+	mockService := new(MockWarehouseService)
+	handler := NewWarehouseHandler(mockService)
+	router := setupRouter()
+	router.POST("/transfers/:id/ship", handler.ShipTransfer)
+
+	mockService.On("ShipTransfer", mock.Anything, 4).
+		Return(nil, errors.New("insufficient stock in warehouse 1 for variant 7"))
+
+	// Act
+	req, _ := http.NewRequest("POST", "/transfers/4/ship", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestWarehouseHandler_ReceiveTransfer(t *testing.T) {
+	// Arrange
+	mockService := new(MockWarehouseService)
+	handler := NewWarehouseHandler(mockService)
+	router := setupRouter()
+	router.POST("/transfers/:id/receive", handler.ReceiveTransfer)
+
+	mockService.On("ReceiveTransfer", mock.Anything, 4).
+		Return(&models.StockTransfer{ID: 4, Status: models.TransferReceived}, nil)
+
+	// Act
+	req, _ := http.NewRequest("POST", "/transfers/4/receive", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.StockTransfer
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, models.TransferReceived, response.Status)
+	mockService.AssertExpectations(t)
+}
+
+func TestWarehouseHandler_GetTransferByID_NotFound(t *testing.T) {
+	// Arrange
+	mockService := new(MockWarehouseService)
+	handler := NewWarehouseHandler(mockService)
+	router := setupRouter()
+	router.GET("/transfers/:id", handler.GetTransferByID)
+
+	mockService.On("GetTransferByID", mock.Anything, 9).Return(nil, errors.New("transfer not found"))
+
+	// Act
+	req, _ := http.NewRequest("GET", "/transfers/9", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"backend-store/internal/models"
+	"backend-store/internal/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookHandler struct {
+	webhookService service.WebhookService
+}
+
+func NewWebhookHandler(webhookService service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{webhookService: webhookService}
+}
+
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	var webhook models.Webhook
+	if err := c.ShouldBindJSON(&webhook); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	if err := h.webhookService.CreateWebhook(c.Request.Context(), &webhook); err != nil {
+		respondWebhookError(c, err, "Failed to create webhook: ")
+		return
+	}
+
+	c.JSON(http.StatusCreated, webhook)
+}
+
+func (h *WebhookHandler) GetWebhooks(c *gin.Context) {
+	webhooks, err := h.webhookService.GetWebhooks(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch webhooks: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"webhooks": webhooks})
+}
+
+func (h *WebhookHandler) GetWebhookByID(c *gin.Context) {
+	id, ok := parseID(c, "id", "Invalid webhook ID")
+	if !ok {
+		return
+	}
+
+	webhook, err := h.webhookService.GetWebhookByID(c.Request.Context(), id)
+	if err != nil {
+		respondWebhookError(c, err, "Failed to fetch webhook: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, webhook)
+}
+
+// UpdateWebhook заменяет вебхук целиком; без поля active вебхук включается.
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	id, ok := parseID(c, "id", "Invalid webhook ID")
+	if !ok {
+		return
+	}
+
+	webhook := models.Webhook{Active: true}
+	if err := c.ShouldBindJSON(&webhook); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	webhook.ID = id
+
+	if err := h.webhookService.UpdateWebhook(c.Request.Context(), &webhook); err != nil {
+		respondWebhookError(c, err, "Failed to update webhook: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, webhook)
+}
+
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	id, ok := parseID(c, "id", "Invalid webhook ID")
+	if !ok {
+		return
+	}
+
+	if err := h.webhookService.DeleteWebhook(c.Request.Context(), id); err != nil {
+		respondWebhookError(c, err, "Failed to delete webhook: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook deleted successfully"})
+}
+
+func (h *WebhookHandler) GetWebhookDeliveries(c *gin.Context) {
+	id, ok := parseID(c, "id", "Invalid webhook ID")
+	if !ok {
+		return
+	}
+
+	filter := models.WebhookDeliveryFilter{WebhookID: id, Status: c.Query("status")}
+	if limit := c.Query("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+			return
+		}
+		filter.Limit = n
+	}
+
+	deliveries, err := h.webhookService.GetWebhookDeliveries(c.Request.Context(), filter)
+	if err != nil {
+		respondWebhookError(c, err, "Failed to fetch webhook deliveries: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"deliveries": deliveries})
+}
+
+func (h *WebhookHandler) GetWebhookDeliveryByID(c *gin.Context) {
+	id, ok := parseID(c, "id", "Invalid webhook delivery ID")
+	if !ok {
+		return
+	}
+
+	delivery, err := h.webhookService.GetWebhookDeliveryByID(c.Request.Context(), id)
+	if err != nil {
+		respondWebhookError(c, err, "Failed to fetch webhook delivery: ")
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery)
+}
+
+func (h *WebhookHandler) RedeliverWebhook(c *gin.Context) {
+	id, ok := parseID(c, "id", "Invalid webhook delivery ID")
+	if !ok {
+		return
+	}
+
+	delivery, err := h.webhookService.RedeliverWebhook(c.Request.Context(), id)
+	if err != nil {
+		respondWebhookError(c, err, "Failed to redeliver webhook: ")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, delivery)
+}
+
+func respondWebhookError(c *gin.Context, err error, prefix string) {
+	switch {
+	case contains(err.Error(), "validate"):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case contains(err.Error(), "not found"):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case contains(err.Error(), "cannot"):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": prefix + err.Error()})
+	}
+}
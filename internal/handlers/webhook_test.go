@@ -0,0 +1,278 @@
+package handlers
+
+import (
+	"backend-store/internal/models"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockWebhookService реализует интерфейс service.WebhookService для тестов
+type MockWebhookService struct {
+	mock.Mock
+}
+
+func (m *MockWebhookService) CreateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	args := m.Called(ctx, webhook)
+	return args.Error(0)
+}
+
+func (m *MockWebhookService) GetWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookService) GetWebhookByID(ctx context.Context, id int) (*models.Webhook, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookService) UpdateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	args := m.Called(ctx, webhook)
+	return args.Error(0)
+}
+
+func (m *MockWebhookService) DeleteWebhook(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockWebhookService) GetWebhookDeliveries(ctx context.Context, filter models.WebhookDeliveryFilter) ([]models.WebhookDelivery, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookService) GetWebhookDeliveryByID(ctx context.Context, id int) (*models.WebhookDelivery, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookService) RedeliverWebhook(ctx context.Context, deliveryID int) (*models.WebhookDelivery, error) {
+	args := m.Called(ctx, deliveryID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WebhookDelivery), args.Error(1)
+}
+
+func setupWebhookRouter(mockService *MockWebhookService) *gin.Engine {
+	handler := NewWebhookHandler(mockService)
+	router := setupRouter()
+	router.POST("/webhooks", handler.CreateWebhook)
+	router.GET("/webhooks", handler.GetWebhooks)
+	router.GET("/webhooks/:id", handler.GetWebhookByID)
+	router.PUT("/webhooks/:id", handler.UpdateWebhook)
+	router.DELETE("/webhooks/:id", handler.DeleteWebhook)
+	router.GET("/webhooks/:id/deliveries", handler.GetWebhookDeliveries)
+	router.GET("/deliveries/:id", handler.GetWebhookDeliveryByID)
+	router.POST("/deliveries/:id/redeliver", handler.RedeliverWebhook)
+	return router
+}
+
+func TestWebhookHandler_CreateWebhook_Success(t *testing.T) {
+	// Arrange
+	mockService := new(MockWebhookService)
+	router := setupWebhookRouter(mockService)
+
+	mockService.On("CreateWebhook", mock.Anything, mock.MatchedBy(func(w *models.Webhook) bool {
+		return w.URL == "https://erp.example.com/hooks" && len(w.EventTypes) == 2
+	})).Run(func(args mock.Arguments) {
+		w := args.Get(1).(*models.Webhook)
+		w.ID = 1
+		w.Secret = "generated-secret-value"
+		w.Active = true
+	}).Return(nil)
+
+	body := `{"url":"https://erp.example.com/hooks","event_types":["order.created","order.status_changed"]}`
+
+	// Act
+	req, _ := http.NewRequest("POST", "/webhooks", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response models.Webhook
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, 1, response.ID)
+	assert.Equal(t, "generated-secret-value", response.Secret)
+	assert.True(t, response.Active)
+	mockService.AssertExpectations(t)
+}
+
+func TestWebhookHandler_CreateWebhook_ValidationError(t *testing.T) {
+	// Arrange
+	mockService := new(MockWebhookService)
+	router := setupWebhookRouter(mockService)
+
+	mockService.On("CreateWebhook", mock.Anything, mock.Anything).Return(errors.New(`validate: unknown event type "order.deleted"`))
+
+	// Act
+	req, _ := http.NewRequest("POST", "/webhooks", bytes.NewBufferString(`{"url":"https://example.com","event_types":["order.deleted"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestWebhookHandler_UpdateWebhook_DefaultsToActive(t *testing.T) {
+	// Arrange
+	mockService := new(MockWebhookService)
+	router := setupWebhookRouter(mockService)
+
+	mockService.On("UpdateWebhook", mock.Anything, mock.MatchedBy(func(w *models.Webhook) bool {
+		return w.ID == 3 && w.Active
+	})).Return(nil)
+
+	// Act
+	req, _ := http.NewRequest("PUT", "/webhooks/3", bytes.NewBufferString(`{"url":"https://example.com","event_types":["stock.depleted"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestWebhookHandler_UpdateWebhook_Disable(t *testing.T) {
+	// Arrange
+	mockService := new(MockWebhookService)
+	router := setupWebhookRouter(mockService)
+
+	mockService.On("UpdateWebhook", mock.Anything, mock.MatchedBy(func(w *models.Webhook) bool {
+		return w.ID == 3 && !w.Active
+	})).Return(nil)
+
+	// Act
+	req, _ := http.NewRequest("PUT", "/webhooks/3", bytes.NewBufferString(`{"url":"https://example.com","event_types":["stock.depleted"],"active":false}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestWebhookHandler_DeleteWebhook_NotFound(t *testing.T) {
+	// Arrange
+	mockService := new(MockWebhookService)
+	router := setupWebhookRouter(mockService)
+
+	mockService.On("DeleteWebhook", mock.Anything, 9).Return(errors.New("failed to delete webhook: webhook not found"))
+
+	// Act
+	req, _ := http.NewRequest("DELETE", "/webhooks/9", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestWebhookHandler_GetWebhookDeliveries(t *testing.T) {
+	// Arrange
+	mockService := new(MockWebhookService)
+	router := setupWebhookRouter(mockService)
+
+	filter := models.WebhookDeliveryFilter{WebhookID: 3, Status: models.WebhookDeliveryFailed, Limit: 10}
+	deliveries := []models.WebhookDelivery{{ID: 12, WebhookID: 3, Status: models.WebhookDeliveryFailed, ResponseCode: 500, Attempts: 8}}
+	mockService.On("GetWebhookDeliveries", mock.Anything, filter).Return(deliveries, nil)
+
+	// Act
+	req, _ := http.NewRequest("GET", "/webhooks/3/deliveries?status=failed&limit=10", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Deliveries []models.WebhookDelivery `json:"deliveries"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Len(t, response.Deliveries, 1)
+	assert.Equal(t, 500, response.Deliveries[0].ResponseCode)
+	mockService.AssertExpectations(t)
+}
+
+func TestWebhookHandler_GetWebhookDeliveries_InvalidLimit(t *testing.T) {
+	// Arrange
+	mockService := new(MockWebhookService)
+	router := setupWebhookRouter(mockService)
+
+	// Act
+	req, _ := http.NewRequest("GET", "/webhooks/3/deliveries?limit=abc", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "GetWebhookDeliveries", mock.Anything, mock.Anything)
+}
+
+func TestWebhookHandler_RedeliverWebhook_Success(t *testing.T) {
+	// Arrange
+	mockService := new(MockWebhookService)
+	router := setupWebhookRouter(mockService)
+
+	delivery := &models.WebhookDelivery{ID: 13, WebhookID: 3, Status: models.WebhookDeliveryPending, RedeliveryOf: 12}
+	mockService.On("RedeliverWebhook", mock.Anything, 12).Return(delivery, nil)
+
+	// Act
+	req, _ := http.NewRequest("POST", "/deliveries/12/redeliver", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusAccepted, w.Code)
+
+	var response models.WebhookDelivery
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, 12, response.RedeliveryOf)
+	mockService.AssertExpectations(t)
+}
+
+func TestWebhookHandler_RedeliverWebhook_Disabled(t *testing.T) {
+	// Arrange
+	mockService := new(MockWebhookService)
+	router := setupWebhookRouter(mockService)
+
+	mockService.On("RedeliverWebhook", mock.Anything, 12).Return(nil, errors.New("cannot redeliver to webhook 3: webhook is disabled"))
+
+	// Act
+	req, _ := http.NewRequest("POST", "/deliveries/12/redeliver", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockService.AssertExpectations(t)
+}
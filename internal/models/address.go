@@ -0,0 +1,89 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"math"
+	"strings"
+)
+
+// Address - почтовый адрес склада или доставки. Координаты необязательны;
+// без них расстояние оценивается по совпадению страны, региона и города.
+type Address struct {
+	Line1      string   `json:"line1,omitempty"`
+	Line2      string   `json:"line2,omitempty"`
+	City       string   `json:"city,omitempty"`
+	Region     string   `json:"region,omitempty"`
+	PostalCode string   `json:"postal_code,omitempty"`
+	Country    string   `json:"country,omitempty"`
+	Latitude   *float64 `json:"latitude,omitempty"`
+	Longitude  *float64 `json:"longitude,omitempty"`
+}
+
+func (a *Address) Validate() error {
+	if len(a.Country) > 2 {
+		return errors.New("address country must be an ISO 3166-1 alpha-2 code")
+	}
+	if (a.Latitude == nil) != (a.Longitude == nil) {
+		return errors.New("address latitude and longitude must be set together")
+	}
+	if a.Latitude != nil && (math.Abs(*a.Latitude) > 90 || math.Abs(*a.Longitude) > 180) {
+		return errors.New("address coordinates are out of range")
+	}
+	return nil
+}
+
+func (a *Address) HasCoordinates() bool {
+	return a.Latitude != nil && a.Longitude != nil
+}
+
+// Оценки расстояния в километрах для адресов без координат.
+const (
+	distanceSameCity    = 50
+	distanceSameRegion  = 300
+	distanceSameCountry = 1500
+	distanceUnknown     = 20000
+)
+
+// DistanceTo возвращает расстояние в километрах: по формуле гаверсинусов,
+// если у обоих адресов есть координаты, иначе грубую оценку по совпадению
+// страны, региона и города.
+func (a *Address) DistanceTo(b *Address) float64 {
+	if a == nil || b == nil {
+		return distanceUnknown
+	}
+	if a.HasCoordinates() && b.HasCoordinates() {
+		return haversine(*a.Latitude, *a.Longitude, *b.Latitude, *b.Longitude)
+	}
+	if a.Country == "" || !strings.EqualFold(a.Country, b.Country) {
+		return distanceUnknown
+	}
+	if a.Region == "" || !strings.EqualFold(a.Region, b.Region) {
+		return distanceSameCountry
+	}
+	if a.City == "" || !strings.EqualFold(a.City, b.City) {
+		return distanceSameRegion
+	}
+	return distanceSameCity
+}
+
+func haversine(lat1, lon1, lat2, lon2 float64) float64 {
+	const earthRadiusKm = 6371
+	toRad := func(d float64) float64 { return d * math.Pi / 180 }
+	dLat := toRad(lat2 - lat1)
+	dLon := toRad(lon2 - lon1)
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(h))
+}
+
+// Address хранится в PostgreSQL как JSONB.
+func (a Address) Value() (driver.Value, error) {
+	data, err := json.Marshal(a)
+	return string(data), err
+}
+
+func (a *Address) Scan(src interface{}) error {
+	return scanJSON(src, a)
+}
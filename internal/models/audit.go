@@ -0,0 +1,140 @@
+package models
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Действия, записываемые в журнал аудита.
+const (
+	AuditCreate = "create"
+	AuditUpdate = "update"
+	AuditDelete = "delete"
+)
+
+// AuditEntityVariant - сущность журнала для вариантов товара; товары и
+// заказы записываются как AggregateProduct и AggregateOrder.
+const AuditEntityVariant = "product_variant"
+
+// AuditEntry - запись журнала аудита. Журнал только дополняется, записи
+// удаляются лишь по сроку хранения. Changes содержит поля, значения которых
+// отличаются до и после изменения.
+type AuditEntry struct {
+	ID    int    `json:"id" db:"id"`
+	Actor string `json:"actor" db:"actor"`
+	// ActorVerified ложно, если Actor назвал клиент (X-Actor) без проверки.
+	ActorVerified bool         `json:"actor_verified" db:"actor_verified"`
+	Action        string       `json:"action" db:"action"`
+	EntityType    string       `json:"entity_type" db:"entity_type"`
+	EntityID      int          `json:"entity_id" db:"entity_id"`
+	Changes       AuditChanges `json:"changes" db:"changes"`
+	RequestID     string       `json:"request_id,omitempty" db:"request_id"`
+	IP            string       `json:"ip,omitempty" db:"ip"`
+	CreatedAt     time.Time    `json:"created_at" db:"created_at"`
+}
+
+// AuditChange - значение поля до и после изменения в JSON; null означает,
+// что поля не было (создание, удаление или пустое значение).
+type AuditChange struct {
+	Before json.RawMessage `json:"before"`
+	After  json.RawMessage `json:"after"`
+}
+
+// AuditChanges - изменения по именам полей JSON-представления сущности.
+type AuditChanges map[string]AuditChange
+
+func (c AuditChanges) Value() (driver.Value, error) {
+	if c == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(c)
+	return string(data), err
+}
+
+func (c *AuditChanges) Scan(src interface{}) error {
+	return scanJSON(src, c)
+}
+
+// auditIgnoredFields не попадают в изменения: они меняются при каждом
+// изменении или выводятся из других данных и записываются отдельно.
+var auditIgnoredFields = map[string]bool{
+	"updated_at":  true,
+	"variants":    true,
+	"stock":       true,
+	"allocations": true,
+	"returns":     true,
+	"shipments":   true,
+	"adjustments": true,
+}
+
+// DiffAudit сравнивает JSON-представления before и after по полям верхнего
+// уровня. Для создания before - nil, для удаления after - nil.
+func DiffAudit(before, after interface{}) (AuditChanges, error) {
+	beforeFields, err := auditFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := auditFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := AuditChanges{}
+	for name, value := range beforeFields {
+		if other, ok := afterFields[name]; !ok || !bytes.Equal(value, other) {
+			changes[name] = AuditChange{Before: value, After: afterFields[name]}
+		}
+	}
+	for name, value := range afterFields {
+		if _, ok := beforeFields[name]; !ok {
+			changes[name] = AuditChange{After: value}
+		}
+	}
+	return changes, nil
+}
+
+func auditFields(v interface{}) (map[string]json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode audit snapshot: %w", err)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("failed to encode audit snapshot: %w", err)
+	}
+	for name, value := range fields {
+		if auditIgnoredFields[name] || bytes.Equal(value, []byte("null")) {
+			delete(fields, name)
+		}
+	}
+	return fields, nil
+}
+
+// AuditFilter отбирает записи журнала; пустые поля не ограничивают
+// выборку. Записи возвращаются от новых к старым.
+type AuditFilter struct {
+	EntityType string
+	EntityID   int
+	Limit      int
+}
+
+func (f AuditFilter) Validate() error {
+	if f.EntityID < 0 {
+		return errors.New("invalid entity ID")
+	}
+	if f.EntityID > 0 && f.EntityType == "" {
+		return errors.New("entity is required when id is set")
+	}
+	if f.Limit < 0 {
+		return errors.New("limit cannot be negative")
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffAudit_Update(t *testing.T) {
+	// Arrange
+	before := Product{ID: 1, Name: "Mug", SKU: "MUG-1", Price: 10, UpdatedAt: time.Now()}
+	after := before
+	after.Price = 12
+	after.UpdatedAt = before.UpdatedAt.Add(time.Minute)
+
+	// Act
+	changes, err := DiffAudit(&before, &after)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, changes, 1)
+	assert.JSONEq(t, "10", string(changes["price"].Before))
+	assert.JSONEq(t, "12", string(changes["price"].After))
+}
+
+func TestDiffAudit_CreateAndDelete(t *testing.T) {
+	product := &Product{ID: 3, Name: "Mug", SKU: "MUG-3", Price: 10}
+
+	created, err := DiffAudit(nil, product)
+	assert.NoError(t, err)
+	assert.Nil(t, created["name"].Before)
+	assert.JSONEq(t, `"Mug"`, string(created["name"].After))
+
+	deleted, err := DiffAudit(product, nil)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `"MUG-3"`, string(deleted["sku"].Before))
+	assert.Nil(t, deleted["sku"].After)
+}
+
+func TestDiffAudit_IgnoredAndNullFields(t *testing.T) {
+	// Arrange
+	before := map[string]interface{}{"status": "pending", "updated_at": "a", "note": nil}
+	after := map[string]interface{}{"status": "pending", "updated_at": "b"}
+
+	// Act
+	changes, err := DiffAudit(before, after)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestAuditChanges_Value(t *testing.T) {
+	var empty AuditChanges
+	value, err := empty.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "{}", value)
+
+	changes := AuditChanges{"status": {Before: json.RawMessage(`"pending"`), After: json.RawMessage(`"paid"`)}}
+	value, err = changes.Value()
+	assert.NoError(t, err)
+
+	var scanned AuditChanges
+	assert.NoError(t, scanned.Scan([]byte(value.(string))))
+	assert.JSONEq(t, `"paid"`, string(scanned["status"].After))
+}
+
+func TestAuditFilter_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  AuditFilter
+		wantErr bool
+	}{
+		{name: "empty", filter: AuditFilter{}},
+		{name: "entity and id", filter: AuditFilter{EntityType: AggregateProduct, EntityID: 5, Limit: 10}},
+		{name: "id without entity", filter: AuditFilter{EntityID: 5}, wantErr: true},
+		{name: "negative id", filter: AuditFilter{EntityType: AggregateOrder, EntityID: -1}, wantErr: true},
+		{name: "negative limit", filter: AuditFilter{Limit: -1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.filter.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
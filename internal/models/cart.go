@@ -0,0 +1,63 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// Статусы корзины. Оформленная корзина хранит ссылку на заказ и больше не
+// изменяется.
+const (
+	CartActive     = "active"
+	CartCheckedOut = "checked_out"
+)
+
+// Cart - серверная корзина. Анонимная корзина доступна по Token; корзина
+// пользователя дополнительно привязана к UserID - идентификатору покупателя
+// (Customer), у пользователя не больше одной активной корзины. Цены и
+// доступность позиций не хранятся, а рассчитываются при каждом чтении.
+type Cart struct {
+	ID        int        `json:"id" db:"id"`
+	Token     string     `json:"token" db:"token"`
+	UserID    int        `json:"user_id,omitempty" db:"user_id"`
+	Status    string     `json:"status" db:"status"`
+	OrderID   int        `json:"order_id,omitempty" db:"order_id"`
+	Items     []CartItem `json:"items"`
+	Subtotal  int        `json:"subtotal" db:"-"`
+	ExpiresAt time.Time  `json:"expires_at" db:"expires_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// IsExpired сообщает, истек ли срок жизни активной корзины.
+func (c *Cart) IsExpired(now time.Time) bool {
+	return c.Status == CartActive && !c.ExpiresAt.After(now)
+}
+
+// CartItem - позиция корзины. Поля после CreatedAt заполняются по текущим
+// данным товара при чтении корзины; цены округляются так же, как в заказе.
+type CartItem struct {
+	ID        int       `json:"id" db:"id"`
+	CartID    int       `json:"cart_id" db:"cart_id"`
+	ProductID int       `json:"product_id" db:"product_id"`
+	VariantID int       `json:"variant_id,omitempty" db:"variant_id"`
+	Quantity  int       `json:"quantity" db:"quantity"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+
+	SKU       string `json:"sku,omitempty" db:"-"`
+	Name      string `json:"name" db:"-"`
+	UnitPrice int    `json:"unit_price" db:"-"`
+	LineTotal int    `json:"line_total" db:"-"`
+	Available int    `json:"available" db:"-"`
+	InStock   bool   `json:"in_stock" db:"-"`
+}
+
+func (i *CartItem) Validate() error {
+	if i.ProductID <= 0 && i.VariantID <= 0 {
+		return errors.New("product ID is required")
+	}
+	if i.Quantity <= 0 {
+		return errors.New("quantity must be positive")
+	}
+	return nil
+}
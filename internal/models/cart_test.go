@@ -0,0 +1,34 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCart_IsExpired(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		cart Cart
+		want bool
+	}{
+		{"active before expiry", Cart{Status: CartActive, ExpiresAt: now.Add(time.Minute)}, false},
+		{"active at expiry", Cart{Status: CartActive, ExpiresAt: now}, true},
+		{"checked out never expires", Cart{Status: CartCheckedOut, ExpiresAt: now.Add(-time.Hour)}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.cart.IsExpired(now))
+		})
+	}
+}
+
+func TestCartItem_Validate(t *testing.T) {
+	assert.NoError(t, (&CartItem{VariantID: 3, Quantity: 1}).Validate())
+	assert.EqualError(t, (&CartItem{Quantity: 1}).Validate(), "product ID is required")
+	assert.EqualError(t, (&CartItem{ProductID: 1}).Validate(), "quantity must be positive")
+}
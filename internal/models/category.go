@@ -0,0 +1,181 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Category - узел дерева категорий (список смежности: ParentID + Position
+// задают место среди соседей).
+type Category struct {
+	ID        int       `json:"id" db:"id"`
+	ParentID  *int      `json:"parent_id" db:"parent_id"`
+	Name      string    `json:"name" db:"name"`
+	Slug      string    `json:"slug" db:"slug"`
+	Position  int       `json:"position" db:"position"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+
+	Children []*Category `json:"children,omitempty"`
+}
+
+// CategoryPositionEnd ставит категорию последней среди соседей.
+const CategoryPositionEnd = math.MaxInt32
+
+func (c *Category) Validate() error {
+	if strings.TrimSpace(c.Name) == "" {
+		return errors.New("category name is required")
+	}
+	if len(c.Name) > 100 {
+		return errors.New("category name is too long")
+	}
+	if len(c.Slug) > 100 {
+		return errors.New("category slug is too long")
+	}
+	if c.Position < 0 {
+		return errors.New("category position cannot be negative")
+	}
+	if c.ParentID != nil && *c.ParentID <= 0 {
+		return errors.New("invalid parent category ID")
+	}
+	return nil
+}
+
+// ParentKey возвращает ID родителя или 0 для корневой категории.
+func (c *Category) ParentKey() int {
+	if c.ParentID == nil {
+		return 0
+	}
+	return *c.ParentID
+}
+
+// Slugify строит slug из названия: буквы и цифры в нижнем регистре,
+// остальные символы заменяются дефисом.
+func Slugify(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(strings.TrimSpace(name)) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
+
+// CategoryTree - индекс плоского списка категорий для обхода дерева.
+type CategoryTree struct {
+	byID     map[int]*Category
+	children map[int][]*Category
+}
+
+func NewCategoryTree(categories []*Category) *CategoryTree {
+	t := &CategoryTree{
+		byID:     make(map[int]*Category, len(categories)),
+		children: make(map[int][]*Category),
+	}
+	for _, c := range categories {
+		t.byID[c.ID] = c
+		t.children[c.ParentKey()] = append(t.children[c.ParentKey()], c)
+	}
+	for _, siblings := range t.children {
+		sortCategories(siblings)
+	}
+	return t
+}
+
+func (t *CategoryTree) Get(id int) (*Category, bool) {
+	c, ok := t.byID[id]
+	return c, ok
+}
+
+// Children возвращает прямых потомков категории (0 - корень) в порядке Position.
+func (t *CategoryTree) Children(parentID int) []*Category {
+	return t.children[parentID]
+}
+
+// Descendants возвращает ID категории и всех ее потомков.
+func (t *CategoryTree) Descendants(id int) []int {
+	ids := []int{id}
+	for i := 0; i < len(ids); i++ {
+		for _, child := range t.children[ids[i]] {
+			ids = append(ids, child.ID)
+		}
+	}
+	return ids
+}
+
+// IsDescendant сообщает, лежит ли candidate в поддереве id (включая сам id).
+func (t *CategoryTree) IsDescendant(candidate, id int) bool {
+	for _, d := range t.Descendants(id) {
+		if d == candidate {
+			return true
+		}
+	}
+	return false
+}
+
+// Build возвращает копию дерева с заполненными Children.
+func (t *CategoryTree) Build() []*Category {
+	var build func(parentID int) []*Category
+	build = func(parentID int) []*Category {
+		siblings := t.children[parentID]
+		nodes := make([]*Category, 0, len(siblings))
+		for _, c := range siblings {
+			node := *c
+			node.Children = build(c.ID)
+			nodes = append(nodes, &node)
+		}
+		return nodes
+	}
+	return build(0)
+}
+
+func sortCategories(categories []*Category) {
+	sort.Slice(categories, func(i, j int) bool {
+		if categories[i].Position != categories[j].Position {
+			return categories[i].Position < categories[j].Position
+		}
+		return categories[i].ID < categories[j].ID
+	})
+}
+
+// NormalizeTags приводит теги к нижнему регистру, убирает пробелы по краям,
+// пустые значения и дубликаты. Порядок первых вхождений сохраняется.
+func NormalizeTags(tags []string) ([]string, error) {
+	result := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if len(tag) > 50 {
+			return nil, fmt.Errorf("tag %q is too long", tag)
+		}
+		seen[tag] = true
+		result = append(result, tag)
+	}
+	return result, nil
+}
+
+// TagCount - тег и количество товаров с ним.
+type TagCount struct {
+	Tag      string `json:"tag" db:"tag"`
+	Products int    `json:"products" db:"products"`
+}
+
+// ProductFilter - условия отбора товаров в списке.
+// CategoryID включает товары из всех подкатегорий.
+type ProductFilter struct {
+	CategoryID int
+	Tag        string
+}
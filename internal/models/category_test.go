@@ -0,0 +1,40 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCategoryTree(t *testing.T) {
+	one, two := 1, 2
+	tree := NewCategoryTree([]*Category{
+		{ID: 1, Name: "Electronics", Position: 0},
+		{ID: 2, ParentID: &one, Name: "Computers", Position: 1},
+		{ID: 3, ParentID: &one, Name: "Phones", Position: 0},
+		{ID: 4, ParentID: &two, Name: "Laptops", Position: 0},
+		{ID: 5, Name: "Books", Position: 1},
+	})
+
+	assert.ElementsMatch(t, []int{1, 2, 3, 4}, tree.Descendants(1))
+	assert.Equal(t, []int{4}, tree.Descendants(4))
+	assert.True(t, tree.IsDescendant(4, 1))
+	assert.False(t, tree.IsDescendant(5, 1))
+
+	built := tree.Build()
+	assert.Len(t, built, 2)
+	assert.Equal(t, "Electronics", built[0].Name)
+	assert.Equal(t, "Phones", built[0].Children[0].Name)
+	assert.Equal(t, "Laptops", built[0].Children[1].Children[0].Name)
+}
+
+func TestSlugify(t *testing.T) {
+	assert.Equal(t, "home-garden", Slugify("  Home & Garden "))
+	assert.Equal(t, "tv-4k", Slugify("TV: 4K!"))
+}
+
+func TestNormalizeTags(t *testing.T) {
+	tags, err := NormalizeTags([]string{" Sale", "sale", "", "New "})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"sale", "new"}, tags)
+}
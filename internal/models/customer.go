@@ -0,0 +1,101 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// Назначение адреса покупателя.
+const (
+	AddressShipping = "shipping"
+	AddressBilling  = "billing"
+)
+
+// Customer - покупатель. Email уникален без учета регистра и хранится в
+// нижнем регистре. Адреса сохраняются вместе с покупателем и при обновлении
+// заменяются целиком.
+type Customer struct {
+	ID        int               `json:"id" db:"id"`
+	Email     string            `json:"email" db:"email"`
+	Name      string            `json:"name" db:"name"`
+	Phone     string            `json:"phone,omitempty" db:"phone"`
+	Addresses []CustomerAddress `json:"addresses"`
+	CreatedAt time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at" db:"updated_at"`
+}
+
+// CustomerAddress - адрес доставки или оплаты. Среди адресов одного
+// назначения не больше одного адреса по умолчанию.
+type CustomerAddress struct {
+	ID         int       `json:"id" db:"id"`
+	CustomerID int       `json:"customer_id" db:"customer_id"`
+	Kind       string    `json:"kind" db:"kind"`
+	IsDefault  bool      `json:"is_default" db:"is_default"`
+	Address    Address   `json:"address" db:"address"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// Normalize приводит email к каноническому виду и убирает лишние пробелы.
+func (c *Customer) Normalize() {
+	c.Email = strings.ToLower(strings.TrimSpace(c.Email))
+	c.Name = strings.TrimSpace(c.Name)
+	c.Phone = strings.TrimSpace(c.Phone)
+}
+
+func (c *Customer) Validate() error {
+	if c.Email == "" {
+		return errors.New("customer email is required")
+	}
+	if len(c.Email) > 255 {
+		return errors.New("customer email is too long")
+	}
+	if addr, err := mail.ParseAddress(c.Email); err != nil || addr.Address != c.Email {
+		return fmt.Errorf("customer email %q is invalid", c.Email)
+	}
+	if len(c.Name) > 255 {
+		return errors.New("customer name is too long")
+	}
+	if len(c.Phone) > 32 {
+		return errors.New("customer phone is too long")
+	}
+
+	defaults := map[string]bool{}
+	for i := range c.Addresses {
+		a := &c.Addresses[i]
+		if a.Kind != AddressShipping && a.Kind != AddressBilling {
+			return fmt.Errorf("address kind must be %q or %q", AddressShipping, AddressBilling)
+		}
+		if err := a.Address.Validate(); err != nil {
+			return err
+		}
+		if a.IsDefault {
+			if defaults[a.Kind] {
+				return fmt.Errorf("customer can have only one default %s address", a.Kind)
+			}
+			defaults[a.Kind] = true
+		}
+	}
+	return nil
+}
+
+// DefaultAddress возвращает адрес по умолчанию указанного назначения, а если
+// он не отмечен - первый адрес этого назначения.
+func (c *Customer) DefaultAddress(kind string) *Address {
+	var first *Address
+	for i := range c.Addresses {
+		a := &c.Addresses[i]
+		if a.Kind != kind {
+			continue
+		}
+		if a.IsDefault {
+			return &a.Address
+		}
+		if first == nil {
+			first = &a.Address
+		}
+	}
+	return first
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCustomer_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		customer Customer
+		err      string
+	}{
+		{name: "valid", customer: Customer{Email: "ann@example.com"}},
+		{name: "missing email", customer: Customer{}, err: "customer email is required"},
+		{name: "invalid email", customer: Customer{Email: "Ann <ann@example.com>"}, err: `customer email "Ann <ann@example.com>" is invalid`},
+		{
+			name: "unknown address kind",
+			customer: Customer{Email: "ann@example.com", Addresses: []CustomerAddress{
+				{Kind: "home"},
+			}},
+			err: `address kind must be "shipping" or "billing"`,
+		},
+		{
+			name: "two default shipping addresses",
+			customer: Customer{Email: "ann@example.com", Addresses: []CustomerAddress{
+				{Kind: AddressShipping, IsDefault: true},
+				{Kind: AddressBilling, IsDefault: true},
+				{Kind: AddressShipping, IsDefault: true},
+			}},
+			err: "customer can have only one default shipping address",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.customer.Validate()
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestCustomer_Normalize(t *testing.T) {
+	customer := Customer{Email: "  Ann@Example.COM ", Name: " Ann ", Phone: " +7 900 "}
+
+	customer.Normalize()
+
+	assert.Equal(t, "ann@example.com", customer.Email)
+	assert.Equal(t, "Ann", customer.Name)
+	assert.Equal(t, "+7 900", customer.Phone)
+}
+
+func TestCustomer_DefaultAddress(t *testing.T) {
+	customer := Customer{Addresses: []CustomerAddress{
+		{Kind: AddressBilling, IsDefault: true, Address: Address{City: "Kazan"}},
+		{Kind: AddressShipping, Address: Address{City: "Moscow"}},
+		{Kind: AddressShipping, IsDefault: true, Address: Address{City: "Tver"}},
+	}}
+
+	assert.Equal(t, "Tver", customer.DefaultAddress(AddressShipping).City)
+	assert.Equal(t, "Kazan", customer.DefaultAddress(AddressBilling).City)
+
+	customer.Addresses[2].IsDefault = false
+	assert.Equal(t, "Moscow", customer.DefaultAddress(AddressShipping).City)
+	assert.Nil(t, (&Customer{}).DefaultAddress(AddressShipping))
+}
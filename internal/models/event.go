@@ -0,0 +1,164 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Типы доменных событий.
+const (
+	EventOrderCreated        = "order.created"
+	EventOrderStatusChanged  = "order.status_changed"
+	EventProductPriceChanged = "product.price_changed"
+	EventStockDepleted       = "stock.depleted"
+)
+
+// Сущности, к которым относятся события.
+const (
+	AggregateOrder   = "order"
+	AggregateProduct = "product"
+)
+
+// Состояния события в outbox. Событие ждет доставки в OutboxPending; после
+// исчерпания попыток переходит в OutboxDead и доставляется только повторно
+// вручную.
+const (
+	OutboxPending   = "pending"
+	OutboxDelivered = "delivered"
+	OutboxDead      = "dead"
+)
+
+// Интервал повтора доставки удваивается с каждой попыткой от
+// outboxRetryBase до outboxRetryMax.
+const (
+	outboxRetryBase = 10 * time.Second
+	outboxRetryMax  = time.Hour
+)
+
+// OutboxEvent - доменное событие, записанное в той же транзакции, что и
+// изменение данных. Доставка обработчикам выполняется не менее одного раза,
+// поэтому обработчики должны быть идемпотентны по ID события.
+type OutboxEvent struct {
+	ID            int             `json:"id" db:"id"`
+	Type          string          `json:"type" db:"type"`
+	AggregateType string          `json:"aggregate_type" db:"aggregate_type"`
+	AggregateID   int             `json:"aggregate_id" db:"aggregate_id"`
+	Payload       json.RawMessage `json:"payload" db:"payload"`
+	Status        string          `json:"status" db:"status"`
+	Attempts      int             `json:"attempts" db:"attempts"`
+	LastError     string          `json:"last_error,omitempty" db:"last_error"`
+	NextAttemptAt time.Time       `json:"next_attempt_at" db:"next_attempt_at"`
+	CreatedAt     time.Time       `json:"created_at" db:"created_at"`
+	DeliveredAt   *time.Time      `json:"delivered_at,omitempty" db:"delivered_at"`
+}
+
+// NewOutboxEvent создает событие, готовое к немедленной доставке.
+func NewOutboxEvent(eventType, aggregateType string, aggregateID int, payload interface{}) (*OutboxEvent, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s payload: %w", eventType, err)
+	}
+	now := time.Now()
+	return &OutboxEvent{
+		Type:          eventType,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		Payload:       data,
+		Status:        OutboxPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}, nil
+}
+
+// Delivered отмечает успешную доставку.
+func (e *OutboxEvent) Delivered(now time.Time) {
+	e.Attempts++
+	e.Status = OutboxDelivered
+	e.LastError = ""
+	e.DeliveredAt = &now
+}
+
+// Failed учитывает неудачную попытку: назначает следующую с растущим
+// интервалом или, если попыток было maxAttempts, переводит событие в
+// OutboxDead.
+func (e *OutboxEvent) Failed(err error, now time.Time, maxAttempts int) {
+	e.Attempts++
+	e.LastError = err.Error()
+	if maxAttempts > 0 && e.Attempts >= maxAttempts {
+		e.Status = OutboxDead
+		return
+	}
+	e.NextAttemptAt = now.Add(OutboxRetryDelay(e.Attempts))
+}
+
+// Replay возвращает доставленное или отброшенное событие в очередь с
+// новым счетчиком попыток.
+func (e *OutboxEvent) Replay(now time.Time) error {
+	if e.Status == OutboxPending {
+		return fmt.Errorf("cannot replay event %d: already pending", e.ID)
+	}
+	e.Status = OutboxPending
+	e.Attempts = 0
+	e.LastError = ""
+	e.NextAttemptAt = now
+	e.DeliveredAt = nil
+	return nil
+}
+
+// OutboxRetryDelay возвращает паузу перед попыткой, следующей за attempts
+// неудачными.
+func OutboxRetryDelay(attempts int) time.Duration {
+	return backoff(outboxRetryBase, outboxRetryMax, attempts)
+}
+
+// backoff удваивает base с каждой неудачной попыткой, не превышая limit.
+func backoff(base, limit time.Duration, attempts int) time.Duration {
+	delay := base
+	for i := 1; i < attempts && delay < limit; i++ {
+		delay *= 2
+	}
+	return min(delay, limit)
+}
+
+// OutboxFilter отбирает события для просмотра; пустые поля не ограничивают
+// выборку. События возвращаются от новых к старым.
+type OutboxFilter struct {
+	Status string
+	Type   string
+	Limit  int
+}
+
+// OrderCreatedPayload - данные события EventOrderCreated.
+type OrderCreatedPayload struct {
+	OrderID    int    `json:"order_id"`
+	CustomerID int    `json:"customer_id"`
+	Status     string `json:"status"`
+	Total      int    `json:"total"`
+}
+
+// OrderStatusChangedPayload - данные события EventOrderStatusChanged.
+type OrderStatusChangedPayload struct {
+	OrderID    int    `json:"order_id"`
+	CustomerID int    `json:"customer_id"`
+	From       string `json:"from"`
+	To         string `json:"to"`
+}
+
+// ProductPriceChangedPayload - данные события EventProductPriceChanged.
+// VariantID пуст, если изменилась базовая цена товара.
+type ProductPriceChangedPayload struct {
+	ProductID int     `json:"product_id"`
+	VariantID int     `json:"variant_id,omitempty"`
+	SKU       string  `json:"sku,omitempty"`
+	OldPrice  float64 `json:"old_price"`
+	NewPrice  float64 `json:"new_price"`
+}
+
+// StockDepletedPayload - данные события EventStockDepleted: суммарный
+// остаток варианта по всем складам стал нулевым.
+type StockDepletedPayload struct {
+	ProductID int    `json:"product_id"`
+	VariantID int    `json:"variant_id"`
+	SKU       string `json:"sku,omitempty"`
+}
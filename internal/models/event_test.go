@@ -0,0 +1,82 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewOutboxEvent(t *testing.T) {
+	// Act
+	event, err := NewOutboxEvent(EventOrderStatusChanged, AggregateOrder, 5, OrderStatusChangedPayload{
+		OrderID: 5, CustomerID: 2, From: OrderStatusPending, To: OrderStatusProcessing,
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, OutboxPending, event.Status)
+	assert.Equal(t, 5, event.AggregateID)
+	assert.False(t, event.NextAttemptAt.After(time.Now()))
+
+	var payload OrderStatusChangedPayload
+	assert.NoError(t, json.Unmarshal(event.Payload, &payload))
+	assert.Equal(t, OrderStatusProcessing, payload.To)
+}
+
+func TestOutboxRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		expected time.Duration
+	}{
+		{attempts: 1, expected: 10 * time.Second},
+		{attempts: 2, expected: 20 * time.Second},
+		{attempts: 4, expected: 80 * time.Second},
+		{attempts: 12, expected: time.Hour},
+		{attempts: 100, expected: time.Hour},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, OutboxRetryDelay(tt.attempts), "attempts %d", tt.attempts)
+	}
+}
+
+func TestOutboxEvent_FailedUntilDead(t *testing.T) {
+	// Arrange
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	event := OutboxEvent{ID: 1, Status: OutboxPending}
+
+	// Act
+	event.Failed(errors.New("connection refused"), now, 3)
+	event.Failed(errors.New("connection refused"), now, 3)
+
+	// Assert
+	assert.Equal(t, OutboxPending, event.Status)
+	assert.Equal(t, now.Add(20*time.Second), event.NextAttemptAt)
+
+	event.Failed(errors.New("timeout"), now, 3)
+	assert.Equal(t, OutboxDead, event.Status)
+	assert.Equal(t, 3, event.Attempts)
+	assert.Equal(t, "timeout", event.LastError)
+}
+
+func TestOutboxEvent_Replay(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	delivered := now.Add(-time.Hour)
+
+	dead := OutboxEvent{ID: 1, Status: OutboxDead, Attempts: 10, LastError: "timeout"}
+	assert.NoError(t, dead.Replay(now))
+	assert.Equal(t, OutboxPending, dead.Status)
+	assert.Equal(t, 0, dead.Attempts)
+	assert.Empty(t, dead.LastError)
+	assert.Equal(t, now, dead.NextAttemptAt)
+
+	done := OutboxEvent{ID: 2, Status: OutboxDelivered, DeliveredAt: &delivered}
+	assert.NoError(t, done.Replay(now))
+	assert.Nil(t, done.DeliveredAt)
+
+	pending := OutboxEvent{ID: 3, Status: OutboxPending}
+	assert.EqualError(t, pending.Replay(now), "cannot replay event 3: already pending")
+}
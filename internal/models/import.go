@@ -0,0 +1,41 @@
+package models
+
+type ImportRowStatus string
+
+const (
+	ImportRowCreated ImportRowStatus = "created"
+	ImportRowUpdated ImportRowStatus = "updated"
+	ImportRowFailed  ImportRowStatus = "failed"
+)
+
+// ImportRowResult - результат обработки одной строки импорта.
+// Row - номер строки во входном файле (для CSV с учетом заголовка).
+type ImportRowResult struct {
+	Row       int             `json:"row"`
+	SKU       string          `json:"sku"`
+	Status    ImportRowStatus `json:"status"`
+	ProductID int             `json:"product_id,omitempty"`
+	Error     string          `json:"error,omitempty"`
+}
+
+type ImportReport struct {
+	DryRun  bool              `json:"dry_run"`
+	Total   int               `json:"total"`
+	Created int               `json:"created"`
+	Updated int               `json:"updated"`
+	Failed  int               `json:"failed"`
+	Rows    []ImportRowResult `json:"rows"`
+}
+
+func (r *ImportReport) Add(result ImportRowResult) {
+	r.Total++
+	switch result.Status {
+	case ImportRowCreated:
+		r.Created++
+	case ImportRowUpdated:
+		r.Updated++
+	case ImportRowFailed:
+		r.Failed++
+	}
+	r.Rows = append(r.Rows, result)
+}
@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+// LowStockEventType - тип уведомления о том, что товар заканчивается.
+const LowStockEventType = "stock.low"
+
+// LowStockAlert фиксирует, что остаток товара опустился до порога дозаказа.
+// У товара может быть только одно открытое предупреждение: новое создается
+// лишь после того, как остаток поднимется выше порога и текущее закроется.
+type LowStockAlert struct {
+	ID              int        `json:"id" db:"id"`
+	ProductID       int        `json:"product_id" db:"product_id"`
+	Quantity        int        `json:"quantity" db:"quantity"`
+	ReorderPoint    int        `json:"reorder_point" db:"reorder_point"`
+	ReorderQuantity int        `json:"reorder_quantity" db:"reorder_quantity"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	NotifiedAt      *time.Time `json:"notified_at,omitempty" db:"notified_at"`
+	ResolvedAt      *time.Time `json:"resolved_at,omitempty" db:"resolved_at"`
+}
+
+// LowStockEvent - уведомление, отправляемое при открытии предупреждения.
+type LowStockEvent struct {
+	Event           string    `json:"event"`
+	AlertID         int       `json:"alert_id"`
+	ProductID       int       `json:"product_id"`
+	SKU             string    `json:"sku,omitempty"`
+	Name            string    `json:"name"`
+	Quantity        int       `json:"quantity"`
+	ReorderPoint    int       `json:"reorder_point"`
+	ReorderQuantity int       `json:"reorder_quantity"`
+	OccurredAt      time.Time `json:"occurred_at"`
+}
+
+func NewLowStockEvent(alert LowStockAlert, product *Product) LowStockEvent {
+	return LowStockEvent{
+		Event:           LowStockEventType,
+		AlertID:         alert.ID,
+		ProductID:       product.ID,
+		SKU:             product.SKU,
+		Name:            product.Name,
+		Quantity:        alert.Quantity,
+		ReorderPoint:    alert.ReorderPoint,
+		ReorderQuantity: alert.ReorderQuantity,
+		OccurredAt:      alert.CreatedAt,
+	}
+}
+
+// LowStockItem - строка отчета о заканчивающихся товарах. AlertedAt пуст,
+// пока фоновая проверка еще не открыла предупреждение.
+type LowStockItem struct {
+	ProductID       int        `json:"product_id"`
+	SKU             string     `json:"sku,omitempty"`
+	Name            string     `json:"name"`
+	Quantity        int        `json:"quantity"`
+	ReorderPoint    int        `json:"reorder_point"`
+	ReorderQuantity int        `json:"reorder_quantity"`
+	AlertedAt       *time.Time `json:"alerted_at,omitempty"`
+}
@@ -0,0 +1,72 @@
+package models
+
+import "time"
+
+// Причины движения товара. Знак Delta показывает направление: отгрузка по
+// перемещению и возврат отгруженного при его отмене имеют одну причину.
+const (
+	MovementOpening      = "opening"
+	MovementOrder        = "order"
+	MovementCancellation = "cancellation"
+	MovementAdjustment   = "adjustment"
+	MovementImport       = "import"
+	MovementTransfer     = "transfer"
+	MovementStocktake    = "stocktake"
+	MovementReturn       = "return"
+	MovementPurchase     = "purchase"
+)
+
+// StockMovement - неизменяемая запись журнала движения товара. Остаток на
+// складе является проекцией журнала: сумма Delta по складу и варианту равна
+// StockLevel.Quantity, а BalanceAfter - остатку сразу после движения.
+type StockMovement struct {
+	ID           int    `json:"id" db:"id"`
+	WarehouseID  int    `json:"warehouse_id" db:"warehouse_id"`
+	VariantID    int    `json:"variant_id" db:"variant_id"`
+	ProductID    int    `json:"product_id" db:"product_id"`
+	Delta        int    `json:"delta" db:"delta"`
+	BalanceAfter int    `json:"balance_after" db:"balance_after"`
+	Reason       string `json:"reason" db:"reason"`
+	Actor        string `json:"actor" db:"actor"`
+	// ActorVerified ложно, если Actor назвал клиент (X-Actor) без проверки.
+	ActorVerified bool      `json:"actor_verified" db:"actor_verified"`
+	Reference     string    `json:"reference,omitempty" db:"reference"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
+
+// StockBalance - остаток по журналу для пары склад-вариант.
+type StockBalance struct {
+	WarehouseID int `db:"warehouse_id"`
+	VariantID   int `db:"variant_id"`
+	ProductID   int `db:"product_id"`
+	Quantity    int `db:"quantity"`
+	// BrokenChain - число движений, у которых BalanceAfter не равен
+	// предыдущему BalanceAfter плюс Delta.
+	BrokenChain int `db:"broken_chain"`
+}
+
+const (
+	DriftWarehouseStock = "warehouse_stock"
+	DriftVariant        = "variant"
+	DriftProduct        = "product"
+	DriftLedgerChain    = "ledger_chain"
+)
+
+// StockDrift - расхождение сохраненного остатка с его проекцией.
+// Expected - значение по журналу (или сумма нижнего уровня), Actual - сохраненное.
+type StockDrift struct {
+	Scope       string `json:"scope"`
+	WarehouseID int    `json:"warehouse_id,omitempty"`
+	VariantID   int    `json:"variant_id,omitempty"`
+	ProductID   int    `json:"product_id"`
+	Expected    int    `json:"expected"`
+	Actual      int    `json:"actual"`
+}
+
+type StockReconciliation struct {
+	CheckedAt time.Time    `json:"checked_at"`
+	Levels    int          `json:"levels"`
+	Variants  int          `json:"variants"`
+	Products  int          `json:"products"`
+	Drifts    []StockDrift `json:"drifts"`
+}
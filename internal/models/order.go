@@ -2,29 +2,72 @@ package models
 
 import (
 	"errors"
+	"fmt"
 	"time"
 )
 
 type Order struct {
-	ID        int         `json:"id"`
+	ID        int         `json:"id" db:"id"`
 	Products  []OrderItem `json:"products"`
-	Status    string      `json:"status"`
-	Total     int         `json:"total"`
-	CreatedAt time.Time   `json:"created_at"`
-	UpdatedAt time.Time   `json:"updated_at"`
+	Status    string      `json:"status" db:"status"`
+	Total     int         `json:"total" db:"total"`
+	CreatedAt time.Time   `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at" db:"updated_at"`
+
+	CustomerID      int               `json:"customer_id" db:"customer_id"`
+	ShippingAddress *Address          `json:"shipping_address,omitempty" db:"shipping_address"`
+	Allocations     []StockAllocation `json:"allocations,omitempty"`
+
+	// Returns и Shipments заполняются только при получении заказа по ID.
+	Returns   []ReturnRequest `json:"returns,omitempty"`
+	Shipments []Shipment      `json:"shipments,omitempty"`
+
+	// Subtotal - сумма позиций до скидок, Total = Subtotal - Discount.
+	// Adjustments заполняется только при получении заказа по ID.
+	PromoCode    string            `json:"promo_code,omitempty" db:"promo_code"`
+	Subtotal     int               `json:"subtotal" db:"subtotal"`
+	Discount     int               `json:"discount" db:"discount"`
+	FreeShipping bool              `json:"free_shipping" db:"free_shipping"`
+	Adjustments  []OrderAdjustment `json:"adjustments,omitempty"`
+
+	// Tax - налог по позициям. При TaxInclusive цены уже включают налог и
+	// Tax в Total не добавляется. Shipping - стоимость доставки выбранным
+	// способом ShippingMethod.
+	Tax            int    `json:"tax" db:"tax"`
+	TaxInclusive   bool   `json:"tax_inclusive" db:"tax_inclusive"`
+	ShippingMethod string `json:"shipping_method,omitempty" db:"shipping_method"`
+	Shipping       int    `json:"shipping" db:"shipping"`
 }
 
+// Статусы заказа. Новый заказ ждет оплаты в OrderStatusPending и переходит в
+// OrderStatusProcessing только после списания платежа. При переходе в
+// OrderStatusCancelled списанный под заказ товар возвращается на склады.
+const (
+	OrderStatusPending    = "pending"
+	OrderStatusProcessing = "processing"
+	OrderStatusCancelled  = "cancelled"
+)
+
 type OrderItem struct {
-	ID        int `json:"id"`
-	OrderID   int `json:"order_id"`
-	ProductID int `json:"product_id"`
-	Quantity  int `json:"quantity"`
-	Price     int `json:"price"`
+	ID        int `json:"id" db:"id"`
+	OrderID   int `json:"order_id" db:"order_id"`
+	ProductID int `json:"product_id" db:"product_id"`
+	VariantID int `json:"variant_id,omitempty" db:"variant_id"`
+	Quantity  int `json:"quantity" db:"quantity"`
+	Price     int `json:"price" db:"price"`
+
+	// TaxClass - налоговый класс товара на момент заказа, Tax - налог
+	// позиции после скидок.
+	TaxClass string `json:"tax_class,omitempty" db:"tax_class"`
+	Tax      int    `json:"tax" db:"tax"`
+
+	// Cost - себестоимость единицы на момент заказа для расчета маржи.
+	Cost int `json:"cost,omitempty" db:"cost"`
 }
 
 func (o *Order) Validate() error {
-	if o.ID <= 0 {
-		return errors.New("user ID is required")
+	if o.CustomerID <= 0 {
+		return errors.New("customer ID is required")
 	}
 	if len(o.Products) == 0 {
 		return errors.New("order must contain at least one product")
@@ -35,11 +78,19 @@ func (o *Order) Validate() error {
 			return err
 		}
 	}
+	if len(o.ShippingMethod) > 64 {
+		return errors.New("shipping method is too long")
+	}
+	if o.ShippingAddress != nil {
+		if err := o.ShippingAddress.Validate(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
 func (oi *OrderItem) Validate() error {
-	if oi.ProductID <= 0 {
+	if oi.ProductID <= 0 && oi.VariantID <= 0 {
 		return errors.New("product ID is required")
 	}
 	if oi.Quantity <= 0 {
@@ -51,10 +102,68 @@ func (oi *OrderItem) Validate() error {
 	return nil
 }
 
+// CalculateTotal складывает итог заказа из разбивки: сумма позиций минус
+// скидка, плюс доставка и налог, если он не входит в цены.
 func (o *Order) CalculateTotal() int {
-	total := 0
-	for _, item := range o.Products {
-		total += item.Price * item.Quantity
+	total := o.Subtotal - o.Discount + o.Shipping
+	if !o.TaxInclusive {
+		total += o.Tax
 	}
 	return total
 }
+
+// OrderFilter ограничивает выборку заказов. Нулевые значения не фильтруют.
+// From включительно, To - исключительно.
+type OrderFilter struct {
+	CustomerID int
+	Status     string
+	From       time.Time
+	To         time.Time
+}
+
+func (f OrderFilter) Match(o *Order) bool {
+	if f.CustomerID != 0 && o.CustomerID != f.CustomerID {
+		return false
+	}
+	if f.Status != "" && o.Status != f.Status {
+		return false
+	}
+	if !f.From.IsZero() && o.CreatedAt.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !o.CreatedAt.Before(f.To) {
+		return false
+	}
+	return true
+}
+
+// ParseOrderFilter разбирает границы периода в формате RFC3339 или YYYY-MM-DD.
+// Дата в to означает конец дня, чтобы to=2024-01-31 включал весь день.
+func ParseOrderFilter(status, from, to string) (OrderFilter, error) {
+	filter := OrderFilter{Status: status}
+	var err error
+	if filter.From, err = parseTimeBound(from, false); err != nil {
+		return filter, fmt.Errorf("invalid from: %w", err)
+	}
+	if filter.To, err = parseTimeBound(to, true); err != nil {
+		return filter, fmt.Errorf("invalid to: %w", err)
+	}
+	return filter, nil
+}
+
+func parseTimeBound(s string, upper bool) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected RFC3339 or YYYY-MM-DD, got %q", s)
+	}
+	if upper {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
@@ -122,3 +122,87 @@ func TestOrder_NilProducts(t *testing.T) {
 	assert.Equal(t, order.Status, decodedOrder.Status)
 	assert.Nil(t, decodedOrder.Products) // Должен остаться nil
 }
+
+func TestParseOrderFilter(t *testing.T) {
+	// Act
+	filter, err := ParseOrderFilter("completed", "2024-01-01", "2024-01-31")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "completed", filter.Status)
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), filter.From)
+	assert.Equal(t, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), filter.To) // конец дня 31 января
+
+	filter, err = ParseOrderFilter("", "2024-01-01T12:00:00Z", "")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), filter.From)
+	assert.True(t, filter.To.IsZero())
+
+	_, err = ParseOrderFilter("", "", "31.01.2024")
+	assert.EqualError(t, err, `invalid to: expected RFC3339 or YYYY-MM-DD, got "31.01.2024"`)
+}
+
+func TestOrderFilter_Match(t *testing.T) {
+	filter := OrderFilter{
+		Status: "pending",
+		From:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:     time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name  string
+		order Order
+		match bool
+	}{
+		{name: "inside period", order: Order{Status: "pending", CreatedAt: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)}, match: true},
+		{name: "at lower bound", order: Order{Status: "pending", CreatedAt: filter.From}, match: true},
+		{name: "at upper bound", order: Order{Status: "pending", CreatedAt: filter.To}, match: false},
+		{name: "other status", order: Order{Status: "completed", CreatedAt: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)}, match: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.match, filter.Match(&tt.order))
+		})
+	}
+}
+
+func TestOrder_Validate_RequiresCustomer(t *testing.T) {
+	// Arrange
+	order := &Order{
+		ID:       1,
+		Products: []OrderItem{{ProductID: 1, Quantity: 1}},
+	}
+
+	// Act & Assert
+	assert.EqualError(t, order.Validate(), "customer ID is required")
+
+	order.CustomerID = 7
+	assert.NoError(t, order.Validate())
+}
+
+func TestOrderFilter_Match_Customer(t *testing.T) {
+	filter := OrderFilter{CustomerID: 7}
+
+	assert.True(t, filter.Match(&Order{CustomerID: 7}))
+	assert.False(t, filter.Match(&Order{CustomerID: 8}))
+	assert.True(t, OrderFilter{}.Match(&Order{CustomerID: 8}))
+}
+
+func TestOrder_CalculateTotal(t *testing.T) {
+	tests := []struct {
+		name  string
+		order Order
+		want  int
+	}{
+		{"tax added", Order{Subtotal: 1000, Discount: 100, Shipping: 50, Tax: 90}, 1040},
+		{"tax included in prices", Order{Subtotal: 1000, Discount: 100, Shipping: 50, Tax: 150, TaxInclusive: true}, 950},
+		{"no tax", Order{Subtotal: 300}, 300},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.order.CalculateTotal())
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OrderStreamEvent - изменение заказа для потока SSE. ID совпадает с ID
+// события outbox и передается клиенту как id события SSE.
+type OrderStreamEvent struct {
+	ID             int       `json:"-"`
+	Type           string    `json:"type"`
+	OrderID        int       `json:"order_id"`
+	CustomerID     int       `json:"customer_id"`
+	Status         string    `json:"status"`
+	PreviousStatus string    `json:"previous_status,omitempty"`
+	Total          int       `json:"total,omitempty"`
+	OccurredAt     time.Time `json:"occurred_at"`
+}
+
+// NewOrderStreamEvent строит событие потока из события outbox о заказе.
+// Для событий других типов возвращает false.
+func NewOrderStreamEvent(event OutboxEvent) (OrderStreamEvent, bool, error) {
+	result := OrderStreamEvent{ID: event.ID, Type: event.Type, OccurredAt: event.CreatedAt}
+	switch event.Type {
+	case EventOrderCreated:
+		var payload OrderCreatedPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return result, false, fmt.Errorf("failed to decode %s payload: %w", event.Type, err)
+		}
+		result.OrderID = payload.OrderID
+		result.CustomerID = payload.CustomerID
+		result.Status = payload.Status
+		result.Total = payload.Total
+	case EventOrderStatusChanged:
+		var payload OrderStatusChangedPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return result, false, fmt.Errorf("failed to decode %s payload: %w", event.Type, err)
+		}
+		result.OrderID = payload.OrderID
+		result.CustomerID = payload.CustomerID
+		result.Status = payload.To
+		result.PreviousStatus = payload.From
+	default:
+		return result, false, nil
+	}
+	return result, true, nil
+}
+
+// EventID возвращает id события SSE.
+func (e OrderStreamEvent) EventID() string {
+	return strconv.Itoa(e.ID)
+}
+
+// OrderStreamFilter отбирает события потока; нулевые поля не фильтруют.
+// Statuses сравнивается с новым статусом заказа.
+type OrderStreamFilter struct {
+	OrderID    int
+	CustomerID int
+	Statuses   []string
+}
+
+// ParseOrderStreamFilter разбирает параметры запроса: status - список
+// статусов через запятую, customer_id - ID покупателя.
+func ParseOrderStreamFilter(status, customerID string) (OrderStreamFilter, error) {
+	var filter OrderStreamFilter
+	for _, s := range strings.Split(status, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		if len(s) > 32 {
+			return filter, fmt.Errorf("invalid status %q", s)
+		}
+		filter.Statuses = append(filter.Statuses, s)
+	}
+	if customerID != "" {
+		id, err := strconv.Atoi(customerID)
+		if err != nil || id <= 0 {
+			return filter, fmt.Errorf("invalid customer ID %q", customerID)
+		}
+		filter.CustomerID = id
+	}
+	return filter, nil
+}
+
+func (f OrderStreamFilter) Match(e OrderStreamEvent) bool {
+	if f.OrderID != 0 && e.OrderID != f.OrderID {
+		return false
+	}
+	if f.CustomerID != 0 && e.CustomerID != f.CustomerID {
+		return false
+	}
+	if len(f.Statuses) == 0 {
+		return true
+	}
+	for _, s := range f.Statuses {
+		if s == e.Status {
+			return true
+		}
+	}
+	return false
+}
+
+// StreamAccess - права клиента потока заказов. Сотрудник (CustomerID == 0)
+// видит все заказы, покупатель - только свои.
+type StreamAccess struct {
+	CustomerID int `json:"customer_id,omitempty"`
+}
+
+func (a StreamAccess) Staff() bool {
+	return a.CustomerID == 0
+}
+
+// CanSee сообщает, доступен ли клиенту заказ покупателя customerID.
+func (a StreamAccess) CanSee(customerID int) bool {
+	return a.Staff() || a.CustomerID == customerID
+}
@@ -0,0 +1,92 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewOrderStreamEvent(t *testing.T) {
+	createdAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	statusChanged, _ := json.Marshal(OrderStatusChangedPayload{OrderID: 5, CustomerID: 2, From: OrderStatusPending, To: OrderStatusProcessing})
+	event, ok, err := NewOrderStreamEvent(OutboxEvent{ID: 11, Type: EventOrderStatusChanged, Payload: statusChanged, CreatedAt: createdAt})
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, OrderStreamEvent{
+		ID: 11, Type: EventOrderStatusChanged, OrderID: 5, CustomerID: 2,
+		Status: OrderStatusProcessing, PreviousStatus: OrderStatusPending, OccurredAt: createdAt,
+	}, event)
+	assert.Equal(t, "11", event.EventID())
+
+	created, _ := json.Marshal(OrderCreatedPayload{OrderID: 6, CustomerID: 2, Status: OrderStatusPending, Total: 1500})
+	event, ok, err = NewOrderStreamEvent(OutboxEvent{ID: 12, Type: EventOrderCreated, Payload: created})
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 1500, event.Total)
+
+	_, ok, err = NewOrderStreamEvent(OutboxEvent{ID: 13, Type: EventStockDepleted, Payload: []byte(`{}`)})
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestParseOrderStreamFilter(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     string
+		customerID string
+		expected   OrderStreamFilter
+		wantErr    bool
+	}{
+		{name: "empty", expected: OrderStreamFilter{}},
+		{name: "statuses", status: "pending, processing,", expected: OrderStreamFilter{Statuses: []string{"pending", "processing"}}},
+		{name: "customer", customerID: "7", expected: OrderStreamFilter{CustomerID: 7}},
+		{name: "invalid customer", customerID: "abc", wantErr: true},
+		{name: "negative customer", customerID: "-1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := ParseOrderStreamFilter(tt.status, tt.customerID)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, filter)
+		})
+	}
+}
+
+func TestOrderStreamFilter_Match(t *testing.T) {
+	event := OrderStreamEvent{ID: 1, OrderID: 5, CustomerID: 2, Status: OrderStatusProcessing}
+
+	tests := []struct {
+		name     string
+		filter   OrderStreamFilter
+		expected bool
+	}{
+		{name: "no filter", filter: OrderStreamFilter{}, expected: true},
+		{name: "same order", filter: OrderStreamFilter{OrderID: 5}, expected: true},
+		{name: "other order", filter: OrderStreamFilter{OrderID: 6}, expected: false},
+		{name: "same customer", filter: OrderStreamFilter{CustomerID: 2}, expected: true},
+		{name: "other customer", filter: OrderStreamFilter{CustomerID: 3}, expected: false},
+		{name: "status listed", filter: OrderStreamFilter{Statuses: []string{OrderStatusPending, OrderStatusProcessing}}, expected: true},
+		{name: "status not listed", filter: OrderStreamFilter{Statuses: []string{OrderStatusCancelled}}, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.filter.Match(event))
+		})
+	}
+}
+
+func TestStreamAccess_CanSee(t *testing.T) {
+	assert.True(t, StreamAccess{}.CanSee(5))
+	assert.True(t, StreamAccess{CustomerID: 5}.CanSee(5))
+	assert.False(t, StreamAccess{CustomerID: 5}.CanSee(6))
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Статусы платежа. Попытка начинается в PaymentPending; PaymentRequiresAction
+// ждет подтверждения покупателем, после которого шлюз присылает уведомление.
+// Списанный платеж (PaymentCaptured) переводит заказ в обработку.
+const (
+	PaymentPending        = "pending"
+	PaymentRequiresAction = "requires_action"
+	PaymentAuthorized     = "authorized"
+	PaymentCaptured       = "captured"
+	PaymentVoided         = "voided"
+	PaymentRefunded       = "refunded"
+	PaymentFailed         = "failed"
+)
+
+// Payment - попытка оплаты заказа. У заказа может быть много неудачных
+// попыток, но не больше одной активной.
+type Payment struct {
+	ID             int       `json:"id" db:"id"`
+	OrderID        int       `json:"order_id" db:"order_id"`
+	Provider       string    `json:"provider" db:"provider"`
+	Reference      string    `json:"reference,omitempty" db:"reference"`
+	Amount         int       `json:"amount" db:"amount"`
+	RefundedAmount int       `json:"refunded_amount" db:"refunded_amount"`
+	Status         string    `json:"status" db:"status"`
+	FailureReason  string    `json:"failure_reason,omitempty" db:"failure_reason"`
+	ActionURL      string    `json:"action_url,omitempty" db:"action_url"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// IsActive сообщает, занимает ли попытка заказ: пока она активна, новую
+// попытку начать нельзя.
+func (p *Payment) IsActive() bool {
+	switch p.Status {
+	case PaymentPending, PaymentRequiresAction, PaymentAuthorized, PaymentCaptured:
+		return true
+	}
+	return false
+}
@@ -2,17 +2,47 @@ package models
 
 import (
 	"errors"
+	"strings"
 	"time"
 )
 
 type Product struct {
-	ID          int       `json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	Price       float64   `json:"price"`
-	Quantity    int       `json:"quantity"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          int       `json:"id" db:"id"`
+	SKU         string    `json:"sku" db:"sku"`
+	Name        string    `json:"name" db:"name"`
+	Description string    `json:"description" db:"description"`
+	Price       float64   `json:"price" db:"price"`
+	Quantity    int       `json:"quantity" db:"quantity"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+
+	// ReorderPoint - порог остатка, при достижении которого товар считается
+	// заканчивающимся; 0 отключает контроль. ReorderQuantity - рекомендуемый
+	// объем дозаказа.
+	ReorderPoint    int `json:"reorder_point" db:"reorder_point"`
+	ReorderQuantity int `json:"reorder_quantity" db:"reorder_quantity"`
+
+	// TaxClass определяет ставку налога товара и его вариантов; пустой
+	// класс означает стандартную ставку.
+	TaxClass string `json:"tax_class" db:"tax_class"`
+
+	// Weight - вес в граммах, Length, Width и Height - габариты упаковки в
+	// миллиметрах; используются при расчете доставки, 0 - не указано.
+	Weight int `json:"weight" db:"weight"`
+	Length int `json:"length" db:"length"`
+	Width  int `json:"width" db:"width"`
+	Height int `json:"height" db:"height"`
+
+	Options  ProductOptions   `json:"options,omitempty" db:"options"`
+	Variants []ProductVariant `json:"variants,omitempty"`
+
+	// CategoryIDs и Tags хранятся в отдельных таблицах; nil при обновлении
+	// означает "не менять".
+	CategoryIDs []int    `json:"category_ids,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+
+	// Stock заполняется только при получении товара по ID.
+	Stock *ProductStock `json:"stock,omitempty"`
 }
 
 func (p *Product) Validate() error {
@@ -22,11 +52,39 @@ func (p *Product) Validate() error {
 	if len(p.Name) > 100 {
 		return errors.New("product name is too long")
 	}
+	if len(p.SKU) > 64 {
+		return errors.New("product sku is too long")
+	}
 	if p.Price <= 0 {
 		return errors.New("product price must be positive")
 	}
 	if p.Quantity < 0 {
 		return errors.New("product quantity cannot be negative")
 	}
+	if p.ReorderPoint < 0 {
+		return errors.New("product reorder point cannot be negative")
+	}
+	if p.ReorderQuantity < 0 {
+		return errors.New("product reorder quantity cannot be negative")
+	}
+	if len(p.TaxClass) > 32 || strings.ContainsAny(p.TaxClass, " ,;") {
+		return errors.New("invalid product tax class")
+	}
+	if p.Weight < 0 || p.Length < 0 || p.Width < 0 || p.Height < 0 {
+		return errors.New("product weight and dimensions cannot be negative")
+	}
+	if err := p.Options.Validate(); err != nil {
+		return err
+	}
 	return nil
 }
+
+// IsLowStock сообщает, опустился ли остаток до порога дозаказа.
+func (p *Product) IsLowStock() bool {
+	return p.ReorderPoint > 0 && p.Quantity <= p.ReorderPoint
+}
+
+// Volume возвращает объем упаковки в кубических миллиметрах.
+func (p *Product) Volume() int {
+	return p.Length * p.Width * p.Height
+}
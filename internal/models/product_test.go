@@ -221,3 +221,50 @@ func TestProduct_EdgeCases(t *testing.T) {
 		})
 	}
 }
+
+func TestProduct_Validate_NegativeReorderPoint(t *testing.T) {
+	// Arrange
+	product := &Product{Name: "Mug", Price: 5, Quantity: 10, ReorderPoint: -1}
+
+	// Act
+	err := product.Validate()
+
+	// Assert
+	assert.Error(t, err)
+	assert.Equal(t, "product reorder point cannot be negative", err.Error())
+}
+
+func TestProduct_IsLowStock(t *testing.T) {
+	tests := []struct {
+		name         string
+		quantity     int
+		reorderPoint int
+		want         bool
+	}{
+		{"control disabled", 0, 0, false},
+		{"above threshold", 6, 5, false},
+		{"at threshold", 5, 5, true},
+		{"below threshold", 1, 5, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			product := &Product{Quantity: tt.quantity, ReorderPoint: tt.reorderPoint}
+			assert.Equal(t, tt.want, product.IsLowStock())
+		})
+	}
+}
+
+func TestProduct_Validate_TaxClass(t *testing.T) {
+	product := &Product{Name: "Mug", Price: 5, Quantity: 10, TaxClass: "reduced"}
+	assert.NoError(t, product.Validate())
+
+	product.TaxClass = "food,reduced"
+	assert.EqualError(t, product.Validate(), "invalid product tax class")
+}
+
+func TestProduct_Validate_NegativeDimensions(t *testing.T) {
+	product := &Product{Name: "Mug", Price: 5, Quantity: 10, Weight: 300, Length: -1}
+
+	assert.EqualError(t, product.Validate(), "product weight and dimensions cannot be negative")
+}
@@ -0,0 +1,338 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Типы промоакций. Value означает процент скидки для PromotionPercentage,
+// сумму скидки для PromotionFixed и процент скидки на бесплатные единицы для
+// PromotionBuyXGetY (100 - единицы бесплатны).
+const (
+	PromotionPercentage   = "percentage"
+	PromotionFixed        = "fixed"
+	PromotionBuyXGetY     = "buy_x_get_y"
+	PromotionFreeShipping = "free_shipping"
+)
+
+// Promotion - промоакция. Акция без Code применяется автоматически, акция с
+// кодом - только когда покупатель указал код. UsageLimit и PerCustomerLimit
+// ограничивают число заказов со скидкой; 0 снимает ограничение.
+type Promotion struct {
+	ID               int        `json:"id" db:"id"`
+	Code             string     `json:"code,omitempty" db:"code"`
+	Name             string     `json:"name" db:"name"`
+	Type             string     `json:"type" db:"type"`
+	Value            int        `json:"value" db:"value"`
+	BuyQuantity      int        `json:"buy_quantity,omitempty" db:"buy_quantity"`
+	GetQuantity      int        `json:"get_quantity,omitempty" db:"get_quantity"`
+	ProductIDs       IDList     `json:"product_ids,omitempty" db:"product_ids"`
+	MinSubtotal      int        `json:"min_subtotal" db:"min_subtotal"`
+	UsageLimit       int        `json:"usage_limit" db:"usage_limit"`
+	PerCustomerLimit int        `json:"per_customer_limit" db:"per_customer_limit"`
+	UsageCount       int        `json:"usage_count" db:"usage_count"`
+	StartsAt         *time.Time `json:"starts_at,omitempty" db:"starts_at"`
+	EndsAt           *time.Time `json:"ends_at,omitempty" db:"ends_at"`
+	Active           bool       `json:"active" db:"active"`
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// IDList хранится в PostgreSQL как JSONB.
+type IDList []int
+
+func (l IDList) Value() (driver.Value, error) {
+	if l == nil {
+		l = IDList{}
+	}
+	data, err := json.Marshal([]int(l))
+	return string(data), err
+}
+
+func (l *IDList) Scan(src interface{}) error {
+	return scanJSON(src, (*[]int)(l))
+}
+
+// PromotionRedemption - использование промоакции заказом.
+type PromotionRedemption struct {
+	ID          int       `json:"id" db:"id"`
+	PromotionID int       `json:"promotion_id" db:"promotion_id"`
+	OrderID     int       `json:"order_id" db:"order_id"`
+	CustomerID  int       `json:"customer_id" db:"customer_id"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// Normalize приводит код к верхнему регистру: коды сравниваются без учета
+// регистра.
+func (p *Promotion) Normalize() {
+	p.Code = strings.ToUpper(strings.TrimSpace(p.Code))
+	p.Name = strings.TrimSpace(p.Name)
+}
+
+func (p *Promotion) Validate() error {
+	if p.Name == "" {
+		return errors.New("promotion name is required")
+	}
+	if len(p.Name) > 255 {
+		return errors.New("promotion name is too long")
+	}
+	if len(p.Code) > 64 {
+		return errors.New("promotion code is too long")
+	}
+	if strings.ContainsAny(p.Code, " \t\n") {
+		return errors.New("promotion code cannot contain spaces")
+	}
+
+	switch p.Type {
+	case PromotionPercentage:
+		if p.Value < 1 || p.Value > 100 {
+			return errors.New("percentage must be between 1 and 100")
+		}
+	case PromotionFixed:
+		if p.Value <= 0 {
+			return errors.New("fixed discount must be positive")
+		}
+	case PromotionBuyXGetY:
+		if p.BuyQuantity <= 0 || p.GetQuantity <= 0 {
+			return errors.New("buy and get quantities must be positive")
+		}
+		if p.Value < 1 || p.Value > 100 {
+			return errors.New("percentage must be between 1 and 100")
+		}
+	case PromotionFreeShipping:
+		if p.Value != 0 {
+			return errors.New("free shipping promotion has no value")
+		}
+	default:
+		return fmt.Errorf("unknown promotion type %q", p.Type)
+	}
+
+	for _, id := range p.ProductIDs {
+		if id <= 0 {
+			return errors.New("product IDs must be positive")
+		}
+	}
+	if p.MinSubtotal < 0 || p.UsageLimit < 0 || p.PerCustomerLimit < 0 {
+		return errors.New("minimum subtotal and usage limits cannot be negative")
+	}
+	if p.StartsAt != nil && p.EndsAt != nil && !p.EndsAt.After(*p.StartsAt) {
+		return errors.New("promotion must end after it starts")
+	}
+	return nil
+}
+
+// CheckAvailable проверяет, что акция включена, действует в момент now и
+// не исчерпала общий лимит использований.
+func (p *Promotion) CheckAvailable(now time.Time) error {
+	switch {
+	case !p.Active:
+		return fmt.Errorf("promotion %q is not active", p.label())
+	case p.StartsAt != nil && now.Before(*p.StartsAt):
+		return fmt.Errorf("promotion %q has not started yet", p.label())
+	case p.EndsAt != nil && !now.Before(*p.EndsAt):
+		return fmt.Errorf("promotion %q has expired", p.label())
+	case p.UsageLimit > 0 && p.UsageCount >= p.UsageLimit:
+		return fmt.Errorf("promotion %q usage limit reached", p.label())
+	}
+	return nil
+}
+
+func (p *Promotion) label() string {
+	if p.Code != "" {
+		return p.Code
+	}
+	return p.Name
+}
+
+func (p *Promotion) appliesTo(productID int) bool {
+	if len(p.ProductIDs) == 0 {
+		return true
+	}
+	for _, id := range p.ProductIDs {
+		if id == productID {
+			return true
+		}
+	}
+	return false
+}
+
+// OrderAdjustment - скидка, которую дала промоакция. Скидка на позицию
+// (line-level) указывает товар и вариант позиции, скидка на заказ - нет.
+// Amount - положительная сумма скидки.
+type OrderAdjustment struct {
+	ID          int    `json:"id" db:"id"`
+	OrderID     int    `json:"order_id" db:"order_id"`
+	PromotionID int    `json:"promotion_id" db:"promotion_id"`
+	Code        string `json:"code,omitempty" db:"code"`
+	Type        string `json:"type" db:"type"`
+	ProductID   int    `json:"product_id,omitempty" db:"product_id"`
+	VariantID   int    `json:"variant_id,omitempty" db:"variant_id"`
+	Amount      int    `json:"amount" db:"amount"`
+	Description string `json:"description" db:"description"`
+}
+
+// PricingLine - позиция заказа или корзины для расчета скидок.
+type PricingLine struct {
+	ProductID int
+	VariantID int
+	Quantity  int
+	UnitPrice int
+}
+
+// Pricing - итог применения промоакций.
+type Pricing struct {
+	Subtotal     int               `json:"subtotal"`
+	Discount     int               `json:"discount"`
+	Total        int               `json:"total"`
+	FreeShipping bool              `json:"free_shipping"`
+	Adjustments  []OrderAdjustment `json:"adjustments"`
+	// Rejected - причины, по которым указанный код не дал скидки.
+	Rejected []string `json:"rejected,omitempty"`
+	// LineTotals - суммы позиций после всех скидок в порядке позиций, база
+	// для расчета налога.
+	LineTotals []int `json:"-"`
+}
+
+// promotionOrder задает порядок применения: сначала скидки на позиции,
+// затем на заказ, чтобы процент на заказ считался от уже сниженных цен.
+var promotionOrder = map[string]int{
+	PromotionBuyXGetY:     0,
+	PromotionPercentage:   1,
+	PromotionFixed:        2,
+	PromotionFreeShipping: 3,
+}
+
+// ApplyPromotions рассчитывает скидки по позициям. Доступность акций
+// (сроки, лимиты) проверяет вызывающий; здесь проверяются только условия
+// самой корзины. Акция, не выполнившая условия, пропускается, а причина
+// попадает в Rejected, если у акции есть код. Скидки не опускают сумму
+// позиции и заказа ниже нуля.
+func ApplyPromotions(lines []PricingLine, promotions []*Promotion) Pricing {
+	result := Pricing{Adjustments: []OrderAdjustment{}}
+	remaining := make([]int, len(lines))
+	for i, l := range lines {
+		remaining[i] = l.UnitPrice * l.Quantity
+		result.Subtotal += remaining[i]
+	}
+
+	ordered := append([]*Promotion{}, promotions...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return promotionOrder[ordered[i].Type] < promotionOrder[ordered[j].Type]
+	})
+
+	for _, p := range ordered {
+		if result.Subtotal < p.MinSubtotal {
+			if p.Code != "" {
+				result.Rejected = append(result.Rejected,
+					fmt.Sprintf("promotion %q requires a subtotal of at least %d", p.Code, p.MinSubtotal))
+			}
+			continue
+		}
+
+		adjustments := p.apply(lines, remaining)
+		if len(adjustments) == 0 && p.Type != PromotionFreeShipping {
+			if p.Code != "" {
+				result.Rejected = append(result.Rejected, fmt.Sprintf("promotion %q does not apply to these items", p.Code))
+			}
+			continue
+		}
+		if p.Type == PromotionFreeShipping {
+			result.FreeShipping = true
+			adjustments = []OrderAdjustment{p.adjustment(0, 0, 0)}
+		}
+		for _, a := range adjustments {
+			result.Discount += a.Amount
+		}
+		result.Adjustments = append(result.Adjustments, adjustments...)
+	}
+
+	result.Total = result.Subtotal - result.Discount
+	result.LineTotals = remaining
+	return result
+}
+
+// apply считает скидки одной акции и уменьшает remaining - остаток суммы
+// каждой позиции после уже примененных скидок.
+func (p *Promotion) apply(lines []PricingLine, remaining []int) []OrderAdjustment {
+	var adjustments []OrderAdjustment
+	eligible := 0
+	for i, l := range lines {
+		if p.appliesTo(l.ProductID) {
+			eligible += remaining[i]
+		}
+	}
+
+	switch p.Type {
+	case PromotionBuyXGetY:
+		group := p.BuyQuantity + p.GetQuantity
+		for i, l := range lines {
+			if !p.appliesTo(l.ProductID) {
+				continue
+			}
+			free := l.Quantity / group * p.GetQuantity
+			amount := min(free*l.UnitPrice*p.Value/100, remaining[i])
+			if amount > 0 {
+				remaining[i] -= amount
+				adjustments = append(adjustments, p.adjustment(l.ProductID, l.VariantID, amount))
+			}
+		}
+
+	case PromotionPercentage:
+		if len(p.ProductIDs) == 0 {
+			if amount := eligible * p.Value / 100; amount > 0 {
+				spread(remaining, lines, p, amount)
+				adjustments = append(adjustments, p.adjustment(0, 0, amount))
+			}
+			break
+		}
+		for i, l := range lines {
+			if !p.appliesTo(l.ProductID) {
+				continue
+			}
+			if amount := remaining[i] * p.Value / 100; amount > 0 {
+				remaining[i] -= amount
+				adjustments = append(adjustments, p.adjustment(l.ProductID, l.VariantID, amount))
+			}
+		}
+
+	case PromotionFixed:
+		if amount := min(p.Value, eligible); amount > 0 {
+			spread(remaining, lines, p, amount)
+			adjustments = append(adjustments, p.adjustment(0, 0, amount))
+		}
+	}
+	return adjustments
+}
+
+// spread списывает скидку на заказ с остатков подходящих позиций по
+// порядку, чтобы следующие акции видели уже сниженные суммы.
+func spread(remaining []int, lines []PricingLine, p *Promotion, amount int) {
+	for i, l := range lines {
+		if amount == 0 {
+			return
+		}
+		if !p.appliesTo(l.ProductID) {
+			continue
+		}
+		take := min(amount, remaining[i])
+		remaining[i] -= take
+		amount -= take
+	}
+}
+
+func (p *Promotion) adjustment(productID, variantID, amount int) OrderAdjustment {
+	return OrderAdjustment{
+		PromotionID: p.ID,
+		Code:        p.Code,
+		Type:        p.Type,
+		ProductID:   productID,
+		VariantID:   variantID,
+		Amount:      amount,
+		Description: p.Name,
+	}
+}
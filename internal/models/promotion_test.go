@@ -0,0 +1,178 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPromotion_Validate(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+
+	tests := []struct {
+		name      string
+		promotion Promotion
+		err       string
+	}{
+		{name: "percentage", promotion: Promotion{Name: "Sale", Type: PromotionPercentage, Value: 10}},
+		{name: "free shipping", promotion: Promotion{Name: "Ship", Code: "SHIP", Type: PromotionFreeShipping}},
+		{name: "missing name", promotion: Promotion{Type: PromotionFixed, Value: 100}, err: "promotion name is required"},
+		{
+			name:      "percentage over 100",
+			promotion: Promotion{Name: "Sale", Type: PromotionPercentage, Value: 150},
+			err:       "percentage must be between 1 and 100",
+		},
+		{
+			name:      "zero fixed",
+			promotion: Promotion{Name: "Sale", Type: PromotionFixed},
+			err:       "fixed discount must be positive",
+		},
+		{
+			name:      "buy x get y without quantities",
+			promotion: Promotion{Name: "B2G1", Type: PromotionBuyXGetY, Value: 100},
+			err:       "buy and get quantities must be positive",
+		},
+		{
+			name:      "code with spaces",
+			promotion: Promotion{Name: "Sale", Code: "SAVE 10", Type: PromotionPercentage, Value: 10},
+			err:       "promotion code cannot contain spaces",
+		},
+		{
+			name:      "unknown type",
+			promotion: Promotion{Name: "Sale", Type: "bogo"},
+			err:       `unknown promotion type "bogo"`,
+		},
+		{
+			name:      "ends before start",
+			promotion: Promotion{Name: "Sale", Type: PromotionFixed, Value: 1, StartsAt: &end, EndsAt: &start},
+			err:       "promotion must end after it starts",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.promotion.Validate()
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestPromotion_CheckAvailable(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	tests := []struct {
+		name      string
+		promotion Promotion
+		err       string
+	}{
+		{name: "available", promotion: Promotion{Code: "A", Active: true, StartsAt: &past, EndsAt: &future}},
+		{name: "inactive", promotion: Promotion{Code: "A"}, err: `promotion "A" is not active`},
+		{name: "not started", promotion: Promotion{Code: "A", Active: true, StartsAt: &future}, err: `promotion "A" has not started yet`},
+		{name: "expired", promotion: Promotion{Code: "A", Active: true, EndsAt: &now}, err: `promotion "A" has expired`},
+		{
+			name:      "usage limit",
+			promotion: Promotion{Name: "Auto", Active: true, UsageLimit: 5, UsageCount: 5},
+			err:       `promotion "Auto" usage limit reached`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.promotion.CheckAvailable(now)
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestApplyPromotions(t *testing.T) {
+	lines := []PricingLine{
+		{ProductID: 1, Quantity: 2, UnitPrice: 100},
+		{ProductID: 2, VariantID: 7, Quantity: 3, UnitPrice: 50},
+	}
+
+	tests := []struct {
+		name         string
+		promotions   []*Promotion
+		discount     int
+		adjustments  int
+		freeShipping bool
+		rejected     []string
+	}{
+		{name: "no promotions", discount: 0},
+		{
+			name:        "order percentage",
+			promotions:  []*Promotion{{ID: 1, Type: PromotionPercentage, Value: 10}},
+			discount:    35,
+			adjustments: 1,
+		},
+		{
+			name:        "line percentage",
+			promotions:  []*Promotion{{ID: 1, Type: PromotionPercentage, Value: 10, ProductIDs: IDList{1}}},
+			discount:    20,
+			adjustments: 1,
+		},
+		{
+			name:        "fixed capped by subtotal",
+			promotions:  []*Promotion{{ID: 1, Type: PromotionFixed, Value: 1000}},
+			discount:    350,
+			adjustments: 1,
+		},
+		{
+			name:        "buy two get one",
+			promotions:  []*Promotion{{ID: 1, Type: PromotionBuyXGetY, BuyQuantity: 2, GetQuantity: 1, Value: 100, ProductIDs: IDList{2}}},
+			discount:    50,
+			adjustments: 1,
+		},
+		{
+			// Процент считается от суммы после скидки на позицию: (350-50)*10%.
+			name: "line before order",
+			promotions: []*Promotion{
+				{ID: 1, Type: PromotionPercentage, Value: 10},
+				{ID: 2, Type: PromotionBuyXGetY, BuyQuantity: 2, GetQuantity: 1, Value: 100},
+			},
+			discount:    80,
+			adjustments: 2,
+		},
+		{
+			name:         "free shipping",
+			promotions:   []*Promotion{{ID: 1, Code: "SHIP", Type: PromotionFreeShipping}},
+			adjustments:  1,
+			freeShipping: true,
+		},
+		{
+			name:       "minimum subtotal",
+			promotions: []*Promotion{{ID: 1, Code: "BIG", Type: PromotionFixed, Value: 50, MinSubtotal: 500}},
+			rejected:   []string{`promotion "BIG" requires a subtotal of at least 500`},
+		},
+		{
+			name:       "no matching items",
+			promotions: []*Promotion{{ID: 1, Code: "OTHER", Type: PromotionPercentage, Value: 10, ProductIDs: IDList{9}}},
+			rejected:   []string{`promotion "OTHER" does not apply to these items`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pricing := ApplyPromotions(lines, tt.promotions)
+
+			assert.Equal(t, 350, pricing.Subtotal)
+			assert.Equal(t, tt.discount, pricing.Discount)
+			assert.Equal(t, 350-tt.discount, pricing.Total)
+			assert.Len(t, pricing.Adjustments, tt.adjustments)
+			assert.Equal(t, tt.freeShipping, pricing.FreeShipping)
+			assert.Equal(t, tt.rejected, pricing.Rejected)
+		})
+	}
+}
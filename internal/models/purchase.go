@@ -0,0 +1,168 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// Статусы заказа поставщику. Черновик редактируется и отправляется
+// поставщику (PurchaseOrderSent), после чего товар принимается одной или
+// несколькими приемками. Отменить можно заказ, принятый не полностью.
+const (
+	PurchaseOrderDraft             = "draft"
+	PurchaseOrderSent              = "sent"
+	PurchaseOrderPartiallyReceived = "partially_received"
+	PurchaseOrderReceived          = "received"
+	PurchaseOrderCancelled         = "cancelled"
+)
+
+type Supplier struct {
+	ID        int       `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"`
+	Email     string    `json:"email,omitempty" db:"email"`
+	Phone     string    `json:"phone,omitempty" db:"phone"`
+	Note      string    `json:"note,omitempty" db:"note"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+func (s *Supplier) Validate() error {
+	s.Name = strings.TrimSpace(s.Name)
+	s.Email = strings.TrimSpace(s.Email)
+	if s.Name == "" {
+		return errors.New("supplier name is required")
+	}
+	if len(s.Name) > 255 {
+		return errors.New("supplier name is too long")
+	}
+	if s.Email != "" {
+		if addr, err := mail.ParseAddress(s.Email); err != nil || addr.Address != s.Email {
+			return errors.New("invalid supplier email")
+		}
+	}
+	if len(s.Phone) > 32 {
+		return errors.New("supplier phone is too long")
+	}
+	if len(s.Note) > 500 {
+		return errors.New("supplier note is too long")
+	}
+	return nil
+}
+
+// PurchaseOrder - заказ поставщику. Товар приходует на склад WarehouseID;
+// UnitCost позиций - закупочная цена за единицу, по ней пересчитывается
+// себестоимость варианта при приемке.
+type PurchaseOrder struct {
+	ID          int                 `json:"id" db:"id"`
+	SupplierID  int                 `json:"supplier_id" db:"supplier_id"`
+	WarehouseID int                 `json:"warehouse_id" db:"warehouse_id"`
+	Status      string              `json:"status" db:"status"`
+	Note        string              `json:"note,omitempty" db:"note"`
+	Items       []PurchaseOrderItem `json:"items"`
+	Total       int                 `json:"total" db:"total"`
+	SentAt      *time.Time          `json:"sent_at,omitempty" db:"sent_at"`
+	ReceivedAt  *time.Time          `json:"received_at,omitempty" db:"received_at"`
+	CreatedAt   time.Time           `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time           `json:"updated_at" db:"updated_at"`
+}
+
+type PurchaseOrderItem struct {
+	ID               int `json:"id" db:"id"`
+	PurchaseOrderID  int `json:"purchase_order_id" db:"purchase_order_id"`
+	ProductID        int `json:"product_id" db:"product_id"`
+	VariantID        int `json:"variant_id" db:"variant_id"`
+	Quantity         int `json:"quantity" db:"quantity"`
+	ReceivedQuantity int `json:"received_quantity" db:"received_quantity"`
+	UnitCost         int `json:"unit_cost" db:"unit_cost"`
+}
+
+// Outstanding возвращает количество, которое еще ожидается от поставщика.
+func (i *PurchaseOrderItem) Outstanding() int {
+	return max(i.Quantity-i.ReceivedQuantity, 0)
+}
+
+func (p *PurchaseOrder) Validate() error {
+	if p.SupplierID <= 0 {
+		return errors.New("supplier ID is required")
+	}
+	if len(p.Items) == 0 {
+		return errors.New("purchase order must contain at least one item")
+	}
+	if len(p.Note) > 500 {
+		return errors.New("purchase order note is too long")
+	}
+
+	type line struct{ productID, variantID int }
+	seen := make(map[line]bool, len(p.Items))
+	for _, item := range p.Items {
+		if item.ProductID <= 0 && item.VariantID <= 0 {
+			return errors.New("purchase order item product ID is required")
+		}
+		if item.Quantity <= 0 {
+			return errors.New("purchase order item quantity must be positive")
+		}
+		if item.UnitCost < 0 {
+			return errors.New("purchase order item cost cannot be negative")
+		}
+		l := line{item.ProductID, item.VariantID}
+		if seen[l] {
+			return fmt.Errorf("duplicate purchase order item for product %d", item.ProductID)
+		}
+		seen[l] = true
+	}
+	return nil
+}
+
+// CalculateTotal считает стоимость заказа по заказанному количеству.
+func (p *PurchaseOrder) CalculateTotal() {
+	total := 0
+	for _, item := range p.Items {
+		total += item.Quantity * item.UnitCost
+	}
+	p.Total = total
+}
+
+// CanTransition проверяет допустимость перехода статуса заказа поставщику.
+// Повторная приемка частично принятого заказа допустима.
+func (p *PurchaseOrder) CanTransition(status string) bool {
+	switch status {
+	case PurchaseOrderSent:
+		return p.Status == PurchaseOrderDraft
+	case PurchaseOrderPartiallyReceived, PurchaseOrderReceived:
+		return p.Status == PurchaseOrderSent || p.Status == PurchaseOrderPartiallyReceived
+	case PurchaseOrderCancelled:
+		return p.Status == PurchaseOrderDraft || p.Status == PurchaseOrderSent || p.Status == PurchaseOrderPartiallyReceived
+	default:
+		return false
+	}
+}
+
+// ReceiptStatus возвращает статус заказа по принятым количествам.
+func (p *PurchaseOrder) ReceiptStatus() string {
+	for _, item := range p.Items {
+		if item.Outstanding() > 0 {
+			return PurchaseOrderPartiallyReceived
+		}
+	}
+	return PurchaseOrderReceived
+}
+
+// GoodsReceiptItem - фактически принятое количество по позиции заказа.
+type GoodsReceiptItem struct {
+	ItemID   int `json:"item_id"`
+	Quantity int `json:"quantity"`
+}
+
+// AverageCost пересчитывает себестоимость единицы по средневзвешенной:
+// onHand единиц по cost и received единиц по unitCost. Отрицательный
+// остаток не учитывается.
+func AverageCost(onHand, cost, received, unitCost int) int {
+	onHand = max(onHand, 0)
+	if onHand+received <= 0 {
+		return cost
+	}
+	return (onHand*cost + received*unitCost + (onHand+received)/2) / (onHand + received)
+}
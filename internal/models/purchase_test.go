@@ -0,0 +1,76 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSupplier_Validate(t *testing.T) {
+	assert.NoError(t, (&Supplier{Name: " Acme ", Email: "orders@acme.test"}).Validate())
+	assert.EqualError(t, (&Supplier{Name: "  "}).Validate(), "supplier name is required")
+	assert.EqualError(t, (&Supplier{Name: "Acme", Email: "Acme <orders@acme.test>"}).Validate(), "invalid supplier email")
+}
+
+func TestPurchaseOrder_Validate(t *testing.T) {
+	item := PurchaseOrderItem{ProductID: 1, Quantity: 5, UnitCost: 300}
+
+	tests := []struct {
+		name  string
+		order PurchaseOrder
+		err   string
+	}{
+		{name: "valid", order: PurchaseOrder{SupplierID: 1, Items: []PurchaseOrderItem{item}}},
+		{name: "no supplier", order: PurchaseOrder{Items: []PurchaseOrderItem{item}}, err: "supplier ID is required"},
+		{name: "no items", order: PurchaseOrder{SupplierID: 1}, err: "purchase order must contain at least one item"},
+		{name: "duplicate item", order: PurchaseOrder{SupplierID: 1, Items: []PurchaseOrderItem{item, item}},
+			err: "duplicate purchase order item for product 1"},
+		{name: "zero quantity", order: PurchaseOrder{SupplierID: 1, Items: []PurchaseOrderItem{{ProductID: 1}}},
+			err: "purchase order item quantity must be positive"},
+		{name: "negative cost", order: PurchaseOrder{SupplierID: 1, Items: []PurchaseOrderItem{{ProductID: 1, Quantity: 1, UnitCost: -1}}},
+			err: "purchase order item cost cannot be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.order.Validate()
+			if tt.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestPurchaseOrder_CanTransition(t *testing.T) {
+	draft := PurchaseOrder{Status: PurchaseOrderDraft}
+	sent := PurchaseOrder{Status: PurchaseOrderSent}
+	partial := PurchaseOrder{Status: PurchaseOrderPartiallyReceived}
+	received := PurchaseOrder{Status: PurchaseOrderReceived}
+
+	assert.True(t, draft.CanTransition(PurchaseOrderSent))
+	assert.False(t, draft.CanTransition(PurchaseOrderReceived))
+	assert.True(t, sent.CanTransition(PurchaseOrderPartiallyReceived))
+	assert.True(t, partial.CanTransition(PurchaseOrderReceived))
+	assert.True(t, partial.CanTransition(PurchaseOrderCancelled))
+	assert.False(t, received.CanTransition(PurchaseOrderCancelled))
+}
+
+func TestPurchaseOrder_ReceiptStatus(t *testing.T) {
+	order := PurchaseOrder{Items: []PurchaseOrderItem{
+		{Quantity: 5, ReceivedQuantity: 5},
+		{Quantity: 3, ReceivedQuantity: 1},
+	}}
+	assert.Equal(t, PurchaseOrderPartiallyReceived, order.ReceiptStatus())
+
+	order.Items[1].ReceivedQuantity = 3
+	assert.Equal(t, PurchaseOrderReceived, order.ReceiptStatus())
+}
+
+func TestAverageCost(t *testing.T) {
+	assert.Equal(t, 300, AverageCost(0, 0, 10, 300))
+	assert.Equal(t, 250, AverageCost(10, 200, 10, 300))
+	assert.Equal(t, 300, AverageCost(-4, 200, 10, 300)) // отрицательный остаток не учитывается
+	assert.Equal(t, 200, AverageCost(0, 200, 0, 300))
+}
@@ -0,0 +1,233 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Интервалы группировки отчета о выручке. Неделя начинается с понедельника,
+// как date_trunc('week') в PostgreSQL.
+const (
+	ReportDay   = "day"
+	ReportWeek  = "week"
+	ReportMonth = "month"
+)
+
+// Сортировка отчета о товарах.
+const (
+	ReportByUnits   = "units"
+	ReportByRevenue = "revenue"
+)
+
+// ReportFilter - период и часовой пояс отчета. From включительно, To -
+// исключительно; нулевые границы не ограничивают период. Периоды группировки
+// и даты без времени отсчитываются в Location.
+type ReportFilter struct {
+	From     time.Time
+	To       time.Time
+	Location *time.Location
+	Interval string
+	SortBy   string
+	Limit    int
+}
+
+// ParseReportFilter разбирает границы периода в формате RFC3339 или
+// YYYY-MM-DD и часовой пояс IANA (по умолчанию UTC). Дата в to означает конец
+// дня, как и в ParseOrderFilter.
+func ParseReportFilter(from, to, tz string) (ReportFilter, error) {
+	filter := ReportFilter{Location: time.UTC}
+	if tz != "" {
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			return filter, fmt.Errorf("invalid tz: unknown time zone %q", tz)
+		}
+		filter.Location = loc
+	}
+
+	var err error
+	if filter.From, err = parseReportBound(from, false, filter.Location); err != nil {
+		return filter, fmt.Errorf("invalid from: %w", err)
+	}
+	if filter.To, err = parseReportBound(to, true, filter.Location); err != nil {
+		return filter, fmt.Errorf("invalid to: %w", err)
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return filter, errors.New("invalid period: from must be before to")
+	}
+	return filter, nil
+}
+
+func parseReportBound(s string, upper bool, loc *time.Location) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", s, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected RFC3339 or YYYY-MM-DD, got %q", s)
+	}
+	if upper {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+// Validate проверяет интервал и сортировку, подставляя значения по
+// умолчанию: группировку по дням, сортировку по количеству и 10 товаров.
+func (f *ReportFilter) Validate() error {
+	if f.Location == nil {
+		f.Location = time.UTC
+	}
+	f.Interval = strings.ToLower(strings.TrimSpace(f.Interval))
+	switch f.Interval {
+	case "":
+		f.Interval = ReportDay
+	case ReportDay, ReportWeek, ReportMonth:
+	default:
+		return fmt.Errorf("invalid interval %q: expected day, week or month", f.Interval)
+	}
+	f.SortBy = strings.ToLower(strings.TrimSpace(f.SortBy))
+	switch f.SortBy {
+	case "":
+		f.SortBy = ReportByUnits
+	case ReportByUnits, ReportByRevenue:
+	default:
+		return fmt.Errorf("invalid sort %q: expected units or revenue", f.SortBy)
+	}
+	if f.Limit == 0 {
+		f.Limit = 10
+	}
+	if f.Limit < 0 || f.Limit > 100 {
+		return errors.New("invalid limit: expected 1 to 100")
+	}
+	return nil
+}
+
+// Match сообщает, попадает ли момент t в период отчета.
+func (f ReportFilter) Match(t time.Time) bool {
+	if !f.From.IsZero() && t.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !t.Before(f.To) {
+		return false
+	}
+	return true
+}
+
+// Period возвращает начало дня, недели или месяца, в который попадает t, в
+// часовом поясе отчета.
+func (f ReportFilter) Period(t time.Time) time.Time {
+	t = t.In(f.Location)
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, f.Location)
+	switch f.Interval {
+	case ReportWeek:
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case ReportMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, f.Location)
+	}
+	return day
+}
+
+// Key однозначно описывает фильтр для кэша отчетов.
+func (f ReportFilter) Key() string {
+	bound := func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.UTC().Format(time.RFC3339Nano)
+	}
+	return strings.Join([]string{
+		bound(f.From), bound(f.To), f.Location.String(), f.Interval, f.SortBy, fmt.Sprint(f.Limit),
+	}, "|")
+}
+
+// CountsAsRevenue сообщает, входит ли заказ в выручку: учитываются все
+// заказы, кроме отмененных.
+func (o *Order) CountsAsRevenue() bool {
+	return o.Status != OrderStatusCancelled
+}
+
+// SalesPoint - выручка и число заказов за период, начинающийся в Period.
+type SalesPoint struct {
+	Period  time.Time `json:"period" db:"period"`
+	Orders  int       `json:"orders" db:"orders"`
+	Revenue int       `json:"revenue" db:"revenue"`
+}
+
+// ProductSales - продажи товара за период. Revenue - сумма позиций по ценам
+// заказа до скидок, Cost - себестоимость проданного по снимку в позициях.
+type ProductSales struct {
+	ProductID int    `json:"product_id" db:"product_id"`
+	Name      string `json:"name" db:"name"`
+	Units     int    `json:"units" db:"units"`
+	Revenue   int    `json:"revenue" db:"revenue"`
+	Cost      int    `json:"cost" db:"cost"`
+	Margin    int    `json:"margin" db:"margin"`
+}
+
+// OrderValue - средний чек за период, округленный до целого.
+type OrderValue struct {
+	Orders  int `json:"orders" db:"orders"`
+	Revenue int `json:"revenue" db:"revenue"`
+	Average int `json:"average" db:"average"`
+}
+
+// StatusCount - число заказов периода в статусе и их сумма.
+type StatusCount struct {
+	Status  string `json:"status" db:"status"`
+	Orders  int    `json:"orders" db:"orders"`
+	Revenue int    `json:"revenue" db:"revenue"`
+}
+
+// StockValuation - стоимость остатка варианта по журналу движения на конец
+// периода и текущей себестоимости варианта.
+type StockValuation struct {
+	ProductID int    `json:"product_id" db:"product_id"`
+	VariantID int    `json:"variant_id" db:"variant_id"`
+	SKU       string `json:"sku" db:"sku"`
+	Name      string `json:"name" db:"name"`
+	Quantity  int    `json:"quantity" db:"quantity"`
+	UnitCost  int    `json:"unit_cost" db:"unit_cost"`
+	Value     int    `json:"value" db:"value"`
+}
+
+// orderFunnel - порядок статусов заказа в воронке.
+var orderFunnel = []string{
+	OrderStatusPending,
+	OrderStatusProcessing,
+	OrderStatusPartiallyShipped,
+	OrderStatusShipped,
+	OrderStatusDelivered,
+	OrderStatusCancelled,
+}
+
+// OrderFunnel упорядочивает счетчики по этапам воронки, добавляя этапы без
+// заказов с нулями. Неизвестные статусы идут в конце по алфавиту.
+func OrderFunnel(counts []StatusCount) []StatusCount {
+	byStatus := make(map[string]StatusCount, len(counts))
+	for _, c := range counts {
+		byStatus[c.Status] = c
+	}
+
+	funnel := make([]StatusCount, 0, len(orderFunnel)+len(counts))
+	for _, status := range orderFunnel {
+		c, ok := byStatus[status]
+		if !ok {
+			c = StatusCount{Status: status}
+		}
+		funnel = append(funnel, c)
+		delete(byStatus, status)
+	}
+
+	var other []StatusCount
+	for _, c := range byStatus {
+		other = append(other, c)
+	}
+	sort.Slice(other, func(i, j int) bool { return other[i].Status < other[j].Status })
+	return append(funnel, other...)
+}
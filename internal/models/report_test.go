@@ -0,0 +1,102 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseReportFilter(t *testing.T) {
+	// Act
+	filter, err := ParseReportFilter("2024-03-01", "2024-03-31", "Europe/Moscow")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "Europe/Moscow", filter.Location.String())
+	assert.True(t, filter.From.Equal(time.Date(2024, 2, 29, 21, 0, 0, 0, time.UTC)))
+	assert.True(t, filter.To.Equal(time.Date(2024, 3, 31, 21, 0, 0, 0, time.UTC))) // конец дня 31 марта по Москве
+
+	filter, err = ParseReportFilter("2024-03-01T12:00:00Z", "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, time.UTC, filter.Location)
+	assert.True(t, filter.From.Equal(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)))
+	assert.True(t, filter.To.IsZero())
+
+	_, err = ParseReportFilter("", "", "Mars/Olympus")
+	assert.EqualError(t, err, `invalid tz: unknown time zone "Mars/Olympus"`)
+
+	_, err = ParseReportFilter("2024-03-31", "2024-03-01", "")
+	assert.EqualError(t, err, "invalid period: from must be before to")
+}
+
+func TestReportFilter_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  ReportFilter
+		wantErr string
+	}{
+		{name: "defaults", filter: ReportFilter{}},
+		{name: "week by revenue", filter: ReportFilter{Interval: "Week", SortBy: "revenue", Limit: 20}},
+		{name: "unknown interval", filter: ReportFilter{Interval: "year"}, wantErr: `invalid interval "year": expected day, week or month`},
+		{name: "unknown sort", filter: ReportFilter{SortBy: "margin"}, wantErr: `invalid sort "margin": expected units or revenue`},
+		{name: "limit too large", filter: ReportFilter{Limit: 500}, wantErr: "invalid limit: expected 1 to 100"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.filter.Validate()
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NotEmpty(t, tt.filter.Interval)
+			assert.NotEmpty(t, tt.filter.SortBy)
+			assert.Positive(t, tt.filter.Limit)
+		})
+	}
+}
+
+func TestReportFilter_Period(t *testing.T) {
+	moscow, _ := time.LoadLocation("Europe/Moscow")
+	// 22:30 UTC воскресенья 3 марта - уже понедельник 4 марта в Москве.
+	at := time.Date(2024, 3, 3, 22, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		filter   ReportFilter
+		expected time.Time
+	}{
+		{name: "day utc", filter: ReportFilter{Location: time.UTC, Interval: ReportDay}, expected: time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC)},
+		{name: "day moscow", filter: ReportFilter{Location: moscow, Interval: ReportDay}, expected: time.Date(2024, 3, 4, 0, 0, 0, 0, moscow)},
+		{name: "week utc", filter: ReportFilter{Location: time.UTC, Interval: ReportWeek}, expected: time.Date(2024, 2, 26, 0, 0, 0, 0, time.UTC)},
+		{name: "week moscow", filter: ReportFilter{Location: moscow, Interval: ReportWeek}, expected: time.Date(2024, 3, 4, 0, 0, 0, 0, moscow)},
+		{name: "month", filter: ReportFilter{Location: moscow, Interval: ReportMonth}, expected: time.Date(2024, 3, 1, 0, 0, 0, 0, moscow)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.True(t, tt.expected.Equal(tt.filter.Period(at)), "got %s", tt.filter.Period(at))
+		})
+	}
+}
+
+func TestOrderFunnel(t *testing.T) {
+	// Act
+	funnel := OrderFunnel([]StatusCount{
+		{Status: "legacy", Orders: 1},
+		{Status: OrderStatusCancelled, Orders: 2, Revenue: 300},
+		{Status: OrderStatusPending, Orders: 5, Revenue: 900},
+	})
+
+	// Assert
+	statuses := make([]string, len(funnel))
+	for i, c := range funnel {
+		statuses[i] = c.Status
+	}
+	assert.Equal(t, []string{"pending", "processing", "partially_shipped", "shipped", "delivered", "cancelled", "legacy"}, statuses)
+	assert.Equal(t, 5, funnel[0].Orders)
+	assert.Equal(t, 0, funnel[1].Orders)
+	assert.Equal(t, 300, funnel[5].Revenue)
+}
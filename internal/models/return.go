@@ -0,0 +1,117 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Статусы возврата. Заявка (ReturnRequested) одобряется или отклоняется
+// сотрудником; одобренный возврат принимается на склад (ReturnReceived),
+// после чего покупателю возвращаются деньги (ReturnRefunded). На время
+// обращения к платежному шлюзу заявка находится в ReturnRefunding.
+const (
+	ReturnRequested = "requested"
+	ReturnApproved  = "approved"
+	ReturnRejected  = "rejected"
+	ReturnReceived  = "received"
+	ReturnRefunding = "refunding"
+	ReturnRefunded  = "refunded"
+)
+
+// Причины возврата позиции.
+const (
+	ReturnReasonDamaged        = "damaged"
+	ReturnReasonDefective      = "defective"
+	ReturnReasonWrongItem      = "wrong_item"
+	ReturnReasonNotAsDescribed = "not_as_described"
+	ReturnReasonNoLongerNeeded = "no_longer_needed"
+	ReturnReasonOther          = "other"
+)
+
+// Судьба принятого товара: возврат на склад через журнал движения или
+// списание поврежденного товара.
+const (
+	DispositionRestock  = "restock"
+	DispositionWriteOff = "write_off"
+)
+
+// ReturnRequest - заявка на возврат позиций заказа. Позиции хранят товар,
+// вариант и цену на момент заявки, потому что позиции заказа при его
+// изменении пересоздаются.
+type ReturnRequest struct {
+	ID             int          `json:"id" db:"id"`
+	OrderID        int          `json:"order_id" db:"order_id"`
+	Status         string       `json:"status" db:"status"`
+	Note           string       `json:"note,omitempty" db:"note"`
+	ResolutionNote string       `json:"resolution_note,omitempty" db:"resolution_note"`
+	RefundAmount   int          `json:"refund_amount" db:"refund_amount"`
+	PaymentID      int          `json:"payment_id,omitempty" db:"payment_id"`
+	Items          []ReturnItem `json:"items"`
+	CreatedAt      time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at" db:"updated_at"`
+}
+
+type ReturnItem struct {
+	ID          int    `json:"id" db:"id"`
+	ReturnID    int    `json:"return_id" db:"return_id"`
+	OrderItemID int    `json:"order_item_id" db:"order_item_id"`
+	ProductID   int    `json:"product_id" db:"product_id"`
+	VariantID   int    `json:"variant_id,omitempty" db:"variant_id"`
+	Quantity    int    `json:"quantity" db:"quantity"`
+	Price       int    `json:"price" db:"price"`
+	Reason      string `json:"reason" db:"reason"`
+	Disposition string `json:"disposition,omitempty" db:"disposition"`
+}
+
+func (r *ReturnRequest) Validate() error {
+	if len(r.Items) == 0 {
+		return errors.New("return must contain at least one item")
+	}
+	if len(r.Note) > 1000 {
+		return errors.New("return note is too long")
+	}
+
+	seen := make(map[int]bool, len(r.Items))
+	for _, item := range r.Items {
+		if item.OrderItemID <= 0 {
+			return errors.New("order item ID is required")
+		}
+		if seen[item.OrderItemID] {
+			return fmt.Errorf("order item %d is listed twice", item.OrderItemID)
+		}
+		seen[item.OrderItemID] = true
+		if item.Quantity <= 0 {
+			return errors.New("return quantity must be positive")
+		}
+		if !validReturnReason(item.Reason) {
+			return fmt.Errorf("unknown return reason %q", item.Reason)
+		}
+	}
+	return nil
+}
+
+func validReturnReason(reason string) bool {
+	switch reason {
+	case ReturnReasonDamaged, ReturnReasonDefective, ReturnReasonWrongItem,
+		ReturnReasonNotAsDescribed, ReturnReasonNoLongerNeeded, ReturnReasonOther:
+		return true
+	}
+	return false
+}
+
+// Value - стоимость возвращаемых позиций по ценам заказа; больше этой суммы
+// по возврату вернуть нельзя.
+func (r *ReturnRequest) Value() int {
+	value := 0
+	for _, item := range r.Items {
+		value += item.Price * item.Quantity
+	}
+	return value
+}
+
+// IsOpen сообщает, учитываются ли позиции возврата в уже возвращенном
+// количестве. Отклоненная заявка позиции не занимает.
+func (r *ReturnRequest) IsOpen() bool {
+	return r.Status != ReturnRejected
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReturnRequest_Validate(t *testing.T) {
+	item := ReturnItem{OrderItemID: 1, Quantity: 1, Reason: ReturnReasonDamaged}
+
+	tests := []struct {
+		name string
+		ret  ReturnRequest
+		err  string
+	}{
+		{name: "valid", ret: ReturnRequest{Items: []ReturnItem{item}}},
+		{name: "no items", ret: ReturnRequest{}, err: "return must contain at least one item"},
+		{
+			name: "missing order item",
+			ret:  ReturnRequest{Items: []ReturnItem{{Quantity: 1, Reason: ReturnReasonOther}}},
+			err:  "order item ID is required",
+		},
+		{name: "duplicate item", ret: ReturnRequest{Items: []ReturnItem{item, item}}, err: "order item 1 is listed twice"},
+		{
+			name: "zero quantity",
+			ret:  ReturnRequest{Items: []ReturnItem{{OrderItemID: 1, Reason: ReturnReasonOther}}},
+			err:  "return quantity must be positive",
+		},
+		{
+			name: "unknown reason",
+			ret:  ReturnRequest{Items: []ReturnItem{{OrderItemID: 1, Quantity: 1, Reason: "changed_mind"}}},
+			err:  `unknown return reason "changed_mind"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.ret.Validate()
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestReturnRequest_Value(t *testing.T) {
+	ret := ReturnRequest{Items: []ReturnItem{
+		{Quantity: 2, Price: 150},
+		{Quantity: 1, Price: 300},
+	}}
+
+	assert.Equal(t, 600, ret.Value())
+}
+
+func TestReturnRequest_IsOpen(t *testing.T) {
+	assert.True(t, (&ReturnRequest{Status: ReturnRequested}).IsOpen())
+	assert.True(t, (&ReturnRequest{Status: ReturnRefunded}).IsOpen())
+	assert.False(t, (&ReturnRequest{Status: ReturnRejected}).IsOpen())
+}
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"errors"
+	"strings"
+)
+
+// ProductSearch - параметры полнотекстового поиска товаров.
+// CategoryIDs заполняет сервис: категория из CategoryID и все ее потомки.
+type ProductSearch struct {
+	Query       string
+	CategoryID  int
+	CategoryIDs []int
+	InStock     bool
+	Limit       int
+	Offset      int
+}
+
+func (s *ProductSearch) Validate() error {
+	if strings.TrimSpace(s.Query) == "" {
+		return errors.New("search query is required")
+	}
+	if len(s.Query) > 200 {
+		return errors.New("search query is too long")
+	}
+	return nil
+}
+
+// SearchHit - найденный товар и его релевантность.
+type SearchHit struct {
+	*Product
+	Score float64 `json:"score"`
+}
+
+type SearchResult struct {
+	Hits   []SearchHit  `json:"products"`
+	Total  int          `json:"total"`
+	Facets SearchFacets `json:"facets"`
+}
+
+// SearchFacets считаются по всем найденным товарам, а не только по странице.
+type SearchFacets struct {
+	Categories []CategoryFacet `json:"categories"`
+	Prices     []PriceFacet    `json:"prices"`
+	InStock    int             `json:"in_stock"`
+	OutOfStock int             `json:"out_of_stock"`
+}
+
+type CategoryFacet struct {
+	ID    int    `json:"id" db:"id"`
+	Name  string `json:"name" db:"name"`
+	Count int    `json:"count" db:"count"`
+}
+
+type PriceFacet struct {
+	PriceBucket
+	Count int `json:"count"`
+}
+
+// PriceBucket - диапазон цен [Min, Max). Max == 0 означает "без верхней границы".
+type PriceBucket struct {
+	Label string  `json:"label"`
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max,omitempty"`
+}
+
+func (b PriceBucket) Contains(price float64) bool {
+	return price >= b.Min && (b.Max == 0 || price < b.Max)
+}
+
+var PriceBuckets = []PriceBucket{
+	{Label: "0-50", Min: 0, Max: 50},
+	{Label: "50-100", Min: 50, Max: 100},
+	{Label: "100-500", Min: 100, Max: 500},
+	{Label: "500-1000", Min: 500, Max: 1000},
+	{Label: "1000+", Min: 1000},
+}
@@ -0,0 +1,147 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Статусы отправления. Этикетка создается при сборке (ShipmentLabelCreated),
+// затем посылка передается перевозчику (ShipmentShipped) и доставляется
+// (ShipmentDelivered). Отмена возможна только до передачи перевозчику.
+const (
+	ShipmentLabelCreated = "label_created"
+	ShipmentShipped      = "shipped"
+	ShipmentDelivered    = "delivered"
+	ShipmentCancelled    = "cancelled"
+)
+
+// Статусы заказа, которые рассчитываются по отправлениям, см.
+// FulfillmentStatus.
+const (
+	OrderStatusPartiallyShipped = "partially_shipped"
+	OrderStatusShipped          = "shipped"
+	OrderStatusDelivered        = "delivered"
+)
+
+// Shipment - отправление части позиций заказа. Позиции хранят товар и
+// вариант на момент отправки, как и позиции возврата.
+type Shipment struct {
+	ID             int            `json:"id" db:"id"`
+	OrderID        int            `json:"order_id" db:"order_id"`
+	Status         string         `json:"status" db:"status"`
+	Carrier        string         `json:"carrier,omitempty" db:"carrier"`
+	TrackingNumber string         `json:"tracking_number,omitempty" db:"tracking_number"`
+	Items          []ShipmentItem `json:"items"`
+	ShippedAt      *time.Time     `json:"shipped_at,omitempty" db:"shipped_at"`
+	DeliveredAt    *time.Time     `json:"delivered_at,omitempty" db:"delivered_at"`
+	CreatedAt      time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+type ShipmentItem struct {
+	ID          int `json:"id" db:"id"`
+	ShipmentID  int `json:"shipment_id" db:"shipment_id"`
+	OrderItemID int `json:"order_item_id" db:"order_item_id"`
+	ProductID   int `json:"product_id" db:"product_id"`
+	VariantID   int `json:"variant_id,omitempty" db:"variant_id"`
+	Quantity    int `json:"quantity" db:"quantity"`
+}
+
+func (s *Shipment) Validate() error {
+	if len(s.Items) == 0 {
+		return errors.New("shipment must contain at least one item")
+	}
+	if len(s.Carrier) > 64 {
+		return errors.New("shipment carrier is too long")
+	}
+	if len(s.TrackingNumber) > 128 {
+		return errors.New("shipment tracking number is too long")
+	}
+
+	seen := make(map[int]bool, len(s.Items))
+	for _, item := range s.Items {
+		if item.OrderItemID <= 0 {
+			return errors.New("order item ID is required")
+		}
+		if seen[item.OrderItemID] {
+			return fmt.Errorf("order item %d is listed twice", item.OrderItemID)
+		}
+		seen[item.OrderItemID] = true
+		if item.Quantity <= 0 {
+			return errors.New("shipment quantity must be positive")
+		}
+	}
+	return nil
+}
+
+// CanTransition сообщает, допустим ли переход отправления в status.
+// Повторная установка текущего статуса допустима.
+func (s *Shipment) CanTransition(status string) bool {
+	if status == s.Status {
+		return true
+	}
+	switch s.Status {
+	case ShipmentLabelCreated:
+		return status == ShipmentShipped || status == ShipmentDelivered || status == ShipmentCancelled
+	case ShipmentShipped:
+		return status == ShipmentDelivered
+	}
+	return false
+}
+
+// IsOpen сообщает, учитываются ли позиции отправления в отправленном
+// количестве. Отмененное отправление позиции не занимает.
+func (s *Shipment) IsOpen() bool {
+	return s.Status != ShipmentCancelled
+}
+
+// FulfillmentStatus рассчитывает статус заказа по отправлениям: delivered,
+// когда доставлены все позиции, shipped - когда все переданы перевозчику,
+// partially_shipped - когда передана часть. Пока ничего не передано,
+// возвращается OrderStatusProcessing.
+func FulfillmentStatus(order *Order, shipments []Shipment) string {
+	type line struct{ productID, variantID int }
+	ordered := make(map[line]int)
+	for _, item := range order.Products {
+		ordered[line{item.ProductID, item.VariantID}] += item.Quantity
+	}
+
+	shipped := make(map[line]int)
+	delivered := make(map[line]int)
+	for _, s := range shipments {
+		if s.Status != ShipmentShipped && s.Status != ShipmentDelivered {
+			continue
+		}
+		for _, item := range s.Items {
+			l := line{item.ProductID, item.VariantID}
+			shipped[l] += item.Quantity
+			if s.Status == ShipmentDelivered {
+				delivered[l] += item.Quantity
+			}
+		}
+	}
+
+	allShipped, allDelivered, anyShipped := len(ordered) > 0, len(ordered) > 0, false
+	for l, quantity := range ordered {
+		if shipped[l] > 0 {
+			anyShipped = true
+		}
+		if shipped[l] < quantity {
+			allShipped = false
+		}
+		if delivered[l] < quantity {
+			allDelivered = false
+		}
+	}
+
+	switch {
+	case allDelivered:
+		return OrderStatusDelivered
+	case allShipped:
+		return OrderStatusShipped
+	case anyShipped:
+		return OrderStatusPartiallyShipped
+	}
+	return OrderStatusProcessing
+}
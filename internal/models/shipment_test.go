@@ -0,0 +1,87 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShipment_Validate(t *testing.T) {
+	item := ShipmentItem{OrderItemID: 1, Quantity: 1}
+
+	tests := []struct {
+		name     string
+		shipment Shipment
+		err      string
+	}{
+		{name: "valid", shipment: Shipment{Items: []ShipmentItem{item}}},
+		{name: "no items", shipment: Shipment{}, err: "shipment must contain at least one item"},
+		{name: "duplicate item", shipment: Shipment{Items: []ShipmentItem{item, item}}, err: "order item 1 is listed twice"},
+		{name: "zero quantity", shipment: Shipment{Items: []ShipmentItem{{OrderItemID: 1}}}, err: "shipment quantity must be positive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.shipment.Validate()
+			if tt.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestShipment_CanTransition(t *testing.T) {
+	label := Shipment{Status: ShipmentLabelCreated}
+	shipped := Shipment{Status: ShipmentShipped}
+
+	assert.True(t, label.CanTransition(ShipmentShipped))
+	assert.True(t, label.CanTransition(ShipmentCancelled))
+	assert.True(t, shipped.CanTransition(ShipmentDelivered))
+	assert.False(t, shipped.CanTransition(ShipmentCancelled))
+	assert.False(t, shipped.CanTransition(ShipmentLabelCreated))
+}
+
+func TestFulfillmentStatus(t *testing.T) {
+	order := &Order{Products: []OrderItem{
+		{ID: 1, ProductID: 1, VariantID: 10, Quantity: 2},
+		{ID: 2, ProductID: 2, VariantID: 20, Quantity: 1},
+	}}
+	shipment := func(status string, items ...ShipmentItem) Shipment {
+		return Shipment{Status: status, Items: items}
+	}
+	first := ShipmentItem{OrderItemID: 1, ProductID: 1, VariantID: 10, Quantity: 2}
+	second := ShipmentItem{OrderItemID: 2, ProductID: 2, VariantID: 20, Quantity: 1}
+
+	tests := []struct {
+		name      string
+		shipments []Shipment
+		want      string
+	}{
+		{name: "nothing shipped", want: OrderStatusProcessing},
+		{name: "label only", shipments: []Shipment{shipment(ShipmentLabelCreated, first, second)}, want: OrderStatusProcessing},
+		{name: "partially shipped", shipments: []Shipment{shipment(ShipmentShipped, first)}, want: OrderStatusPartiallyShipped},
+		{
+			name:      "all shipped, part delivered",
+			shipments: []Shipment{shipment(ShipmentDelivered, first), shipment(ShipmentShipped, second)},
+			want:      OrderStatusShipped,
+		},
+		{
+			name:      "all delivered",
+			shipments: []Shipment{shipment(ShipmentDelivered, first), shipment(ShipmentDelivered, second)},
+			want:      OrderStatusDelivered,
+		},
+		{
+			name:      "cancelled shipment ignored",
+			shipments: []Shipment{shipment(ShipmentCancelled, first, second)},
+			want:      OrderStatusProcessing,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, FulfillmentStatus(order, tt.shipments))
+		})
+	}
+}
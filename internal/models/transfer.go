@@ -0,0 +1,80 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	TransferPending   = "pending"
+	TransferInTransit = "in_transit"
+	TransferReceived  = "received"
+	TransferCancelled = "cancelled"
+)
+
+// StockTransfer - документ перемещения товара между складами.
+// pending -> in_transit (списано с источника) -> received (оприходовано
+// на получателе); pending и in_transit можно отменить.
+type StockTransfer struct {
+	ID              int            `json:"id" db:"id"`
+	FromWarehouseID int            `json:"from_warehouse_id" db:"from_warehouse_id"`
+	ToWarehouseID   int            `json:"to_warehouse_id" db:"to_warehouse_id"`
+	Status          string         `json:"status" db:"status"`
+	Note            string         `json:"note,omitempty" db:"note"`
+	Items           []TransferItem `json:"items"`
+	CreatedAt       time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at" db:"updated_at"`
+	ShippedAt       *time.Time     `json:"shipped_at,omitempty" db:"shipped_at"`
+	ReceivedAt      *time.Time     `json:"received_at,omitempty" db:"received_at"`
+}
+
+type TransferItem struct {
+	ID         int `json:"id" db:"id"`
+	TransferID int `json:"transfer_id" db:"transfer_id"`
+	VariantID  int `json:"variant_id" db:"variant_id"`
+	Quantity   int `json:"quantity" db:"quantity"`
+}
+
+func (t *StockTransfer) Validate() error {
+	if t.FromWarehouseID <= 0 || t.ToWarehouseID <= 0 {
+		return errors.New("source and destination warehouses are required")
+	}
+	if t.FromWarehouseID == t.ToWarehouseID {
+		return errors.New("source and destination warehouses must differ")
+	}
+	if len(t.Items) == 0 {
+		return errors.New("transfer must contain at least one item")
+	}
+	seen := make(map[int]bool, len(t.Items))
+	for _, item := range t.Items {
+		if item.VariantID <= 0 {
+			return errors.New("transfer item variant ID is required")
+		}
+		if item.Quantity <= 0 {
+			return errors.New("transfer item quantity must be positive")
+		}
+		if seen[item.VariantID] {
+			return fmt.Errorf("duplicate transfer item for variant %d", item.VariantID)
+		}
+		seen[item.VariantID] = true
+	}
+	if len(t.Note) > 500 {
+		return errors.New("transfer note is too long")
+	}
+	return nil
+}
+
+// CanTransition проверяет допустимость перехода статуса перемещения.
+func (t *StockTransfer) CanTransition(status string) bool {
+	switch status {
+	case TransferInTransit:
+		return t.Status == TransferPending
+	case TransferReceived:
+		return t.Status == TransferInTransit
+	case TransferCancelled:
+		return t.Status == TransferPending || t.Status == TransferInTransit
+	default:
+		return false
+	}
+}
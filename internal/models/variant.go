@@ -0,0 +1,155 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ProductOption - ось вариантов товара, например размер или цвет.
+type ProductOption struct {
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+}
+
+// ProductVariant - продаваемая единица товара со своим SKU, ценой и остатком.
+// Товар без осей вариантов имеет один вариант по умолчанию с пустыми Options.
+// Cost - средняя закупочная цена единицы: задается вручную и пересчитывается
+// при приемке товара от поставщика.
+type ProductVariant struct {
+	ID        int            `json:"id" db:"id"`
+	ProductID int            `json:"product_id" db:"product_id"`
+	SKU       string         `json:"sku" db:"sku"`
+	Options   VariantOptions `json:"options" db:"options"`
+	Price     float64        `json:"price" db:"price"`
+	Quantity  int            `json:"quantity" db:"quantity"`
+	Cost      int            `json:"cost" db:"cost"`
+	CreatedAt time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+func (v *ProductVariant) Validate() error {
+	if len(v.SKU) > 64 {
+		return errors.New("variant sku is too long")
+	}
+	if v.Price <= 0 {
+		return errors.New("variant price must be positive")
+	}
+	if v.Quantity < 0 {
+		return errors.New("variant quantity cannot be negative")
+	}
+	if v.Cost < 0 {
+		return errors.New("variant cost cannot be negative")
+	}
+	return nil
+}
+
+// IsDefault сообщает, что вариант не задает значений опций.
+func (v *ProductVariant) IsDefault() bool {
+	return len(v.Options) == 0
+}
+
+// ProductOptions хранится в PostgreSQL как JSONB.
+type ProductOptions []ProductOption
+
+func (o ProductOptions) Validate() error {
+	seen := make(map[string]bool, len(o))
+	for _, opt := range o {
+		name := strings.ToLower(strings.TrimSpace(opt.Name))
+		if name == "" {
+			return errors.New("option name is required")
+		}
+		if seen[name] {
+			return fmt.Errorf("duplicate option %q", opt.Name)
+		}
+		seen[name] = true
+		if len(opt.Values) == 0 {
+			return fmt.Errorf("option %q must have at least one value", opt.Name)
+		}
+	}
+	return nil
+}
+
+// ValidateVariant проверяет, что вариант задает ровно одно допустимое значение
+// для каждой оси товара.
+func (o ProductOptions) ValidateVariant(v *ProductVariant) error {
+	if len(v.Options) != len(o) {
+		return fmt.Errorf("variant must set exactly %d option(s)", len(o))
+	}
+	for _, opt := range o {
+		value, ok := v.Options[opt.Name]
+		if !ok {
+			return fmt.Errorf("variant is missing option %q", opt.Name)
+		}
+		allowed := false
+		for _, candidate := range opt.Values {
+			if candidate == value {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Errorf("value %q is not allowed for option %q", value, opt.Name)
+		}
+	}
+	return nil
+}
+
+func (o ProductOptions) Value() (driver.Value, error) {
+	if o == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(o)
+	return string(data), err
+}
+
+func (o *ProductOptions) Scan(src interface{}) error {
+	return scanJSON(src, o)
+}
+
+// VariantOptions - значения опций варианта: имя оси -> значение.
+type VariantOptions map[string]string
+
+// Key возвращает каноническое представление комбинации опций для проверки
+// уникальности вариантов.
+func (o VariantOptions) Key() string {
+	keys := make([]string, 0, len(o))
+	for k := range o {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k + "=" + o[k]
+	}
+	return strings.Join(parts, ";")
+}
+
+func (o VariantOptions) Value() (driver.Value, error) {
+	if o == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(o)
+	return string(data), err
+}
+
+func (o *VariantOptions) Scan(src interface{}) error {
+	return scanJSON(src, o)
+}
+
+func scanJSON(src interface{}, dest interface{}) error {
+	switch data := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(data, dest)
+	case string:
+		return json.Unmarshal([]byte(data), dest)
+	default:
+		return fmt.Errorf("cannot scan %T into %T", src, dest)
+	}
+}
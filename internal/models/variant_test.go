@@ -0,0 +1,114 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProductOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  ProductOptions
+		errorMsg string
+	}{
+		{
+			name:    "Valid options",
+			options: ProductOptions{{Name: "size", Values: []string{"S", "M"}}, {Name: "color", Values: []string{"red"}}},
+		},
+		{
+			name:    "No options",
+			options: nil,
+		},
+		{
+			name:     "Empty name",
+			options:  ProductOptions{{Name: " ", Values: []string{"S"}}},
+			errorMsg: "option name is required",
+		},
+		{
+			name:     "Duplicate name",
+			options:  ProductOptions{{Name: "size", Values: []string{"S"}}, {Name: "Size", Values: []string{"M"}}},
+			errorMsg: `duplicate option "Size"`,
+		},
+		{
+			name:     "No values",
+			options:  ProductOptions{{Name: "size"}},
+			errorMsg: `option "size" must have at least one value`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.options.Validate()
+			if tt.errorMsg == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.errorMsg)
+			}
+		})
+	}
+}
+
+func TestProductOptions_ValidateVariant(t *testing.T) {
+	options := ProductOptions{
+		{Name: "size", Values: []string{"S", "M"}},
+		{Name: "color", Values: []string{"red", "blue"}},
+	}
+
+	tests := []struct {
+		name     string
+		variant  VariantOptions
+		errorMsg string
+	}{
+		{name: "Valid combination", variant: VariantOptions{"size": "M", "color": "red"}},
+		{name: "Missing option", variant: VariantOptions{"size": "M", "material": "wool"}, errorMsg: `variant is missing option "color"`},
+		{name: "Unknown value", variant: VariantOptions{"size": "XL", "color": "red"}, errorMsg: `value "XL" is not allowed for option "size"`},
+		{name: "Too few options", variant: VariantOptions{"size": "M"}, errorMsg: "variant must set exactly 2 option(s)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := options.ValidateVariant(&ProductVariant{Options: tt.variant})
+			if tt.errorMsg == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.errorMsg)
+			}
+		})
+	}
+}
+
+func TestVariantOptions_Key(t *testing.T) {
+	a := VariantOptions{"size": "M", "color": "red"}
+	b := VariantOptions{"color": "red", "size": "M"}
+
+	assert.Equal(t, "color=red;size=M", a.Key())
+	assert.Equal(t, a.Key(), b.Key())
+	assert.Equal(t, "", VariantOptions{}.Key())
+}
+
+func TestVariantOptions_ValueScan(t *testing.T) {
+	// Arrange
+	options := VariantOptions{"size": "M"}
+
+	// Act
+	value, err := options.Value()
+	assert.NoError(t, err)
+
+	var decoded VariantOptions
+	err = decoded.Scan([]byte(value.(string)))
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, options, decoded)
+
+	value, _ = VariantOptions(nil).Value()
+	assert.Equal(t, "{}", value) // NULL в JSONB не пишем
+}
+
+func TestProductVariant_Validate(t *testing.T) {
+	assert.NoError(t, (&ProductVariant{Price: 10, Quantity: 0}).Validate())
+	assert.EqualError(t, (&ProductVariant{Price: 0}).Validate(), "variant price must be positive")
+	assert.EqualError(t, (&ProductVariant{Price: 10, Quantity: -1}).Validate(), "variant quantity cannot be negative")
+	assert.EqualError(t, (&ProductVariant{Price: 10, Cost: -1}).Validate(), "variant cost cannot be negative")
+}
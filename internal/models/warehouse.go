@@ -0,0 +1,157 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Warehouse - склад, с которого отгружаются заказы. Меньший Priority означает
+// более предпочтительный склад; склад с наименьшим приоритетом является
+// основным и принимает остатки, заданные через API товаров.
+type Warehouse struct {
+	ID        int       `json:"id" db:"id"`
+	Code      string    `json:"code" db:"code"`
+	Name      string    `json:"name" db:"name"`
+	Address   Address   `json:"address" db:"address"`
+	Priority  int       `json:"priority" db:"priority"`
+	Active    bool      `json:"active" db:"active"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+func (w *Warehouse) Validate() error {
+	if strings.TrimSpace(w.Code) == "" {
+		return errors.New("warehouse code is required")
+	}
+	if len(w.Code) > 32 {
+		return errors.New("warehouse code is too long")
+	}
+	if strings.TrimSpace(w.Name) == "" {
+		return errors.New("warehouse name is required")
+	}
+	if len(w.Name) > 100 {
+		return errors.New("warehouse name is too long")
+	}
+	if w.Priority < 0 {
+		return errors.New("warehouse priority cannot be negative")
+	}
+	return w.Address.Validate()
+}
+
+// StockLevel - остаток варианта на складе.
+type StockLevel struct {
+	WarehouseID int       `json:"warehouse_id" db:"warehouse_id"`
+	VariantID   int       `json:"variant_id" db:"variant_id"`
+	ProductID   int       `json:"product_id" db:"product_id"`
+	Quantity    int       `json:"quantity" db:"quantity"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// StockAllocation - количество варианта, списанное со склада под заказ.
+type StockAllocation struct {
+	ID          int       `json:"id" db:"id"`
+	OrderID     int       `json:"order_id" db:"order_id"`
+	VariantID   int       `json:"variant_id" db:"variant_id"`
+	WarehouseID int       `json:"warehouse_id" db:"warehouse_id"`
+	Quantity    int       `json:"quantity" db:"quantity"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// ProductStock - доступность товара в разрезе складов.
+// Available совпадает с Product.Quantity; InTransit - товар в перемещениях.
+type ProductStock struct {
+	Available  int              `json:"available"`
+	InTransit  int              `json:"in_transit"`
+	Warehouses []WarehouseStock `json:"warehouses"`
+	Levels     []StockLevel     `json:"levels"`
+}
+
+type WarehouseStock struct {
+	WarehouseID int    `json:"warehouse_id"`
+	Code        string `json:"code"`
+	Name        string `json:"name"`
+	Quantity    int    `json:"quantity"`
+}
+
+type AllocationStrategy string
+
+const (
+	// AllocationPriority списывает со складов в порядке Priority.
+	AllocationPriority AllocationStrategy = "priority"
+	// AllocationNearest списывает с ближайших к адресу доставки складов;
+	// без адреса работает как AllocationPriority.
+	AllocationNearest AllocationStrategy = "nearest"
+)
+
+func ParseAllocationStrategy(s string) (AllocationStrategy, error) {
+	switch AllocationStrategy(strings.ToLower(strings.TrimSpace(s))) {
+	case AllocationPriority, "":
+		return AllocationPriority, nil
+	case AllocationNearest:
+		return AllocationNearest, nil
+	default:
+		return "", fmt.Errorf("unknown allocation strategy %q", s)
+	}
+}
+
+// SortWarehouses упорядочивает активные склады по стратегии распределения.
+func SortWarehouses(warehouses []*Warehouse, strategy AllocationStrategy, destination *Address) []*Warehouse {
+	sorted := make([]*Warehouse, 0, len(warehouses))
+	for _, w := range warehouses {
+		if w.Active {
+			sorted = append(sorted, w)
+		}
+	}
+
+	byPriority := func(a, b *Warehouse) bool {
+		if a.Priority != b.Priority {
+			return a.Priority < b.Priority
+		}
+		return a.ID < b.ID
+	}
+
+	if strategy == AllocationNearest && destination != nil {
+		distance := make(map[int]float64, len(sorted))
+		for _, w := range sorted {
+			distance[w.ID] = w.Address.DistanceTo(destination)
+		}
+		sort.SliceStable(sorted, func(i, j int) bool {
+			if distance[sorted[i].ID] != distance[sorted[j].ID] {
+				return distance[sorted[i].ID] < distance[sorted[j].ID]
+			}
+			return byPriority(sorted[i], sorted[j])
+		})
+		return sorted
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool { return byPriority(sorted[i], sorted[j]) })
+	return sorted
+}
+
+// PlanAllocation распределяет quantity по складам в заданном порядке, забирая
+// со склада столько, сколько на нем есть. available: warehouseID -> остаток.
+func PlanAllocation(ordered []*Warehouse, available map[int]int, quantity int) ([]StockAllocation, error) {
+	var plan []StockAllocation
+	remaining := quantity
+	for _, w := range ordered {
+		if remaining == 0 {
+			break
+		}
+		take := available[w.ID]
+		if take > remaining {
+			take = remaining
+		}
+		if take <= 0 {
+			continue
+		}
+		plan = append(plan, StockAllocation{WarehouseID: w.ID, Quantity: take})
+		remaining -= take
+	}
+	if remaining > 0 {
+		return nil, fmt.Errorf("insufficient stock: available %d, requested %d", quantity-remaining, quantity)
+	}
+	return plan, nil
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortWarehouses(t *testing.T) {
+	mskLat, mskLon := 55.75, 37.62
+	spbLat, spbLon := 59.93, 30.34
+	warehouses := []*Warehouse{
+		{ID: 1, Code: "MAIN", Priority: 0, Active: true, Address: Address{Country: "RU", Latitude: &mskLat, Longitude: &mskLon}},
+		{ID: 2, Code: "SPB", Priority: 10, Active: true, Address: Address{Country: "RU", Latitude: &spbLat, Longitude: &spbLon}},
+		{ID: 3, Code: "OLD", Priority: 5, Active: false},
+	}
+
+	byPriority := SortWarehouses(warehouses, AllocationPriority, nil)
+	assert.Equal(t, []int{1, 2}, warehouseIDs(byPriority))
+
+	destLat, destLon := 59.8, 30.1
+	nearest := SortWarehouses(warehouses, AllocationNearest, &Address{Latitude: &destLat, Longitude: &destLon})
+	assert.Equal(t, []int{2, 1}, warehouseIDs(nearest))
+
+	// Без адреса доставки "nearest" совпадает с "priority".
+	assert.Equal(t, []int{1, 2}, warehouseIDs(SortWarehouses(warehouses, AllocationNearest, nil)))
+}
+
+func TestPlanAllocation(t *testing.T) {
+	ordered := []*Warehouse{{ID: 2}, {ID: 1}}
+
+	plan, err := PlanAllocation(ordered, map[int]int{1: 10, 2: 3}, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, []StockAllocation{{WarehouseID: 2, Quantity: 3}, {WarehouseID: 1, Quantity: 2}}, plan)
+
+	_, err = PlanAllocation(ordered, map[int]int{1: 1, 2: 3}, 5)
+	assert.EqualError(t, err, "insufficient stock: available 4, requested 5")
+}
+
+func TestAddressDistanceTo(t *testing.T) {
+	msk := &Address{Country: "RU", Region: "Moscow", City: "Moscow"}
+	assert.Equal(t, float64(distanceSameCity), msk.DistanceTo(&Address{Country: "ru", Region: "moscow", City: "Moscow"}))
+	assert.Equal(t, float64(distanceSameCountry), msk.DistanceTo(&Address{Country: "RU", Region: "Tatarstan"}))
+	assert.Equal(t, float64(distanceUnknown), msk.DistanceTo(&Address{Country: "DE"}))
+
+	mskLat, mskLon := 55.7558, 37.6173
+	spbLat, spbLon := 59.9311, 30.3609
+	a := &Address{Latitude: &mskLat, Longitude: &mskLon}
+	b := &Address{Latitude: &spbLat, Longitude: &spbLon}
+	assert.InDelta(t, 634, a.DistanceTo(b), 5)
+}
+
+func TestStockTransferCanTransition(t *testing.T) {
+	transfer := &StockTransfer{Status: TransferPending}
+	assert.True(t, transfer.CanTransition(TransferInTransit))
+	assert.False(t, transfer.CanTransition(TransferReceived))
+	assert.True(t, transfer.CanTransition(TransferCancelled))
+
+	transfer.Status = TransferReceived
+	assert.False(t, transfer.CanTransition(TransferCancelled))
+}
+
+func warehouseIDs(warehouses []*Warehouse) []int {
+	ids := make([]int, len(warehouses))
+	for i, w := range warehouses {
+		ids[i] = w.ID
+	}
+	return ids
+}
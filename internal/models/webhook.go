@@ -0,0 +1,260 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// EventTypes - типы доменных событий, на которые можно подписать вебхук.
+var EventTypes = []string{
+	EventOrderCreated,
+	EventOrderStatusChanged,
+	EventProductPriceChanged,
+	EventStockDepleted,
+}
+
+// Состояния доставки вебхука. Доставка ждет отправки в
+// WebhookDeliveryPending и переходит в WebhookDeliveryFailed после
+// исчерпания попыток или отключения вебхука.
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// Интервал повтора доставки вебхука удваивается с каждой попыткой от
+// webhookRetryBase до webhookRetryMax.
+const (
+	webhookRetryBase = 30 * time.Second
+	webhookRetryMax  = time.Hour
+)
+
+const (
+	minWebhookSecret = 16
+	maxWebhookSecret = 256
+	// maxWebhookResponse - сколько байт ответа получателя сохраняется в
+	// журнале доставок.
+	maxWebhookResponse = 1024
+)
+
+// Webhook - подписка внешней системы на доменные события. Каждое
+// событие подписанных типов отправляется POST-запросом на URL с подписью
+// HMAC-SHA256 по Secret. После DisableAfter неудачных попыток подряд
+// вебхук отключается и включается снова только вручную.
+type Webhook struct {
+	ID                  int        `json:"id" db:"id"`
+	URL                 string     `json:"url" db:"url"`
+	EventTypes          []string   `json:"event_types" db:"-"`
+	Secret              string     `json:"secret,omitempty" db:"secret"`
+	Active              bool       `json:"active" db:"active"`
+	ConsecutiveFailures int        `json:"consecutive_failures" db:"consecutive_failures"`
+	DisabledReason      string     `json:"disabled_reason,omitempty" db:"disabled_reason"`
+	DisabledAt          *time.Time `json:"disabled_at,omitempty" db:"disabled_at"`
+	CreatedAt           time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+func (w *Webhook) Validate() error {
+	w.URL = strings.TrimSpace(w.URL)
+	if w.URL == "" {
+		return errors.New("webhook URL is required")
+	}
+	if len(w.URL) > 2048 {
+		return errors.New("webhook URL is too long")
+	}
+	u, err := url.Parse(w.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("webhook URL must be an absolute http or https URL")
+	}
+
+	if len(w.EventTypes) == 0 {
+		return errors.New("at least one event type is required")
+	}
+	seen := make(map[string]bool, len(w.EventTypes))
+	types := make([]string, 0, len(w.EventTypes))
+	for _, t := range w.EventTypes {
+		t = strings.TrimSpace(t)
+		if !IsEventType(t) {
+			return fmt.Errorf("unknown event type %q", t)
+		}
+		if !seen[t] {
+			seen[t] = true
+			types = append(types, t)
+		}
+	}
+	w.EventTypes = types
+
+	if len(w.Secret) < minWebhookSecret || len(w.Secret) > maxWebhookSecret {
+		return fmt.Errorf("webhook secret must be %d to %d characters", minWebhookSecret, maxWebhookSecret)
+	}
+	return nil
+}
+
+// IsEventType сообщает, является ли t известным типом доменного события.
+func IsEventType(t string) bool {
+	for _, known := range EventTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Subscribes сообщает, нужно ли отправлять вебхуку события типа eventType.
+func (w *Webhook) Subscribes(eventType string) bool {
+	for _, t := range w.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Enable включает вебхук и сбрасывает счетчик неудач.
+func (w *Webhook) Enable() {
+	w.Active = true
+	w.ConsecutiveFailures = 0
+	w.DisabledReason = ""
+	w.DisabledAt = nil
+}
+
+// RecordSuccess сбрасывает счетчик неудач после успешной доставки.
+func (w *Webhook) RecordSuccess() {
+	w.ConsecutiveFailures = 0
+}
+
+// RecordFailure учитывает неудачную попытку доставки и отключает вебхук,
+// если неудач подряд стало disableAfter. Возвращает true, если вебхук
+// отключен этим вызовом.
+func (w *Webhook) RecordFailure(reason string, now time.Time, disableAfter int) bool {
+	w.ConsecutiveFailures++
+	if !w.Active || disableAfter <= 0 || w.ConsecutiveFailures < disableAfter {
+		return false
+	}
+	w.Active = false
+	w.DisabledReason = fmt.Sprintf("%d consecutive failed deliveries, last: %s", w.ConsecutiveFailures, reason)
+	w.DisabledAt = &now
+	return true
+}
+
+// WebhookEnvelope - тело запроса вебхука. ID совпадает с ID события outbox
+// и не меняется при повторных доставках, по нему получатель отбрасывает
+// дубликаты.
+type WebhookEnvelope struct {
+	ID        int             `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// WebhookDelivery - запись журнала доставок: отправка одного события
+// одному вебхуку со всеми попытками. ResponseCode и ResponseBody
+// относятся к последней попытке; ResponseCode пуст, если ответа не было.
+// Повторная отправка вручную создает новую запись с RedeliveryOf.
+type WebhookDelivery struct {
+	ID            int             `json:"id" db:"id"`
+	WebhookID     int             `json:"webhook_id" db:"webhook_id"`
+	EventID       int             `json:"event_id" db:"event_id"`
+	EventType     string          `json:"event_type" db:"event_type"`
+	Payload       json.RawMessage `json:"payload" db:"payload"`
+	Status        string          `json:"status" db:"status"`
+	Attempts      int             `json:"attempts" db:"attempts"`
+	ResponseCode  int             `json:"response_code,omitempty" db:"response_code"`
+	ResponseBody  string          `json:"response_body,omitempty" db:"response_body"`
+	LastError     string          `json:"last_error,omitempty" db:"last_error"`
+	DurationMs    int             `json:"duration_ms" db:"duration_ms"`
+	RedeliveryOf  int             `json:"redelivery_of,omitempty" db:"redelivery_of"`
+	NextAttemptAt time.Time       `json:"next_attempt_at" db:"next_attempt_at"`
+	CreatedAt     time.Time       `json:"created_at" db:"created_at"`
+	DeliveredAt   *time.Time      `json:"delivered_at,omitempty" db:"delivered_at"`
+}
+
+// NewWebhookDelivery создает доставку события event вебхуку webhookID,
+// готовую к немедленной отправке.
+func NewWebhookDelivery(webhookID int, event OutboxEvent) (*WebhookDelivery, error) {
+	body, err := json.Marshal(WebhookEnvelope{
+		ID:        event.ID,
+		Type:      event.Type,
+		CreatedAt: event.CreatedAt,
+		Data:      event.Payload,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode webhook body: %w", err)
+	}
+	now := time.Now()
+	return &WebhookDelivery{
+		WebhookID:     webhookID,
+		EventID:       event.ID,
+		EventType:     event.Type,
+		Payload:       body,
+		Status:        WebhookDeliveryPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}, nil
+}
+
+// Redelivery создает новую доставку того же тела тому же вебхуку.
+func (d *WebhookDelivery) Redelivery(now time.Time) *WebhookDelivery {
+	return &WebhookDelivery{
+		WebhookID:     d.WebhookID,
+		EventID:       d.EventID,
+		EventType:     d.EventType,
+		Payload:       d.Payload,
+		Status:        WebhookDeliveryPending,
+		RedeliveryOf:  d.ID,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+}
+
+// Attempted записывает результат попытки: код и начало тела ответа,
+// длительность и ошибку. Без ошибки доставка считается успешной; иначе
+// назначается следующая попытка, а после maxAttempts доставка
+// переходит в WebhookDeliveryFailed.
+func (d *WebhookDelivery) Attempted(code int, body string, elapsed time.Duration, err error, now time.Time, maxAttempts int) {
+	d.Attempts++
+	d.ResponseCode = code
+	if len(body) > maxWebhookResponse {
+		body = body[:maxWebhookResponse]
+	}
+	d.ResponseBody = body
+	d.DurationMs = int(elapsed.Milliseconds())
+
+	if err == nil {
+		d.Status = WebhookDeliverySucceeded
+		d.LastError = ""
+		d.DeliveredAt = &now
+		return
+	}
+	d.LastError = err.Error()
+	if maxAttempts > 0 && d.Attempts >= maxAttempts {
+		d.Status = WebhookDeliveryFailed
+		return
+	}
+	d.NextAttemptAt = now.Add(WebhookRetryDelay(d.Attempts))
+}
+
+// Abandon прекращает доставку без попытки, например если вебхук отключен.
+func (d *WebhookDelivery) Abandon(reason string) {
+	d.Status = WebhookDeliveryFailed
+	d.LastError = reason
+}
+
+// WebhookRetryDelay возвращает паузу перед попыткой, следующей за attempts
+// неудачными.
+func WebhookRetryDelay(attempts int) time.Duration {
+	return backoff(webhookRetryBase, webhookRetryMax, attempts)
+}
+
+// WebhookDeliveryFilter отбирает записи журнала доставок; пустые поля не
+// ограничивают выборку. Записи возвращаются от новых к старым.
+type WebhookDeliveryFilter struct {
+	WebhookID int
+	EventID   int
+	Status    string
+	Limit     int
+}
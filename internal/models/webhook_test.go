@@ -0,0 +1,138 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhook_Validate(t *testing.T) {
+	secret := "0123456789abcdef"
+
+	tests := []struct {
+		name    string
+		webhook Webhook
+		wantErr bool
+	}{
+		{name: "valid", webhook: Webhook{URL: " https://erp.example.com/hooks ", EventTypes: []string{EventOrderCreated}, Secret: secret}},
+		{name: "missing URL", webhook: Webhook{EventTypes: []string{EventOrderCreated}, Secret: secret}, wantErr: true},
+		{name: "relative URL", webhook: Webhook{URL: "/hooks", EventTypes: []string{EventOrderCreated}, Secret: secret}, wantErr: true},
+		{name: "unsupported scheme", webhook: Webhook{URL: "ftp://example.com", EventTypes: []string{EventOrderCreated}, Secret: secret}, wantErr: true},
+		{name: "no event types", webhook: Webhook{URL: "https://example.com", Secret: secret}, wantErr: true},
+		{name: "unknown event type", webhook: Webhook{URL: "https://example.com", EventTypes: []string{"order.deleted"}, Secret: secret}, wantErr: true},
+		{name: "short secret", webhook: Webhook{URL: "https://example.com", EventTypes: []string{EventOrderCreated}, Secret: "short"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.webhook.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestWebhook_ValidateDeduplicatesEventTypes(t *testing.T) {
+	webhook := Webhook{
+		URL:        "https://example.com",
+		EventTypes: []string{EventOrderCreated, EventStockDepleted, EventOrderCreated},
+		Secret:     "0123456789abcdef",
+	}
+
+	assert.NoError(t, webhook.Validate())
+	assert.Equal(t, []string{EventOrderCreated, EventStockDepleted}, webhook.EventTypes)
+	assert.True(t, webhook.Subscribes(EventStockDepleted))
+	assert.False(t, webhook.Subscribes(EventProductPriceChanged))
+}
+
+func TestWebhook_RecordFailureDisables(t *testing.T) {
+	// Arrange
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	webhook := Webhook{ID: 1, Active: true}
+
+	// Act
+	assert.False(t, webhook.RecordFailure("status 500", now, 3))
+	assert.False(t, webhook.RecordFailure("status 500", now, 3))
+	disabled := webhook.RecordFailure("timeout", now, 3)
+
+	// Assert
+	assert.True(t, disabled)
+	assert.False(t, webhook.Active)
+	assert.Equal(t, 3, webhook.ConsecutiveFailures)
+	assert.Contains(t, webhook.DisabledReason, "timeout")
+	assert.Equal(t, &now, webhook.DisabledAt)
+
+	webhook.Enable()
+	assert.True(t, webhook.Active)
+	assert.Zero(t, webhook.ConsecutiveFailures)
+	assert.Nil(t, webhook.DisabledAt)
+}
+
+func TestWebhook_RecordSuccessResetsFailures(t *testing.T) {
+	webhook := Webhook{ID: 1, Active: true}
+	webhook.RecordFailure("status 500", time.Now(), 3)
+	webhook.RecordFailure("status 500", time.Now(), 3)
+
+	webhook.RecordSuccess()
+	assert.False(t, webhook.RecordFailure("status 500", time.Now(), 3))
+	assert.True(t, webhook.Active)
+}
+
+func TestNewWebhookDelivery_Envelope(t *testing.T) {
+	// Arrange
+	createdAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	event := OutboxEvent{ID: 9, Type: EventOrderCreated, Payload: json.RawMessage(`{"order_id":5}`), CreatedAt: createdAt}
+
+	// Act
+	delivery, err := NewWebhookDelivery(2, event)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, WebhookDeliveryPending, delivery.Status)
+	assert.Equal(t, 9, delivery.EventID)
+
+	var envelope WebhookEnvelope
+	assert.NoError(t, json.Unmarshal(delivery.Payload, &envelope))
+	assert.Equal(t, 9, envelope.ID)
+	assert.Equal(t, EventOrderCreated, envelope.Type)
+	assert.True(t, createdAt.Equal(envelope.CreatedAt))
+	assert.JSONEq(t, `{"order_id":5}`, string(envelope.Data))
+}
+
+func TestWebhookDelivery_Attempted(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	delivery := WebhookDelivery{ID: 1, Status: WebhookDeliveryPending}
+
+	delivery.Attempted(503, "busy", 120*time.Millisecond, errors.New("status 503"), now, 2)
+	assert.Equal(t, WebhookDeliveryPending, delivery.Status)
+	assert.Equal(t, 503, delivery.ResponseCode)
+	assert.Equal(t, 120, delivery.DurationMs)
+	assert.Equal(t, now.Add(30*time.Second), delivery.NextAttemptAt)
+
+	delivery.Attempted(0, "", time.Second, errors.New("connection refused"), now, 2)
+	assert.Equal(t, WebhookDeliveryFailed, delivery.Status)
+	assert.Equal(t, 2, delivery.Attempts)
+	assert.Zero(t, delivery.ResponseCode)
+
+	retry := delivery.Redelivery(now)
+	assert.Equal(t, WebhookDeliveryPending, retry.Status)
+	assert.Equal(t, 1, retry.RedeliveryOf)
+	assert.Zero(t, retry.Attempts)
+
+	retry.Attempted(204, "", time.Millisecond, nil, now, 2)
+	assert.Equal(t, WebhookDeliverySucceeded, retry.Status)
+	assert.Equal(t, &now, retry.DeliveredAt)
+	assert.Empty(t, retry.LastError)
+}
+
+func TestWebhookRetryDelay(t *testing.T) {
+	assert.Equal(t, 30*time.Second, WebhookRetryDelay(1))
+	assert.Equal(t, 4*time.Minute, WebhookRetryDelay(4))
+	assert.Equal(t, time.Hour, WebhookRetryDelay(20))
+}
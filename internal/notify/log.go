@@ -0,0 +1,22 @@
+package notify
+
+import (
+	"backend-store/internal/models"
+	"backend-store/pkg/logger"
+	"context"
+)
+
+type logNotifier struct {
+	log logger.Log
+}
+
+// NewLogNotifier пишет уведомления в журнал приложения.
+func NewLogNotifier(log logger.Log) Notifier {
+	return &logNotifier{log: log}
+}
+
+func (n *logNotifier) Notify(ctx context.Context, event models.LowStockEvent) error {
+	n.log.Warn("Low stock: product %d (%s) has %d left, reorder point %d, reorder quantity %d",
+		event.ProductID, event.Name, event.Quantity, event.ReorderPoint, event.ReorderQuantity)
+	return nil
+}
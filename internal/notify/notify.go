@@ -0,0 +1,33 @@
+// Package notify доставляет уведомления о событиях склада во внешние
+// каналы: журнал приложения, вебхуки.
+package notify
+
+import (
+	"backend-store/internal/models"
+	"context"
+	"errors"
+)
+
+// Notifier доставляет уведомление о заканчивающемся товаре. Ошибка
+// означает, что уведомление не доставлено и его стоит повторить.
+type Notifier interface {
+	Notify(ctx context.Context, event models.LowStockEvent) error
+}
+
+type multiNotifier []Notifier
+
+// Multi рассылает уведомление всем notifiers и возвращает объединенную
+// ошибку тех, кому доставить не удалось.
+func Multi(notifiers ...Notifier) Notifier {
+	return multiNotifier(notifiers)
+}
+
+func (m multiNotifier) Notify(ctx context.Context, event models.LowStockEvent) error {
+	var errs []error
+	for _, n := range m {
+		if err := n.Notify(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
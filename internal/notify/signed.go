@@ -0,0 +1,134 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Заголовки подписанного вебхука. SignatureHeader имеет вид
+// "t=<unix-время>,v1=<hex HMAC-SHA256>"; подписывается строка
+// "<unix-время>.<тело запроса>", поэтому перехваченный запрос нельзя
+// переотправить позже допуска по времени.
+const (
+	SignatureHeader  = "X-Signature"
+	EventTypeHeader  = "X-Event-Type"
+	DeliveryIDHeader = "X-Delivery-ID"
+)
+
+// maxResponseBody - сколько байт ответа получателя читается.
+const maxResponseBody = 4096
+
+// Sign возвращает значение SignatureHeader для тела body, отправленного в
+// момент timestamp.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+	return "t=" + ts + ",v1=" + signature(secret, ts, body)
+}
+
+// VerifySignature проверяет значение SignatureHeader: подпись должна
+// совпадать, а время подписи отличаться от now не более чем на tolerance.
+func VerifySignature(secret, header string, body []byte, now time.Time, tolerance time.Duration) error {
+	var ts, sig string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			ts = value
+		case "v1":
+			sig = value
+		}
+	}
+	if ts == "" || sig == "" {
+		return errors.New("malformed signature header")
+	}
+
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return errors.New("malformed signature timestamp")
+	}
+	if age := now.Sub(time.Unix(unix, 0)); age > tolerance || age < -tolerance {
+		return errors.New("signature timestamp outside tolerance")
+	}
+	if !hmac.Equal([]byte(sig), []byte(signature(secret, ts, body))) {
+		return errors.New("signature mismatch")
+	}
+	return nil
+}
+
+func signature(secret, ts string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// SignedMessage - подписанный вебхук для отправки.
+type SignedMessage struct {
+	URL        string
+	Secret     string
+	EventType  string
+	DeliveryID int
+	Body       []byte
+}
+
+// SignedResponse - ответ получателя. StatusCode равен нулю, если ответа
+// не было.
+type SignedResponse struct {
+	StatusCode int
+	Body       string
+}
+
+// WebhookSender отправляет подписанные вебхуки.
+type WebhookSender struct {
+	client *http.Client
+}
+
+func NewWebhookSender(client *http.Client) *WebhookSender {
+	if client == nil {
+		client = &http.Client{Timeout: webhookTimeout}
+	}
+	return &WebhookSender{client: client}
+}
+
+// Timeout возвращает таймаут запроса; 0 означает, что он не ограничен.
+func (s *WebhookSender) Timeout() time.Duration {
+	return s.client.Timeout
+}
+
+// Send отправляет msg POST-запросом. Ответ вне диапазона 2xx считается
+// ошибкой доставки; ответ возвращается и вместе с ошибкой.
+func (s *WebhookSender) Send(ctx context.Context, msg SignedMessage) (SignedResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, msg.URL, bytes.NewReader(msg.Body))
+	if err != nil {
+		return SignedResponse{}, fmt.Errorf("failed to build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventTypeHeader, msg.EventType)
+	req.Header.Set(DeliveryIDHeader, strconv.Itoa(msg.DeliveryID))
+	req.Header.Set(SignatureHeader, Sign(msg.Secret, time.Now(), msg.Body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return SignedResponse{}, fmt.Errorf("webhook delivery failed: %w", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	io.Copy(io.Discard, resp.Body)
+
+	result := SignedResponse{StatusCode: resp.StatusCode, Body: string(body)}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return result, fmt.Errorf("webhook delivery failed: status %d", resp.StatusCode)
+	}
+	return result, nil
+}
@@ -0,0 +1,119 @@
+package notify
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecret = "0123456789abcdef0123456789abcdef"
+
+func TestWebhookSender_SignsRequest(t *testing.T) {
+	// Arrange
+	var body []byte
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		header = r.Header.Clone()
+		body, _ = io.ReadAll(r.Body)
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	sender := NewWebhookSender(server.Client())
+	msg := SignedMessage{
+		URL:        server.URL,
+		Secret:     testSecret,
+		EventType:  "order.created",
+		DeliveryID: 42,
+		Body:       []byte(`{"id":7,"type":"order.created"}`),
+	}
+
+	// Act
+	resp, err := sender.Send(context.Background(), msg)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "ok", resp.Body)
+	assert.Equal(t, msg.Body, body)
+	assert.Equal(t, "application/json", header.Get("Content-Type"))
+	assert.Equal(t, "order.created", header.Get(EventTypeHeader))
+	assert.Equal(t, "42", header.Get(DeliveryIDHeader))
+	assert.NoError(t, VerifySignature(testSecret, header.Get(SignatureHeader), body, time.Now(), time.Minute))
+}
+
+func TestWebhookSender_RejectedStatus(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte("upstream down"))
+	}))
+	defer server.Close()
+
+	sender := NewWebhookSender(server.Client())
+
+	// Act
+	resp, err := sender.Send(context.Background(), SignedMessage{URL: server.URL, Secret: testSecret, Body: []byte(`{}`)})
+
+	// Assert
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 502")
+	assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+	assert.Equal(t, "upstream down", resp.Body)
+}
+
+func TestWebhookSender_Unreachable(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	sender := NewWebhookSender(nil)
+
+	// Act
+	resp, err := sender.Send(context.Background(), SignedMessage{URL: url, Secret: testSecret, Body: []byte(`{}`)})
+
+	// Assert
+	assert.Error(t, err)
+	assert.Zero(t, resp.StatusCode)
+}
+
+func TestVerifySignature(t *testing.T) {
+	signedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	body := []byte(`{"id":1}`)
+	header := Sign(testSecret, signedAt, body)
+
+	tests := []struct {
+		name    string
+		secret  string
+		header  string
+		body    []byte
+		now     time.Time
+		wantErr string
+	}{
+		{name: "valid", secret: testSecret, header: header, body: body, now: signedAt.Add(30 * time.Second)},
+		{name: "wrong secret", secret: "another-secret-value", header: header, body: body, now: signedAt, wantErr: "signature mismatch"},
+		{name: "tampered body", secret: testSecret, header: header, body: []byte(`{"id":2}`), now: signedAt, wantErr: "signature mismatch"},
+		{name: "too old", secret: testSecret, header: header, body: body, now: signedAt.Add(10 * time.Minute), wantErr: "outside tolerance"},
+		{name: "malformed", secret: testSecret, header: "sha256=abc", body: body, now: signedAt, wantErr: "malformed signature header"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifySignature(tt.secret, tt.header, tt.body, tt.now, 5*time.Minute)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package notify
+
+import (
+	"backend-store/internal/models"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const webhookTimeout = 10 * time.Second
+
+type webhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookNotifier отправляет уведомления POST-запросом с JSON-телом
+// события на url. Ответ вне диапазона 2xx считается ошибкой доставки.
+func NewWebhookNotifier(url string, client *http.Client) Notifier {
+	if client == nil {
+		client = &http.Client{Timeout: webhookTimeout}
+	}
+	return &webhookNotifier{url: url, client: client}
+}
+
+func (n *webhookNotifier) Notify(ctx context.Context, event models.LowStockEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-Type", event.Event)
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook delivery failed: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook delivery failed: status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package notify
+
+import (
+	"backend-store/internal/models"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testEvent() models.LowStockEvent {
+	return models.LowStockEvent{
+		Event:           models.LowStockEventType,
+		AlertID:         3,
+		ProductID:       7,
+		SKU:             "TSHIRT",
+		Name:            "T-Shirt",
+		Quantity:        2,
+		ReorderPoint:    5,
+		ReorderQuantity: 20,
+		OccurredAt:      time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestWebhookNotifier_PostsEvent(t *testing.T) {
+	// Arrange
+	var received models.LowStockEvent
+	var contentType, eventType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		contentType = r.Header.Get("Content-Type")
+		eventType = r.Header.Get("X-Event-Type")
+		json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(server.URL, server.Client())
+
+	// Act
+	err := notifier.Notify(context.Background(), testEvent())
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "application/json", contentType)
+	assert.Equal(t, models.LowStockEventType, eventType)
+	assert.Equal(t, testEvent(), received)
+}
+
+func TestWebhookNotifier_RejectedStatus(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(server.URL, server.Client())
+
+	// Act
+	err := notifier.Notify(context.Background(), testEvent())
+
+	// Assert
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 503")
+}
+
+func TestWebhookNotifier_Unreachable(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	notifier := NewWebhookNotifier(url, nil)
+
+	// Act
+	err := notifier.Notify(context.Background(), testEvent())
+
+	// Assert
+	assert.Error(t, err)
+}
+
+type stubNotifier struct {
+	events []models.LowStockEvent
+	err    error
+}
+
+func (s *stubNotifier) Notify(ctx context.Context, event models.LowStockEvent) error {
+	s.events = append(s.events, event)
+	return s.err
+}
+
+func TestMulti_DeliversToAllAndJoinsErrors(t *testing.T) {
+	// Arrange
+	failing := &stubNotifier{err: errors.New("sink down")}
+	working := &stubNotifier{}
+	notifier := Multi(failing, working)
+
+	// Act
+	err := notifier.Notify(context.Background(), testEvent())
+
+	// Assert
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "sink down")
+	assert.Len(t, failing.events, 1)
+	assert.Len(t, working.events, 1)
+}
@@ -0,0 +1,260 @@
+package payment
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// FakeProvider - имя встроенного тестового шлюза.
+const FakeProvider = "fake"
+
+// Outcome - ответ тестового шлюза на очередную операцию.
+type Outcome string
+
+const (
+	Approve       Outcome = "approve"
+	Decline       Outcome = "decline"
+	Timeout       Outcome = "timeout"
+	RequireAction Outcome = "require_action"
+)
+
+// Operation - операция шлюза, для которой задается сценарий.
+type Operation string
+
+const (
+	OpAuthorize Operation = "authorize"
+	OpCapture   Operation = "capture"
+	OpVoid      Operation = "void"
+	OpRefund    Operation = "refund"
+)
+
+// Токены способа оплаты, задающие ответ Authorize без сценария. Любой
+// другой токен одобряется.
+const (
+	TokenDecline       = "tok_decline"
+	TokenTimeout       = "tok_timeout"
+	TokenRequireAction = "tok_3ds"
+)
+
+const fakeWebhookTimeout = 10 * time.Second
+
+// fakeAuthorization - состояние блокировки внутри тестового шлюза.
+type fakeAuthorization struct {
+	amount   int
+	captured int
+	refunded int
+	pending  bool
+	voided   bool
+}
+
+// Fake - платежный шлюз в памяти процесса. Ответы задаются сценарием через
+// Script или токеном способа оплаты. Блокировка, требующая подтверждения,
+// завершается вызовом Confirm, который отправляет Event на webhookURL так
+// же, как это сделал бы настоящий провайдер.
+type Fake struct {
+	webhookURL string
+	actionURL  string
+	secret     string
+	client     *http.Client
+
+	mu     sync.Mutex
+	script map[Operation][]Outcome
+	auths  map[string]*fakeAuthorization
+	seq    int
+}
+
+// NewFake создает тестовый шлюз. Ссылка подтверждения строится как
+// actionURL + reference + "/confirm".
+func NewFake(webhookURL, actionURL, secret string, client *http.Client) *Fake {
+	if client == nil {
+		client = &http.Client{Timeout: fakeWebhookTimeout}
+	}
+	return &Fake{
+		webhookURL: webhookURL,
+		actionURL:  actionURL,
+		secret:     secret,
+		client:     client,
+		script:     make(map[Operation][]Outcome),
+		auths:      make(map[string]*fakeAuthorization),
+	}
+}
+
+func (f *Fake) Name() string {
+	return FakeProvider
+}
+
+// Script ставит ответы на следующие вызовы операции op в порядке очереди.
+// RequireAction имеет смысл только для OpAuthorize.
+func (f *Fake) Script(op Operation, outcomes ...Outcome) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.script[op] = append(f.script[op], outcomes...)
+}
+
+func (f *Fake) next(op Operation) (Outcome, bool) {
+	queue := f.script[op]
+	if len(queue) == 0 {
+		return Approve, false
+	}
+	f.script[op] = queue[1:]
+	return queue[0], true
+}
+
+func (f *Fake) Authorize(ctx context.Context, req AuthorizeRequest) (Authorization, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	outcome, scripted := f.next(OpAuthorize)
+	if !scripted {
+		switch req.Method {
+		case TokenDecline:
+			outcome = Decline
+		case TokenTimeout:
+			outcome = Timeout
+		case TokenRequireAction:
+			outcome = RequireAction
+		}
+	}
+
+	switch outcome {
+	case Decline:
+		return Authorization{}, fmt.Errorf("%w: insufficient funds", ErrDeclined)
+	case Timeout:
+		return Authorization{}, ErrTimeout
+	}
+
+	f.seq++
+	reference := fmt.Sprintf("fake_auth_%d", f.seq)
+	f.auths[reference] = &fakeAuthorization{amount: req.Amount, pending: outcome == RequireAction}
+	if outcome == RequireAction {
+		return Authorization{
+			Reference:      reference,
+			RequiresAction: true,
+			ActionURL:      f.actionURL + reference + "/confirm",
+		}, nil
+	}
+	return Authorization{Reference: reference}, nil
+}
+
+func (f *Fake) Capture(ctx context.Context, reference string, amount int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	auth, err := f.operate(OpCapture, reference)
+	if err != nil {
+		return err
+	}
+	if auth.captured+amount > auth.amount {
+		return fmt.Errorf("%w: capture exceeds authorized amount", ErrDeclined)
+	}
+	auth.captured += amount
+	return nil
+}
+
+func (f *Fake) Void(ctx context.Context, reference string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	auth, err := f.operate(OpVoid, reference)
+	if err != nil {
+		return err
+	}
+	if auth.captured > 0 {
+		return fmt.Errorf("%w: authorization already captured", ErrDeclined)
+	}
+	auth.voided = true
+	return nil
+}
+
+func (f *Fake) Refund(ctx context.Context, reference string, amount int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	auth, err := f.operate(OpRefund, reference)
+	if err != nil {
+		return err
+	}
+	if auth.refunded+amount > auth.captured {
+		return fmt.Errorf("%w: refund exceeds captured amount", ErrDeclined)
+	}
+	auth.refunded += amount
+	return nil
+}
+
+// operate применяет сценарий операции и проверяет, что блокировка
+// существует, подтверждена и не отменена.
+func (f *Fake) operate(op Operation, reference string) (*fakeAuthorization, error) {
+	auth, exists := f.auths[reference]
+	if !exists {
+		return nil, fmt.Errorf("%w: unknown authorization %q", ErrDeclined, reference)
+	}
+	switch outcome, _ := f.next(op); outcome {
+	case Decline:
+		return nil, fmt.Errorf("%w: %s rejected", ErrDeclined, op)
+	case Timeout:
+		return nil, ErrTimeout
+	}
+	if auth.pending {
+		return nil, fmt.Errorf("%w: authorization requires action", ErrDeclined)
+	}
+	if auth.voided {
+		return nil, fmt.Errorf("%w: authorization voided", ErrDeclined)
+	}
+	return auth, nil
+}
+
+// Confirm завершает подтверждение блокировки и отправляет итог на
+// webhookURL. Ошибка доставки возвращается, состояние шлюза при этом уже
+// изменено, поэтому повторный Confirm невозможен.
+func (f *Fake) Confirm(ctx context.Context, reference string, approved bool) error {
+	f.mu.Lock()
+	auth, exists := f.auths[reference]
+	if !exists || !auth.pending {
+		f.mu.Unlock()
+		return errors.New("authorization not found or already confirmed")
+	}
+	auth.pending = false
+	event := Event{Reference: reference, Status: EventAuthorized}
+	if !approved {
+		auth.voided = true
+		event = Event{Reference: reference, Status: EventFailed, Reason: "authentication failed"}
+	}
+	f.mu.Unlock()
+
+	return f.deliver(ctx, event)
+}
+
+func (f *Fake) deliver(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if f.secret != "" {
+		req.Header.Set(WebhookSecretHeader, f.secret)
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook delivery failed: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook delivery failed: status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package payment
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFake_AuthorizeByToken(t *testing.T) {
+	// Arrange
+	fake := NewFake("", "http://shop/api/payment/fake/", "", nil)
+	ctx := context.Background()
+
+	// Act
+	approved, approveErr := fake.Authorize(ctx, AuthorizeRequest{OrderID: 1, Amount: 100, Method: "tok_visa"})
+	_, declineErr := fake.Authorize(ctx, AuthorizeRequest{OrderID: 1, Amount: 100, Method: TokenDecline})
+	_, timeoutErr := fake.Authorize(ctx, AuthorizeRequest{OrderID: 1, Amount: 100, Method: TokenTimeout})
+	pending, actionErr := fake.Authorize(ctx, AuthorizeRequest{OrderID: 1, Amount: 100, Method: TokenRequireAction})
+
+	// Assert
+	require.NoError(t, approveErr)
+	assert.False(t, approved.RequiresAction)
+	assert.ErrorIs(t, declineErr, ErrDeclined)
+	assert.ErrorIs(t, timeoutErr, ErrTimeout)
+	require.NoError(t, actionErr)
+	assert.True(t, pending.RequiresAction)
+	assert.Equal(t, "http://shop/api/payment/fake/"+pending.Reference+"/confirm", pending.ActionURL)
+}
+
+func TestFake_ScriptOverridesToken(t *testing.T) {
+	// Arrange
+	fake := NewFake("", "", "", nil)
+	fake.Script(OpAuthorize, Decline)
+	fake.Script(OpCapture, Timeout)
+	ctx := context.Background()
+
+	// Act
+	_, firstErr := fake.Authorize(ctx, AuthorizeRequest{Amount: 100, Method: "tok_visa"})
+	auth, secondErr := fake.Authorize(ctx, AuthorizeRequest{Amount: 100, Method: "tok_visa"})
+	captureErr := fake.Capture(ctx, auth.Reference, 100)
+	retryErr := fake.Capture(ctx, auth.Reference, 100)
+
+	// Assert
+	assert.ErrorIs(t, firstErr, ErrDeclined)
+	require.NoError(t, secondErr)
+	assert.ErrorIs(t, captureErr, ErrTimeout)
+	assert.NoError(t, retryErr)
+}
+
+func TestFake_CaptureRefundLimits(t *testing.T) {
+	// Arrange
+	fake := NewFake("", "", "", nil)
+	ctx := context.Background()
+	auth, err := fake.Authorize(ctx, AuthorizeRequest{Amount: 100, Method: "tok_visa"})
+	require.NoError(t, err)
+
+	// Act & Assert
+	assert.ErrorIs(t, fake.Capture(ctx, auth.Reference, 150), ErrDeclined)
+	require.NoError(t, fake.Capture(ctx, auth.Reference, 100))
+	assert.ErrorIs(t, fake.Void(ctx, auth.Reference), ErrDeclined)
+	require.NoError(t, fake.Refund(ctx, auth.Reference, 60))
+	assert.ErrorIs(t, fake.Refund(ctx, auth.Reference, 50), ErrDeclined)
+	assert.NoError(t, fake.Refund(ctx, auth.Reference, 40))
+}
+
+func TestFake_ConfirmDeliversEvent(t *testing.T) {
+	// Arrange
+	var received Event
+	var secret string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		secret = r.Header.Get(WebhookSecretHeader)
+		json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	fake := NewFake(server.URL, "", "s3cret", server.Client())
+	ctx := context.Background()
+	auth, err := fake.Authorize(ctx, AuthorizeRequest{Amount: 100, Method: TokenRequireAction})
+	require.NoError(t, err)
+	require.ErrorIs(t, fake.Capture(ctx, auth.Reference, 100), ErrDeclined)
+
+	// Act
+	err = fake.Confirm(ctx, auth.Reference, true)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", secret)
+	assert.Equal(t, Event{Reference: auth.Reference, Status: EventAuthorized}, received)
+	assert.NoError(t, fake.Capture(ctx, auth.Reference, 100))
+	assert.Error(t, fake.Confirm(ctx, auth.Reference, true))
+}
+
+func TestFake_ConfirmRejectedVoidsAuthorization(t *testing.T) {
+	// Arrange
+	var received Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	fake := NewFake(server.URL, "", "", server.Client())
+	ctx := context.Background()
+	auth, err := fake.Authorize(ctx, AuthorizeRequest{Amount: 100, Method: TokenRequireAction})
+	require.NoError(t, err)
+
+	// Act
+	err = fake.Confirm(ctx, auth.Reference, false)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, EventFailed, received.Status)
+	assert.ErrorIs(t, fake.Capture(ctx, auth.Reference, 100), ErrDeclined)
+}
@@ -0,0 +1,58 @@
+// Package payment описывает платежный шлюз и содержит встроенный тестовый
+// шлюз со сценариями ответов.
+package payment
+
+import (
+	"context"
+	"errors"
+)
+
+// Ошибки шлюза. Конкретная причина отказа оборачивается в ErrDeclined.
+var (
+	ErrDeclined = errors.New("payment declined")
+	ErrTimeout  = errors.New("payment gateway timeout")
+)
+
+// AuthorizeRequest - запрос на блокировку суммы заказа. Method - токен
+// способа оплаты, выданный провайдером клиенту.
+type AuthorizeRequest struct {
+	OrderID int
+	Amount  int
+	Method  string
+}
+
+// Authorization - результат блокировки. Если RequiresAction, покупатель
+// должен пройти подтверждение (3-D Secure) по ActionURL, а итог придет
+// асинхронно событием Event.
+type Authorization struct {
+	Reference      string
+	RequiresAction bool
+	ActionURL      string
+}
+
+// Gateway - платежный провайдер. Операции после Authorize адресуются по
+// Reference блокировки.
+type Gateway interface {
+	Name() string
+	Authorize(ctx context.Context, req AuthorizeRequest) (Authorization, error)
+	Capture(ctx context.Context, reference string, amount int) error
+	Void(ctx context.Context, reference string) error
+	Refund(ctx context.Context, reference string, amount int) error
+}
+
+// Статусы асинхронного подтверждения блокировки.
+const (
+	EventAuthorized = "authorized"
+	EventFailed     = "failed"
+)
+
+// Event - уведомление провайдера об итоге подтверждения блокировки.
+type Event struct {
+	Reference string `json:"reference"`
+	Status    string `json:"status"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// WebhookSecretHeader - заголовок с общим секретом, которым провайдер
+// подписывает уведомления.
+const WebhookSecretHeader = "X-Webhook-Secret"
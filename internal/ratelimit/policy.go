@@ -0,0 +1,93 @@
+package ratelimit
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Policy - лимит для маршрутов. Method - HTTP-метод или "*". Route -
+// шаблон маршрута gin ("/api/order/:id") или префикс со звездочкой в конце
+// ("/api/*").
+type Policy struct {
+	Method string
+	Route  string
+	Limit  Limit
+}
+
+// Name - имя политики в ключах хранилища и метриках.
+func (p Policy) Name() string {
+	return p.Method + " " + p.Route
+}
+
+func (p Policy) Match(method, route string) bool {
+	if p.Method != "*" && !strings.EqualFold(p.Method, method) {
+		return false
+	}
+	if prefix, ok := strings.CutSuffix(p.Route, "*"); ok {
+		return strings.HasPrefix(route, prefix)
+	}
+	return p.Route == route
+}
+
+// ParsePolicies разбирает политики из строки конфигурации: записи
+// "METHOD ROUTE,requests,period[,burst]", разделенные точкой с запятой,
+// например "POST /api/order/,30,1m,10;* /api/*,600,1m". Порядок важен:
+// к запросу применяется первая подходящая политика.
+func ParsePolicies(s string) ([]Policy, error) {
+	reader := csv.NewReader(strings.NewReader(strings.ReplaceAll(s, ";", "\n")))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var policies []Policy
+	for n := 1; ; n++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate limits: %w", err)
+		}
+
+		policy, err := parsePolicy(record)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate limit %d: %w", n, err)
+		}
+		policies = append(policies, policy)
+	}
+	return policies, nil
+}
+
+func parsePolicy(record []string) (Policy, error) {
+	if len(record) != 3 && len(record) != 4 {
+		return Policy{}, errors.New(`expected "METHOD ROUTE,requests,period[,burst]"`)
+	}
+
+	method, route, ok := strings.Cut(strings.TrimSpace(record[0]), " ")
+	route = strings.TrimSpace(route)
+	if !ok || method == "" || !strings.HasPrefix(route, "/") {
+		return Policy{}, fmt.Errorf("route %q must be a method and a path", record[0])
+	}
+
+	requests, err := strconv.Atoi(strings.TrimSpace(record[1]))
+	if err != nil || requests <= 0 {
+		return Policy{}, fmt.Errorf("requests %q must be a positive number", record[1])
+	}
+	period, err := time.ParseDuration(strings.TrimSpace(record[2]))
+	if err != nil || period <= 0 {
+		return Policy{}, fmt.Errorf("period %q must be a positive duration", record[2])
+	}
+
+	limit := Limit{Requests: requests, Period: period}
+	if len(record) == 4 {
+		limit.Burst, err = strconv.Atoi(strings.TrimSpace(record[3]))
+		if err != nil || limit.Burst <= 0 {
+			return Policy{}, fmt.Errorf("burst %q must be a positive number", record[3])
+		}
+	}
+	return Policy{Method: strings.ToUpper(method), Route: route, Limit: limit}, nil
+}
@@ -0,0 +1,101 @@
+// Package ratelimit ограничивает частоту запросов клиентов по алгоритму
+// token bucket с политиками для отдельных маршрутов.
+package ratelimit
+
+import (
+	"context"
+	"expvar"
+	"math"
+	"time"
+)
+
+// Limit - Requests запросов за Period в среднем и не больше Burst подряд.
+// Нулевой Burst равен Requests.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+	Burst    int
+}
+
+func (l Limit) burst() int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+	return l.Requests
+}
+
+// rate - число токенов, восстанавливаемых за секунду.
+func (l Limit) rate() float64 {
+	return float64(l.Requests) / l.Period.Seconds()
+}
+
+// Result - итог попытки запроса. Reset - через сколько корзина клиента
+// снова заполнится, RetryAfter - через сколько появится токен, если
+// запрос отклонен.
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+// Store хранит корзины токенов. MemoryStore подходит для одного
+// экземпляра сервиса; общие для нескольких экземпляров лимиты требуют
+// внешнего хранилища с той же семантикой Take.
+type Store interface {
+	// Take забирает токен из корзины key, восстановив ее на момент now.
+	Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error)
+}
+
+// Метрики публикуются через expvar: число отклоненных запросов по
+// политикам и число ошибок хранилища (запрос при этом пропускается).
+var (
+	throttledRequests = expvar.NewMap("ratelimit_throttled")
+	storeErrors       = expvar.NewInt("ratelimit_store_errors")
+)
+
+// Limiter применяет к запросу первую подходящую политику.
+type Limiter struct {
+	policies []Policy
+	store    Store
+	now      func() time.Time
+}
+
+func NewLimiter(policies []Policy, store Store) *Limiter {
+	return &Limiter{policies: policies, store: store, now: time.Now}
+}
+
+// Allow учитывает запрос клиента key к маршруту route. Если ни одна
+// политика не подходит, policy - nil и запрос не ограничивается. Ошибка
+// хранилища не блокирует запрос.
+func (l *Limiter) Allow(ctx context.Context, method, route, key string) (Result, *Policy, error) {
+	policy := l.match(method, route)
+	if policy == nil {
+		return Result{Allowed: true}, nil, nil
+	}
+
+	result, err := l.store.Take(ctx, policy.Name()+"|"+key, policy.Limit, l.now())
+	if err != nil {
+		storeErrors.Add(1)
+		return Result{Allowed: true}, policy, err
+	}
+	if !result.Allowed {
+		throttledRequests.Add(policy.Name(), 1)
+	}
+	return result, policy, nil
+}
+
+func (l *Limiter) match(method, route string) *Policy {
+	for i := range l.policies {
+		if l.policies[i].Match(method, route) {
+			return &l.policies[i]
+		}
+	}
+	return nil
+}
+
+// Seconds округляет d вверх до целых секунд для заголовков ответа.
+func Seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,148 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore_TokenBucket(t *testing.T) {
+	// Arrange
+	store := NewMemoryStore()
+	limit := Limit{Requests: 60, Period: time.Minute, Burst: 2}
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	// Act
+	first, _ := store.Take(ctx, "k", limit, now)
+	second, _ := store.Take(ctx, "k", limit, now)
+	denied, _ := store.Take(ctx, "k", limit, now.Add(500*time.Millisecond))
+	other, _ := store.Take(ctx, "other", limit, now)
+	refilled, err := store.Take(ctx, "k", limit, now.Add(time.Second))
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, Result{Allowed: true, Limit: 2, Remaining: 1, Reset: time.Second}, first)
+	assert.Equal(t, Result{Allowed: true, Limit: 2, Remaining: 0, Reset: 2 * time.Second}, second)
+	assert.False(t, denied.Allowed)
+	assert.Equal(t, 500*time.Millisecond, denied.RetryAfter)
+	assert.True(t, other.Allowed)
+	assert.True(t, refilled.Allowed)
+	assert.Equal(t, 0, refilled.Remaining)
+}
+
+func TestMemoryStore_SweepsFullBuckets(t *testing.T) {
+	store := NewMemoryStore()
+	limit := Limit{Requests: 10, Period: time.Second}
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	store.Take(context.Background(), "a", limit, now)
+	store.Take(context.Background(), "b", limit, now.Add(2*sweepInterval))
+
+	assert.Len(t, store.buckets, 1)
+	assert.Contains(t, store.buckets, "b")
+}
+
+func TestParsePolicies(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []Policy
+		wantErr  bool
+	}{
+		{name: "empty", input: ""},
+		{
+			name:  "several",
+			input: "post /api/order/,30,1m,10; * /api/*,600,1m;",
+			expected: []Policy{
+				{Method: "POST", Route: "/api/order/", Limit: Limit{Requests: 30, Period: time.Minute, Burst: 10}},
+				{Method: "*", Route: "/api/*", Limit: Limit{Requests: 600, Period: time.Minute}},
+			},
+		},
+		{name: "no method", input: "/api/order/,30,1m", wantErr: true},
+		{name: "invalid requests", input: "POST /api/order/,0,1m", wantErr: true},
+		{name: "invalid period", input: "POST /api/order/,30,minute", wantErr: true},
+		{name: "invalid burst", input: "POST /api/order/,30,1m,-1", wantErr: true},
+		{name: "missing fields", input: "POST /api/order/,30", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policies, err := ParsePolicies(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, policies)
+		})
+	}
+}
+
+func TestPolicy_Match(t *testing.T) {
+	exact := Policy{Method: "POST", Route: "/api/order/"}
+	prefix := Policy{Method: "*", Route: "/api/product/*"}
+
+	assert.True(t, exact.Match("POST", "/api/order/"))
+	assert.False(t, exact.Match("GET", "/api/order/"))
+	assert.False(t, exact.Match("POST", "/api/order/:id"))
+	assert.True(t, prefix.Match("DELETE", "/api/product/:id"))
+	assert.False(t, prefix.Match("GET", "/api/order/"))
+}
+
+type failingStore struct{}
+
+func (failingStore) Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error) {
+	return Result{}, errors.New("store unavailable")
+}
+
+func TestLimiter_Allow(t *testing.T) {
+	// Arrange
+	policies := []Policy{
+		{Method: "POST", Route: "/api/order/", Limit: Limit{Requests: 1, Period: time.Hour}},
+		{Method: "*", Route: "/api/*", Limit: Limit{Requests: 100, Period: time.Hour}},
+	}
+	limiter := NewLimiter(policies, NewMemoryStore())
+	ctx := context.Background()
+	throttledBefore := counter(throttledRequests.Get("POST /api/order/"))
+
+	// Act
+	first, policy, _ := limiter.Allow(ctx, "POST", "/api/order/", "ip:1")
+	second, _, _ := limiter.Allow(ctx, "POST", "/api/order/", "ip:1")
+	otherClient, _, _ := limiter.Allow(ctx, "POST", "/api/order/", "ip:2")
+	general, generalPolicy, _ := limiter.Allow(ctx, "GET", "/api/order/", "ip:1")
+	_, none, _ := limiter.Allow(ctx, "GET", "/health", "ip:1")
+
+	// Assert
+	assert.True(t, first.Allowed)
+	assert.Equal(t, &policies[0], policy)
+	assert.False(t, second.Allowed)
+	assert.True(t, otherClient.Allowed)
+	assert.True(t, general.Allowed)
+	assert.Equal(t, &policies[1], generalPolicy)
+	assert.Nil(t, none)
+	assert.Equal(t, throttledBefore+1, counter(throttledRequests.Get("POST /api/order/")))
+}
+
+func TestLimiter_StoreErrorAllows(t *testing.T) {
+	limiter := NewLimiter([]Policy{{Method: "*", Route: "/*", Limit: Limit{Requests: 1, Period: time.Second}}}, failingStore{})
+	errorsBefore := storeErrors.Value()
+
+	result, _, err := limiter.Allow(context.Background(), "GET", "/", "ip:1")
+
+	assert.Error(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, errorsBefore+1, storeErrors.Value())
+}
+
+func counter(v expvar.Var) int64 {
+	if v == nil {
+		return 0
+	}
+	return v.(*expvar.Int).Value()
+}
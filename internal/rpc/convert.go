@@ -0,0 +1,150 @@
+package rpc
+
+import (
+	storev1 "backend-store/api/proto/store/v1"
+	"backend-store/internal/models"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func toProto(p *models.Product) *storev1.Product {
+	result := &storev1.Product{
+		Id:              int64(p.ID),
+		Sku:             p.SKU,
+		Name:            p.Name,
+		Description:     p.Description,
+		Price:           p.Price,
+		Quantity:        int64(p.Quantity),
+		ReorderPoint:    int64(p.ReorderPoint),
+		ReorderQuantity: int64(p.ReorderQuantity),
+		TaxClass:        p.TaxClass,
+		Weight:          int64(p.Weight),
+		Length:          int64(p.Length),
+		Width:           int64(p.Width),
+		Height:          int64(p.Height),
+		Tags:            p.Tags,
+		CreatedAt:       timestamp(p.CreatedAt),
+		UpdatedAt:       timestamp(p.UpdatedAt),
+	}
+	for _, id := range p.CategoryIDs {
+		result.CategoryIds = append(result.CategoryIds, int64(id))
+	}
+	for _, v := range p.Variants {
+		result.Variants = append(result.Variants, &storev1.ProductVariant{
+			Id:       int64(v.ID),
+			Sku:      v.SKU,
+			Options:  v.Options,
+			Price:    v.Price,
+			Quantity: int64(v.Quantity),
+		})
+	}
+	return result
+}
+
+// fromProto переносит в товар поля, которые клиент может задать; ID,
+// остаток складов и даты заполняет сервис.
+func fromProto(p *storev1.Product) *models.Product {
+	result := &models.Product{
+		ID:              int(p.GetId()),
+		SKU:             p.GetSku(),
+		Name:            p.GetName(),
+		Description:     p.GetDescription(),
+		Price:           p.GetPrice(),
+		Quantity:        int(p.GetQuantity()),
+		ReorderPoint:    int(p.GetReorderPoint()),
+		ReorderQuantity: int(p.GetReorderQuantity()),
+		TaxClass:        p.GetTaxClass(),
+		Weight:          int(p.GetWeight()),
+		Length:          int(p.GetLength()),
+		Width:           int(p.GetWidth()),
+		Height:          int(p.GetHeight()),
+		Tags:            p.GetTags(),
+	}
+	for _, id := range p.GetCategoryIds() {
+		result.CategoryIDs = append(result.CategoryIDs, int(id))
+	}
+	return result
+}
+
+func orderToProto(o *models.Order) *storev1.Order {
+	result := &storev1.Order{
+		Id:             int64(o.ID),
+		CustomerId:     int64(o.CustomerID),
+		Status:         o.Status,
+		PromoCode:      o.PromoCode,
+		ShippingMethod: o.ShippingMethod,
+		Subtotal:       int64(o.Subtotal),
+		Discount:       int64(o.Discount),
+		Tax:            int64(o.Tax),
+		TaxInclusive:   o.TaxInclusive,
+		Shipping:       int64(o.Shipping),
+		FreeShipping:   o.FreeShipping,
+		Total:          int64(o.Total),
+		CreatedAt:      timestamp(o.CreatedAt),
+		UpdatedAt:      timestamp(o.UpdatedAt),
+	}
+	for _, item := range o.Products {
+		result.Items = append(result.Items, &storev1.OrderItem{
+			Id:        int64(item.ID),
+			ProductId: int64(item.ProductID),
+			VariantId: int64(item.VariantID),
+			Quantity:  int64(item.Quantity),
+			Price:     int64(item.Price),
+			TaxClass:  item.TaxClass,
+			Tax:       int64(item.Tax),
+		})
+	}
+	if a := o.ShippingAddress; a != nil {
+		result.ShippingAddress = &storev1.Address{
+			Line1:      a.Line1,
+			Line2:      a.Line2,
+			City:       a.City,
+			Region:     a.Region,
+			PostalCode: a.PostalCode,
+			Country:    a.Country,
+			Latitude:   a.Latitude,
+			Longitude:  a.Longitude,
+		}
+	}
+	return result
+}
+
+// orderFromProto переносит поля заказа, которые принимает REST API при
+// создании и изменении; суммы рассчитывает сервис.
+func orderFromProto(o *storev1.Order) *models.Order {
+	result := &models.Order{
+		ID:             int(o.GetId()),
+		CustomerID:     int(o.GetCustomerId()),
+		Status:         o.GetStatus(),
+		PromoCode:      o.GetPromoCode(),
+		ShippingMethod: o.GetShippingMethod(),
+	}
+	for _, item := range o.GetItems() {
+		result.Products = append(result.Products, models.OrderItem{
+			ProductID: int(item.GetProductId()),
+			VariantID: int(item.GetVariantId()),
+			Quantity:  int(item.GetQuantity()),
+		})
+	}
+	if a := o.GetShippingAddress(); a != nil {
+		result.ShippingAddress = &models.Address{
+			Line1:      a.GetLine1(),
+			Line2:      a.GetLine2(),
+			City:       a.GetCity(),
+			Region:     a.GetRegion(),
+			PostalCode: a.GetPostalCode(),
+			Country:    a.GetCountry(),
+			Latitude:   a.Latitude,
+			Longitude:  a.Longitude,
+		}
+	}
+	return result
+}
@@ -0,0 +1,44 @@
+package rpc
+
+import (
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// toStatus переводит ошибку сервиса в статус gRPC по тем же признакам, по
+// которым REST API выбирает код ответа. Неизвестные ошибки возвращаются как
+// Internal с префиксом failed.
+func toStatus(err error, failed string) error {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "validate"), strings.Contains(msg, "invalid"):
+		return status.Error(codes.InvalidArgument, msg)
+	case strings.Contains(msg, "already exists"):
+		return status.Error(codes.AlreadyExists, msg)
+	case strings.Contains(msg, "not found"):
+		return status.Error(codes.NotFound, msg)
+	case strings.Contains(msg, "insufficient quantity"), strings.Contains(msg, "cannot"):
+		return status.Error(codes.FailedPrecondition, msg)
+	default:
+		return status.Error(codes.Internal, failed+": "+msg)
+	}
+}
+
+// orderStatus - toStatus для заказов: ненайденный товар или покупатель
+// заказа - ошибка запроса, а не отсутствие самого заказа.
+func orderStatus(err error, failed string) error {
+	msg := err.Error()
+	if (strings.Contains(msg, "product") || strings.Contains(msg, "customer")) && strings.Contains(msg, "not found") {
+		return status.Error(codes.InvalidArgument, msg)
+	}
+	return toStatus(err, failed)
+}
+
+func invalidID(entity string, id int64) error {
+	if id <= 0 {
+		return status.Errorf(codes.InvalidArgument, "invalid %s ID", entity)
+	}
+	return nil
+}
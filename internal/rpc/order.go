@@ -0,0 +1,81 @@
+package rpc
+
+import (
+	storev1 "backend-store/api/proto/store/v1"
+	"backend-store/internal/service"
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type orderServer struct {
+	storev1.UnimplementedOrderServiceServer
+	orderService service.OrderService
+}
+
+func (s *orderServer) CreateOrder(ctx context.Context, req *storev1.CreateOrderRequest) (*storev1.Order, error) {
+	if req.GetOrder() == nil {
+		return nil, status.Error(codes.InvalidArgument, "order is required")
+	}
+	order := orderFromProto(req.GetOrder())
+	order.ID = 0
+
+	if err := s.orderService.CreateOrder(ctx, order); err != nil {
+		return nil, orderStatus(err, "failed to create order")
+	}
+	return orderToProto(order), nil
+}
+
+func (s *orderServer) GetOrder(ctx context.Context, req *storev1.GetOrderRequest) (*storev1.Order, error) {
+	if err := invalidID("order", req.GetId()); err != nil {
+		return nil, err
+	}
+
+	order, err := s.orderService.GetOrderByID(ctx, int(req.GetId()))
+	if err != nil {
+		return nil, toStatus(err, "failed to fetch order")
+	}
+	return orderToProto(order), nil
+}
+
+func (s *orderServer) ListOrders(req *storev1.ListOrdersRequest, stream storev1.OrderService_ListOrdersServer) error {
+	orders, err := s.orderService.GetAllOrders(stream.Context())
+	if err != nil {
+		return toStatus(err, "failed to fetch orders")
+	}
+
+	for _, order := range orders {
+		if err := stream.Send(orderToProto(order)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *orderServer) UpdateOrder(ctx context.Context, req *storev1.UpdateOrderRequest) (*storev1.Order, error) {
+	if req.GetOrder() == nil {
+		return nil, status.Error(codes.InvalidArgument, "order is required")
+	}
+	if err := invalidID("order", req.GetOrder().GetId()); err != nil {
+		return nil, err
+	}
+
+	order := orderFromProto(req.GetOrder())
+	if err := s.orderService.UpdateOrder(ctx, order); err != nil {
+		return nil, orderStatus(err, "failed to update order")
+	}
+	return orderToProto(order), nil
+}
+
+func (s *orderServer) DeleteOrder(ctx context.Context, req *storev1.DeleteOrderRequest) (*emptypb.Empty, error) {
+	if err := invalidID("order", req.GetId()); err != nil {
+		return nil, err
+	}
+
+	if err := s.orderService.DeleteOrder(ctx, int(req.GetId())); err != nil {
+		return nil, toStatus(err, "failed to delete order")
+	}
+	return &emptypb.Empty{}, nil
+}
@@ -0,0 +1,96 @@
+package rpc
+
+import (
+	storev1 "backend-store/api/proto/store/v1"
+	"backend-store/internal/models"
+	"backend-store/internal/service"
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type productServer struct {
+	storev1.UnimplementedProductServiceServer
+	productService service.ProductService
+}
+
+func (s *productServer) CreateProduct(ctx context.Context, req *storev1.CreateProductRequest) (*storev1.Product, error) {
+	if req.GetProduct() == nil {
+		return nil, status.Error(codes.InvalidArgument, "product is required")
+	}
+	product := fromProto(req.GetProduct())
+	product.ID = 0
+
+	if err := s.productService.CreateProduct(ctx, product); err != nil {
+		return nil, toStatus(err, "failed to create product")
+	}
+	return toProto(product), nil
+}
+
+func (s *productServer) GetProduct(ctx context.Context, req *storev1.GetProductRequest) (*storev1.Product, error) {
+	if err := invalidID("product", req.GetId()); err != nil {
+		return nil, err
+	}
+
+	product, err := s.productService.GetProductByID(ctx, int(req.GetId()))
+	if err != nil {
+		return nil, toStatus(err, "failed to fetch product")
+	}
+	return toProto(product), nil
+}
+
+func (s *productServer) ListProducts(req *storev1.ListProductsRequest, stream storev1.ProductService_ListProductsServer) error {
+	filter := models.ProductFilter{CategoryID: int(req.GetCategoryId()), Tag: req.GetTag()}
+	products, err := s.productService.GetAllProducts(stream.Context(), filter)
+	if err != nil {
+		return toStatus(err, "failed to fetch products")
+	}
+
+	for _, product := range products {
+		if err := stream.Send(toProto(product)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *productServer) UpdateProduct(ctx context.Context, req *storev1.UpdateProductRequest) (*storev1.Product, error) {
+	if req.GetProduct() == nil {
+		return nil, status.Error(codes.InvalidArgument, "product is required")
+	}
+	if err := invalidID("product", req.GetProduct().GetId()); err != nil {
+		return nil, err
+	}
+
+	product := fromProto(req.GetProduct())
+	switch {
+	case !req.GetUpdateCategoryIds():
+		product.CategoryIDs = nil
+	case product.CategoryIDs == nil:
+		product.CategoryIDs = []int{}
+	}
+	switch {
+	case !req.GetUpdateTags():
+		product.Tags = nil
+	case product.Tags == nil:
+		product.Tags = []string{}
+	}
+
+	if err := s.productService.UpdateProduct(ctx, product); err != nil {
+		return nil, toStatus(err, "failed to update product")
+	}
+	return toProto(product), nil
+}
+
+func (s *productServer) DeleteProduct(ctx context.Context, req *storev1.DeleteProductRequest) (*emptypb.Empty, error) {
+	if err := invalidID("product", req.GetId()); err != nil {
+		return nil, err
+	}
+
+	if err := s.productService.DeleteProduct(ctx, int(req.GetId())); err != nil {
+		return nil, toStatus(err, "failed to delete product")
+	}
+	return &emptypb.Empty{}, nil
+}
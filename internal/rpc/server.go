@@ -0,0 +1,149 @@
+package rpc
+
+import (
+	storev1 "backend-store/api/proto/store/v1"
+	"backend-store/internal/service"
+	"backend-store/pkg/logger"
+	"context"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+// ActorMetadata - ключ метаданных с инициатором изменений, аналог
+// заголовка X-Actor REST API; имя так же записывается как неподтвержденное.
+const ActorMetadata = "x-actor"
+
+// RequestIDMetadata - ключ метаданных с ID запроса для журнала аудита,
+// аналог заголовка X-Request-ID REST API.
+const RequestIDMetadata = "x-request-id"
+
+const (
+	defaultActor       = "grpc"
+	maxActorLength     = 100
+	maxRequestIDLength = 128
+)
+
+// Server - gRPC API товаров и заказов поверх тех же сервисов, что и REST
+// API. Кроме сервисов магазина регистрирует grpc.health.v1 и reflection.
+type Server struct {
+	server *grpc.Server
+	health *health.Server
+	log    logger.Log
+}
+
+func NewServer(productService service.ProductService, orderService service.OrderService, log logger.Log) *Server {
+	s := &Server{health: health.NewServer(), log: log}
+	s.server = grpc.NewServer(
+		grpc.ChainUnaryInterceptor(s.recoverUnary, actorUnary),
+		grpc.ChainStreamInterceptor(s.recoverStream, actorStream),
+	)
+
+	storev1.RegisterProductServiceServer(s.server, &productServer{productService: productService})
+	storev1.RegisterOrderServiceServer(s.server, &orderServer{orderService: orderService})
+	healthpb.RegisterHealthServer(s.server, s.health)
+	reflection.Register(s.server)
+
+	for name := range s.server.GetServiceInfo() {
+		s.health.SetServingStatus(name, healthpb.HealthCheckResponse_SERVING)
+	}
+	return s
+}
+
+// Serve принимает соединения до вызова Shutdown.
+func (s *Server) Serve(lis net.Listener) error {
+	return s.server.Serve(lis)
+}
+
+// Shutdown переводит health-check в NOT_SERVING и ждет завершения текущих
+// вызовов. Если ctx истекает раньше, оставшиеся вызовы и потоки
+// прерываются и возвращается ошибка ctx.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.health.Shutdown()
+
+	done := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.server.Stop()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// actorContext переносит инициатора, ID запроса и адрес клиента в контекст
+// по тем же правилам, что и handlers.Actor и handlers.RequestID. ID запроса
+// не генерируется: в журнал аудита попадает только переданный клиентом.
+func actorContext(ctx context.Context) context.Context {
+	ctx = service.WithActor(ctx, defaultActor)
+	if values := metadata.ValueFromIncomingContext(ctx, ActorMetadata); len(values) > 0 && values[0] != "" {
+		actor := values[0]
+		if len(actor) > maxActorLength {
+			actor = actor[:maxActorLength]
+		}
+		ctx = service.WithClaimedActor(ctx, actor)
+	}
+
+	var requestID, ip string
+	if values := metadata.ValueFromIncomingContext(ctx, RequestIDMetadata); len(values) > 0 && len(values[0]) <= maxRequestIDLength {
+		requestID = values[0]
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		ip = p.Addr.String()
+		if host, _, err := net.SplitHostPort(ip); err == nil {
+			ip = host
+		}
+	}
+
+	return service.WithRequest(ctx, requestID, ip)
+}
+
+func actorUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	return handler(actorContext(ctx), req)
+}
+
+func actorStream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &contextStream{ServerStream: ss, ctx: actorContext(ss.Context())})
+}
+
+// contextStream подменяет контекст потока.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// recoverUnary и recoverStream, как gin.Recovery, превращают панику
+// обработчика в ошибку Internal вместо падения сервера.
+func (s *Server) recoverUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	defer s.recover(info.FullMethod, &err)
+	return handler(ctx, req)
+}
+
+func (s *Server) recoverStream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer s.recover(info.FullMethod, &err)
+	return handler(srv, ss)
+}
+
+func (s *Server) recover(method string, err *error) {
+	if r := recover(); r != nil {
+		s.log.Error("gRPC handler panic in %s: %v", method, r)
+		*err = status.Error(codes.Internal, "internal error")
+	}
+}
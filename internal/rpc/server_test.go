@@ -0,0 +1,328 @@
+package rpc
+
+import (
+	storev1 "backend-store/api/proto/store/v1"
+	"backend-store/internal/models"
+	"backend-store/internal/service"
+	"backend-store/pkg/logger"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// MockProductService реализует интерфейс service.ProductService для тестов;
+// не используемые gRPC методы не реализованы.
+type MockProductService struct {
+	mock.Mock
+	service.ProductService
+}
+
+func (m *MockProductService) CreateProduct(ctx context.Context, product *models.Product) error {
+	args := m.Called(ctx, product)
+	return args.Error(0)
+}
+
+func (m *MockProductService) GetAllProducts(ctx context.Context, filter models.ProductFilter) ([]*models.Product, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]*models.Product), args.Error(1)
+}
+
+func (m *MockProductService) GetProductByID(ctx context.Context, id int) (*models.Product, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Product), args.Error(1)
+}
+
+func (m *MockProductService) UpdateProduct(ctx context.Context, product *models.Product) error {
+	args := m.Called(ctx, product)
+	return args.Error(0)
+}
+
+func (m *MockProductService) DeleteProduct(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// MockOrderService реализует интерфейс service.OrderService для тестов;
+// не используемые gRPC методы не реализованы.
+type MockOrderService struct {
+	mock.Mock
+	service.OrderService
+}
+
+func (m *MockOrderService) CreateOrder(ctx context.Context, order *models.Order) error {
+	args := m.Called(ctx, order)
+	return args.Error(0)
+}
+
+func (m *MockOrderService) GetAllOrders(ctx context.Context) ([]*models.Order, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*models.Order), args.Error(1)
+}
+
+func (m *MockOrderService) GetOrderByID(ctx context.Context, id int) (*models.Order, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Order), args.Error(1)
+}
+
+// startServer запускает Server на bufconn и возвращает соединение клиента.
+func startServer(t *testing.T, products *MockProductService, orders *MockOrderService) (*Server, *grpc.ClientConn) {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	srv := NewServer(products, orders, logger.New("error"))
+	go srv.Serve(lis)
+	t.Cleanup(func() { srv.Shutdown(context.Background()) })
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return srv, conn
+}
+
+func TestProductServer_GetProduct(t *testing.T) {
+	// Arrange
+	products := new(MockProductService)
+	_, conn := startServer(t, products, new(MockOrderService))
+	client := storev1.NewProductServiceClient(conn)
+
+	createdAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	products.On("GetProductByID", mock.Anything, 5).Return(&models.Product{
+		ID: 5, SKU: "SKU-5", Name: "Phone", Price: 99.5, Quantity: 7,
+		CategoryIDs: []int{2}, Tags: []string{"sale"}, CreatedAt: createdAt,
+		Variants: []models.ProductVariant{{ID: 1, SKU: "SKU-5-B", Options: models.VariantOptions{"color": "black"}, Quantity: 3}},
+	}, nil)
+
+	// Act
+	product, err := client.GetProduct(context.Background(), &storev1.GetProductRequest{Id: 5})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), product.GetId())
+	assert.Equal(t, "Phone", product.GetName())
+	assert.Equal(t, 99.5, product.GetPrice())
+	assert.Equal(t, []int64{2}, product.GetCategoryIds())
+	assert.Equal(t, []string{"sale"}, product.GetTags())
+	assert.Equal(t, createdAt, product.GetCreatedAt().AsTime())
+	require.Len(t, product.GetVariants(), 1)
+	assert.Equal(t, "black", product.GetVariants()[0].GetOptions()["color"])
+	products.AssertExpectations(t)
+}
+
+func TestProductServer_Errors(t *testing.T) {
+	products := new(MockProductService)
+	_, conn := startServer(t, products, new(MockOrderService))
+	client := storev1.NewProductServiceClient(conn)
+
+	products.On("GetProductByID", mock.Anything, 404).Return(nil, errors.New("failed to get product: product not found"))
+	products.On("CreateProduct", mock.Anything, mock.Anything).Return(errors.New("product name is required")).Maybe()
+	products.On("DeleteProduct", mock.Anything, 7).Return(errors.New("cannot delete product 7: it has orders"))
+	products.On("UpdateProduct", mock.Anything, mock.Anything).Return(errors.New("failed to validate product: unknown category 9"))
+
+	tests := []struct {
+		name string
+		call func() error
+		code codes.Code
+	}{
+		{name: "invalid ID", call: func() error {
+			_, err := client.GetProduct(context.Background(), &storev1.GetProductRequest{})
+			return err
+		}, code: codes.InvalidArgument},
+		{name: "not found", call: func() error {
+			_, err := client.GetProduct(context.Background(), &storev1.GetProductRequest{Id: 404})
+			return err
+		}, code: codes.NotFound},
+		{name: "missing product", call: func() error {
+			_, err := client.CreateProduct(context.Background(), &storev1.CreateProductRequest{})
+			return err
+		}, code: codes.InvalidArgument},
+		{name: "validation", call: func() error {
+			_, err := client.UpdateProduct(context.Background(), &storev1.UpdateProductRequest{Product: &storev1.Product{Id: 3}})
+			return err
+		}, code: codes.InvalidArgument},
+		{name: "conflict", call: func() error {
+			_, err := client.DeleteProduct(context.Background(), &storev1.DeleteProductRequest{Id: 7})
+			return err
+		}, code: codes.FailedPrecondition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.code, status.Code(tt.call()))
+		})
+	}
+}
+
+func TestProductServer_UpdateProduct_Taxonomy(t *testing.T) {
+	// Arrange
+	products := new(MockProductService)
+	_, conn := startServer(t, products, new(MockOrderService))
+	client := storev1.NewProductServiceClient(conn)
+
+	products.On("UpdateProduct", mock.Anything, mock.MatchedBy(func(p *models.Product) bool {
+		return p.ID == 3 && p.CategoryIDs == nil && p.Tags != nil && len(p.Tags) == 0
+	})).Return(nil)
+
+	// Act
+	_, err := client.UpdateProduct(context.Background(), &storev1.UpdateProductRequest{
+		Product:    &storev1.Product{Id: 3, Name: "Phone", Price: 10, CategoryIds: []int64{1}},
+		UpdateTags: true,
+	})
+
+	// Assert
+	require.NoError(t, err)
+	products.AssertExpectations(t)
+}
+
+func TestProductServer_ListProducts(t *testing.T) {
+	// Arrange
+	products := new(MockProductService)
+	_, conn := startServer(t, products, new(MockOrderService))
+	client := storev1.NewProductServiceClient(conn)
+
+	products.On("GetAllProducts", mock.Anything, models.ProductFilter{CategoryID: 2, Tag: "sale"}).
+		Return([]*models.Product{{ID: 1, Name: "A"}, {ID: 2, Name: "B"}}, nil)
+
+	// Act
+	stream, err := client.ListProducts(context.Background(), &storev1.ListProductsRequest{CategoryId: 2, Tag: "sale"})
+	require.NoError(t, err)
+
+	var names []string
+	for {
+		product, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		names = append(names, product.GetName())
+	}
+
+	// Assert
+	assert.Equal(t, []string{"A", "B"}, names)
+	products.AssertExpectations(t)
+}
+
+func TestOrderServer_CreateOrder(t *testing.T) {
+	// Arrange
+	orders := new(MockOrderService)
+	_, conn := startServer(t, new(MockProductService), orders)
+	client := storev1.NewOrderServiceClient(conn)
+
+	orders.On("CreateOrder", mock.Anything, mock.MatchedBy(func(o *models.Order) bool {
+		return o.CustomerID == 2 && len(o.Products) == 1 && o.Products[0].ProductID == 5 && o.ShippingAddress.City == "Berlin"
+	})).Run(func(args mock.Arguments) {
+		order := args.Get(1).(*models.Order)
+		order.ID = 10
+		order.Status = models.OrderStatusPending
+		order.Total = 1500
+	}).Return(nil)
+
+	// Act
+	order, err := client.CreateOrder(context.Background(), &storev1.CreateOrderRequest{Order: &storev1.Order{
+		CustomerId:      2,
+		Items:           []*storev1.OrderItem{{ProductId: 5, Quantity: 3}},
+		ShippingAddress: &storev1.Address{City: "Berlin", Country: "DE"},
+	}})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), order.GetId())
+	assert.Equal(t, models.OrderStatusPending, order.GetStatus())
+	assert.Equal(t, int64(1500), order.GetTotal())
+	orders.AssertExpectations(t)
+}
+
+func TestOrderServer_CreateOrder_UnknownProduct(t *testing.T) {
+	// Arrange
+	orders := new(MockOrderService)
+	_, conn := startServer(t, new(MockProductService), orders)
+	client := storev1.NewOrderServiceClient(conn)
+
+	orders.On("CreateOrder", mock.Anything, mock.Anything).Return(errors.New("product 99 not found"))
+
+	// Act
+	_, err := client.CreateOrder(context.Background(), &storev1.CreateOrderRequest{Order: &storev1.Order{
+		Items: []*storev1.OrderItem{{ProductId: 99, Quantity: 1}},
+	}})
+
+	// Assert
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestOrderServer_ListOrders(t *testing.T) {
+	// Arrange
+	orders := new(MockOrderService)
+	_, conn := startServer(t, new(MockProductService), orders)
+	client := storev1.NewOrderServiceClient(conn)
+
+	orders.On("GetAllOrders", mock.Anything).Return([]*models.Order{
+		{ID: 1, Products: []models.OrderItem{{ProductID: 5, Quantity: 1, Price: 100}}},
+		{ID: 2},
+	}, nil)
+
+	// Act
+	stream, err := client.ListOrders(context.Background(), &storev1.ListOrdersRequest{})
+	require.NoError(t, err)
+
+	var ids []int64
+	for {
+		order, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		ids = append(ids, order.GetId())
+	}
+
+	// Assert
+	assert.Equal(t, []int64{1, 2}, ids)
+}
+
+func TestServer_Health(t *testing.T) {
+	// Arrange
+	srv, conn := startServer(t, new(MockProductService), new(MockOrderService))
+	client := healthpb.NewHealthClient(conn)
+
+	// Act
+	resp, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "store.v1.OrderService"})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+
+	require.NoError(t, srv.Shutdown(context.Background()))
+}
+
+func TestServer_Recover(t *testing.T) {
+	// Arrange
+	orders := new(MockOrderService)
+	_, conn := startServer(t, new(MockProductService), orders)
+	client := storev1.NewOrderServiceClient(conn)
+
+	// Act: DeleteOrder не реализован в моке и паникует.
+	_, err := client.DeleteOrder(context.Background(), &storev1.DeleteOrderRequest{Id: 1})
+
+	// Assert
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
@@ -0,0 +1,168 @@
+// Package search - инвертированный индекс в памяти. Используется MemoryStorage
+// вместо tsvector/pg_trgm, поэтому правила разбора текста и нечеткого поиска
+// повторяют конфигурацию PostgreSQL: словарь 'simple' без стемминга
+// и триграммное сходство с порогом 0.3.
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Веса полей соответствуют весам ts_rank для меток A и B.
+const (
+	WeightName        = 1.0
+	WeightDescription = 0.4
+
+	// SimilarityThreshold - порог pg_trgm.similarity_threshold по умолчанию.
+	SimilarityThreshold = 0.3
+	// fuzzyPenalty понижает вклад нечетких совпадений относительно точных.
+	fuzzyPenalty = 0.5
+)
+
+// Field - индексируемый текст с весом.
+type Field struct {
+	Text   string
+	Weight float64
+}
+
+type Index struct {
+	postings map[string]map[int]float64
+	docs     map[int][]string
+	trigrams map[string][]string
+}
+
+func NewIndex() *Index {
+	return &Index{
+		postings: make(map[string]map[int]float64),
+		docs:     make(map[int][]string),
+		trigrams: make(map[string][]string),
+	}
+}
+
+// Add индексирует документ, заменяя предыдущую версию с тем же id.
+func (idx *Index) Add(id int, fields ...Field) {
+	idx.Remove(id)
+
+	var terms []string
+	for _, f := range fields {
+		for _, term := range Tokenize(f.Text) {
+			docs, ok := idx.postings[term]
+			if !ok {
+				docs = make(map[int]float64)
+				idx.postings[term] = docs
+				idx.trigrams[term] = trigrams(term)
+			}
+			if _, seen := docs[id]; !seen {
+				terms = append(terms, term)
+			}
+			docs[id] += f.Weight
+		}
+	}
+	idx.docs[id] = terms
+}
+
+func (idx *Index) Remove(id int) {
+	for _, term := range idx.docs[id] {
+		docs := idx.postings[term]
+		delete(docs, id)
+		if len(docs) == 0 {
+			delete(idx.postings, term)
+			delete(idx.trigrams, term)
+		}
+	}
+	delete(idx.docs, id)
+}
+
+// Search возвращает документы, содержащие каждое слово запроса точно или
+// с опечаткой, и их релевантность.
+func (idx *Index) Search(query string) map[int]float64 {
+	tokens := Tokenize(query)
+	if len(tokens) == 0 {
+		return map[int]float64{}
+	}
+
+	var scores map[int]float64
+	for _, token := range tokens {
+		matches := idx.match(token)
+		if scores == nil {
+			scores = matches
+			continue
+		}
+		for id, score := range scores {
+			if m, ok := matches[id]; ok {
+				scores[id] = score + m
+			} else {
+				delete(scores, id)
+			}
+		}
+	}
+	return scores
+}
+
+// match находит документы для одного слова: точное совпадение дает полный
+// вес, похожие термины словаря - вес, умноженный на сходство и штраф.
+func (idx *Index) match(token string) map[int]float64 {
+	result := make(map[int]float64)
+	for id, weight := range idx.postings[token] {
+		result[id] = weight
+	}
+
+	tokenTrigrams := trigrams(token)
+	for term, termTrigrams := range idx.trigrams {
+		if term == token {
+			continue
+		}
+		sim := similarity(tokenTrigrams, termTrigrams)
+		if sim < SimilarityThreshold {
+			continue
+		}
+		for id, weight := range idx.postings[term] {
+			if score := weight * sim * fuzzyPenalty; score > result[id] {
+				result[id] = score
+			}
+		}
+	}
+	return result
+}
+
+// Tokenize разбивает текст на слова в нижнем регистре.
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// trigrams повторяет pg_trgm: слово дополняется двумя пробелами слева
+// и одним справа, дубликаты отбрасываются.
+func trigrams(word string) []string {
+	runes := []rune("  " + word + " ")
+	seen := make(map[string]bool, len(runes))
+	result := make([]string, 0, len(runes))
+	for i := 0; i+3 <= len(runes); i++ {
+		t := string(runes[i : i+3])
+		if !seen[t] {
+			seen[t] = true
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+// similarity - доля общих триграмм (как pg_trgm.similarity).
+func similarity(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	set := make(map[string]bool, len(a))
+	for _, t := range a {
+		set[t] = true
+	}
+	common := 0
+	for _, t := range b {
+		if set[t] {
+			common++
+		}
+	}
+	return float64(common) / float64(len(a)+len(b)-common)
+}
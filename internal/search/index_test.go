@@ -0,0 +1,59 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testIndex() *Index {
+	idx := NewIndex()
+	idx.Add(1, Field{"Gaming Laptop", WeightName}, Field{"Fast laptop with RTX graphics", WeightDescription})
+	idx.Add(2, Field{"Laptop Bag", WeightName}, Field{"Fits 15 inch laptops", WeightDescription})
+	idx.Add(3, Field{"Mechanical Keyboard", WeightName}, Field{"Great for gaming", WeightDescription})
+	return idx
+}
+
+func TestIndex_ExactMatchRanksNameAboveDescription(t *testing.T) {
+	idx := testIndex()
+
+	scores := idx.Search("gaming")
+
+	assert.Len(t, scores, 2)
+	assert.Greater(t, scores[1], scores[3])
+}
+
+func TestIndex_AllTermsRequired(t *testing.T) {
+	idx := testIndex()
+
+	scores := idx.Search("gaming laptop")
+
+	assert.Len(t, scores, 1)
+	assert.Contains(t, scores, 1)
+}
+
+func TestIndex_TypoTolerance(t *testing.T) {
+	idx := testIndex()
+
+	scores := idx.Search("keybord")
+
+	assert.Contains(t, scores, 3)
+	assert.Less(t, scores[3], WeightName)
+}
+
+func TestIndex_ReplaceAndRemove(t *testing.T) {
+	idx := testIndex()
+
+	idx.Add(3, Field{"Wireless Mouse", WeightName})
+	assert.Empty(t, idx.Search("keyboard"))
+	assert.Contains(t, idx.Search("mouse"), 3)
+
+	idx.Remove(3)
+	assert.Empty(t, idx.Search("mouse"))
+}
+
+func TestSimilarity(t *testing.T) {
+	assert.Equal(t, 1.0, similarity(trigrams("word"), trigrams("word")))
+	assert.InDelta(t, 0.667, similarity(trigrams("laptop"), trigrams("laptops")), 0.001)
+	assert.Equal(t, 0.0, similarity(trigrams("abc"), trigrams("xyz")))
+}
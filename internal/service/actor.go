@@ -0,0 +1,56 @@
+package service
+
+import "context"
+
+type actorKey struct{}
+
+type requestKey struct{}
+
+// defaultActor записывается в журнал, если инициатор изменения неизвестен.
+const defaultActor = "system"
+
+// actorInfo - инициатор изменения. verified ложно, если имя передал клиент
+// и оно ничем не подтверждено.
+type actorInfo struct {
+	name     string
+	verified bool
+}
+
+// WithActor сохраняет в контексте инициатора изменений, известного самому
+// приложению (команда CLI, канал API), для журнала движения товара и
+// журнала аудита.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actorInfo{name: actor, verified: true})
+}
+
+// WithClaimedActor сохраняет инициатора, которого назвал клиент, например
+// заголовком X-Actor. Аутентификации в API нет, поэтому такое имя
+// записывается в журналы с признаком actor_verified = false.
+func WithClaimedActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actorInfo{name: actor})
+}
+
+func actorFromContext(ctx context.Context) actorInfo {
+	if actor, ok := ctx.Value(actorKey{}).(actorInfo); ok && actor.name != "" {
+		return actor
+	}
+	return actorInfo{name: defaultActor, verified: true}
+}
+
+// requestInfo - сведения о запросе, в рамках которого выполняется
+// изменение.
+type requestInfo struct {
+	id string
+	ip string
+}
+
+// WithRequest сохраняет в контексте ID запроса и IP-адрес клиента для
+// журнала аудита.
+func WithRequest(ctx context.Context, requestID, ip string) context.Context {
+	return context.WithValue(ctx, requestKey{}, requestInfo{id: requestID, ip: ip})
+}
+
+func requestFromContext(ctx context.Context) requestInfo {
+	info, _ := ctx.Value(requestKey{}).(requestInfo)
+	return info
+}
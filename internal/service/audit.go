@@ -0,0 +1,89 @@
+package service
+
+import (
+	"backend-store/internal/models"
+	"backend-store/internal/storage"
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+type auditService struct {
+	storage   storage.Storage
+	retention time.Duration
+}
+
+// NewAuditService создает сервис журнала аудита. Записи старше retention
+// удаляются PurgeAuditLog; retention 0 хранит журнал бессрочно.
+func NewAuditService(storage storage.Storage, retention time.Duration) AuditService {
+	return &auditService{storage: storage, retention: retention}
+}
+
+func (s *auditService) GetAuditLog(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, fmt.Errorf("validate: %w", err)
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultAuditLimit
+	}
+	filter.Limit = min(filter.Limit, maxAuditLimit)
+
+	entries, err := s.storage.GetAuditEntries(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audit log: %w", err)
+	}
+	return entries, nil
+}
+
+func (s *auditService) PurgeAuditLog(ctx context.Context) (int, error) {
+	if s.retention <= 0 {
+		return 0, nil
+	}
+	deleted, err := s.storage.DeleteAuditEntriesBefore(ctx, time.Now().Add(-s.retention))
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete audit entries: %w", err)
+	}
+	return deleted, nil
+}
+
+// recordAudit записывает изменение сущности в журнал аудита в транзакции
+// tx. При создании before - nil, при удалении after - nil.
+func recordAudit(ctx context.Context, tx storage.StorageTx, action, entityType string, entityID int, before, after interface{}) error {
+	changes, err := models.DiffAudit(before, after)
+	if err != nil {
+		return err
+	}
+
+	actor := actorFromContext(ctx)
+	request := requestFromContext(ctx)
+	entry := &models.AuditEntry{
+		Actor:         actor.name,
+		ActorVerified: actor.verified,
+		Action:        action,
+		EntityType:    entityType,
+		EntityID:      entityID,
+		Changes:       changes,
+		RequestID:     request.id,
+		IP:            request.ip,
+		CreatedAt:     time.Now(),
+	}
+	if err := tx.CreateAuditEntry(ctx, entry); err != nil {
+		return fmt.Errorf("failed to record audit entry: %w", err)
+	}
+	return nil
+}
+
+// productAuditState возвращает состояние товара для журнала с категориями
+// и тегами из базы: у переданного товара они могут быть не заполнены.
+func productAuditState(ctx context.Context, tx storage.StorageTx, product *models.Product) (*models.Product, error) {
+	state := *product
+	if err := loadProductTaxonomy(ctx, tx, &state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"backend-store/internal/models"
+	"backend-store/internal/payment"
+	"backend-store/internal/shipping"
+	"backend-store/internal/storage"
+	"backend-store/internal/tax"
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// auditSpy запоминает записи аудита, сделанные в зафиксированных
+// транзакциях, и считает записи в обход транзакции.
+type auditSpy struct {
+	storage.Storage
+
+	mu        sync.Mutex
+	direct    int
+	committed []models.AuditEntry
+}
+
+func (s *auditSpy) CreateAuditEntry(ctx context.Context, entry *models.AuditEntry) error {
+	s.mu.Lock()
+	s.direct++
+	s.mu.Unlock()
+	return s.Storage.CreateAuditEntry(ctx, entry)
+}
+
+func (s *auditSpy) BeginTx(ctx context.Context) (storage.StorageTx, error) {
+	tx, err := s.Storage.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &auditSpyTx{StorageTx: tx, spy: s}, nil
+}
+
+type auditSpyTx struct {
+	storage.StorageTx
+	spy     *auditSpy
+	pending []models.AuditEntry
+}
+
+func (tx *auditSpyTx) CreateAuditEntry(ctx context.Context, entry *models.AuditEntry) error {
+	if err := tx.StorageTx.CreateAuditEntry(ctx, entry); err != nil {
+		return err
+	}
+	tx.pending = append(tx.pending, *entry)
+	return nil
+}
+
+func (tx *auditSpyTx) Commit() error {
+	if err := tx.StorageTx.Commit(); err != nil {
+		return err
+	}
+	tx.spy.mu.Lock()
+	tx.spy.committed = append(tx.spy.committed, tx.pending...)
+	tx.spy.mu.Unlock()
+	return nil
+}
+
+func newTestOrderService(s storage.Storage) OrderService {
+	return NewOrderService(s, models.AllocationPriority, tax.NewTable(nil, false), shipping.NewTable(nil), nil)
+}
+
+func TestAudit_WrittenInOperationTransaction(t *testing.T) {
+	type auditKey struct {
+		entityType string
+		action     string
+	}
+
+	tests := []struct {
+		name        string
+		arrange     func(t *testing.T, f *stockFixture) int // ID заказа для act
+		act         func(ctx context.Context, t *testing.T, s storage.Storage, f *stockFixture, orderID int) error
+		wantEntries []auditKey
+		wantChanged []string // поля, которые должна содержать последняя запись
+		wantErr     string
+	}{
+		{
+			name: "create product",
+			act: func(ctx context.Context, t *testing.T, s storage.Storage, f *stockFixture, orderID int) error {
+				return NewProductService(s, nil).CreateProduct(ctx, &models.Product{SKU: "CUP", Name: "Cup", Price: 5})
+			},
+			wantEntries: []auditKey{{models.AggregateProduct, models.AuditCreate}},
+		},
+		{
+			name: "update product price",
+			act: func(ctx context.Context, t *testing.T, s storage.Storage, f *stockFixture, orderID int) error {
+				product, err := s.GetProductByID(ctx, f.productID)
+				require.NoError(t, err)
+				updated := *product
+				updated.Price = 12
+				return NewProductService(s, nil).UpdateProduct(ctx, &updated)
+			},
+			wantEntries: []auditKey{{models.AggregateProduct, models.AuditUpdate}},
+			wantChanged: []string{"price"},
+		},
+		{
+			name: "delete product",
+			act: func(ctx context.Context, t *testing.T, s storage.Storage, f *stockFixture, orderID int) error {
+				return NewProductService(s, nil).DeleteProduct(ctx, f.productID)
+			},
+			wantEntries: []auditKey{{models.AggregateProduct, models.AuditDelete}},
+		},
+		{
+			name: "create order",
+			act: func(ctx context.Context, t *testing.T, s storage.Storage, f *stockFixture, orderID int) error {
+				order := &models.Order{CustomerID: f.customerID, Products: []models.OrderItem{{ProductID: f.productID, Quantity: 1}}}
+				return newTestOrderService(s).CreateOrder(ctx, order)
+			},
+			wantEntries: []auditKey{{models.AggregateOrder, models.AuditCreate}},
+		},
+		{
+			name: "payment capture moves order to processing",
+			arrange: func(t *testing.T, f *stockFixture) int {
+				order := &models.Order{CustomerID: f.customerID, Products: []models.OrderItem{{ProductID: f.productID, Quantity: 1}}}
+				require.NoError(t, newTestOrderService(f.store).CreateOrder(context.Background(), order))
+				return order.ID
+			},
+			act: func(ctx context.Context, t *testing.T, s storage.Storage, f *stockFixture, orderID int) error {
+				_, err := NewPaymentService(s, payment.NewFake("", "", "", nil)).PayOrder(ctx, orderID, "tok_visa")
+				return err
+			},
+			wantEntries: []auditKey{{models.AggregateOrder, models.AuditUpdate}},
+			wantChanged: []string{"status"},
+		},
+		{
+			name: "failed order is not audited",
+			act: func(ctx context.Context, t *testing.T, s storage.Storage, f *stockFixture, orderID int) error {
+				order := &models.Order{CustomerID: f.customerID, Products: []models.OrderItem{{ProductID: f.productID, Quantity: 100}}}
+				return newTestOrderService(s).CreateOrder(ctx, order)
+			},
+			wantErr: "insufficient quantity",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			f := newStockFixture(t, map[string]int{"MAIN": 5})
+			orderID := 0
+			if tt.arrange != nil {
+				orderID = tt.arrange(t, f)
+			}
+			spy := &auditSpy{Storage: f.store}
+			ctx := WithRequest(WithClaimedActor(context.Background(), "alice"), "req-1", "10.0.0.1")
+			before, err := f.store.GetAuditEntries(context.Background(), models.AuditFilter{})
+			require.NoError(t, err)
+
+			// Act
+			err = tt.act(ctx, t, spy, f, orderID)
+
+			// Assert
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Zero(t, spy.direct, "audit entries written outside a transaction")
+
+			after, err := f.store.GetAuditEntries(context.Background(), models.AuditFilter{})
+			require.NoError(t, err)
+			var got []auditKey
+			for _, e := range spy.committed {
+				got = append(got, auditKey{e.EntityType, e.Action})
+				assert.Equal(t, "alice", e.Actor)
+				assert.False(t, e.ActorVerified)
+				assert.Equal(t, "req-1", e.RequestID)
+				assert.Equal(t, "10.0.0.1", e.IP)
+			}
+			assert.Equal(t, tt.wantEntries, got)
+			assert.Len(t, after, len(before)+len(spy.committed))
+			if len(tt.wantChanged) > 0 {
+				last := spy.committed[len(spy.committed)-1]
+				for _, field := range tt.wantChanged {
+					assert.Contains(t, last.Changes, field)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,504 @@
+package service
+
+import (
+	"backend-store/internal/models"
+	"backend-store/internal/storage"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+type cartService struct {
+	storage storage.Storage
+	orders  OrderService
+	ttl     time.Duration
+}
+
+// NewCartService создает сервис корзин. Корзина живет ttl с последнего
+// изменения; оформление идет через orders, чтобы заказ из корзины проходил
+// те же проверки и резервирование, что и созданный напрямую.
+func NewCartService(storage storage.Storage, orders OrderService, ttl time.Duration) CartService {
+	return &cartService{storage: storage, orders: orders, ttl: ttl}
+}
+
+// CreateCart создает анонимную корзину или, если указан userID, возвращает
+// активную корзину пользователя, создавая ее при необходимости.
+func (s *cartService) CreateCart(ctx context.Context, userID int) (*models.Cart, error) {
+	if userID < 0 {
+		return nil, errors.New("validate: invalid user ID")
+	}
+
+	tx, err := s.storage.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	if userID > 0 {
+		cart, err := s.activeUserCart(ctx, tx, userID, now)
+		if err != nil {
+			return nil, err
+		}
+		if cart != nil {
+			return s.finish(ctx, tx, cart)
+		}
+	}
+
+	token, err := newCartToken()
+	if err != nil {
+		return nil, err
+	}
+	cart := &models.Cart{
+		Token:     token,
+		UserID:    userID,
+		Status:    models.CartActive,
+		ExpiresAt: now.Add(s.ttl),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := tx.CreateCart(ctx, cart); err != nil {
+		return nil, fmt.Errorf("failed to create cart: %w", err)
+	}
+	cart.Items = []models.CartItem{}
+
+	return s.finish(ctx, tx, cart)
+}
+
+func (s *cartService) GetCart(ctx context.Context, id int, token string) (*models.Cart, error) {
+	tx, err := s.storage.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	cart, err := loadCart(ctx, tx, id, token, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return s.finish(ctx, tx, cart)
+}
+
+// AddItem добавляет товар в корзину; повторное добавление того же товара
+// увеличивает количество в существующей строке.
+func (s *cartService) AddItem(ctx context.Context, id int, token string, item models.CartItem) (*models.Cart, error) {
+	if err := item.Validate(); err != nil {
+		return nil, fmt.Errorf("validate: %w", err)
+	}
+
+	return s.modify(ctx, id, token, func(tx storage.StorageTx, cart *models.Cart) error {
+		available, err := resolveCartItem(ctx, tx, &item)
+		if err != nil {
+			return err
+		}
+
+		for _, line := range cart.Items {
+			if line.ProductID == item.ProductID && line.VariantID == item.VariantID {
+				line.Quantity += item.Quantity
+				if err := checkCartAvailability(line.Quantity, available); err != nil {
+					return err
+				}
+				return tx.UpdateCartItem(ctx, &line)
+			}
+		}
+
+		if err := checkCartAvailability(item.Quantity, available); err != nil {
+			return err
+		}
+		item.ID = 0
+		item.CartID = cart.ID
+		if err := tx.AddCartItem(ctx, &item); err != nil {
+			return fmt.Errorf("failed to add cart item: %w", err)
+		}
+		return nil
+	})
+}
+
+// UpdateItem задает количество в строке корзины. Уменьшить количество можно
+// и тогда, когда товара уже не хватает.
+func (s *cartService) UpdateItem(ctx context.Context, id int, token string, itemID, quantity int) (*models.Cart, error) {
+	if quantity <= 0 {
+		return nil, errors.New("validate: quantity must be positive")
+	}
+
+	return s.modify(ctx, id, token, func(tx storage.StorageTx, cart *models.Cart) error {
+		line, err := findCartItem(cart, itemID)
+		if err != nil {
+			return err
+		}
+		if quantity > line.Quantity {
+			available, err := resolveCartItem(ctx, tx, line)
+			if err != nil {
+				return err
+			}
+			if err := checkCartAvailability(quantity, available); err != nil {
+				return err
+			}
+		}
+		line.Quantity = quantity
+		return tx.UpdateCartItem(ctx, line)
+	})
+}
+
+func (s *cartService) RemoveItem(ctx context.Context, id int, token string, itemID int) (*models.Cart, error) {
+	return s.modify(ctx, id, token, func(tx storage.StorageTx, cart *models.Cart) error {
+		if _, err := findCartItem(cart, itemID); err != nil {
+			return err
+		}
+		return tx.DeleteCartItem(ctx, itemID)
+	})
+}
+
+// MergeCart привязывает анонимную корзину к пользователю при входе. Если у
+// пользователя уже есть активная корзина, позиции переносятся в нее, а
+// анонимная корзина удаляется; возвращается корзина пользователя.
+func (s *cartService) MergeCart(ctx context.Context, id int, token string, userID int) (*models.Cart, error) {
+	if userID <= 0 {
+		return nil, errors.New("validate: user ID is required")
+	}
+
+	tx, err := s.storage.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	cart, err := loadActiveCart(ctx, tx, id, token, now)
+	if err != nil {
+		return nil, err
+	}
+	if cart.UserID == userID {
+		return s.finish(ctx, tx, cart)
+	}
+	if cart.UserID != 0 {
+		return nil, errors.New("cannot merge a cart that belongs to another user")
+	}
+
+	target, err := s.activeUserCart(ctx, tx, userID, now)
+	if err != nil {
+		return nil, err
+	}
+	if target == nil {
+		cart.UserID = userID
+		if err := s.touch(ctx, tx, cart, now); err != nil {
+			return nil, err
+		}
+		return s.finish(ctx, tx, cart)
+	}
+
+	for _, item := range cart.Items {
+		if err := mergeCartItem(ctx, tx, target, item); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.DeleteCart(ctx, cart.ID); err != nil {
+		return nil, fmt.Errorf("failed to delete merged cart: %w", err)
+	}
+	if err := s.touch(ctx, tx, target, now); err != nil {
+		return nil, err
+	}
+
+	merged, err := tx.GetCartByID(ctx, target.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.finish(ctx, tx, merged)
+}
+
+// Checkout оформляет корзину пользователя в заказ. Корзина помечается
+// оформленной до создания заказа, чтобы повторный запрос не создал второй
+// заказ; если заказ создать не удалось, корзина снова становится активной.
+// promoCode и shippingMethod применяются к заказу так же, как при создании
+// заказа напрямую.
+func (s *cartService) Checkout(ctx context.Context, id int, token string, address *models.Address, promoCode, shippingMethod string) (*models.Order, error) {
+	cart, err := s.claim(ctx, id, token)
+	if err != nil {
+		return nil, err
+	}
+
+	order := &models.Order{
+		CustomerID:      cart.UserID,
+		ShippingAddress: address,
+		PromoCode:       promoCode,
+		ShippingMethod:  shippingMethod,
+	}
+	for _, item := range cart.Items {
+		order.Products = append(order.Products, models.OrderItem{
+			ProductID: item.ProductID,
+			VariantID: item.VariantID,
+			Quantity:  item.Quantity,
+		})
+	}
+
+	if err := s.orders.CreateOrder(ctx, order); err != nil {
+		if reopenErr := s.setStatus(ctx, cart, models.CartActive, 0); reopenErr != nil {
+			return nil, fmt.Errorf("%w (failed to reopen cart: %v)", err, reopenErr)
+		}
+		return nil, err
+	}
+
+	if err := s.setStatus(ctx, cart, models.CartCheckedOut, order.ID); err != nil {
+		return nil, fmt.Errorf("order %d created but cart was not updated: %w", order.ID, err)
+	}
+	return order, nil
+}
+
+func (s *cartService) PurgeExpiredCarts(ctx context.Context) (int, error) {
+	deleted, err := s.storage.DeleteExpiredCarts(ctx, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired carts: %w", err)
+	}
+	return deleted, nil
+}
+
+// claim проверяет, что корзину можно оформить, и помечает ее оформленной.
+func (s *cartService) claim(ctx context.Context, id int, token string) (*models.Cart, error) {
+	tx, err := s.storage.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	cart, err := loadActiveCart(ctx, tx, id, token, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if len(cart.Items) == 0 {
+		return nil, errors.New("cannot check out an empty cart")
+	}
+	if cart.UserID == 0 {
+		return nil, errors.New("cannot check out an anonymous cart: merge it into a user cart first")
+	}
+
+	cart.Status = models.CartCheckedOut
+	cart.UpdatedAt = time.Now()
+	if err := tx.UpdateCart(ctx, cart); err != nil {
+		return nil, fmt.Errorf("failed to update cart: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return cart, nil
+}
+
+func (s *cartService) setStatus(ctx context.Context, cart *models.Cart, status string, orderID int) error {
+	tx, err := s.storage.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	cart.Status = status
+	cart.OrderID = orderID
+	cart.UpdatedAt = time.Now()
+	if err := tx.UpdateCart(ctx, cart); err != nil {
+		return fmt.Errorf("failed to update cart: %w", err)
+	}
+	return tx.Commit()
+}
+
+// modify выполняет apply над активной корзиной, продлевает ее срок жизни и
+// возвращает обновленную корзину с текущими ценами.
+func (s *cartService) modify(ctx context.Context, id int, token string, apply func(storage.StorageTx, *models.Cart) error) (*models.Cart, error) {
+	tx, err := s.storage.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	cart, err := loadActiveCart(ctx, tx, id, token, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := apply(tx, cart); err != nil {
+		return nil, err
+	}
+	if err := s.touch(ctx, tx, cart, now); err != nil {
+		return nil, err
+	}
+
+	updated, err := tx.GetCartByID(ctx, cart.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.finish(ctx, tx, updated)
+}
+
+func (s *cartService) touch(ctx context.Context, tx storage.StorageTx, cart *models.Cart, now time.Time) error {
+	cart.ExpiresAt = now.Add(s.ttl)
+	cart.UpdatedAt = now
+	if err := tx.UpdateCart(ctx, cart); err != nil {
+		return fmt.Errorf("failed to update cart: %w", err)
+	}
+	return nil
+}
+
+// finish рассчитывает цены корзины и фиксирует транзакцию.
+func (s *cartService) finish(ctx context.Context, tx storage.StorageTx, cart *models.Cart) (*models.Cart, error) {
+	if err := priceCart(ctx, tx, cart); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return cart, nil
+}
+
+// activeUserCart возвращает активную корзину пользователя или nil.
+// Просроченная корзина удаляется, чтобы не мешать созданию новой.
+func (s *cartService) activeUserCart(ctx context.Context, tx storage.StorageTx, userID int, now time.Time) (*models.Cart, error) {
+	cart, err := tx.GetActiveCartByUser(ctx, userID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get user cart: %w", err)
+	}
+	if cart.IsExpired(now) {
+		if err := tx.DeleteCart(ctx, cart.ID); err != nil {
+			return nil, fmt.Errorf("failed to delete expired cart: %w", err)
+		}
+		return nil, nil
+	}
+	return cart, nil
+}
+
+// loadCart находит корзину по ID и токену. Неверный токен неотличим от
+// отсутствующей корзины.
+func loadCart(ctx context.Context, tx storage.StorageTx, id int, token string, now time.Time) (*models.Cart, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid cart ID")
+	}
+	cart, err := tx.GetCartByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(cart.Token), []byte(token)) != 1 {
+		return nil, errors.New("cart not found")
+	}
+	if cart.IsExpired(now) {
+		return nil, errors.New("cart expired")
+	}
+	return cart, nil
+}
+
+func loadActiveCart(ctx context.Context, tx storage.StorageTx, id int, token string, now time.Time) (*models.Cart, error) {
+	cart, err := loadCart(ctx, tx, id, token, now)
+	if err != nil {
+		return nil, err
+	}
+	if cart.Status != models.CartActive {
+		return nil, errors.New("cannot modify a checked out cart")
+	}
+	return cart, nil
+}
+
+func findCartItem(cart *models.Cart, itemID int) (*models.CartItem, error) {
+	for i := range cart.Items {
+		if cart.Items[i].ID == itemID {
+			return &cart.Items[i], nil
+		}
+	}
+	return nil, errors.New("cart item not found")
+}
+
+// mergeCartItem переносит позицию в корзину target, складывая количество с
+// уже имеющейся строкой того же товара.
+func mergeCartItem(ctx context.Context, tx storage.StorageTx, target *models.Cart, item models.CartItem) error {
+	for _, line := range target.Items {
+		if line.ProductID == item.ProductID && line.VariantID == item.VariantID {
+			line.Quantity += item.Quantity
+			return tx.UpdateCartItem(ctx, &line)
+		}
+	}
+	item.ID = 0
+	item.CartID = target.ID
+	if err := tx.AddCartItem(ctx, &item); err != nil {
+		return fmt.Errorf("failed to move cart item: %w", err)
+	}
+	return nil
+}
+
+// resolveCartItem проверяет товар позиции так же, как позицию заказа, и
+// возвращает доступный остаток.
+func resolveCartItem(ctx context.Context, tx storage.StorageTx, item *models.CartItem) (int, error) {
+	orderItem := models.OrderItem{ProductID: item.ProductID, VariantID: item.VariantID}
+	available, err := resolveOrderItem(ctx, tx, &orderItem)
+	if err != nil {
+		return 0, err
+	}
+	item.ProductID = orderItem.ProductID
+	item.VariantID = orderItem.VariantID
+	return available, nil
+}
+
+func checkCartAvailability(quantity, available int) error {
+	if quantity > available {
+		return fmt.Errorf("insufficient stock: %d available", available)
+	}
+	return nil
+}
+
+// cartPricingLines возвращает позиции корзины, оцененные priceCart, для
+// расчета скидок.
+func cartPricingLines(cart *models.Cart) []models.PricingLine {
+	lines := make([]models.PricingLine, 0, len(cart.Items))
+	for _, item := range cart.Items {
+		lines = append(lines, models.PricingLine{
+			ProductID: item.ProductID,
+			VariantID: item.VariantID,
+			Quantity:  item.Quantity,
+			UnitPrice: item.UnitPrice,
+		})
+	}
+	return lines
+}
+
+// priceCart заполняет позиции текущими названиями, ценами и остатками.
+func priceCart(ctx context.Context, tx storage.StorageTx, cart *models.Cart) error {
+	cart.Subtotal = 0
+	for i := range cart.Items {
+		item := &cart.Items[i]
+		product, err := tx.GetProductByID(ctx, item.ProductID)
+		if err != nil {
+			return fmt.Errorf("product %d not found: %w", item.ProductID, err)
+		}
+		item.Name = product.Name
+		item.SKU = product.SKU
+		item.UnitPrice = int(math.Round(product.Price))
+		item.Available = product.Quantity
+
+		if item.VariantID > 0 {
+			variant, err := tx.GetVariantByID(ctx, item.VariantID)
+			if err != nil {
+				return fmt.Errorf("product variant %d not found: %w", item.VariantID, err)
+			}
+			if variant.SKU != "" {
+				item.SKU = variant.SKU
+			}
+			item.UnitPrice = int(math.Round(variant.Price))
+			item.Available = variant.Quantity
+		}
+
+		item.LineTotal = item.UnitPrice * item.Quantity
+		item.InStock = item.Available >= item.Quantity
+		cart.Subtotal += item.LineTotal
+	}
+	return nil
+}
+
+func newCartToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate cart token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,441 @@
+package service
+
+import (
+	"backend-store/internal/models"
+	"backend-store/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type categoryService struct {
+	storage storage.Storage
+}
+
+func NewCategoryService(storage storage.Storage) CategoryService {
+	return &categoryService{storage: storage}
+}
+
+func (s *categoryService) CreateCategory(ctx context.Context, category *models.Category) error {
+	if category.Slug == "" {
+		category.Slug = models.Slugify(category.Name)
+	}
+	if err := category.Validate(); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	category.CreatedAt = now
+	category.UpdatedAt = now
+	category.Children = nil
+
+	tx, err := s.storage.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	tree, err := loadCategoryTree(ctx, tx)
+	if err != nil {
+		return err
+	}
+	if category.ParentID != nil {
+		if _, ok := tree.Get(*category.ParentID); !ok {
+			return errors.New("parent category not found")
+		}
+	}
+
+	// Новая категория вставляется на место Position, соседи сдвигаются.
+	siblings := tree.Children(category.ParentKey())
+	category.Position = clampPosition(category.Position, len(siblings))
+	if err := tx.CreateCategory(ctx, category); err != nil {
+		return fmt.Errorf("failed to create category: %w", err)
+	}
+	if err := renumberSiblings(ctx, tx, insertCategory(siblings, category)); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *categoryService) GetCategoryTree(ctx context.Context) ([]*models.Category, error) {
+	tx, err := s.storage.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	tree, err := loadCategoryTree(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return tree.Build(), nil
+}
+
+func (s *categoryService) GetCategoryByID(ctx context.Context, id int) (*models.Category, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid category ID")
+	}
+
+	tx, err := s.storage.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	tree, err := loadCategoryTree(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	category, ok := tree.Get(id)
+	if !ok {
+		return nil, errors.New("category not found")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	result := *category
+	for _, node := range tree.Build() {
+		if found := findCategory(node, id); found != nil {
+			result.Children = found.Children
+			break
+		}
+	}
+	return &result, nil
+}
+
+// UpdateCategory меняет название и slug. Родитель и позиция меняются через MoveCategory.
+func (s *categoryService) UpdateCategory(ctx context.Context, category *models.Category) error {
+	if category.Slug == "" {
+		category.Slug = models.Slugify(category.Name)
+	}
+	if err := category.Validate(); err != nil {
+		return err
+	}
+
+	tx, err := s.storage.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	existing, err := tx.GetCategoryByID(ctx, category.ID)
+	if err != nil {
+		return fmt.Errorf("category not found: %w", err)
+	}
+
+	category.ParentID = existing.ParentID
+	category.Position = existing.Position
+	category.CreatedAt = existing.CreatedAt
+	category.UpdatedAt = time.Now()
+	category.Children = nil
+
+	if err := tx.UpdateCategory(ctx, category); err != nil {
+		return fmt.Errorf("failed to update category: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func (s *categoryService) MoveCategory(ctx context.Context, id int, parentID *int, position int) (*models.Category, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid category ID")
+	}
+	if position < 0 {
+		return nil, errors.New("category position cannot be negative")
+	}
+
+	tx, err := s.storage.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	tree, err := loadCategoryTree(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	category, ok := tree.Get(id)
+	if !ok {
+		return nil, errors.New("category not found")
+	}
+	if parentID != nil {
+		if _, ok := tree.Get(*parentID); !ok {
+			return nil, errors.New("parent category not found")
+		}
+		if tree.IsDescendant(*parentID, id) {
+			return nil, errors.New("cannot move category into itself or its subcategory")
+		}
+	}
+
+	moved := *category
+	moved.ParentID = parentID
+	moved.UpdatedAt = time.Now()
+
+	oldSiblings := removeCategory(tree.Children(category.ParentKey()), id)
+	newSiblings := removeCategory(tree.Children(moved.ParentKey()), id)
+	moved.Position = clampPosition(position, len(newSiblings))
+
+	if err := tx.UpdateCategory(ctx, &moved); err != nil {
+		return nil, fmt.Errorf("failed to move category: %w", err)
+	}
+	if category.ParentKey() != moved.ParentKey() {
+		if err := renumberSiblings(ctx, tx, oldSiblings); err != nil {
+			return nil, err
+		}
+	}
+	if err := renumberSiblings(ctx, tx, insertCategory(newSiblings, &moved)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &moved, nil
+}
+
+// DeleteCategory удаляет пустую категорию. Если в категории есть товары,
+// их нужно перенести в reassignTo, иначе удаление запрещено.
+func (s *categoryService) DeleteCategory(ctx context.Context, id, reassignTo int) error {
+	if id <= 0 {
+		return errors.New("invalid category ID")
+	}
+	if reassignTo == id {
+		return errors.New("cannot reassign products to the deleted category")
+	}
+
+	tx, err := s.storage.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	tree, err := loadCategoryTree(ctx, tx)
+	if err != nil {
+		return err
+	}
+	category, ok := tree.Get(id)
+	if !ok {
+		return errors.New("category not found")
+	}
+	if len(tree.Children(id)) > 0 {
+		return errors.New("cannot delete category with subcategories")
+	}
+
+	productIDs, err := tx.GetProductIDsByCategories(ctx, []int{id})
+	if err != nil {
+		return fmt.Errorf("failed to get category products: %w", err)
+	}
+	if len(productIDs) > 0 {
+		if reassignTo <= 0 {
+			return fmt.Errorf("cannot delete category with %d product(s): reassign them first", len(productIDs))
+		}
+		if _, ok := tree.Get(reassignTo); !ok {
+			return errors.New("target category not found")
+		}
+		if err := tx.ReassignCategoryProducts(ctx, id, reassignTo); err != nil {
+			return fmt.Errorf("failed to reassign products: %w", err)
+		}
+	}
+
+	if err := tx.DeleteCategory(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete category: %w", err)
+	}
+	if err := renumberSiblings(ctx, tx, removeCategory(tree.Children(category.ParentKey()), id)); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func loadCategoryTree(ctx context.Context, tx storage.StorageTx) (*models.CategoryTree, error) {
+	categories, err := tx.GetAllCategories(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get categories: %w", err)
+	}
+	return models.NewCategoryTree(categories), nil
+}
+
+// renumberSiblings записывает позиции 0..n-1 в порядке среза, обновляя
+// только изменившиеся категории.
+func renumberSiblings(ctx context.Context, tx storage.StorageTx, siblings []*models.Category) error {
+	for i, c := range siblings {
+		if c.Position == i {
+			continue
+		}
+		updated := *c
+		updated.Position = i
+		if err := tx.UpdateCategory(ctx, &updated); err != nil {
+			return fmt.Errorf("failed to reorder categories: %w", err)
+		}
+	}
+	return nil
+}
+
+func insertCategory(siblings []*models.Category, category *models.Category) []*models.Category {
+	result := make([]*models.Category, 0, len(siblings)+1)
+	result = append(result, siblings[:category.Position]...)
+	result = append(result, category)
+	return append(result, siblings[category.Position:]...)
+}
+
+func removeCategory(siblings []*models.Category, id int) []*models.Category {
+	result := make([]*models.Category, 0, len(siblings))
+	for _, c := range siblings {
+		if c.ID != id {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+func clampPosition(position, max int) int {
+	if position > max {
+		return max
+	}
+	return position
+}
+
+func findCategory(node *models.Category, id int) *models.Category {
+	if node.ID == id {
+		return node
+	}
+	for _, child := range node.Children {
+		if found := findCategory(child, id); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func (s *productService) GetTags(ctx context.Context) ([]models.TagCount, error) {
+	tx, err := s.storage.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	tags, err := tx.GetAllTags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+// saveProductTaxonomy сохраняет категории и теги товара. Поля со значением nil
+// не меняются; после сохранения в product записывается актуальное состояние.
+func saveProductTaxonomy(ctx context.Context, tx storage.StorageTx, product *models.Product) error {
+	if product.CategoryIDs != nil {
+		for _, id := range product.CategoryIDs {
+			if _, err := tx.GetCategoryByID(ctx, id); err != nil {
+				return fmt.Errorf("failed to validate product: unknown category %d", id)
+			}
+		}
+		if err := tx.SetProductCategories(ctx, product.ID, product.CategoryIDs); err != nil {
+			return fmt.Errorf("failed to set product categories: %w", err)
+		}
+	}
+
+	if product.Tags != nil {
+		tags, err := models.NormalizeTags(product.Tags)
+		if err != nil {
+			return fmt.Errorf("failed to validate product: %w", err)
+		}
+		if err := tx.SetProductTags(ctx, product.ID, tags); err != nil {
+			return fmt.Errorf("failed to set product tags: %w", err)
+		}
+	}
+
+	return loadProductTaxonomy(ctx, tx, product)
+}
+
+func loadProductTaxonomy(ctx context.Context, tx storage.StorageTx, product *models.Product) error {
+	categoryIDs, err := tx.GetProductCategoryIDs(ctx, product.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get product categories: %w", err)
+	}
+	tags, err := tx.GetProductTags(ctx, product.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get product tags: %w", err)
+	}
+	product.CategoryIDs = categoryIDs
+	product.Tags = tags
+	return nil
+}
+
+// filterProducts оставляет товары, подходящие под фильтр. Фильтр по категории
+// включает все ее подкатегории.
+func filterProducts(ctx context.Context, tx storage.StorageTx, products []*models.Product, filter models.ProductFilter) ([]*models.Product, error) {
+	var allowed []map[int]bool
+
+	if filter.CategoryID > 0 {
+		tree, err := loadCategoryTree(ctx, tx)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := tree.Get(filter.CategoryID); !ok {
+			return nil, errors.New("category not found")
+		}
+		ids, err := tx.GetProductIDsByCategories(ctx, tree.Descendants(filter.CategoryID))
+		if err != nil {
+			return nil, fmt.Errorf("failed to filter by category: %w", err)
+		}
+		allowed = append(allowed, intSet(ids))
+	}
+
+	if filter.Tag != "" {
+		tags, err := models.NormalizeTags([]string{filter.Tag})
+		if err != nil {
+			return nil, err
+		}
+		ids := []int{}
+		if len(tags) > 0 {
+			ids, err = tx.GetProductIDsByTag(ctx, tags[0])
+			if err != nil {
+				return nil, fmt.Errorf("failed to filter by tag: %w", err)
+			}
+		}
+		allowed = append(allowed, intSet(ids))
+	}
+
+	if len(allowed) == 0 {
+		return products, nil
+	}
+
+	result := make([]*models.Product, 0, len(products))
+	for _, p := range products {
+		match := true
+		for _, set := range allowed {
+			if !set[p.ID] {
+				match = false
+				break
+			}
+		}
+		if match {
+			result = append(result, p)
+		}
+	}
+	return result, nil
+}
+
+func intSet(values []int) map[int]bool {
+	set := make(map[int]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"backend-store/internal/models"
+	"backend-store/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type customerService struct {
+	storage storage.Storage
+}
+
+func NewCustomerService(storage storage.Storage) CustomerService {
+	return &customerService{storage: storage}
+}
+
+func (s *customerService) CreateCustomer(ctx context.Context, customer *models.Customer) error {
+	customer.Normalize()
+	if err := customer.Validate(); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+
+	now := time.Now()
+	customer.CreatedAt = now
+	customer.UpdatedAt = now
+
+	tx, err := s.storage.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := tx.CreateCustomer(ctx, customer); err != nil {
+		return fmt.Errorf("failed to create customer: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func (s *customerService) GetAllCustomers(ctx context.Context) ([]*models.Customer, error) {
+	customers, err := s.storage.GetAllCustomers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get customers: %w", err)
+	}
+	return customers, nil
+}
+
+func (s *customerService) GetCustomerByID(ctx context.Context, id int) (*models.Customer, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid customer ID")
+	}
+	return s.storage.GetCustomerByID(ctx, id)
+}
+
+// UpdateCustomer заменяет данные покупателя и весь список его адресов.
+func (s *customerService) UpdateCustomer(ctx context.Context, customer *models.Customer) error {
+	customer.Normalize()
+	if err := customer.Validate(); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+
+	tx, err := s.storage.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	existing, err := tx.GetCustomerByID(ctx, customer.ID)
+	if err != nil {
+		return err
+	}
+	customer.CreatedAt = existing.CreatedAt
+	customer.UpdatedAt = time.Now()
+
+	if err := tx.UpdateCustomer(ctx, customer); err != nil {
+		return fmt.Errorf("failed to update customer: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func (s *customerService) DeleteCustomer(ctx context.Context, id int) error {
+	if id <= 0 {
+		return errors.New("invalid customer ID")
+	}
+	if err := s.storage.DeleteCustomer(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete customer: %w", err)
+	}
+	return nil
+}
+
+// GetCustomerOrders возвращает заказы покупателя в порядке создания.
+func (s *customerService) GetCustomerOrders(ctx context.Context, id int) ([]*models.Order, error) {
+	if _, err := s.GetCustomerByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	orders := []*models.Order{}
+	err := s.storage.IterateOrders(ctx, models.OrderFilter{CustomerID: id}, func(o *models.Order) error {
+		orders = append(orders, o)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get customer orders: %w", err)
+	}
+	return orders, nil
+}
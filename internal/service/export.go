@@ -0,0 +1,64 @@
+package service
+
+import (
+	"backend-store/internal/bulk"
+	"backend-store/internal/models"
+	"context"
+	"fmt"
+	"io"
+)
+
+var productExportColumns = []string{
+	"id", "sku", "name", "description", "price", "quantity", "created_at", "updated_at",
+}
+
+// orderExportColumns - заказ в развернутом виде: одна строка на позицию.
+// Заказ без позиций выгружается одной строкой с пустыми полями позиции.
+var orderExportColumns = []string{
+	"order_id", "customer_id", "status", "total", "created_at", "updated_at",
+	"item_id", "product_id", "quantity", "price",
+}
+
+func (s *productService) ExportProducts(ctx context.Context, format bulk.Format, w io.Writer) error {
+	writer, err := bulk.NewWriter(w, format, "Products", productExportColumns)
+	if err != nil {
+		return err
+	}
+
+	err = s.storage.IterateProducts(ctx, func(p *models.Product) error {
+		return writer.Write([]interface{}{
+			p.ID, p.SKU, p.Name, p.Description, p.Price, p.Quantity, p.CreatedAt, p.UpdatedAt,
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to export products: %w", err)
+	}
+
+	return writer.Close()
+}
+
+func (s *orderService) ExportOrders(ctx context.Context, filter models.OrderFilter, format bulk.Format, w io.Writer) error {
+	writer, err := bulk.NewWriter(w, format, "Orders", orderExportColumns)
+	if err != nil {
+		return err
+	}
+
+	err = s.storage.IterateOrders(ctx, filter, func(o *models.Order) error {
+		head := []interface{}{o.ID, o.CustomerID, o.Status, o.Total, o.CreatedAt, o.UpdatedAt}
+		if len(o.Products) == 0 {
+			return writer.Write(append(head, nil, nil, nil, nil))
+		}
+		for _, item := range o.Products {
+			row := append(head[:len(head):len(head)], item.ID, item.ProductID, item.Quantity, item.Price)
+			if err := writer.Write(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to export orders: %w", err)
+	}
+
+	return writer.Close()
+}
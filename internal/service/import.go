@@ -0,0 +1,202 @@
+package service
+
+import (
+	"backend-store/internal/bulk"
+	"backend-store/internal/models"
+	"backend-store/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+const defaultImportBatchSize = 100
+
+func (s *productService) ImportProducts(ctx context.Context, reader bulk.ProductReader, opts ImportOptions) (*models.ImportReport, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultImportBatchSize
+	}
+
+	report := &models.ImportReport{DryRun: opts.DryRun, Rows: []models.ImportRowResult{}}
+	batch := make([]*bulk.ProductRow, 0, opts.BatchSize)
+
+	for {
+		row, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return report, fmt.Errorf("failed to read import data: %w", err)
+		}
+
+		if err := validateImportRow(row); err != nil {
+			report.Add(failedImportRow(row, err))
+			continue
+		}
+
+		batch = append(batch, row)
+		if len(batch) == opts.BatchSize {
+			if err := s.importBatch(ctx, batch, opts.DryRun, report); err != nil {
+				return report, err
+			}
+			batch = batch[:0]
+		}
+	}
+
+	if len(batch) > 0 {
+		if err := s.importBatch(ctx, batch, opts.DryRun, report); err != nil {
+			return report, err
+		}
+	}
+
+	// Строки с ошибками валидации попадают в отчет раньше своей пачки.
+	sort.SliceStable(report.Rows, func(i, j int) bool { return report.Rows[i].Row < report.Rows[j].Row })
+
+	return report, nil
+}
+
+func validateImportRow(row *bulk.ProductRow) error {
+	if row.Err != nil {
+		return row.Err
+	}
+	if row.Product.SKU == "" {
+		return errors.New("product sku is required")
+	}
+	return row.Product.Validate()
+}
+
+func failedImportRow(row *bulk.ProductRow, err error) models.ImportRowResult {
+	result := models.ImportRowResult{
+		Row:    row.Line,
+		Status: models.ImportRowFailed,
+		Error:  err.Error(),
+	}
+	if row.Product != nil {
+		result.SKU = row.Product.SKU
+	}
+	return result
+}
+
+// importBatch записывает пачку строк в одной транзакции. При ошибке
+// хранилища все строки пачки попадают в отчет как failed, а импорт
+// продолжается со следующей пачки. PostgreSQL откатывает пачку целиком;
+// MemoryStorage откатывать не умеет, и строки до ошибочной остаются
+// записанными. Ошибку возвращает только отмена контекста.
+func (s *productService) importBatch(ctx context.Context, batch []*bulk.ProductRow, dryRun bool, report *models.ImportReport) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	results, err := s.upsertBatch(ctx, batch, dryRun)
+	if err != nil {
+		for _, row := range batch {
+			report.Add(failedImportRow(row, err))
+		}
+		return nil
+	}
+
+	for _, result := range results {
+		report.Add(result)
+	}
+	return nil
+}
+
+func (s *productService) upsertBatch(ctx context.Context, batch []*bulk.ProductRow, dryRun bool) ([]models.ImportRowResult, error) {
+	tx, err := s.storage.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	results := make([]models.ImportRowResult, 0, len(batch))
+	for _, row := range batch {
+		result, err := upsertProduct(ctx, tx, row.Product, dryRun)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", row.Line, err)
+		}
+		result.Row = row.Line
+		results = append(results, result)
+	}
+
+	if dryRun {
+		return results, nil
+	}
+	if err := commitStockChange(tx, s.observer); err != nil {
+		return nil, fmt.Errorf("failed to commit import batch: %w", err)
+	}
+	return results, nil
+}
+
+func upsertProduct(ctx context.Context, tx storage.StorageTx, product *models.Product, dryRun bool) (models.ImportRowResult, error) {
+	result := models.ImportRowResult{SKU: product.SKU}
+	now := time.Now()
+
+	existing, err := tx.GetProductBySKU(ctx, product.SKU)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return result, fmt.Errorf("failed to look up sku %q: %w", product.SKU, err)
+	}
+
+	if existing == nil {
+		result.Status = models.ImportRowCreated
+		if dryRun {
+			return result, nil
+		}
+		product.CreatedAt = now
+		product.UpdatedAt = now
+		if err := tx.CreateProduct(ctx, product); err != nil {
+			return result, fmt.Errorf("failed to create product: %w", err)
+		}
+		if err := createVariants(ctx, tx, product, models.MovementImport); err != nil {
+			return result, err
+		}
+		after, err := productAuditState(ctx, tx, product)
+		if err != nil {
+			return result, err
+		}
+		if err := recordAudit(ctx, tx, models.AuditCreate, models.AggregateProduct, product.ID, nil, after); err != nil {
+			return result, err
+		}
+		result.ProductID = product.ID
+		return result, nil
+	}
+
+	result.Status = models.ImportRowUpdated
+	result.ProductID = existing.ID
+	if dryRun {
+		return result, nil
+	}
+	before, err := productAuditState(ctx, tx, existing)
+	if err != nil {
+		return result, err
+	}
+	product.ID = existing.ID
+	product.CreatedAt = existing.CreatedAt
+	product.UpdatedAt = now
+	product.Options = existing.Options
+	product.ReorderPoint = existing.ReorderPoint
+	product.ReorderQuantity = existing.ReorderQuantity
+	product.TaxClass = existing.TaxClass
+	product.Weight = existing.Weight
+	product.Length = existing.Length
+	product.Width = existing.Width
+	product.Height = existing.Height
+	if err := tx.UpdateProduct(ctx, product); err != nil {
+		return result, fmt.Errorf("failed to update product: %w", err)
+	}
+	if err := syncVariants(ctx, tx, product, models.MovementImport); err != nil {
+		return result, err
+	}
+	if err := recordPriceChange(ctx, tx, existing, product); err != nil {
+		return result, err
+	}
+	after, err := productAuditState(ctx, tx, product)
+	if err != nil {
+		return result, err
+	}
+	if err := recordAudit(ctx, tx, models.AuditUpdate, models.AggregateProduct, product.ID, before, after); err != nil {
+		return result, err
+	}
+	return result, nil
+}
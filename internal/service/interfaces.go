@@ -1,16 +1,46 @@
 package service
 
 import (
+	"backend-store/internal/bulk"
 	"backend-store/internal/models"
+	"backend-store/internal/payment"
+	"backend-store/internal/shipping"
 	"context"
+	"io"
 )
 
 type ProductService interface {
 	CreateProduct(ctx context.Context, product *models.Product) error
-	GetAllProducts(ctx context.Context) ([]*models.Product, error)
+	GetAllProducts(ctx context.Context, filter models.ProductFilter) ([]*models.Product, error)
+	SearchProducts(ctx context.Context, query models.ProductSearch) (*models.SearchResult, error)
 	GetProductByID(ctx context.Context, id int) (*models.Product, error)
 	UpdateProduct(ctx context.Context, product *models.Product) error
 	DeleteProduct(ctx context.Context, id int) error
+	ImportProducts(ctx context.Context, reader bulk.ProductReader, opts ImportOptions) (*models.ImportReport, error)
+	ExportProducts(ctx context.Context, format bulk.Format, w io.Writer) error
+
+	GetVariants(ctx context.Context, productID int) ([]*models.ProductVariant, error)
+	CreateVariant(ctx context.Context, variant *models.ProductVariant) error
+	UpdateVariant(ctx context.Context, variant *models.ProductVariant) error
+	DeleteVariant(ctx context.Context, productID, variantID int) error
+
+	GetTags(ctx context.Context) ([]models.TagCount, error)
+	GetStockMovements(ctx context.Context, productID int) ([]models.StockMovement, error)
+	GetLowStock(ctx context.Context) ([]models.LowStockItem, error)
+}
+
+// ImportOptions управляет массовым импортом товаров.
+// BatchSize - количество строк, записываемых в одной транзакции.
+type ImportOptions struct {
+	DryRun    bool
+	BatchSize int
+}
+
+// AuditService читает журнал аудита изменений товаров и заказов и
+// удаляет записи по сроку хранения.
+type AuditService interface {
+	GetAuditLog(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error)
+	PurgeAuditLog(ctx context.Context) (int, error)
 }
 
 type OrderService interface {
@@ -19,4 +49,160 @@ type OrderService interface {
 	GetOrderByID(ctx context.Context, id int) (*models.Order, error)
 	UpdateOrder(ctx context.Context, order *models.Order) error
 	DeleteOrder(ctx context.Context, id int) error
+	ExportOrders(ctx context.Context, filter models.OrderFilter, format bulk.Format, w io.Writer) error
+}
+
+// PaymentService проводит оплату заказов. Итог попытки оплаты отражается
+// в статусе models.Payment, ошибка означает, что попытка не состоялась.
+type PaymentService interface {
+	PayOrder(ctx context.Context, orderID int, method string) (*models.Payment, error)
+	GetOrderPayments(ctx context.Context, orderID int) ([]models.Payment, error)
+	HandleEvent(ctx context.Context, event payment.Event) (*models.Payment, error)
+	VoidPayment(ctx context.Context, id int) (*models.Payment, error)
+	RefundPayment(ctx context.Context, id, amount int) (*models.Payment, error)
+}
+
+// ReturnService ведет возвраты: заявка, решение сотрудника, прием товара
+// на склад или списание и возврат денег через PaymentService.
+type ReturnService interface {
+	CreateReturn(ctx context.Context, ret *models.ReturnRequest) error
+	GetReturn(ctx context.Context, id int) (*models.ReturnRequest, error)
+	GetOrderReturns(ctx context.Context, orderID int) ([]models.ReturnRequest, error)
+	ApproveReturn(ctx context.Context, id int, note string) (*models.ReturnRequest, error)
+	RejectReturn(ctx context.Context, id int, note string) (*models.ReturnRequest, error)
+	ReceiveReturn(ctx context.Context, id int, dispositions map[int]string) (*models.ReturnRequest, error)
+	RefundReturn(ctx context.Context, id, amount int) (*models.ReturnRequest, error)
+}
+
+// ShipmentService ведет отправления заказа. Статус заказа пересчитывается
+// по отправлениям после каждого изменения, см. models.FulfillmentStatus.
+type ShipmentService interface {
+	CreateShipment(ctx context.Context, shipment *models.Shipment) error
+	GetShipment(ctx context.Context, id int) (*models.Shipment, error)
+	GetOrderShipments(ctx context.Context, orderID int) ([]models.Shipment, error)
+	UpdateShipment(ctx context.Context, id int, status, carrier, trackingNumber string) (*models.Shipment, error)
+}
+
+// PromotionService управляет промоакциями. Скидки применяются при создании
+// заказа в OrderService; PreviewCart показывает их заранее.
+type PromotionService interface {
+	CreatePromotion(ctx context.Context, promotion *models.Promotion) error
+	GetAllPromotions(ctx context.Context) ([]*models.Promotion, error)
+	GetPromotion(ctx context.Context, id int) (*models.Promotion, error)
+	UpdatePromotion(ctx context.Context, promotion *models.Promotion) error
+	DeletePromotion(ctx context.Context, id int) error
+	PreviewCart(ctx context.Context, cartID int, token, code string) (*models.Pricing, error)
+}
+
+// ShippingService рассчитывает доставку корзины до оформления. Стоимость
+// доставки заказа рассчитывается в OrderService тем же способом.
+type ShippingService interface {
+	QuoteCart(ctx context.Context, cartID int, token string, address *models.Address, promoCode string) ([]shipping.Quote, error)
+}
+
+type CustomerService interface {
+	CreateCustomer(ctx context.Context, customer *models.Customer) error
+	GetAllCustomers(ctx context.Context) ([]*models.Customer, error)
+	GetCustomerByID(ctx context.Context, id int) (*models.Customer, error)
+	UpdateCustomer(ctx context.Context, customer *models.Customer) error
+	DeleteCustomer(ctx context.Context, id int) error
+	GetCustomerOrders(ctx context.Context, id int) ([]*models.Order, error)
+}
+
+// CartService работает с корзинами. Все операции с существующей корзиной
+// требуют ее токен.
+type CartService interface {
+	CreateCart(ctx context.Context, userID int) (*models.Cart, error)
+	GetCart(ctx context.Context, id int, token string) (*models.Cart, error)
+	AddItem(ctx context.Context, id int, token string, item models.CartItem) (*models.Cart, error)
+	UpdateItem(ctx context.Context, id int, token string, itemID, quantity int) (*models.Cart, error)
+	RemoveItem(ctx context.Context, id int, token string, itemID int) (*models.Cart, error)
+	MergeCart(ctx context.Context, id int, token string, userID int) (*models.Cart, error)
+	Checkout(ctx context.Context, id int, token string, address *models.Address, promoCode, shippingMethod string) (*models.Order, error)
+	PurgeExpiredCarts(ctx context.Context) (int, error)
+}
+
+type CategoryService interface {
+	CreateCategory(ctx context.Context, category *models.Category) error
+	GetCategoryTree(ctx context.Context) ([]*models.Category, error)
+	GetCategoryByID(ctx context.Context, id int) (*models.Category, error)
+	UpdateCategory(ctx context.Context, category *models.Category) error
+	MoveCategory(ctx context.Context, id int, parentID *int, position int) (*models.Category, error)
+	DeleteCategory(ctx context.Context, id, reassignTo int) error
+}
+
+type WarehouseService interface {
+	CreateWarehouse(ctx context.Context, warehouse *models.Warehouse) error
+	GetAllWarehouses(ctx context.Context) ([]*models.Warehouse, error)
+	GetWarehouseByID(ctx context.Context, id int) (*models.Warehouse, error)
+	UpdateWarehouse(ctx context.Context, warehouse *models.Warehouse) error
+	DeleteWarehouse(ctx context.Context, id int) error
+	GetWarehouseStock(ctx context.Context, id int) ([]models.StockLevel, error)
+	SetWarehouseStock(ctx context.Context, warehouseID, variantID, quantity int) (*models.StockLevel, error)
+
+	CreateTransfer(ctx context.Context, transfer *models.StockTransfer) error
+	GetAllTransfers(ctx context.Context, status string) ([]*models.StockTransfer, error)
+	GetTransferByID(ctx context.Context, id int) (*models.StockTransfer, error)
+	ShipTransfer(ctx context.Context, id int) (*models.StockTransfer, error)
+	ReceiveTransfer(ctx context.Context, id int) (*models.StockTransfer, error)
+	CancelTransfer(ctx context.Context, id int) (*models.StockTransfer, error)
+
+	ReconcileStock(ctx context.Context) (*models.StockReconciliation, error)
+}
+
+// PurchaseService ведет поставщиков и заказы поставщикам. Приемка товара
+// оприходует его на склад заказа и пересчитывает себестоимость вариантов.
+type PurchaseService interface {
+	CreateSupplier(ctx context.Context, supplier *models.Supplier) error
+	GetAllSuppliers(ctx context.Context) ([]*models.Supplier, error)
+	GetSupplierByID(ctx context.Context, id int) (*models.Supplier, error)
+	UpdateSupplier(ctx context.Context, supplier *models.Supplier) error
+	DeleteSupplier(ctx context.Context, id int) error
+
+	CreatePurchaseOrder(ctx context.Context, order *models.PurchaseOrder) error
+	GetAllPurchaseOrders(ctx context.Context, status string) ([]*models.PurchaseOrder, error)
+	GetPurchaseOrderByID(ctx context.Context, id int) (*models.PurchaseOrder, error)
+	UpdatePurchaseOrder(ctx context.Context, order *models.PurchaseOrder) error
+	SendPurchaseOrder(ctx context.Context, id int) (*models.PurchaseOrder, error)
+	ReceivePurchaseOrder(ctx context.Context, id int, items []models.GoodsReceiptItem) (*models.PurchaseOrder, error)
+	CancelPurchaseOrder(ctx context.Context, id int) (*models.PurchaseOrder, error)
+}
+
+// ReportService строит отчеты о продажах и остатках. Фильтр проверяется
+// сервисом; ошибки проверки начинаются с "validate".
+type ReportService interface {
+	Sales(ctx context.Context, filter models.ReportFilter) ([]models.SalesPoint, error)
+	TopProducts(ctx context.Context, filter models.ReportFilter) ([]models.ProductSales, error)
+	OrderValue(ctx context.Context, filter models.ReportFilter) (*models.OrderValue, error)
+	OrderStatuses(ctx context.Context, filter models.ReportFilter) ([]models.StatusCount, error)
+	StockValuation(ctx context.Context, filter models.ReportFilter) ([]models.StockValuation, error)
+	ExportReport(ctx context.Context, report string, filter models.ReportFilter, format bulk.Format, w io.Writer) error
+}
+
+// OutboxService показывает доменные события outbox и позволяет повторно
+// поставить событие в очередь доставки.
+type OutboxService interface {
+	GetOutboxEvents(ctx context.Context, filter models.OutboxFilter) ([]models.OutboxEvent, error)
+	GetOutboxEventByID(ctx context.Context, id int) (*models.OutboxEvent, error)
+	ReplayOutboxEvent(ctx context.Context, id int) (*models.OutboxEvent, error)
+}
+
+// WebhookService управляет подписками внешних систем на доменные события
+// и журналом их доставок.
+type WebhookService interface {
+	CreateWebhook(ctx context.Context, webhook *models.Webhook) error
+	GetWebhooks(ctx context.Context) ([]models.Webhook, error)
+	GetWebhookByID(ctx context.Context, id int) (*models.Webhook, error)
+	UpdateWebhook(ctx context.Context, webhook *models.Webhook) error
+	DeleteWebhook(ctx context.Context, id int) error
+	GetWebhookDeliveries(ctx context.Context, filter models.WebhookDeliveryFilter) ([]models.WebhookDelivery, error)
+	GetWebhookDeliveryByID(ctx context.Context, id int) (*models.WebhookDelivery, error)
+	RedeliverWebhook(ctx context.Context, deliveryID int) (*models.WebhookDelivery, error)
+}
+
+// OrderStreamService подписывает клиентов на поток изменений заказов.
+// Ошибки прав начинаются с "unauthorized" или "forbidden".
+type OrderStreamService interface {
+	Authorize(token string) (models.StreamAccess, error)
+	Subscribe(ctx context.Context, access models.StreamAccess, filter models.OrderStreamFilter, lastEventID string) (*OrderSubscription, error)
 }
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"backend-store/internal/models"
+	"backend-store/internal/notify"
+	"backend-store/internal/storage"
+	"backend-store/pkg/logger"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// StockObserver получает сигнал после фиксации транзакции, изменившей остатки.
+type StockObserver interface {
+	StockChanged()
+}
+
+// commitStockChange фиксирует транзакцию, изменившую остатки, и сообщает об
+// этом наблюдателю.
+func commitStockChange(tx storage.StorageTx, observer StockObserver) error {
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	if observer != nil {
+		observer.StockChanged()
+	}
+	return nil
+}
+
+// LowStockMonitor в фоне сравнивает остатки товаров с порогом дозаказа.
+// Проверка запускается после изменения остатков и по расписанию; при
+// достижении порога открывается предупреждение и отправляется уведомление.
+// Пока предупреждение открыто, повторных уведомлений нет; оно закрывается,
+// когда остаток поднимается выше порога.
+type LowStockMonitor struct {
+	storage  storage.Storage
+	notifier notify.Notifier
+	interval time.Duration
+	log      logger.Log
+
+	trigger chan struct{}
+	mu      sync.Mutex
+}
+
+const defaultLowStockInterval = 5 * time.Minute
+
+func NewLowStockMonitor(storage storage.Storage, notifier notify.Notifier, interval time.Duration, log logger.Log) *LowStockMonitor {
+	if interval <= 0 {
+		interval = defaultLowStockInterval
+	}
+	return &LowStockMonitor{
+		storage:  storage,
+		notifier: notifier,
+		interval: interval,
+		log:      log,
+		trigger:  make(chan struct{}, 1),
+	}
+}
+
+// StockChanged ставит внеочередную проверку. Сигналы, пришедшие до ее
+// начала, схлопываются в одну проверку.
+func (m *LowStockMonitor) StockChanged() {
+	select {
+	case m.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// Run выполняет проверки до отмены ctx.
+func (m *LowStockMonitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := m.Evaluate(ctx); err != nil && ctx.Err() == nil {
+			m.log.Error(fmt.Errorf("low stock evaluation failed: %w", err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-m.trigger:
+		}
+	}
+}
+
+// Evaluate открывает предупреждения для товаров, достигших порога, закрывает
+// предупреждения пополненных товаров и отправляет недоставленные уведомления.
+func (m *LowStockMonitor) Evaluate(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	products, err := m.storage.GetAllProducts(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get products: %w", err)
+	}
+	alerts, err := m.storage.GetOpenLowStockAlerts(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get low stock alerts: %w", err)
+	}
+	open := make(map[int]models.LowStockAlert, len(alerts))
+	for _, a := range alerts {
+		open[a.ProductID] = a
+	}
+
+	now := time.Now()
+	for _, product := range products {
+		alert, alerted := open[product.ID]
+		switch {
+		case !product.IsLowStock() && alerted:
+			if err := m.storage.ResolveLowStockAlert(ctx, alert.ID, now); err != nil {
+				return fmt.Errorf("failed to resolve low stock alert: %w", err)
+			}
+		case product.IsLowStock() && !alerted:
+			alert = models.LowStockAlert{
+				ProductID:       product.ID,
+				Quantity:        product.Quantity,
+				ReorderPoint:    product.ReorderPoint,
+				ReorderQuantity: product.ReorderQuantity,
+			}
+			if err := m.storage.CreateLowStockAlert(ctx, &alert); err != nil {
+				// Предупреждение уже открыл другой экземпляр приложения.
+				if strings.Contains(err.Error(), "already exists") {
+					continue
+				}
+				return fmt.Errorf("failed to create low stock alert: %w", err)
+			}
+			m.deliver(ctx, alert, product)
+		case alerted && alert.NotifiedAt == nil:
+			m.deliver(ctx, alert, product)
+		}
+	}
+	return nil
+}
+
+// deliver отправляет уведомление по предупреждению. При ошибке
+// предупреждение остается неотмеченным и уведомление повторяется при
+// следующей проверке.
+func (m *LowStockMonitor) deliver(ctx context.Context, alert models.LowStockAlert, product *models.Product) {
+	if err := m.notifier.Notify(ctx, models.NewLowStockEvent(alert, product)); err != nil {
+		m.log.Error(fmt.Errorf("failed to notify about low stock of product %d: %w", product.ID, err))
+		return
+	}
+	if err := m.storage.MarkLowStockAlertNotified(ctx, alert.ID, time.Now()); err != nil {
+		m.log.Error(fmt.Errorf("failed to mark low stock alert %d notified: %w", alert.ID, err))
+	}
+}
+
+// GetLowStock возвращает товары, остаток которых не выше порога дозаказа,
+// с отметкой об открытом предупреждении.
+func (s *productService) GetLowStock(ctx context.Context) ([]models.LowStockItem, error) {
+	tx, err := s.storage.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	products, err := tx.GetAllProducts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get products: %w", err)
+	}
+	alerts, err := tx.GetOpenLowStockAlerts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get low stock alerts: %w", err)
+	}
+	alertedAt := make(map[int]time.Time, len(alerts))
+	for _, a := range alerts {
+		alertedAt[a.ProductID] = a.CreatedAt
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	items := []models.LowStockItem{}
+	for _, p := range products {
+		if !p.IsLowStock() {
+			continue
+		}
+		item := models.LowStockItem{
+			ProductID:       p.ID,
+			SKU:             p.SKU,
+			Name:            p.Name,
+			Quantity:        p.Quantity,
+			ReorderPoint:    p.ReorderPoint,
+			ReorderQuantity: p.ReorderQuantity,
+		}
+		if at, ok := alertedAt[p.ID]; ok {
+			item.AlertedAt = &at
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
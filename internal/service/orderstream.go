@@ -0,0 +1,259 @@
+package service
+
+import (
+	"backend-store/internal/models"
+	"backend-store/internal/storage"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultOrderStreamBuffer = 1000
+	// orderSubscriberBuffer - сколько событий ждет медленного клиента;
+	// при переполнении клиент отключается и переподключается с
+	// Last-Event-ID.
+	orderSubscriberBuffer = 64
+)
+
+// OrderStream раздает изменения заказов подписчикам потока SSE. События
+// поступают от OutboxDispatcher после фиксации транзакции; последние
+// события хранятся в буфере для возобновления по Last-Event-ID. Буфер и
+// подписчики живут в памяти экземпляра, поэтому клиент видит события,
+// доставленные диспетчером этого экземпляра.
+type OrderStream struct {
+	mu          sync.Mutex
+	size        int
+	buffer      []models.OrderStreamEvent
+	buffered    map[int]bool
+	subscribers map[*orderSubscriber]struct{}
+}
+
+type orderSubscriber struct {
+	filter models.OrderStreamFilter
+	events chan models.OrderStreamEvent
+}
+
+func NewOrderStream(size int) *OrderStream {
+	if size <= 0 {
+		size = defaultOrderStreamBuffer
+	}
+	return &OrderStream{
+		size:        size,
+		buffered:    make(map[int]bool),
+		subscribers: make(map[*orderSubscriber]struct{}),
+	}
+}
+
+// HandleEvent публикует событие outbox о заказе; события других типов
+// пропускаются.
+func (s *OrderStream) HandleEvent(ctx context.Context, event models.OutboxEvent) error {
+	streamEvent, ok, err := models.NewOrderStreamEvent(event)
+	if err != nil || !ok {
+		return err
+	}
+	s.Publish(streamEvent)
+	return nil
+}
+
+// Publish добавляет событие в буфер и рассылает подходящим подписчикам.
+// Повторно доставленное диспетчером событие игнорируется.
+func (s *OrderStream) Publish(event models.OrderStreamEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.buffered[event.ID] {
+		return
+	}
+	if len(s.buffer) == s.size {
+		delete(s.buffered, s.buffer[0].ID)
+		s.buffer = s.buffer[1:]
+	}
+	s.buffer = append(s.buffer, event)
+	s.buffered[event.ID] = true
+
+	for sub := range s.subscribers {
+		if !sub.filter.Match(event) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			delete(s.subscribers, sub)
+			close(sub.events)
+		}
+	}
+}
+
+// subscribe регистрирует подписчика и возвращает события буфера после
+// lastEventID. reset равен true, если lastEventID в буфере нет.
+func (s *OrderStream) subscribe(filter models.OrderStreamFilter, lastEventID string) (replay []models.OrderStreamEvent, reset bool, sub *orderSubscriber) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if lastEventID != "" {
+		pos := -1
+		for i, e := range s.buffer {
+			if e.EventID() == lastEventID {
+				pos = i
+				break
+			}
+		}
+		if pos < 0 {
+			reset = true
+		} else {
+			for _, e := range s.buffer[pos+1:] {
+				if filter.Match(e) {
+					replay = append(replay, e)
+				}
+			}
+		}
+	}
+
+	sub = &orderSubscriber{filter: filter, events: make(chan models.OrderStreamEvent, orderSubscriberBuffer)}
+	s.subscribers[sub] = struct{}{}
+	return replay, reset, sub
+}
+
+func (s *OrderStream) unsubscribe(sub *orderSubscriber) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.subscribers[sub]; ok {
+		delete(s.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+// OrderSubscription - подписка на поток заказов. Events закрывается, если
+// клиент не успевает читать события.
+type OrderSubscription struct {
+	// Snapshot - текущее состояние заказа для потока одного заказа.
+	Snapshot *models.Order
+	// Replay - события буфера после Last-Event-ID.
+	Replay []models.OrderStreamEvent
+	// Reset означает, что Last-Event-ID уже вытеснен из буфера и клиенту
+	// стоит перечитать заказы.
+	Reset  bool
+	Events <-chan models.OrderStreamEvent
+
+	cancel func()
+}
+
+// Close отписывает клиента от потока.
+func (s *OrderSubscription) Close() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+}
+
+type orderStreamService struct {
+	storage storage.Storage
+	stream  *OrderStream
+	secret  string
+}
+
+// NewOrderStreamService создает сервис потока заказов. С пустым secret
+// ни один токен не проходит проверку.
+func NewOrderStreamService(storage storage.Storage, stream *OrderStream, secret string) OrderStreamService {
+	return &orderStreamService{storage: storage, stream: stream, secret: secret}
+}
+
+func (s *orderStreamService) Authorize(token string) (models.StreamAccess, error) {
+	if s.secret == "" {
+		return models.StreamAccess{}, errors.New("unauthorized: order streams are not configured")
+	}
+	if token == "" {
+		return models.StreamAccess{}, errors.New("unauthorized: stream token is required")
+	}
+	access, err := ParseStreamToken(s.secret, token, time.Now())
+	if err != nil {
+		return models.StreamAccess{}, fmt.Errorf("unauthorized: %w", err)
+	}
+	return access, nil
+}
+
+func (s *orderStreamService) Subscribe(ctx context.Context, access models.StreamAccess, filter models.OrderStreamFilter, lastEventID string) (*OrderSubscription, error) {
+	if !access.Staff() {
+		if filter.CustomerID != 0 && filter.CustomerID != access.CustomerID {
+			return nil, errors.New("forbidden: cannot stream orders of another customer")
+		}
+		filter.CustomerID = access.CustomerID
+	}
+
+	// Подписка до чтения заказа: событие между чтением и подпиской не
+	// теряется, а повтор состояния из снимка безопасен.
+	replay, reset, sub := s.stream.subscribe(filter, lastEventID)
+	result := &OrderSubscription{
+		Replay: replay,
+		Reset:  reset,
+		Events: sub.events,
+		cancel: func() { s.stream.unsubscribe(sub) },
+	}
+
+	if filter.OrderID != 0 {
+		order, err := s.storage.GetOrderByID(ctx, filter.OrderID)
+		if err != nil || !access.CanSee(order.CustomerID) {
+			result.Close()
+			return nil, fmt.Errorf("order %d not found", filter.OrderID)
+		}
+		result.Snapshot = order
+	}
+	return result, nil
+}
+
+// streamClaims - содержимое токена потока заказов.
+type streamClaims struct {
+	CustomerID int   `json:"customer_id,omitempty"`
+	ExpiresAt  int64 `json:"exp"`
+}
+
+// IssueStreamToken выдает токен потока заказов с правами access до
+// expiresAt. Токен - "<base64url claims>.<base64url HMAC-SHA256>"; его
+// может выпускать любой сервис, знающий secret, например витрина после
+// входа покупателя.
+func IssueStreamToken(secret string, access models.StreamAccess, expiresAt time.Time) (string, error) {
+	claims, err := json.Marshal(streamClaims{CustomerID: access.CustomerID, ExpiresAt: expiresAt.Unix()})
+	if err != nil {
+		return "", err
+	}
+	payload := base64.RawURLEncoding.EncodeToString(claims)
+	return payload + "." + signStreamToken(secret, payload), nil
+}
+
+// ParseStreamToken проверяет подпись и срок токена потока заказов.
+func ParseStreamToken(secret, token string, now time.Time) (models.StreamAccess, error) {
+	payload, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return models.StreamAccess{}, errors.New("malformed stream token")
+	}
+	if !hmac.Equal([]byte(sig), []byte(signStreamToken(secret, payload))) {
+		return models.StreamAccess{}, errors.New("invalid stream token signature")
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return models.StreamAccess{}, errors.New("malformed stream token")
+	}
+	var claims streamClaims
+	if err := json.Unmarshal(data, &claims); err != nil {
+		return models.StreamAccess{}, errors.New("malformed stream token")
+	}
+	if !now.Before(time.Unix(claims.ExpiresAt, 0)) {
+		return models.StreamAccess{}, errors.New("stream token expired")
+	}
+	return models.StreamAccess{CustomerID: claims.CustomerID}, nil
+}
+
+func signStreamToken(secret, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
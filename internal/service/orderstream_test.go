@@ -0,0 +1,47 @@
+package service
+
+import (
+	"backend-store/internal/models"
+	"backend-store/internal/storage"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderStreamService_Authorize(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	stream := NewOrderStream(0)
+	customerToken, err := IssueStreamToken("s3cret", models.StreamAccess{CustomerID: 7}, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	expiredToken, err := IssueStreamToken("s3cret", models.StreamAccess{}, time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		secret  string
+		token   string
+		access  models.StreamAccess
+		wantErr bool
+	}{
+		{name: "no secret rejects without token", secret: "", token: "", wantErr: true},
+		{name: "no secret rejects any token", secret: "", token: customerToken, wantErr: true},
+		{name: "missing token", secret: "s3cret", token: "", wantErr: true},
+		{name: "wrong secret", secret: "other", token: customerToken, wantErr: true},
+		{name: "expired token", secret: "s3cret", token: expiredToken, wantErr: true},
+		{name: "customer token", secret: "s3cret", token: customerToken, access: models.StreamAccess{CustomerID: 7}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			access, err := NewOrderStreamService(store, stream, tt.secret).Authorize(tt.token)
+			if tt.wantErr {
+				assert.ErrorContains(t, err, "unauthorized")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.access, access)
+		})
+	}
+}
@@ -0,0 +1,335 @@
+package service
+
+import (
+	"backend-store/internal/models"
+	"backend-store/internal/storage"
+	"backend-store/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// EventHandler обрабатывает доменное событие. Ошибка означает, что событие
+// будет доставлено повторно, в том числе обработчикам, уже принявшим его.
+type EventHandler interface {
+	HandleEvent(ctx context.Context, event models.OutboxEvent) error
+}
+
+// EventHandlerFunc позволяет использовать функцию как EventHandler.
+type EventHandlerFunc func(ctx context.Context, event models.OutboxEvent) error
+
+func (f EventHandlerFunc) HandleEvent(ctx context.Context, event models.OutboxEvent) error {
+	return f(ctx, event)
+}
+
+type eventSubscription struct {
+	name    string
+	types   map[string]bool
+	handler EventHandler
+}
+
+func (s eventSubscription) accepts(eventType string) bool {
+	return len(s.types) == 0 || s.types[eventType]
+}
+
+// OutboxDispatcher в фоне доставляет события outbox зарегистрированным
+// обработчикам. Событие считается доставленным, когда его приняли все
+// подходящие обработчики; при ошибке доставка повторяется с растущим
+// интервалом, а после maxAttempts попыток событие отбрасывается.
+type OutboxDispatcher struct {
+	storage     storage.Storage
+	interval    time.Duration
+	maxAttempts int
+	log         logger.Log
+
+	mu       sync.RWMutex
+	handlers []eventSubscription
+	trigger  chan struct{}
+}
+
+const (
+	defaultOutboxInterval    = time.Second
+	defaultOutboxMaxAttempts = 10
+	outboxBatchSize          = 100
+	// outboxLease - время на доставку выбранной пачки событий. Если
+	// экземпляр не успел отметить результат, событие снова выдается по его
+	// истечении.
+	outboxLease = time.Minute
+	// outboxHandleTimeout ограничивает доставку одного события. Новое
+	// событие пачки начинается, только пока до конца аренды остается не
+	// меньше двух таймаутов: обработчик завершится, а результат будет
+	// записан до того, как событие выдадут повторно.
+	outboxHandleTimeout = 15 * time.Second
+)
+
+func NewOutboxDispatcher(storage storage.Storage, interval time.Duration, maxAttempts int, log logger.Log) *OutboxDispatcher {
+	if interval <= 0 {
+		interval = defaultOutboxInterval
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = defaultOutboxMaxAttempts
+	}
+	return &OutboxDispatcher{
+		storage:     storage,
+		interval:    interval,
+		maxAttempts: maxAttempts,
+		log:         log,
+		trigger:     make(chan struct{}, 1),
+	}
+}
+
+// Register подписывает обработчик на события типов types; без типов
+// обработчик получает все события. name используется в сообщениях об
+// ошибках доставки.
+func (d *OutboxDispatcher) Register(name string, handler EventHandler, types ...string) {
+	sub := eventSubscription{name: name, handler: handler}
+	if len(types) > 0 {
+		sub.types = make(map[string]bool, len(types))
+		for _, t := range types {
+			sub.types[t] = true
+		}
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.handlers = append(d.handlers, sub)
+}
+
+// Wake ставит внеочередной проход, не дожидаясь интервала опроса.
+func (d *OutboxDispatcher) Wake() {
+	wake(d.trigger)
+}
+
+// Run доставляет события до отмены ctx.
+func (d *OutboxDispatcher) Run(ctx context.Context) {
+	poll(ctx, d.interval, d.trigger, func() {
+		if _, err := d.Dispatch(ctx); err != nil && ctx.Err() == nil {
+			d.log.Error(fmt.Errorf("outbox dispatch failed: %w", err))
+		}
+	})
+}
+
+// poll вызывает pass сразу, затем каждые interval и по сигналу trigger до
+// отмены ctx.
+func poll(ctx context.Context, interval time.Duration, trigger <-chan struct{}, pass func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		pass()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-trigger:
+		}
+	}
+}
+
+// wake сигналит trigger, не блокируясь, если сигнал уже ожидает.
+func wake(trigger chan<- struct{}) {
+	select {
+	case trigger <- struct{}{}:
+	default:
+	}
+}
+
+// Dispatch доставляет все события, срок попытки которых наступил, и
+// возвращает число обработанных событий.
+func (d *OutboxDispatcher) Dispatch(ctx context.Context) (int, error) {
+	processed := 0
+	for {
+		claimedAt := time.Now()
+		events, err := d.storage.ClaimOutboxEvents(ctx, claimedAt, outboxLease, outboxBatchSize)
+		if err != nil {
+			return processed, err
+		}
+		// Оставшиеся события пачки выдаются снова после окончания аренды.
+		deadline := claimedAt.Add(outboxLease - 2*outboxHandleTimeout)
+		for i := range events {
+			if time.Now().After(deadline) {
+				return processed, nil
+			}
+			if err := d.deliver(ctx, &events[i]); err != nil {
+				return processed, err
+			}
+			processed++
+		}
+		if len(events) < outboxBatchSize {
+			return processed, nil
+		}
+	}
+}
+
+func (d *OutboxDispatcher) deliver(ctx context.Context, event *models.OutboxEvent) error {
+	handleCtx, cancel := context.WithTimeout(ctx, outboxHandleTimeout)
+	err := d.handle(handleCtx, *event)
+	cancel()
+
+	now := time.Now()
+	if err != nil {
+		event.Failed(err, now, d.maxAttempts)
+		if event.Status == models.OutboxDead {
+			d.log.Error(fmt.Errorf("outbox event %d (%s) dead after %d attempts: %w", event.ID, event.Type, event.Attempts, err))
+		}
+	} else {
+		event.Delivered(now)
+	}
+
+	if err := d.storage.UpdateOutboxEvent(ctx, event); err != nil {
+		return fmt.Errorf("failed to update outbox event %d: %w", event.ID, err)
+	}
+	return nil
+}
+
+func (d *OutboxDispatcher) handle(ctx context.Context, event models.OutboxEvent) error {
+	d.mu.RLock()
+	handlers := d.handlers
+	d.mu.RUnlock()
+
+	var errs []error
+	for _, sub := range handlers {
+		if !sub.accepts(event.Type) {
+			continue
+		}
+		if err := sub.handler.HandleEvent(ctx, event); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", sub.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+type outboxService struct {
+	storage    storage.Storage
+	dispatcher *OutboxDispatcher
+}
+
+func NewOutboxService(storage storage.Storage, dispatcher *OutboxDispatcher) OutboxService {
+	return &outboxService{storage: storage, dispatcher: dispatcher}
+}
+
+func (s *outboxService) GetOutboxEvents(ctx context.Context, filter models.OutboxFilter) ([]models.OutboxEvent, error) {
+	switch filter.Status {
+	case "", models.OutboxPending, models.OutboxDelivered, models.OutboxDead:
+	default:
+		return nil, fmt.Errorf("validate: invalid status %q", filter.Status)
+	}
+	if filter.Limit <= 0 || filter.Limit > 500 {
+		filter.Limit = 100
+	}
+
+	events, err := s.storage.GetOutboxEvents(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get outbox events: %w", err)
+	}
+	return events, nil
+}
+
+func (s *outboxService) GetOutboxEventByID(ctx context.Context, id int) (*models.OutboxEvent, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid outbox event ID")
+	}
+	return s.storage.GetOutboxEventByID(ctx, id)
+}
+
+// ReplayOutboxEvent ставит доставленное или отброшенное событие в очередь
+// заново.
+func (s *outboxService) ReplayOutboxEvent(ctx context.Context, id int) (*models.OutboxEvent, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid outbox event ID")
+	}
+
+	tx, err := s.storage.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	event, err := tx.GetOutboxEventByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := event.Replay(time.Now()); err != nil {
+		return nil, err
+	}
+	if err := tx.UpdateOutboxEvent(ctx, event); err != nil {
+		return nil, fmt.Errorf("failed to update outbox event: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	if s.dispatcher != nil {
+		s.dispatcher.Wake()
+	}
+	return event, nil
+}
+
+// recordEvent записывает доменное событие в outbox в транзакции tx.
+func recordEvent(ctx context.Context, tx storage.StorageTx, eventType, aggregateType string, aggregateID int, payload interface{}) error {
+	event, err := models.NewOutboxEvent(eventType, aggregateType, aggregateID, payload)
+	if err != nil {
+		return err
+	}
+	if err := tx.CreateOutboxEvent(ctx, event); err != nil {
+		return fmt.Errorf("failed to record %s event: %w", eventType, err)
+	}
+	return nil
+}
+
+// recordOrderStatusChange записывает смену статуса заказа, если статус
+// действительно изменился.
+func recordOrderStatusChange(ctx context.Context, tx storage.StorageTx, order *models.Order, status string) error {
+	if order.Status == status {
+		return nil
+	}
+	return recordEvent(ctx, tx, models.EventOrderStatusChanged, models.AggregateOrder, order.ID, models.OrderStatusChangedPayload{
+		OrderID:    order.ID,
+		CustomerID: order.CustomerID,
+		From:       order.Status,
+		To:         status,
+	})
+}
+
+// setOrderStatus меняет только статус заказа и записывает смену статуса в
+// outbox и журнал аудита. order после вызова содержит новый статус.
+func setOrderStatus(ctx context.Context, tx storage.StorageTx, order *models.Order, status string) error {
+	if order.Status == status {
+		return nil
+	}
+	before := *order
+	if err := recordOrderStatusChange(ctx, tx, order, status); err != nil {
+		return err
+	}
+	if err := tx.UpdateOrderStatus(ctx, order.ID, status); err != nil {
+		return fmt.Errorf("failed to update order status: %w", err)
+	}
+
+	order.Status = status
+	return recordAudit(ctx, tx, models.AuditUpdate, models.AggregateOrder, order.ID, &before, order)
+}
+
+// recordPriceChange записывает изменение базовой цены товара.
+func recordPriceChange(ctx context.Context, tx storage.StorageTx, existing, product *models.Product) error {
+	if existing.Price == product.Price {
+		return nil
+	}
+	return recordEvent(ctx, tx, models.EventProductPriceChanged, models.AggregateProduct, product.ID, models.ProductPriceChangedPayload{
+		ProductID: product.ID,
+		SKU:       product.SKU,
+		OldPrice:  existing.Price,
+		NewPrice:  product.Price,
+	})
+}
+
+// recordStockDepleted записывает, что на складах не осталось варианта.
+func recordStockDepleted(ctx context.Context, tx storage.StorageTx, variant *models.ProductVariant) error {
+	return recordEvent(ctx, tx, models.EventStockDepleted, models.AggregateProduct, variant.ProductID, models.StockDepletedPayload{
+		ProductID: variant.ProductID,
+		VariantID: variant.ID,
+		SKU:       variant.SKU,
+	})
+}
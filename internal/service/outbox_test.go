@@ -0,0 +1,157 @@
+package service
+
+import (
+	"backend-store/internal/models"
+	"backend-store/internal/storage"
+	"backend-store/pkg/logger"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// enqueueTestEvent сохраняет событие о заказе в outbox.
+func enqueueTestEvent(t *testing.T, store storage.Storage) int {
+	t.Helper()
+	ctx := context.Background()
+	tx, err := store.BeginTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback()
+	require.NoError(t, recordEvent(ctx, tx, models.EventOrderCreated, models.AggregateOrder, 1, map[string]int{"order_id": 1}))
+	require.NoError(t, tx.Commit())
+
+	events, err := store.GetOutboxEvents(ctx, models.OutboxFilter{})
+	require.NoError(t, err)
+	require.NotEmpty(t, events)
+	return events[0].ID
+}
+
+// makeDue переносит следующую попытку события на текущий момент, чтобы не
+// ждать паузы между попытками.
+func makeDue(t *testing.T, store storage.Storage, id int) {
+	t.Helper()
+	ctx := context.Background()
+	event, err := store.GetOutboxEventByID(ctx, id)
+	require.NoError(t, err)
+	event.NextAttemptAt = time.Now().Add(-time.Second)
+	require.NoError(t, store.UpdateOutboxEvent(ctx, event))
+}
+
+func TestOutboxDispatcher_RetryAndDeadLetter(t *testing.T) {
+	tests := []struct {
+		name         string
+		failures     int // сколько первых попыток обработчик отклоняет
+		maxAttempts  int
+		rounds       int
+		wantStatus   string
+		wantAttempts int
+		wantCalls    int
+	}{
+		{name: "delivered first time", failures: 0, maxAttempts: 3, rounds: 1, wantStatus: models.OutboxDelivered, wantAttempts: 1, wantCalls: 1},
+		{name: "delivered after retries", failures: 2, maxAttempts: 3, rounds: 3, wantStatus: models.OutboxDelivered, wantAttempts: 3, wantCalls: 3},
+		{name: "pending between retries", failures: 5, maxAttempts: 3, rounds: 2, wantStatus: models.OutboxPending, wantAttempts: 2, wantCalls: 2},
+		{name: "dead after max attempts", failures: 5, maxAttempts: 3, rounds: 3, wantStatus: models.OutboxDead, wantAttempts: 3, wantCalls: 3},
+		{name: "dead event is not delivered again", failures: 5, maxAttempts: 3, rounds: 5, wantStatus: models.OutboxDead, wantAttempts: 3, wantCalls: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+			store := storage.NewMemoryStorage()
+			dispatcher := NewOutboxDispatcher(store, time.Second, tt.maxAttempts, logger.New("error"))
+			calls := 0
+			dispatcher.Register("test", EventHandlerFunc(func(ctx context.Context, event models.OutboxEvent) error {
+				calls++
+				if calls <= tt.failures {
+					return errors.New("subscriber unavailable")
+				}
+				return nil
+			}))
+			id := enqueueTestEvent(t, store)
+
+			// Act
+			for i := 0; i < tt.rounds; i++ {
+				if i > 0 {
+					makeDue(t, store, id)
+				}
+				_, err := dispatcher.Dispatch(ctx)
+				require.NoError(t, err)
+			}
+
+			// Assert
+			event, err := store.GetOutboxEventByID(ctx, id)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, event.Status)
+			assert.Equal(t, tt.wantAttempts, event.Attempts)
+			assert.Equal(t, tt.wantCalls, calls)
+			if tt.wantStatus == models.OutboxDelivered {
+				assert.Empty(t, event.LastError)
+				assert.NotNil(t, event.DeliveredAt)
+			} else {
+				assert.Equal(t, "test: subscriber unavailable", event.LastError)
+			}
+		})
+	}
+}
+
+func TestOutboxDispatcher_FailureDelaysNextAttempt(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	store := storage.NewMemoryStorage()
+	dispatcher := NewOutboxDispatcher(store, time.Second, 3, logger.New("error"))
+	dispatcher.Register("test", EventHandlerFunc(func(ctx context.Context, event models.OutboxEvent) error {
+		return errors.New("subscriber unavailable")
+	}))
+	id := enqueueTestEvent(t, store)
+
+	// Act
+	first, err := dispatcher.Dispatch(ctx)
+	require.NoError(t, err)
+	second, err := dispatcher.Dispatch(ctx)
+	require.NoError(t, err)
+
+	// Assert
+	assert.Equal(t, 1, first)
+	assert.Equal(t, 0, second)
+	event, err := store.GetOutboxEventByID(ctx, id)
+	require.NoError(t, err)
+	assert.True(t, event.NextAttemptAt.After(time.Now()))
+}
+
+func TestOutboxDispatcher_FailingHandlerRetriesAll(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	store := storage.NewMemoryStorage()
+	dispatcher := NewOutboxDispatcher(store, time.Second, 3, logger.New("error"))
+	accepted := 0
+	dispatcher.Register("accepting", EventHandlerFunc(func(ctx context.Context, event models.OutboxEvent) error {
+		accepted++
+		return nil
+	}))
+	dispatcher.Register("failing", EventHandlerFunc(func(ctx context.Context, event models.OutboxEvent) error {
+		return errors.New("subscriber unavailable")
+	}))
+	dispatcher.Register("other types", EventHandlerFunc(func(ctx context.Context, event models.OutboxEvent) error {
+		return errors.New("must not be called")
+	}), models.EventStockDepleted)
+	id := enqueueTestEvent(t, store)
+
+	// Act
+	_, err := dispatcher.Dispatch(ctx)
+	require.NoError(t, err)
+	makeDue(t, store, id)
+	_, err = dispatcher.Dispatch(ctx)
+	require.NoError(t, err)
+
+	// Assert
+	event, err := store.GetOutboxEventByID(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, models.OutboxPending, event.Status)
+	assert.Equal(t, 2, event.Attempts)
+	assert.Equal(t, "failing: subscriber unavailable", event.LastError)
+	assert.Equal(t, 2, accepted)
+}
@@ -0,0 +1,267 @@
+package service
+
+import (
+	"backend-store/internal/models"
+	"backend-store/internal/payment"
+	"backend-store/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type paymentService struct {
+	storage storage.Storage
+	gateway payment.Gateway
+}
+
+// NewPaymentService создает сервис оплаты заказов через gateway. Попытка
+// оплаты сохраняется до обращения к шлюзу, каждый ответ шлюза сразу
+// фиксируется в ней, а транзакция хранилища на время запроса к шлюзу не
+// удерживается.
+func NewPaymentService(storage storage.Storage, gateway payment.Gateway) PaymentService {
+	return &paymentService{storage: storage, gateway: gateway}
+}
+
+// PayOrder блокирует и сразу списывает сумму заказа. Отказ и таймаут шлюза
+// не считаются ошибкой: попытка возвращается в статусе failed. Если шлюз
+// требует подтверждения, попытка ждет уведомления в статусе requires_action.
+func (s *paymentService) PayOrder(ctx context.Context, orderID int, method string) (*models.Payment, error) {
+	if orderID <= 0 {
+		return nil, errors.New("invalid order ID")
+	}
+	if method == "" {
+		return nil, errors.New("validate: payment method is required")
+	}
+
+	p, err := s.start(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	auth, err := s.gateway.Authorize(ctx, payment.AuthorizeRequest{OrderID: orderID, Amount: p.Amount, Method: method})
+	if err != nil {
+		return s.fail(ctx, p, err.Error())
+	}
+
+	p.Reference = auth.Reference
+	if auth.RequiresAction {
+		p.Status = models.PaymentRequiresAction
+		p.ActionURL = auth.ActionURL
+		return p, s.save(ctx, p)
+	}
+	return s.capture(ctx, p)
+}
+
+func (s *paymentService) GetOrderPayments(ctx context.Context, orderID int) ([]models.Payment, error) {
+	if orderID <= 0 {
+		return nil, errors.New("invalid order ID")
+	}
+	if _, err := s.storage.GetOrderByID(ctx, orderID); err != nil {
+		return nil, err
+	}
+	payments, err := s.storage.GetPaymentsByOrder(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payments: %w", err)
+	}
+	return payments, nil
+}
+
+// HandleEvent применяет уведомление шлюза об итоге подтверждения. Повторная
+// доставка уже обработанного уведомления ничего не меняет.
+func (s *paymentService) HandleEvent(ctx context.Context, event payment.Event) (*models.Payment, error) {
+	p, err := s.storage.GetPaymentByReference(ctx, s.gateway.Name(), event.Reference)
+	if err != nil {
+		return nil, err
+	}
+	if p.Status != models.PaymentRequiresAction {
+		return p, nil
+	}
+
+	p.ActionURL = ""
+	switch event.Status {
+	case payment.EventAuthorized:
+		return s.capture(ctx, p)
+	case payment.EventFailed:
+		reason := event.Reason
+		if reason == "" {
+			reason = "authorization failed"
+		}
+		return s.fail(ctx, p, reason)
+	default:
+		return nil, fmt.Errorf("validate: unknown event status %q", event.Status)
+	}
+}
+
+// VoidPayment снимает блокировку, которую не удалось списать.
+func (s *paymentService) VoidPayment(ctx context.Context, id int) (*models.Payment, error) {
+	p, err := s.storage.GetPaymentByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if p.Status != models.PaymentAuthorized {
+		return nil, fmt.Errorf("cannot void payment in status %q", p.Status)
+	}
+
+	if err := s.gateway.Void(ctx, p.Reference); err != nil {
+		return nil, fmt.Errorf("void failed: %w", err)
+	}
+	p.Status = models.PaymentVoided
+	return p, s.save(ctx, p)
+}
+
+// RefundPayment возвращает amount из списанной суммы; amount 0 означает
+// весь остаток. После полного возврата платеж переходит в refunded.
+//
+// Сумма резервируется в хранилище до обращения к шлюзу, поэтому
+// одновременные возвраты не превысят списанную сумму. Если шлюз отказал,
+// резерв снимается.
+func (s *paymentService) RefundPayment(ctx context.Context, id, amount int) (*models.Payment, error) {
+	p, err := s.storage.GetPaymentByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if p.Status != models.PaymentCaptured {
+		return nil, fmt.Errorf("cannot refund payment in status %q", p.Status)
+	}
+
+	remaining := p.Amount - p.RefundedAmount
+	if amount == 0 {
+		amount = remaining
+	}
+	if amount < 1 || amount > remaining {
+		return nil, fmt.Errorf("validate: refund amount must be between 1 and %d", remaining)
+	}
+
+	refunded, err := s.storage.AddPaymentRefund(ctx, id, amount)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.gateway.Refund(ctx, p.Reference, amount); err != nil {
+		if _, releaseErr := s.storage.AddPaymentRefund(ctx, id, -amount); releaseErr != nil {
+			return nil, fmt.Errorf("refund failed: %w (failed to release refund: %v)", err, releaseErr)
+		}
+		return nil, fmt.Errorf("refund failed: %w", err)
+	}
+	return refunded, nil
+}
+
+// start проверяет, что заказ ждет оплаты, и сохраняет новую попытку.
+func (s *paymentService) start(ctx context.Context, orderID int) (*models.Payment, error) {
+	tx, err := s.storage.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	order, err := tx.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != models.OrderStatusPending {
+		return nil, fmt.Errorf("cannot pay order in status %q", order.Status)
+	}
+	if order.Total <= 0 {
+		return nil, errors.New("cannot pay order with zero total")
+	}
+
+	now := time.Now()
+	p := &models.Payment{
+		OrderID:   orderID,
+		Provider:  s.gateway.Name(),
+		Amount:    order.Total,
+		Status:    models.PaymentPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := tx.CreatePayment(ctx, p); err != nil {
+		return nil, fmt.Errorf("failed to create payment: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// capture фиксирует блокировку и списывает сумму. Если списание отклонено,
+// блокировка снимается; если снять не удалось, платеж остается authorized,
+// чтобы его можно было отменить вручную.
+func (s *paymentService) capture(ctx context.Context, p *models.Payment) (*models.Payment, error) {
+	p.Status = models.PaymentAuthorized
+	if err := s.save(ctx, p); err != nil {
+		return nil, err
+	}
+
+	if err := s.gateway.Capture(ctx, p.Reference, p.Amount); err != nil {
+		p.FailureReason = "capture failed: " + err.Error()
+		if voidErr := s.gateway.Void(ctx, p.Reference); voidErr == nil {
+			p.Status = models.PaymentVoided
+		}
+		return p, s.save(ctx, p)
+	}
+
+	return p, s.complete(ctx, p)
+}
+
+// complete отмечает платеж списанным и переводит заказ в обработку.
+func (s *paymentService) complete(ctx context.Context, p *models.Payment) error {
+	tx, err := s.storage.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	p.Status = models.PaymentCaptured
+	p.FailureReason = ""
+	p.UpdatedAt = time.Now()
+	if err := tx.UpdatePayment(ctx, p); err != nil {
+		return fmt.Errorf("failed to update payment: %w", err)
+	}
+
+	order, err := tx.GetOrderByID(ctx, p.OrderID)
+	if err != nil {
+		return err
+	}
+	if order.Status == models.OrderStatusPending {
+		if err := setOrderStatus(ctx, tx, order, models.OrderStatusProcessing); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *paymentService) fail(ctx context.Context, p *models.Payment, reason string) (*models.Payment, error) {
+	p.Status = models.PaymentFailed
+	p.FailureReason = reason
+	return p, s.save(ctx, p)
+}
+
+func (s *paymentService) save(ctx context.Context, p *models.Payment) error {
+	p.UpdatedAt = time.Now()
+	if err := s.storage.UpdatePayment(ctx, p); err != nil {
+		return fmt.Errorf("failed to update payment: %w", err)
+	}
+	return nil
+}
+
+// checkOrderPaid запрещает выводить заказ из ожидания оплаты без списанного
+// платежа. Отмена неоплаченного заказа разрешена.
+func checkOrderPaid(ctx context.Context, tx storage.StorageTx, existing *models.Order, status string) error {
+	if existing.Status != models.OrderStatusPending ||
+		status == models.OrderStatusPending || status == models.OrderStatusCancelled {
+		return nil
+	}
+
+	payments, err := tx.GetPaymentsByOrder(ctx, existing.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get payments: %w", err)
+	}
+	for _, p := range payments {
+		if p.Status == models.PaymentCaptured {
+			return nil
+		}
+	}
+	return errors.New("validate: order cannot leave pending before its payment is captured")
+}
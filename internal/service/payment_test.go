@@ -0,0 +1,115 @@
+package service
+
+import (
+	"backend-store/internal/models"
+	"backend-store/internal/payment"
+	"backend-store/internal/storage"
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createPaidOrder создает заказ на total и оплачивает его через тестовый
+// шлюз.
+func createPaidOrder(t *testing.T, store storage.Storage, payments PaymentService, total int) (*models.Order, *models.Payment) {
+	t.Helper()
+	ctx := context.Background()
+
+	customer := &models.Customer{Email: "buyer@example.com", Name: "Buyer"}
+	require.NoError(t, store.CreateCustomer(ctx, customer))
+	order := &models.Order{CustomerID: customer.ID, Status: models.OrderStatusPending, Subtotal: total, Total: total}
+	require.NoError(t, store.CreateOrder(ctx, order))
+
+	p, err := payments.PayOrder(ctx, order.ID, "tok_visa")
+	require.NoError(t, err)
+	require.Equal(t, models.PaymentCaptured, p.Status)
+	return order, p
+}
+
+func TestPaymentService_RefundPayment(t *testing.T) {
+	tests := []struct {
+		name         string
+		refunds      []int
+		declineLast  bool
+		wantErr      string
+		wantRefunded int
+		wantStatus   string
+	}{
+		{name: "full refund", refunds: []int{0}, wantRefunded: 100, wantStatus: models.PaymentRefunded},
+		{name: "partial refunds up to amount", refunds: []int{60, 40}, wantRefunded: 100, wantStatus: models.PaymentRefunded},
+		{name: "partial refund keeps captured", refunds: []int{30}, wantRefunded: 30, wantStatus: models.PaymentCaptured},
+		{name: "exceeds remaining", refunds: []int{60, 50}, wantErr: "validate: refund amount must be between 1 and 40", wantRefunded: 60, wantStatus: models.PaymentCaptured},
+		{name: "negative amount", refunds: []int{-5}, wantErr: "validate", wantRefunded: 0, wantStatus: models.PaymentCaptured},
+		{name: "repeated full refund", refunds: []int{0, 0}, wantErr: "cannot refund payment in status \"refunded\"", wantRefunded: 100, wantStatus: models.PaymentRefunded},
+		{name: "gateway decline releases reservation", refunds: []int{40}, declineLast: true, wantErr: "refund failed", wantRefunded: 0, wantStatus: models.PaymentCaptured},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+			store := storage.NewMemoryStorage()
+			fake := payment.NewFake("", "", "", nil)
+			payments := NewPaymentService(store, fake)
+			_, p := createPaidOrder(t, store, payments, 100)
+
+			// Act
+			var err error
+			for i, amount := range tt.refunds {
+				if tt.declineLast && i == len(tt.refunds)-1 {
+					fake.Script(payment.OpRefund, payment.Decline)
+				}
+				if _, err = payments.RefundPayment(ctx, p.ID, amount); err != nil {
+					break
+				}
+			}
+
+			// Assert
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			stored, err := store.GetPaymentByID(ctx, p.ID)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantRefunded, stored.RefundedAmount)
+			assert.Equal(t, tt.wantStatus, stored.Status)
+		})
+	}
+}
+
+func TestPaymentService_RefundPayment_Concurrent(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	store := storage.NewMemoryStorage()
+	payments := NewPaymentService(store, payment.NewFake("", "", "", nil))
+	_, p := createPaidOrder(t, store, payments, 100)
+
+	// Act
+	const attempts = 10
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	succeeded := 0
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := payments.RefundPayment(ctx, p.ID, 100); err == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Assert
+	assert.Equal(t, 1, succeeded)
+	stored, err := store.GetPaymentByID(ctx, p.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 100, stored.RefundedAmount)
+	assert.Equal(t, models.PaymentRefunded, stored.Status)
+}
@@ -0,0 +1,277 @@
+package service
+
+import (
+	"backend-store/internal/models"
+	"backend-store/internal/shipping"
+	"backend-store/internal/storage"
+	"backend-store/internal/tax"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type promotionService struct {
+	storage storage.Storage
+}
+
+func NewPromotionService(storage storage.Storage) PromotionService {
+	return &promotionService{storage: storage}
+}
+
+func (s *promotionService) CreatePromotion(ctx context.Context, promotion *models.Promotion) error {
+	promotion.Normalize()
+	if err := promotion.Validate(); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+
+	now := time.Now()
+	promotion.UsageCount = 0
+	promotion.CreatedAt = now
+	promotion.UpdatedAt = now
+
+	if err := s.storage.CreatePromotion(ctx, promotion); err != nil {
+		return fmt.Errorf("failed to create promotion: %w", err)
+	}
+	return nil
+}
+
+func (s *promotionService) GetAllPromotions(ctx context.Context) ([]*models.Promotion, error) {
+	promotions, err := s.storage.GetAllPromotions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get promotions: %w", err)
+	}
+	return promotions, nil
+}
+
+func (s *promotionService) GetPromotion(ctx context.Context, id int) (*models.Promotion, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid promotion ID")
+	}
+	return s.storage.GetPromotionByID(ctx, id)
+}
+
+// UpdatePromotion заменяет условия акции. Уже оформленные заказы сохраняют
+// рассчитанные скидки; счетчик использований не меняется.
+func (s *promotionService) UpdatePromotion(ctx context.Context, promotion *models.Promotion) error {
+	if promotion.ID <= 0 {
+		return errors.New("invalid promotion ID")
+	}
+	promotion.Normalize()
+	if err := promotion.Validate(); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+
+	promotion.UpdatedAt = time.Now()
+	if err := s.storage.UpdatePromotion(ctx, promotion); err != nil {
+		return fmt.Errorf("failed to update promotion: %w", err)
+	}
+	return nil
+}
+
+// DeletePromotion удаляет неиспользованную акцию. Использованную можно
+// только выключить, чтобы не потерять счетчики лимитов.
+func (s *promotionService) DeletePromotion(ctx context.Context, id int) error {
+	if id <= 0 {
+		return errors.New("invalid promotion ID")
+	}
+	if err := s.storage.DeletePromotion(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete promotion: %w", err)
+	}
+	return nil
+}
+
+// PreviewCart рассчитывает скидки для корзины так же, как они будут
+// рассчитаны при оформлении. Неподходящий код не считается ошибкой: причина
+// возвращается в Pricing.Rejected.
+func (s *promotionService) PreviewCart(ctx context.Context, cartID int, token, code string) (*models.Pricing, error) {
+	tx, err := s.storage.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	cart, err := loadActiveCart(ctx, tx, cartID, token, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := priceCart(ctx, tx, cart); err != nil {
+		return nil, err
+	}
+
+	pricing, err := applyPromotions(ctx, tx, cartPricingLines(cart), code, cart.UserID, now)
+	if err != nil {
+		return nil, err
+	}
+	return pricing, nil
+}
+
+// applyPromotions подбирает действующие автоматические акции и акцию по
+// коду и рассчитывает скидки. Недоступная автоматическая акция молча
+// пропускается, а причина отказа по коду попадает в Rejected. customerID 0
+// означает, что покупатель неизвестен и личный лимит не проверяется.
+func applyPromotions(ctx context.Context, tx storage.StorageTx, lines []models.PricingLine, code string, customerID int, now time.Time) (*models.Pricing, error) {
+	all, err := tx.GetAllPromotions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get promotions: %w", err)
+	}
+
+	var rejected []string
+	var promotions []*models.Promotion
+	for _, p := range all {
+		if p.Code != "" || p.CheckAvailable(now) != nil {
+			continue
+		}
+		ok, err := withinCustomerLimit(ctx, tx, p, customerID)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			promotions = append(promotions, p)
+		}
+	}
+
+	if code = strings.ToUpper(strings.TrimSpace(code)); code != "" {
+		p, err := tx.GetPromotionByCode(ctx, code)
+		if err != nil {
+			rejected = append(rejected, fmt.Sprintf("promotion code %q not found", code))
+		} else if err := p.CheckAvailable(now); err != nil {
+			rejected = append(rejected, err.Error())
+		} else {
+			ok, err := withinCustomerLimit(ctx, tx, p, customerID)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				promotions = append(promotions, p)
+			} else {
+				rejected = append(rejected, fmt.Sprintf("promotion %q was already used by this customer", p.Code))
+			}
+		}
+	}
+
+	pricing := models.ApplyPromotions(lines, promotions)
+	pricing.Rejected = append(rejected, pricing.Rejected...)
+	return &pricing, nil
+}
+
+// withinCustomerLimit - предварительная проверка лимита на покупателя для
+// расчета скидок. Одновременные заказы ее проходят, поэтому окончательно
+// лимит проверяет RedeemPromotion при оформлении заказа.
+func withinCustomerLimit(ctx context.Context, tx storage.StorageTx, p *models.Promotion, customerID int) (bool, error) {
+	if p.PerCustomerLimit == 0 || customerID == 0 {
+		return true, nil
+	}
+	used, err := tx.CountPromotionRedemptions(ctx, p.ID, customerID)
+	if err != nil {
+		return false, fmt.Errorf("failed to count promotion redemptions: %w", err)
+	}
+	return used < p.PerCustomerLimit, nil
+}
+
+// priceOrder рассчитывает итог заказа по ценам позиций, проставленным
+// allocateOrder: скидки промоакций, доставку выбранным способом и налог с
+// сумм позиций после скидок. Код, который не дал скидки, - ошибка:
+// покупатель не должен получить заказ без обещанной скидки.
+func priceOrder(ctx context.Context, tx storage.StorageTx, order *models.Order, taxes tax.Calculator, rates shipping.RateProvider) error {
+	lines := make([]models.PricingLine, 0, len(order.Products))
+	for _, item := range order.Products {
+		lines = append(lines, models.PricingLine{
+			ProductID: item.ProductID,
+			VariantID: item.VariantID,
+			Quantity:  item.Quantity,
+			UnitPrice: item.Price,
+		})
+	}
+
+	order.PromoCode = strings.ToUpper(strings.TrimSpace(order.PromoCode))
+	pricing, err := applyPromotions(ctx, tx, lines, order.PromoCode, order.CustomerID, time.Now())
+	if err != nil {
+		return err
+	}
+	if len(pricing.Rejected) > 0 {
+		return fmt.Errorf("validate: %s", pricing.Rejected[0])
+	}
+
+	order.Subtotal = pricing.Subtotal
+	order.Discount = pricing.Discount
+	order.FreeShipping = pricing.FreeShipping
+	order.Adjustments = pricing.Adjustments
+
+	if err := applyShipping(ctx, tx, order, lines, rates); err != nil {
+		return err
+	}
+	if err := applyTax(ctx, order, pricing.LineTotals, taxes); err != nil {
+		return err
+	}
+	order.Total = order.CalculateTotal()
+	return nil
+}
+
+// applyTax рассчитывает налог позиций по адресу доставки заказа. amounts -
+// суммы позиций после скидок в порядке order.Products.
+func applyTax(ctx context.Context, order *models.Order, amounts []int, taxes tax.Calculator) error {
+	req := tax.Request{Lines: make([]tax.Line, len(order.Products))}
+	if order.ShippingAddress != nil {
+		req.Country = order.ShippingAddress.Country
+		req.Region = order.ShippingAddress.Region
+	}
+	for i, item := range order.Products {
+		req.Lines[i] = tax.Line{TaxClass: item.TaxClass, Amount: amounts[i]}
+	}
+
+	result, err := taxes.Calculate(ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed to calculate tax: %w", err)
+	}
+	if len(result.Lines) != len(order.Products) {
+		return fmt.Errorf("failed to calculate tax: got %d lines for %d items", len(result.Lines), len(order.Products))
+	}
+
+	for i := range order.Products {
+		order.Products[i].Tax = result.Lines[i]
+	}
+	order.Tax = result.Total
+	order.TaxInclusive = result.Inclusive
+	return nil
+}
+
+// redeemPromotions засчитывает использование каждой сработавшей акции и
+// сохраняет скидки заказа. Вызывается, когда у заказа уже есть ID.
+func redeemPromotions(ctx context.Context, tx storage.StorageTx, order *models.Order) error {
+	redeemed := make(map[int]bool)
+	for _, a := range order.Adjustments {
+		if redeemed[a.PromotionID] {
+			continue
+		}
+		redeemed[a.PromotionID] = true
+		redemption := &models.PromotionRedemption{
+			PromotionID: a.PromotionID,
+			OrderID:     order.ID,
+			CustomerID:  order.CustomerID,
+			CreatedAt:   order.UpdatedAt,
+		}
+		if err := tx.RedeemPromotion(ctx, redemption); err != nil {
+			if errors.Is(err, storage.ErrUsageLimitReached) {
+				return fmt.Errorf("validate: %w", err)
+			}
+			return fmt.Errorf("failed to redeem promotion: %w", err)
+		}
+	}
+
+	if err := tx.CreateOrderAdjustments(ctx, order.ID, order.Adjustments); err != nil {
+		return fmt.Errorf("failed to save order adjustments: %w", err)
+	}
+	return nil
+}
+
+// releasePromotions возвращает акциям использования заказа. Скидки
+// остаются в истории заказа.
+func releasePromotions(ctx context.Context, tx storage.StorageTx, orderID int) error {
+	if err := tx.ReleasePromotions(ctx, orderID); err != nil {
+		return fmt.Errorf("failed to release promotions: %w", err)
+	}
+	return nil
+}
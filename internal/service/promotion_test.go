@@ -0,0 +1,160 @@
+package service
+
+import (
+	"backend-store/internal/models"
+	"backend-store/internal/storage"
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createCustomers создает n покупателей и возвращает их ID.
+func createCustomers(t *testing.T, store storage.Storage, n int) []int {
+	t.Helper()
+	ids := make([]int, n)
+	for i := range ids {
+		customer := &models.Customer{Email: fmt.Sprintf("buyer%d@example.com", i), Name: "Buyer"}
+		require.NoError(t, store.CreateCustomer(context.Background(), customer))
+		ids[i] = customer.ID
+	}
+	return ids
+}
+
+// createDiscountedOrder создает заказ покупателя со скидкой акции.
+func createDiscountedOrder(t *testing.T, store storage.Storage, promotion *models.Promotion, customerID int) *models.Order {
+	t.Helper()
+	order := &models.Order{CustomerID: customerID, Status: models.OrderStatusPending, Subtotal: 100, Total: 90, UpdatedAt: time.Now()}
+	require.NoError(t, store.CreateOrder(context.Background(), order))
+	order.Adjustments = []models.OrderAdjustment{{PromotionID: promotion.ID, Code: promotion.Code, Type: promotion.Type, Amount: 10}}
+	return order
+}
+
+// redeemOrder засчитывает использование акций заказа в транзакции.
+func redeemOrder(ctx context.Context, store storage.Storage, order *models.Order) error {
+	tx, err := store.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := redeemPromotions(ctx, tx, order); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func createTestPromotion(t *testing.T, store storage.Storage, usageLimit, perCustomerLimit int) *models.Promotion {
+	t.Helper()
+	promotion := &models.Promotion{
+		Code:             "SAVE10",
+		Name:             "Save 10%",
+		Type:             models.PromotionPercentage,
+		Value:            10,
+		UsageLimit:       usageLimit,
+		PerCustomerLimit: perCustomerLimit,
+		Active:           true,
+	}
+	require.NoError(t, NewPromotionService(store).CreatePromotion(context.Background(), promotion))
+	return promotion
+}
+
+func TestRedeemPromotions_UsageLimits(t *testing.T) {
+	tests := []struct {
+		name             string
+		usageLimit       int
+		perCustomerLimit int
+		customers        []int // индексы покупателей, оформляющих заказы по очереди
+		wantRejected     []bool
+		wantUsage        int
+	}{
+		{name: "no limits", customers: []int{0, 0, 0}, wantRejected: []bool{false, false, false}, wantUsage: 3},
+		{name: "usage limit", usageLimit: 2, customers: []int{0, 1, 2}, wantRejected: []bool{false, false, true}, wantUsage: 2},
+		{name: "per-customer limit", perCustomerLimit: 1, customers: []int{0, 0, 1}, wantRejected: []bool{false, true, false}, wantUsage: 2},
+		{name: "per-customer limit of two", perCustomerLimit: 2, customers: []int{0, 0, 0, 1}, wantRejected: []bool{false, false, true, false}, wantUsage: 3},
+		{name: "both limits", usageLimit: 2, perCustomerLimit: 1, customers: []int{0, 0, 1, 2}, wantRejected: []bool{false, true, false, true}, wantUsage: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+			store := storage.NewMemoryStorage()
+			customers := createCustomers(t, store, 3)
+			promotion := createTestPromotion(t, store, tt.usageLimit, tt.perCustomerLimit)
+
+			// Act & Assert
+			for i, c := range tt.customers {
+				err := redeemOrder(ctx, store, createDiscountedOrder(t, store, promotion, customers[c]))
+				if tt.wantRejected[i] {
+					assert.ErrorIs(t, err, storage.ErrUsageLimitReached, "order %d", i)
+					assert.ErrorContains(t, err, "validate:", "order %d", i)
+				} else {
+					assert.NoError(t, err, "order %d", i)
+				}
+			}
+			stored, err := store.GetPromotionByID(ctx, promotion.ID)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantUsage, stored.UsageCount)
+		})
+	}
+}
+
+func TestRedeemPromotions_ReleaseFreesLimit(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	store := storage.NewMemoryStorage()
+	customers := createCustomers(t, store, 1)
+	promotion := createTestPromotion(t, store, 1, 1)
+	first := createDiscountedOrder(t, store, promotion, customers[0])
+	require.NoError(t, redeemOrder(ctx, store, first))
+	require.ErrorIs(t, redeemOrder(ctx, store, createDiscountedOrder(t, store, promotion, customers[0])), storage.ErrUsageLimitReached)
+
+	// Act
+	tx, err := store.BeginTx(ctx)
+	require.NoError(t, err)
+	require.NoError(t, releasePromotions(ctx, tx, first.ID))
+	require.NoError(t, tx.Commit())
+
+	// Assert
+	assert.NoError(t, redeemOrder(ctx, store, createDiscountedOrder(t, store, promotion, customers[0])))
+}
+
+func TestRedeemPromotions_ConcurrentPerCustomerLimit(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	store := storage.NewMemoryStorage()
+	customers := createCustomers(t, store, 1)
+	promotion := createTestPromotion(t, store, 0, 1)
+	const attempts = 10
+	orders := make([]*models.Order, attempts)
+	for i := range orders {
+		orders[i] = createDiscountedOrder(t, store, promotion, customers[0])
+	}
+
+	// Act
+	var wg sync.WaitGroup
+	errs := make([]error, attempts)
+	for i := range orders {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = redeemOrder(ctx, store, orders[i])
+		}(i)
+	}
+	wg.Wait()
+
+	// Assert
+	succeeded := 0
+	for _, err := range errs {
+		if err == nil {
+			succeeded++
+		} else {
+			assert.ErrorIs(t, err, storage.ErrUsageLimitReached)
+		}
+	}
+	assert.Equal(t, 1, succeeded)
+}
@@ -0,0 +1,350 @@
+package service
+
+import (
+	"backend-store/internal/models"
+	"backend-store/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type purchaseService struct {
+	storage  storage.Storage
+	observer StockObserver
+}
+
+func NewPurchaseService(storage storage.Storage, observer StockObserver) PurchaseService {
+	return &purchaseService{storage: storage, observer: observer}
+}
+
+func (s *purchaseService) CreateSupplier(ctx context.Context, supplier *models.Supplier) error {
+	if err := supplier.Validate(); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+
+	now := time.Now()
+	supplier.CreatedAt = now
+	supplier.UpdatedAt = now
+
+	if err := s.storage.CreateSupplier(ctx, supplier); err != nil {
+		return fmt.Errorf("failed to create supplier: %w", err)
+	}
+	return nil
+}
+
+func (s *purchaseService) GetAllSuppliers(ctx context.Context) ([]*models.Supplier, error) {
+	suppliers, err := s.storage.GetAllSuppliers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get suppliers: %w", err)
+	}
+	return suppliers, nil
+}
+
+func (s *purchaseService) GetSupplierByID(ctx context.Context, id int) (*models.Supplier, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid supplier ID")
+	}
+	return s.storage.GetSupplierByID(ctx, id)
+}
+
+func (s *purchaseService) UpdateSupplier(ctx context.Context, supplier *models.Supplier) error {
+	if err := supplier.Validate(); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+
+	tx, err := s.storage.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	existing, err := tx.GetSupplierByID(ctx, supplier.ID)
+	if err != nil {
+		return err
+	}
+	supplier.CreatedAt = existing.CreatedAt
+	supplier.UpdatedAt = time.Now()
+
+	if err := tx.UpdateSupplier(ctx, supplier); err != nil {
+		return fmt.Errorf("failed to update supplier: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func (s *purchaseService) DeleteSupplier(ctx context.Context, id int) error {
+	if id <= 0 {
+		return errors.New("invalid supplier ID")
+	}
+	if err := s.storage.DeleteSupplier(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete supplier: %w", err)
+	}
+	return nil
+}
+
+// CreatePurchaseOrder создает черновик заказа поставщику. Без склада товар
+// будет принят на основной склад.
+func (s *purchaseService) CreatePurchaseOrder(ctx context.Context, order *models.PurchaseOrder) error {
+	if err := order.Validate(); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+
+	now := time.Now()
+	order.Status = models.PurchaseOrderDraft
+	order.CreatedAt = now
+	order.UpdatedAt = now
+	order.SentAt = nil
+	order.ReceivedAt = nil
+
+	tx, err := s.storage.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := preparePurchaseOrder(ctx, tx, order); err != nil {
+		return err
+	}
+
+	if err := tx.CreatePurchaseOrder(ctx, order); err != nil {
+		return fmt.Errorf("failed to create purchase order: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func (s *purchaseService) GetAllPurchaseOrders(ctx context.Context, status string) ([]*models.PurchaseOrder, error) {
+	orders, err := s.storage.GetAllPurchaseOrders(ctx, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get purchase orders: %w", err)
+	}
+	return orders, nil
+}
+
+func (s *purchaseService) GetPurchaseOrderByID(ctx context.Context, id int) (*models.PurchaseOrder, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid purchase order ID")
+	}
+	return s.storage.GetPurchaseOrderByID(ctx, id)
+}
+
+// UpdatePurchaseOrder заменяет поставщика, склад, примечание и позиции
+// черновика. Отправленный поставщику заказ не редактируется.
+func (s *purchaseService) UpdatePurchaseOrder(ctx context.Context, order *models.PurchaseOrder) error {
+	if err := order.Validate(); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+
+	tx, err := s.storage.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	existing, err := tx.LockPurchaseOrder(ctx, order.ID)
+	if err != nil {
+		return err
+	}
+	if existing.Status != models.PurchaseOrderDraft {
+		return fmt.Errorf("cannot edit purchase order in status %s", existing.Status)
+	}
+
+	order.Status = existing.Status
+	order.SentAt = nil
+	order.ReceivedAt = nil
+	order.CreatedAt = existing.CreatedAt
+	order.UpdatedAt = time.Now()
+	for i := range order.Items {
+		order.Items[i].ReceivedQuantity = 0
+	}
+
+	if err := preparePurchaseOrder(ctx, tx, order); err != nil {
+		return err
+	}
+
+	if err := tx.ReplacePurchaseOrderItems(ctx, order); err != nil {
+		return fmt.Errorf("failed to update purchase order items: %w", err)
+	}
+	if err := tx.UpdatePurchaseOrder(ctx, order); err != nil {
+		return fmt.Errorf("failed to update purchase order: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// SendPurchaseOrder фиксирует отправку заказа поставщику; после этого товар
+// можно принимать.
+func (s *purchaseService) SendPurchaseOrder(ctx context.Context, id int) (*models.PurchaseOrder, error) {
+	return s.transition(ctx, id, models.PurchaseOrderSent, func(tx storage.StorageTx, po *models.PurchaseOrder) error {
+		now := time.Now()
+		po.SentAt = &now
+		return nil
+	})
+}
+
+// CancelPurchaseOrder закрывает заказ без ожидания оставшегося товара. Уже
+// принятый товар остается на складе.
+func (s *purchaseService) CancelPurchaseOrder(ctx context.Context, id int) (*models.PurchaseOrder, error) {
+	return s.transition(ctx, id, models.PurchaseOrderCancelled, func(tx storage.StorageTx, po *models.PurchaseOrder) error {
+		return nil
+	})
+}
+
+// ReceivePurchaseOrder оприходует фактически принятое количество на склад
+// заказа и пересчитывает себестоимость вариантов по средневзвешенной.
+// Пустой список означает приемку всего ожидаемого товара. Принять больше
+// заказанного нельзя.
+func (s *purchaseService) ReceivePurchaseOrder(ctx context.Context, id int, items []models.GoodsReceiptItem) (*models.PurchaseOrder, error) {
+	return s.transition(ctx, id, models.PurchaseOrderReceived, func(tx storage.StorageTx, po *models.PurchaseOrder) error {
+		if len(items) == 0 {
+			for _, item := range po.Items {
+				if item.Outstanding() > 0 {
+					items = append(items, models.GoodsReceiptItem{ItemID: item.ID, Quantity: item.Outstanding()})
+				}
+			}
+		}
+		if len(items) == 0 {
+			return errors.New("validate: nothing left to receive")
+		}
+
+		received := make(map[int]int, len(items))
+		for _, r := range items {
+			if r.Quantity <= 0 {
+				return errors.New("validate: received quantity must be positive")
+			}
+			received[r.ItemID] += r.Quantity
+		}
+		outstanding := make(map[int]int, len(po.Items))
+		for _, item := range po.Items {
+			outstanding[item.ID] = item.Outstanding()
+		}
+		for itemID, quantity := range received {
+			left, ok := outstanding[itemID]
+			if !ok {
+				return fmt.Errorf("validate: item %d does not belong to purchase order %d", itemID, po.ID)
+			}
+			if quantity > left {
+				return fmt.Errorf("validate: cannot receive %d of item %d: %d outstanding", quantity, itemID, left)
+			}
+		}
+
+		reference := purchaseOrderReference(po)
+		for i := range po.Items {
+			item := &po.Items[i]
+			quantity := received[item.ID]
+			if quantity == 0 {
+				continue
+			}
+
+			variant, err := tx.GetVariantByID(ctx, item.VariantID)
+			if err != nil {
+				return fmt.Errorf("product variant %d not found: %w", item.VariantID, err)
+			}
+			updated := *variant
+			updated.Cost = models.AverageCost(variant.Quantity, variant.Cost, quantity, item.UnitCost)
+			if updated.Cost != variant.Cost {
+				if err := tx.UpdateVariant(ctx, &updated); err != nil {
+					return fmt.Errorf("failed to update variant: %w", err)
+				}
+			}
+
+			if err := adjustStock(ctx, tx, po.WarehouseID, item.VariantID, quantity, models.MovementPurchase, reference); err != nil {
+				return err
+			}
+			item.ReceivedQuantity += quantity
+		}
+
+		po.Status = po.ReceiptStatus()
+		if po.Status == models.PurchaseOrderReceived {
+			now := time.Now()
+			po.ReceivedAt = &now
+		}
+		return nil
+	})
+}
+
+// transition переводит заказ поставщику в статус status, выполняя apply в
+// той же транзакции. apply может уточнить статус: приемка завершает заказ
+// только при полном поступлении. Заказ блокируется до фиксации, иначе две
+// параллельные приемки оприходовали бы одни и те же остатки.
+func (s *purchaseService) transition(ctx context.Context, id int, status string, apply func(storage.StorageTx, *models.PurchaseOrder) error) (*models.PurchaseOrder, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid purchase order ID")
+	}
+
+	tx, err := s.storage.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	existing, err := tx.LockPurchaseOrder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !existing.CanTransition(status) {
+		return nil, fmt.Errorf("cannot change purchase order status from %s to %s", existing.Status, status)
+	}
+
+	order := *existing
+	order.Items = append([]models.PurchaseOrderItem{}, existing.Items...)
+	order.Status = status
+	if err := apply(tx, &order); err != nil {
+		return nil, err
+	}
+	order.UpdatedAt = time.Now()
+
+	if err := tx.UpdatePurchaseOrder(ctx, &order); err != nil {
+		return nil, fmt.Errorf("failed to update purchase order: %w", err)
+	}
+
+	if err := commitStockChange(tx, s.observer); err != nil {
+		return nil, err
+	}
+
+	return &order, nil
+}
+
+// preparePurchaseOrder проверяет поставщика и склад, определяет варианты
+// позиций и считает сумму заказа.
+func preparePurchaseOrder(ctx context.Context, tx storage.StorageTx, order *models.PurchaseOrder) error {
+	if _, err := tx.GetSupplierByID(ctx, order.SupplierID); err != nil {
+		return fmt.Errorf("validate: supplier %d not found", order.SupplierID)
+	}
+
+	if order.WarehouseID == 0 {
+		warehouse, err := primaryWarehouse(ctx, tx)
+		if err != nil {
+			return err
+		}
+		order.WarehouseID = warehouse.ID
+	} else if _, err := tx.GetWarehouseByID(ctx, order.WarehouseID); err != nil {
+		return fmt.Errorf("validate: warehouse %d not found", order.WarehouseID)
+	}
+
+	seen := make(map[int]bool, len(order.Items))
+	for i := range order.Items {
+		item := &order.Items[i]
+		line := models.OrderItem{ProductID: item.ProductID, VariantID: item.VariantID}
+		if _, err := resolveOrderItem(ctx, tx, &line); err != nil {
+			return err
+		}
+		if line.VariantID == 0 {
+			return fmt.Errorf("validate: product %d has no variants", line.ProductID)
+		}
+		if seen[line.VariantID] {
+			return fmt.Errorf("validate: duplicate purchase order item for variant %d", line.VariantID)
+		}
+		seen[line.VariantID] = true
+		item.ProductID = line.ProductID
+		item.VariantID = line.VariantID
+	}
+
+	order.CalculateTotal()
+	return nil
+}
+
+func purchaseOrderReference(po *models.PurchaseOrder) string {
+	return fmt.Sprintf("purchase_order:%d", po.ID)
+}
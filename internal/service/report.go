@@ -0,0 +1,209 @@
+package service
+
+import (
+	"backend-store/internal/bulk"
+	"backend-store/internal/models"
+	"backend-store/internal/storage"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Названия отчетов; совпадают с путями API и именами файлов выгрузки.
+const (
+	ReportSales          = "sales"
+	ReportTopProducts    = "top-products"
+	ReportOrderValue     = "order-value"
+	ReportOrderStatuses  = "order-statuses"
+	ReportStockValuation = "stock-valuation"
+)
+
+// reportCacheSweep - размер кэша, после которого при записи удаляются
+// устаревшие отчеты, а если их нет - весь кэш.
+const reportCacheSweep = 256
+
+type reportService struct {
+	storage storage.Storage
+	ttl     time.Duration
+
+	mu    sync.Mutex
+	cache map[string]reportCacheEntry
+}
+
+type reportCacheEntry struct {
+	value   interface{}
+	expires time.Time
+}
+
+// NewReportService создает сервис отчетов. Результаты хранятся в памяти
+// процесса ttl; нулевой ttl отключает кэш.
+func NewReportService(storage storage.Storage, ttl time.Duration) ReportService {
+	return &reportService{storage: storage, ttl: ttl, cache: make(map[string]reportCacheEntry)}
+}
+
+func (s *reportService) Sales(ctx context.Context, filter models.ReportFilter) ([]models.SalesPoint, error) {
+	value, err := s.cached(ReportSales, filter, func(filter models.ReportFilter) (interface{}, error) {
+		return s.storage.ReportSales(ctx, filter)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.([]models.SalesPoint), nil
+}
+
+func (s *reportService) TopProducts(ctx context.Context, filter models.ReportFilter) ([]models.ProductSales, error) {
+	value, err := s.cached(ReportTopProducts, filter, func(filter models.ReportFilter) (interface{}, error) {
+		return s.storage.ReportTopProducts(ctx, filter)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.([]models.ProductSales), nil
+}
+
+func (s *reportService) OrderValue(ctx context.Context, filter models.ReportFilter) (*models.OrderValue, error) {
+	value, err := s.cached(ReportOrderValue, filter, func(filter models.ReportFilter) (interface{}, error) {
+		return s.storage.ReportOrderValue(ctx, filter)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(*models.OrderValue), nil
+}
+
+// OrderStatuses возвращает воронку статусов: все заказы периода, включая
+// отмененные, в порядке этапов.
+func (s *reportService) OrderStatuses(ctx context.Context, filter models.ReportFilter) ([]models.StatusCount, error) {
+	value, err := s.cached(ReportOrderStatuses, filter, func(filter models.ReportFilter) (interface{}, error) {
+		counts, err := s.storage.ReportOrderStatuses(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		return models.OrderFunnel(counts), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.([]models.StatusCount), nil
+}
+
+// StockValuation оценивает остатки на конец периода по текущей
+// себестоимости вариантов.
+func (s *reportService) StockValuation(ctx context.Context, filter models.ReportFilter) ([]models.StockValuation, error) {
+	value, err := s.cached(ReportStockValuation, filter, func(filter models.ReportFilter) (interface{}, error) {
+		return s.storage.ReportStockValuation(ctx, filter)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.([]models.StockValuation), nil
+}
+
+// ExportReport выгружает отчет report в формате format.
+func (s *reportService) ExportReport(ctx context.Context, report string, filter models.ReportFilter, format bulk.Format, w io.Writer) error {
+	var columns []string
+	var rows [][]interface{}
+
+	switch report {
+	case ReportSales:
+		points, err := s.Sales(ctx, filter)
+		if err != nil {
+			return err
+		}
+		columns = []string{"period", "orders", "revenue"}
+		for _, p := range points {
+			rows = append(rows, []interface{}{p.Period, p.Orders, p.Revenue})
+		}
+	case ReportTopProducts:
+		products, err := s.TopProducts(ctx, filter)
+		if err != nil {
+			return err
+		}
+		columns = []string{"product_id", "name", "units", "revenue", "cost", "margin"}
+		for _, p := range products {
+			rows = append(rows, []interface{}{p.ProductID, p.Name, p.Units, p.Revenue, p.Cost, p.Margin})
+		}
+	case ReportOrderValue:
+		value, err := s.OrderValue(ctx, filter)
+		if err != nil {
+			return err
+		}
+		columns = []string{"orders", "revenue", "average"}
+		rows = append(rows, []interface{}{value.Orders, value.Revenue, value.Average})
+	case ReportOrderStatuses:
+		counts, err := s.OrderStatuses(ctx, filter)
+		if err != nil {
+			return err
+		}
+		columns = []string{"status", "orders", "revenue"}
+		for _, c := range counts {
+			rows = append(rows, []interface{}{c.Status, c.Orders, c.Revenue})
+		}
+	case ReportStockValuation:
+		valuation, err := s.StockValuation(ctx, filter)
+		if err != nil {
+			return err
+		}
+		columns = []string{"product_id", "variant_id", "sku", "name", "quantity", "unit_cost", "value"}
+		for _, v := range valuation {
+			rows = append(rows, []interface{}{v.ProductID, v.VariantID, v.SKU, v.Name, v.Quantity, v.UnitCost, v.Value})
+		}
+	default:
+		return fmt.Errorf("unknown report %q", report)
+	}
+
+	writer, err := bulk.NewWriter(w, format, "Report", columns)
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := writer.Write(row); err != nil {
+			return fmt.Errorf("failed to export report: %w", err)
+		}
+	}
+	return writer.Close()
+}
+
+// cached проверяет фильтр и возвращает отчет из кэша или строит его заново.
+// Отчет кэшируется целиком вместе с параметрами, поэтому разные периоды и
+// часовые пояса не смешиваются.
+func (s *reportService) cached(report string, filter models.ReportFilter, load func(models.ReportFilter) (interface{}, error)) (interface{}, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, fmt.Errorf("validate: %w", err)
+	}
+	key := report + "|" + filter.Key()
+
+	now := time.Now()
+	if s.ttl > 0 {
+		s.mu.Lock()
+		entry, ok := s.cache[key]
+		s.mu.Unlock()
+		if ok && now.Before(entry.expires) {
+			return entry.value, nil
+		}
+	}
+
+	value, err := load(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build %s report: %w", report, err)
+	}
+
+	if s.ttl > 0 {
+		s.mu.Lock()
+		if len(s.cache) >= reportCacheSweep {
+			for k, entry := range s.cache {
+				if !now.Before(entry.expires) {
+					delete(s.cache, k)
+				}
+			}
+		}
+		if len(s.cache) >= reportCacheSweep {
+			s.cache = make(map[string]reportCacheEntry)
+		}
+		s.cache[key] = reportCacheEntry{value: value, expires: now.Add(s.ttl)}
+		s.mu.Unlock()
+	}
+	return value, nil
+}
@@ -0,0 +1,361 @@
+package service
+
+import (
+	"backend-store/internal/models"
+	"backend-store/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type returnService struct {
+	storage  storage.Storage
+	payments PaymentService
+	observer StockObserver
+}
+
+// NewReturnService создает сервис возвратов. Деньги возвращаются через
+// payments, поэтому сумма возвратов по заказу не превышает списанную.
+func NewReturnService(storage storage.Storage, payments PaymentService, observer StockObserver) ReturnService {
+	return &returnService{storage: storage, payments: payments, observer: observer}
+}
+
+// returnLine - позиция заказа с точностью до товара и варианта. Возвращенное
+// количество считается по ней, а не по ID позиции, который меняется при
+// изменении заказа.
+type returnLine struct {
+	productID int
+	variantID int
+}
+
+// CreateReturn создает заявку на возврат позиций отправленного или
+// доставленного заказа; заказ в другом статусе отклоняется с ошибкой
+// "cannot return items of order in status". Для каждой позиции нельзя
+// вернуть больше, чем заказано, за вычетом позиций других неотклоненных
+// заявок.
+func (s *returnService) CreateReturn(ctx context.Context, ret *models.ReturnRequest) error {
+	if ret.OrderID <= 0 {
+		return errors.New("invalid order ID")
+	}
+	if err := ret.Validate(); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+
+	tx, err := s.storage.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	order, err := tx.GetOrderByID(ctx, ret.OrderID)
+	if err != nil {
+		return err
+	}
+	if order.Status != models.OrderStatusShipped && order.Status != models.OrderStatusDelivered {
+		return fmt.Errorf("cannot return items of order in status %q", order.Status)
+	}
+
+	ordered := make(map[returnLine]int)
+	items := make(map[int]models.OrderItem, len(order.Products))
+	for _, item := range order.Products {
+		ordered[returnLine{item.ProductID, item.VariantID}] += item.Quantity
+		items[item.ID] = item
+	}
+
+	existing, err := tx.GetReturnsByOrder(ctx, ret.OrderID)
+	if err != nil {
+		return fmt.Errorf("failed to get returns: %w", err)
+	}
+	returned := make(map[returnLine]int)
+	for _, r := range existing {
+		if !r.IsOpen() {
+			continue
+		}
+		for _, item := range r.Items {
+			returned[returnLine{item.ProductID, item.VariantID}] += item.Quantity
+		}
+	}
+
+	for i := range ret.Items {
+		item := &ret.Items[i]
+		orderItem, ok := items[item.OrderItemID]
+		if !ok {
+			return fmt.Errorf("validate: order item %d not found in order %d", item.OrderItemID, ret.OrderID)
+		}
+		item.ProductID = orderItem.ProductID
+		item.VariantID = orderItem.VariantID
+		item.Price = orderItem.Price
+		item.Disposition = ""
+
+		line := returnLine{item.ProductID, item.VariantID}
+		if available := ordered[line] - returned[line]; item.Quantity > available {
+			return fmt.Errorf("validate: only %d of order item %d can be returned", available, item.OrderItemID)
+		}
+		returned[line] += item.Quantity
+	}
+
+	now := time.Now()
+	ret.Status = models.ReturnRequested
+	ret.ResolutionNote = ""
+	ret.RefundAmount = 0
+	ret.PaymentID = 0
+	ret.CreatedAt = now
+	ret.UpdatedAt = now
+	if err := tx.CreateReturn(ctx, ret); err != nil {
+		return fmt.Errorf("failed to create return: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func (s *returnService) GetReturn(ctx context.Context, id int) (*models.ReturnRequest, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid return ID")
+	}
+	return s.storage.GetReturnByID(ctx, id)
+}
+
+func (s *returnService) GetOrderReturns(ctx context.Context, orderID int) ([]models.ReturnRequest, error) {
+	if orderID <= 0 {
+		return nil, errors.New("invalid order ID")
+	}
+	if _, err := s.storage.GetOrderByID(ctx, orderID); err != nil {
+		return nil, err
+	}
+	returns, err := s.storage.GetReturnsByOrder(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get returns: %w", err)
+	}
+	return returns, nil
+}
+
+func (s *returnService) ApproveReturn(ctx context.Context, id int, note string) (*models.ReturnRequest, error) {
+	return s.resolve(ctx, id, models.ReturnApproved, note)
+}
+
+// RejectReturn отклоняет заявку; ее позиции снова можно вернуть другой
+// заявкой.
+func (s *returnService) RejectReturn(ctx context.Context, id int, note string) (*models.ReturnRequest, error) {
+	return s.resolve(ctx, id, models.ReturnRejected, note)
+}
+
+func (s *returnService) resolve(ctx context.Context, id int, status, note string) (*models.ReturnRequest, error) {
+	if len(note) > 1000 {
+		return nil, errors.New("validate: resolution note is too long")
+	}
+
+	tx, err := s.storage.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	ret, err := tx.GetReturnByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if ret.Status != models.ReturnRequested {
+		return nil, fmt.Errorf("cannot resolve return in status %q", ret.Status)
+	}
+
+	ret.Status = status
+	ret.ResolutionNote = note
+	ret.UpdatedAt = time.Now()
+	if err := tx.UpdateReturn(ctx, ret); err != nil {
+		return nil, fmt.Errorf("failed to update return: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// ReceiveReturn принимает товар одобренного возврата. dispositions задает
+// судьбу позиций по их ID: restock возвращает товар на склад, с которого он
+// был отгружен, write_off списывает его. Позиции без указания возвращаются
+// на склад. Заявка переводится в received до оприходования, чтобы
+// параллельный прием не вернул товар на склад второй раз.
+func (s *returnService) ReceiveReturn(ctx context.Context, id int, dispositions map[int]string) (*models.ReturnRequest, error) {
+	tx, err := s.storage.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	ret, err := tx.GetReturnByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if ret.Status != models.ReturnApproved {
+		return nil, fmt.Errorf("cannot receive return in status %q", ret.Status)
+	}
+
+	known := make(map[int]bool, len(ret.Items))
+	for _, item := range ret.Items {
+		known[item.ID] = true
+	}
+	for itemID, disposition := range dispositions {
+		if !known[itemID] {
+			return nil, fmt.Errorf("validate: return item %d not found in return %d", itemID, id)
+		}
+		if disposition != models.DispositionRestock && disposition != models.DispositionWriteOff {
+			return nil, fmt.Errorf("validate: disposition must be %q or %q", models.DispositionRestock, models.DispositionWriteOff)
+		}
+	}
+
+	if err := tx.SetReturnStatus(ctx, ret.ID, models.ReturnApproved, models.ReturnReceived); err != nil {
+		return nil, err
+	}
+
+	allocations, err := tx.GetAllocationsByOrder(ctx, ret.OrderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stock allocations: %w", err)
+	}
+	reference := fmt.Sprintf("return:%d", ret.ID)
+	for i := range ret.Items {
+		item := &ret.Items[i]
+		item.Disposition = models.DispositionRestock
+		if d, ok := dispositions[item.ID]; ok {
+			item.Disposition = d
+		}
+		// Товар без вариантов не имеет складских остатков.
+		if item.Disposition != models.DispositionRestock || item.VariantID == 0 {
+			continue
+		}
+
+		warehouseID, err := returnWarehouse(ctx, tx, allocations, item.VariantID)
+		if err != nil {
+			return nil, err
+		}
+		if err := adjustStock(ctx, tx, warehouseID, item.VariantID, item.Quantity, models.MovementReturn, reference); err != nil {
+			return nil, err
+		}
+	}
+
+	ret.Status = models.ReturnReceived
+	ret.UpdatedAt = time.Now()
+	if err := tx.UpdateReturn(ctx, ret); err != nil {
+		return nil, fmt.Errorf("failed to update return: %w", err)
+	}
+
+	if err := commitStockChange(tx, s.observer); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// returnWarehouse выбирает склад, с которого вариант был отгружен по заказу,
+// а если распределения нет - основной склад.
+func returnWarehouse(ctx context.Context, tx storage.StorageTx, allocations []models.StockAllocation, variantID int) (int, error) {
+	for _, a := range allocations {
+		if a.VariantID == variantID {
+			return a.WarehouseID, nil
+		}
+	}
+	primary, err := primaryWarehouse(ctx, tx)
+	if err != nil {
+		return 0, err
+	}
+	return primary.ID, nil
+}
+
+// RefundReturn возвращает деньги за принятый возврат через списанный платеж
+// заказа. amount 0 означает полную стоимость возвращенных позиций; меньшая
+// сумма - частичный возврат, например с удержанием за поврежденный товар.
+//
+// До обращения к шлюзу заявка переводится в refunding, поэтому повторный
+// или одновременный запрос деньги второй раз не вернет. При отказе шлюза
+// заявка возвращается в received.
+func (s *returnService) RefundReturn(ctx context.Context, id, amount int) (*models.ReturnRequest, error) {
+	ret, paymentID, amount, err := s.claimRefund(ctx, id, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.payments.RefundPayment(ctx, paymentID, amount); err != nil {
+		if releaseErr := s.storage.SetReturnStatus(ctx, ret.ID, models.ReturnRefunding, models.ReturnReceived); releaseErr != nil {
+			return nil, fmt.Errorf("%w (failed to release return: %v)", err, releaseErr)
+		}
+		return nil, err
+	}
+
+	ret.Status = models.ReturnRefunded
+	ret.RefundAmount = amount
+	ret.PaymentID = paymentID
+	ret.UpdatedAt = time.Now()
+	if err := s.storage.UpdateReturn(ctx, ret); err != nil {
+		return nil, fmt.Errorf("failed to update return: %w", err)
+	}
+	return ret, nil
+}
+
+// claimRefund проверяет принятый возврат и сумму, находит списанный платеж
+// заказа и переводит заявку из received в refunding. Возвращает заявку,
+// платеж и итоговую сумму возврата.
+func (s *returnService) claimRefund(ctx context.Context, id, amount int) (*models.ReturnRequest, int, int, error) {
+	if id <= 0 {
+		return nil, 0, 0, errors.New("invalid return ID")
+	}
+
+	tx, err := s.storage.BeginTx(ctx)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	ret, err := tx.GetReturnByID(ctx, id)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if ret.Status != models.ReturnReceived {
+		return nil, 0, 0, fmt.Errorf("cannot refund return in status %q", ret.Status)
+	}
+
+	value := ret.Value()
+	if amount == 0 {
+		amount = value
+	}
+	if amount < 1 || amount > value {
+		return nil, 0, 0, fmt.Errorf("validate: refund amount must be between 1 and %d", value)
+	}
+
+	payments, err := tx.GetPaymentsByOrder(ctx, ret.OrderID)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("failed to get payments: %w", err)
+	}
+	paymentID := 0
+	for _, p := range payments {
+		if p.Status == models.PaymentCaptured {
+			paymentID = p.ID
+		}
+	}
+	if paymentID == 0 {
+		return nil, 0, 0, fmt.Errorf("cannot refund return: order %d has no captured payment", ret.OrderID)
+	}
+
+	if err := tx.SetReturnStatus(ctx, ret.ID, models.ReturnReceived, models.ReturnRefunding); err != nil {
+		return nil, 0, 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, 0, 0, err
+	}
+	ret.Status = models.ReturnRefunding
+	return ret, paymentID, amount, nil
+}
+
+// checkOrderReturns запрещает отменять заказ и менять его состав, если по
+// нему есть неотклоненные возвраты: иначе товар вернулся бы на склад дважды.
+func checkOrderReturns(ctx context.Context, tx storage.StorageTx, orderID int) error {
+	returns, err := tx.GetReturnsByOrder(ctx, orderID)
+	if err != nil {
+		return fmt.Errorf("failed to get returns: %w", err)
+	}
+	for _, r := range returns {
+		if r.IsOpen() {
+			return errors.New("validate: order with returns cannot be cancelled or changed")
+		}
+	}
+	return nil
+}
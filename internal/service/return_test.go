@@ -0,0 +1,153 @@
+package service
+
+import (
+	"backend-store/internal/models"
+	"backend-store/internal/payment"
+	"backend-store/internal/storage"
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createReceivedReturn сохраняет принятый возврат на value по заказу.
+func createReceivedReturn(t *testing.T, store storage.Storage, orderID, value int) *models.ReturnRequest {
+	t.Helper()
+	ret := &models.ReturnRequest{
+		OrderID: orderID,
+		Status:  models.ReturnReceived,
+		Items:   []models.ReturnItem{{OrderItemID: 1, ProductID: 1, Quantity: 1, Price: value, Reason: models.ReturnReasonDamaged}},
+	}
+	require.NoError(t, store.CreateReturn(context.Background(), ret))
+	return ret
+}
+
+func TestReturnService_RefundReturn(t *testing.T) {
+	tests := []struct {
+		name         string
+		amounts      []int
+		decline      bool
+		wantErr      string
+		wantStatus   string
+		wantRefunded int
+	}{
+		{name: "full value", amounts: []int{0}, wantStatus: models.ReturnRefunded, wantRefunded: 60},
+		{name: "partial with deduction", amounts: []int{45}, wantStatus: models.ReturnRefunded, wantRefunded: 45},
+		{name: "more than return value", amounts: []int{61}, wantErr: "validate: refund amount must be between 1 and 60", wantStatus: models.ReturnReceived},
+		{name: "repeated refund", amounts: []int{0, 0}, wantErr: "cannot refund return in status \"refunded\"", wantStatus: models.ReturnRefunded, wantRefunded: 60},
+		{name: "gateway decline releases claim", amounts: []int{0}, decline: true, wantErr: "refund failed", wantStatus: models.ReturnReceived},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+			store := storage.NewMemoryStorage()
+			fake := payment.NewFake("", "", "", nil)
+			payments := NewPaymentService(store, fake)
+			returns := NewReturnService(store, payments, nil)
+			order, p := createPaidOrder(t, store, payments, 100)
+			ret := createReceivedReturn(t, store, order.ID, 60)
+			if tt.decline {
+				fake.Script(payment.OpRefund, payment.Decline)
+			}
+
+			// Act
+			var err error
+			for _, amount := range tt.amounts {
+				if _, err = returns.RefundReturn(ctx, ret.ID, amount); err != nil {
+					break
+				}
+			}
+
+			// Assert
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			stored, err := store.GetReturnByID(ctx, ret.ID)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, stored.Status)
+			storedPayment, err := store.GetPaymentByID(ctx, p.ID)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantRefunded, storedPayment.RefundedAmount)
+		})
+	}
+}
+
+func TestReturnService_RefundReturn_Concurrent(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	store := storage.NewMemoryStorage()
+	payments := NewPaymentService(store, payment.NewFake("", "", "", nil))
+	returns := NewReturnService(store, payments, nil)
+	order, p := createPaidOrder(t, store, payments, 100)
+	ret := createReceivedReturn(t, store, order.ID, 60)
+
+	// Act
+	const attempts = 10
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	succeeded := 0
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := returns.RefundReturn(ctx, ret.ID, 0); err == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Assert
+	assert.Equal(t, 1, succeeded)
+	storedPayment, err := store.GetPaymentByID(ctx, p.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 60, storedPayment.RefundedAmount)
+}
+
+func TestReturnService_ReceiveReturn_Concurrent(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	f := newStockFixture(t, map[string]int{"MAIN": 5})
+	returns := NewReturnService(f.store, nil, nil)
+	order := &models.Order{CustomerID: f.customerID, Status: models.OrderStatusDelivered, Subtotal: 20, Total: 20}
+	require.NoError(t, f.store.CreateOrder(ctx, order))
+	ret := &models.ReturnRequest{
+		OrderID: order.ID,
+		Status:  models.ReturnApproved,
+		Items:   []models.ReturnItem{{OrderItemID: 1, ProductID: f.productID, VariantID: f.variantID, Quantity: 2, Price: 10, Reason: models.ReturnReasonDamaged}},
+	}
+	require.NoError(t, f.store.CreateReturn(ctx, ret))
+
+	// Act
+	const attempts = 10
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	succeeded := 0
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := returns.ReceiveReturn(ctx, ret.ID, nil); err == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Assert
+	assert.Equal(t, 1, succeeded)
+	assert.Equal(t, map[string]int{"MAIN": 7}, f.levels(t))
+	stored, err := f.store.GetReturnByID(ctx, ret.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ReturnReceived, stored.Status)
+}
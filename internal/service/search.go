@@ -0,0 +1,45 @@
+package service
+
+import (
+	"backend-store/internal/models"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+func (s *productService) SearchProducts(ctx context.Context, query models.ProductSearch) (*models.SearchResult, error) {
+	query.Query = strings.TrimSpace(query.Query)
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.storage.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query.CategoryIDs = nil
+	if query.CategoryID > 0 {
+		tree, err := loadCategoryTree(ctx, tx)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := tree.Get(query.CategoryID); !ok {
+			return nil, errors.New("category not found")
+		}
+		query.CategoryIDs = tree.Descendants(query.CategoryID)
+	}
+
+	result, err := tx.SearchProducts(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search products: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
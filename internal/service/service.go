@@ -2,7 +2,9 @@ package service
 
 import (
 	"backend-store/internal/models"
+	"backend-store/internal/shipping"
 	"backend-store/internal/storage"
+	"backend-store/internal/tax"
 	"context"
 	"errors"
 	"fmt"
@@ -10,21 +12,24 @@ import (
 )
 
 type orderService struct {
-	storage storage.Storage
+	storage  storage.Storage
+	strategy models.AllocationStrategy
+	taxes    tax.Calculator
+	rates    shipping.RateProvider
+	observer StockObserver
 }
 
-func NewOrderService(storage storage.Storage) OrderService {
-	return &orderService{storage: storage}
+func NewOrderService(storage storage.Storage, strategy models.AllocationStrategy, taxes tax.Calculator, rates shipping.RateProvider, observer StockObserver) OrderService {
+	return &orderService{storage: storage, strategy: strategy, taxes: taxes, rates: rates, observer: observer}
 }
 
 func (s *orderService) CreateOrder(ctx context.Context, order *models.Order) error {
 	if err := order.Validate(); err != nil {
-		return err
+		return fmt.Errorf("validate: %w", err)
 	}
 
-	if order.Status == "" {
-		order.Status = "pending"
-	}
+	// Заказ переходит дальше только после оплаты, см. paymentService.
+	order.Status = models.OrderStatusPending
 
 	now := time.Now()
 	order.CreatedAt = now
@@ -36,23 +41,48 @@ func (s *orderService) CreateOrder(ctx context.Context, order *models.Order) err
 	}
 	defer tx.Rollback()
 
-	for _, item := range order.Products {
-		product, err := tx.GetProductByID(ctx, item.ProductID)
-		if err != nil {
-			return fmt.Errorf("product %d not found: %w", item.ProductID, err)
-		}
+	customer, err := tx.GetCustomerByID(ctx, order.CustomerID)
+	if err != nil {
+		return fmt.Errorf("customer %d not found", order.CustomerID)
+	}
+	if order.ShippingAddress == nil {
+		order.ShippingAddress = customer.DefaultAddress(models.AddressShipping)
+	}
 
-		if product.Quantity < item.Quantity {
-			return fmt.Errorf("insufficient quantity for product %d: available %d, requested %d",
-				item.ProductID, product.Quantity, item.Quantity)
-		}
+	if err := allocateOrder(ctx, tx, order, s.strategy); err != nil {
+		return err
+	}
+
+	if err := priceOrder(ctx, tx, order, s.taxes, s.rates); err != nil {
+		return err
 	}
 
 	if err := tx.CreateOrder(ctx, order); err != nil {
 		return fmt.Errorf("failed to create order: %w", err)
 	}
+	if err := recordAudit(ctx, tx, models.AuditCreate, models.AggregateOrder, order.ID, nil, order); err != nil {
+		return err
+	}
+
+	err = recordEvent(ctx, tx, models.EventOrderCreated, models.AggregateOrder, order.ID, models.OrderCreatedPayload{
+		OrderID:    order.ID,
+		CustomerID: order.CustomerID,
+		Status:     order.Status,
+		Total:      order.Total,
+	})
+	if err != nil {
+		return err
+	}
 
-	return tx.Commit()
+	if err := redeemPromotions(ctx, tx, order); err != nil {
+		return err
+	}
+
+	if err := applyAllocations(ctx, tx, order); err != nil {
+		return err
+	}
+
+	return commitStockChange(tx, s.observer)
 }
 
 func (s *orderService) GetAllOrders(ctx context.Context) ([]*models.Order, error) {
@@ -90,20 +120,36 @@ func (s *orderService) GetOrderByID(ctx context.Context, id int) (*models.Order,
 		return nil, fmt.Errorf("order not found: %w", err)
 	}
 
+	result := *order
+	result.Allocations, err = tx.GetAllocationsByOrder(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stock allocations: %w", err)
+	}
+	result.Returns, err = tx.GetReturnsByOrder(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get returns: %w", err)
+	}
+	result.Shipments, err = tx.GetShipmentsByOrder(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shipments: %w", err)
+	}
+	result.Adjustments, err = tx.GetOrderAdjustments(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order adjustments: %w", err)
+	}
+
 	if err := tx.Commit(); err != nil {
 		return nil, err
 	}
 
-	return order, nil
+	return &result, nil
 }
 
+// UpdateOrder без customer_id оставляет заказ за прежним покупателем, без
+// status - в прежнем статусе. Код промоакции задается только при создании
+// заказа: при изменении состава скидки пересчитываются по тому же коду, а
+// при отмене использования акций возвращаются.
 func (s *orderService) UpdateOrder(ctx context.Context, order *models.Order) error {
-	if err := order.Validate(); err != nil {
-		return err
-	}
-
-	order.UpdatedAt = time.Now()
-
 	tx, err := s.storage.BeginTx(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to start transaction: %w", err)
@@ -115,13 +161,96 @@ func (s *orderService) UpdateOrder(ctx context.Context, order *models.Order) err
 		return fmt.Errorf("order not found: %w", err)
 	}
 
+	if order.CustomerID == 0 {
+		order.CustomerID = existingOrder.CustomerID
+	}
+	if order.Status == "" {
+		order.Status = existingOrder.Status
+	}
+	if err := order.Validate(); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+	if order.CustomerID != existingOrder.CustomerID {
+		if _, err := tx.GetCustomerByID(ctx, order.CustomerID); err != nil {
+			return fmt.Errorf("customer %d not found", order.CustomerID)
+		}
+	}
+
 	order.CreatedAt = existingOrder.CreatedAt
+	order.UpdatedAt = time.Now()
+	if order.ShippingAddress == nil {
+		order.ShippingAddress = existingOrder.ShippingAddress
+	}
+	order.PromoCode = existingOrder.PromoCode
+	order.ShippingMethod = existingOrder.ShippingMethod
+
+	if err := checkOrderPaid(ctx, tx, existingOrder, order.Status); err != nil {
+		return err
+	}
+
+	wasCancelled := existingOrder.Status == models.OrderStatusCancelled
+	cancelled := order.Status == models.OrderStatusCancelled
+	reallocate := !cancelled && !sameOrderItems(existingOrder.Products, order.Products)
+	switch {
+	case wasCancelled && !cancelled:
+		return errors.New("validate: cancelled order cannot be reopened")
+	case cancelled && !wasCancelled, reallocate:
+		if err := checkOrderReturns(ctx, tx, order.ID); err != nil {
+			return err
+		}
+		if err := checkOrderShipments(ctx, tx, order.ID); err != nil {
+			return err
+		}
+		if err := releaseOrderStock(ctx, tx, order.ID); err != nil {
+			return err
+		}
+		if err := releasePromotions(ctx, tx, order.ID); err != nil {
+			return err
+		}
+	}
+	if reallocate {
+		if err := allocateOrder(ctx, tx, order, s.strategy); err != nil {
+			return err
+		}
+		if err := priceOrder(ctx, tx, order, s.taxes, s.rates); err != nil {
+			return err
+		}
+	} else {
+		// Состав не изменился: позиции, скидки, доставка и налог остаются
+		// прежними.
+		order.Products = existingOrder.Products
+		order.Subtotal = existingOrder.Subtotal
+		order.Discount = existingOrder.Discount
+		order.Total = existingOrder.Total
+		order.FreeShipping = existingOrder.FreeShipping
+		order.Tax = existingOrder.Tax
+		order.TaxInclusive = existingOrder.TaxInclusive
+		order.Shipping = existingOrder.Shipping
+	}
 
 	if err := tx.UpdateOrder(ctx, order); err != nil {
 		return fmt.Errorf("failed to update order: %w", err)
 	}
+	if err := recordOrderStatusChange(ctx, tx, existingOrder, order.Status); err != nil {
+		return err
+	}
+	if err := recordAudit(ctx, tx, models.AuditUpdate, models.AggregateOrder, order.ID, existingOrder, order); err != nil {
+		return err
+	}
+
+	if reallocate {
+		if err := tx.DeleteOrderAdjustments(ctx, order.ID); err != nil {
+			return fmt.Errorf("failed to delete order adjustments: %w", err)
+		}
+		if err := redeemPromotions(ctx, tx, order); err != nil {
+			return err
+		}
+		if err := applyAllocations(ctx, tx, order); err != nil {
+			return err
+		}
+	}
 
-	return tx.Commit()
+	return commitStockChange(tx, s.observer)
 }
 
 func (s *orderService) DeleteOrder(ctx context.Context, id int) error {
@@ -135,24 +264,45 @@ func (s *orderService) DeleteOrder(ctx context.Context, id int) error {
 	}
 	defer tx.Rollback()
 
-	_, err = tx.GetOrderByID(ctx, id)
+	order, err := tx.GetOrderByID(ctx, id)
 	if err != nil {
 		return fmt.Errorf("order not found: %w", err)
 	}
 
+	payments, err := tx.GetPaymentsByOrder(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get payments: %w", err)
+	}
+	if len(payments) > 0 {
+		return errors.New("cannot delete order with payments")
+	}
+
+	if order.Status != models.OrderStatusCancelled {
+		if err := releaseOrderStock(ctx, tx, id); err != nil {
+			return err
+		}
+	}
+	if err := releasePromotions(ctx, tx, id); err != nil {
+		return err
+	}
+
 	if err := tx.DeleteOrder(ctx, id); err != nil {
 		return fmt.Errorf("failed to delete order: %w", err)
 	}
+	if err := recordAudit(ctx, tx, models.AuditDelete, models.AggregateOrder, id, order, nil); err != nil {
+		return err
+	}
 
-	return tx.Commit()
+	return commitStockChange(tx, s.observer)
 }
 
 type productService struct {
-	storage storage.Storage
+	storage  storage.Storage
+	observer StockObserver
 }
 
-func NewProductService(storage storage.Storage) ProductService {
-	return &productService{storage: storage}
+func NewProductService(storage storage.Storage, observer StockObserver) ProductService {
+	return &productService{storage: storage, observer: observer}
 }
 
 func (s *productService) CreateProduct(ctx context.Context, product *models.Product) error {
@@ -174,10 +324,26 @@ func (s *productService) CreateProduct(ctx context.Context, product *models.Prod
 		return fmt.Errorf("failed to create product: %w", err)
 	}
 
-	return tx.Commit()
+	if err := createVariants(ctx, tx, product, models.MovementAdjustment); err != nil {
+		return err
+	}
+
+	if err := saveProductTaxonomy(ctx, tx, product); err != nil {
+		return err
+	}
+
+	after, err := productAuditState(ctx, tx, product)
+	if err != nil {
+		return err
+	}
+	if err := recordAudit(ctx, tx, models.AuditCreate, models.AggregateProduct, product.ID, nil, after); err != nil {
+		return err
+	}
+
+	return commitStockChange(tx, s.observer)
 }
 
-func (s *productService) GetAllProducts(ctx context.Context) ([]*models.Product, error) {
+func (s *productService) GetAllProducts(ctx context.Context, filter models.ProductFilter) ([]*models.Product, error) {
 	tx, err := s.storage.BeginTx(ctx)
 	if err != nil {
 		return nil, err
@@ -189,6 +355,11 @@ func (s *productService) GetAllProducts(ctx context.Context) ([]*models.Product,
 		return nil, fmt.Errorf("failed to get products: %w", err)
 	}
 
+	products, err = filterProducts(ctx, tx, products, filter)
+	if err != nil {
+		return nil, err
+	}
+
 	if err := tx.Commit(); err != nil {
 		return nil, err
 	}
@@ -212,11 +383,29 @@ func (s *productService) GetProductByID(ctx context.Context, id int) (*models.Pr
 		return nil, fmt.Errorf("failed to get product: %w", err)
 	}
 
+	variants, err := tx.GetVariantsByProductID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get variants: %w", err)
+	}
+
+	result := *product
+	if err := loadProductTaxonomy(ctx, tx, &result); err != nil {
+		return nil, err
+	}
+	if err := loadProductStock(ctx, tx, &result, variants); err != nil {
+		return nil, err
+	}
+
 	if err := tx.Commit(); err != nil {
 		return nil, err
 	}
 
-	return product, nil
+	result.Variants = make([]models.ProductVariant, len(variants))
+	for i, v := range variants {
+		result.Variants[i] = *v
+	}
+
+	return &result, nil
 }
 
 func (s *productService) UpdateProduct(ctx context.Context, product *models.Product) error {
@@ -236,14 +425,44 @@ func (s *productService) UpdateProduct(ctx context.Context, product *models.Prod
 	if err != nil {
 		return fmt.Errorf("product not found: %w", err)
 	}
+	before, err := productAuditState(ctx, tx, existingProduct)
+	if err != nil {
+		return err
+	}
 
 	product.CreatedAt = existingProduct.CreatedAt
+	if product.Options == nil {
+		product.Options = existingProduct.Options
+	}
+	if product.SKU == "" {
+		product.SKU = existingProduct.SKU
+	}
 
+	product.Variants = nil
 	if err := tx.UpdateProduct(ctx, product); err != nil {
 		return fmt.Errorf("failed to update product: %w", err)
 	}
 
-	return tx.Commit()
+	if err := syncVariants(ctx, tx, product, models.MovementAdjustment); err != nil {
+		return err
+	}
+	if err := recordPriceChange(ctx, tx, existingProduct, product); err != nil {
+		return err
+	}
+
+	if err := saveProductTaxonomy(ctx, tx, product); err != nil {
+		return err
+	}
+
+	after, err := productAuditState(ctx, tx, product)
+	if err != nil {
+		return err
+	}
+	if err := recordAudit(ctx, tx, models.AuditUpdate, models.AggregateProduct, product.ID, before, after); err != nil {
+		return err
+	}
+
+	return commitStockChange(tx, s.observer)
 }
 
 func (s *productService) DeleteProduct(ctx context.Context, id int) error {
@@ -257,14 +476,31 @@ func (s *productService) DeleteProduct(ctx context.Context, id int) error {
 	}
 	defer tx.Rollback()
 
-	_, err = tx.GetProductByID(ctx, id)
+	product, err := tx.GetProductByID(ctx, id)
 	if err != nil {
 		return fmt.Errorf("product not found: %w", err)
 	}
+	before, err := productAuditState(ctx, tx, product)
+	if err != nil {
+		return err
+	}
+
+	variants, err := tx.GetVariantsByProductID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get variants: %w", err)
+	}
+	for _, v := range variants {
+		if err := clearVariantStock(ctx, tx, v.ID); err != nil {
+			return err
+		}
+	}
 
 	if err := tx.DeleteProduct(ctx, id); err != nil {
 		return fmt.Errorf("failed to delete product: %w", err)
 	}
+	if err := recordAudit(ctx, tx, models.AuditDelete, models.AggregateProduct, id, before, nil); err != nil {
+		return err
+	}
 
-	return tx.Commit()
+	return commitStockChange(tx, s.observer)
 }
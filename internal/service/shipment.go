@@ -0,0 +1,232 @@
+package service
+
+import (
+	"backend-store/internal/models"
+	"backend-store/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type shipmentService struct {
+	storage storage.Storage
+}
+
+func NewShipmentService(storage storage.Storage) ShipmentService {
+	return &shipmentService{storage: storage}
+}
+
+// shipmentLine, как и returnLine, считает отправленное количество по товару
+// и варианту позиции.
+type shipmentLine struct {
+	productID int
+	variantID int
+}
+
+// CreateShipment создает отправление позиций оплаченного заказа. Для каждой
+// позиции нельзя отправить больше, чем заказано, за вычетом позиций других
+// неотмененных отправлений. Отправление создается с этикеткой или сразу
+// переданным перевозчику.
+func (s *shipmentService) CreateShipment(ctx context.Context, shipment *models.Shipment) error {
+	if shipment.OrderID <= 0 {
+		return errors.New("invalid order ID")
+	}
+	if shipment.Status == "" {
+		shipment.Status = models.ShipmentLabelCreated
+	}
+	if shipment.Status != models.ShipmentLabelCreated && shipment.Status != models.ShipmentShipped {
+		return fmt.Errorf("validate: new shipment status must be %q or %q", models.ShipmentLabelCreated, models.ShipmentShipped)
+	}
+	if err := shipment.Validate(); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+
+	tx, err := s.storage.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	order, err := tx.GetOrderByID(ctx, shipment.OrderID)
+	if err != nil {
+		return err
+	}
+	if order.Status == models.OrderStatusPending || order.Status == models.OrderStatusCancelled {
+		return fmt.Errorf("cannot ship order in status %q", order.Status)
+	}
+
+	ordered := make(map[shipmentLine]int)
+	items := make(map[int]models.OrderItem, len(order.Products))
+	for _, item := range order.Products {
+		ordered[shipmentLine{item.ProductID, item.VariantID}] += item.Quantity
+		items[item.ID] = item
+	}
+
+	existing, err := tx.GetShipmentsByOrder(ctx, shipment.OrderID)
+	if err != nil {
+		return fmt.Errorf("failed to get shipments: %w", err)
+	}
+	shipped := make(map[shipmentLine]int)
+	for _, sh := range existing {
+		if !sh.IsOpen() {
+			continue
+		}
+		for _, item := range sh.Items {
+			shipped[shipmentLine{item.ProductID, item.VariantID}] += item.Quantity
+		}
+	}
+
+	for i := range shipment.Items {
+		item := &shipment.Items[i]
+		orderItem, ok := items[item.OrderItemID]
+		if !ok {
+			return fmt.Errorf("validate: order item %d not found in order %d", item.OrderItemID, shipment.OrderID)
+		}
+		item.ProductID = orderItem.ProductID
+		item.VariantID = orderItem.VariantID
+
+		line := shipmentLine{item.ProductID, item.VariantID}
+		if available := ordered[line] - shipped[line]; item.Quantity > available {
+			return fmt.Errorf("validate: only %d of order item %d can be shipped", available, item.OrderItemID)
+		}
+		shipped[line] += item.Quantity
+	}
+
+	now := time.Now()
+	shipment.ShippedAt = nil
+	shipment.DeliveredAt = nil
+	if shipment.Status == models.ShipmentShipped {
+		shipment.ShippedAt = &now
+	}
+	shipment.CreatedAt = now
+	shipment.UpdatedAt = now
+	if err := tx.CreateShipment(ctx, shipment); err != nil {
+		return fmt.Errorf("failed to create shipment: %w", err)
+	}
+
+	if err := rollupOrderStatus(ctx, tx, order); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *shipmentService) GetShipment(ctx context.Context, id int) (*models.Shipment, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid shipment ID")
+	}
+	return s.storage.GetShipmentByID(ctx, id)
+}
+
+func (s *shipmentService) GetOrderShipments(ctx context.Context, orderID int) ([]models.Shipment, error) {
+	if orderID <= 0 {
+		return nil, errors.New("invalid order ID")
+	}
+	if _, err := s.storage.GetOrderByID(ctx, orderID); err != nil {
+		return nil, err
+	}
+	shipments, err := s.storage.GetShipmentsByOrder(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shipments: %w", err)
+	}
+	return shipments, nil
+}
+
+// UpdateShipment меняет статус, перевозчика и трек-номер отправления; пустое
+// значение оставляет прежнее. Статус меняется только вперед, см.
+// models.Shipment.CanTransition. Доставленное или отмененное отправление
+// не изменяется.
+func (s *shipmentService) UpdateShipment(ctx context.Context, id int, status, carrier, trackingNumber string) (*models.Shipment, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid shipment ID")
+	}
+
+	tx, err := s.storage.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	shipment, err := tx.GetShipmentByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if shipment.Status == models.ShipmentDelivered || shipment.Status == models.ShipmentCancelled {
+		return nil, fmt.Errorf("cannot update shipment in status %q", shipment.Status)
+	}
+	if status == "" {
+		status = shipment.Status
+	}
+	if !shipment.CanTransition(status) {
+		return nil, fmt.Errorf("validate: shipment cannot move from %q to %q", shipment.Status, status)
+	}
+	if carrier != "" {
+		shipment.Carrier = carrier
+	}
+	if trackingNumber != "" {
+		shipment.TrackingNumber = trackingNumber
+	}
+	if err := shipment.Validate(); err != nil {
+		return nil, fmt.Errorf("validate: %w", err)
+	}
+
+	now := time.Now()
+	if status == models.ShipmentShipped || status == models.ShipmentDelivered {
+		if shipment.ShippedAt == nil {
+			shipment.ShippedAt = &now
+		}
+	}
+	if status == models.ShipmentDelivered && shipment.DeliveredAt == nil {
+		shipment.DeliveredAt = &now
+	}
+	shipment.Status = status
+	shipment.UpdatedAt = now
+	if err := tx.UpdateShipment(ctx, shipment); err != nil {
+		return nil, fmt.Errorf("failed to update shipment: %w", err)
+	}
+
+	order, err := tx.GetOrderByID(ctx, shipment.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	if err := rollupOrderStatus(ctx, tx, order); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return shipment, nil
+}
+
+// rollupOrderStatus пересчитывает статус заказа по его отправлениям. Заказ в
+// другом статусе, например ожидающий оплаты или отмененный, не меняется.
+func rollupOrderStatus(ctx context.Context, tx storage.StorageTx, order *models.Order) error {
+	switch order.Status {
+	case models.OrderStatusProcessing, models.OrderStatusPartiallyShipped,
+		models.OrderStatusShipped, models.OrderStatusDelivered:
+	default:
+		return nil
+	}
+
+	shipments, err := tx.GetShipmentsByOrder(ctx, order.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get shipments: %w", err)
+	}
+	return setOrderStatus(ctx, tx, order, models.FulfillmentStatus(order, shipments))
+}
+
+// checkOrderShipments запрещает отменять заказ и менять его состав, если по
+// нему есть неотмененные отправления.
+func checkOrderShipments(ctx context.Context, tx storage.StorageTx, orderID int) error {
+	shipments, err := tx.GetShipmentsByOrder(ctx, orderID)
+	if err != nil {
+		return fmt.Errorf("failed to get shipments: %w", err)
+	}
+	for _, s := range shipments {
+		if s.IsOpen() {
+			return errors.New("validate: order with shipments cannot be cancelled or changed")
+		}
+	}
+	return nil
+}
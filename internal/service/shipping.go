@@ -0,0 +1,123 @@
+package service
+
+import (
+	"backend-store/internal/models"
+	"backend-store/internal/shipping"
+	"backend-store/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type shippingService struct {
+	storage storage.Storage
+	rates   shipping.RateProvider
+}
+
+func NewShippingService(storage storage.Storage, rates shipping.RateProvider) ShippingService {
+	return &shippingService{storage: storage, rates: rates}
+}
+
+// QuoteCart возвращает способы доставки корзины по адресу. Порог бесплатной
+// доставки сравнивается с суммой после скидок, а акция с бесплатной
+// доставкой обнуляет стоимость - так же, как при оформлении заказа.
+func (s *shippingService) QuoteCart(ctx context.Context, cartID int, token string, address *models.Address, promoCode string) ([]shipping.Quote, error) {
+	if address != nil {
+		if err := address.Validate(); err != nil {
+			return nil, fmt.Errorf("validate: %w", err)
+		}
+	}
+
+	tx, err := s.storage.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	cart, err := loadActiveCart(ctx, tx, cartID, token, now)
+	if err != nil {
+		return nil, err
+	}
+	if address == nil && cart.UserID > 0 {
+		if customer, err := tx.GetCustomerByID(ctx, cart.UserID); err == nil {
+			address = customer.DefaultAddress(models.AddressShipping)
+		}
+	}
+	if err := priceCart(ctx, tx, cart); err != nil {
+		return nil, err
+	}
+
+	lines := cartPricingLines(cart)
+	pricing, err := applyPromotions(ctx, tx, lines, promoCode, cart.UserID, now)
+	if err != nil {
+		return nil, err
+	}
+	return quoteShipping(ctx, tx, s.rates, lines, address, pricing.Subtotal-pricing.Discount, pricing.FreeShipping)
+}
+
+// quoteShipping собирает посылку из товаров позиций и запрашивает способы
+// доставки. subtotal - сумма товаров после скидок.
+func quoteShipping(ctx context.Context, tx storage.StorageTx, rates shipping.RateProvider, lines []models.PricingLine,
+	address *models.Address, subtotal int, freeShipping bool) ([]shipping.Quote, error) {
+	req := shipping.Request{Subtotal: subtotal}
+	if address != nil {
+		req.Country = address.Country
+		req.Region = address.Region
+		req.PostalCode = address.PostalCode
+	}
+
+	products := make(map[int]*models.Product)
+	for _, line := range lines {
+		product, ok := products[line.ProductID]
+		if !ok {
+			var err error
+			product, err = tx.GetProductByID(ctx, line.ProductID)
+			if err != nil {
+				return nil, fmt.Errorf("product %d not found: %w", line.ProductID, err)
+			}
+			products[line.ProductID] = product
+		}
+		req.Weight += product.Weight * line.Quantity
+		req.Volume += product.Volume() * line.Quantity
+	}
+
+	quotes, err := rates.Quote(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to quote shipping: %w", err)
+	}
+	if freeShipping {
+		for i := range quotes {
+			quotes[i].Cost = 0
+		}
+	}
+	return quotes, nil
+}
+
+// applyShipping рассчитывает стоимость доставки заказа выбранным способом.
+// Заказ без способа доставки допускается, только если по его адресу нет ни
+// одного способа, например когда способы не настроены.
+func applyShipping(ctx context.Context, tx storage.StorageTx, order *models.Order, lines []models.PricingLine, rates shipping.RateProvider) error {
+	quotes, err := quoteShipping(ctx, tx, rates, lines, order.ShippingAddress, order.Subtotal-order.Discount, order.FreeShipping)
+	if err != nil {
+		return err
+	}
+
+	order.ShippingMethod = strings.ToLower(strings.TrimSpace(order.ShippingMethod))
+	if order.ShippingMethod == "" {
+		if len(quotes) > 0 {
+			return errors.New("validate: shipping method is required")
+		}
+		order.Shipping = 0
+		return nil
+	}
+	for _, q := range quotes {
+		if q.Method == order.ShippingMethod {
+			order.Shipping = q.Cost
+			return nil
+		}
+	}
+	return fmt.Errorf("validate: shipping method %q is not available for this order", order.ShippingMethod)
+}
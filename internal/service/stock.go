@@ -0,0 +1,481 @@
+package service
+
+import (
+	"backend-store/internal/models"
+	"backend-store/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Складские остатки (warehouse_stock) являются источником истины: остаток
+// варианта равен сумме его остатков по складам, остаток товара - сумме
+// остатков вариантов.
+
+// primaryWarehouse возвращает активный склад с наименьшим приоритетом; на него
+// поступает товар, остаток которого задан через API товаров.
+func primaryWarehouse(ctx context.Context, tx storage.StorageTx) (*models.Warehouse, error) {
+	warehouses, err := tx.GetAllWarehouses(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get warehouses: %w", err)
+	}
+	ordered := models.SortWarehouses(warehouses, models.AllocationPriority, nil)
+	if len(ordered) == 0 {
+		return nil, errors.New("no active warehouse to receive stock")
+	}
+	return ordered[0], nil
+}
+
+// setVariantStock приводит сумму складских остатков варианта к quantity.
+// Прирост поступает на основной склад, уменьшение списывается со складов
+// в порядке приоритета. Остаток самого варианта вызывающий уже сохранил.
+func setVariantStock(ctx context.Context, tx storage.StorageTx, variantID, quantity int, reason, reference string) error {
+	levels, err := tx.GetStockLevelsByVariant(ctx, variantID)
+	if err != nil {
+		return fmt.Errorf("failed to get stock levels: %w", err)
+	}
+	available := make(map[int]int, len(levels))
+	current := 0
+	for _, l := range levels {
+		available[l.WarehouseID] = l.Quantity
+		current += l.Quantity
+	}
+
+	delta := quantity - current
+	switch {
+	case delta > 0:
+		primary, err := primaryWarehouse(ctx, tx)
+		if err != nil {
+			return err
+		}
+		return moveStock(ctx, tx, primary.ID, variantID, delta, reason, reference)
+	case delta < 0:
+		warehouses, err := tx.GetAllWarehouses(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get warehouses: %w", err)
+		}
+		plan, err := models.PlanAllocation(warehouses, available, -delta)
+		if err != nil {
+			return err
+		}
+		for _, a := range plan {
+			if err := moveStock(ctx, tx, a.WarehouseID, variantID, -a.Quantity, reason, reference); err != nil {
+				return err
+			}
+		}
+		if quantity == 0 {
+			variant, err := tx.GetVariantByID(ctx, variantID)
+			if err != nil {
+				return fmt.Errorf("product variant %d not found: %w", variantID, err)
+			}
+			return recordStockDepleted(ctx, tx, variant)
+		}
+	}
+	return nil
+}
+
+// clearVariantStock списывает все остатки варианта перед его удалением,
+// чтобы журнал продолжал сходиться с остатками.
+func clearVariantStock(ctx context.Context, tx storage.StorageTx, variantID int) error {
+	levels, err := tx.GetStockLevelsByVariant(ctx, variantID)
+	if err != nil {
+		return fmt.Errorf("failed to get stock levels: %w", err)
+	}
+	for _, l := range levels {
+		err := moveStock(ctx, tx, l.WarehouseID, variantID, -l.Quantity,
+			models.MovementAdjustment, fmt.Sprintf("variant:%d deleted", variantID))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// moveStock меняет остаток на складе и записывает движение в журнал.
+// Все изменения складских остатков проходят через эту функцию.
+func moveStock(ctx context.Context, tx storage.StorageTx, warehouseID, variantID, delta int, reason, reference string) error {
+	if delta == 0 {
+		return nil
+	}
+
+	variant, err := tx.GetVariantByID(ctx, variantID)
+	if err != nil {
+		return fmt.Errorf("product variant %d not found: %w", variantID, err)
+	}
+
+	balance, err := tx.AdjustStockLevel(ctx, warehouseID, variantID, delta)
+	if err != nil {
+		return err
+	}
+
+	actor := actorFromContext(ctx)
+	movement := &models.StockMovement{
+		WarehouseID:   warehouseID,
+		VariantID:     variantID,
+		ProductID:     variant.ProductID,
+		Delta:         delta,
+		BalanceAfter:  balance,
+		Reason:        reason,
+		Actor:         actor.name,
+		ActorVerified: actor.verified,
+		Reference:     reference,
+	}
+	if err := tx.CreateStockMovement(ctx, movement); err != nil {
+		return fmt.Errorf("failed to record stock movement: %w", err)
+	}
+	return nil
+}
+
+// adjustStock меняет остаток варианта на складе и пересчитывает остатки
+// варианта и товара.
+func adjustStock(ctx context.Context, tx storage.StorageTx, warehouseID, variantID, delta int, reason, reference string) error {
+	if err := moveStock(ctx, tx, warehouseID, variantID, delta, reason, reference); err != nil {
+		return err
+	}
+	return syncVariantQuantity(ctx, tx, variantID)
+}
+
+func syncVariantQuantity(ctx context.Context, tx storage.StorageTx, variantID int) error {
+	levels, err := tx.GetStockLevelsByVariant(ctx, variantID)
+	if err != nil {
+		return fmt.Errorf("failed to get stock levels: %w", err)
+	}
+	quantity := 0
+	for _, l := range levels {
+		quantity += l.Quantity
+	}
+
+	variant, err := tx.GetVariantByID(ctx, variantID)
+	if err != nil {
+		return fmt.Errorf("product variant %d not found: %w", variantID, err)
+	}
+	if variant.Quantity != quantity {
+		updated := *variant
+		updated.Quantity = quantity
+		if err := tx.UpdateVariant(ctx, &updated); err != nil {
+			return fmt.Errorf("failed to update variant: %w", err)
+		}
+		if quantity == 0 {
+			if err := recordStockDepleted(ctx, tx, variant); err != nil {
+				return err
+			}
+		}
+	}
+
+	product, err := tx.GetProductByID(ctx, variant.ProductID)
+	if err != nil {
+		return fmt.Errorf("product not found: %w", err)
+	}
+	return refreshProductStock(ctx, tx, product)
+}
+
+// allocateOrder проверяет наличие, проставляет цены позиций и распределяет
+// товар по складам в порядке стратегии. План сохраняется в order.Allocations;
+// списание выполняет applyAllocations, когда у заказа уже есть ID.
+func allocateOrder(ctx context.Context, tx storage.StorageTx, order *models.Order, strategy models.AllocationStrategy) error {
+	warehouses, err := tx.GetAllWarehouses(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get warehouses: %w", err)
+	}
+	ordered := models.SortWarehouses(warehouses, strategy, order.ShippingAddress)
+
+	// Остатки с учетом уже распределенных позиций этого заказа.
+	reserved := make(map[int]int)
+	stock := make(map[int]map[int]int)
+
+	order.Allocations = nil
+	for i := range order.Products {
+		item := &order.Products[i]
+		available, err := resolveOrderItem(ctx, tx, item)
+		if err != nil {
+			return err
+		}
+		available -= reserved[item.VariantID]
+
+		if available < item.Quantity {
+			return fmt.Errorf("insufficient quantity for product %d: available %d, requested %d",
+				item.ProductID, available, item.Quantity)
+		}
+
+		// Товар без вариантов не имеет складских остатков.
+		if item.VariantID == 0 {
+			continue
+		}
+
+		if stock[item.VariantID] == nil {
+			levels, err := tx.GetStockLevelsByVariant(ctx, item.VariantID)
+			if err != nil {
+				return fmt.Errorf("failed to get stock levels: %w", err)
+			}
+			stock[item.VariantID] = make(map[int]int, len(levels))
+			for _, l := range levels {
+				stock[item.VariantID][l.WarehouseID] = l.Quantity
+			}
+		}
+
+		plan, err := models.PlanAllocation(ordered, stock[item.VariantID], item.Quantity)
+		if err != nil {
+			return fmt.Errorf("insufficient quantity for product %d in active warehouses: %w", item.ProductID, err)
+		}
+		for _, a := range plan {
+			stock[item.VariantID][a.WarehouseID] -= a.Quantity
+			a.VariantID = item.VariantID
+			order.Allocations = append(order.Allocations, a)
+		}
+		reserved[item.VariantID] += item.Quantity
+	}
+	return nil
+}
+
+// applyAllocations списывает товар по плану allocateOrder и сохраняет
+// распределения заказа.
+func applyAllocations(ctx context.Context, tx storage.StorageTx, order *models.Order) error {
+	reference := fmt.Sprintf("order:%d", order.ID)
+	for i := range order.Allocations {
+		allocation := &order.Allocations[i]
+		err := adjustStock(ctx, tx, allocation.WarehouseID, allocation.VariantID, -allocation.Quantity,
+			models.MovementOrder, reference)
+		if err != nil {
+			return err
+		}
+		allocation.OrderID = order.ID
+		if err := tx.CreateAllocation(ctx, allocation); err != nil {
+			return fmt.Errorf("failed to save stock allocation: %w", err)
+		}
+	}
+	return nil
+}
+
+// releaseOrderStock возвращает списанный под заказ товар на те же склады.
+func releaseOrderStock(ctx context.Context, tx storage.StorageTx, orderID int) error {
+	allocations, err := tx.GetAllocationsByOrder(ctx, orderID)
+	if err != nil {
+		return fmt.Errorf("failed to get stock allocations: %w", err)
+	}
+	reference := fmt.Sprintf("order:%d", orderID)
+	for _, a := range allocations {
+		err := adjustStock(ctx, tx, a.WarehouseID, a.VariantID, a.Quantity, models.MovementCancellation, reference)
+		if err != nil {
+			return err
+		}
+	}
+	if err := tx.DeleteAllocationsByOrder(ctx, orderID); err != nil {
+		return fmt.Errorf("failed to delete stock allocations: %w", err)
+	}
+	return nil
+}
+
+// loadProductStock заполняет product.Stock: остатки по складам и товар,
+// находящийся в перемещениях между складами.
+func loadProductStock(ctx context.Context, tx storage.StorageTx, product *models.Product, variants []*models.ProductVariant) error {
+	levels, err := tx.GetStockLevelsByProduct(ctx, product.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get stock levels: %w", err)
+	}
+	warehouses, err := tx.GetAllWarehouses(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get warehouses: %w", err)
+	}
+
+	byWarehouse := make(map[int]int)
+	stock := &models.ProductStock{Levels: levels, Warehouses: []models.WarehouseStock{}}
+	for _, l := range levels {
+		byWarehouse[l.WarehouseID] += l.Quantity
+		stock.Available += l.Quantity
+	}
+	for _, w := range warehouses {
+		if quantity, ok := byWarehouse[w.ID]; ok {
+			stock.Warehouses = append(stock.Warehouses, models.WarehouseStock{
+				WarehouseID: w.ID,
+				Code:        w.Code,
+				Name:        w.Name,
+				Quantity:    quantity,
+			})
+		}
+	}
+
+	transfers, err := tx.GetAllTransfers(ctx, models.TransferInTransit)
+	if err != nil {
+		return fmt.Errorf("failed to get transfers: %w", err)
+	}
+	own := make(map[int]bool, len(variants))
+	for _, v := range variants {
+		own[v.ID] = true
+	}
+	for _, t := range transfers {
+		for _, item := range t.Items {
+			if own[item.VariantID] {
+				stock.InTransit += item.Quantity
+			}
+		}
+	}
+
+	product.Stock = stock
+	return nil
+}
+
+// sameOrderItems сравнивает состав заказа без учета порядка позиций и цен.
+func sameOrderItems(a, b []models.OrderItem) bool {
+	count := func(items []models.OrderItem) map[[2]int]int {
+		m := make(map[[2]int]int, len(items))
+		for _, item := range items {
+			m[[2]int{item.ProductID, item.VariantID}] += item.Quantity
+		}
+		return m
+	}
+	ca, cb := count(a), count(b)
+	if len(ca) != len(cb) {
+		return false
+	}
+	for key, quantity := range ca {
+		if cb[key] != quantity {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *productService) GetStockMovements(ctx context.Context, productID int) ([]models.StockMovement, error) {
+	if productID <= 0 {
+		return nil, errors.New("invalid product ID")
+	}
+
+	tx, err := s.storage.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.GetProductByID(ctx, productID); err != nil {
+		return nil, fmt.Errorf("product not found: %w", err)
+	}
+
+	movements, err := tx.GetStockMovementsByProduct(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stock movements: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return movements, nil
+}
+
+// ReconcileStock сверяет остатки с журналом движения: складские остатки с
+// суммой движений, остатки вариантов с суммой по складам и остатки товаров с
+// суммой по вариантам. Расхождения только сообщаются, но не исправляются.
+func (s *warehouseService) ReconcileStock(ctx context.Context) (*models.StockReconciliation, error) {
+	tx, err := s.storage.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	levels, err := tx.GetAllStockLevels(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stock levels: %w", err)
+	}
+	balances, err := tx.GetStockLedgerBalances(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stock ledger: %w", err)
+	}
+	products, err := tx.GetAllProducts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get products: %w", err)
+	}
+
+	report := &models.StockReconciliation{
+		CheckedAt: time.Now(),
+		Levels:    len(levels),
+		Products:  len(products),
+		Drifts:    []models.StockDrift{},
+	}
+
+	type key struct{ warehouseID, variantID int }
+	ledger := make(map[key]models.StockBalance, len(balances))
+	for _, b := range balances {
+		ledger[key{b.WarehouseID, b.VariantID}] = b
+		if b.BrokenChain > 0 {
+			report.Drifts = append(report.Drifts, models.StockDrift{
+				Scope:       models.DriftLedgerChain,
+				WarehouseID: b.WarehouseID,
+				VariantID:   b.VariantID,
+				ProductID:   b.ProductID,
+				Expected:    0,
+				Actual:      b.BrokenChain,
+			})
+		}
+	}
+
+	variantStock := make(map[int]int)
+	for _, l := range levels {
+		variantStock[l.VariantID] += l.Quantity
+		k := key{l.WarehouseID, l.VariantID}
+		b := ledger[k]
+		delete(ledger, k)
+		if b.Quantity != l.Quantity {
+			report.Drifts = append(report.Drifts, models.StockDrift{
+				Scope:       models.DriftWarehouseStock,
+				WarehouseID: l.WarehouseID,
+				VariantID:   l.VariantID,
+				ProductID:   l.ProductID,
+				Expected:    b.Quantity,
+				Actual:      l.Quantity,
+			})
+		}
+	}
+	// Движения без строки остатка: остаток был удален в обход журнала.
+	for _, b := range balances {
+		if _, missing := ledger[key{b.WarehouseID, b.VariantID}]; missing && b.Quantity != 0 {
+			report.Drifts = append(report.Drifts, models.StockDrift{
+				Scope:       models.DriftWarehouseStock,
+				WarehouseID: b.WarehouseID,
+				VariantID:   b.VariantID,
+				ProductID:   b.ProductID,
+				Expected:    b.Quantity,
+				Actual:      0,
+			})
+		}
+	}
+
+	for _, product := range products {
+		variants, err := tx.GetVariantsByProductID(ctx, product.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get variants: %w", err)
+		}
+		if len(variants) == 0 {
+			continue
+		}
+		report.Variants += len(variants)
+
+		total := 0
+		for _, v := range variants {
+			total += v.Quantity
+			if v.Quantity != variantStock[v.ID] {
+				report.Drifts = append(report.Drifts, models.StockDrift{
+					Scope:     models.DriftVariant,
+					VariantID: v.ID,
+					ProductID: product.ID,
+					Expected:  variantStock[v.ID],
+					Actual:    v.Quantity,
+				})
+			}
+		}
+		if product.Quantity != total {
+			report.Drifts = append(report.Drifts, models.StockDrift{
+				Scope:     models.DriftProduct,
+				ProductID: product.ID,
+				Expected:  total,
+				Actual:    product.Quantity,
+			})
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
@@ -0,0 +1,219 @@
+package service
+
+import (
+	"backend-store/internal/models"
+	"backend-store/internal/shipping"
+	"backend-store/internal/storage"
+	"backend-store/internal/tax"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stockFixture - товар с остатками на складах MAIN (приоритет 0, без
+// адреса), EAST (1, Берлин) и WEST (2, Париж).
+type stockFixture struct {
+	store      storage.Storage
+	customerID int
+	productID  int
+	variantID  int
+	warehouses map[string]int // код склада -> ID
+}
+
+func newStockFixture(t *testing.T, stock map[string]int) *stockFixture {
+	t.Helper()
+	ctx := context.Background()
+	store := storage.NewMemoryStorage()
+	warehouses := NewWarehouseService(store, nil)
+	f := &stockFixture{store: store, customerID: createCustomers(t, store, 1)[0], warehouses: map[string]int{}}
+
+	all, err := warehouses.GetAllWarehouses(ctx)
+	require.NoError(t, err)
+	f.warehouses["MAIN"] = all[0].ID
+	for _, w := range []*models.Warehouse{
+		{Code: "EAST", Name: "East", Priority: 1, Active: true, Address: models.Address{Country: "DE", City: "Berlin"}},
+		{Code: "WEST", Name: "West", Priority: 2, Active: true, Address: models.Address{Country: "FR", City: "Paris"}},
+	} {
+		require.NoError(t, warehouses.CreateWarehouse(ctx, w))
+		f.warehouses[w.Code] = w.ID
+	}
+
+	product := &models.Product{SKU: "MUG", Name: "Mug", Price: 10}
+	require.NoError(t, NewProductService(store, nil).CreateProduct(ctx, product))
+	f.productID = product.ID
+	variants, err := store.GetVariantsByProductID(ctx, product.ID)
+	require.NoError(t, err)
+	require.Len(t, variants, 1)
+	f.variantID = variants[0].ID
+
+	for code, quantity := range stock {
+		_, err := warehouses.SetWarehouseStock(ctx, f.warehouses[code], f.variantID, quantity)
+		require.NoError(t, err)
+	}
+	return f
+}
+
+// levels возвращает остатки варианта по кодам складов.
+func (f *stockFixture) levels(t *testing.T) map[string]int {
+	t.Helper()
+	levels, err := f.store.GetStockLevelsByVariant(context.Background(), f.variantID)
+	require.NoError(t, err)
+	result := map[string]int{}
+	for code, id := range f.warehouses {
+		for _, l := range levels {
+			if l.WarehouseID == id && l.Quantity > 0 {
+				result[code] = l.Quantity
+			}
+		}
+	}
+	return result
+}
+
+func (f *stockFixture) orderService(strategy models.AllocationStrategy) OrderService {
+	return NewOrderService(f.store, strategy, tax.NewTable(nil, false), shipping.NewTable(nil), nil)
+}
+
+func TestOrderService_AllocatesAcrossWarehouses(t *testing.T) {
+	stock := map[string]int{"MAIN": 3, "EAST": 4, "WEST": 5}
+	paris := &models.Address{Line1: "1 Rue de Rivoli", City: "Paris", PostalCode: "75001", Country: "FR"}
+
+	tests := []struct {
+		name      string
+		strategy  models.AllocationStrategy
+		quantity  int
+		address   *models.Address
+		inactive  string
+		wantAlloc map[string]int
+		wantErr   string
+	}{
+		{name: "single warehouse", strategy: models.AllocationPriority, quantity: 2, wantAlloc: map[string]int{"MAIN": 2}},
+		{name: "split by priority", strategy: models.AllocationPriority, quantity: 6, wantAlloc: map[string]int{"MAIN": 3, "EAST": 3}},
+		{name: "all warehouses", strategy: models.AllocationPriority, quantity: 12, wantAlloc: map[string]int{"MAIN": 3, "EAST": 4, "WEST": 5}},
+		{name: "nearest first", strategy: models.AllocationNearest, quantity: 6, address: paris, wantAlloc: map[string]int{"WEST": 5, "MAIN": 1}},
+		{name: "nearest without address uses priority", strategy: models.AllocationNearest, quantity: 4, wantAlloc: map[string]int{"MAIN": 3, "EAST": 1}},
+		{name: "inactive warehouse skipped", strategy: models.AllocationPriority, quantity: 6, inactive: "EAST", wantAlloc: map[string]int{"MAIN": 3, "WEST": 3}},
+		{name: "insufficient stock", strategy: models.AllocationPriority, quantity: 13, wantErr: "insufficient quantity"},
+		{name: "insufficient in active warehouses", strategy: models.AllocationPriority, quantity: 10, inactive: "WEST", wantErr: "insufficient quantity"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+			f := newStockFixture(t, stock)
+			if tt.inactive != "" {
+				warehouse, err := f.store.GetWarehouseByID(ctx, f.warehouses[tt.inactive])
+				require.NoError(t, err)
+				warehouse.Active = false
+				require.NoError(t, f.store.UpdateWarehouse(ctx, warehouse))
+			}
+			order := &models.Order{
+				CustomerID:      f.customerID,
+				ShippingAddress: tt.address,
+				Products:        []models.OrderItem{{ProductID: f.productID, Quantity: tt.quantity}},
+			}
+
+			// Act
+			err := f.orderService(tt.strategy).CreateOrder(ctx, order)
+
+			// Assert
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				assert.Equal(t, stock, f.levels(t))
+				return
+			}
+			require.NoError(t, err)
+
+			allocations, err := f.store.GetAllocationsByOrder(ctx, order.ID)
+			require.NoError(t, err)
+			got := map[string]int{}
+			for _, a := range allocations {
+				assert.Equal(t, f.variantID, a.VariantID)
+				for code, id := range f.warehouses {
+					if a.WarehouseID == id {
+						got[code] += a.Quantity
+					}
+				}
+			}
+			assert.Equal(t, tt.wantAlloc, got)
+
+			want := map[string]int{}
+			for code, quantity := range stock {
+				if left := quantity - tt.wantAlloc[code]; left > 0 {
+					want[code] = left
+				}
+			}
+			assert.Equal(t, want, f.levels(t))
+		})
+	}
+}
+
+func TestOrderService_DeleteOrderReturnsStockToWarehouses(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	stock := map[string]int{"MAIN": 3, "EAST": 4}
+	f := newStockFixture(t, stock)
+	orders := f.orderService(models.AllocationPriority)
+	order := &models.Order{CustomerID: f.customerID, Products: []models.OrderItem{{ProductID: f.productID, Quantity: 5}}}
+	require.NoError(t, orders.CreateOrder(ctx, order))
+	require.Equal(t, map[string]int{"EAST": 2}, f.levels(t))
+
+	// Act
+	err := orders.DeleteOrder(ctx, order.ID)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, stock, f.levels(t))
+}
+
+func TestWarehouseService_StaleTransferTransitionConflicts(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	f := newStockFixture(t, map[string]int{"MAIN": 5})
+	warehouses := NewWarehouseService(f.store, nil)
+	transfer := &models.StockTransfer{
+		FromWarehouseID: f.warehouses["MAIN"],
+		ToWarehouseID:   f.warehouses["EAST"],
+		Items:           []models.TransferItem{{VariantID: f.variantID, Quantity: 2}},
+	}
+	require.NoError(t, warehouses.CreateTransfer(ctx, transfer))
+	stale := *transfer
+	_, err := warehouses.ShipTransfer(ctx, transfer.ID)
+	require.NoError(t, err)
+
+	// Act
+	stale.Status = models.TransferInTransit
+	err = f.store.UpdateTransfer(ctx, &stale, models.TransferPending)
+
+	// Assert
+	assert.ErrorContains(t, err, "cannot change status of transfer")
+	_, err = warehouses.ShipTransfer(ctx, transfer.ID)
+	assert.ErrorContains(t, err, "cannot")
+	assert.Equal(t, map[string]int{"MAIN": 3}, f.levels(t))
+}
+
+// countingObserver считает уведомления об изменении остатков.
+type countingObserver struct {
+	calls int
+}
+
+func (o *countingObserver) StockChanged() {
+	o.calls++
+}
+
+func TestProductService_DeleteProductNotifiesObserver(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	f := newStockFixture(t, map[string]int{"MAIN": 3, "EAST": 2})
+	observer := &countingObserver{}
+
+	// Act
+	err := NewProductService(f.store, observer).DeleteProduct(ctx, f.productID)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 1, observer.calls)
+	assert.Empty(t, f.levels(t))
+}
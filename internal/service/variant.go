@@ -0,0 +1,378 @@
+package service
+
+import (
+	"backend-store/internal/models"
+	"backend-store/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+func (s *productService) GetVariants(ctx context.Context, productID int) ([]*models.ProductVariant, error) {
+	if productID <= 0 {
+		return nil, errors.New("invalid product ID")
+	}
+
+	tx, err := s.storage.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.GetProductByID(ctx, productID); err != nil {
+		return nil, fmt.Errorf("product not found: %w", err)
+	}
+
+	variants, err := tx.GetVariantsByProductID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get variants: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return variants, nil
+}
+
+func (s *productService) CreateVariant(ctx context.Context, variant *models.ProductVariant) error {
+	if err := variant.Validate(); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	variant.CreatedAt = now
+	variant.UpdatedAt = now
+
+	tx, err := s.storage.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	product, err := tx.GetProductByID(ctx, variant.ProductID)
+	if err != nil {
+		return fmt.Errorf("product not found: %w", err)
+	}
+
+	variants, err := tx.GetVariantsByProductID(ctx, product.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get variants: %w", err)
+	}
+	if err := checkVariant(product, variants, variant); err != nil {
+		return err
+	}
+
+	if err := tx.CreateVariant(ctx, variant); err != nil {
+		return fmt.Errorf("failed to create variant: %w", err)
+	}
+	if err := recordAudit(ctx, tx, models.AuditCreate, models.AuditEntityVariant, variant.ID, nil, variant); err != nil {
+		return err
+	}
+
+	if err := setVariantStock(ctx, tx, variant.ID, variant.Quantity,
+		models.MovementAdjustment, fmt.Sprintf("variant:%d", variant.ID)); err != nil {
+		return err
+	}
+
+	if err := refreshProductStock(ctx, tx, product); err != nil {
+		return err
+	}
+
+	return commitStockChange(tx, s.observer)
+}
+
+func (s *productService) UpdateVariant(ctx context.Context, variant *models.ProductVariant) error {
+	if err := variant.Validate(); err != nil {
+		return err
+	}
+
+	variant.UpdatedAt = time.Now()
+
+	tx, err := s.storage.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	existing, err := tx.GetVariantByID(ctx, variant.ID)
+	if err != nil || existing.ProductID != variant.ProductID {
+		return errors.New("variant not found")
+	}
+	variant.CreatedAt = existing.CreatedAt
+
+	product, err := tx.GetProductByID(ctx, variant.ProductID)
+	if err != nil {
+		return fmt.Errorf("product not found: %w", err)
+	}
+
+	variants, err := tx.GetVariantsByProductID(ctx, product.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get variants: %w", err)
+	}
+	if err := checkVariant(product, variants, variant); err != nil {
+		return err
+	}
+
+	if err := tx.UpdateVariant(ctx, variant); err != nil {
+		return fmt.Errorf("failed to update variant: %w", err)
+	}
+	if err := recordAudit(ctx, tx, models.AuditUpdate, models.AuditEntityVariant, variant.ID, existing, variant); err != nil {
+		return err
+	}
+	if existing.Price != variant.Price {
+		err := recordEvent(ctx, tx, models.EventProductPriceChanged, models.AggregateProduct, product.ID, models.ProductPriceChangedPayload{
+			ProductID: product.ID,
+			VariantID: variant.ID,
+			SKU:       variant.SKU,
+			OldPrice:  existing.Price,
+			NewPrice:  variant.Price,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := setVariantStock(ctx, tx, variant.ID, variant.Quantity,
+		models.MovementAdjustment, fmt.Sprintf("variant:%d", variant.ID)); err != nil {
+		return err
+	}
+
+	if err := refreshProductStock(ctx, tx, product); err != nil {
+		return err
+	}
+
+	return commitStockChange(tx, s.observer)
+}
+
+func (s *productService) DeleteVariant(ctx context.Context, productID, variantID int) error {
+	if productID <= 0 || variantID <= 0 {
+		return errors.New("invalid variant ID")
+	}
+
+	tx, err := s.storage.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	existing, err := tx.GetVariantByID(ctx, variantID)
+	if err != nil || existing.ProductID != productID {
+		return errors.New("variant not found")
+	}
+
+	variants, err := tx.GetVariantsByProductID(ctx, productID)
+	if err != nil {
+		return fmt.Errorf("failed to get variants: %w", err)
+	}
+	if len(variants) == 1 {
+		return errors.New("cannot delete the last variant of a product")
+	}
+
+	if err := clearVariantStock(ctx, tx, variantID); err != nil {
+		return err
+	}
+
+	if err := tx.DeleteVariant(ctx, variantID); err != nil {
+		return fmt.Errorf("failed to delete variant: %w", err)
+	}
+	if err := recordAudit(ctx, tx, models.AuditDelete, models.AuditEntityVariant, variantID, existing, nil); err != nil {
+		return err
+	}
+
+	product, err := tx.GetProductByID(ctx, productID)
+	if err != nil {
+		return fmt.Errorf("product not found: %w", err)
+	}
+	if err := refreshProductStock(ctx, tx, product); err != nil {
+		return err
+	}
+
+	return commitStockChange(tx, s.observer)
+}
+
+// checkVariant проверяет вариант относительно осей товара и остальных
+// вариантов: комбинация опций должна быть уникальной в пределах товара.
+func checkVariant(product *models.Product, variants []*models.ProductVariant, variant *models.ProductVariant) error {
+	if len(product.Options) == 0 {
+		if !variant.IsDefault() {
+			return errors.New("validate: product has no options; add options to the product first")
+		}
+	} else if err := product.Options.ValidateVariant(variant); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+
+	key := variant.Options.Key()
+	for _, v := range variants {
+		if v.ID != variant.ID && v.Options.Key() == key {
+			return fmt.Errorf("variant with options %q already exists", key)
+		}
+	}
+	return nil
+}
+
+// createVariants создает варианты нового товара. Товар без вариантов в
+// запросе получает один вариант по умолчанию с его SKU, ценой и остатком.
+func createVariants(ctx context.Context, tx storage.StorageTx, product *models.Product, reason string) error {
+	if len(product.Variants) == 0 {
+		product.Variants = []models.ProductVariant{defaultVariant(product)}
+	}
+
+	created := make([]*models.ProductVariant, 0, len(product.Variants))
+	for i := range product.Variants {
+		variant := &product.Variants[i]
+		variant.ID = 0
+		variant.ProductID = product.ID
+		variant.CreatedAt = product.CreatedAt
+		variant.UpdatedAt = product.UpdatedAt
+
+		if err := variant.Validate(); err != nil {
+			return err
+		}
+		if err := checkVariant(product, created, variant); err != nil {
+			return err
+		}
+		if err := tx.CreateVariant(ctx, variant); err != nil {
+			return fmt.Errorf("failed to create variant: %w", err)
+		}
+		if err := setVariantStock(ctx, tx, variant.ID, variant.Quantity, reason, productReference(product)); err != nil {
+			return err
+		}
+		created = append(created, variant)
+	}
+
+	return refreshProductStock(ctx, tx, product)
+}
+
+// syncVariants вызывается после обновления товара. Если у товара единственный
+// вариант по умолчанию, он повторяет SKU, цену и остаток товара; иначе цена
+// и остаток товара вычисляются по вариантам.
+func syncVariants(ctx context.Context, tx storage.StorageTx, product *models.Product, reason string) error {
+	variants, err := tx.GetVariantsByProductID(ctx, product.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get variants: %w", err)
+	}
+
+	if len(variants) == 0 {
+		variant := defaultVariant(product)
+		variant.ProductID = product.ID
+		variant.CreatedAt = product.UpdatedAt
+		variant.UpdatedAt = product.UpdatedAt
+		if err := tx.CreateVariant(ctx, &variant); err != nil {
+			return fmt.Errorf("failed to create variant: %w", err)
+		}
+		return setVariantStock(ctx, tx, variant.ID, variant.Quantity, reason, productReference(product))
+	}
+
+	if len(variants) == 1 && variants[0].IsDefault() {
+		variant := *variants[0]
+		variant.SKU = product.SKU
+		variant.Price = product.Price
+		variant.Quantity = product.Quantity
+		variant.UpdatedAt = product.UpdatedAt
+		if err := tx.UpdateVariant(ctx, &variant); err != nil {
+			return fmt.Errorf("failed to update variant: %w", err)
+		}
+		return setVariantStock(ctx, tx, variant.ID, variant.Quantity, reason, productReference(product))
+	}
+
+	return refreshProductStock(ctx, tx, product)
+}
+
+func productReference(product *models.Product) string {
+	return fmt.Sprintf("product:%d", product.ID)
+}
+
+func defaultVariant(product *models.Product) models.ProductVariant {
+	return models.ProductVariant{
+		ProductID: product.ID,
+		SKU:       product.SKU,
+		Options:   models.VariantOptions{},
+		Price:     product.Price,
+		Quantity:  product.Quantity,
+	}
+}
+
+// refreshProductStock пересчитывает остаток товара как сумму остатков
+// вариантов, а цену - как минимальную цену варианта ("от").
+func refreshProductStock(ctx context.Context, tx storage.StorageTx, product *models.Product) error {
+	variants, err := tx.GetVariantsByProductID(ctx, product.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get variants: %w", err)
+	}
+	if len(variants) == 0 {
+		return nil
+	}
+
+	quantity := 0
+	price := variants[0].Price
+	for _, v := range variants {
+		quantity += v.Quantity
+		if v.Price < price {
+			price = v.Price
+		}
+	}
+	if product.Quantity == quantity && product.Price == price {
+		return nil
+	}
+
+	updated := *product
+	updated.Quantity = quantity
+	updated.Price = price
+	updated.Variants = nil
+	if err := tx.UpdateProduct(ctx, &updated); err != nil {
+		return fmt.Errorf("failed to update product stock: %w", err)
+	}
+	product.Quantity = quantity
+	product.Price = price
+	return nil
+}
+
+// resolveOrderItem находит вариант позиции заказа, проставляет его цену и
+// налоговый класс товара и возвращает доступный остаток. Если вариант не
+// указан, подставляется единственный вариант товара.
+func resolveOrderItem(ctx context.Context, tx storage.StorageTx, item *models.OrderItem) (int, error) {
+	var variant *models.ProductVariant
+	if item.VariantID > 0 {
+		var err error
+		variant, err = tx.GetVariantByID(ctx, item.VariantID)
+		if err != nil {
+			return 0, fmt.Errorf("product variant %d not found: %w", item.VariantID, err)
+		}
+		if item.ProductID == 0 {
+			item.ProductID = variant.ProductID
+		}
+		if variant.ProductID != item.ProductID {
+			return 0, fmt.Errorf("validate: variant %d does not belong to product %d", item.VariantID, item.ProductID)
+		}
+	}
+
+	product, err := tx.GetProductByID(ctx, item.ProductID)
+	if err != nil {
+		return 0, fmt.Errorf("product %d not found: %w", item.ProductID, err)
+	}
+	item.TaxClass = product.TaxClass
+
+	if variant == nil {
+		variants, err := tx.GetVariantsByProductID(ctx, item.ProductID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get variants: %w", err)
+		}
+		switch len(variants) {
+		case 0:
+			item.Price = int(math.Round(product.Price))
+			return product.Quantity, nil
+		case 1:
+			variant = variants[0]
+			item.VariantID = variant.ID
+		default:
+			return 0, fmt.Errorf("validate: variant is required for product %d", item.ProductID)
+		}
+	}
+
+	item.Price = int(math.Round(variant.Price))
+	item.Cost = variant.Cost
+	return variant.Quantity, nil
+}
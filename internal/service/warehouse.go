@@ -0,0 +1,349 @@
+package service
+
+import (
+	"backend-store/internal/models"
+	"backend-store/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type warehouseService struct {
+	storage  storage.Storage
+	observer StockObserver
+}
+
+func NewWarehouseService(storage storage.Storage, observer StockObserver) WarehouseService {
+	return &warehouseService{storage: storage, observer: observer}
+}
+
+func (s *warehouseService) CreateWarehouse(ctx context.Context, warehouse *models.Warehouse) error {
+	warehouse.Code = strings.ToUpper(strings.TrimSpace(warehouse.Code))
+	if err := warehouse.Validate(); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	warehouse.CreatedAt = now
+	warehouse.UpdatedAt = now
+
+	tx, err := s.storage.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := tx.CreateWarehouse(ctx, warehouse); err != nil {
+		return fmt.Errorf("failed to create warehouse: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func (s *warehouseService) GetAllWarehouses(ctx context.Context) ([]*models.Warehouse, error) {
+	warehouses, err := s.storage.GetAllWarehouses(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get warehouses: %w", err)
+	}
+	return warehouses, nil
+}
+
+func (s *warehouseService) GetWarehouseByID(ctx context.Context, id int) (*models.Warehouse, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid warehouse ID")
+	}
+	return s.storage.GetWarehouseByID(ctx, id)
+}
+
+func (s *warehouseService) UpdateWarehouse(ctx context.Context, warehouse *models.Warehouse) error {
+	warehouse.Code = strings.ToUpper(strings.TrimSpace(warehouse.Code))
+	if err := warehouse.Validate(); err != nil {
+		return err
+	}
+
+	warehouse.UpdatedAt = time.Now()
+
+	tx, err := s.storage.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	existing, err := tx.GetWarehouseByID(ctx, warehouse.ID)
+	if err != nil {
+		return err
+	}
+	warehouse.CreatedAt = existing.CreatedAt
+
+	if existing.Active && !warehouse.Active {
+		if err := checkOtherActiveWarehouse(ctx, tx, warehouse.ID); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.UpdateWarehouse(ctx, warehouse); err != nil {
+		return fmt.Errorf("failed to update warehouse: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// DeleteWarehouse удаляет пустой склад. Склад, на который ссылаются заказы
+// или перемещения, можно только деактивировать.
+func (s *warehouseService) DeleteWarehouse(ctx context.Context, id int) error {
+	if id <= 0 {
+		return errors.New("invalid warehouse ID")
+	}
+
+	tx, err := s.storage.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	warehouse, err := tx.GetWarehouseByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	levels, err := tx.GetStockLevelsByWarehouse(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get stock levels: %w", err)
+	}
+	for _, l := range levels {
+		if l.Quantity > 0 {
+			return errors.New("cannot delete warehouse with stock on hand: transfer it first")
+		}
+	}
+
+	if warehouse.Active {
+		if err := checkOtherActiveWarehouse(ctx, tx, id); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.DeleteWarehouse(ctx, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// checkOtherActiveWarehouse не дает отключить последний активный склад:
+// без него некуда принимать остатки и не с чего отгружать заказы.
+func checkOtherActiveWarehouse(ctx context.Context, tx storage.StorageTx, id int) error {
+	warehouses, err := tx.GetAllWarehouses(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get warehouses: %w", err)
+	}
+	for _, w := range warehouses {
+		if w.Active && w.ID != id {
+			return nil
+		}
+	}
+	return errors.New("cannot deactivate the last active warehouse")
+}
+
+func (s *warehouseService) GetWarehouseStock(ctx context.Context, id int) ([]models.StockLevel, error) {
+	if _, err := s.GetWarehouseByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	levels, err := s.storage.GetStockLevelsByWarehouse(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stock levels: %w", err)
+	}
+	return levels, nil
+}
+
+// SetWarehouseStock задает остаток варианта на складе (инвентаризация).
+func (s *warehouseService) SetWarehouseStock(ctx context.Context, warehouseID, variantID, quantity int) (*models.StockLevel, error) {
+	if quantity < 0 {
+		return nil, errors.New("validate: quantity cannot be negative")
+	}
+
+	tx, err := s.storage.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.GetWarehouseByID(ctx, warehouseID); err != nil {
+		return nil, err
+	}
+	if _, err := tx.GetVariantByID(ctx, variantID); err != nil {
+		return nil, fmt.Errorf("product variant %d not found", variantID)
+	}
+
+	current, err := stockLevel(ctx, tx, warehouseID, variantID)
+	if err != nil {
+		return nil, err
+	}
+	if err := adjustStock(ctx, tx, warehouseID, variantID, quantity-current.Quantity, models.MovementStocktake, ""); err != nil {
+		return nil, err
+	}
+
+	level, err := stockLevel(ctx, tx, warehouseID, variantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := commitStockChange(tx, s.observer); err != nil {
+		return nil, err
+	}
+
+	return level, nil
+}
+
+func stockLevel(ctx context.Context, tx storage.StorageTx, warehouseID, variantID int) (*models.StockLevel, error) {
+	levels, err := tx.GetStockLevelsByVariant(ctx, variantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stock levels: %w", err)
+	}
+	for _, l := range levels {
+		if l.WarehouseID == warehouseID {
+			return &l, nil
+		}
+	}
+	return &models.StockLevel{WarehouseID: warehouseID, VariantID: variantID}, nil
+}
+
+func (s *warehouseService) CreateTransfer(ctx context.Context, transfer *models.StockTransfer) error {
+	if err := transfer.Validate(); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+
+	now := time.Now()
+	transfer.Status = models.TransferPending
+	transfer.CreatedAt = now
+	transfer.UpdatedAt = now
+	transfer.ShippedAt = nil
+	transfer.ReceivedAt = nil
+
+	tx, err := s.storage.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, id := range []int{transfer.FromWarehouseID, transfer.ToWarehouseID} {
+		if _, err := tx.GetWarehouseByID(ctx, id); err != nil {
+			return fmt.Errorf("validate: warehouse %d not found", id)
+		}
+	}
+	for _, item := range transfer.Items {
+		if _, err := tx.GetVariantByID(ctx, item.VariantID); err != nil {
+			return fmt.Errorf("validate: product variant %d not found", item.VariantID)
+		}
+	}
+
+	if err := tx.CreateTransfer(ctx, transfer); err != nil {
+		return fmt.Errorf("failed to create transfer: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func (s *warehouseService) GetAllTransfers(ctx context.Context, status string) ([]*models.StockTransfer, error) {
+	transfers, err := s.storage.GetAllTransfers(ctx, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transfers: %w", err)
+	}
+	return transfers, nil
+}
+
+func (s *warehouseService) GetTransferByID(ctx context.Context, id int) (*models.StockTransfer, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid transfer ID")
+	}
+	return s.storage.GetTransferByID(ctx, id)
+}
+
+// ShipTransfer списывает товар со склада-источника; до получения он
+// учитывается как находящийся в пути.
+func (s *warehouseService) ShipTransfer(ctx context.Context, id int) (*models.StockTransfer, error) {
+	return s.transition(ctx, id, models.TransferInTransit, func(tx storage.StorageTx, t *models.StockTransfer) error {
+		for _, item := range t.Items {
+			if err := adjustStock(ctx, tx, t.FromWarehouseID, item.VariantID, -item.Quantity, models.MovementTransfer, transferReference(t)); err != nil {
+				return err
+			}
+		}
+		now := time.Now()
+		t.ShippedAt = &now
+		return nil
+	})
+}
+
+func (s *warehouseService) ReceiveTransfer(ctx context.Context, id int) (*models.StockTransfer, error) {
+	return s.transition(ctx, id, models.TransferReceived, func(tx storage.StorageTx, t *models.StockTransfer) error {
+		for _, item := range t.Items {
+			if err := adjustStock(ctx, tx, t.ToWarehouseID, item.VariantID, item.Quantity, models.MovementTransfer, transferReference(t)); err != nil {
+				return err
+			}
+		}
+		now := time.Now()
+		t.ReceivedAt = &now
+		return nil
+	})
+}
+
+// CancelTransfer отменяет перемещение; отгруженный товар возвращается на
+// склад-источник.
+func (s *warehouseService) CancelTransfer(ctx context.Context, id int) (*models.StockTransfer, error) {
+	return s.transition(ctx, id, models.TransferCancelled, func(tx storage.StorageTx, t *models.StockTransfer) error {
+		if t.Status != models.TransferInTransit {
+			return nil
+		}
+		for _, item := range t.Items {
+			if err := adjustStock(ctx, tx, t.FromWarehouseID, item.VariantID, item.Quantity, models.MovementTransfer, transferReference(t)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// transition переводит перемещение в статус status, выполняя apply в той же
+// транзакции до смены статуса.
+func (s *warehouseService) transition(ctx context.Context, id int, status string, apply func(storage.StorageTx, *models.StockTransfer) error) (*models.StockTransfer, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid transfer ID")
+	}
+
+	tx, err := s.storage.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	existing, err := tx.GetTransferByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !existing.CanTransition(status) {
+		return nil, fmt.Errorf("cannot change transfer status from %s to %s", existing.Status, status)
+	}
+
+	transfer := *existing
+	if err := apply(tx, &transfer); err != nil {
+		return nil, err
+	}
+	transfer.Status = status
+	transfer.UpdatedAt = time.Now()
+
+	if err := tx.UpdateTransfer(ctx, &transfer, existing.Status); err != nil {
+		return nil, fmt.Errorf("failed to update transfer: %w", err)
+	}
+
+	if err := commitStockChange(tx, s.observer); err != nil {
+		return nil, err
+	}
+
+	return &transfer, nil
+}
+
+func transferReference(t *models.StockTransfer) string {
+	return fmt.Sprintf("transfer:%d", t.ID)
+}
@@ -0,0 +1,420 @@
+package service
+
+import (
+	"backend-store/internal/models"
+	"backend-store/internal/notify"
+	"backend-store/internal/storage"
+	"backend-store/pkg/logger"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	defaultWebhookInterval     = time.Second
+	defaultWebhookMaxAttempts  = 8
+	defaultWebhookDisableAfter = 20
+	webhookBatchSize           = 50
+	// webhookLease - время на отправку выбранной пачки доставок. Новая
+	// доставка пачки начинается, только пока до конца аренды остается не
+	// меньше двух таймаутов отправителя, иначе ее выдали бы повторно и
+	// подписчик получил бы ее дважды.
+	webhookLease = time.Minute
+)
+
+// ValidateWebhookTimeout проверяет, что в аренде пачки при таймауте
+// отправителя timeout остается время на доставку.
+func ValidateWebhookTimeout(timeout time.Duration) error {
+	if timeout <= 0 || 2*timeout >= webhookLease {
+		return fmt.Errorf("webhook timeout must be positive and less than %s", webhookLease/2)
+	}
+	return nil
+}
+
+// WebhookWorker раскладывает доменные события по доставкам подписанным
+// вебхукам и отправляет их. Как обработчик OutboxDispatcher он только
+// записывает доставки, поэтому медленный получатель не задерживает
+// остальных; отправка и повторы с растущим интервалом выполняются в Run.
+type WebhookWorker struct {
+	storage      storage.Storage
+	sender       *notify.WebhookSender
+	interval     time.Duration
+	maxAttempts  int
+	disableAfter int
+	log          logger.Log
+	trigger      chan struct{}
+}
+
+// NewWebhookWorker создает отправителя вебхуков. Доставка прекращается
+// после maxAttempts неудачных попыток, а вебхук отключается после
+// disableAfter неудачных попыток подряд по всем его доставкам.
+func NewWebhookWorker(storage storage.Storage, sender *notify.WebhookSender, interval time.Duration, maxAttempts, disableAfter int, log logger.Log) *WebhookWorker {
+	if interval <= 0 {
+		interval = defaultWebhookInterval
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = defaultWebhookMaxAttempts
+	}
+	if disableAfter <= 0 {
+		disableAfter = defaultWebhookDisableAfter
+	}
+	return &WebhookWorker{
+		storage:      storage,
+		sender:       sender,
+		interval:     interval,
+		maxAttempts:  maxAttempts,
+		disableAfter: disableAfter,
+		log:          log,
+		trigger:      make(chan struct{}, 1),
+	}
+}
+
+// HandleEvent создает доставки события всем активным вебхукам, подписанным
+// на его тип. Повторная обработка того же события доставки не дублирует.
+func (w *WebhookWorker) HandleEvent(ctx context.Context, event models.OutboxEvent) error {
+	tx, err := w.storage.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	webhooks, err := tx.GetWebhooks(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get webhooks: %w", err)
+	}
+
+	created := 0
+	for i := range webhooks {
+		webhook := &webhooks[i]
+		if !webhook.Active || !webhook.Subscribes(event.Type) {
+			continue
+		}
+		existing, err := tx.GetWebhookDeliveries(ctx, models.WebhookDeliveryFilter{WebhookID: webhook.ID, EventID: event.ID, Limit: 1})
+		if err != nil {
+			return fmt.Errorf("failed to get webhook deliveries: %w", err)
+		}
+		if len(existing) > 0 {
+			continue
+		}
+
+		delivery, err := models.NewWebhookDelivery(webhook.ID, event)
+		if err != nil {
+			return err
+		}
+		if err := tx.CreateWebhookDelivery(ctx, delivery); err != nil {
+			return fmt.Errorf("failed to create webhook delivery: %w", err)
+		}
+		created++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	if created > 0 {
+		w.Wake()
+	}
+	return nil
+}
+
+// Wake ставит внеочередной проход, не дожидаясь интервала опроса.
+func (w *WebhookWorker) Wake() {
+	wake(w.trigger)
+}
+
+// Run отправляет доставки до отмены ctx.
+func (w *WebhookWorker) Run(ctx context.Context) {
+	poll(ctx, w.interval, w.trigger, func() {
+		if _, err := w.Deliver(ctx); err != nil && ctx.Err() == nil {
+			w.log.Error(fmt.Errorf("webhook delivery failed: %w", err))
+		}
+	})
+}
+
+// Deliver отправляет все доставки, срок попытки которых наступил, и
+// возвращает число отправленных.
+func (w *WebhookWorker) Deliver(ctx context.Context) (int, error) {
+	processed := 0
+	for {
+		claimedAt := time.Now()
+		deliveries, err := w.storage.ClaimWebhookDeliveries(ctx, claimedAt, webhookLease, webhookBatchSize)
+		if err != nil {
+			return processed, err
+		}
+		// Оставшиеся доставки пачки выдаются снова после окончания аренды.
+		deadline := claimedAt.Add(webhookLease - 2*w.sender.Timeout())
+		for i := range deliveries {
+			if time.Now().After(deadline) {
+				return processed, nil
+			}
+			if err := w.send(ctx, &deliveries[i]); err != nil {
+				return processed, err
+			}
+			processed++
+		}
+		if len(deliveries) < webhookBatchSize {
+			return processed, nil
+		}
+	}
+}
+
+func (w *WebhookWorker) send(ctx context.Context, delivery *models.WebhookDelivery) error {
+	webhook, err := w.storage.GetWebhookByID(ctx, delivery.WebhookID)
+	if err != nil {
+		return fmt.Errorf("failed to get webhook %d: %w", delivery.WebhookID, err)
+	}
+	if !webhook.Active {
+		delivery.Abandon("webhook disabled")
+		if err := w.storage.UpdateWebhookDelivery(ctx, delivery); err != nil {
+			return fmt.Errorf("failed to update webhook delivery %d: %w", delivery.ID, err)
+		}
+		return nil
+	}
+
+	start := time.Now()
+	resp, sendErr := w.sender.Send(ctx, notify.SignedMessage{
+		URL:        webhook.URL,
+		Secret:     webhook.Secret,
+		EventType:  delivery.EventType,
+		DeliveryID: delivery.ID,
+		Body:       delivery.Payload,
+	})
+	now := time.Now()
+	delivery.Attempted(resp.StatusCode, resp.Body, now.Sub(start), sendErr, now, w.maxAttempts)
+
+	return w.record(ctx, delivery, sendErr, now)
+}
+
+// record сохраняет результат попытки и счетчик неудач вебхука в одной
+// транзакции.
+func (w *WebhookWorker) record(ctx context.Context, delivery *models.WebhookDelivery, sendErr error, now time.Time) error {
+	tx, err := w.storage.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := tx.UpdateWebhookDelivery(ctx, delivery); err != nil {
+		return fmt.Errorf("failed to update webhook delivery %d: %w", delivery.ID, err)
+	}
+
+	webhook, err := tx.GetWebhookByID(ctx, delivery.WebhookID)
+	if err != nil {
+		return fmt.Errorf("failed to get webhook %d: %w", delivery.WebhookID, err)
+	}
+	if sendErr == nil {
+		if webhook.ConsecutiveFailures == 0 {
+			return tx.Commit()
+		}
+		webhook.RecordSuccess()
+	} else if webhook.RecordFailure(sendErr.Error(), now, w.disableAfter) {
+		webhook.UpdatedAt = now
+		w.log.Warn("Webhook %d disabled after %d consecutive failed deliveries", webhook.ID, webhook.ConsecutiveFailures)
+	}
+	if err := tx.UpdateWebhook(ctx, webhook); err != nil {
+		return fmt.Errorf("failed to update webhook %d: %w", webhook.ID, err)
+	}
+
+	return tx.Commit()
+}
+
+type webhookService struct {
+	storage storage.Storage
+	worker  *WebhookWorker
+}
+
+func NewWebhookService(storage storage.Storage, worker *WebhookWorker) WebhookService {
+	return &webhookService{storage: storage, worker: worker}
+}
+
+// CreateWebhook создает активный вебхук. Без секрета он генерируется;
+// секрет возвращается только при создании и изменении.
+func (s *webhookService) CreateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	if webhook.Secret == "" {
+		secret, err := generateWebhookSecret()
+		if err != nil {
+			return err
+		}
+		webhook.Secret = secret
+	}
+	if err := webhook.Validate(); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+
+	now := time.Now()
+	webhook.Enable()
+	webhook.CreatedAt = now
+	webhook.UpdatedAt = now
+
+	if err := s.storage.CreateWebhook(ctx, webhook); err != nil {
+		return fmt.Errorf("failed to create webhook: %w", err)
+	}
+	return nil
+}
+
+func (s *webhookService) GetWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	webhooks, err := s.storage.GetWebhooks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhooks: %w", err)
+	}
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+	return webhooks, nil
+}
+
+func (s *webhookService) GetWebhookByID(ctx context.Context, id int) (*models.Webhook, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid webhook ID")
+	}
+	webhook, err := s.storage.GetWebhookByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	webhook.Secret = ""
+	return webhook, nil
+}
+
+// UpdateWebhook заменяет URL, типы событий и состояние вебхука. Пустой
+// секрет оставляет прежний. Включение отключенного вебхука сбрасывает
+// счетчик неудач; доставки, прекращенные на время отключения, можно
+// отправить повторно.
+func (s *webhookService) UpdateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	tx, err := s.storage.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	existing, err := tx.GetWebhookByID(ctx, webhook.ID)
+	if err != nil {
+		return err
+	}
+
+	keepSecret := webhook.Secret == ""
+	if keepSecret {
+		webhook.Secret = existing.Secret
+	}
+	if err := webhook.Validate(); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+
+	now := time.Now()
+	switch {
+	case webhook.Active && !existing.Active:
+		webhook.Enable()
+	case webhook.Active:
+		webhook.ConsecutiveFailures = existing.ConsecutiveFailures
+	case existing.Active:
+		webhook.ConsecutiveFailures = existing.ConsecutiveFailures
+		webhook.DisabledReason = "disabled manually"
+		webhook.DisabledAt = &now
+	default:
+		webhook.ConsecutiveFailures = existing.ConsecutiveFailures
+		webhook.DisabledReason = existing.DisabledReason
+		webhook.DisabledAt = existing.DisabledAt
+	}
+	webhook.CreatedAt = existing.CreatedAt
+	webhook.UpdatedAt = now
+
+	if err := tx.UpdateWebhook(ctx, webhook); err != nil {
+		return fmt.Errorf("failed to update webhook: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	if keepSecret {
+		webhook.Secret = ""
+	}
+	return nil
+}
+
+func (s *webhookService) DeleteWebhook(ctx context.Context, id int) error {
+	if id <= 0 {
+		return errors.New("invalid webhook ID")
+	}
+	if err := s.storage.DeleteWebhook(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	return nil
+}
+
+func (s *webhookService) GetWebhookDeliveries(ctx context.Context, filter models.WebhookDeliveryFilter) ([]models.WebhookDelivery, error) {
+	switch filter.Status {
+	case "", models.WebhookDeliveryPending, models.WebhookDeliverySucceeded, models.WebhookDeliveryFailed:
+	default:
+		return nil, fmt.Errorf("validate: invalid status %q", filter.Status)
+	}
+	if filter.Limit <= 0 || filter.Limit > 500 {
+		filter.Limit = 100
+	}
+
+	if filter.WebhookID != 0 {
+		if _, err := s.storage.GetWebhookByID(ctx, filter.WebhookID); err != nil {
+			return nil, err
+		}
+	}
+
+	deliveries, err := s.storage.GetWebhookDeliveries(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+func (s *webhookService) GetWebhookDeliveryByID(ctx context.Context, id int) (*models.WebhookDelivery, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid webhook delivery ID")
+	}
+	return s.storage.GetWebhookDeliveryByID(ctx, id)
+}
+
+// RedeliverWebhook ставит в очередь новую доставку того же тела. Исходная
+// запись журнала не меняется.
+func (s *webhookService) RedeliverWebhook(ctx context.Context, deliveryID int) (*models.WebhookDelivery, error) {
+	if deliveryID <= 0 {
+		return nil, errors.New("invalid webhook delivery ID")
+	}
+
+	tx, err := s.storage.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	original, err := tx.GetWebhookDeliveryByID(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	webhook, err := tx.GetWebhookByID(ctx, original.WebhookID)
+	if err != nil {
+		return nil, err
+	}
+	if !webhook.Active {
+		return nil, fmt.Errorf("cannot redeliver to webhook %d: webhook is disabled", webhook.ID)
+	}
+
+	delivery := original.Redelivery(time.Now())
+	if err := tx.CreateWebhookDelivery(ctx, delivery); err != nil {
+		return nil, fmt.Errorf("failed to create webhook delivery: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	if s.worker != nil {
+		s.worker.Wake()
+	}
+	return delivery, nil
+}
+
+func generateWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
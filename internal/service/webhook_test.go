@@ -0,0 +1,190 @@
+package service
+
+import (
+	"backend-store/internal/models"
+	"backend-store/internal/notify"
+	"backend-store/internal/storage"
+	"backend-store/pkg/logger"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// webhookReceiver отвечает на i-й запрос статусом statuses[i]; после
+// конца списка повторяется последний статус.
+type webhookReceiver struct {
+	mu       sync.Mutex
+	statuses []int
+	requests int
+}
+
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	status := r.statuses[min(r.requests, len(r.statuses)-1)]
+	r.requests++
+	r.mu.Unlock()
+	w.WriteHeader(status)
+}
+
+// fanOutEvents раскладывает count событий о заказах по доставкам вебхуков.
+func fanOutEvents(t *testing.T, worker *WebhookWorker, count int) {
+	t.Helper()
+	for i := 1; i <= count; i++ {
+		event, err := models.NewOutboxEvent(models.EventOrderCreated, models.AggregateOrder, i, map[string]int{"order_id": i})
+		require.NoError(t, err)
+		event.ID = i
+		require.NoError(t, worker.HandleEvent(context.Background(), *event))
+	}
+}
+
+func TestWebhookWorker_AutoDisable(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int
+		events       int
+		wantActive   bool
+		wantFailures int
+		wantRequests int
+	}{
+		{name: "failures below threshold", statuses: []int{500}, events: 2, wantActive: true, wantFailures: 2, wantRequests: 2},
+		{name: "disabled at threshold", statuses: []int{500}, events: 3, wantActive: false, wantFailures: 3, wantRequests: 3},
+		{name: "success resets counter", statuses: []int{500, 500, 200, 500}, events: 4, wantActive: true, wantFailures: 1, wantRequests: 4},
+		{name: "remaining deliveries abandoned", statuses: []int{500}, events: 5, wantActive: false, wantFailures: 3, wantRequests: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+			store := storage.NewMemoryStorage()
+			receiver := &webhookReceiver{statuses: tt.statuses}
+			server := httptest.NewServer(receiver)
+			defer server.Close()
+
+			worker := NewWebhookWorker(store, notify.NewWebhookSender(server.Client()), time.Second, 10, 3, logger.New("error"))
+			webhook := &models.Webhook{URL: server.URL, EventTypes: []string{models.EventOrderCreated}}
+			require.NoError(t, NewWebhookService(store, worker).CreateWebhook(ctx, webhook))
+			fanOutEvents(t, worker, tt.events)
+
+			// Act
+			_, err := worker.Deliver(ctx)
+			require.NoError(t, err)
+
+			// Assert
+			stored, err := store.GetWebhookByID(ctx, webhook.ID)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantActive, stored.Active)
+			assert.Equal(t, tt.wantFailures, stored.ConsecutiveFailures)
+			assert.Equal(t, tt.wantRequests, receiver.requests)
+			if tt.wantActive {
+				assert.Nil(t, stored.DisabledAt)
+				return
+			}
+			assert.NotNil(t, stored.DisabledAt)
+			assert.Contains(t, stored.DisabledReason, "3 consecutive failed deliveries")
+
+			deliveries, err := store.GetWebhookDeliveries(ctx, models.WebhookDeliveryFilter{WebhookID: webhook.ID})
+			require.NoError(t, err)
+			abandoned := 0
+			for _, d := range deliveries {
+				if d.LastError == "webhook disabled" {
+					assert.Equal(t, models.WebhookDeliveryFailed, d.Status)
+					abandoned++
+				}
+			}
+			assert.Equal(t, tt.events-tt.wantRequests, abandoned)
+		})
+	}
+}
+
+func TestWebhookWorker_DisabledWebhookGetsNoDeliveries(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	store := storage.NewMemoryStorage()
+	receiver := &webhookReceiver{statuses: []int{500}}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	worker := NewWebhookWorker(store, notify.NewWebhookSender(server.Client()), time.Second, 10, 1, logger.New("error"))
+	webhook := &models.Webhook{URL: server.URL, EventTypes: []string{models.EventOrderCreated}}
+	require.NoError(t, NewWebhookService(store, worker).CreateWebhook(ctx, webhook))
+	fanOutEvents(t, worker, 1)
+	_, err := worker.Deliver(ctx)
+	require.NoError(t, err)
+
+	// Act
+	event, err := models.NewOutboxEvent(models.EventOrderCreated, models.AggregateOrder, 2, map[string]int{"order_id": 2})
+	require.NoError(t, err)
+	event.ID = 2
+	require.NoError(t, worker.HandleEvent(ctx, *event))
+
+	// Assert
+	deliveries, err := store.GetWebhookDeliveries(ctx, models.WebhookDeliveryFilter{WebhookID: webhook.ID, EventID: 2})
+	require.NoError(t, err)
+	assert.Empty(t, deliveries)
+}
+
+func TestWebhookWorker_StopsBatchBeforeLeaseEnds(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	store := storage.NewMemoryStorage()
+	receiver := &webhookReceiver{statuses: []int{200}}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	// При таком таймауте до конца аренды не успеть ни одной доставки.
+	client := server.Client()
+	client.Timeout = webhookLease/2 + time.Second
+	worker := NewWebhookWorker(store, notify.NewWebhookSender(client), time.Second, 10, 3, logger.New("error"))
+	webhook := &models.Webhook{URL: server.URL, EventTypes: []string{models.EventOrderCreated}}
+	require.NoError(t, NewWebhookService(store, worker).CreateWebhook(ctx, webhook))
+	fanOutEvents(t, worker, 2)
+
+	// Act
+	processed, err := worker.Deliver(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Zero(t, processed)
+	assert.Zero(t, receiver.requests)
+	deliveries, err := store.GetWebhookDeliveries(ctx, models.WebhookDeliveryFilter{WebhookID: webhook.ID})
+	require.NoError(t, err)
+	for _, d := range deliveries {
+		assert.Equal(t, models.WebhookDeliveryPending, d.Status)
+		assert.Zero(t, d.Attempts)
+	}
+}
+
+func TestValidateWebhookTimeout(t *testing.T) {
+	tests := []struct {
+		name    string
+		timeout time.Duration
+		wantErr bool
+	}{
+		{name: "default", timeout: 10 * time.Second},
+		{name: "just below half the lease", timeout: webhookLease/2 - time.Second},
+		{name: "half the lease", timeout: webhookLease / 2, wantErr: true},
+		{name: "longer than the lease", timeout: 2 * webhookLease, wantErr: true},
+		{name: "unlimited", timeout: 0, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			err := ValidateWebhookTimeout(tt.timeout)
+
+			// Assert
+			if tt.wantErr {
+				assert.ErrorContains(t, err, "webhook timeout must be positive")
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
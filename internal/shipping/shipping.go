@@ -0,0 +1,30 @@
+// Package shipping рассчитывает стоимость доставки и содержит локальную
+// таблицу способов доставки.
+package shipping
+
+import "context"
+
+// Request - посылка для расчета доставки. Weight - вес в граммах, Volume -
+// объем в кубических миллиметрах, Subtotal - сумма товаров после скидок.
+// Пустая страна означает, что адрес неизвестен.
+type Request struct {
+	Country    string
+	Region     string
+	PostalCode string
+	Weight     int
+	Volume     int
+	Subtotal   int
+}
+
+// Quote - доступный способ доставки и его стоимость.
+type Quote struct {
+	Method string `json:"method"`
+	Name   string `json:"name"`
+	Cost   int    `json:"cost"`
+}
+
+// RateProvider - источник способов и стоимости доставки: локальная таблица
+// или внешний перевозчик.
+type RateProvider interface {
+	Quote(ctx context.Context, req Request) ([]Quote, error)
+}
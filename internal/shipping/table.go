@@ -0,0 +1,156 @@
+package shipping
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// Типы способов доставки.
+const (
+	// TypeFlat - одна цена за заказ.
+	TypeFlat = "flat"
+	// TypeWeight - цена по весовым диапазонам.
+	TypeWeight = "weight"
+)
+
+// Method - способ доставки. Для TypeFlat стоимость задает Rate, для
+// TypeWeight - первый диапазон Brackets, в который попадает вес посылки;
+// более тяжелая посылка этим способом не отправляется. При VolumetricDivisor
+// больше нуля вес не меньше объемного: объем в мм³ / VolumetricDivisor
+// (5000 соответствует обычным 5000 см³/кг). FreeAbove - сумма товаров, начиная
+// с которой доставка бесплатна. Пустой Countries означает любую страну.
+type Method struct {
+	Code              string    `json:"code"`
+	Name              string    `json:"name"`
+	Type              string    `json:"type"`
+	Rate              int       `json:"rate"`
+	Brackets          []Bracket `json:"brackets"`
+	VolumetricDivisor int       `json:"volumetric_divisor"`
+	FreeAbove         int       `json:"free_above"`
+	Countries         []string  `json:"countries"`
+}
+
+// Bracket - весовой диапазон до MaxWeight граммов включительно.
+type Bracket struct {
+	MaxWeight int `json:"max_weight"`
+	Rate      int `json:"rate"`
+}
+
+func (m *Method) validate() error {
+	if m.Code == "" {
+		return errors.New("code is required")
+	}
+	if m.Name == "" {
+		m.Name = m.Code
+	}
+	if m.Rate < 0 || m.FreeAbove < 0 || m.VolumetricDivisor < 0 {
+		return errors.New("rate, free_above and volumetric_divisor cannot be negative")
+	}
+	for i, c := range m.Countries {
+		c = strings.ToUpper(strings.TrimSpace(c))
+		if len(c) != 2 {
+			return fmt.Errorf("country %q must be a two-letter code", m.Countries[i])
+		}
+		m.Countries[i] = c
+	}
+
+	switch m.Type {
+	case TypeFlat:
+		if len(m.Brackets) > 0 {
+			return errors.New("flat rate method cannot have weight brackets")
+		}
+	case TypeWeight:
+		if len(m.Brackets) == 0 {
+			return errors.New("weight method requires brackets")
+		}
+		prev := 0
+		for _, b := range m.Brackets {
+			if b.MaxWeight <= prev {
+				return errors.New("bracket weights must be positive and increasing")
+			}
+			if b.Rate < 0 {
+				return errors.New("bracket rate cannot be negative")
+			}
+			prev = b.MaxWeight
+		}
+	default:
+		return fmt.Errorf("unknown type %q", m.Type)
+	}
+	return nil
+}
+
+// Table рассчитывает доставку по списку способов. Способы возвращаются в
+// порядке списка.
+type Table struct {
+	methods []Method
+}
+
+// NewTable создает таблицу из проверенных LoadJSON способов.
+func NewTable(methods []Method) *Table {
+	return &Table{methods: methods}
+}
+
+func (t *Table) Quote(ctx context.Context, req Request) ([]Quote, error) {
+	country := strings.ToUpper(strings.TrimSpace(req.Country))
+
+	quotes := make([]Quote, 0, len(t.methods))
+	for _, m := range t.methods {
+		if len(m.Countries) > 0 && !slices.Contains(m.Countries, country) {
+			continue
+		}
+		cost, ok := m.cost(req)
+		if !ok {
+			continue
+		}
+		quotes = append(quotes, Quote{Method: m.Code, Name: m.Name, Cost: cost})
+	}
+	return quotes, nil
+}
+
+func (m *Method) cost(req Request) (int, bool) {
+	cost := m.Rate
+	if m.Type == TypeWeight {
+		weight := req.Weight
+		if m.VolumetricDivisor > 0 {
+			weight = max(weight, (req.Volume+m.VolumetricDivisor-1)/m.VolumetricDivisor)
+		}
+		i := slices.IndexFunc(m.Brackets, func(b Bracket) bool { return weight <= b.MaxWeight })
+		if i < 0 {
+			return 0, false
+		}
+		cost = m.Brackets[i].Rate
+	}
+	if m.FreeAbove > 0 && req.Subtotal >= m.FreeAbove {
+		cost = 0
+	}
+	return cost, true
+}
+
+// LoadJSON читает способы доставки: JSON-массив объектов Method.
+func LoadJSON(r io.Reader) ([]Method, error) {
+	var methods []Method
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&methods); err != nil {
+		return nil, fmt.Errorf("invalid shipping methods: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	for i := range methods {
+		m := &methods[i]
+		m.Code = strings.ToLower(strings.TrimSpace(m.Code))
+		if err := m.validate(); err != nil {
+			return nil, fmt.Errorf("invalid shipping method %d: %w", i+1, err)
+		}
+		if seen[m.Code] {
+			return nil, fmt.Errorf("invalid shipping method %d: duplicate code %q", i+1, m.Code)
+		}
+		seen[m.Code] = true
+	}
+	return methods, nil
+}
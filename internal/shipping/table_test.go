@@ -0,0 +1,86 @@
+package shipping
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testMethods = `[
+	{"code": "standard", "name": "Standard", "type": "weight", "free_above": 10000,
+	 "brackets": [{"max_weight": 1000, "rate": 500}, {"max_weight": 5000, "rate": 900}]},
+	{"code": "express", "name": "Express", "type": "flat", "rate": 1500, "countries": ["us"]},
+	{"code": "bulky", "type": "weight", "volumetric_divisor": 5000,
+	 "brackets": [{"max_weight": 2000, "rate": 700}, {"max_weight": 20000, "rate": 2000}]}
+]`
+
+func TestTable_Quote(t *testing.T) {
+	// Arrange
+	methods, err := LoadJSON(strings.NewReader(testMethods))
+	require.NoError(t, err)
+	table := NewTable(methods)
+
+	tests := []struct {
+		name string
+		req  Request
+		want []Quote
+	}{
+		{
+			name: "light parcel in US",
+			req:  Request{Country: "us", Weight: 800, Subtotal: 3000},
+			want: []Quote{
+				{Method: "standard", Name: "Standard", Cost: 500},
+				{Method: "express", Name: "Express", Cost: 1500},
+				{Method: "bulky", Name: "bulky", Cost: 700},
+			},
+		},
+		{
+			name: "free above threshold, volumetric weight",
+			req:  Request{Country: "DE", Weight: 1500, Volume: 50_000_000, Subtotal: 10000},
+			want: []Quote{
+				{Method: "standard", Name: "Standard", Cost: 0},
+				{Method: "bulky", Name: "bulky", Cost: 2000},
+			},
+		},
+		{
+			name: "too heavy for standard",
+			req:  Request{Country: "DE", Weight: 6000},
+			want: []Quote{{Method: "bulky", Name: "bulky", Cost: 2000}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			quotes, err := table.Quote(context.Background(), tt.req)
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, quotes)
+		})
+	}
+}
+
+func TestLoadJSON_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		err   string
+	}{
+		{name: "unknown type", input: `[{"code":"a","type":"zone"}]`, err: `invalid shipping method 1: unknown type "zone"`},
+		{name: "duplicate", input: `[{"code":"a","type":"flat"},{"code":"A","type":"flat"}]`, err: `invalid shipping method 2: duplicate code "a"`},
+		{name: "brackets not increasing", input: `[{"code":"a","type":"weight","brackets":[{"max_weight":500},{"max_weight":500}]}]`,
+			err: "invalid shipping method 1: bracket weights must be positive and increasing"},
+		{name: "bad country", input: `[{"code":"a","type":"flat","countries":["USA"]}]`, err: `invalid shipping method 1: country "USA" must be a two-letter code`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadJSON(strings.NewReader(tt.input))
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
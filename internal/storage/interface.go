@@ -3,23 +3,260 @@ package storage
 import (
 	"backend-store/internal/models"
 	"context"
+	"errors"
+	"fmt"
+	"time"
 )
 
+// ErrNotFound возвращают обе реализации, если запись не найдена; текст
+// ошибки по-прежнему называет сущность, например "product not found".
+// Проверяется через errors.Is.
+var ErrNotFound = errors.New("not found")
+
+// ErrUsageLimitReached возвращает RedeemPromotion, если исчерпан общий
+// лимит акции или лимит на покупателя.
+var ErrUsageLimitReached = errors.New("usage limit reached")
+
+func notFound(entity string) error {
+	return fmt.Errorf("%s %w", entity, ErrNotFound)
+}
+
 type Storage interface {
 	// Products
 	CreateProduct(ctx context.Context, product *models.Product) error
 	GetProductByID(ctx context.Context, id int) (*models.Product, error)
+	GetProductBySKU(ctx context.Context, sku string) (*models.Product, error)
 	GetAllProducts(ctx context.Context) ([]*models.Product, error)
 	UpdateProduct(ctx context.Context, product *models.Product) error
 	DeleteProduct(ctx context.Context, id int) error
 
+	// Variants
+	CreateVariant(ctx context.Context, variant *models.ProductVariant) error
+	GetVariantByID(ctx context.Context, id int) (*models.ProductVariant, error)
+	GetVariantsByProductID(ctx context.Context, productID int) ([]*models.ProductVariant, error)
+	UpdateVariant(ctx context.Context, variant *models.ProductVariant) error
+	DeleteVariant(ctx context.Context, id int) error
+
+	// Search: полнотекстовый поиск с ранжированием, пагинацией и фасетами.
+	SearchProducts(ctx context.Context, search models.ProductSearch) (*models.SearchResult, error)
+
+	// Categories
+	CreateCategory(ctx context.Context, category *models.Category) error
+	GetCategoryByID(ctx context.Context, id int) (*models.Category, error)
+	GetAllCategories(ctx context.Context) ([]*models.Category, error)
+	UpdateCategory(ctx context.Context, category *models.Category) error
+	DeleteCategory(ctx context.Context, id int) error
+	GetProductCategoryIDs(ctx context.Context, productID int) ([]int, error)
+	SetProductCategories(ctx context.Context, productID int, categoryIDs []int) error
+	GetProductIDsByCategories(ctx context.Context, categoryIDs []int) ([]int, error)
+	// ReassignCategoryProducts добавляет товары категории fromID в toID.
+	ReassignCategoryProducts(ctx context.Context, fromID, toID int) error
+
+	// Tags
+	GetProductTags(ctx context.Context, productID int) ([]string, error)
+	SetProductTags(ctx context.Context, productID int, tags []string) error
+	GetProductIDsByTag(ctx context.Context, tag string) ([]int, error)
+	GetAllTags(ctx context.Context) ([]models.TagCount, error)
+
+	// Warehouses
+	CreateWarehouse(ctx context.Context, warehouse *models.Warehouse) error
+	GetWarehouseByID(ctx context.Context, id int) (*models.Warehouse, error)
+	GetAllWarehouses(ctx context.Context) ([]*models.Warehouse, error)
+	UpdateWarehouse(ctx context.Context, warehouse *models.Warehouse) error
+	DeleteWarehouse(ctx context.Context, id int) error
+
+	// Stock levels: остатки вариантов по складам.
+	GetStockLevelsByVariant(ctx context.Context, variantID int) ([]models.StockLevel, error)
+	GetStockLevelsByProduct(ctx context.Context, productID int) ([]models.StockLevel, error)
+	GetStockLevelsByWarehouse(ctx context.Context, warehouseID int) ([]models.StockLevel, error)
+	GetAllStockLevels(ctx context.Context) ([]models.StockLevel, error)
+	// AdjustStockLevel изменяет остаток на delta и возвращает новое значение.
+	// Остаток не может стать отрицательным.
+	AdjustStockLevel(ctx context.Context, warehouseID, variantID, delta int) (int, error)
+
+	// Stock ledger: журнал движения товара только дополняется.
+	CreateStockMovement(ctx context.Context, movement *models.StockMovement) error
+	GetStockMovementsByProduct(ctx context.Context, productID int) ([]models.StockMovement, error)
+	// GetStockLedgerBalances возвращает остатки по журналу для всех пар
+	// склад-вариант, у которых есть движения.
+	GetStockLedgerBalances(ctx context.Context) ([]models.StockBalance, error)
+
+	// Low stock alerts: у товара не более одного открытого предупреждения,
+	// повторное создание возвращает ошибку "already exists".
+	CreateLowStockAlert(ctx context.Context, alert *models.LowStockAlert) error
+	GetOpenLowStockAlerts(ctx context.Context) ([]models.LowStockAlert, error)
+	MarkLowStockAlertNotified(ctx context.Context, id int, at time.Time) error
+	ResolveLowStockAlert(ctx context.Context, id int, at time.Time) error
+
+	// Allocations
+	CreateAllocation(ctx context.Context, allocation *models.StockAllocation) error
+	GetAllocationsByOrder(ctx context.Context, orderID int) ([]models.StockAllocation, error)
+	DeleteAllocationsByOrder(ctx context.Context, orderID int) error
+
+	// Transfers: UpdateTransfer сохраняет документ, только если он все еще
+	// в статусе from; иначе возвращает ошибку "cannot".
+	CreateTransfer(ctx context.Context, transfer *models.StockTransfer) error
+	GetTransferByID(ctx context.Context, id int) (*models.StockTransfer, error)
+	GetAllTransfers(ctx context.Context, status string) ([]*models.StockTransfer, error)
+	UpdateTransfer(ctx context.Context, transfer *models.StockTransfer, from string) error
+
+	// Suppliers: поставщика с заказами удалить нельзя.
+	CreateSupplier(ctx context.Context, supplier *models.Supplier) error
+	GetSupplierByID(ctx context.Context, id int) (*models.Supplier, error)
+	GetAllSuppliers(ctx context.Context) ([]*models.Supplier, error)
+	UpdateSupplier(ctx context.Context, supplier *models.Supplier) error
+	DeleteSupplier(ctx context.Context, id int) error
+
+	// Purchase orders: заказы поставщикам читаются вместе с позициями.
+	// UpdatePurchaseOrder сохраняет реквизиты, статус и принятые количества
+	// позиций; ReplacePurchaseOrderItems заменяет состав черновика.
+	// LockPurchaseOrder читает заказ, блокируя его изменение другими
+	// транзакциями до конца текущей.
+	CreatePurchaseOrder(ctx context.Context, order *models.PurchaseOrder) error
+	GetPurchaseOrderByID(ctx context.Context, id int) (*models.PurchaseOrder, error)
+	LockPurchaseOrder(ctx context.Context, id int) (*models.PurchaseOrder, error)
+	GetAllPurchaseOrders(ctx context.Context, status string) ([]*models.PurchaseOrder, error)
+	UpdatePurchaseOrder(ctx context.Context, order *models.PurchaseOrder) error
+	ReplacePurchaseOrderItems(ctx context.Context, order *models.PurchaseOrder) error
+
 	// Orders
 	CreateOrder(ctx context.Context, order *models.Order) error
 	GetOrderByID(ctx context.Context, id int) (*models.Order, error)
 	GetAllOrders(ctx context.Context) ([]*models.Order, error)
 	UpdateOrder(ctx context.Context, order *models.Order) error
+	// UpdateOrderStatus меняет только статус, не пересчитывая позиции и сумму.
+	UpdateOrderStatus(ctx context.Context, id int, status string) error
 	DeleteOrder(ctx context.Context, id int) error
 
+	// Payments: у заказа не больше одной активной попытки оплаты (models.Payment.IsActive),
+	// повторное создание возвращает ошибку "already has an active payment".
+	CreatePayment(ctx context.Context, payment *models.Payment) error
+	GetPaymentByID(ctx context.Context, id int) (*models.Payment, error)
+	GetPaymentByReference(ctx context.Context, provider, reference string) (*models.Payment, error)
+	GetPaymentsByOrder(ctx context.Context, orderID int) ([]models.Payment, error)
+	UpdatePayment(ctx context.Context, payment *models.Payment) error
+	// AddPaymentRefund атомарно добавляет delta к возвращенной сумме
+	// списанного платежа, если она остается в пределах от 0 до суммы
+	// платежа, и возвращает платеж после изменения. Полностью возвращенный
+	// платеж переходит в refunded; отрицательный delta снимает резерв.
+	AddPaymentRefund(ctx context.Context, id, delta int) (*models.Payment, error)
+
+	// Promotions: код уникален без учета регистра. RedeemPromotion атомарно
+	// проверяет общий лимит и лимит на покупателя и записывает
+	// использование, при исчерпании возвращает ErrUsageLimitReached;
+	// ReleasePromotions отменяет использования заказа.
+	CreatePromotion(ctx context.Context, promotion *models.Promotion) error
+	GetPromotionByID(ctx context.Context, id int) (*models.Promotion, error)
+	GetPromotionByCode(ctx context.Context, code string) (*models.Promotion, error)
+	GetAllPromotions(ctx context.Context) ([]*models.Promotion, error)
+	UpdatePromotion(ctx context.Context, promotion *models.Promotion) error
+	DeletePromotion(ctx context.Context, id int) error
+	RedeemPromotion(ctx context.Context, redemption *models.PromotionRedemption) error
+	CountPromotionRedemptions(ctx context.Context, promotionID, customerID int) (int, error)
+	ReleasePromotions(ctx context.Context, orderID int) error
+
+	// Order adjustments: скидки заказа сохраняются отдельно от позиций.
+	CreateOrderAdjustments(ctx context.Context, orderID int, adjustments []models.OrderAdjustment) error
+	GetOrderAdjustments(ctx context.Context, orderID int) ([]models.OrderAdjustment, error)
+	DeleteOrderAdjustments(ctx context.Context, orderID int) error
+
+	// Returns: заявки сохраняются и читаются вместе с позициями. UpdateReturn
+	// меняет статус, решение, сумму возврата и судьбу принятых позиций.
+	CreateReturn(ctx context.Context, ret *models.ReturnRequest) error
+	GetReturnByID(ctx context.Context, id int) (*models.ReturnRequest, error)
+	GetReturnsByOrder(ctx context.Context, orderID int) ([]models.ReturnRequest, error)
+	UpdateReturn(ctx context.Context, ret *models.ReturnRequest) error
+	// SetReturnStatus меняет статус заявки с from на to, только если она все
+	// еще в статусе from; иначе возвращает ошибку "cannot".
+	SetReturnStatus(ctx context.Context, id int, from, to string) error
+
+	// Shipments: отправления сохраняются и читаются вместе с позициями.
+	// UpdateShipment меняет статус, перевозчика и трек-номер; состав не
+	// меняется.
+	CreateShipment(ctx context.Context, shipment *models.Shipment) error
+	GetShipmentByID(ctx context.Context, id int) (*models.Shipment, error)
+	GetShipmentsByOrder(ctx context.Context, orderID int) ([]models.Shipment, error)
+	UpdateShipment(ctx context.Context, shipment *models.Shipment) error
+
+	// Customers: адреса читаются и сохраняются вместе с покупателем,
+	// UpdateCustomer заменяет их целиком. Покупателя с заказами удалить нельзя.
+	CreateCustomer(ctx context.Context, customer *models.Customer) error
+	GetCustomerByID(ctx context.Context, id int) (*models.Customer, error)
+	GetCustomerByEmail(ctx context.Context, email string) (*models.Customer, error)
+	GetAllCustomers(ctx context.Context) ([]*models.Customer, error)
+	UpdateCustomer(ctx context.Context, customer *models.Customer) error
+	DeleteCustomer(ctx context.Context, id int) error
+
+	// Carts: GetCartByID возвращает корзину вместе с позициями.
+	CreateCart(ctx context.Context, cart *models.Cart) error
+	GetCartByID(ctx context.Context, id int) (*models.Cart, error)
+	GetActiveCartByUser(ctx context.Context, userID int) (*models.Cart, error)
+	UpdateCart(ctx context.Context, cart *models.Cart) error
+	DeleteCart(ctx context.Context, id int) error
+	AddCartItem(ctx context.Context, item *models.CartItem) error
+	UpdateCartItem(ctx context.Context, item *models.CartItem) error
+	DeleteCartItem(ctx context.Context, id int) error
+	// DeleteExpiredCarts удаляет активные корзины, срок жизни которых истек
+	// до before, и возвращает их количество.
+	DeleteExpiredCarts(ctx context.Context, before time.Time) (int, error)
+
+	// Export: потоковый обход без загрузки всей выборки в память.
+	// Обход прекращается при первой ошибке fn.
+	IterateProducts(ctx context.Context, fn func(*models.Product) error) error
+	IterateOrders(ctx context.Context, filter models.OrderFilter, fn func(*models.Order) error) error
+
+	// Reports: агрегаты по заказам периода и оценка остатков. Отмененные
+	// заказы не входят в выручку, но учитываются в разбивке по статусам.
+	ReportSales(ctx context.Context, filter models.ReportFilter) ([]models.SalesPoint, error)
+	ReportTopProducts(ctx context.Context, filter models.ReportFilter) ([]models.ProductSales, error)
+	ReportOrderValue(ctx context.Context, filter models.ReportFilter) (*models.OrderValue, error)
+	ReportOrderStatuses(ctx context.Context, filter models.ReportFilter) ([]models.StatusCount, error)
+	ReportStockValuation(ctx context.Context, filter models.ReportFilter) ([]models.StockValuation, error)
+
+	// Outbox: доменные события записываются в транзакции изменения.
+	// ClaimOutboxEvents выбирает ожидающие события, срок попытки которых
+	// наступил к now, и откладывает их следующую попытку на lease, чтобы
+	// другие экземпляры приложения не доставляли их одновременно.
+	CreateOutboxEvent(ctx context.Context, event *models.OutboxEvent) error
+	GetOutboxEventByID(ctx context.Context, id int) (*models.OutboxEvent, error)
+	GetOutboxEvents(ctx context.Context, filter models.OutboxFilter) ([]models.OutboxEvent, error)
+	ClaimOutboxEvents(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.OutboxEvent, error)
+	UpdateOutboxEvent(ctx context.Context, event *models.OutboxEvent) error
+
+	// Webhooks: подписки на события и журнал доставок. Удаление вебхука
+	// удаляет его доставки. ClaimWebhookDeliveries работает как
+	// ClaimOutboxEvents.
+	CreateWebhook(ctx context.Context, webhook *models.Webhook) error
+	GetWebhookByID(ctx context.Context, id int) (*models.Webhook, error)
+	GetWebhooks(ctx context.Context) ([]models.Webhook, error)
+	UpdateWebhook(ctx context.Context, webhook *models.Webhook) error
+	DeleteWebhook(ctx context.Context, id int) error
+	CreateWebhookDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+	GetWebhookDeliveryByID(ctx context.Context, id int) (*models.WebhookDelivery, error)
+	GetWebhookDeliveries(ctx context.Context, filter models.WebhookDeliveryFilter) ([]models.WebhookDelivery, error)
+	ClaimWebhookDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error)
+	UpdateWebhookDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+
+	// Audit: журнал аудита только дополняется и записывается в транзакции
+	// изменения. DeleteAuditEntriesBefore удаляет записи старше before по
+	// сроку хранения и возвращает их количество.
+	CreateAuditEntry(ctx context.Context, entry *models.AuditEntry) error
+	GetAuditEntries(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error)
+	DeleteAuditEntriesBefore(ctx context.Context, before time.Time) (int, error)
+
+	// Batch: пакетное чтение для загрузчиков GraphQL одним запросом на
+	// набор ключей. Отсутствующие ID пропускаются; результат упорядочен по
+	// ID, списки ID по ключу - по возрастанию.
+	GetProductsByIDs(ctx context.Context, ids []int) ([]*models.Product, error)
+	GetOrdersByIDs(ctx context.Context, ids []int) ([]*models.Order, error)
+	GetCustomersByIDs(ctx context.Context, ids []int) ([]*models.Customer, error)
+	GetCategoriesByIDs(ctx context.Context, ids []int) ([]*models.Category, error)
+	GetCategoryIDsByProducts(ctx context.Context, productIDs []int) (map[int][]int, error)
+	GetTagsByProducts(ctx context.Context, productIDs []int) (map[int][]string, error)
+	GetStockLevelsByProducts(ctx context.Context, productIDs []int) ([]models.StockLevel, error)
+	GetOrderIDsByProducts(ctx context.Context, productIDs []int) (map[int][]int, error)
+	GetOrderIDsByCustomers(ctx context.Context, customerIDs []int) (map[int][]int, error)
+
 	// Transactions
 	BeginTx(ctx context.Context) (StorageTx, error)
 
@@ -27,7 +264,8 @@ type Storage interface {
 	Close() error
 }
 
-// StorageTx интерфейс для транзакций
+// StorageTx интерфейс для транзакций. Rollback отменяет изменения только в
+// PostgresStorage, см. MemoryTx.
 type StorageTx interface {
 	Storage
 	Commit() error
@@ -2,34 +2,155 @@ package storage
 
 import (
 	"backend-store/internal/models"
+	"backend-store/internal/search"
 	"context"
 	"errors"
+	"fmt"
+	"sort"
 	"sync"
+	"time"
 )
 
 type MemoryStorage struct {
 	products     map[int]*models.Product
 	orders       map[int]*models.Order
+	variants     map[int]*models.ProductVariant
+	categories   map[int]*models.Category
 	productIDSeq int
 	orderIDSeq   int
-	mu           sync.RWMutex
+	variantIDSeq int
+	itemIDSeq    int
+
+	categoryIDSeq     int
+	productCategories map[int][]int
+	productTags       map[int][]string
+	searchIndex       *search.Index
+
+	warehouses        map[int]*models.Warehouse
+	stock             map[stockKey]*models.StockLevel
+	allocations       map[int]*models.StockAllocation
+	transfers         map[int]*models.StockTransfer
+	warehouseIDSeq    int
+	allocationIDSeq   int
+	transferIDSeq     int
+	transferItemIDSeq int
+
+	movements     []*models.StockMovement
+	movementIDSeq int
+
+	lowStockAlerts     map[int]*models.LowStockAlert
+	lowStockAlertIDSeq int
+
+	carts         map[int]*models.Cart
+	cartItems     map[int]*models.CartItem
+	cartIDSeq     int
+	cartItemIDSeq int
+
+	customers     map[int]*models.Customer
+	customerIDSeq int
+	addressIDSeq  int
+
+	payments     map[int]*models.Payment
+	paymentIDSeq int
+
+	returns         map[int]*models.ReturnRequest
+	returnIDSeq     int
+	returnItemIDSeq int
+
+	shipments         map[int]*models.Shipment
+	shipmentIDSeq     int
+	shipmentItemIDSeq int
+
+	suppliers              map[int]*models.Supplier
+	purchaseOrders         map[int]*models.PurchaseOrder
+	supplierIDSeq          int
+	purchaseOrderIDSeq     int
+	purchaseOrderItemIDSeq int
+
+	promotions      map[int]*models.Promotion
+	redemptions     []*models.PromotionRedemption
+	adjustments     map[int][]models.OrderAdjustment
+	promotionIDSeq  int
+	redemptionIDSeq int
+	adjustmentIDSeq int
+
+	outbox      map[int]*models.OutboxEvent
+	outboxIDSeq int
+
+	webhooks          map[int]*models.Webhook
+	webhookDeliveries map[int]*models.WebhookDelivery
+	webhookIDSeq      int
+	deliveryIDSeq     int
+
+	audit      []models.AuditEntry
+	auditIDSeq int
+
+	mu sync.RWMutex
 }
 
-// MemoryTx - транзакция для in-memory хранилища
+// MemoryTx - транзакция для in-memory хранилища.
+// Держит блокировку хранилища до Commit/Rollback, поэтому методы транзакции
+// работают с данными напрямую, без повторного захвата мьютекса. Журнала
+// отмены нет: Rollback только снимает блокировку, и изменения, сделанные до
+// ошибки, остаются. Атомарность обеспечивает лишь PostgresStorage.
 type MemoryTx struct {
 	storage *MemoryStorage
 	mu      *sync.RWMutex
+	done    bool
 }
 
 func (mt *MemoryTx) Close() error {
-	panic("unimplemented")
+	return nil
 }
 
 func NewMemoryStorage() *MemoryStorage {
-	return &MemoryStorage{
+	m := &MemoryStorage{
 		products: make(map[int]*models.Product),
 		orders:   make(map[int]*models.Order),
+		variants: make(map[int]*models.ProductVariant),
+
+		categories:        make(map[int]*models.Category),
+		productCategories: make(map[int][]int),
+		productTags:       make(map[int][]string),
+		searchIndex:       search.NewIndex(),
+
+		warehouses:  make(map[int]*models.Warehouse),
+		stock:       make(map[stockKey]*models.StockLevel),
+		allocations: make(map[int]*models.StockAllocation),
+		transfers:   make(map[int]*models.StockTransfer),
+
+		lowStockAlerts: make(map[int]*models.LowStockAlert),
+
+		carts:     make(map[int]*models.Cart),
+		cartItems: make(map[int]*models.CartItem),
+
+		customers: make(map[int]*models.Customer),
+		payments:  make(map[int]*models.Payment),
+		returns:   make(map[int]*models.ReturnRequest),
+		shipments: make(map[int]*models.Shipment),
+
+		suppliers:      make(map[int]*models.Supplier),
+		purchaseOrders: make(map[int]*models.PurchaseOrder),
+
+		promotions:  make(map[int]*models.Promotion),
+		adjustments: make(map[int][]models.OrderAdjustment),
+
+		outbox: make(map[int]*models.OutboxEvent),
+
+		webhooks:          make(map[int]*models.Webhook),
+		webhookDeliveries: make(map[int]*models.WebhookDelivery),
 	}
+
+	// Основной склад, как и в миграции PostgreSQL.
+	now := time.Now()
+	m.createWarehouse(&models.Warehouse{
+		Code:      "MAIN",
+		Name:      "Main warehouse",
+		Active:    true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	return m
 }
 
 func (m *MemoryStorage) Close() error {
@@ -49,227 +170,308 @@ func (mt *MemoryTx) BeginTx(ctx context.Context) (StorageTx, error) {
 }
 
 func (mt *MemoryTx) Commit() error {
-	mt.mu.Unlock()
-	return nil
+	return mt.finish()
 }
 
 func (mt *MemoryTx) Rollback() error {
+	return mt.finish()
+}
+
+// finish снимает блокировку один раз: сервисы вызывают Rollback через defer
+// и после успешного Commit.
+func (mt *MemoryTx) finish() error {
+	if mt.done {
+		return nil
+	}
+	mt.done = true
 	mt.mu.Unlock()
 	return nil
 }
 
 func (mt *MemoryTx) CreateProduct(ctx context.Context, product *models.Product) error {
-	mt.mu.Lock()
-	defer mt.mu.Unlock()
-
-	mt.storage.productIDSeq++
-	product.ID = mt.storage.productIDSeq
-	mt.storage.products[product.ID] = product
-	return nil
+	return mt.storage.createProduct(product)
 }
 
 func (mt *MemoryTx) GetAllProducts(ctx context.Context) ([]*models.Product, error) {
-	mt.mu.RLock()
-	defer mt.mu.RUnlock()
-
-	products := make([]*models.Product, 0, len(mt.storage.products))
-	for _, p := range mt.storage.products {
-		products = append(products, p)
-	}
-	return products, nil
+	return mt.storage.getAllProducts(), nil
 }
 
 func (mt *MemoryTx) GetProductByID(ctx context.Context, id int) (*models.Product, error) {
-	mt.mu.RLock()
-	defer mt.mu.RUnlock()
+	return mt.storage.getProductByID(id)
+}
 
-	product, exists := mt.storage.products[id]
-	if !exists {
-		return nil, errors.New("product not found")
-	}
-	return product, nil
+func (mt *MemoryTx) GetProductBySKU(ctx context.Context, sku string) (*models.Product, error) {
+	return mt.storage.getProductBySKU(sku)
 }
 
 func (mt *MemoryTx) UpdateProduct(ctx context.Context, product *models.Product) error {
-	mt.mu.Lock()
-	defer mt.mu.Unlock()
-
-	if _, exists := mt.storage.products[product.ID]; !exists {
-		return errors.New("product not found")
-	}
-	mt.storage.products[product.ID] = product
-	return nil
+	return mt.storage.updateProduct(product)
 }
 
 func (mt *MemoryTx) DeleteProduct(ctx context.Context, id int) error {
-	mt.mu.Lock()
-	defer mt.mu.Unlock()
-
-	if _, exists := mt.storage.products[id]; !exists {
-		return errors.New("product not found")
-	}
-	delete(mt.storage.products, id)
-	return nil
+	return mt.storage.deleteProduct(id)
 }
 
 func (mt *MemoryTx) CreateOrder(ctx context.Context, order *models.Order) error {
-	mt.mu.Lock()
-	defer mt.mu.Unlock()
-
-	mt.storage.orderIDSeq++
-	order.ID = mt.storage.orderIDSeq
-	mt.storage.orders[order.ID] = order
-	return nil
+	return mt.storage.createOrder(order)
 }
 
 func (mt *MemoryTx) GetAllOrders(ctx context.Context) ([]*models.Order, error) {
-	mt.mu.RLock()
-	defer mt.mu.RUnlock()
-
-	orders := make([]*models.Order, 0, len(mt.storage.orders))
-	for _, o := range mt.storage.orders {
-		orders = append(orders, o)
-	}
-	return orders, nil
+	return mt.storage.getAllOrders(), nil
 }
 
 func (mt *MemoryTx) GetOrderByID(ctx context.Context, id int) (*models.Order, error) {
-	mt.mu.RLock()
-	defer mt.mu.RUnlock()
-
-	order, exists := mt.storage.orders[id]
-	if !exists {
-		return nil, errors.New("order not found")
-	}
-	return order, nil
+	return mt.storage.getOrderByID(id)
 }
 
 func (mt *MemoryTx) UpdateOrder(ctx context.Context, order *models.Order) error {
-	mt.mu.Lock()
-	defer mt.mu.Unlock()
+	return mt.storage.updateOrder(order)
+}
 
-	if _, exists := mt.storage.orders[order.ID]; !exists {
-		return errors.New("order not found")
-	}
-	mt.storage.orders[order.ID] = order
-	return nil
+func (mt *MemoryTx) UpdateOrderStatus(ctx context.Context, id int, status string) error {
+	return mt.storage.updateOrderStatus(id, status)
 }
 
 func (mt *MemoryTx) DeleteOrder(ctx context.Context, id int) error {
-	mt.mu.Lock()
-	defer mt.mu.Unlock()
-
-	if _, exists := mt.storage.orders[id]; !exists {
-		return errors.New("order not found")
-	}
-	delete(mt.storage.orders, id)
-	return nil
+	return mt.storage.deleteOrder(id)
 }
 
 func (m *MemoryStorage) CreateProduct(ctx context.Context, product *models.Product) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	return m.createProduct(product)
+}
+
+func (m *MemoryStorage) GetAllProducts(ctx context.Context) ([]*models.Product, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getAllProducts(), nil
+}
+
+func (m *MemoryStorage) GetProductByID(ctx context.Context, id int) (*models.Product, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getProductByID(id)
+}
 
+func (m *MemoryStorage) GetProductBySKU(ctx context.Context, sku string) (*models.Product, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getProductBySKU(sku)
+}
+
+func (m *MemoryStorage) UpdateProduct(ctx context.Context, product *models.Product) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.updateProduct(product)
+}
+
+func (m *MemoryStorage) DeleteProduct(ctx context.Context, id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.deleteProduct(id)
+}
+
+func (m *MemoryStorage) CreateOrder(ctx context.Context, order *models.Order) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.createOrder(order)
+}
+
+func (m *MemoryStorage) GetAllOrders(ctx context.Context) ([]*models.Order, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getAllOrders(), nil
+}
+
+func (m *MemoryStorage) GetOrderByID(ctx context.Context, id int) (*models.Order, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getOrderByID(id)
+}
+
+func (m *MemoryStorage) UpdateOrder(ctx context.Context, order *models.Order) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.updateOrder(order)
+}
+
+func (m *MemoryStorage) UpdateOrderStatus(ctx context.Context, id int, status string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.updateOrderStatus(id, status)
+}
+
+func (m *MemoryStorage) DeleteOrder(ctx context.Context, id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.deleteOrder(id)
+}
+
+// Методы ниже вызываются под блокировкой m.mu.
+
+func (m *MemoryStorage) createProduct(product *models.Product) error {
+	if err := m.checkSKU(product); err != nil {
+		return err
+	}
 	m.productIDSeq++
 	product.ID = m.productIDSeq
 	m.products[product.ID] = product
+	m.indexProduct(product)
 	return nil
 }
 
-func (m *MemoryStorage) GetAllProducts(ctx context.Context) ([]*models.Product, error) {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
-
+func (m *MemoryStorage) getAllProducts() []*models.Product {
 	products := make([]*models.Product, 0, len(m.products))
 	for _, p := range m.products {
 		products = append(products, p)
 	}
-	return products, nil
+	sort.Slice(products, func(i, j int) bool { return products[i].ID < products[j].ID })
+	return products
 }
 
-func (m *MemoryStorage) GetProductByID(ctx context.Context, id int) (*models.Product, error) {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
-
+func (m *MemoryStorage) getProductByID(id int) (*models.Product, error) {
 	product, exists := m.products[id]
 	if !exists {
-		return nil, errors.New("product not found")
+		return nil, notFound("product")
 	}
 	return product, nil
 }
 
-func (m *MemoryStorage) UpdateProduct(ctx context.Context, product *models.Product) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
+func (m *MemoryStorage) getProductBySKU(sku string) (*models.Product, error) {
+	if sku != "" {
+		for _, p := range m.products {
+			if p.SKU == sku {
+				return p, nil
+			}
+		}
+	}
+	return nil, notFound("product")
+}
 
-	if _, exists := m.products[product.ID]; !exists {
-		return errors.New("product not found")
+// updateProduct, как и UPDATE в PostgreSQL, сохраняет прежний SKU, если
+// новый не указан.
+func (m *MemoryStorage) updateProduct(product *models.Product) error {
+	existing, exists := m.products[product.ID]
+	if !exists {
+		return notFound("product")
 	}
-	m.products[product.ID] = product
+	if err := m.checkSKU(product); err != nil {
+		return err
+	}
+	updated := *product
+	if updated.SKU == "" {
+		updated.SKU = existing.SKU
+	}
+	m.products[product.ID] = &updated
+	m.indexProduct(&updated)
 	return nil
 }
 
-func (m *MemoryStorage) DeleteProduct(ctx context.Context, id int) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
+// checkSKU повторяет уникальный индекс products.sku из PostgreSQL.
+func (m *MemoryStorage) checkSKU(product *models.Product) error {
+	if product.SKU == "" {
+		return nil
+	}
+	if existing, err := m.getProductBySKU(product.SKU); err == nil && existing.ID != product.ID {
+		return fmt.Errorf("product with sku %q already exists", product.SKU)
+	}
+	return nil
+}
 
+func (m *MemoryStorage) deleteProduct(id int) error {
 	if _, exists := m.products[id]; !exists {
-		return errors.New("product not found")
+		return notFound("product")
 	}
 	delete(m.products, id)
+	for variantID, v := range m.variants {
+		if v.ProductID == id {
+			delete(m.variants, variantID)
+			m.deleteVariantStock(variantID)
+		}
+	}
+	delete(m.productCategories, id)
+	delete(m.productTags, id)
+	m.deleteProductAlerts(id)
+	m.deleteCartItems(func(item *models.CartItem) bool { return item.ProductID == id })
+	m.searchIndex.Remove(id)
 	return nil
 }
 
-func (m *MemoryStorage) CreateOrder(ctx context.Context, order *models.Order) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
+func (m *MemoryStorage) createOrder(order *models.Order) error {
+	if err := m.checkOrderCustomer(order); err != nil {
+		return err
+	}
 	m.orderIDSeq++
 	order.ID = m.orderIDSeq
+	m.assignItemIDs(order)
 	m.orders[order.ID] = order
 	return nil
 }
 
-func (m *MemoryStorage) GetAllOrders(ctx context.Context) ([]*models.Order, error) {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
+// assignItemIDs повторяет поведение SERIAL для order_items.
+func (m *MemoryStorage) assignItemIDs(order *models.Order) {
+	for i := range order.Products {
+		m.itemIDSeq++
+		order.Products[i].ID = m.itemIDSeq
+		order.Products[i].OrderID = order.ID
+	}
+}
 
+func (m *MemoryStorage) getAllOrders() []*models.Order {
 	orders := make([]*models.Order, 0, len(m.orders))
 	for _, o := range m.orders {
 		orders = append(orders, o)
 	}
-	return orders, nil
+	sort.Slice(orders, func(i, j int) bool { return orders[i].ID < orders[j].ID })
+	return orders
 }
 
-func (m *MemoryStorage) GetOrderByID(ctx context.Context, id int) (*models.Order, error) {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
-
+func (m *MemoryStorage) getOrderByID(id int) (*models.Order, error) {
 	order, exists := m.orders[id]
 	if !exists {
-		return nil, errors.New("order not found")
+		return nil, notFound("order")
 	}
 	return order, nil
 }
 
-func (m *MemoryStorage) UpdateOrder(ctx context.Context, order *models.Order) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
+func (m *MemoryStorage) updateOrder(order *models.Order) error {
 	if _, exists := m.orders[order.ID]; !exists {
-		return errors.New("order not found")
+		return notFound("order")
+	}
+	if err := m.checkOrderCustomer(order); err != nil {
+		return err
 	}
+	m.assignItemIDs(order)
 	m.orders[order.ID] = order
 	return nil
 }
 
-func (m *MemoryStorage) DeleteOrder(ctx context.Context, id int) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
+func (m *MemoryStorage) updateOrderStatus(id int, status string) error {
+	order, exists := m.orders[id]
+	if !exists {
+		return notFound("order")
+	}
+	// Хранимый заказ заменяется копией, чтобы не менять заказ, прочитанный
+	// вызывающим кодом раньше.
+	updated := *order
+	updated.Status = status
+	updated.UpdatedAt = time.Now()
+	m.orders[id] = &updated
+	return nil
+}
 
+func (m *MemoryStorage) deleteOrder(id int) error {
 	if _, exists := m.orders[id]; !exists {
-		return errors.New("order not found")
+		return notFound("order")
+	}
+	if m.orderHasPayments(id) {
+		return errors.New("cannot delete order with payments")
 	}
 	delete(m.orders, id)
+	m.deleteAllocationsByOrder(id)
+	m.deleteOrderAdjustments(id)
+	m.releasePromotions(id)
+	m.clearCartOrder(id)
 	return nil
 }
@@ -0,0 +1,76 @@
+package storage
+
+import (
+	"backend-store/internal/models"
+	"context"
+	"time"
+)
+
+func (m *MemoryStorage) CreateAuditEntry(ctx context.Context, entry *models.AuditEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.createAuditEntry(entry)
+	return nil
+}
+
+func (m *MemoryStorage) GetAuditEntries(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getAuditEntries(filter), nil
+}
+
+func (m *MemoryStorage) DeleteAuditEntriesBefore(ctx context.Context, before time.Time) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.deleteAuditEntriesBefore(before), nil
+}
+
+func (mt *MemoryTx) CreateAuditEntry(ctx context.Context, entry *models.AuditEntry) error {
+	mt.storage.createAuditEntry(entry)
+	return nil
+}
+
+func (mt *MemoryTx) GetAuditEntries(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error) {
+	return mt.storage.getAuditEntries(filter), nil
+}
+
+func (mt *MemoryTx) DeleteAuditEntriesBefore(ctx context.Context, before time.Time) (int, error) {
+	return mt.storage.deleteAuditEntriesBefore(before), nil
+}
+
+func (m *MemoryStorage) createAuditEntry(entry *models.AuditEntry) {
+	m.auditIDSeq++
+	entry.ID = m.auditIDSeq
+	m.audit = append(m.audit, *entry)
+}
+
+// getAuditEntries обходит журнал с конца: записи в нем упорядочены по ID.
+func (m *MemoryStorage) getAuditEntries(filter models.AuditFilter) []models.AuditEntry {
+	entries := []models.AuditEntry{}
+	for i := len(m.audit) - 1; i >= 0; i-- {
+		e := m.audit[i]
+		if filter.EntityType != "" && e.EntityType != filter.EntityType {
+			continue
+		}
+		if filter.EntityID != 0 && e.EntityID != filter.EntityID {
+			continue
+		}
+		entries = append(entries, e)
+		if filter.Limit > 0 && len(entries) == filter.Limit {
+			break
+		}
+	}
+	return entries
+}
+
+func (m *MemoryStorage) deleteAuditEntriesBefore(before time.Time) int {
+	kept := m.audit[:0]
+	for _, e := range m.audit {
+		if !e.CreatedAt.Before(before) {
+			kept = append(kept, e)
+		}
+	}
+	deleted := len(m.audit) - len(kept)
+	m.audit = kept
+	return deleted
+}
@@ -0,0 +1,212 @@
+package storage
+
+import (
+	"backend-store/internal/models"
+	"context"
+	"sort"
+)
+
+func (m *MemoryStorage) GetProductsByIDs(ctx context.Context, ids []int) ([]*models.Product, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getProductsByIDs(ids), nil
+}
+
+func (m *MemoryStorage) GetOrdersByIDs(ctx context.Context, ids []int) ([]*models.Order, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getOrdersByIDs(ids), nil
+}
+
+func (m *MemoryStorage) GetCustomersByIDs(ctx context.Context, ids []int) ([]*models.Customer, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getCustomersByIDs(ids), nil
+}
+
+func (m *MemoryStorage) GetCategoriesByIDs(ctx context.Context, ids []int) ([]*models.Category, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getCategoriesByIDs(ids), nil
+}
+
+func (m *MemoryStorage) GetCategoryIDsByProducts(ctx context.Context, productIDs []int) (map[int][]int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getCategoryIDsByProducts(productIDs), nil
+}
+
+func (m *MemoryStorage) GetTagsByProducts(ctx context.Context, productIDs []int) (map[int][]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getTagsByProducts(productIDs), nil
+}
+
+func (m *MemoryStorage) GetStockLevelsByProducts(ctx context.Context, productIDs []int) ([]models.StockLevel, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getStockLevelsByProducts(productIDs), nil
+}
+
+func (m *MemoryStorage) GetOrderIDsByProducts(ctx context.Context, productIDs []int) (map[int][]int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getOrderIDsByProducts(productIDs), nil
+}
+
+func (m *MemoryStorage) GetOrderIDsByCustomers(ctx context.Context, customerIDs []int) (map[int][]int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getOrderIDsByCustomers(customerIDs), nil
+}
+
+func (mt *MemoryTx) GetProductsByIDs(ctx context.Context, ids []int) ([]*models.Product, error) {
+	return mt.storage.getProductsByIDs(ids), nil
+}
+
+func (mt *MemoryTx) GetOrdersByIDs(ctx context.Context, ids []int) ([]*models.Order, error) {
+	return mt.storage.getOrdersByIDs(ids), nil
+}
+
+func (mt *MemoryTx) GetCustomersByIDs(ctx context.Context, ids []int) ([]*models.Customer, error) {
+	return mt.storage.getCustomersByIDs(ids), nil
+}
+
+func (mt *MemoryTx) GetCategoriesByIDs(ctx context.Context, ids []int) ([]*models.Category, error) {
+	return mt.storage.getCategoriesByIDs(ids), nil
+}
+
+func (mt *MemoryTx) GetCategoryIDsByProducts(ctx context.Context, productIDs []int) (map[int][]int, error) {
+	return mt.storage.getCategoryIDsByProducts(productIDs), nil
+}
+
+func (mt *MemoryTx) GetTagsByProducts(ctx context.Context, productIDs []int) (map[int][]string, error) {
+	return mt.storage.getTagsByProducts(productIDs), nil
+}
+
+func (mt *MemoryTx) GetStockLevelsByProducts(ctx context.Context, productIDs []int) ([]models.StockLevel, error) {
+	return mt.storage.getStockLevelsByProducts(productIDs), nil
+}
+
+func (mt *MemoryTx) GetOrderIDsByProducts(ctx context.Context, productIDs []int) (map[int][]int, error) {
+	return mt.storage.getOrderIDsByProducts(productIDs), nil
+}
+
+func (mt *MemoryTx) GetOrderIDsByCustomers(ctx context.Context, customerIDs []int) (map[int][]int, error) {
+	return mt.storage.getOrderIDsByCustomers(customerIDs), nil
+}
+
+// idSet возвращает множество ID в порядке возрастания без повторов.
+func idSet(ids []int) []int {
+	seen := make(map[int]bool, len(ids))
+	result := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	sort.Ints(result)
+	return result
+}
+
+func (m *MemoryStorage) getProductsByIDs(ids []int) []*models.Product {
+	products := []*models.Product{}
+	for _, id := range idSet(ids) {
+		if p, exists := m.products[id]; exists {
+			products = append(products, p)
+		}
+	}
+	return products
+}
+
+func (m *MemoryStorage) getOrdersByIDs(ids []int) []*models.Order {
+	orders := []*models.Order{}
+	for _, id := range idSet(ids) {
+		if o, exists := m.orders[id]; exists {
+			orders = append(orders, o)
+		}
+	}
+	return orders
+}
+
+func (m *MemoryStorage) getCustomersByIDs(ids []int) []*models.Customer {
+	customers := []*models.Customer{}
+	for _, id := range idSet(ids) {
+		if c, exists := m.customers[id]; exists {
+			customers = append(customers, m.copyCustomer(c))
+		}
+	}
+	return customers
+}
+
+func (m *MemoryStorage) getCategoriesByIDs(ids []int) []*models.Category {
+	categories := []*models.Category{}
+	for _, id := range idSet(ids) {
+		if c, exists := m.categories[id]; exists {
+			categories = append(categories, c)
+		}
+	}
+	return categories
+}
+
+func (m *MemoryStorage) getCategoryIDsByProducts(productIDs []int) map[int][]int {
+	result := make(map[int][]int)
+	for _, id := range idSet(productIDs) {
+		if ids := m.getProductCategoryIDs(id); len(ids) > 0 {
+			sort.Ints(ids)
+			result[id] = ids
+		}
+	}
+	return result
+}
+
+func (m *MemoryStorage) getTagsByProducts(productIDs []int) map[int][]string {
+	result := make(map[int][]string)
+	for _, id := range idSet(productIDs) {
+		if tags := m.getProductTags(id); len(tags) > 0 {
+			result[id] = tags
+		}
+	}
+	return result
+}
+
+func (m *MemoryStorage) getStockLevelsByProducts(productIDs []int) []models.StockLevel {
+	wanted := make(map[int]bool, len(productIDs))
+	for _, id := range productIDs {
+		wanted[id] = true
+	}
+	return m.getStockLevels(func(l *models.StockLevel) bool { return wanted[l.ProductID] })
+}
+
+func (m *MemoryStorage) getOrderIDsByProducts(productIDs []int) map[int][]int {
+	wanted := make(map[int]bool, len(productIDs))
+	for _, id := range productIDs {
+		wanted[id] = true
+	}
+	result := make(map[int][]int)
+	for _, o := range m.getAllOrders() {
+		added := make(map[int]bool)
+		for _, item := range o.Products {
+			if wanted[item.ProductID] && !added[item.ProductID] {
+				added[item.ProductID] = true
+				result[item.ProductID] = append(result[item.ProductID], o.ID)
+			}
+		}
+	}
+	return result
+}
+
+func (m *MemoryStorage) getOrderIDsByCustomers(customerIDs []int) map[int][]int {
+	wanted := make(map[int]bool, len(customerIDs))
+	for _, id := range customerIDs {
+		wanted[id] = true
+	}
+	result := make(map[int][]int)
+	for _, o := range m.getAllOrders() {
+		if wanted[o.CustomerID] {
+			result[o.CustomerID] = append(result[o.CustomerID], o.ID)
+		}
+	}
+	return result
+}
@@ -0,0 +1,250 @@
+package storage
+
+import (
+	"backend-store/internal/models"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+func (m *MemoryStorage) CreateCart(ctx context.Context, cart *models.Cart) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.createCart(cart)
+}
+
+func (m *MemoryStorage) GetCartByID(ctx context.Context, id int) (*models.Cart, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getCartByID(id)
+}
+
+func (m *MemoryStorage) GetActiveCartByUser(ctx context.Context, userID int) (*models.Cart, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getActiveCartByUser(userID)
+}
+
+func (m *MemoryStorage) UpdateCart(ctx context.Context, cart *models.Cart) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.updateCart(cart)
+}
+
+func (m *MemoryStorage) DeleteCart(ctx context.Context, id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.deleteCart(id)
+}
+
+func (m *MemoryStorage) AddCartItem(ctx context.Context, item *models.CartItem) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.addCartItem(item)
+}
+
+func (m *MemoryStorage) UpdateCartItem(ctx context.Context, item *models.CartItem) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.updateCartItem(item)
+}
+
+func (m *MemoryStorage) DeleteCartItem(ctx context.Context, id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.deleteCartItem(id)
+}
+
+func (m *MemoryStorage) DeleteExpiredCarts(ctx context.Context, before time.Time) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.deleteExpiredCarts(before), nil
+}
+
+func (mt *MemoryTx) CreateCart(ctx context.Context, cart *models.Cart) error {
+	return mt.storage.createCart(cart)
+}
+
+func (mt *MemoryTx) GetCartByID(ctx context.Context, id int) (*models.Cart, error) {
+	return mt.storage.getCartByID(id)
+}
+
+func (mt *MemoryTx) GetActiveCartByUser(ctx context.Context, userID int) (*models.Cart, error) {
+	return mt.storage.getActiveCartByUser(userID)
+}
+
+func (mt *MemoryTx) UpdateCart(ctx context.Context, cart *models.Cart) error {
+	return mt.storage.updateCart(cart)
+}
+
+func (mt *MemoryTx) DeleteCart(ctx context.Context, id int) error {
+	return mt.storage.deleteCart(id)
+}
+
+func (mt *MemoryTx) AddCartItem(ctx context.Context, item *models.CartItem) error {
+	return mt.storage.addCartItem(item)
+}
+
+func (mt *MemoryTx) UpdateCartItem(ctx context.Context, item *models.CartItem) error {
+	return mt.storage.updateCartItem(item)
+}
+
+func (mt *MemoryTx) DeleteCartItem(ctx context.Context, id int) error {
+	return mt.storage.deleteCartItem(id)
+}
+
+func (mt *MemoryTx) DeleteExpiredCarts(ctx context.Context, before time.Time) (int, error) {
+	return mt.storage.deleteExpiredCarts(before), nil
+}
+
+func (m *MemoryStorage) createCart(cart *models.Cart) error {
+	if err := m.checkCart(cart); err != nil {
+		return err
+	}
+	m.cartIDSeq++
+	cart.ID = m.cartIDSeq
+	stored := *cart
+	stored.Items = nil
+	m.carts[cart.ID] = &stored
+	return nil
+}
+
+// checkCart повторяет уникальные индексы PostgreSQL: токен корзины уникален,
+// у пользователя не больше одной активной корзины.
+func (m *MemoryStorage) checkCart(cart *models.Cart) error {
+	for _, c := range m.carts {
+		if c.ID == cart.ID {
+			continue
+		}
+		if c.Token == cart.Token {
+			return errors.New("cart token already exists")
+		}
+		if cart.UserID > 0 && c.UserID == cart.UserID &&
+			c.Status == models.CartActive && cart.Status == models.CartActive {
+			return fmt.Errorf("active cart for user %d already exists", cart.UserID)
+		}
+	}
+	return nil
+}
+
+func (m *MemoryStorage) getCartByID(id int) (*models.Cart, error) {
+	stored, exists := m.carts[id]
+	if !exists {
+		return nil, notFound("cart")
+	}
+	cart := *stored
+	cart.Items = []models.CartItem{}
+	for _, item := range m.cartItems {
+		if item.CartID == id {
+			cart.Items = append(cart.Items, *item)
+		}
+	}
+	sort.Slice(cart.Items, func(i, j int) bool { return cart.Items[i].ID < cart.Items[j].ID })
+	return &cart, nil
+}
+
+func (m *MemoryStorage) getActiveCartByUser(userID int) (*models.Cart, error) {
+	for _, c := range m.carts {
+		if c.UserID == userID && c.Status == models.CartActive {
+			return m.getCartByID(c.ID)
+		}
+	}
+	return nil, notFound("cart")
+}
+
+func (m *MemoryStorage) updateCart(cart *models.Cart) error {
+	stored, exists := m.carts[cart.ID]
+	if !exists {
+		return notFound("cart")
+	}
+	if err := m.checkCart(cart); err != nil {
+		return err
+	}
+	stored.UserID = cart.UserID
+	stored.Status = cart.Status
+	stored.OrderID = cart.OrderID
+	stored.ExpiresAt = cart.ExpiresAt
+	stored.UpdatedAt = cart.UpdatedAt
+	return nil
+}
+
+func (m *MemoryStorage) deleteCart(id int) error {
+	if _, exists := m.carts[id]; !exists {
+		return notFound("cart")
+	}
+	delete(m.carts, id)
+	m.deleteCartItems(func(item *models.CartItem) bool { return item.CartID == id })
+	return nil
+}
+
+func (m *MemoryStorage) addCartItem(item *models.CartItem) error {
+	if _, exists := m.carts[item.CartID]; !exists {
+		return notFound("cart")
+	}
+	if _, exists := m.products[item.ProductID]; !exists {
+		return notFound("product")
+	}
+	if _, exists := m.variants[item.VariantID]; item.VariantID > 0 && !exists {
+		return notFound("variant")
+	}
+	for _, i := range m.cartItems {
+		if i.CartID == item.CartID && i.ProductID == item.ProductID && i.VariantID == item.VariantID {
+			return errors.New("cart item already exists")
+		}
+	}
+	m.cartItemIDSeq++
+	item.ID = m.cartItemIDSeq
+	item.CreatedAt = time.Now()
+	stored := *item
+	m.cartItems[item.ID] = &stored
+	return nil
+}
+
+func (m *MemoryStorage) updateCartItem(item *models.CartItem) error {
+	stored, exists := m.cartItems[item.ID]
+	if !exists {
+		return notFound("cart item")
+	}
+	stored.Quantity = item.Quantity
+	return nil
+}
+
+func (m *MemoryStorage) deleteCartItem(id int) error {
+	if _, exists := m.cartItems[id]; !exists {
+		return notFound("cart item")
+	}
+	delete(m.cartItems, id)
+	return nil
+}
+
+func (m *MemoryStorage) deleteExpiredCarts(before time.Time) int {
+	deleted := 0
+	for id, c := range m.carts {
+		if c.Status == models.CartActive && c.ExpiresAt.Before(before) {
+			m.deleteCart(id)
+			deleted++
+		}
+	}
+	return deleted
+}
+
+// deleteCartItems повторяет ON DELETE CASCADE позиций корзин при удалении
+// корзины, товара или варианта.
+func (m *MemoryStorage) deleteCartItems(match func(*models.CartItem) bool) {
+	for id, item := range m.cartItems {
+		if match(item) {
+			delete(m.cartItems, id)
+		}
+	}
+}
+
+// clearCartOrder повторяет ON DELETE SET NULL ссылки корзины на заказ.
+func (m *MemoryStorage) clearCartOrder(orderID int) {
+	for _, c := range m.carts {
+		if c.OrderID == orderID {
+			c.OrderID = 0
+		}
+	}
+}
@@ -0,0 +1,314 @@
+package storage
+
+import (
+	"backend-store/internal/models"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+func (m *MemoryStorage) CreateCategory(ctx context.Context, category *models.Category) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.createCategory(category)
+}
+
+func (m *MemoryStorage) GetCategoryByID(ctx context.Context, id int) (*models.Category, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getCategoryByID(id)
+}
+
+func (m *MemoryStorage) GetAllCategories(ctx context.Context) ([]*models.Category, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getAllCategories(), nil
+}
+
+func (m *MemoryStorage) UpdateCategory(ctx context.Context, category *models.Category) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.updateCategory(category)
+}
+
+func (m *MemoryStorage) DeleteCategory(ctx context.Context, id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.deleteCategory(id)
+}
+
+func (m *MemoryStorage) GetProductCategoryIDs(ctx context.Context, productID int) ([]int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getProductCategoryIDs(productID), nil
+}
+
+func (m *MemoryStorage) SetProductCategories(ctx context.Context, productID int, categoryIDs []int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.setProductCategories(productID, categoryIDs)
+}
+
+func (m *MemoryStorage) GetProductIDsByCategories(ctx context.Context, categoryIDs []int) ([]int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getProductIDsByCategories(categoryIDs), nil
+}
+
+func (m *MemoryStorage) ReassignCategoryProducts(ctx context.Context, fromID, toID int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.reassignCategoryProducts(fromID, toID)
+	return nil
+}
+
+func (m *MemoryStorage) GetProductTags(ctx context.Context, productID int) ([]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getProductTags(productID), nil
+}
+
+func (m *MemoryStorage) SetProductTags(ctx context.Context, productID int, tags []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.setProductTags(productID, tags)
+}
+
+func (m *MemoryStorage) GetProductIDsByTag(ctx context.Context, tag string) ([]int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getProductIDsByTag(tag), nil
+}
+
+func (m *MemoryStorage) GetAllTags(ctx context.Context) ([]models.TagCount, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getAllTags(), nil
+}
+
+func (mt *MemoryTx) CreateCategory(ctx context.Context, category *models.Category) error {
+	return mt.storage.createCategory(category)
+}
+
+func (mt *MemoryTx) GetCategoryByID(ctx context.Context, id int) (*models.Category, error) {
+	return mt.storage.getCategoryByID(id)
+}
+
+func (mt *MemoryTx) GetAllCategories(ctx context.Context) ([]*models.Category, error) {
+	return mt.storage.getAllCategories(), nil
+}
+
+func (mt *MemoryTx) UpdateCategory(ctx context.Context, category *models.Category) error {
+	return mt.storage.updateCategory(category)
+}
+
+func (mt *MemoryTx) DeleteCategory(ctx context.Context, id int) error {
+	return mt.storage.deleteCategory(id)
+}
+
+func (mt *MemoryTx) GetProductCategoryIDs(ctx context.Context, productID int) ([]int, error) {
+	return mt.storage.getProductCategoryIDs(productID), nil
+}
+
+func (mt *MemoryTx) SetProductCategories(ctx context.Context, productID int, categoryIDs []int) error {
+	return mt.storage.setProductCategories(productID, categoryIDs)
+}
+
+func (mt *MemoryTx) GetProductIDsByCategories(ctx context.Context, categoryIDs []int) ([]int, error) {
+	return mt.storage.getProductIDsByCategories(categoryIDs), nil
+}
+
+func (mt *MemoryTx) ReassignCategoryProducts(ctx context.Context, fromID, toID int) error {
+	mt.storage.reassignCategoryProducts(fromID, toID)
+	return nil
+}
+
+func (mt *MemoryTx) GetProductTags(ctx context.Context, productID int) ([]string, error) {
+	return mt.storage.getProductTags(productID), nil
+}
+
+func (mt *MemoryTx) SetProductTags(ctx context.Context, productID int, tags []string) error {
+	return mt.storage.setProductTags(productID, tags)
+}
+
+func (mt *MemoryTx) GetProductIDsByTag(ctx context.Context, tag string) ([]int, error) {
+	return mt.storage.getProductIDsByTag(tag), nil
+}
+
+func (mt *MemoryTx) GetAllTags(ctx context.Context) ([]models.TagCount, error) {
+	return mt.storage.getAllTags(), nil
+}
+
+func (m *MemoryStorage) createCategory(category *models.Category) error {
+	if err := m.checkCategorySlug(category); err != nil {
+		return err
+	}
+	m.categoryIDSeq++
+	category.ID = m.categoryIDSeq
+	m.categories[category.ID] = category
+	return nil
+}
+
+func (m *MemoryStorage) getCategoryByID(id int) (*models.Category, error) {
+	category, exists := m.categories[id]
+	if !exists {
+		return nil, notFound("category")
+	}
+	return category, nil
+}
+
+func (m *MemoryStorage) getAllCategories() []*models.Category {
+	categories := make([]*models.Category, 0, len(m.categories))
+	for _, c := range m.categories {
+		categories = append(categories, c)
+	}
+	sort.Slice(categories, func(i, j int) bool { return categories[i].ID < categories[j].ID })
+	return categories
+}
+
+func (m *MemoryStorage) updateCategory(category *models.Category) error {
+	if _, exists := m.categories[category.ID]; !exists {
+		return notFound("category")
+	}
+	if err := m.checkCategorySlug(category); err != nil {
+		return err
+	}
+	m.categories[category.ID] = category
+	return nil
+}
+
+// deleteCategory повторяет ограничения внешних ключей PostgreSQL:
+// подкатегории запрещают удаление, связи с товарами удаляются каскадно.
+func (m *MemoryStorage) deleteCategory(id int) error {
+	if _, exists := m.categories[id]; !exists {
+		return notFound("category")
+	}
+	for _, c := range m.categories {
+		if c.ParentID != nil && *c.ParentID == id {
+			return errors.New("cannot delete category with subcategories")
+		}
+	}
+	delete(m.categories, id)
+	for productID, ids := range m.productCategories {
+		m.productCategories[productID] = removeInt(ids, id)
+	}
+	return nil
+}
+
+func (m *MemoryStorage) checkCategorySlug(category *models.Category) error {
+	for _, c := range m.categories {
+		if c.Slug == category.Slug && c.ID != category.ID {
+			return fmt.Errorf("category with slug %q already exists", category.Slug)
+		}
+	}
+	return nil
+}
+
+func (m *MemoryStorage) getProductCategoryIDs(productID int) []int {
+	return append([]int{}, m.productCategories[productID]...)
+}
+
+func (m *MemoryStorage) setProductCategories(productID int, categoryIDs []int) error {
+	if _, exists := m.products[productID]; !exists {
+		return notFound("product")
+	}
+	ids := make([]int, 0, len(categoryIDs))
+	for _, id := range categoryIDs {
+		if _, exists := m.categories[id]; !exists {
+			return notFound("category")
+		}
+		if !containsInt(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	m.productCategories[productID] = ids
+	return nil
+}
+
+func (m *MemoryStorage) getProductIDsByCategories(categoryIDs []int) []int {
+	ids := []int{}
+	for productID, linked := range m.productCategories {
+		for _, id := range linked {
+			if containsInt(categoryIDs, id) {
+				ids = append(ids, productID)
+				break
+			}
+		}
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func (m *MemoryStorage) reassignCategoryProducts(fromID, toID int) {
+	for productID, ids := range m.productCategories {
+		if containsInt(ids, fromID) && !containsInt(ids, toID) {
+			ids = append(ids, toID)
+			sort.Ints(ids)
+			m.productCategories[productID] = ids
+		}
+	}
+}
+
+func (m *MemoryStorage) getProductTags(productID int) []string {
+	tags := append([]string{}, m.productTags[productID]...)
+	sort.Strings(tags)
+	return tags
+}
+
+func (m *MemoryStorage) setProductTags(productID int, tags []string) error {
+	if _, exists := m.products[productID]; !exists {
+		return notFound("product")
+	}
+	m.productTags[productID] = append([]string{}, tags...)
+	return nil
+}
+
+func (m *MemoryStorage) getProductIDsByTag(tag string) []int {
+	ids := []int{}
+	for productID, tags := range m.productTags {
+		for _, t := range tags {
+			if t == tag {
+				ids = append(ids, productID)
+				break
+			}
+		}
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func (m *MemoryStorage) getAllTags() []models.TagCount {
+	counts := make(map[string]int)
+	for _, tags := range m.productTags {
+		for _, t := range tags {
+			counts[t]++
+		}
+	}
+	result := make([]models.TagCount, 0, len(counts))
+	for tag, n := range counts {
+		result = append(result, models.TagCount{Tag: tag, Products: n})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Tag < result[j].Tag })
+	return result
+}
+
+func containsInt(values []int, v int) bool {
+	for _, x := range values {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+func removeInt(values []int, v int) []int {
+	result := values[:0]
+	for _, x := range values {
+		if x != v {
+			result = append(result, x)
+		}
+	}
+	return result
+}
@@ -0,0 +1,168 @@
+package storage
+
+import (
+	"backend-store/internal/models"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+func (m *MemoryStorage) CreateCustomer(ctx context.Context, customer *models.Customer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.createCustomer(customer)
+}
+
+func (m *MemoryStorage) GetCustomerByID(ctx context.Context, id int) (*models.Customer, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getCustomerByID(id)
+}
+
+func (m *MemoryStorage) GetCustomerByEmail(ctx context.Context, email string) (*models.Customer, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getCustomerByEmail(email)
+}
+
+func (m *MemoryStorage) GetAllCustomers(ctx context.Context) ([]*models.Customer, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getAllCustomers(), nil
+}
+
+func (m *MemoryStorage) UpdateCustomer(ctx context.Context, customer *models.Customer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.updateCustomer(customer)
+}
+
+func (m *MemoryStorage) DeleteCustomer(ctx context.Context, id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.deleteCustomer(id)
+}
+
+func (mt *MemoryTx) CreateCustomer(ctx context.Context, customer *models.Customer) error {
+	return mt.storage.createCustomer(customer)
+}
+
+func (mt *MemoryTx) GetCustomerByID(ctx context.Context, id int) (*models.Customer, error) {
+	return mt.storage.getCustomerByID(id)
+}
+
+func (mt *MemoryTx) GetCustomerByEmail(ctx context.Context, email string) (*models.Customer, error) {
+	return mt.storage.getCustomerByEmail(email)
+}
+
+func (mt *MemoryTx) GetAllCustomers(ctx context.Context) ([]*models.Customer, error) {
+	return mt.storage.getAllCustomers(), nil
+}
+
+func (mt *MemoryTx) UpdateCustomer(ctx context.Context, customer *models.Customer) error {
+	return mt.storage.updateCustomer(customer)
+}
+
+func (mt *MemoryTx) DeleteCustomer(ctx context.Context, id int) error {
+	return mt.storage.deleteCustomer(id)
+}
+
+func (m *MemoryStorage) createCustomer(customer *models.Customer) error {
+	if err := m.checkCustomerEmail(customer); err != nil {
+		return err
+	}
+	m.customerIDSeq++
+	customer.ID = m.customerIDSeq
+	m.assignAddressIDs(customer)
+	m.customers[customer.ID] = m.copyCustomer(customer)
+	return nil
+}
+
+// assignAddressIDs повторяет поведение SERIAL для customer_addresses.
+func (m *MemoryStorage) assignAddressIDs(customer *models.Customer) {
+	now := time.Now()
+	for i := range customer.Addresses {
+		m.addressIDSeq++
+		customer.Addresses[i].ID = m.addressIDSeq
+		customer.Addresses[i].CustomerID = customer.ID
+		customer.Addresses[i].CreatedAt = now
+	}
+}
+
+func (m *MemoryStorage) copyCustomer(customer *models.Customer) *models.Customer {
+	c := *customer
+	c.Addresses = append([]models.CustomerAddress{}, customer.Addresses...)
+	return &c
+}
+
+func (m *MemoryStorage) getCustomerByID(id int) (*models.Customer, error) {
+	customer, exists := m.customers[id]
+	if !exists {
+		return nil, notFound("customer")
+	}
+	return m.copyCustomer(customer), nil
+}
+
+func (m *MemoryStorage) getCustomerByEmail(email string) (*models.Customer, error) {
+	for _, c := range m.customers {
+		if c.Email == email {
+			return m.copyCustomer(c), nil
+		}
+	}
+	return nil, notFound("customer")
+}
+
+func (m *MemoryStorage) getAllCustomers() []*models.Customer {
+	customers := make([]*models.Customer, 0, len(m.customers))
+	for _, c := range m.customers {
+		customers = append(customers, m.copyCustomer(c))
+	}
+	sort.Slice(customers, func(i, j int) bool { return customers[i].ID < customers[j].ID })
+	return customers
+}
+
+func (m *MemoryStorage) updateCustomer(customer *models.Customer) error {
+	if _, exists := m.customers[customer.ID]; !exists {
+		return notFound("customer")
+	}
+	if err := m.checkCustomerEmail(customer); err != nil {
+		return err
+	}
+	m.assignAddressIDs(customer)
+	m.customers[customer.ID] = m.copyCustomer(customer)
+	return nil
+}
+
+// deleteCustomer повторяет внешние ключи PostgreSQL: заказы запрещают
+// удаление покупателя.
+func (m *MemoryStorage) deleteCustomer(id int) error {
+	if _, exists := m.customers[id]; !exists {
+		return notFound("customer")
+	}
+	for _, o := range m.orders {
+		if o.CustomerID == id {
+			return errors.New("cannot delete customer with orders")
+		}
+	}
+	delete(m.customers, id)
+	return nil
+}
+
+func (m *MemoryStorage) checkCustomerEmail(customer *models.Customer) error {
+	for _, c := range m.customers {
+		if c.Email == customer.Email && c.ID != customer.ID {
+			return fmt.Errorf("customer with email %q already exists", customer.Email)
+		}
+	}
+	return nil
+}
+
+// checkOrderCustomer повторяет внешний ключ заказа на покупателя.
+func (m *MemoryStorage) checkOrderCustomer(order *models.Order) error {
+	if _, exists := m.customers[order.CustomerID]; !exists {
+		return notFound("customer")
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"backend-store/internal/models"
+	"context"
+)
+
+// Обход в памяти работает по снимку списка: блокировка не удерживается,
+// пока fn пишет данные клиенту.
+
+func (m *MemoryStorage) IterateProducts(ctx context.Context, fn func(*models.Product) error) error {
+	m.mu.RLock()
+	products := m.getAllProducts()
+	m.mu.RUnlock()
+	return iterateMemoryProducts(ctx, products, fn)
+}
+
+func (m *MemoryStorage) IterateOrders(ctx context.Context, filter models.OrderFilter, fn func(*models.Order) error) error {
+	m.mu.RLock()
+	orders := m.getAllOrders()
+	m.mu.RUnlock()
+	return iterateMemoryOrders(ctx, orders, filter, fn)
+}
+
+func (mt *MemoryTx) IterateProducts(ctx context.Context, fn func(*models.Product) error) error {
+	return iterateMemoryProducts(ctx, mt.storage.getAllProducts(), fn)
+}
+
+func (mt *MemoryTx) IterateOrders(ctx context.Context, filter models.OrderFilter, fn func(*models.Order) error) error {
+	return iterateMemoryOrders(ctx, mt.storage.getAllOrders(), filter, fn)
+}
+
+func iterateMemoryProducts(ctx context.Context, products []*models.Product, fn func(*models.Product) error) error {
+	for _, p := range products {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func iterateMemoryOrders(ctx context.Context, orders []*models.Order, filter models.OrderFilter, fn func(*models.Order) error) error {
+	for _, o := range orders {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !filter.Match(o) {
+			continue
+		}
+		if err := fn(o); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"backend-store/internal/models"
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+func (m *MemoryStorage) CreateLowStockAlert(ctx context.Context, alert *models.LowStockAlert) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.createLowStockAlert(alert)
+}
+
+func (m *MemoryStorage) GetOpenLowStockAlerts(ctx context.Context) ([]models.LowStockAlert, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getOpenLowStockAlerts(), nil
+}
+
+func (m *MemoryStorage) MarkLowStockAlertNotified(ctx context.Context, id int, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.updateLowStockAlert(id, func(a *models.LowStockAlert) { a.NotifiedAt = &at })
+}
+
+func (m *MemoryStorage) ResolveLowStockAlert(ctx context.Context, id int, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.updateLowStockAlert(id, func(a *models.LowStockAlert) { a.ResolvedAt = &at })
+}
+
+func (mt *MemoryTx) CreateLowStockAlert(ctx context.Context, alert *models.LowStockAlert) error {
+	return mt.storage.createLowStockAlert(alert)
+}
+
+func (mt *MemoryTx) GetOpenLowStockAlerts(ctx context.Context) ([]models.LowStockAlert, error) {
+	return mt.storage.getOpenLowStockAlerts(), nil
+}
+
+func (mt *MemoryTx) MarkLowStockAlertNotified(ctx context.Context, id int, at time.Time) error {
+	return mt.storage.updateLowStockAlert(id, func(a *models.LowStockAlert) { a.NotifiedAt = &at })
+}
+
+func (mt *MemoryTx) ResolveLowStockAlert(ctx context.Context, id int, at time.Time) error {
+	return mt.storage.updateLowStockAlert(id, func(a *models.LowStockAlert) { a.ResolvedAt = &at })
+}
+
+// createLowStockAlert повторяет частичный уникальный индекс PostgreSQL по
+// открытым предупреждениям товара.
+func (m *MemoryStorage) createLowStockAlert(alert *models.LowStockAlert) error {
+	if _, exists := m.products[alert.ProductID]; !exists {
+		return notFound("product")
+	}
+	for _, a := range m.lowStockAlerts {
+		if a.ProductID == alert.ProductID && a.ResolvedAt == nil {
+			return fmt.Errorf("low stock alert for product %d already exists", alert.ProductID)
+		}
+	}
+	m.lowStockAlertIDSeq++
+	alert.ID = m.lowStockAlertIDSeq
+	alert.CreatedAt = time.Now()
+	alert.NotifiedAt = nil
+	alert.ResolvedAt = nil
+	stored := *alert
+	m.lowStockAlerts[alert.ID] = &stored
+	return nil
+}
+
+func (m *MemoryStorage) getOpenLowStockAlerts() []models.LowStockAlert {
+	alerts := []models.LowStockAlert{}
+	for _, a := range m.lowStockAlerts {
+		if a.ResolvedAt == nil {
+			alerts = append(alerts, *a)
+		}
+	}
+	sort.Slice(alerts, func(i, j int) bool { return alerts[i].ID < alerts[j].ID })
+	return alerts
+}
+
+func (m *MemoryStorage) updateLowStockAlert(id int, apply func(*models.LowStockAlert)) error {
+	alert, exists := m.lowStockAlerts[id]
+	if !exists || alert.ResolvedAt != nil {
+		return notFound("low stock alert")
+	}
+	apply(alert)
+	return nil
+}
+
+// deleteProductAlerts повторяет ON DELETE CASCADE для предупреждений товара.
+func (m *MemoryStorage) deleteProductAlerts(productID int) {
+	for id, a := range m.lowStockAlerts {
+		if a.ProductID == productID {
+			delete(m.lowStockAlerts, id)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"backend-store/internal/models"
+	"context"
+	"errors"
+	"sort"
+	"time"
+)
+
+func (m *MemoryStorage) GetAllStockLevels(ctx context.Context) ([]models.StockLevel, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getStockLevels(func(*models.StockLevel) bool { return true }), nil
+}
+
+func (m *MemoryStorage) CreateStockMovement(ctx context.Context, movement *models.StockMovement) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.createStockMovement(movement)
+}
+
+func (m *MemoryStorage) GetStockMovementsByProduct(ctx context.Context, productID int) ([]models.StockMovement, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getStockMovementsByProduct(productID), nil
+}
+
+func (m *MemoryStorage) GetStockLedgerBalances(ctx context.Context) ([]models.StockBalance, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getStockLedgerBalances(), nil
+}
+
+func (mt *MemoryTx) GetAllStockLevels(ctx context.Context) ([]models.StockLevel, error) {
+	return mt.storage.getStockLevels(func(*models.StockLevel) bool { return true }), nil
+}
+
+func (mt *MemoryTx) CreateStockMovement(ctx context.Context, movement *models.StockMovement) error {
+	return mt.storage.createStockMovement(movement)
+}
+
+func (mt *MemoryTx) GetStockMovementsByProduct(ctx context.Context, productID int) ([]models.StockMovement, error) {
+	return mt.storage.getStockMovementsByProduct(productID), nil
+}
+
+func (mt *MemoryTx) GetStockLedgerBalances(ctx context.Context) ([]models.StockBalance, error) {
+	return mt.storage.getStockLedgerBalances(), nil
+}
+
+// Журнал хранится срезом в порядке добавления; записи не изменяются и не
+// удаляются, в том числе при удалении товара или варианта.
+func (m *MemoryStorage) createStockMovement(movement *models.StockMovement) error {
+	if movement.Delta == 0 {
+		return errors.New("invalid stock movement: delta cannot be zero")
+	}
+	if _, exists := m.warehouses[movement.WarehouseID]; !exists {
+		return notFound("warehouse")
+	}
+	m.movementIDSeq++
+	movement.ID = m.movementIDSeq
+	movement.CreatedAt = time.Now()
+	stored := *movement
+	m.movements = append(m.movements, &stored)
+	return nil
+}
+
+func (m *MemoryStorage) getStockMovementsByProduct(productID int) []models.StockMovement {
+	movements := []models.StockMovement{}
+	for _, mv := range m.movements {
+		if mv.ProductID == productID {
+			movements = append(movements, *mv)
+		}
+	}
+	return movements
+}
+
+func (m *MemoryStorage) getStockLedgerBalances() []models.StockBalance {
+	balances := make(map[stockKey]*models.StockBalance)
+	previous := make(map[stockKey]int)
+	for _, mv := range m.movements {
+		key := stockKey{warehouseID: mv.WarehouseID, variantID: mv.VariantID}
+		b, exists := balances[key]
+		if !exists {
+			b = &models.StockBalance{WarehouseID: mv.WarehouseID, VariantID: mv.VariantID, ProductID: mv.ProductID}
+			balances[key] = b
+		}
+		b.Quantity += mv.Delta
+		if previous[key]+mv.Delta != mv.BalanceAfter {
+			b.BrokenChain++
+		}
+		previous[key] = mv.BalanceAfter
+	}
+
+	result := make([]models.StockBalance, 0, len(balances))
+	for _, b := range balances {
+		result = append(result, *b)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].WarehouseID != result[j].WarehouseID {
+			return result[i].WarehouseID < result[j].WarehouseID
+		}
+		return result[i].VariantID < result[j].VariantID
+	})
+	return result
+}
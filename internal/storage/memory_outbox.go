@@ -0,0 +1,123 @@
+package storage
+
+import (
+	"backend-store/internal/models"
+	"context"
+	"sort"
+	"time"
+)
+
+func (m *MemoryStorage) CreateOutboxEvent(ctx context.Context, event *models.OutboxEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.createOutboxEvent(event)
+}
+
+func (m *MemoryStorage) GetOutboxEventByID(ctx context.Context, id int) (*models.OutboxEvent, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getOutboxEventByID(id)
+}
+
+func (m *MemoryStorage) GetOutboxEvents(ctx context.Context, filter models.OutboxFilter) ([]models.OutboxEvent, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getOutboxEvents(filter), nil
+}
+
+func (m *MemoryStorage) ClaimOutboxEvents(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.OutboxEvent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.claimOutboxEvents(now, lease, limit), nil
+}
+
+func (m *MemoryStorage) UpdateOutboxEvent(ctx context.Context, event *models.OutboxEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.updateOutboxEvent(event)
+}
+
+func (mt *MemoryTx) CreateOutboxEvent(ctx context.Context, event *models.OutboxEvent) error {
+	return mt.storage.createOutboxEvent(event)
+}
+
+func (mt *MemoryTx) GetOutboxEventByID(ctx context.Context, id int) (*models.OutboxEvent, error) {
+	return mt.storage.getOutboxEventByID(id)
+}
+
+func (mt *MemoryTx) GetOutboxEvents(ctx context.Context, filter models.OutboxFilter) ([]models.OutboxEvent, error) {
+	return mt.storage.getOutboxEvents(filter), nil
+}
+
+func (mt *MemoryTx) ClaimOutboxEvents(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.OutboxEvent, error) {
+	return mt.storage.claimOutboxEvents(now, lease, limit), nil
+}
+
+func (mt *MemoryTx) UpdateOutboxEvent(ctx context.Context, event *models.OutboxEvent) error {
+	return mt.storage.updateOutboxEvent(event)
+}
+
+func (m *MemoryStorage) createOutboxEvent(event *models.OutboxEvent) error {
+	m.outboxIDSeq++
+	event.ID = m.outboxIDSeq
+	stored := *event
+	m.outbox[event.ID] = &stored
+	return nil
+}
+
+func (m *MemoryStorage) getOutboxEventByID(id int) (*models.OutboxEvent, error) {
+	event, exists := m.outbox[id]
+	if !exists {
+		return nil, notFound("outbox event")
+	}
+	result := *event
+	return &result, nil
+}
+
+func (m *MemoryStorage) getOutboxEvents(filter models.OutboxFilter) []models.OutboxEvent {
+	events := []models.OutboxEvent{}
+	for _, e := range m.outbox {
+		if filter.Status != "" && e.Status != filter.Status {
+			continue
+		}
+		if filter.Type != "" && e.Type != filter.Type {
+			continue
+		}
+		events = append(events, *e)
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].ID > events[j].ID })
+	if filter.Limit > 0 && len(events) > filter.Limit {
+		events = events[:filter.Limit]
+	}
+	return events
+}
+
+// claimOutboxEvents выдает события в порядке записи.
+func (m *MemoryStorage) claimOutboxEvents(now time.Time, lease time.Duration, limit int) []models.OutboxEvent {
+	var due []*models.OutboxEvent
+	for _, e := range m.outbox {
+		if e.Status == models.OutboxPending && !e.NextAttemptAt.After(now) {
+			due = append(due, e)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].ID < due[j].ID })
+	if limit > 0 && len(due) > limit {
+		due = due[:limit]
+	}
+
+	events := make([]models.OutboxEvent, 0, len(due))
+	for _, e := range due {
+		e.NextAttemptAt = now.Add(lease)
+		events = append(events, *e)
+	}
+	return events
+}
+
+func (m *MemoryStorage) updateOutboxEvent(event *models.OutboxEvent) error {
+	if _, exists := m.outbox[event.ID]; !exists {
+		return notFound("outbox event")
+	}
+	stored := *event
+	m.outbox[event.ID] = &stored
+	return nil
+}
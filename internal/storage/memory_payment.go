@@ -0,0 +1,175 @@
+package storage
+
+import (
+	"backend-store/internal/models"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+func (m *MemoryStorage) CreatePayment(ctx context.Context, payment *models.Payment) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.createPayment(payment)
+}
+
+func (m *MemoryStorage) GetPaymentByID(ctx context.Context, id int) (*models.Payment, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getPaymentByID(id)
+}
+
+func (m *MemoryStorage) GetPaymentByReference(ctx context.Context, provider, reference string) (*models.Payment, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getPaymentByReference(provider, reference)
+}
+
+func (m *MemoryStorage) GetPaymentsByOrder(ctx context.Context, orderID int) ([]models.Payment, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getPaymentsByOrder(orderID), nil
+}
+
+func (m *MemoryStorage) UpdatePayment(ctx context.Context, payment *models.Payment) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.updatePayment(payment)
+}
+
+func (m *MemoryStorage) AddPaymentRefund(ctx context.Context, id, delta int) (*models.Payment, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.addPaymentRefund(id, delta)
+}
+
+func (mt *MemoryTx) CreatePayment(ctx context.Context, payment *models.Payment) error {
+	return mt.storage.createPayment(payment)
+}
+
+func (mt *MemoryTx) GetPaymentByID(ctx context.Context, id int) (*models.Payment, error) {
+	return mt.storage.getPaymentByID(id)
+}
+
+func (mt *MemoryTx) GetPaymentByReference(ctx context.Context, provider, reference string) (*models.Payment, error) {
+	return mt.storage.getPaymentByReference(provider, reference)
+}
+
+func (mt *MemoryTx) GetPaymentsByOrder(ctx context.Context, orderID int) ([]models.Payment, error) {
+	return mt.storage.getPaymentsByOrder(orderID), nil
+}
+
+func (mt *MemoryTx) UpdatePayment(ctx context.Context, payment *models.Payment) error {
+	return mt.storage.updatePayment(payment)
+}
+
+func (mt *MemoryTx) AddPaymentRefund(ctx context.Context, id, delta int) (*models.Payment, error) {
+	return mt.storage.addPaymentRefund(id, delta)
+}
+
+func (m *MemoryStorage) createPayment(payment *models.Payment) error {
+	if _, exists := m.orders[payment.OrderID]; !exists {
+		return notFound("order")
+	}
+	if err := m.checkPayment(payment); err != nil {
+		return err
+	}
+	m.paymentIDSeq++
+	payment.ID = m.paymentIDSeq
+	stored := *payment
+	m.payments[payment.ID] = &stored
+	return nil
+}
+
+// checkPayment повторяет уникальные индексы PostgreSQL: одна активная
+// попытка на заказ и уникальная ссылка в пределах провайдера.
+func (m *MemoryStorage) checkPayment(payment *models.Payment) error {
+	for _, p := range m.payments {
+		if p.ID == payment.ID {
+			continue
+		}
+		if p.OrderID == payment.OrderID && p.IsActive() && payment.IsActive() {
+			return fmt.Errorf("order %d already has an active payment", payment.OrderID)
+		}
+		if payment.Reference != "" && p.Provider == payment.Provider && p.Reference == payment.Reference {
+			return errors.New("payment reference already exists")
+		}
+	}
+	return nil
+}
+
+func (m *MemoryStorage) getPaymentByID(id int) (*models.Payment, error) {
+	payment, exists := m.payments[id]
+	if !exists {
+		return nil, notFound("payment")
+	}
+	p := *payment
+	return &p, nil
+}
+
+func (m *MemoryStorage) getPaymentByReference(provider, reference string) (*models.Payment, error) {
+	for _, payment := range m.payments {
+		if payment.Provider == provider && payment.Reference == reference {
+			p := *payment
+			return &p, nil
+		}
+	}
+	return nil, notFound("payment")
+}
+
+func (m *MemoryStorage) getPaymentsByOrder(orderID int) []models.Payment {
+	payments := []models.Payment{}
+	for _, p := range m.payments {
+		if p.OrderID == orderID {
+			payments = append(payments, *p)
+		}
+	}
+	sort.Slice(payments, func(i, j int) bool { return payments[i].ID < payments[j].ID })
+	return payments
+}
+
+func (m *MemoryStorage) updatePayment(payment *models.Payment) error {
+	if _, exists := m.payments[payment.ID]; !exists {
+		return notFound("payment")
+	}
+	if err := m.checkPayment(payment); err != nil {
+		return err
+	}
+	stored := *payment
+	m.payments[payment.ID] = &stored
+	return nil
+}
+
+func (m *MemoryStorage) addPaymentRefund(id, delta int) (*models.Payment, error) {
+	payment, exists := m.payments[id]
+	if !exists {
+		return nil, notFound("payment")
+	}
+	refunded := payment.RefundedAmount + delta
+	if (payment.Status != models.PaymentCaptured && payment.Status != models.PaymentRefunded) ||
+		refunded < 0 || refunded > payment.Amount {
+		return nil, refundError(payment, delta)
+	}
+
+	payment.RefundedAmount = refunded
+	payment.Status = models.PaymentCaptured
+	if refunded == payment.Amount {
+		payment.Status = models.PaymentRefunded
+	}
+	payment.UpdatedAt = time.Now()
+	p := *payment
+	return &p, nil
+}
+
+// orderHasPayments повторяет внешний ключ платежей на заказ: заказ с
+// попытками оплаты удалить нельзя.
+func (m *MemoryStorage) orderHasPayments(orderID int) bool {
+	for _, p := range m.payments {
+		if p.OrderID == orderID {
+			return true
+		}
+	}
+	return false
+}
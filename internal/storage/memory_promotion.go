@@ -0,0 +1,282 @@
+package storage
+
+import (
+	"backend-store/internal/models"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+func (m *MemoryStorage) CreatePromotion(ctx context.Context, promotion *models.Promotion) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.createPromotion(promotion)
+}
+
+func (m *MemoryStorage) GetPromotionByID(ctx context.Context, id int) (*models.Promotion, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getPromotionByID(id)
+}
+
+func (m *MemoryStorage) GetPromotionByCode(ctx context.Context, code string) (*models.Promotion, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getPromotionByCode(code)
+}
+
+func (m *MemoryStorage) GetAllPromotions(ctx context.Context) ([]*models.Promotion, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getAllPromotions(), nil
+}
+
+func (m *MemoryStorage) UpdatePromotion(ctx context.Context, promotion *models.Promotion) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.updatePromotion(promotion)
+}
+
+func (m *MemoryStorage) DeletePromotion(ctx context.Context, id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.deletePromotion(id)
+}
+
+func (m *MemoryStorage) RedeemPromotion(ctx context.Context, redemption *models.PromotionRedemption) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.redeemPromotion(redemption)
+}
+
+func (m *MemoryStorage) CountPromotionRedemptions(ctx context.Context, promotionID, customerID int) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.countPromotionRedemptions(promotionID, customerID), nil
+}
+
+func (m *MemoryStorage) ReleasePromotions(ctx context.Context, orderID int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.releasePromotions(orderID)
+	return nil
+}
+
+func (m *MemoryStorage) CreateOrderAdjustments(ctx context.Context, orderID int, adjustments []models.OrderAdjustment) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.createOrderAdjustments(orderID, adjustments)
+}
+
+func (m *MemoryStorage) GetOrderAdjustments(ctx context.Context, orderID int) ([]models.OrderAdjustment, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getOrderAdjustments(orderID), nil
+}
+
+func (m *MemoryStorage) DeleteOrderAdjustments(ctx context.Context, orderID int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deleteOrderAdjustments(orderID)
+	return nil
+}
+
+func (mt *MemoryTx) CreatePromotion(ctx context.Context, promotion *models.Promotion) error {
+	return mt.storage.createPromotion(promotion)
+}
+
+func (mt *MemoryTx) GetPromotionByID(ctx context.Context, id int) (*models.Promotion, error) {
+	return mt.storage.getPromotionByID(id)
+}
+
+func (mt *MemoryTx) GetPromotionByCode(ctx context.Context, code string) (*models.Promotion, error) {
+	return mt.storage.getPromotionByCode(code)
+}
+
+func (mt *MemoryTx) GetAllPromotions(ctx context.Context) ([]*models.Promotion, error) {
+	return mt.storage.getAllPromotions(), nil
+}
+
+func (mt *MemoryTx) UpdatePromotion(ctx context.Context, promotion *models.Promotion) error {
+	return mt.storage.updatePromotion(promotion)
+}
+
+func (mt *MemoryTx) DeletePromotion(ctx context.Context, id int) error {
+	return mt.storage.deletePromotion(id)
+}
+
+func (mt *MemoryTx) RedeemPromotion(ctx context.Context, redemption *models.PromotionRedemption) error {
+	return mt.storage.redeemPromotion(redemption)
+}
+
+func (mt *MemoryTx) CountPromotionRedemptions(ctx context.Context, promotionID, customerID int) (int, error) {
+	return mt.storage.countPromotionRedemptions(promotionID, customerID), nil
+}
+
+func (mt *MemoryTx) ReleasePromotions(ctx context.Context, orderID int) error {
+	mt.storage.releasePromotions(orderID)
+	return nil
+}
+
+func (mt *MemoryTx) CreateOrderAdjustments(ctx context.Context, orderID int, adjustments []models.OrderAdjustment) error {
+	return mt.storage.createOrderAdjustments(orderID, adjustments)
+}
+
+func (mt *MemoryTx) GetOrderAdjustments(ctx context.Context, orderID int) ([]models.OrderAdjustment, error) {
+	return mt.storage.getOrderAdjustments(orderID), nil
+}
+
+func (mt *MemoryTx) DeleteOrderAdjustments(ctx context.Context, orderID int) error {
+	mt.storage.deleteOrderAdjustments(orderID)
+	return nil
+}
+
+func (m *MemoryStorage) createPromotion(promotion *models.Promotion) error {
+	if err := m.checkPromotionCode(promotion); err != nil {
+		return err
+	}
+	m.promotionIDSeq++
+	promotion.ID = m.promotionIDSeq
+	m.promotions[promotion.ID] = copyPromotion(promotion)
+	return nil
+}
+
+// checkPromotionCode повторяет уникальный индекс кода в PostgreSQL.
+func (m *MemoryStorage) checkPromotionCode(promotion *models.Promotion) error {
+	if promotion.Code == "" {
+		return nil
+	}
+	for _, p := range m.promotions {
+		if p.ID != promotion.ID && p.Code == promotion.Code {
+			return fmt.Errorf("promotion with code %q already exists", promotion.Code)
+		}
+	}
+	return nil
+}
+
+func (m *MemoryStorage) getPromotionByID(id int) (*models.Promotion, error) {
+	promotion, exists := m.promotions[id]
+	if !exists {
+		return nil, notFound("promotion")
+	}
+	return copyPromotion(promotion), nil
+}
+
+func (m *MemoryStorage) getPromotionByCode(code string) (*models.Promotion, error) {
+	for _, p := range m.promotions {
+		if code != "" && p.Code == code {
+			return copyPromotion(p), nil
+		}
+	}
+	return nil, notFound("promotion")
+}
+
+func (m *MemoryStorage) getAllPromotions() []*models.Promotion {
+	promotions := make([]*models.Promotion, 0, len(m.promotions))
+	for _, p := range m.promotions {
+		promotions = append(promotions, copyPromotion(p))
+	}
+	sort.Slice(promotions, func(i, j int) bool { return promotions[i].ID < promotions[j].ID })
+	return promotions
+}
+
+// updatePromotion, как и UPDATE в PostgreSQL, не меняет счетчик
+// использований.
+func (m *MemoryStorage) updatePromotion(promotion *models.Promotion) error {
+	stored, exists := m.promotions[promotion.ID]
+	if !exists {
+		return notFound("promotion")
+	}
+	if err := m.checkPromotionCode(promotion); err != nil {
+		return err
+	}
+	promotion.UsageCount = stored.UsageCount
+	promotion.CreatedAt = stored.CreatedAt
+	m.promotions[promotion.ID] = copyPromotion(promotion)
+	return nil
+}
+
+func (m *MemoryStorage) deletePromotion(id int) error {
+	if _, exists := m.promotions[id]; !exists {
+		return notFound("promotion")
+	}
+	for _, r := range m.redemptions {
+		if r.PromotionID == id {
+			return errors.New("cannot delete promotion with redemptions")
+		}
+	}
+	delete(m.promotions, id)
+	return nil
+}
+
+func (m *MemoryStorage) redeemPromotion(redemption *models.PromotionRedemption) error {
+	promotion, exists := m.promotions[redemption.PromotionID]
+	if !exists {
+		return notFound("promotion")
+	}
+	if promotion.UsageLimit > 0 && promotion.UsageCount >= promotion.UsageLimit {
+		return fmt.Errorf("promotion %q %w", promotion.Code, ErrUsageLimitReached)
+	}
+	if promotion.PerCustomerLimit > 0 && redemption.CustomerID != 0 &&
+		m.countPromotionRedemptions(promotion.ID, redemption.CustomerID) >= promotion.PerCustomerLimit {
+		return fmt.Errorf("promotion %q per-customer %w", promotion.Code, ErrUsageLimitReached)
+	}
+	promotion.UsageCount++
+	m.redemptionIDSeq++
+	redemption.ID = m.redemptionIDSeq
+	stored := *redemption
+	m.redemptions = append(m.redemptions, &stored)
+	return nil
+}
+
+func (m *MemoryStorage) countPromotionRedemptions(promotionID, customerID int) int {
+	count := 0
+	for _, r := range m.redemptions {
+		if r.PromotionID == promotionID && r.CustomerID == customerID {
+			count++
+		}
+	}
+	return count
+}
+
+func (m *MemoryStorage) releasePromotions(orderID int) {
+	kept := m.redemptions[:0]
+	for _, r := range m.redemptions {
+		if r.OrderID != orderID {
+			kept = append(kept, r)
+			continue
+		}
+		if p, exists := m.promotions[r.PromotionID]; exists && p.UsageCount > 0 {
+			p.UsageCount--
+		}
+	}
+	m.redemptions = kept
+}
+
+func (m *MemoryStorage) createOrderAdjustments(orderID int, adjustments []models.OrderAdjustment) error {
+	if _, exists := m.orders[orderID]; !exists {
+		return notFound("order")
+	}
+	for i := range adjustments {
+		m.adjustmentIDSeq++
+		adjustments[i].ID = m.adjustmentIDSeq
+		adjustments[i].OrderID = orderID
+		m.adjustments[orderID] = append(m.adjustments[orderID], adjustments[i])
+	}
+	return nil
+}
+
+func (m *MemoryStorage) getOrderAdjustments(orderID int) []models.OrderAdjustment {
+	return append([]models.OrderAdjustment{}, m.adjustments[orderID]...)
+}
+
+func (m *MemoryStorage) deleteOrderAdjustments(orderID int) {
+	delete(m.adjustments, orderID)
+}
+
+func copyPromotion(promotion *models.Promotion) *models.Promotion {
+	c := *promotion
+	c.ProductIDs = append(models.IDList(nil), promotion.ProductIDs...)
+	return &c
+}
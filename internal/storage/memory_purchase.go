@@ -0,0 +1,257 @@
+package storage
+
+import (
+	"backend-store/internal/models"
+	"context"
+	"errors"
+	"sort"
+)
+
+func (m *MemoryStorage) CreateSupplier(ctx context.Context, supplier *models.Supplier) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.createSupplier(supplier)
+}
+
+func (m *MemoryStorage) GetSupplierByID(ctx context.Context, id int) (*models.Supplier, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getSupplierByID(id)
+}
+
+func (m *MemoryStorage) GetAllSuppliers(ctx context.Context) ([]*models.Supplier, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getAllSuppliers(), nil
+}
+
+func (m *MemoryStorage) UpdateSupplier(ctx context.Context, supplier *models.Supplier) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.updateSupplier(supplier)
+}
+
+func (m *MemoryStorage) DeleteSupplier(ctx context.Context, id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.deleteSupplier(id)
+}
+
+func (m *MemoryStorage) CreatePurchaseOrder(ctx context.Context, order *models.PurchaseOrder) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.createPurchaseOrder(order)
+}
+
+func (m *MemoryStorage) GetPurchaseOrderByID(ctx context.Context, id int) (*models.PurchaseOrder, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getPurchaseOrderByID(id)
+}
+
+// LockPurchaseOrder не отличается от GetPurchaseOrderByID: транзакции
+// памяти и так выполняются под общей блокировкой.
+func (m *MemoryStorage) LockPurchaseOrder(ctx context.Context, id int) (*models.PurchaseOrder, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getPurchaseOrderByID(id)
+}
+
+func (m *MemoryStorage) GetAllPurchaseOrders(ctx context.Context, status string) ([]*models.PurchaseOrder, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getAllPurchaseOrders(status), nil
+}
+
+func (m *MemoryStorage) UpdatePurchaseOrder(ctx context.Context, order *models.PurchaseOrder) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.updatePurchaseOrder(order)
+}
+
+func (m *MemoryStorage) ReplacePurchaseOrderItems(ctx context.Context, order *models.PurchaseOrder) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.replacePurchaseOrderItems(order)
+}
+
+func (mt *MemoryTx) CreateSupplier(ctx context.Context, supplier *models.Supplier) error {
+	return mt.storage.createSupplier(supplier)
+}
+
+func (mt *MemoryTx) GetSupplierByID(ctx context.Context, id int) (*models.Supplier, error) {
+	return mt.storage.getSupplierByID(id)
+}
+
+func (mt *MemoryTx) GetAllSuppliers(ctx context.Context) ([]*models.Supplier, error) {
+	return mt.storage.getAllSuppliers(), nil
+}
+
+func (mt *MemoryTx) UpdateSupplier(ctx context.Context, supplier *models.Supplier) error {
+	return mt.storage.updateSupplier(supplier)
+}
+
+func (mt *MemoryTx) DeleteSupplier(ctx context.Context, id int) error {
+	return mt.storage.deleteSupplier(id)
+}
+
+func (mt *MemoryTx) CreatePurchaseOrder(ctx context.Context, order *models.PurchaseOrder) error {
+	return mt.storage.createPurchaseOrder(order)
+}
+
+func (mt *MemoryTx) GetPurchaseOrderByID(ctx context.Context, id int) (*models.PurchaseOrder, error) {
+	return mt.storage.getPurchaseOrderByID(id)
+}
+
+func (mt *MemoryTx) LockPurchaseOrder(ctx context.Context, id int) (*models.PurchaseOrder, error) {
+	return mt.storage.getPurchaseOrderByID(id)
+}
+
+func (mt *MemoryTx) GetAllPurchaseOrders(ctx context.Context, status string) ([]*models.PurchaseOrder, error) {
+	return mt.storage.getAllPurchaseOrders(status), nil
+}
+
+func (mt *MemoryTx) UpdatePurchaseOrder(ctx context.Context, order *models.PurchaseOrder) error {
+	return mt.storage.updatePurchaseOrder(order)
+}
+
+func (mt *MemoryTx) ReplacePurchaseOrderItems(ctx context.Context, order *models.PurchaseOrder) error {
+	return mt.storage.replacePurchaseOrderItems(order)
+}
+
+func (m *MemoryStorage) createSupplier(supplier *models.Supplier) error {
+	m.supplierIDSeq++
+	supplier.ID = m.supplierIDSeq
+	stored := *supplier
+	m.suppliers[supplier.ID] = &stored
+	return nil
+}
+
+func (m *MemoryStorage) getSupplierByID(id int) (*models.Supplier, error) {
+	supplier, exists := m.suppliers[id]
+	if !exists {
+		return nil, notFound("supplier")
+	}
+	c := *supplier
+	return &c, nil
+}
+
+func (m *MemoryStorage) getAllSuppliers() []*models.Supplier {
+	suppliers := make([]*models.Supplier, 0, len(m.suppliers))
+	for _, s := range m.suppliers {
+		c := *s
+		suppliers = append(suppliers, &c)
+	}
+	sort.Slice(suppliers, func(i, j int) bool { return suppliers[i].ID < suppliers[j].ID })
+	return suppliers
+}
+
+func (m *MemoryStorage) updateSupplier(supplier *models.Supplier) error {
+	if _, exists := m.suppliers[supplier.ID]; !exists {
+		return notFound("supplier")
+	}
+	stored := *supplier
+	m.suppliers[supplier.ID] = &stored
+	return nil
+}
+
+func (m *MemoryStorage) deleteSupplier(id int) error {
+	if _, exists := m.suppliers[id]; !exists {
+		return notFound("supplier")
+	}
+	for _, po := range m.purchaseOrders {
+		if po.SupplierID == id {
+			return errors.New("cannot delete supplier with purchase orders")
+		}
+	}
+	delete(m.suppliers, id)
+	return nil
+}
+
+func (m *MemoryStorage) createPurchaseOrder(order *models.PurchaseOrder) error {
+	if _, exists := m.suppliers[order.SupplierID]; !exists {
+		return notFound("supplier")
+	}
+	if _, exists := m.warehouses[order.WarehouseID]; !exists {
+		return notFound("warehouse")
+	}
+	m.purchaseOrderIDSeq++
+	order.ID = m.purchaseOrderIDSeq
+	m.assignPurchaseOrderItemIDs(order)
+	m.purchaseOrders[order.ID] = copyPurchaseOrder(order)
+	return nil
+}
+
+func (m *MemoryStorage) getPurchaseOrderByID(id int) (*models.PurchaseOrder, error) {
+	order, exists := m.purchaseOrders[id]
+	if !exists {
+		return nil, notFound("purchase order")
+	}
+	return copyPurchaseOrder(order), nil
+}
+
+func (m *MemoryStorage) getAllPurchaseOrders(status string) []*models.PurchaseOrder {
+	orders := make([]*models.PurchaseOrder, 0)
+	for _, po := range m.purchaseOrders {
+		if status == "" || po.Status == status {
+			orders = append(orders, copyPurchaseOrder(po))
+		}
+	}
+	sort.Slice(orders, func(i, j int) bool { return orders[i].ID < orders[j].ID })
+	return orders
+}
+
+// updatePurchaseOrder, как и UPDATE в PostgreSQL, не меняет состав позиций:
+// из позиций переносятся только принятые количества.
+func (m *MemoryStorage) updatePurchaseOrder(order *models.PurchaseOrder) error {
+	stored, exists := m.purchaseOrders[order.ID]
+	if !exists {
+		return notFound("purchase order")
+	}
+	if _, exists := m.suppliers[order.SupplierID]; !exists {
+		return notFound("supplier")
+	}
+	if _, exists := m.warehouses[order.WarehouseID]; !exists {
+		return notFound("warehouse")
+	}
+
+	received := make(map[int]int, len(order.Items))
+	for _, item := range order.Items {
+		received[item.ID] = item.ReceivedQuantity
+	}
+	updated := copyPurchaseOrder(order)
+	updated.Items = append([]models.PurchaseOrderItem{}, stored.Items...)
+	for i := range updated.Items {
+		if quantity, ok := received[updated.Items[i].ID]; ok {
+			updated.Items[i].ReceivedQuantity = quantity
+		}
+	}
+	updated.CreatedAt = stored.CreatedAt
+	m.purchaseOrders[order.ID] = updated
+	return nil
+}
+
+func (m *MemoryStorage) replacePurchaseOrderItems(order *models.PurchaseOrder) error {
+	stored, exists := m.purchaseOrders[order.ID]
+	if !exists {
+		return notFound("purchase order")
+	}
+	m.assignPurchaseOrderItemIDs(order)
+	stored.Items = append([]models.PurchaseOrderItem{}, order.Items...)
+	return nil
+}
+
+// assignPurchaseOrderItemIDs повторяет поведение SERIAL для позиций.
+func (m *MemoryStorage) assignPurchaseOrderItemIDs(order *models.PurchaseOrder) {
+	for i := range order.Items {
+		m.purchaseOrderItemIDSeq++
+		order.Items[i].ID = m.purchaseOrderItemIDSeq
+		order.Items[i].PurchaseOrderID = order.ID
+	}
+}
+
+func copyPurchaseOrder(order *models.PurchaseOrder) *models.PurchaseOrder {
+	c := *order
+	c.Items = append([]models.PurchaseOrderItem{}, order.Items...)
+	return &c
+}
@@ -0,0 +1,204 @@
+package storage
+
+import (
+	"backend-store/internal/models"
+	"context"
+	"math"
+	"sort"
+)
+
+func (m *MemoryStorage) ReportSales(ctx context.Context, filter models.ReportFilter) ([]models.SalesPoint, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.reportSales(filter), nil
+}
+
+func (m *MemoryStorage) ReportTopProducts(ctx context.Context, filter models.ReportFilter) ([]models.ProductSales, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.reportTopProducts(filter), nil
+}
+
+func (m *MemoryStorage) ReportOrderValue(ctx context.Context, filter models.ReportFilter) (*models.OrderValue, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.reportOrderValue(filter), nil
+}
+
+func (m *MemoryStorage) ReportOrderStatuses(ctx context.Context, filter models.ReportFilter) ([]models.StatusCount, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.reportOrderStatuses(filter), nil
+}
+
+func (m *MemoryStorage) ReportStockValuation(ctx context.Context, filter models.ReportFilter) ([]models.StockValuation, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.reportStockValuation(filter), nil
+}
+
+func (mt *MemoryTx) ReportSales(ctx context.Context, filter models.ReportFilter) ([]models.SalesPoint, error) {
+	return mt.storage.reportSales(filter), nil
+}
+
+func (mt *MemoryTx) ReportTopProducts(ctx context.Context, filter models.ReportFilter) ([]models.ProductSales, error) {
+	return mt.storage.reportTopProducts(filter), nil
+}
+
+func (mt *MemoryTx) ReportOrderValue(ctx context.Context, filter models.ReportFilter) (*models.OrderValue, error) {
+	return mt.storage.reportOrderValue(filter), nil
+}
+
+func (mt *MemoryTx) ReportOrderStatuses(ctx context.Context, filter models.ReportFilter) ([]models.StatusCount, error) {
+	return mt.storage.reportOrderStatuses(filter), nil
+}
+
+func (mt *MemoryTx) ReportStockValuation(ctx context.Context, filter models.ReportFilter) ([]models.StockValuation, error) {
+	return mt.storage.reportStockValuation(filter), nil
+}
+
+// reportOrders возвращает заказы периода, входящие в выручку.
+func (m *MemoryStorage) reportOrders(filter models.ReportFilter) []*models.Order {
+	var orders []*models.Order
+	for _, o := range m.getAllOrders() {
+		if filter.Match(o.CreatedAt) && o.CountsAsRevenue() {
+			orders = append(orders, o)
+		}
+	}
+	return orders
+}
+
+func (m *MemoryStorage) reportSales(filter models.ReportFilter) []models.SalesPoint {
+	byPeriod := make(map[int64]*models.SalesPoint)
+	for _, o := range m.reportOrders(filter) {
+		period := filter.Period(o.CreatedAt)
+		point, ok := byPeriod[period.Unix()]
+		if !ok {
+			point = &models.SalesPoint{Period: period}
+			byPeriod[period.Unix()] = point
+		}
+		point.Orders++
+		point.Revenue += o.Total
+	}
+
+	points := make([]models.SalesPoint, 0, len(byPeriod))
+	for _, point := range byPeriod {
+		points = append(points, *point)
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i].Period.Before(points[j].Period) })
+	return points
+}
+
+func (m *MemoryStorage) reportTopProducts(filter models.ReportFilter) []models.ProductSales {
+	byProduct := make(map[int]*models.ProductSales)
+	for _, o := range m.reportOrders(filter) {
+		for _, item := range o.Products {
+			sales, ok := byProduct[item.ProductID]
+			if !ok {
+				sales = &models.ProductSales{ProductID: item.ProductID}
+				if product, exists := m.products[item.ProductID]; exists {
+					sales.Name = product.Name
+				}
+				byProduct[item.ProductID] = sales
+			}
+			sales.Units += item.Quantity
+			sales.Revenue += item.Price * item.Quantity
+			sales.Cost += item.Cost * item.Quantity
+		}
+	}
+
+	products := make([]models.ProductSales, 0, len(byProduct))
+	for _, sales := range byProduct {
+		sales.Margin = sales.Revenue - sales.Cost
+		products = append(products, *sales)
+	}
+	sort.Slice(products, func(i, j int) bool {
+		a, b := products[i], products[j]
+		if filter.SortBy == models.ReportByRevenue && a.Revenue != b.Revenue {
+			return a.Revenue > b.Revenue
+		}
+		if a.Units != b.Units {
+			return a.Units > b.Units
+		}
+		if a.Revenue != b.Revenue {
+			return a.Revenue > b.Revenue
+		}
+		return a.ProductID < b.ProductID
+	})
+	if filter.Limit > 0 && len(products) > filter.Limit {
+		products = products[:filter.Limit]
+	}
+	return products
+}
+
+func (m *MemoryStorage) reportOrderValue(filter models.ReportFilter) *models.OrderValue {
+	value := &models.OrderValue{}
+	for _, o := range m.reportOrders(filter) {
+		value.Orders++
+		value.Revenue += o.Total
+	}
+	if value.Orders > 0 {
+		value.Average = int(math.Round(float64(value.Revenue) / float64(value.Orders)))
+	}
+	return value
+}
+
+func (m *MemoryStorage) reportOrderStatuses(filter models.ReportFilter) []models.StatusCount {
+	byStatus := make(map[string]*models.StatusCount)
+	for _, o := range m.getAllOrders() {
+		if !filter.Match(o.CreatedAt) {
+			continue
+		}
+		count, ok := byStatus[o.Status]
+		if !ok {
+			count = &models.StatusCount{Status: o.Status}
+			byStatus[o.Status] = count
+		}
+		count.Orders++
+		count.Revenue += o.Total
+	}
+
+	counts := make([]models.StatusCount, 0, len(byStatus))
+	for _, count := range byStatus {
+		counts = append(counts, *count)
+	}
+	return counts
+}
+
+// reportStockValuation считает остаток по журналу на конец периода, поэтому
+// From не учитывается. Варианты без остатка и удаленные варианты пропускаются.
+func (m *MemoryStorage) reportStockValuation(filter models.ReportFilter) []models.StockValuation {
+	quantities := make(map[int]int)
+	for _, mv := range m.movements {
+		if filter.To.IsZero() || mv.CreatedAt.Before(filter.To) {
+			quantities[mv.VariantID] += mv.Delta
+		}
+	}
+
+	valuation := []models.StockValuation{}
+	for variantID, quantity := range quantities {
+		variant, exists := m.variants[variantID]
+		if !exists || quantity == 0 {
+			continue
+		}
+		item := models.StockValuation{
+			ProductID: variant.ProductID,
+			VariantID: variant.ID,
+			SKU:       variant.SKU,
+			Quantity:  quantity,
+			UnitCost:  variant.Cost,
+			Value:     quantity * variant.Cost,
+		}
+		if product, ok := m.products[variant.ProductID]; ok {
+			item.Name = product.Name
+		}
+		valuation = append(valuation, item)
+	}
+	sort.Slice(valuation, func(i, j int) bool {
+		if valuation[i].ProductID != valuation[j].ProductID {
+			return valuation[i].ProductID < valuation[j].ProductID
+		}
+		return valuation[i].VariantID < valuation[j].VariantID
+	})
+	return valuation
+}
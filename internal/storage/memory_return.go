@@ -0,0 +1,141 @@
+package storage
+
+import (
+	"backend-store/internal/models"
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+func (m *MemoryStorage) CreateReturn(ctx context.Context, ret *models.ReturnRequest) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.createReturn(ret)
+}
+
+func (m *MemoryStorage) GetReturnByID(ctx context.Context, id int) (*models.ReturnRequest, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getReturnByID(id)
+}
+
+func (m *MemoryStorage) GetReturnsByOrder(ctx context.Context, orderID int) ([]models.ReturnRequest, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getReturnsByOrder(orderID), nil
+}
+
+func (m *MemoryStorage) UpdateReturn(ctx context.Context, ret *models.ReturnRequest) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.updateReturn(ret)
+}
+
+func (m *MemoryStorage) SetReturnStatus(ctx context.Context, id int, from, to string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.setReturnStatus(id, from, to)
+}
+
+func (mt *MemoryTx) CreateReturn(ctx context.Context, ret *models.ReturnRequest) error {
+	return mt.storage.createReturn(ret)
+}
+
+func (mt *MemoryTx) GetReturnByID(ctx context.Context, id int) (*models.ReturnRequest, error) {
+	return mt.storage.getReturnByID(id)
+}
+
+func (mt *MemoryTx) GetReturnsByOrder(ctx context.Context, orderID int) ([]models.ReturnRequest, error) {
+	return mt.storage.getReturnsByOrder(orderID), nil
+}
+
+func (mt *MemoryTx) UpdateReturn(ctx context.Context, ret *models.ReturnRequest) error {
+	return mt.storage.updateReturn(ret)
+}
+
+func (mt *MemoryTx) SetReturnStatus(ctx context.Context, id int, from, to string) error {
+	return mt.storage.setReturnStatus(id, from, to)
+}
+
+func (m *MemoryStorage) createReturn(ret *models.ReturnRequest) error {
+	if _, exists := m.orders[ret.OrderID]; !exists {
+		return notFound("order")
+	}
+	m.returnIDSeq++
+	ret.ID = m.returnIDSeq
+	for i := range ret.Items {
+		m.returnItemIDSeq++
+		ret.Items[i].ID = m.returnItemIDSeq
+		ret.Items[i].ReturnID = ret.ID
+	}
+	m.returns[ret.ID] = copyReturn(ret)
+	return nil
+}
+
+func (m *MemoryStorage) getReturnByID(id int) (*models.ReturnRequest, error) {
+	ret, exists := m.returns[id]
+	if !exists {
+		return nil, notFound("return")
+	}
+	return copyReturn(ret), nil
+}
+
+func (m *MemoryStorage) getReturnsByOrder(orderID int) []models.ReturnRequest {
+	returns := []models.ReturnRequest{}
+	for _, r := range m.returns {
+		if r.OrderID == orderID {
+			returns = append(returns, *copyReturn(r))
+		}
+	}
+	sort.Slice(returns, func(i, j int) bool { return returns[i].ID < returns[j].ID })
+	return returns
+}
+
+// updateReturn, как и UPDATE в PostgreSQL, не меняет состав позиций: из
+// ret.Items берется только судьба каждой позиции.
+func (m *MemoryStorage) updateReturn(ret *models.ReturnRequest) error {
+	stored, exists := m.returns[ret.ID]
+	if !exists {
+		return notFound("return")
+	}
+	updated := copyReturn(stored)
+	updated.Status = ret.Status
+	updated.ResolutionNote = ret.ResolutionNote
+	updated.RefundAmount = ret.RefundAmount
+	updated.PaymentID = ret.PaymentID
+	updated.UpdatedAt = ret.UpdatedAt
+
+	dispositions := make(map[int]string, len(ret.Items))
+	for _, item := range ret.Items {
+		dispositions[item.ID] = item.Disposition
+	}
+	for i := range updated.Items {
+		if d, ok := dispositions[updated.Items[i].ID]; ok {
+			updated.Items[i].Disposition = d
+		}
+	}
+	m.returns[ret.ID] = updated
+	return nil
+}
+
+func (m *MemoryStorage) setReturnStatus(id int, from, to string) error {
+	stored, exists := m.returns[id]
+	if !exists {
+		return notFound("return")
+	}
+	if stored.Status != from {
+		return fmt.Errorf("cannot change status of return %d: it is no longer %q", id, from)
+	}
+	updated := copyReturn(stored)
+	updated.Status = to
+	updated.UpdatedAt = time.Now()
+	m.returns[id] = updated
+	return nil
+}
+
+func copyReturn(ret *models.ReturnRequest) *models.ReturnRequest {
+	c := *ret
+	c.Items = append([]models.ReturnItem{}, ret.Items...)
+	return &c
+}
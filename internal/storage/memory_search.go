@@ -0,0 +1,121 @@
+package storage
+
+import (
+	"backend-store/internal/models"
+	"backend-store/internal/search"
+	"context"
+	"sort"
+)
+
+func (m *MemoryStorage) SearchProducts(ctx context.Context, query models.ProductSearch) (*models.SearchResult, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.searchProducts(query), nil
+}
+
+func (mt *MemoryTx) SearchProducts(ctx context.Context, query models.ProductSearch) (*models.SearchResult, error) {
+	return mt.storage.searchProducts(query), nil
+}
+
+// indexProduct индексирует название и описание с весами меток A и B,
+// как столбец products.search_vector.
+func (m *MemoryStorage) indexProduct(product *models.Product) {
+	m.searchIndex.Add(product.ID,
+		search.Field{Text: product.Name, Weight: search.WeightName},
+		search.Field{Text: product.Description, Weight: search.WeightDescription},
+	)
+}
+
+func (m *MemoryStorage) searchProducts(query models.ProductSearch) *models.SearchResult {
+	result := &models.SearchResult{Hits: []models.SearchHit{}}
+
+	for id, score := range m.searchIndex.Search(query.Query) {
+		product, exists := m.products[id]
+		if !exists {
+			continue
+		}
+		if query.InStock && product.Quantity <= 0 {
+			continue
+		}
+		if len(query.CategoryIDs) > 0 && !m.inCategories(id, query.CategoryIDs) {
+			continue
+		}
+		result.Hits = append(result.Hits, models.SearchHit{Product: product, Score: score})
+	}
+
+	sort.Slice(result.Hits, func(i, j int) bool {
+		if result.Hits[i].Score != result.Hits[j].Score {
+			return result.Hits[i].Score > result.Hits[j].Score
+		}
+		return result.Hits[i].ID < result.Hits[j].ID
+	})
+
+	result.Total = len(result.Hits)
+	result.Facets = m.searchFacets(result.Hits)
+	result.Hits = paginateHits(result.Hits, query.Offset, query.Limit)
+	return result
+}
+
+func (m *MemoryStorage) inCategories(productID int, categoryIDs []int) bool {
+	for _, id := range m.productCategories[productID] {
+		if containsInt(categoryIDs, id) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *MemoryStorage) searchFacets(hits []models.SearchHit) models.SearchFacets {
+	facets := models.SearchFacets{
+		Categories: []models.CategoryFacet{},
+		Prices:     make([]models.PriceFacet, len(models.PriceBuckets)),
+	}
+	for i, bucket := range models.PriceBuckets {
+		facets.Prices[i].PriceBucket = bucket
+	}
+
+	categoryCounts := make(map[int]int)
+	for _, hit := range hits {
+		if hit.Quantity > 0 {
+			facets.InStock++
+		} else {
+			facets.OutOfStock++
+		}
+		for i, bucket := range models.PriceBuckets {
+			if bucket.Contains(hit.Price) {
+				facets.Prices[i].Count++
+			}
+		}
+		for _, id := range m.productCategories[hit.ID] {
+			categoryCounts[id]++
+		}
+	}
+
+	for id, count := range categoryCounts {
+		if category, exists := m.categories[id]; exists {
+			facets.Categories = append(facets.Categories, models.CategoryFacet{ID: id, Name: category.Name, Count: count})
+		}
+	}
+	sortCategoryFacets(facets.Categories)
+	return facets
+}
+
+func sortCategoryFacets(facets []models.CategoryFacet) {
+	sort.Slice(facets, func(i, j int) bool {
+		if facets[i].Count != facets[j].Count {
+			return facets[i].Count > facets[j].Count
+		}
+		return facets[i].Name < facets[j].Name
+	})
+}
+
+func paginateHits(hits []models.SearchHit, offset, limit int) []models.SearchHit {
+	if offset >= len(hits) {
+		return []models.SearchHit{}
+	}
+	end := len(hits)
+	if limit > 0 && offset+limit < end {
+		end = offset + limit
+	}
+	return hits[offset:end]
+}
@@ -0,0 +1,104 @@
+package storage
+
+import (
+	"backend-store/internal/models"
+	"context"
+	"sort"
+)
+
+func (m *MemoryStorage) CreateShipment(ctx context.Context, shipment *models.Shipment) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.createShipment(shipment)
+}
+
+func (m *MemoryStorage) GetShipmentByID(ctx context.Context, id int) (*models.Shipment, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getShipmentByID(id)
+}
+
+func (m *MemoryStorage) GetShipmentsByOrder(ctx context.Context, orderID int) ([]models.Shipment, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getShipmentsByOrder(orderID), nil
+}
+
+func (m *MemoryStorage) UpdateShipment(ctx context.Context, shipment *models.Shipment) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.updateShipment(shipment)
+}
+
+func (mt *MemoryTx) CreateShipment(ctx context.Context, shipment *models.Shipment) error {
+	return mt.storage.createShipment(shipment)
+}
+
+func (mt *MemoryTx) GetShipmentByID(ctx context.Context, id int) (*models.Shipment, error) {
+	return mt.storage.getShipmentByID(id)
+}
+
+func (mt *MemoryTx) GetShipmentsByOrder(ctx context.Context, orderID int) ([]models.Shipment, error) {
+	return mt.storage.getShipmentsByOrder(orderID), nil
+}
+
+func (mt *MemoryTx) UpdateShipment(ctx context.Context, shipment *models.Shipment) error {
+	return mt.storage.updateShipment(shipment)
+}
+
+func (m *MemoryStorage) createShipment(shipment *models.Shipment) error {
+	if _, exists := m.orders[shipment.OrderID]; !exists {
+		return notFound("order")
+	}
+	m.shipmentIDSeq++
+	shipment.ID = m.shipmentIDSeq
+	for i := range shipment.Items {
+		m.shipmentItemIDSeq++
+		shipment.Items[i].ID = m.shipmentItemIDSeq
+		shipment.Items[i].ShipmentID = shipment.ID
+	}
+	m.shipments[shipment.ID] = copyShipment(shipment)
+	return nil
+}
+
+func (m *MemoryStorage) getShipmentByID(id int) (*models.Shipment, error) {
+	shipment, exists := m.shipments[id]
+	if !exists {
+		return nil, notFound("shipment")
+	}
+	return copyShipment(shipment), nil
+}
+
+func (m *MemoryStorage) getShipmentsByOrder(orderID int) []models.Shipment {
+	shipments := []models.Shipment{}
+	for _, s := range m.shipments {
+		if s.OrderID == orderID {
+			shipments = append(shipments, *copyShipment(s))
+		}
+	}
+	sort.Slice(shipments, func(i, j int) bool { return shipments[i].ID < shipments[j].ID })
+	return shipments
+}
+
+// updateShipment, как и UPDATE в PostgreSQL, не меняет состав позиций.
+func (m *MemoryStorage) updateShipment(shipment *models.Shipment) error {
+	stored, exists := m.shipments[shipment.ID]
+	if !exists {
+		return notFound("shipment")
+	}
+	updated := copyShipment(stored)
+	updated.Status = shipment.Status
+	updated.Carrier = shipment.Carrier
+	updated.TrackingNumber = shipment.TrackingNumber
+	updated.ShippedAt = shipment.ShippedAt
+	updated.DeliveredAt = shipment.DeliveredAt
+	updated.UpdatedAt = shipment.UpdatedAt
+	m.shipments[shipment.ID] = updated
+	return nil
+}
+
+func copyShipment(shipment *models.Shipment) *models.Shipment {
+	c := *shipment
+	c.Items = append([]models.ShipmentItem{}, shipment.Items...)
+	return &c
+}
@@ -0,0 +1,34 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStorage_ErrNotFound(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemoryStorage()
+
+	tests := []struct {
+		name string
+		call func() error
+		text string
+	}{
+		{name: "product by id", call: func() error { _, err := m.GetProductByID(ctx, 42); return err }, text: "product not found"},
+		{name: "product by sku", call: func() error { _, err := m.GetProductBySKU(ctx, "NOPE"); return err }, text: "product not found"},
+		{name: "active cart", call: func() error { _, err := m.GetActiveCartByUser(ctx, 7); return err }, text: "cart not found"},
+		{name: "order", call: func() error { return m.DeleteOrder(ctx, 3) }, text: "order not found"},
+		{name: "payment", call: func() error { _, err := m.GetPaymentByID(ctx, 5); return err }, text: "payment not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			assert.True(t, errors.Is(err, ErrNotFound))
+			assert.EqualError(t, err, tt.text)
+		})
+	}
+}
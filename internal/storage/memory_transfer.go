@@ -0,0 +1,103 @@
+package storage
+
+import (
+	"backend-store/internal/models"
+	"context"
+	"fmt"
+	"sort"
+)
+
+func (m *MemoryStorage) CreateTransfer(ctx context.Context, transfer *models.StockTransfer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.createTransfer(transfer)
+}
+
+func (m *MemoryStorage) GetTransferByID(ctx context.Context, id int) (*models.StockTransfer, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getTransferByID(id)
+}
+
+func (m *MemoryStorage) GetAllTransfers(ctx context.Context, status string) ([]*models.StockTransfer, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getAllTransfers(status), nil
+}
+
+func (m *MemoryStorage) UpdateTransfer(ctx context.Context, transfer *models.StockTransfer, from string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.updateTransfer(transfer, from)
+}
+
+func (mt *MemoryTx) CreateTransfer(ctx context.Context, transfer *models.StockTransfer) error {
+	return mt.storage.createTransfer(transfer)
+}
+
+func (mt *MemoryTx) GetTransferByID(ctx context.Context, id int) (*models.StockTransfer, error) {
+	return mt.storage.getTransferByID(id)
+}
+
+func (mt *MemoryTx) GetAllTransfers(ctx context.Context, status string) ([]*models.StockTransfer, error) {
+	return mt.storage.getAllTransfers(status), nil
+}
+
+func (mt *MemoryTx) UpdateTransfer(ctx context.Context, transfer *models.StockTransfer, from string) error {
+	return mt.storage.updateTransfer(transfer, from)
+}
+
+func (m *MemoryStorage) createTransfer(transfer *models.StockTransfer) error {
+	if _, exists := m.warehouses[transfer.FromWarehouseID]; !exists {
+		return notFound("warehouse")
+	}
+	if _, exists := m.warehouses[transfer.ToWarehouseID]; !exists {
+		return notFound("warehouse")
+	}
+	m.transferIDSeq++
+	transfer.ID = m.transferIDSeq
+	for i := range transfer.Items {
+		m.transferItemIDSeq++
+		transfer.Items[i].ID = m.transferItemIDSeq
+		transfer.Items[i].TransferID = transfer.ID
+	}
+	m.transfers[transfer.ID] = transfer
+	return nil
+}
+
+func (m *MemoryStorage) getTransferByID(id int) (*models.StockTransfer, error) {
+	transfer, exists := m.transfers[id]
+	if !exists {
+		return nil, notFound("transfer")
+	}
+	return transfer, nil
+}
+
+func (m *MemoryStorage) getAllTransfers(status string) []*models.StockTransfer {
+	transfers := make([]*models.StockTransfer, 0)
+	for _, t := range m.transfers {
+		if status == "" || t.Status == status {
+			transfers = append(transfers, t)
+		}
+	}
+	sort.Slice(transfers, func(i, j int) bool { return transfers[i].ID < transfers[j].ID })
+	return transfers
+}
+
+// updateTransfer меняет только статус и отметки времени, как и в PostgreSQL.
+func (m *MemoryStorage) updateTransfer(transfer *models.StockTransfer, from string) error {
+	existing, exists := m.transfers[transfer.ID]
+	if !exists {
+		return notFound("transfer")
+	}
+	if existing.Status != from {
+		return fmt.Errorf("cannot change status of transfer %d: it is no longer %q", transfer.ID, from)
+	}
+	updated := *existing
+	updated.Status = transfer.Status
+	updated.ShippedAt = transfer.ShippedAt
+	updated.ReceivedAt = transfer.ReceivedAt
+	updated.UpdatedAt = transfer.UpdatedAt
+	m.transfers[transfer.ID] = &updated
+	return nil
+}
@@ -0,0 +1,123 @@
+package storage
+
+import (
+	"backend-store/internal/models"
+	"context"
+	"fmt"
+	"sort"
+)
+
+func (m *MemoryStorage) CreateVariant(ctx context.Context, variant *models.ProductVariant) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.createVariant(variant)
+}
+
+func (m *MemoryStorage) GetVariantByID(ctx context.Context, id int) (*models.ProductVariant, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getVariantByID(id)
+}
+
+func (m *MemoryStorage) GetVariantsByProductID(ctx context.Context, productID int) ([]*models.ProductVariant, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getVariantsByProductID(productID), nil
+}
+
+func (m *MemoryStorage) UpdateVariant(ctx context.Context, variant *models.ProductVariant) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.updateVariant(variant)
+}
+
+func (m *MemoryStorage) DeleteVariant(ctx context.Context, id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.deleteVariant(id)
+}
+
+func (mt *MemoryTx) CreateVariant(ctx context.Context, variant *models.ProductVariant) error {
+	return mt.storage.createVariant(variant)
+}
+
+func (mt *MemoryTx) GetVariantByID(ctx context.Context, id int) (*models.ProductVariant, error) {
+	return mt.storage.getVariantByID(id)
+}
+
+func (mt *MemoryTx) GetVariantsByProductID(ctx context.Context, productID int) ([]*models.ProductVariant, error) {
+	return mt.storage.getVariantsByProductID(productID), nil
+}
+
+func (mt *MemoryTx) UpdateVariant(ctx context.Context, variant *models.ProductVariant) error {
+	return mt.storage.updateVariant(variant)
+}
+
+func (mt *MemoryTx) DeleteVariant(ctx context.Context, id int) error {
+	return mt.storage.deleteVariant(id)
+}
+
+func (m *MemoryStorage) createVariant(variant *models.ProductVariant) error {
+	if _, exists := m.products[variant.ProductID]; !exists {
+		return notFound("product")
+	}
+	if err := m.checkVariantSKU(variant); err != nil {
+		return err
+	}
+	m.variantIDSeq++
+	variant.ID = m.variantIDSeq
+	m.variants[variant.ID] = variant
+	return nil
+}
+
+func (m *MemoryStorage) getVariantByID(id int) (*models.ProductVariant, error) {
+	variant, exists := m.variants[id]
+	if !exists {
+		return nil, notFound("variant")
+	}
+	return variant, nil
+}
+
+func (m *MemoryStorage) getVariantsByProductID(productID int) []*models.ProductVariant {
+	variants := make([]*models.ProductVariant, 0)
+	for _, v := range m.variants {
+		if v.ProductID == productID {
+			variants = append(variants, v)
+		}
+	}
+	sort.Slice(variants, func(i, j int) bool { return variants[i].ID < variants[j].ID })
+	return variants
+}
+
+func (m *MemoryStorage) updateVariant(variant *models.ProductVariant) error {
+	if _, exists := m.variants[variant.ID]; !exists {
+		return notFound("variant")
+	}
+	if err := m.checkVariantSKU(variant); err != nil {
+		return err
+	}
+	m.variants[variant.ID] = variant
+	return nil
+}
+
+func (m *MemoryStorage) deleteVariant(id int) error {
+	if _, exists := m.variants[id]; !exists {
+		return notFound("variant")
+	}
+	delete(m.variants, id)
+	m.deleteVariantStock(id)
+	m.deleteCartItems(func(item *models.CartItem) bool { return item.VariantID == id })
+	return nil
+}
+
+func (m *MemoryStorage) checkVariantSKU(variant *models.ProductVariant) error {
+	if variant.SKU == "" {
+		return nil
+	}
+	for _, v := range m.variants {
+		if v.SKU == variant.SKU && v.ID != variant.ID {
+			return fmt.Errorf("variant with sku %q already exists", variant.SKU)
+		}
+	}
+	return nil
+}
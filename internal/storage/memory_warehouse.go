@@ -0,0 +1,298 @@
+package storage
+
+import (
+	"backend-store/internal/models"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+type stockKey struct {
+	warehouseID int
+	variantID   int
+}
+
+func (m *MemoryStorage) CreateWarehouse(ctx context.Context, warehouse *models.Warehouse) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.createWarehouse(warehouse)
+}
+
+func (m *MemoryStorage) GetWarehouseByID(ctx context.Context, id int) (*models.Warehouse, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getWarehouseByID(id)
+}
+
+func (m *MemoryStorage) GetAllWarehouses(ctx context.Context) ([]*models.Warehouse, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getAllWarehouses(), nil
+}
+
+func (m *MemoryStorage) UpdateWarehouse(ctx context.Context, warehouse *models.Warehouse) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.updateWarehouse(warehouse)
+}
+
+func (m *MemoryStorage) DeleteWarehouse(ctx context.Context, id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.deleteWarehouse(id)
+}
+
+func (m *MemoryStorage) GetStockLevelsByVariant(ctx context.Context, variantID int) ([]models.StockLevel, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getStockLevels(func(l *models.StockLevel) bool { return l.VariantID == variantID }), nil
+}
+
+func (m *MemoryStorage) GetStockLevelsByProduct(ctx context.Context, productID int) ([]models.StockLevel, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getStockLevels(func(l *models.StockLevel) bool { return l.ProductID == productID }), nil
+}
+
+func (m *MemoryStorage) GetStockLevelsByWarehouse(ctx context.Context, warehouseID int) ([]models.StockLevel, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getStockLevels(func(l *models.StockLevel) bool { return l.WarehouseID == warehouseID }), nil
+}
+
+func (m *MemoryStorage) AdjustStockLevel(ctx context.Context, warehouseID, variantID, delta int) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.adjustStockLevel(warehouseID, variantID, delta)
+}
+
+func (m *MemoryStorage) CreateAllocation(ctx context.Context, allocation *models.StockAllocation) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.createAllocation(allocation)
+}
+
+func (m *MemoryStorage) GetAllocationsByOrder(ctx context.Context, orderID int) ([]models.StockAllocation, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getAllocationsByOrder(orderID), nil
+}
+
+func (m *MemoryStorage) DeleteAllocationsByOrder(ctx context.Context, orderID int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deleteAllocationsByOrder(orderID)
+	return nil
+}
+
+func (mt *MemoryTx) CreateWarehouse(ctx context.Context, warehouse *models.Warehouse) error {
+	return mt.storage.createWarehouse(warehouse)
+}
+
+func (mt *MemoryTx) GetWarehouseByID(ctx context.Context, id int) (*models.Warehouse, error) {
+	return mt.storage.getWarehouseByID(id)
+}
+
+func (mt *MemoryTx) GetAllWarehouses(ctx context.Context) ([]*models.Warehouse, error) {
+	return mt.storage.getAllWarehouses(), nil
+}
+
+func (mt *MemoryTx) UpdateWarehouse(ctx context.Context, warehouse *models.Warehouse) error {
+	return mt.storage.updateWarehouse(warehouse)
+}
+
+func (mt *MemoryTx) DeleteWarehouse(ctx context.Context, id int) error {
+	return mt.storage.deleteWarehouse(id)
+}
+
+func (mt *MemoryTx) GetStockLevelsByVariant(ctx context.Context, variantID int) ([]models.StockLevel, error) {
+	return mt.storage.getStockLevels(func(l *models.StockLevel) bool { return l.VariantID == variantID }), nil
+}
+
+func (mt *MemoryTx) GetStockLevelsByProduct(ctx context.Context, productID int) ([]models.StockLevel, error) {
+	return mt.storage.getStockLevels(func(l *models.StockLevel) bool { return l.ProductID == productID }), nil
+}
+
+func (mt *MemoryTx) GetStockLevelsByWarehouse(ctx context.Context, warehouseID int) ([]models.StockLevel, error) {
+	return mt.storage.getStockLevels(func(l *models.StockLevel) bool { return l.WarehouseID == warehouseID }), nil
+}
+
+func (mt *MemoryTx) AdjustStockLevel(ctx context.Context, warehouseID, variantID, delta int) (int, error) {
+	return mt.storage.adjustStockLevel(warehouseID, variantID, delta)
+}
+
+func (mt *MemoryTx) CreateAllocation(ctx context.Context, allocation *models.StockAllocation) error {
+	return mt.storage.createAllocation(allocation)
+}
+
+func (mt *MemoryTx) GetAllocationsByOrder(ctx context.Context, orderID int) ([]models.StockAllocation, error) {
+	return mt.storage.getAllocationsByOrder(orderID), nil
+}
+
+func (mt *MemoryTx) DeleteAllocationsByOrder(ctx context.Context, orderID int) error {
+	mt.storage.deleteAllocationsByOrder(orderID)
+	return nil
+}
+
+func (m *MemoryStorage) createWarehouse(warehouse *models.Warehouse) error {
+	if err := m.checkWarehouseCode(warehouse); err != nil {
+		return err
+	}
+	m.warehouseIDSeq++
+	warehouse.ID = m.warehouseIDSeq
+	m.warehouses[warehouse.ID] = warehouse
+	return nil
+}
+
+func (m *MemoryStorage) getWarehouseByID(id int) (*models.Warehouse, error) {
+	warehouse, exists := m.warehouses[id]
+	if !exists {
+		return nil, notFound("warehouse")
+	}
+	return warehouse, nil
+}
+
+func (m *MemoryStorage) getAllWarehouses() []*models.Warehouse {
+	warehouses := make([]*models.Warehouse, 0, len(m.warehouses))
+	for _, w := range m.warehouses {
+		warehouses = append(warehouses, w)
+	}
+	sort.Slice(warehouses, func(i, j int) bool {
+		if warehouses[i].Priority != warehouses[j].Priority {
+			return warehouses[i].Priority < warehouses[j].Priority
+		}
+		return warehouses[i].ID < warehouses[j].ID
+	})
+	return warehouses
+}
+
+func (m *MemoryStorage) updateWarehouse(warehouse *models.Warehouse) error {
+	if _, exists := m.warehouses[warehouse.ID]; !exists {
+		return notFound("warehouse")
+	}
+	if err := m.checkWarehouseCode(warehouse); err != nil {
+		return err
+	}
+	m.warehouses[warehouse.ID] = warehouse
+	return nil
+}
+
+// deleteWarehouse повторяет внешние ключи PostgreSQL: остатки удаляются
+// каскадно, ссылки из заказов, перемещений и журнала движения запрещают удаление.
+func (m *MemoryStorage) deleteWarehouse(id int) error {
+	if _, exists := m.warehouses[id]; !exists {
+		return notFound("warehouse")
+	}
+	referenced := false
+	for _, a := range m.allocations {
+		referenced = referenced || a.WarehouseID == id
+	}
+	for _, t := range m.transfers {
+		referenced = referenced || t.FromWarehouseID == id || t.ToWarehouseID == id
+	}
+	for _, mv := range m.movements {
+		referenced = referenced || mv.WarehouseID == id
+	}
+	if referenced {
+		return errors.New("cannot delete warehouse referenced by orders, transfers or stock movements; deactivate it instead")
+	}
+	delete(m.warehouses, id)
+	for key := range m.stock {
+		if key.warehouseID == id {
+			delete(m.stock, key)
+		}
+	}
+	return nil
+}
+
+func (m *MemoryStorage) checkWarehouseCode(warehouse *models.Warehouse) error {
+	for _, w := range m.warehouses {
+		if w.Code == warehouse.Code && w.ID != warehouse.ID {
+			return fmt.Errorf("warehouse with code %q already exists", warehouse.Code)
+		}
+	}
+	return nil
+}
+
+func (m *MemoryStorage) getStockLevels(match func(*models.StockLevel) bool) []models.StockLevel {
+	levels := []models.StockLevel{}
+	for _, l := range m.stock {
+		if match(l) {
+			levels = append(levels, *l)
+		}
+	}
+	sort.Slice(levels, func(i, j int) bool {
+		if levels[i].WarehouseID != levels[j].WarehouseID {
+			return levels[i].WarehouseID < levels[j].WarehouseID
+		}
+		return levels[i].VariantID < levels[j].VariantID
+	})
+	return levels
+}
+
+func (m *MemoryStorage) adjustStockLevel(warehouseID, variantID, delta int) (int, error) {
+	variant, exists := m.variants[variantID]
+	if _, ok := m.warehouses[warehouseID]; !ok || !exists {
+		return 0, notFound("warehouse or variant")
+	}
+
+	key := stockKey{warehouseID: warehouseID, variantID: variantID}
+	level, exists := m.stock[key]
+	if !exists {
+		level = &models.StockLevel{WarehouseID: warehouseID, VariantID: variantID, ProductID: variant.ProductID}
+	}
+	if level.Quantity+delta < 0 {
+		return 0, fmt.Errorf("insufficient stock in warehouse %d for variant %d", warehouseID, variantID)
+	}
+	level.Quantity += delta
+	level.UpdatedAt = time.Now()
+	m.stock[key] = level
+	return level.Quantity, nil
+}
+
+// deleteVariantStock повторяет ON DELETE CASCADE для остатков и распределений.
+func (m *MemoryStorage) deleteVariantStock(variantID int) {
+	for key := range m.stock {
+		if key.variantID == variantID {
+			delete(m.stock, key)
+		}
+	}
+	for id, a := range m.allocations {
+		if a.VariantID == variantID {
+			delete(m.allocations, id)
+		}
+	}
+}
+
+func (m *MemoryStorage) createAllocation(allocation *models.StockAllocation) error {
+	if _, exists := m.orders[allocation.OrderID]; !exists {
+		return notFound("order")
+	}
+	m.allocationIDSeq++
+	allocation.ID = m.allocationIDSeq
+	allocation.CreatedAt = time.Now()
+	stored := *allocation
+	m.allocations[allocation.ID] = &stored
+	return nil
+}
+
+func (m *MemoryStorage) getAllocationsByOrder(orderID int) []models.StockAllocation {
+	allocations := []models.StockAllocation{}
+	for _, a := range m.allocations {
+		if a.OrderID == orderID {
+			allocations = append(allocations, *a)
+		}
+	}
+	sort.Slice(allocations, func(i, j int) bool { return allocations[i].ID < allocations[j].ID })
+	return allocations
+}
+
+func (m *MemoryStorage) deleteAllocationsByOrder(orderID int) {
+	for id, a := range m.allocations {
+		if a.OrderID == orderID {
+			delete(m.allocations, id)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package storage
+
+import (
+	"backend-store/internal/models"
+	"context"
+	"sort"
+	"time"
+)
+
+func (m *MemoryStorage) CreateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.createWebhook(webhook)
+}
+
+func (m *MemoryStorage) GetWebhookByID(ctx context.Context, id int) (*models.Webhook, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getWebhookByID(id)
+}
+
+func (m *MemoryStorage) GetWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getWebhooks(), nil
+}
+
+func (m *MemoryStorage) UpdateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.updateWebhook(webhook)
+}
+
+func (m *MemoryStorage) DeleteWebhook(ctx context.Context, id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.deleteWebhook(id)
+}
+
+func (m *MemoryStorage) CreateWebhookDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.createWebhookDelivery(delivery)
+}
+
+func (m *MemoryStorage) GetWebhookDeliveryByID(ctx context.Context, id int) (*models.WebhookDelivery, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getWebhookDeliveryByID(id)
+}
+
+func (m *MemoryStorage) GetWebhookDeliveries(ctx context.Context, filter models.WebhookDeliveryFilter) ([]models.WebhookDelivery, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getWebhookDeliveries(filter), nil
+}
+
+func (m *MemoryStorage) ClaimWebhookDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.claimWebhookDeliveries(now, lease, limit), nil
+}
+
+func (m *MemoryStorage) UpdateWebhookDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.updateWebhookDelivery(delivery)
+}
+
+func (mt *MemoryTx) CreateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	return mt.storage.createWebhook(webhook)
+}
+
+func (mt *MemoryTx) GetWebhookByID(ctx context.Context, id int) (*models.Webhook, error) {
+	return mt.storage.getWebhookByID(id)
+}
+
+func (mt *MemoryTx) GetWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	return mt.storage.getWebhooks(), nil
+}
+
+func (mt *MemoryTx) UpdateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	return mt.storage.updateWebhook(webhook)
+}
+
+func (mt *MemoryTx) DeleteWebhook(ctx context.Context, id int) error {
+	return mt.storage.deleteWebhook(id)
+}
+
+func (mt *MemoryTx) CreateWebhookDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	return mt.storage.createWebhookDelivery(delivery)
+}
+
+func (mt *MemoryTx) GetWebhookDeliveryByID(ctx context.Context, id int) (*models.WebhookDelivery, error) {
+	return mt.storage.getWebhookDeliveryByID(id)
+}
+
+func (mt *MemoryTx) GetWebhookDeliveries(ctx context.Context, filter models.WebhookDeliveryFilter) ([]models.WebhookDelivery, error) {
+	return mt.storage.getWebhookDeliveries(filter), nil
+}
+
+func (mt *MemoryTx) ClaimWebhookDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error) {
+	return mt.storage.claimWebhookDeliveries(now, lease, limit), nil
+}
+
+func (mt *MemoryTx) UpdateWebhookDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	return mt.storage.updateWebhookDelivery(delivery)
+}
+
+func copyWebhook(webhook *models.Webhook) *models.Webhook {
+	result := *webhook
+	result.EventTypes = append([]string(nil), webhook.EventTypes...)
+	return &result
+}
+
+func (m *MemoryStorage) createWebhook(webhook *models.Webhook) error {
+	m.webhookIDSeq++
+	webhook.ID = m.webhookIDSeq
+	m.webhooks[webhook.ID] = copyWebhook(webhook)
+	return nil
+}
+
+func (m *MemoryStorage) getWebhookByID(id int) (*models.Webhook, error) {
+	webhook, exists := m.webhooks[id]
+	if !exists {
+		return nil, notFound("webhook")
+	}
+	return copyWebhook(webhook), nil
+}
+
+func (m *MemoryStorage) getWebhooks() []models.Webhook {
+	webhooks := make([]models.Webhook, 0, len(m.webhooks))
+	for _, w := range m.webhooks {
+		webhooks = append(webhooks, *copyWebhook(w))
+	}
+	sort.Slice(webhooks, func(i, j int) bool { return webhooks[i].ID < webhooks[j].ID })
+	return webhooks
+}
+
+func (m *MemoryStorage) updateWebhook(webhook *models.Webhook) error {
+	if _, exists := m.webhooks[webhook.ID]; !exists {
+		return notFound("webhook")
+	}
+	m.webhooks[webhook.ID] = copyWebhook(webhook)
+	return nil
+}
+
+func (m *MemoryStorage) deleteWebhook(id int) error {
+	if _, exists := m.webhooks[id]; !exists {
+		return notFound("webhook")
+	}
+	delete(m.webhooks, id)
+	for deliveryID, d := range m.webhookDeliveries {
+		if d.WebhookID == id {
+			delete(m.webhookDeliveries, deliveryID)
+		}
+	}
+	return nil
+}
+
+func (m *MemoryStorage) createWebhookDelivery(delivery *models.WebhookDelivery) error {
+	if _, exists := m.webhooks[delivery.WebhookID]; !exists {
+		return notFound("webhook")
+	}
+	m.deliveryIDSeq++
+	delivery.ID = m.deliveryIDSeq
+	stored := *delivery
+	m.webhookDeliveries[delivery.ID] = &stored
+	return nil
+}
+
+func (m *MemoryStorage) getWebhookDeliveryByID(id int) (*models.WebhookDelivery, error) {
+	delivery, exists := m.webhookDeliveries[id]
+	if !exists {
+		return nil, notFound("webhook delivery")
+	}
+	result := *delivery
+	return &result, nil
+}
+
+func (m *MemoryStorage) getWebhookDeliveries(filter models.WebhookDeliveryFilter) []models.WebhookDelivery {
+	deliveries := []models.WebhookDelivery{}
+	for _, d := range m.webhookDeliveries {
+		if filter.WebhookID != 0 && d.WebhookID != filter.WebhookID {
+			continue
+		}
+		if filter.EventID != 0 && d.EventID != filter.EventID {
+			continue
+		}
+		if filter.Status != "" && d.Status != filter.Status {
+			continue
+		}
+		deliveries = append(deliveries, *d)
+	}
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].ID > deliveries[j].ID })
+	if filter.Limit > 0 && len(deliveries) > filter.Limit {
+		deliveries = deliveries[:filter.Limit]
+	}
+	return deliveries
+}
+
+// claimWebhookDeliveries выдает доставки в порядке создания.
+func (m *MemoryStorage) claimWebhookDeliveries(now time.Time, lease time.Duration, limit int) []models.WebhookDelivery {
+	var due []*models.WebhookDelivery
+	for _, d := range m.webhookDeliveries {
+		if d.Status == models.WebhookDeliveryPending && !d.NextAttemptAt.After(now) {
+			due = append(due, d)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].ID < due[j].ID })
+	if limit > 0 && len(due) > limit {
+		due = due[:limit]
+	}
+
+	deliveries := make([]models.WebhookDelivery, 0, len(due))
+	for _, d := range due {
+		d.NextAttemptAt = now.Add(lease)
+		deliveries = append(deliveries, *d)
+	}
+	return deliveries
+}
+
+func (m *MemoryStorage) updateWebhookDelivery(delivery *models.WebhookDelivery) error {
+	if _, exists := m.webhookDeliveries[delivery.ID]; !exists {
+		return notFound("webhook delivery")
+	}
+	stored := *delivery
+	m.webhookDeliveries[delivery.ID] = &stored
+	return nil
+}
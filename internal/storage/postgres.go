@@ -9,7 +9,7 @@ import (
 	"time"
 
 	"github.com/jmoiron/sqlx"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
 type PostgresStorage struct {
@@ -20,6 +20,25 @@ type PostgresTx struct {
 	tx *sqlx.Tx
 }
 
+// queryer - общий интерфейс *sqlx.DB и *sqlx.Tx, позволяющий писать запрос
+// один раз для PostgresStorage и PostgresTx.
+type queryer interface {
+	sqlx.ExtContext
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+const productColumns = `id, COALESCE(sku, '') AS sku, name, description, price, quantity, reorder_point, reorder_quantity, COALESCE(tax_class, '') AS tax_class,
+	weight, length, width, height, COALESCE(options, '[]') AS options, created_at, updated_at`
+
+const orderColumns = `id, customer_id, status, total, shipping_address, created_at, updated_at,
+	subtotal, discount, COALESCE(promo_code, '') AS promo_code, free_shipping, tax, tax_inclusive,
+	COALESCE(shipping_method, '') AS shipping_method, shipping`
+
+const orderItemColumns = `id, order_id, product_id, COALESCE(variant_id, 0) AS variant_id, quantity, price,
+	COALESCE(tax_class, '') AS tax_class, tax, cost`
+
 func NewPostgresStorage(databaseURL string) (*PostgresStorage, error) {
 	db, err := sqlx.Connect("postgres", databaseURL)
 	if err != nil {
@@ -74,6 +93,12 @@ func (p *PostgresStorage) Init() error {
 		return fmt.Errorf("failed to create order_items table: %w", err)
 	}
 
+	for _, m := range schemaMigrations {
+		if _, err := p.db.Exec(m.stmt); err != nil {
+			return fmt.Errorf("failed to apply %s: %w", m.name, err)
+		}
+	}
+
 	return nil
 }
 
@@ -103,273 +128,207 @@ func (p *PostgresStorage) BeginTx(ctx context.Context) (StorageTx, error) {
 	return &PostgresTx{tx: tx}, nil
 }
 
-func (p *PostgresStorage) CreateProduct(ctx context.Context, product *models.Product) error {
-	query := `
-	INSERT INTO products (name, description, price, quantity) 
-	VALUES ($1, $2, $3, $4) 
-	RETURNING id, created_at, updated_at`
+// Методы PostgresStorage и PostgresTx делегируют запросы общим функциям
+// над queryer, чтобы SQL не расходился между обычным и транзакционным режимом.
 
-	return p.db.QueryRowContext(ctx,
-		query,
-		product.Name,
-		product.Description,
-		product.Price,
-		product.Quantity,
-	).Scan(&product.ID, &product.CreatedAt, &product.UpdatedAt)
+func (p *PostgresStorage) CreateProduct(ctx context.Context, product *models.Product) error {
+	return createProduct(ctx, p.db, product)
 }
 
 func (p *PostgresStorage) GetAllProducts(ctx context.Context) ([]*models.Product, error) {
-	query := `SELECT id, name, description, price, quantity, created_at, updated_at FROM products`
-	var products []*models.Product
-	err := p.db.SelectContext(ctx, &products, query)
-	return products, err
+	return getAllProducts(ctx, p.db)
 }
 
 func (p *PostgresStorage) GetProductByID(ctx context.Context, id int) (*models.Product, error) {
-	query := `SELECT id, name, description, price, quantity, created_at, updated_at FROM products WHERE id = $1`
-	var product models.Product
-	err := p.db.GetContext(ctx, &product, query, id)
-	if err == sql.ErrNoRows {
-		return nil, errors.New("product not found")
-	}
-	return &product, err
+	return getProductByID(ctx, p.db, id)
+}
+
+func (p *PostgresStorage) GetProductBySKU(ctx context.Context, sku string) (*models.Product, error) {
+	return getProductBySKU(ctx, p.db, sku)
 }
 
 func (p *PostgresStorage) UpdateProduct(ctx context.Context, product *models.Product) error {
-	query := `
-		UPDATE products 
-		SET name = $1, description = $2, price = $3, quantity = $4, updated_at = CURRENT_TIMESTAMP 
-		WHERE id = $5
-	`
-	result, err := p.db.ExecContext(ctx, query,
-		product.Name,
-		product.Description,
-		product.Price,
-		product.Quantity,
-		product.ID,
-	)
-	if err != nil {
-		return err
-	}
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected == 0 {
-		return errors.New("product not found")
-	}
-	return nil
+	return updateProduct(ctx, p.db, product)
 }
 
 func (p *PostgresStorage) DeleteProduct(ctx context.Context, id int) error {
-	query := `DELETE FROM products WHERE id = $1`
-	result, err := p.db.ExecContext(ctx, query, id)
-	if err != nil {
-		return err
-	}
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected == 0 {
-		return errors.New("product not found")
-	}
-	return nil
+	return deleteProduct(ctx, p.db, id)
 }
 
 func (p *PostgresStorage) CreateOrder(ctx context.Context, order *models.Order) error {
-	total := 0
-	for _, item := range order.Products {
-		var productPrice int
-		err := p.db.GetContext(ctx, &productPrice, "SELECT price FROM products WHERE id = $1", item.ProductID)
-		if err != nil {
-			return fmt.Errorf("failed to get product price: %w", err)
-		}
-		total += productPrice * item.Quantity
-		item.Price = productPrice
-	}
-	order.Total = total
+	return createOrder(ctx, p.db, order)
+}
 
-	orderQuery := `
-		INSERT INTO orders (user_id, status, total) 
-		VALUES ($1, $2, $3) 
-		RETURNING id, created_at, updated_at`
+func (p *PostgresStorage) GetAllOrders(ctx context.Context) ([]*models.Order, error) {
+	return getAllOrders(ctx, p.db)
+}
 
-	err := p.db.QueryRowContext(ctx,
-		orderQuery,
-		order.ID,
-		order.Status,
-		order.Total,
-	).Scan(&order.ID, &order.CreatedAt, &order.UpdatedAt)
-	if err != nil {
-		return err
-	}
+func (p *PostgresStorage) GetOrderByID(ctx context.Context, id int) (*models.Order, error) {
+	return getOrderByID(ctx, p.db, id)
+}
 
-	itemQuery := `
-		INSERT INTO order_items (order_id, product_id, quantity, price) 
-		VALUES ($1, $2, $3, $4)`
+func (p *PostgresStorage) UpdateOrder(ctx context.Context, order *models.Order) error {
+	return updateOrder(ctx, p.db, order)
+}
 
-	for _, item := range order.Products {
-		_, err = p.db.ExecContext(ctx, itemQuery, order.ID, item.ProductID, item.Quantity, item.Price)
-		if err != nil {
-			return fmt.Errorf("failed to create order item: %w", err)
-		}
-	}
+func (p *PostgresStorage) UpdateOrderStatus(ctx context.Context, id int, status string) error {
+	return updateOrderStatus(ctx, p.db, id, status)
+}
 
-	return nil
+func (p *PostgresStorage) DeleteOrder(ctx context.Context, id int) error {
+	return deleteOrder(ctx, p.db, id)
 }
 
-func (p *PostgresStorage) GetAllOrders(ctx context.Context) ([]*models.Order, error) {
-	query := `SELECT id, user_id, status, total, created_at, updated_at FROM orders`
-	var orders []*models.Order
-	err := p.db.SelectContext(ctx, &orders, query)
-	if err != nil {
-		return nil, err
-	}
+func (p *PostgresStorage) UpdateProductQuantity(ctx context.Context, id int, quantity int) error {
+	query := `UPDATE products SET quantity = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2`
+	_, err := p.db.ExecContext(ctx, query, quantity, id)
+	return err
+}
 
-	for i := range orders {
-		itemsQuery := `
-			SELECT id, order_id, product_id, quantity, price 
-			FROM order_items 
-			WHERE order_id = $1`
+func (pt *PostgresTx) BeginTx(ctx context.Context) (StorageTx, error) {
+	return pt, nil
+}
 
-		var items []models.OrderItem
-		err := p.db.SelectContext(ctx, &items, itemsQuery, orders[i].ID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get order items: %w", err)
-		}
-		orders[i].Products = items
-	}
+func (pt *PostgresTx) CreateProduct(ctx context.Context, product *models.Product) error {
+	return createProduct(ctx, pt.tx, product)
+}
 
-	return orders, nil
+func (pt *PostgresTx) GetAllProducts(ctx context.Context) ([]*models.Product, error) {
+	return getAllProducts(ctx, pt.tx)
 }
 
-func (p *PostgresStorage) GetOrderByID(ctx context.Context, id int) (*models.Order, error) {
-	query := `SELECT id, user_id, status, total, created_at, updated_at FROM orders WHERE id = $1`
-	var order models.Order
-	err := p.db.GetContext(ctx, &order, query, id)
-	if err == sql.ErrNoRows {
-		return nil, errors.New("order not found")
-	}
-	if err != nil {
-		return nil, err
-	}
+func (pt *PostgresTx) GetProductByID(ctx context.Context, id int) (*models.Product, error) {
+	return getProductByID(ctx, pt.tx, id)
+}
 
-	itemsQuery := `
-		SELECT id, order_id, product_id, quantity, price 
-		FROM order_items 
-		WHERE order_id = $1`
+func (pt *PostgresTx) GetProductBySKU(ctx context.Context, sku string) (*models.Product, error) {
+	return getProductBySKU(ctx, pt.tx, sku)
+}
 
-	var items []models.OrderItem
-	err = p.db.SelectContext(ctx, &items, itemsQuery, id)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get order items: %w", err)
-	}
-	order.Products = items
+func (pt *PostgresTx) UpdateProduct(ctx context.Context, product *models.Product) error {
+	return updateProduct(ctx, pt.tx, product)
+}
 
-	return &order, nil
+func (pt *PostgresTx) DeleteProduct(ctx context.Context, id int) error {
+	return deleteProduct(ctx, pt.tx, id)
 }
 
-func (p *PostgresStorage) UpdateOrder(ctx context.Context, order *models.Order) error {
-	total := 0
-	for _, item := range order.Products {
-		var productPrice int
-		err := p.db.GetContext(ctx, &productPrice, "SELECT price FROM products WHERE id = $1", item.ProductID)
-		if err != nil {
-			return fmt.Errorf("failed to get product price: %w", err)
-		}
-		total += productPrice * item.Quantity
-		item.Price = productPrice
-	}
-	order.Total = total
+func (pt *PostgresTx) CreateOrder(ctx context.Context, order *models.Order) error {
+	return createOrder(ctx, pt.tx, order)
+}
 
-	query := `
-		UPDATE orders 
-		SET user_id = $1, status = $2, total = $3, updated_at = CURRENT_TIMESTAMP 
-		WHERE id = $4`
+func (pt *PostgresTx) GetAllOrders(ctx context.Context) ([]*models.Order, error) {
+	return getAllOrders(ctx, pt.tx)
+}
 
-	result, err := p.db.ExecContext(ctx, query, order.ID, order.Status, order.Total)
-	if err != nil {
-		return err
-	}
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected == 0 {
-		return errors.New("order not found")
-	}
+func (pt *PostgresTx) GetOrderByID(ctx context.Context, id int) (*models.Order, error) {
+	return getOrderByID(ctx, pt.tx, id)
+}
 
-	_, err = p.db.ExecContext(ctx, "DELETE FROM order_items WHERE order_id = $1", order.ID)
-	if err != nil {
-		return fmt.Errorf("failed to delete old order items: %w", err)
-	}
+func (pt *PostgresTx) UpdateOrder(ctx context.Context, order *models.Order) error {
+	return updateOrder(ctx, pt.tx, order)
+}
 
-	itemQuery := `
-		INSERT INTO order_items (order_id, product_id, quantity, price) 
-		VALUES ($1, $2, $3, $4)`
+func (pt *PostgresTx) UpdateOrderStatus(ctx context.Context, id int, status string) error {
+	return updateOrderStatus(ctx, pt.tx, id, status)
+}
 
-	for _, item := range order.Products {
-		_, err = p.db.ExecContext(ctx, itemQuery, order.ID, item.ProductID, item.Quantity, item.Price)
-		if err != nil {
-			return fmt.Errorf("failed to create order item: %w", err)
-		}
-	}
+func (pt *PostgresTx) DeleteOrder(ctx context.Context, id int) error {
+	return deleteOrder(ctx, pt.tx, id)
+}
 
-	return nil
+func (pt *PostgresTx) Commit() error {
+	return pt.tx.Commit()
 }
 
-func (p *PostgresStorage) DeleteOrder(ctx context.Context, id int) error {
-	query := `DELETE FROM orders WHERE id = $1`
-	result, err := p.db.ExecContext(ctx, query, id)
-	if err != nil {
-		return err
-	}
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected == 0 {
-		return errors.New("order not found")
-	}
-	return nil
+func (pt *PostgresTx) Rollback() error {
+	return pt.tx.Rollback()
 }
 
-func (pt *PostgresTx) BeginTx(ctx context.Context) (StorageTx, error) {
-	return pt, nil
+func (pt *PostgresTx) Close() error {
+	return nil
 }
 
-func (pt *PostgresTx) CreateProduct(ctx context.Context, product *models.Product) error {
+func createProduct(ctx context.Context, q queryer, product *models.Product) error {
 	query := `
-	INSERT INTO products (name, description, price, quantity) 
-	VALUES ($1, $2, $3, $4) 
+	INSERT INTO products (name, description, price, quantity, sku, options, reorder_point, reorder_quantity, tax_class,
+		weight, length, width, height) 
+	VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7, $8, NULLIF($9, ''), $10, $11, $12, $13) 
 	RETURNING id, created_at, updated_at`
 
-	return pt.tx.QueryRowContext(ctx,
+	return q.QueryRowContext(ctx,
 		query,
 		product.Name,
 		product.Description,
 		product.Price,
 		product.Quantity,
+		product.SKU,
+		product.Options,
+		product.ReorderPoint,
+		product.ReorderQuantity,
+		product.TaxClass,
+		product.Weight,
+		product.Length,
+		product.Width,
+		product.Height,
 	).Scan(&product.ID, &product.CreatedAt, &product.UpdatedAt)
 }
 
-func (pt *PostgresTx) GetAllProducts(ctx context.Context) ([]*models.Product, error) {
-	query := `SELECT id, name, description, price, quantity, created_at, updated_at FROM products`
+func getAllProducts(ctx context.Context, q queryer) ([]*models.Product, error) {
+	query := `SELECT ` + productColumns + ` FROM products ORDER BY id`
 	var products []*models.Product
-	err := pt.tx.SelectContext(ctx, &products, query)
+	err := q.SelectContext(ctx, &products, query)
 	return products, err
 }
 
-func (pt *PostgresTx) GetProductByID(ctx context.Context, id int) (*models.Product, error) {
-	query := `SELECT id, name, description, price, quantity, created_at, updated_at FROM products WHERE id = $1`
+func getProductByID(ctx context.Context, q queryer, id int) (*models.Product, error) {
+	query := `SELECT ` + productColumns + ` FROM products WHERE id = $1`
 	var product models.Product
-	err := pt.tx.GetContext(ctx, &product, query, id)
+	err := q.GetContext(ctx, &product, query, id)
 	if err == sql.ErrNoRows {
-		return nil, errors.New("product not found")
+		return nil, notFound("product")
+	}
+	if err != nil {
+		return nil, err
 	}
-	return &product, err
+	return &product, nil
 }
 
-func (pt *PostgresTx) UpdateProduct(ctx context.Context, product *models.Product) error {
+func getProductBySKU(ctx context.Context, q queryer, sku string) (*models.Product, error) {
+	query := `SELECT ` + productColumns + ` FROM products WHERE sku = $1`
+	var product models.Product
+	err := q.GetContext(ctx, &product, query, sku)
+	if err == sql.ErrNoRows {
+		return nil, notFound("product")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+func updateProduct(ctx context.Context, q queryer, product *models.Product) error {
 	query := `
 		UPDATE products 
-		SET name = $1, description = $2, price = $3, quantity = $4, updated_at = CURRENT_TIMESTAMP 
-		WHERE id = $5
+		SET name = $1, description = $2, price = $3, quantity = $4, sku = COALESCE(NULLIF($5, ''), sku), options = $6,
+			reorder_point = $7, reorder_quantity = $8, tax_class = NULLIF($9, ''),
+			weight = $10, length = $11, width = $12, height = $13, updated_at = CURRENT_TIMESTAMP 
+		WHERE id = $14
 	`
-	result, err := pt.tx.ExecContext(ctx, query,
+	result, err := q.ExecContext(ctx, query,
 		product.Name,
 		product.Description,
 		product.Price,
 		product.Quantity,
+		product.SKU,
+		product.Options,
+		product.ReorderPoint,
+		product.ReorderQuantity,
+		product.TaxClass,
+		product.Weight,
+		product.Length,
+		product.Width,
+		product.Height,
 		product.ID,
 	)
 	if err != nil {
@@ -377,84 +336,93 @@ func (pt *PostgresTx) UpdateProduct(ctx context.Context, product *models.Product
 	}
 	rowsAffected, _ := result.RowsAffected()
 	if rowsAffected == 0 {
-		return errors.New("product not found")
+		return notFound("product")
 	}
 	return nil
 }
 
-func (pt *PostgresTx) DeleteProduct(ctx context.Context, id int) error {
+func deleteProduct(ctx context.Context, q queryer, id int) error {
 	query := `DELETE FROM products WHERE id = $1`
-	result, err := pt.tx.ExecContext(ctx, query, id)
+	result, err := q.ExecContext(ctx, query, id)
 	if err != nil {
 		return err
 	}
 	rowsAffected, _ := result.RowsAffected()
 	if rowsAffected == 0 {
-		return errors.New("product not found")
+		return notFound("product")
 	}
 	return nil
 }
 
-func (pt *PostgresTx) CreateOrder(ctx context.Context, order *models.Order) error {
-	total := 0
-	for _, item := range order.Products {
-		var productPrice int
-		err := pt.tx.GetContext(ctx, &productPrice, "SELECT price FROM products WHERE id = $1", item.ProductID)
+func insertOrderItems(ctx context.Context, q queryer, order *models.Order) error {
+	itemQuery := `
+		INSERT INTO order_items (order_id, product_id, variant_id, quantity, price, tax_class, tax, cost) 
+		VALUES ($1, $2, NULLIF($3, 0), $4, $5, NULLIF($6, ''), $7, $8)
+		RETURNING id`
+
+	for i := range order.Products {
+		item := &order.Products[i]
+		item.OrderID = order.ID
+		err := q.QueryRowContext(ctx, itemQuery, order.ID, item.ProductID, item.VariantID, item.Quantity, item.Price,
+			item.TaxClass, item.Tax, item.Cost).Scan(&item.ID)
 		if err != nil {
-			return fmt.Errorf("failed to get product price: %w", err)
+			return fmt.Errorf("failed to create order item: %w", err)
 		}
-		total += productPrice * item.Quantity
-		item.Price = productPrice
 	}
-	order.Total = total
+	return nil
+}
+
+func getOrderItems(ctx context.Context, q queryer, orderID int) ([]models.OrderItem, error) {
+	itemsQuery := `SELECT ` + orderItemColumns + ` FROM order_items WHERE order_id = $1 ORDER BY id`
+
+	var items []models.OrderItem
+	if err := q.SelectContext(ctx, &items, itemsQuery, orderID); err != nil {
+		return nil, fmt.Errorf("failed to get order items: %w", err)
+	}
+	return items, nil
+}
 
+func createOrder(ctx context.Context, q queryer, order *models.Order) error {
 	orderQuery := `
-		INSERT INTO orders (user_id, status, total) 
-		VALUES ($1, $2, $3) 
+		INSERT INTO orders (customer_id, status, total, shipping_address, subtotal, discount, promo_code, free_shipping,
+			tax, tax_inclusive, shipping, shipping_method) 
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8, $9, $10, $11, NULLIF($12, '')) 
 		RETURNING id, created_at, updated_at`
 
-	err := pt.tx.QueryRowContext(ctx,
+	err := q.QueryRowContext(ctx,
 		orderQuery,
-		order.ID,
+		order.CustomerID,
 		order.Status,
 		order.Total,
+		order.ShippingAddress,
+		order.Subtotal,
+		order.Discount,
+		order.PromoCode,
+		order.FreeShipping,
+		order.Tax,
+		order.TaxInclusive,
+		order.Shipping,
+		order.ShippingMethod,
 	).Scan(&order.ID, &order.CreatedAt, &order.UpdatedAt)
 	if err != nil {
-		return err
-	}
-
-	itemQuery := `
-		INSERT INTO order_items (order_id, product_id, quantity, price) 
-		VALUES ($1, $2, $3, $4)`
-
-	for _, item := range order.Products {
-		_, err = pt.tx.ExecContext(ctx, itemQuery, order.ID, item.ProductID, item.Quantity, item.Price)
-		if err != nil {
-			return fmt.Errorf("failed to create order item: %w", err)
-		}
+		return orderError(err)
 	}
 
-	return nil
+	return insertOrderItems(ctx, q, order)
 }
 
-func (pt *PostgresTx) GetAllOrders(ctx context.Context) ([]*models.Order, error) {
-	query := `SELECT id, user_id, status, total, created_at, updated_at FROM orders`
+func getAllOrders(ctx context.Context, q queryer) ([]*models.Order, error) {
+	query := `SELECT ` + orderColumns + ` FROM orders ORDER BY id`
 	var orders []*models.Order
-	err := pt.tx.SelectContext(ctx, &orders, query)
+	err := q.SelectContext(ctx, &orders, query)
 	if err != nil {
 		return nil, err
 	}
 
 	for i := range orders {
-		itemsQuery := `
-			SELECT id, order_id, product_id, quantity, price 
-			FROM order_items 
-			WHERE order_id = $1`
-
-		var items []models.OrderItem
-		err := pt.tx.SelectContext(ctx, &items, itemsQuery, orders[i].ID)
+		items, err := getOrderItems(ctx, q, orders[i].ID)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get order items: %w", err)
+			return nil, err
 		}
 		orders[i].Products = items
 	}
@@ -462,105 +430,89 @@ func (pt *PostgresTx) GetAllOrders(ctx context.Context) ([]*models.Order, error)
 	return orders, nil
 }
 
-func (pt *PostgresTx) GetOrderByID(ctx context.Context, id int) (*models.Order, error) {
-	query := `SELECT id, user_id, status, total, created_at, updated_at FROM orders WHERE id = $1`
+func getOrderByID(ctx context.Context, q queryer, id int) (*models.Order, error) {
+	query := `SELECT ` + orderColumns + ` FROM orders WHERE id = $1`
 	var order models.Order
-	err := pt.tx.GetContext(ctx, &order, query, id)
+	err := q.GetContext(ctx, &order, query, id)
 	if err == sql.ErrNoRows {
-		return nil, errors.New("order not found")
+		return nil, notFound("order")
 	}
 	if err != nil {
 		return nil, err
 	}
 
-	itemsQuery := `
-		SELECT id, order_id, product_id, quantity, price 
-		FROM order_items 
-		WHERE order_id = $1`
-
-	var items []models.OrderItem
-	err = pt.tx.SelectContext(ctx, &items, itemsQuery, id)
+	items, err := getOrderItems(ctx, q, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get order items: %w", err)
+		return nil, err
 	}
 	order.Products = items
 
 	return &order, nil
 }
 
-func (pt *PostgresTx) UpdateOrder(ctx context.Context, order *models.Order) error {
-	total := 0
-	for _, item := range order.Products {
-		var productPrice int
-		err := pt.tx.GetContext(ctx, &productPrice, "SELECT price FROM products WHERE id = $1", item.ProductID)
-		if err != nil {
-			return fmt.Errorf("failed to get product price: %w", err)
-		}
-		total += productPrice * item.Quantity
-		item.Price = productPrice
-	}
-	order.Total = total
-
+func updateOrder(ctx context.Context, q queryer, order *models.Order) error {
 	query := `
 		UPDATE orders 
-		SET user_id = $1, status = $2, total = $3, updated_at = CURRENT_TIMESTAMP 
-		WHERE id = $4`
-
-	result, err := pt.tx.ExecContext(ctx, query, order.ID, order.Status, order.Total)
+		SET customer_id = $1, status = $2, total = $3, shipping_address = $4,
+			subtotal = $5, discount = $6, promo_code = NULLIF($7, ''), free_shipping = $8,
+			tax = $9, tax_inclusive = $10, shipping = $11, shipping_method = NULLIF($12, ''),
+			updated_at = CURRENT_TIMESTAMP 
+		WHERE id = $13`
+
+	result, err := q.ExecContext(ctx, query, order.CustomerID, order.Status, order.Total, order.ShippingAddress,
+		order.Subtotal, order.Discount, order.PromoCode, order.FreeShipping,
+		order.Tax, order.TaxInclusive, order.Shipping, order.ShippingMethod, order.ID)
 	if err != nil {
-		return err
+		return orderError(err)
 	}
 	rowsAffected, _ := result.RowsAffected()
 	if rowsAffected == 0 {
-		return errors.New("order not found")
+		return notFound("order")
 	}
 
-	_, err = pt.tx.ExecContext(ctx, "DELETE FROM order_items WHERE order_id = $1", order.ID)
+	_, err = q.ExecContext(ctx, "DELETE FROM order_items WHERE order_id = $1", order.ID)
 	if err != nil {
 		return fmt.Errorf("failed to delete old order items: %w", err)
 	}
 
-	itemQuery := `
-		INSERT INTO order_items (order_id, product_id, quantity, price) 
-		VALUES ($1, $2, $3, $4)`
-
-	for _, item := range order.Products {
-		_, err = pt.tx.ExecContext(ctx, itemQuery, order.ID, item.ProductID, item.Quantity, item.Price)
-		if err != nil {
-			return fmt.Errorf("failed to create order item: %w", err)
-		}
-	}
-
-	return nil
+	return insertOrderItems(ctx, q, order)
 }
 
-func (pt *PostgresTx) DeleteOrder(ctx context.Context, id int) error {
-	query := `DELETE FROM orders WHERE id = $1`
-	result, err := pt.tx.ExecContext(ctx, query, id)
+func updateOrderStatus(ctx context.Context, q queryer, id int, status string) error {
+	query := `UPDATE orders SET status = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2`
+	result, err := q.ExecContext(ctx, query, status, id)
 	if err != nil {
 		return err
 	}
 	rowsAffected, _ := result.RowsAffected()
 	if rowsAffected == 0 {
-		return errors.New("order not found")
+		return notFound("order")
 	}
 	return nil
 }
 
-func (pt *PostgresTx) Commit() error {
-	return pt.tx.Commit()
-}
-
-func (pt *PostgresTx) Rollback() error {
-	return pt.tx.Rollback()
+// orderError переводит нарушение внешнего ключа на покупателя в понятную ошибку.
+func orderError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23503" && pqErr.Constraint == "orders_customer_id_fkey" {
+		return notFound("customer")
+	}
+	return err
 }
 
-func (pt *PostgresTx) Close() error {
+func deleteOrder(ctx context.Context, q queryer, id int) error {
+	query := `DELETE FROM orders WHERE id = $1`
+	result, err := q.ExecContext(ctx, query, id)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23503" && pqErr.Constraint == "payments_order_id_fkey" {
+		return errors.New("cannot delete order with payments")
+	}
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return notFound("order")
+	}
 	return nil
 }
-
-func (p *PostgresStorage) UpdateProductQuantity(ctx context.Context, id int, quantity int) error {
-	query := `UPDATE products SET quantity = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2`
-	_, err := p.db.ExecContext(ctx, query, quantity, id)
-	return err
-}
@@ -0,0 +1,79 @@
+package storage
+
+import (
+	"backend-store/internal/models"
+	"context"
+	"time"
+)
+
+const auditColumns = `id, actor, actor_verified, action, entity_type, entity_id, changes, COALESCE(request_id, '') AS request_id, COALESCE(ip, '') AS ip, created_at`
+
+func (p *PostgresStorage) CreateAuditEntry(ctx context.Context, entry *models.AuditEntry) error {
+	return createAuditEntry(ctx, p.db, entry)
+}
+
+func (p *PostgresStorage) GetAuditEntries(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error) {
+	return getAuditEntries(ctx, p.db, filter)
+}
+
+func (p *PostgresStorage) DeleteAuditEntriesBefore(ctx context.Context, before time.Time) (int, error) {
+	return deleteAuditEntriesBefore(ctx, p.db, before)
+}
+
+func (pt *PostgresTx) CreateAuditEntry(ctx context.Context, entry *models.AuditEntry) error {
+	return createAuditEntry(ctx, pt.tx, entry)
+}
+
+func (pt *PostgresTx) GetAuditEntries(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error) {
+	return getAuditEntries(ctx, pt.tx, filter)
+}
+
+func (pt *PostgresTx) DeleteAuditEntriesBefore(ctx context.Context, before time.Time) (int, error) {
+	return deleteAuditEntriesBefore(ctx, pt.tx, before)
+}
+
+func createAuditEntry(ctx context.Context, q queryer, entry *models.AuditEntry) error {
+	query := `
+		INSERT INTO audit_log (actor, actor_verified, action, entity_type, entity_id, changes, request_id, ip, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), NULLIF($8, ''), $9)
+		RETURNING id`
+
+	return q.QueryRowContext(ctx, query,
+		entry.Actor,
+		entry.ActorVerified,
+		entry.Action,
+		entry.EntityType,
+		entry.EntityID,
+		entry.Changes,
+		entry.RequestID,
+		entry.IP,
+		entry.CreatedAt,
+	).Scan(&entry.ID)
+}
+
+func getAuditEntries(ctx context.Context, q queryer, filter models.AuditFilter) ([]models.AuditEntry, error) {
+	query := `
+		SELECT ` + auditColumns + ` FROM audit_log
+		WHERE ($1 = '' OR entity_type = $1) AND ($2 = 0 OR entity_id = $2)
+		ORDER BY id DESC`
+	args := []interface{}{filter.EntityType, filter.EntityID}
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += ` LIMIT $3`
+	}
+
+	entries := []models.AuditEntry{}
+	if err := q.SelectContext(ctx, &entries, query, args...); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func deleteAuditEntriesBefore(ctx context.Context, q queryer, before time.Time) (int, error) {
+	result, err := q.ExecContext(ctx, `DELETE FROM audit_log WHERE created_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	deleted, _ := result.RowsAffected()
+	return int(deleted), nil
+}
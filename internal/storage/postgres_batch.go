@@ -0,0 +1,193 @@
+package storage
+
+import (
+	"backend-store/internal/models"
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func (p *PostgresStorage) GetProductsByIDs(ctx context.Context, ids []int) ([]*models.Product, error) {
+	return getProductsByIDs(ctx, p.db, ids)
+}
+
+func (p *PostgresStorage) GetOrdersByIDs(ctx context.Context, ids []int) ([]*models.Order, error) {
+	return getOrdersByIDs(ctx, p.db, ids)
+}
+
+func (p *PostgresStorage) GetCustomersByIDs(ctx context.Context, ids []int) ([]*models.Customer, error) {
+	return getCustomersByIDs(ctx, p.db, ids)
+}
+
+func (p *PostgresStorage) GetCategoriesByIDs(ctx context.Context, ids []int) ([]*models.Category, error) {
+	return getCategoriesByIDs(ctx, p.db, ids)
+}
+
+func (p *PostgresStorage) GetCategoryIDsByProducts(ctx context.Context, productIDs []int) (map[int][]int, error) {
+	return getIDsByKeys(ctx, p.db, `SELECT product_id AS key, category_id AS id FROM product_categories WHERE product_id IN (?) ORDER BY category_id`, productIDs)
+}
+
+func (p *PostgresStorage) GetTagsByProducts(ctx context.Context, productIDs []int) (map[int][]string, error) {
+	return getTagsByProducts(ctx, p.db, productIDs)
+}
+
+func (p *PostgresStorage) GetStockLevelsByProducts(ctx context.Context, productIDs []int) ([]models.StockLevel, error) {
+	return getStockLevelsByProducts(ctx, p.db, productIDs)
+}
+
+func (p *PostgresStorage) GetOrderIDsByProducts(ctx context.Context, productIDs []int) (map[int][]int, error) {
+	return getIDsByKeys(ctx, p.db, `SELECT DISTINCT product_id AS key, order_id AS id FROM order_items WHERE product_id IN (?) ORDER BY order_id`, productIDs)
+}
+
+func (p *PostgresStorage) GetOrderIDsByCustomers(ctx context.Context, customerIDs []int) (map[int][]int, error) {
+	return getIDsByKeys(ctx, p.db, `SELECT customer_id AS key, id FROM orders WHERE customer_id IN (?) ORDER BY id`, customerIDs)
+}
+
+func (pt *PostgresTx) GetProductsByIDs(ctx context.Context, ids []int) ([]*models.Product, error) {
+	return getProductsByIDs(ctx, pt.tx, ids)
+}
+
+func (pt *PostgresTx) GetOrdersByIDs(ctx context.Context, ids []int) ([]*models.Order, error) {
+	return getOrdersByIDs(ctx, pt.tx, ids)
+}
+
+func (pt *PostgresTx) GetCustomersByIDs(ctx context.Context, ids []int) ([]*models.Customer, error) {
+	return getCustomersByIDs(ctx, pt.tx, ids)
+}
+
+func (pt *PostgresTx) GetCategoriesByIDs(ctx context.Context, ids []int) ([]*models.Category, error) {
+	return getCategoriesByIDs(ctx, pt.tx, ids)
+}
+
+func (pt *PostgresTx) GetCategoryIDsByProducts(ctx context.Context, productIDs []int) (map[int][]int, error) {
+	return getIDsByKeys(ctx, pt.tx, `SELECT product_id AS key, category_id AS id FROM product_categories WHERE product_id IN (?) ORDER BY category_id`, productIDs)
+}
+
+func (pt *PostgresTx) GetTagsByProducts(ctx context.Context, productIDs []int) (map[int][]string, error) {
+	return getTagsByProducts(ctx, pt.tx, productIDs)
+}
+
+func (pt *PostgresTx) GetStockLevelsByProducts(ctx context.Context, productIDs []int) ([]models.StockLevel, error) {
+	return getStockLevelsByProducts(ctx, pt.tx, productIDs)
+}
+
+func (pt *PostgresTx) GetOrderIDsByProducts(ctx context.Context, productIDs []int) (map[int][]int, error) {
+	return getIDsByKeys(ctx, pt.tx, `SELECT DISTINCT product_id AS key, order_id AS id FROM order_items WHERE product_id IN (?) ORDER BY order_id`, productIDs)
+}
+
+func (pt *PostgresTx) GetOrderIDsByCustomers(ctx context.Context, customerIDs []int) (map[int][]int, error) {
+	return getIDsByKeys(ctx, pt.tx, `SELECT customer_id AS key, id FROM orders WHERE customer_id IN (?) ORDER BY id`, customerIDs)
+}
+
+// selectIn выполняет запрос с "IN (?)" по списку ids; пустой список не
+// обращается к базе.
+func selectIn(ctx context.Context, q queryer, dest interface{}, query string, ids []int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	query, args, err := sqlx.In(query, ids)
+	if err != nil {
+		return err
+	}
+	return q.SelectContext(ctx, dest, q.Rebind(query), args...)
+}
+
+func getProductsByIDs(ctx context.Context, q queryer, ids []int) ([]*models.Product, error) {
+	products := []*models.Product{}
+	err := selectIn(ctx, q, &products, `SELECT `+productColumns+` FROM products WHERE id IN (?) ORDER BY id`, ids)
+	return products, err
+}
+
+func getOrdersByIDs(ctx context.Context, q queryer, ids []int) ([]*models.Order, error) {
+	orders := []*models.Order{}
+	if err := selectIn(ctx, q, &orders, `SELECT `+orderColumns+` FROM orders WHERE id IN (?) ORDER BY id`, ids); err != nil {
+		return nil, err
+	}
+
+	var items []models.OrderItem
+	if err := selectIn(ctx, q, &items, `SELECT `+orderItemColumns+` FROM order_items WHERE order_id IN (?) ORDER BY id`, ids); err != nil {
+		return nil, fmt.Errorf("failed to get order items: %w", err)
+	}
+	byOrder := make(map[int][]models.OrderItem)
+	for _, item := range items {
+		byOrder[item.OrderID] = append(byOrder[item.OrderID], item)
+	}
+	for _, o := range orders {
+		o.Products = byOrder[o.ID]
+	}
+	return orders, nil
+}
+
+func getCustomersByIDs(ctx context.Context, q queryer, ids []int) ([]*models.Customer, error) {
+	customers := []*models.Customer{}
+	if err := selectIn(ctx, q, &customers, `SELECT `+customerColumns+` FROM customers WHERE id IN (?) ORDER BY id`, ids); err != nil {
+		return nil, err
+	}
+
+	var addresses []models.CustomerAddress
+	addressQuery := `SELECT ` + customerAddressColumns + ` FROM customer_addresses WHERE customer_id IN (?) ORDER BY customer_id, id`
+	if err := selectIn(ctx, q, &addresses, addressQuery, ids); err != nil {
+		return nil, fmt.Errorf("failed to get customer addresses: %w", err)
+	}
+	byCustomer := make(map[int][]models.CustomerAddress)
+	for _, a := range addresses {
+		byCustomer[a.CustomerID] = append(byCustomer[a.CustomerID], a)
+	}
+	for _, c := range customers {
+		c.Addresses = byCustomer[c.ID]
+		if c.Addresses == nil {
+			c.Addresses = []models.CustomerAddress{}
+		}
+	}
+	return customers, nil
+}
+
+func getCategoriesByIDs(ctx context.Context, q queryer, ids []int) ([]*models.Category, error) {
+	categories := []*models.Category{}
+	err := selectIn(ctx, q, &categories, `SELECT `+categoryColumns+` FROM categories WHERE id IN (?) ORDER BY id`, ids)
+	return categories, err
+}
+
+func getTagsByProducts(ctx context.Context, q queryer, productIDs []int) (map[int][]string, error) {
+	var rows []struct {
+		ProductID int    `db:"product_id"`
+		Tag       string `db:"tag"`
+	}
+	if err := selectIn(ctx, q, &rows, `SELECT product_id, tag FROM product_tags WHERE product_id IN (?) ORDER BY tag`, productIDs); err != nil {
+		return nil, err
+	}
+	result := make(map[int][]string)
+	for _, r := range rows {
+		result[r.ProductID] = append(result[r.ProductID], r.Tag)
+	}
+	return result, nil
+}
+
+func getStockLevelsByProducts(ctx context.Context, q queryer, productIDs []int) ([]models.StockLevel, error) {
+	levels := []models.StockLevel{}
+	query := `
+		SELECT ` + stockLevelColumns + `
+		FROM warehouse_stock s
+		JOIN product_variants v ON v.id = s.variant_id
+		WHERE v.product_id IN (?)
+		ORDER BY s.warehouse_id, s.variant_id`
+	err := selectIn(ctx, q, &levels, query, productIDs)
+	return levels, err
+}
+
+// getIDsByKeys группирует пары (key, id), выбранные query, по key.
+func getIDsByKeys(ctx context.Context, q queryer, query string, keys []int) (map[int][]int, error) {
+	var rows []struct {
+		Key int `db:"key"`
+		ID  int `db:"id"`
+	}
+	if err := selectIn(ctx, q, &rows, query, keys); err != nil {
+		return nil, err
+	}
+	result := make(map[int][]int)
+	for _, r := range rows {
+		result[r.Key] = append(result[r.Key], r.ID)
+	}
+	return result, nil
+}
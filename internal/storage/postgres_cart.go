@@ -0,0 +1,230 @@
+package storage
+
+import (
+	"backend-store/internal/models"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const cartColumns = `id, token, COALESCE(user_id, 0) AS user_id, status, COALESCE(order_id, 0) AS order_id, expires_at, created_at, updated_at`
+
+func (p *PostgresStorage) CreateCart(ctx context.Context, cart *models.Cart) error {
+	return createCart(ctx, p.db, cart)
+}
+
+func (p *PostgresStorage) GetCartByID(ctx context.Context, id int) (*models.Cart, error) {
+	return getCart(ctx, p.db, `id = $1`, id)
+}
+
+func (p *PostgresStorage) GetActiveCartByUser(ctx context.Context, userID int) (*models.Cart, error) {
+	return getCart(ctx, p.db, `user_id = $1 AND status = 'active'`, userID)
+}
+
+func (p *PostgresStorage) UpdateCart(ctx context.Context, cart *models.Cart) error {
+	return updateCart(ctx, p.db, cart)
+}
+
+func (p *PostgresStorage) DeleteCart(ctx context.Context, id int) error {
+	return deleteCart(ctx, p.db, id)
+}
+
+func (p *PostgresStorage) AddCartItem(ctx context.Context, item *models.CartItem) error {
+	return addCartItem(ctx, p.db, item)
+}
+
+func (p *PostgresStorage) UpdateCartItem(ctx context.Context, item *models.CartItem) error {
+	return updateCartItem(ctx, p.db, item)
+}
+
+func (p *PostgresStorage) DeleteCartItem(ctx context.Context, id int) error {
+	return deleteCartItem(ctx, p.db, id)
+}
+
+func (p *PostgresStorage) DeleteExpiredCarts(ctx context.Context, before time.Time) (int, error) {
+	return deleteExpiredCarts(ctx, p.db, before)
+}
+
+func (pt *PostgresTx) CreateCart(ctx context.Context, cart *models.Cart) error {
+	return createCart(ctx, pt.tx, cart)
+}
+
+func (pt *PostgresTx) GetCartByID(ctx context.Context, id int) (*models.Cart, error) {
+	return getCart(ctx, pt.tx, `id = $1`, id)
+}
+
+func (pt *PostgresTx) GetActiveCartByUser(ctx context.Context, userID int) (*models.Cart, error) {
+	return getCart(ctx, pt.tx, `user_id = $1 AND status = 'active'`, userID)
+}
+
+func (pt *PostgresTx) UpdateCart(ctx context.Context, cart *models.Cart) error {
+	return updateCart(ctx, pt.tx, cart)
+}
+
+func (pt *PostgresTx) DeleteCart(ctx context.Context, id int) error {
+	return deleteCart(ctx, pt.tx, id)
+}
+
+func (pt *PostgresTx) AddCartItem(ctx context.Context, item *models.CartItem) error {
+	return addCartItem(ctx, pt.tx, item)
+}
+
+func (pt *PostgresTx) UpdateCartItem(ctx context.Context, item *models.CartItem) error {
+	return updateCartItem(ctx, pt.tx, item)
+}
+
+func (pt *PostgresTx) DeleteCartItem(ctx context.Context, id int) error {
+	return deleteCartItem(ctx, pt.tx, id)
+}
+
+func (pt *PostgresTx) DeleteExpiredCarts(ctx context.Context, before time.Time) (int, error) {
+	return deleteExpiredCarts(ctx, pt.tx, before)
+}
+
+func createCart(ctx context.Context, q queryer, cart *models.Cart) error {
+	query := `
+		INSERT INTO carts (token, user_id, status, expires_at, created_at, updated_at)
+		VALUES ($1, NULLIF($2, 0), $3, $4, $5, $6)
+		RETURNING id`
+
+	err := q.QueryRowContext(ctx, query,
+		cart.Token,
+		cart.UserID,
+		cart.Status,
+		cart.ExpiresAt,
+		cart.CreatedAt,
+		cart.UpdatedAt,
+	).Scan(&cart.ID)
+	return cartError(err, cart)
+}
+
+// cartError переводит нарушения уникальных индексов корзин в ошибки,
+// совпадающие с in-memory хранилищем.
+func cartError(err error, cart *models.Cart) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		if pqErr.Constraint == "idx_carts_active_user" {
+			return fmt.Errorf("active cart for user %d already exists", cart.UserID)
+		}
+		return errors.New("cart token already exists")
+	}
+	return err
+}
+
+func getCart(ctx context.Context, q queryer, condition string, arg interface{}) (*models.Cart, error) {
+	var cart models.Cart
+	err := q.GetContext(ctx, &cart, `SELECT `+cartColumns+` FROM carts WHERE `+condition, arg)
+	if err == sql.ErrNoRows {
+		return nil, notFound("cart")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cart.Items = []models.CartItem{}
+	query := `
+		SELECT id, cart_id, product_id, COALESCE(variant_id, 0) AS variant_id, quantity, created_at
+		FROM cart_items
+		WHERE cart_id = $1
+		ORDER BY id`
+	if err := q.SelectContext(ctx, &cart.Items, query, cart.ID); err != nil {
+		return nil, err
+	}
+	return &cart, nil
+}
+
+func updateCart(ctx context.Context, q queryer, cart *models.Cart) error {
+	query := `
+		UPDATE carts
+		SET user_id = NULLIF($1, 0), status = $2, order_id = NULLIF($3, 0), expires_at = $4, updated_at = $5
+		WHERE id = $6`
+	result, err := q.ExecContext(ctx, query,
+		cart.UserID,
+		cart.Status,
+		cart.OrderID,
+		cart.ExpiresAt,
+		cart.UpdatedAt,
+		cart.ID,
+	)
+	if err != nil {
+		return cartError(err, cart)
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return notFound("cart")
+	}
+	return nil
+}
+
+func deleteCart(ctx context.Context, q queryer, id int) error {
+	result, err := q.ExecContext(ctx, `DELETE FROM carts WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return notFound("cart")
+	}
+	return nil
+}
+
+func addCartItem(ctx context.Context, q queryer, item *models.CartItem) error {
+	query := `
+		INSERT INTO cart_items (cart_id, product_id, variant_id, quantity)
+		VALUES ($1, $2, NULLIF($3, 0), $4)
+		RETURNING id, created_at`
+
+	err := q.QueryRowContext(ctx, query,
+		item.CartID,
+		item.ProductID,
+		item.VariantID,
+		item.Quantity,
+	).Scan(&item.ID, &item.CreatedAt)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case "23505":
+			return errors.New("cart item already exists")
+		case "23503":
+			return notFound("cart, product or variant")
+		}
+	}
+	return err
+}
+
+func updateCartItem(ctx context.Context, q queryer, item *models.CartItem) error {
+	result, err := q.ExecContext(ctx, `UPDATE cart_items SET quantity = $1 WHERE id = $2`, item.Quantity, item.ID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return notFound("cart item")
+	}
+	return nil
+}
+
+func deleteCartItem(ctx context.Context, q queryer, id int) error {
+	result, err := q.ExecContext(ctx, `DELETE FROM cart_items WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return notFound("cart item")
+	}
+	return nil
+}
+
+func deleteExpiredCarts(ctx context.Context, q queryer, before time.Time) (int, error) {
+	result, err := q.ExecContext(ctx, `DELETE FROM carts WHERE status = 'active' AND expires_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	deleted, _ := result.RowsAffected()
+	return int(deleted), nil
+}
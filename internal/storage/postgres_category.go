@@ -0,0 +1,275 @@
+package storage
+
+import (
+	"backend-store/internal/models"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const categoryColumns = `id, parent_id, name, slug, position, created_at, updated_at`
+
+func (p *PostgresStorage) CreateCategory(ctx context.Context, category *models.Category) error {
+	return createCategory(ctx, p.db, category)
+}
+
+func (p *PostgresStorage) GetCategoryByID(ctx context.Context, id int) (*models.Category, error) {
+	return getCategoryByID(ctx, p.db, id)
+}
+
+func (p *PostgresStorage) GetAllCategories(ctx context.Context) ([]*models.Category, error) {
+	return getAllCategories(ctx, p.db)
+}
+
+func (p *PostgresStorage) UpdateCategory(ctx context.Context, category *models.Category) error {
+	return updateCategory(ctx, p.db, category)
+}
+
+func (p *PostgresStorage) DeleteCategory(ctx context.Context, id int) error {
+	return deleteCategory(ctx, p.db, id)
+}
+
+func (p *PostgresStorage) GetProductCategoryIDs(ctx context.Context, productID int) ([]int, error) {
+	return getProductCategoryIDs(ctx, p.db, productID)
+}
+
+func (p *PostgresStorage) SetProductCategories(ctx context.Context, productID int, categoryIDs []int) error {
+	return setProductCategories(ctx, p.db, productID, categoryIDs)
+}
+
+func (p *PostgresStorage) GetProductIDsByCategories(ctx context.Context, categoryIDs []int) ([]int, error) {
+	return getProductIDsByCategories(ctx, p.db, categoryIDs)
+}
+
+func (p *PostgresStorage) ReassignCategoryProducts(ctx context.Context, fromID, toID int) error {
+	return reassignCategoryProducts(ctx, p.db, fromID, toID)
+}
+
+func (p *PostgresStorage) GetProductTags(ctx context.Context, productID int) ([]string, error) {
+	return getProductTags(ctx, p.db, productID)
+}
+
+func (p *PostgresStorage) SetProductTags(ctx context.Context, productID int, tags []string) error {
+	return setProductTags(ctx, p.db, productID, tags)
+}
+
+func (p *PostgresStorage) GetProductIDsByTag(ctx context.Context, tag string) ([]int, error) {
+	return getProductIDsByTag(ctx, p.db, tag)
+}
+
+func (p *PostgresStorage) GetAllTags(ctx context.Context) ([]models.TagCount, error) {
+	return getAllTags(ctx, p.db)
+}
+
+func (pt *PostgresTx) CreateCategory(ctx context.Context, category *models.Category) error {
+	return createCategory(ctx, pt.tx, category)
+}
+
+func (pt *PostgresTx) GetCategoryByID(ctx context.Context, id int) (*models.Category, error) {
+	return getCategoryByID(ctx, pt.tx, id)
+}
+
+func (pt *PostgresTx) GetAllCategories(ctx context.Context) ([]*models.Category, error) {
+	return getAllCategories(ctx, pt.tx)
+}
+
+func (pt *PostgresTx) UpdateCategory(ctx context.Context, category *models.Category) error {
+	return updateCategory(ctx, pt.tx, category)
+}
+
+func (pt *PostgresTx) DeleteCategory(ctx context.Context, id int) error {
+	return deleteCategory(ctx, pt.tx, id)
+}
+
+func (pt *PostgresTx) GetProductCategoryIDs(ctx context.Context, productID int) ([]int, error) {
+	return getProductCategoryIDs(ctx, pt.tx, productID)
+}
+
+func (pt *PostgresTx) SetProductCategories(ctx context.Context, productID int, categoryIDs []int) error {
+	return setProductCategories(ctx, pt.tx, productID, categoryIDs)
+}
+
+func (pt *PostgresTx) GetProductIDsByCategories(ctx context.Context, categoryIDs []int) ([]int, error) {
+	return getProductIDsByCategories(ctx, pt.tx, categoryIDs)
+}
+
+func (pt *PostgresTx) ReassignCategoryProducts(ctx context.Context, fromID, toID int) error {
+	return reassignCategoryProducts(ctx, pt.tx, fromID, toID)
+}
+
+func (pt *PostgresTx) GetProductTags(ctx context.Context, productID int) ([]string, error) {
+	return getProductTags(ctx, pt.tx, productID)
+}
+
+func (pt *PostgresTx) SetProductTags(ctx context.Context, productID int, tags []string) error {
+	return setProductTags(ctx, pt.tx, productID, tags)
+}
+
+func (pt *PostgresTx) GetProductIDsByTag(ctx context.Context, tag string) ([]int, error) {
+	return getProductIDsByTag(ctx, pt.tx, tag)
+}
+
+func (pt *PostgresTx) GetAllTags(ctx context.Context) ([]models.TagCount, error) {
+	return getAllTags(ctx, pt.tx)
+}
+
+func createCategory(ctx context.Context, q queryer, category *models.Category) error {
+	query := `
+		INSERT INTO categories (parent_id, name, slug, position)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at, updated_at`
+
+	err := q.QueryRowContext(ctx, query,
+		category.ParentID,
+		category.Name,
+		category.Slug,
+		category.Position,
+	).Scan(&category.ID, &category.CreatedAt, &category.UpdatedAt)
+	return categoryError(err, category.Slug)
+}
+
+func getCategoryByID(ctx context.Context, q queryer, id int) (*models.Category, error) {
+	query := `SELECT ` + categoryColumns + ` FROM categories WHERE id = $1`
+	var category models.Category
+	err := q.GetContext(ctx, &category, query, id)
+	if err == sql.ErrNoRows {
+		return nil, notFound("category")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+func getAllCategories(ctx context.Context, q queryer) ([]*models.Category, error) {
+	query := `SELECT ` + categoryColumns + ` FROM categories ORDER BY parent_id NULLS FIRST, position, id`
+	var categories []*models.Category
+	err := q.SelectContext(ctx, &categories, query)
+	return categories, err
+}
+
+func updateCategory(ctx context.Context, q queryer, category *models.Category) error {
+	query := `
+		UPDATE categories
+		SET parent_id = $1, name = $2, slug = $3, position = $4, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $5`
+
+	result, err := q.ExecContext(ctx, query,
+		category.ParentID,
+		category.Name,
+		category.Slug,
+		category.Position,
+		category.ID,
+	)
+	if err != nil {
+		return categoryError(err, category.Slug)
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return notFound("category")
+	}
+	return nil
+}
+
+func deleteCategory(ctx context.Context, q queryer, id int) error {
+	result, err := q.ExecContext(ctx, `DELETE FROM categories WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return notFound("category")
+	}
+	return nil
+}
+
+// categoryError переводит нарушение уникального индекса slug в ошибку,
+// которую обработчики отдают как 409.
+func categoryError(err error, slug string) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return fmt.Errorf("category with slug %q already exists", slug)
+	}
+	return err
+}
+
+func getProductCategoryIDs(ctx context.Context, q queryer, productID int) ([]int, error) {
+	ids := []int{}
+	err := q.SelectContext(ctx, &ids,
+		`SELECT category_id FROM product_categories WHERE product_id = $1 ORDER BY category_id`, productID)
+	return ids, err
+}
+
+func setProductCategories(ctx context.Context, q queryer, productID int, categoryIDs []int) error {
+	if _, err := q.ExecContext(ctx, `DELETE FROM product_categories WHERE product_id = $1`, productID); err != nil {
+		return err
+	}
+	for _, categoryID := range categoryIDs {
+		_, err := q.ExecContext(ctx,
+			`INSERT INTO product_categories (product_id, category_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+			productID, categoryID)
+		if err != nil {
+			return fmt.Errorf("failed to link category %d: %w", categoryID, err)
+		}
+	}
+	return nil
+}
+
+func getProductIDsByCategories(ctx context.Context, q queryer, categoryIDs []int) ([]int, error) {
+	ids := []int{}
+	if len(categoryIDs) == 0 {
+		return ids, nil
+	}
+	query, args, err := sqlx.In(
+		`SELECT DISTINCT product_id FROM product_categories WHERE category_id IN (?) ORDER BY product_id`, categoryIDs)
+	if err != nil {
+		return nil, err
+	}
+	err = q.SelectContext(ctx, &ids, q.Rebind(query), args...)
+	return ids, err
+}
+
+func reassignCategoryProducts(ctx context.Context, q queryer, fromID, toID int) error {
+	_, err := q.ExecContext(ctx, `
+		INSERT INTO product_categories (product_id, category_id)
+		SELECT product_id, $2 FROM product_categories WHERE category_id = $1
+		ON CONFLICT DO NOTHING`, fromID, toID)
+	return err
+}
+
+func getProductTags(ctx context.Context, q queryer, productID int) ([]string, error) {
+	tags := []string{}
+	err := q.SelectContext(ctx, &tags, `SELECT tag FROM product_tags WHERE product_id = $1 ORDER BY tag`, productID)
+	return tags, err
+}
+
+func setProductTags(ctx context.Context, q queryer, productID int, tags []string) error {
+	if _, err := q.ExecContext(ctx, `DELETE FROM product_tags WHERE product_id = $1`, productID); err != nil {
+		return err
+	}
+	for _, tag := range tags {
+		_, err := q.ExecContext(ctx,
+			`INSERT INTO product_tags (product_id, tag) VALUES ($1, $2) ON CONFLICT DO NOTHING`, productID, tag)
+		if err != nil {
+			return fmt.Errorf("failed to add tag %q: %w", tag, err)
+		}
+	}
+	return nil
+}
+
+func getProductIDsByTag(ctx context.Context, q queryer, tag string) ([]int, error) {
+	ids := []int{}
+	err := q.SelectContext(ctx, &ids, `SELECT product_id FROM product_tags WHERE tag = $1 ORDER BY product_id`, tag)
+	return ids, err
+}
+
+func getAllTags(ctx context.Context, q queryer) ([]models.TagCount, error) {
+	tags := []models.TagCount{}
+	err := q.SelectContext(ctx, &tags,
+		`SELECT tag, COUNT(*) AS products FROM product_tags GROUP BY tag ORDER BY tag`)
+	return tags, err
+}
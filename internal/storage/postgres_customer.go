@@ -0,0 +1,196 @@
+package storage
+
+import (
+	"backend-store/internal/models"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+const customerColumns = `id, email, name, COALESCE(phone, '') AS phone, created_at, updated_at`
+
+const customerAddressColumns = `id, customer_id, kind, is_default, address, created_at`
+
+func (p *PostgresStorage) CreateCustomer(ctx context.Context, customer *models.Customer) error {
+	return createCustomer(ctx, p.db, customer)
+}
+
+func (p *PostgresStorage) GetCustomerByID(ctx context.Context, id int) (*models.Customer, error) {
+	return getCustomer(ctx, p.db, "id = $1", id)
+}
+
+func (p *PostgresStorage) GetCustomerByEmail(ctx context.Context, email string) (*models.Customer, error) {
+	return getCustomer(ctx, p.db, "email = $1", email)
+}
+
+func (p *PostgresStorage) GetAllCustomers(ctx context.Context) ([]*models.Customer, error) {
+	return getAllCustomers(ctx, p.db)
+}
+
+func (p *PostgresStorage) UpdateCustomer(ctx context.Context, customer *models.Customer) error {
+	return updateCustomer(ctx, p.db, customer)
+}
+
+func (p *PostgresStorage) DeleteCustomer(ctx context.Context, id int) error {
+	return deleteCustomer(ctx, p.db, id)
+}
+
+func (pt *PostgresTx) CreateCustomer(ctx context.Context, customer *models.Customer) error {
+	return createCustomer(ctx, pt.tx, customer)
+}
+
+func (pt *PostgresTx) GetCustomerByID(ctx context.Context, id int) (*models.Customer, error) {
+	return getCustomer(ctx, pt.tx, "id = $1", id)
+}
+
+func (pt *PostgresTx) GetCustomerByEmail(ctx context.Context, email string) (*models.Customer, error) {
+	return getCustomer(ctx, pt.tx, "email = $1", email)
+}
+
+func (pt *PostgresTx) GetAllCustomers(ctx context.Context) ([]*models.Customer, error) {
+	return getAllCustomers(ctx, pt.tx)
+}
+
+func (pt *PostgresTx) UpdateCustomer(ctx context.Context, customer *models.Customer) error {
+	return updateCustomer(ctx, pt.tx, customer)
+}
+
+func (pt *PostgresTx) DeleteCustomer(ctx context.Context, id int) error {
+	return deleteCustomer(ctx, pt.tx, id)
+}
+
+func createCustomer(ctx context.Context, q queryer, customer *models.Customer) error {
+	query := `
+		INSERT INTO customers (email, name, phone, created_at, updated_at)
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5)
+		RETURNING id`
+
+	err := q.QueryRowContext(ctx, query,
+		customer.Email,
+		customer.Name,
+		customer.Phone,
+		customer.CreatedAt,
+		customer.UpdatedAt,
+	).Scan(&customer.ID)
+	if err != nil {
+		return customerError(err, customer.Email)
+	}
+	return insertCustomerAddresses(ctx, q, customer)
+}
+
+func insertCustomerAddresses(ctx context.Context, q queryer, customer *models.Customer) error {
+	query := `
+		INSERT INTO customer_addresses (customer_id, kind, is_default, address)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at`
+
+	for i := range customer.Addresses {
+		a := &customer.Addresses[i]
+		a.CustomerID = customer.ID
+		err := q.QueryRowContext(ctx, query, customer.ID, a.Kind, a.IsDefault, a.Address).Scan(&a.ID, &a.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to create customer address: %w", err)
+		}
+	}
+	return nil
+}
+
+func getCustomer(ctx context.Context, q queryer, condition string, arg interface{}) (*models.Customer, error) {
+	query := `SELECT ` + customerColumns + ` FROM customers WHERE ` + condition
+	var customer models.Customer
+	err := q.GetContext(ctx, &customer, query, arg)
+	if err == sql.ErrNoRows {
+		return nil, notFound("customer")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	addressQuery := `SELECT ` + customerAddressColumns + ` FROM customer_addresses WHERE customer_id = $1 ORDER BY id`
+	customer.Addresses = []models.CustomerAddress{}
+	if err := q.SelectContext(ctx, &customer.Addresses, addressQuery, customer.ID); err != nil {
+		return nil, fmt.Errorf("failed to get customer addresses: %w", err)
+	}
+	return &customer, nil
+}
+
+func getAllCustomers(ctx context.Context, q queryer) ([]*models.Customer, error) {
+	var customers []*models.Customer
+	if err := q.SelectContext(ctx, &customers, `SELECT `+customerColumns+` FROM customers ORDER BY id`); err != nil {
+		return nil, err
+	}
+
+	var addresses []models.CustomerAddress
+	addressQuery := `SELECT ` + customerAddressColumns + ` FROM customer_addresses ORDER BY customer_id, id`
+	if err := q.SelectContext(ctx, &addresses, addressQuery); err != nil {
+		return nil, fmt.Errorf("failed to get customer addresses: %w", err)
+	}
+	byCustomer := make(map[int][]models.CustomerAddress)
+	for _, a := range addresses {
+		byCustomer[a.CustomerID] = append(byCustomer[a.CustomerID], a)
+	}
+	for _, c := range customers {
+		c.Addresses = byCustomer[c.ID]
+		if c.Addresses == nil {
+			c.Addresses = []models.CustomerAddress{}
+		}
+	}
+	return customers, nil
+}
+
+func updateCustomer(ctx context.Context, q queryer, customer *models.Customer) error {
+	query := `
+		UPDATE customers
+		SET email = $1, name = $2, phone = NULLIF($3, ''), updated_at = $4
+		WHERE id = $5`
+
+	result, err := q.ExecContext(ctx, query,
+		customer.Email,
+		customer.Name,
+		customer.Phone,
+		customer.UpdatedAt,
+		customer.ID,
+	)
+	if err != nil {
+		return customerError(err, customer.Email)
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return notFound("customer")
+	}
+
+	if _, err := q.ExecContext(ctx, `DELETE FROM customer_addresses WHERE customer_id = $1`, customer.ID); err != nil {
+		return fmt.Errorf("failed to delete old customer addresses: %w", err)
+	}
+	return insertCustomerAddresses(ctx, q, customer)
+}
+
+func deleteCustomer(ctx context.Context, q queryer, id int) error {
+	result, err := q.ExecContext(ctx, `DELETE FROM customers WHERE id = $1`, id)
+	if err != nil {
+		return customerError(err, "")
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return notFound("customer")
+	}
+	return nil
+}
+
+// customerError переводит нарушения ограничений в понятные ошибки:
+// уникальность email и ссылки из заказов.
+func customerError(err error, email string) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case "23505":
+			return fmt.Errorf("customer with email %q already exists", email)
+		case "23503":
+			return errors.New("cannot delete customer with orders")
+		}
+	}
+	return err
+}
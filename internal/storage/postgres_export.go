@@ -0,0 +1,152 @@
+package storage
+
+import (
+	"backend-store/internal/models"
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+func (p *PostgresStorage) IterateProducts(ctx context.Context, fn func(*models.Product) error) error {
+	return iterateProducts(ctx, p.db, fn)
+}
+
+func (p *PostgresStorage) IterateOrders(ctx context.Context, filter models.OrderFilter, fn func(*models.Order) error) error {
+	return iterateOrders(ctx, p.db, filter, fn)
+}
+
+func (pt *PostgresTx) IterateProducts(ctx context.Context, fn func(*models.Product) error) error {
+	return iterateProducts(ctx, pt.tx, fn)
+}
+
+func (pt *PostgresTx) IterateOrders(ctx context.Context, filter models.OrderFilter, fn func(*models.Order) error) error {
+	return iterateOrders(ctx, pt.tx, filter, fn)
+}
+
+func iterateProducts(ctx context.Context, q queryer, fn func(*models.Product) error) error {
+	rows, err := q.QueryxContext(ctx, `SELECT `+productColumns+` FROM products ORDER BY id`)
+	if err != nil {
+		return fmt.Errorf("failed to query products: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var product models.Product
+		if err := rows.StructScan(&product); err != nil {
+			return fmt.Errorf("failed to scan product: %w", err)
+		}
+		if err := fn(&product); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// orderItemRow - строка LEFT JOIN orders/order_items. Поля позиции пустые
+// у заказов без позиций.
+type orderItemRow struct {
+	OrderID         int             `db:"order_id"`
+	CustomerID      int             `db:"customer_id"`
+	Status          string          `db:"status"`
+	Total           int             `db:"total"`
+	CreatedAt       time.Time       `db:"created_at"`
+	UpdatedAt       time.Time       `db:"updated_at"`
+	ShippingAddress *models.Address `db:"shipping_address"`
+	ItemID          sql.NullInt64   `db:"item_id"`
+	ProductID       sql.NullInt64   `db:"product_id"`
+	VariantID       sql.NullInt64   `db:"variant_id"`
+	Quantity        sql.NullInt64   `db:"quantity"`
+	Price           sql.NullInt64   `db:"price"`
+}
+
+// iterateOrders читает заказы одним запросом, отсортированным по id заказа,
+// и собирает позиции соседних строк в один models.Order.
+func iterateOrders(ctx context.Context, q queryer, filter models.OrderFilter, fn func(*models.Order) error) error {
+	where, args := orderFilterClause(filter)
+	query := `
+		SELECT o.id AS order_id, o.customer_id, o.status, o.total, o.created_at, o.updated_at, o.shipping_address,
+			oi.id AS item_id, oi.product_id, oi.variant_id, oi.quantity, oi.price
+		FROM orders o
+		LEFT JOIN order_items oi ON oi.order_id = o.id` + where + `
+		ORDER BY o.id, oi.id`
+
+	rows, err := q.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query orders: %w", err)
+	}
+	defer rows.Close()
+
+	var current *models.Order
+	for rows.Next() {
+		var row orderItemRow
+		if err := rows.StructScan(&row); err != nil {
+			return fmt.Errorf("failed to scan order: %w", err)
+		}
+
+		if current == nil || current.ID != row.OrderID {
+			if current != nil {
+				if err := fn(current); err != nil {
+					return err
+				}
+			}
+			current = &models.Order{
+				ID:              row.OrderID,
+				CustomerID:      row.CustomerID,
+				Status:          row.Status,
+				Total:           row.Total,
+				CreatedAt:       row.CreatedAt,
+				UpdatedAt:       row.UpdatedAt,
+				ShippingAddress: row.ShippingAddress,
+				Products:        []models.OrderItem{},
+			}
+		}
+
+		if row.ItemID.Valid {
+			current.Products = append(current.Products, models.OrderItem{
+				ID:        int(row.ItemID.Int64),
+				OrderID:   row.OrderID,
+				ProductID: int(row.ProductID.Int64),
+				VariantID: int(row.VariantID.Int64),
+				Quantity:  int(row.Quantity.Int64),
+				Price:     int(row.Price.Int64),
+			})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if current != nil {
+		return fn(current)
+	}
+	return nil
+}
+
+func orderFilterClause(filter models.OrderFilter) (string, []interface{}) {
+	var conds []string
+	var args []interface{}
+
+	if filter.CustomerID != 0 {
+		args = append(args, filter.CustomerID)
+		conds = append(conds, fmt.Sprintf("o.customer_id = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conds = append(conds, fmt.Sprintf("o.status = $%d", len(args)))
+	}
+	if !filter.From.IsZero() {
+		args = append(args, filter.From)
+		conds = append(conds, fmt.Sprintf("o.created_at >= $%d", len(args)))
+	}
+	if !filter.To.IsZero() {
+		args = append(args, filter.To)
+		conds = append(conds, fmt.Sprintf("o.created_at < $%d", len(args)))
+	}
+
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return "\n\t\tWHERE " + strings.Join(conds, " AND "), args
+}
@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"backend-store/internal/models"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+func (p *PostgresStorage) CreateLowStockAlert(ctx context.Context, alert *models.LowStockAlert) error {
+	return createLowStockAlert(ctx, p.db, alert)
+}
+
+func (p *PostgresStorage) GetOpenLowStockAlerts(ctx context.Context) ([]models.LowStockAlert, error) {
+	return getOpenLowStockAlerts(ctx, p.db)
+}
+
+func (p *PostgresStorage) MarkLowStockAlertNotified(ctx context.Context, id int, at time.Time) error {
+	return setLowStockAlertTime(ctx, p.db, "notified_at", id, at)
+}
+
+func (p *PostgresStorage) ResolveLowStockAlert(ctx context.Context, id int, at time.Time) error {
+	return setLowStockAlertTime(ctx, p.db, "resolved_at", id, at)
+}
+
+func (pt *PostgresTx) CreateLowStockAlert(ctx context.Context, alert *models.LowStockAlert) error {
+	return createLowStockAlert(ctx, pt.tx, alert)
+}
+
+func (pt *PostgresTx) GetOpenLowStockAlerts(ctx context.Context) ([]models.LowStockAlert, error) {
+	return getOpenLowStockAlerts(ctx, pt.tx)
+}
+
+func (pt *PostgresTx) MarkLowStockAlertNotified(ctx context.Context, id int, at time.Time) error {
+	return setLowStockAlertTime(ctx, pt.tx, "notified_at", id, at)
+}
+
+func (pt *PostgresTx) ResolveLowStockAlert(ctx context.Context, id int, at time.Time) error {
+	return setLowStockAlertTime(ctx, pt.tx, "resolved_at", id, at)
+}
+
+func createLowStockAlert(ctx context.Context, q queryer, alert *models.LowStockAlert) error {
+	query := `
+		INSERT INTO low_stock_alerts (product_id, quantity, reorder_point, reorder_quantity)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at`
+
+	err := q.QueryRowContext(ctx, query,
+		alert.ProductID,
+		alert.Quantity,
+		alert.ReorderPoint,
+		alert.ReorderQuantity,
+	).Scan(&alert.ID, &alert.CreatedAt)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case "23505":
+			return fmt.Errorf("low stock alert for product %d already exists", alert.ProductID)
+		case "23503":
+			return notFound("product")
+		}
+	}
+	return err
+}
+
+func getOpenLowStockAlerts(ctx context.Context, q queryer) ([]models.LowStockAlert, error) {
+	query := `
+		SELECT id, product_id, quantity, reorder_point, reorder_quantity, created_at, notified_at, resolved_at
+		FROM low_stock_alerts
+		WHERE resolved_at IS NULL
+		ORDER BY id`
+	alerts := []models.LowStockAlert{}
+	err := q.SelectContext(ctx, &alerts, query)
+	return alerts, err
+}
+
+// setLowStockAlertTime проставляет отметку column открытого предупреждения.
+// column - только notified_at или resolved_at.
+func setLowStockAlertTime(ctx context.Context, q queryer, column string, id int, at time.Time) error {
+	query := `UPDATE low_stock_alerts SET ` + column + ` = $1 WHERE id = $2 AND resolved_at IS NULL`
+	result, err := q.ExecContext(ctx, query, at, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return notFound("low stock alert")
+	}
+	return nil
+}
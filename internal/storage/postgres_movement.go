@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"backend-store/internal/models"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+func (p *PostgresStorage) GetAllStockLevels(ctx context.Context) ([]models.StockLevel, error) {
+	return getStockLevels(ctx, p.db, "TRUE")
+}
+
+func (p *PostgresStorage) CreateStockMovement(ctx context.Context, movement *models.StockMovement) error {
+	return createStockMovement(ctx, p.db, movement)
+}
+
+func (p *PostgresStorage) GetStockMovementsByProduct(ctx context.Context, productID int) ([]models.StockMovement, error) {
+	return getStockMovementsByProduct(ctx, p.db, productID)
+}
+
+func (p *PostgresStorage) GetStockLedgerBalances(ctx context.Context) ([]models.StockBalance, error) {
+	return getStockLedgerBalances(ctx, p.db)
+}
+
+func (pt *PostgresTx) GetAllStockLevels(ctx context.Context) ([]models.StockLevel, error) {
+	return getStockLevels(ctx, pt.tx, "TRUE")
+}
+
+func (pt *PostgresTx) CreateStockMovement(ctx context.Context, movement *models.StockMovement) error {
+	return createStockMovement(ctx, pt.tx, movement)
+}
+
+func (pt *PostgresTx) GetStockMovementsByProduct(ctx context.Context, productID int) ([]models.StockMovement, error) {
+	return getStockMovementsByProduct(ctx, pt.tx, productID)
+}
+
+func (pt *PostgresTx) GetStockLedgerBalances(ctx context.Context) ([]models.StockBalance, error) {
+	return getStockLedgerBalances(ctx, pt.tx)
+}
+
+func createStockMovement(ctx context.Context, q queryer, movement *models.StockMovement) error {
+	query := `
+		INSERT INTO stock_movements (warehouse_id, variant_id, product_id, delta, balance_after, reason, actor, actor_verified, reference)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9, ''))
+		RETURNING id, created_at`
+
+	err := q.QueryRowContext(ctx, query,
+		movement.WarehouseID,
+		movement.VariantID,
+		movement.ProductID,
+		movement.Delta,
+		movement.BalanceAfter,
+		movement.Reason,
+		movement.Actor,
+		movement.ActorVerified,
+		movement.Reference,
+	).Scan(&movement.ID, &movement.CreatedAt)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23514" {
+		return fmt.Errorf("invalid stock movement: %s", pqErr.Message)
+	}
+	return err
+}
+
+func getStockMovementsByProduct(ctx context.Context, q queryer, productID int) ([]models.StockMovement, error) {
+	query := `
+		SELECT id, warehouse_id, variant_id, product_id, delta, balance_after, reason, actor, actor_verified,
+			COALESCE(reference, '') AS reference, created_at
+		FROM stock_movements
+		WHERE product_id = $1
+		ORDER BY id`
+	movements := []models.StockMovement{}
+	err := q.SelectContext(ctx, &movements, query, productID)
+	return movements, err
+}
+
+// getStockLedgerBalances суммирует журнал и проверяет цепочку BalanceAfter:
+// каждое движение должно продолжать остаток предыдущего.
+func getStockLedgerBalances(ctx context.Context, q queryer) ([]models.StockBalance, error) {
+	query := `
+		SELECT warehouse_id, variant_id, MAX(product_id) AS product_id,
+			SUM(delta) AS quantity,
+			COUNT(*) FILTER (WHERE COALESCE(prev_balance, 0) + delta <> balance_after) AS broken_chain
+		FROM (
+			SELECT warehouse_id, variant_id, product_id, delta, balance_after,
+				LAG(balance_after) OVER (PARTITION BY warehouse_id, variant_id ORDER BY id) AS prev_balance
+			FROM stock_movements
+		) m
+		GROUP BY warehouse_id, variant_id
+		ORDER BY warehouse_id, variant_id`
+	balances := []models.StockBalance{}
+	err := q.SelectContext(ctx, &balances, query)
+	return balances, err
+}
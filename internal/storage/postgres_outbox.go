@@ -0,0 +1,148 @@
+package storage
+
+import (
+	"backend-store/internal/models"
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+)
+
+const outboxColumns = `id, type, aggregate_type, aggregate_id, payload, status, attempts, COALESCE(last_error, '') AS last_error, next_attempt_at, created_at, delivered_at`
+
+func (p *PostgresStorage) CreateOutboxEvent(ctx context.Context, event *models.OutboxEvent) error {
+	return createOutboxEvent(ctx, p.db, event)
+}
+
+func (p *PostgresStorage) GetOutboxEventByID(ctx context.Context, id int) (*models.OutboxEvent, error) {
+	return getOutboxEventByID(ctx, p.db, id)
+}
+
+func (p *PostgresStorage) GetOutboxEvents(ctx context.Context, filter models.OutboxFilter) ([]models.OutboxEvent, error) {
+	return getOutboxEvents(ctx, p.db, filter)
+}
+
+func (p *PostgresStorage) ClaimOutboxEvents(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.OutboxEvent, error) {
+	return claimOutboxEvents(ctx, p.db, now, lease, limit)
+}
+
+func (p *PostgresStorage) UpdateOutboxEvent(ctx context.Context, event *models.OutboxEvent) error {
+	return updateOutboxEvent(ctx, p.db, event)
+}
+
+func (pt *PostgresTx) CreateOutboxEvent(ctx context.Context, event *models.OutboxEvent) error {
+	return createOutboxEvent(ctx, pt.tx, event)
+}
+
+func (pt *PostgresTx) GetOutboxEventByID(ctx context.Context, id int) (*models.OutboxEvent, error) {
+	return getOutboxEventByID(ctx, pt.tx, id)
+}
+
+func (pt *PostgresTx) GetOutboxEvents(ctx context.Context, filter models.OutboxFilter) ([]models.OutboxEvent, error) {
+	return getOutboxEvents(ctx, pt.tx, filter)
+}
+
+func (pt *PostgresTx) ClaimOutboxEvents(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.OutboxEvent, error) {
+	return claimOutboxEvents(ctx, pt.tx, now, lease, limit)
+}
+
+func (pt *PostgresTx) UpdateOutboxEvent(ctx context.Context, event *models.OutboxEvent) error {
+	return updateOutboxEvent(ctx, pt.tx, event)
+}
+
+func createOutboxEvent(ctx context.Context, q queryer, event *models.OutboxEvent) error {
+	query := `
+		INSERT INTO outbox (type, aggregate_type, aggregate_id, payload, status, attempts, last_error, next_attempt_at, created_at, delivered_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8, $9, $10)
+		RETURNING id`
+
+	return q.QueryRowContext(ctx, query,
+		event.Type,
+		event.AggregateType,
+		event.AggregateID,
+		string(event.Payload),
+		event.Status,
+		event.Attempts,
+		event.LastError,
+		event.NextAttemptAt,
+		event.CreatedAt,
+		event.DeliveredAt,
+	).Scan(&event.ID)
+}
+
+func getOutboxEventByID(ctx context.Context, q queryer, id int) (*models.OutboxEvent, error) {
+	var event models.OutboxEvent
+	err := q.GetContext(ctx, &event, `SELECT `+outboxColumns+` FROM outbox WHERE id = $1`, id)
+	if err == sql.ErrNoRows {
+		return nil, notFound("outbox event")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+func getOutboxEvents(ctx context.Context, q queryer, filter models.OutboxFilter) ([]models.OutboxEvent, error) {
+	query := `
+		SELECT ` + outboxColumns + ` FROM outbox
+		WHERE ($1 = '' OR status = $1) AND ($2 = '' OR type = $2)
+		ORDER BY id DESC`
+	args := []interface{}{filter.Status, filter.Type}
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += ` LIMIT $3`
+	}
+
+	events := []models.OutboxEvent{}
+	if err := q.SelectContext(ctx, &events, query, args...); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// claimOutboxEvents пропускает строки, заблокированные другим экземпляром,
+// поэтому одно событие не выдается двум диспетчерам одновременно.
+func claimOutboxEvents(ctx context.Context, q queryer, now time.Time, lease time.Duration, limit int) ([]models.OutboxEvent, error) {
+	query := `
+		UPDATE outbox SET next_attempt_at = $2
+		WHERE id IN (
+			SELECT id FROM outbox
+			WHERE status = $3 AND next_attempt_at <= $1
+			ORDER BY id
+			LIMIT $4
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + outboxColumns
+
+	events := []models.OutboxEvent{}
+	if err := q.SelectContext(ctx, &events, query, now, now.Add(lease), models.OutboxPending, limit); err != nil {
+		return nil, fmt.Errorf("failed to claim outbox events: %w", err)
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].ID < events[j].ID })
+	return events, nil
+}
+
+func updateOutboxEvent(ctx context.Context, q queryer, event *models.OutboxEvent) error {
+	query := `
+		UPDATE outbox
+		SET status = $1, attempts = $2, last_error = NULLIF($3, ''), next_attempt_at = $4, delivered_at = $5
+		WHERE id = $6`
+
+	result, err := q.ExecContext(ctx, query,
+		event.Status,
+		event.Attempts,
+		event.LastError,
+		event.NextAttemptAt,
+		event.DeliveredAt,
+		event.ID,
+	)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return notFound("outbox event")
+	}
+	return nil
+}
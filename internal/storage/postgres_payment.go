@@ -0,0 +1,184 @@
+package storage
+
+import (
+	"backend-store/internal/models"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const paymentColumns = `id, order_id, provider, COALESCE(reference, '') AS reference, amount, refunded_amount, status,
+	COALESCE(failure_reason, '') AS failure_reason, COALESCE(action_url, '') AS action_url, created_at, updated_at`
+
+func (p *PostgresStorage) CreatePayment(ctx context.Context, payment *models.Payment) error {
+	return createPayment(ctx, p.db, payment)
+}
+
+func (p *PostgresStorage) GetPaymentByID(ctx context.Context, id int) (*models.Payment, error) {
+	return getPayment(ctx, p.db, "id = $1", id)
+}
+
+func (p *PostgresStorage) GetPaymentByReference(ctx context.Context, provider, reference string) (*models.Payment, error) {
+	return getPayment(ctx, p.db, "provider = $1 AND reference = $2", provider, reference)
+}
+
+func (p *PostgresStorage) GetPaymentsByOrder(ctx context.Context, orderID int) ([]models.Payment, error) {
+	return getPaymentsByOrder(ctx, p.db, orderID)
+}
+
+func (p *PostgresStorage) UpdatePayment(ctx context.Context, payment *models.Payment) error {
+	return updatePayment(ctx, p.db, payment)
+}
+
+func (p *PostgresStorage) AddPaymentRefund(ctx context.Context, id, delta int) (*models.Payment, error) {
+	return addPaymentRefund(ctx, p.db, id, delta)
+}
+
+func (pt *PostgresTx) CreatePayment(ctx context.Context, payment *models.Payment) error {
+	return createPayment(ctx, pt.tx, payment)
+}
+
+func (pt *PostgresTx) GetPaymentByID(ctx context.Context, id int) (*models.Payment, error) {
+	return getPayment(ctx, pt.tx, "id = $1", id)
+}
+
+func (pt *PostgresTx) GetPaymentByReference(ctx context.Context, provider, reference string) (*models.Payment, error) {
+	return getPayment(ctx, pt.tx, "provider = $1 AND reference = $2", provider, reference)
+}
+
+func (pt *PostgresTx) GetPaymentsByOrder(ctx context.Context, orderID int) ([]models.Payment, error) {
+	return getPaymentsByOrder(ctx, pt.tx, orderID)
+}
+
+func (pt *PostgresTx) UpdatePayment(ctx context.Context, payment *models.Payment) error {
+	return updatePayment(ctx, pt.tx, payment)
+}
+
+func (pt *PostgresTx) AddPaymentRefund(ctx context.Context, id, delta int) (*models.Payment, error) {
+	return addPaymentRefund(ctx, pt.tx, id, delta)
+}
+
+func createPayment(ctx context.Context, q queryer, payment *models.Payment) error {
+	query := `
+		INSERT INTO payments (order_id, provider, reference, amount, refunded_amount, status,
+			failure_reason, action_url, created_at, updated_at)
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6, NULLIF($7, ''), NULLIF($8, ''), $9, $10)
+		RETURNING id`
+
+	err := q.QueryRowContext(ctx, query,
+		payment.OrderID,
+		payment.Provider,
+		payment.Reference,
+		payment.Amount,
+		payment.RefundedAmount,
+		payment.Status,
+		payment.FailureReason,
+		payment.ActionURL,
+		payment.CreatedAt,
+		payment.UpdatedAt,
+	).Scan(&payment.ID)
+	return paymentError(err, payment.OrderID)
+}
+
+func getPayment(ctx context.Context, q queryer, condition string, args ...interface{}) (*models.Payment, error) {
+	query := `SELECT ` + paymentColumns + ` FROM payments WHERE ` + condition
+	var payment models.Payment
+	err := q.GetContext(ctx, &payment, query, args...)
+	if err == sql.ErrNoRows {
+		return nil, notFound("payment")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+func getPaymentsByOrder(ctx context.Context, q queryer, orderID int) ([]models.Payment, error) {
+	query := `SELECT ` + paymentColumns + ` FROM payments WHERE order_id = $1 ORDER BY id`
+	payments := []models.Payment{}
+	err := q.SelectContext(ctx, &payments, query, orderID)
+	return payments, err
+}
+
+func updatePayment(ctx context.Context, q queryer, payment *models.Payment) error {
+	query := `
+		UPDATE payments
+		SET reference = NULLIF($1, ''), refunded_amount = $2, status = $3,
+			failure_reason = NULLIF($4, ''), action_url = NULLIF($5, ''), updated_at = $6
+		WHERE id = $7`
+
+	result, err := q.ExecContext(ctx, query,
+		payment.Reference,
+		payment.RefundedAmount,
+		payment.Status,
+		payment.FailureReason,
+		payment.ActionURL,
+		payment.UpdatedAt,
+		payment.ID,
+	)
+	if err != nil {
+		return paymentError(err, payment.OrderID)
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return notFound("payment")
+	}
+	return nil
+}
+
+func addPaymentRefund(ctx context.Context, q queryer, id, delta int) (*models.Payment, error) {
+	query := `
+		UPDATE payments
+		SET refunded_amount = refunded_amount + $1,
+			status = CASE WHEN refunded_amount + $1 = amount THEN $3 ELSE $4 END,
+			updated_at = $5
+		WHERE id = $2 AND status IN ($3, $4) AND refunded_amount + $1 BETWEEN 0 AND amount
+		RETURNING ` + paymentColumns
+
+	var payment models.Payment
+	err := q.GetContext(ctx, &payment, query, delta, id, models.PaymentRefunded, models.PaymentCaptured, time.Now())
+	if err == sql.ErrNoRows {
+		existing, err := getPayment(ctx, q, "id = $1", id)
+		if err != nil {
+			return nil, err
+		}
+		return nil, refundError(existing, delta)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+// refundError объясняет, почему возврат delta по платежу невозможен; те же
+// ошибки возвращает MemoryStorage.
+func refundError(payment *models.Payment, delta int) error {
+	if payment.Status != models.PaymentCaptured && payment.Status != models.PaymentRefunded {
+		return fmt.Errorf("cannot refund payment in status %q", payment.Status)
+	}
+	if delta > 0 {
+		return fmt.Errorf("validate: refund amount must be between 1 and %d", payment.Amount-payment.RefundedAmount)
+	}
+	return fmt.Errorf("cannot release %d of payment %d: only %d refunded", -delta, payment.ID, payment.RefundedAmount)
+}
+
+// paymentError переводит нарушения ограничений в те же ошибки, что и
+// MemoryStorage.
+func paymentError(err error, orderID int) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch {
+		case pqErr.Code == "23505" && pqErr.Constraint == "idx_payments_active_order":
+			return fmt.Errorf("order %d already has an active payment", orderID)
+		case pqErr.Code == "23505":
+			return errors.New("payment reference already exists")
+		case pqErr.Code == "23503":
+			return notFound("order")
+		}
+	}
+	return err
+}
@@ -0,0 +1,326 @@
+package storage
+
+import (
+	"backend-store/internal/models"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+const promotionColumns = `id, COALESCE(code, '') AS code, name, type, value, buy_quantity, get_quantity, product_ids,
+	min_subtotal, usage_limit, per_customer_limit, usage_count, starts_at, ends_at, active, created_at, updated_at`
+
+const adjustmentColumns = `id, order_id, promotion_id, COALESCE(code, '') AS code, type, COALESCE(product_id, 0) AS product_id,
+	COALESCE(variant_id, 0) AS variant_id, amount, description`
+
+func (p *PostgresStorage) CreatePromotion(ctx context.Context, promotion *models.Promotion) error {
+	return createPromotion(ctx, p.db, promotion)
+}
+
+func (p *PostgresStorage) GetPromotionByID(ctx context.Context, id int) (*models.Promotion, error) {
+	return getPromotion(ctx, p.db, `WHERE id = $1`, id)
+}
+
+func (p *PostgresStorage) GetPromotionByCode(ctx context.Context, code string) (*models.Promotion, error) {
+	return getPromotion(ctx, p.db, `WHERE code = $1`, code)
+}
+
+func (p *PostgresStorage) GetAllPromotions(ctx context.Context) ([]*models.Promotion, error) {
+	return getAllPromotions(ctx, p.db)
+}
+
+func (p *PostgresStorage) UpdatePromotion(ctx context.Context, promotion *models.Promotion) error {
+	return updatePromotion(ctx, p.db, promotion)
+}
+
+func (p *PostgresStorage) DeletePromotion(ctx context.Context, id int) error {
+	return deletePromotion(ctx, p.db, id)
+}
+
+func (p *PostgresStorage) RedeemPromotion(ctx context.Context, redemption *models.PromotionRedemption) error {
+	return redeemPromotion(ctx, p.db, redemption)
+}
+
+func (p *PostgresStorage) CountPromotionRedemptions(ctx context.Context, promotionID, customerID int) (int, error) {
+	return countPromotionRedemptions(ctx, p.db, promotionID, customerID)
+}
+
+func (p *PostgresStorage) ReleasePromotions(ctx context.Context, orderID int) error {
+	return releasePromotions(ctx, p.db, orderID)
+}
+
+func (p *PostgresStorage) CreateOrderAdjustments(ctx context.Context, orderID int, adjustments []models.OrderAdjustment) error {
+	return createOrderAdjustments(ctx, p.db, orderID, adjustments)
+}
+
+func (p *PostgresStorage) GetOrderAdjustments(ctx context.Context, orderID int) ([]models.OrderAdjustment, error) {
+	return getOrderAdjustments(ctx, p.db, orderID)
+}
+
+func (p *PostgresStorage) DeleteOrderAdjustments(ctx context.Context, orderID int) error {
+	_, err := p.db.ExecContext(ctx, `DELETE FROM order_adjustments WHERE order_id = $1`, orderID)
+	return err
+}
+
+func (pt *PostgresTx) CreatePromotion(ctx context.Context, promotion *models.Promotion) error {
+	return createPromotion(ctx, pt.tx, promotion)
+}
+
+func (pt *PostgresTx) GetPromotionByID(ctx context.Context, id int) (*models.Promotion, error) {
+	return getPromotion(ctx, pt.tx, `WHERE id = $1`, id)
+}
+
+func (pt *PostgresTx) GetPromotionByCode(ctx context.Context, code string) (*models.Promotion, error) {
+	return getPromotion(ctx, pt.tx, `WHERE code = $1`, code)
+}
+
+func (pt *PostgresTx) GetAllPromotions(ctx context.Context) ([]*models.Promotion, error) {
+	return getAllPromotions(ctx, pt.tx)
+}
+
+func (pt *PostgresTx) UpdatePromotion(ctx context.Context, promotion *models.Promotion) error {
+	return updatePromotion(ctx, pt.tx, promotion)
+}
+
+func (pt *PostgresTx) DeletePromotion(ctx context.Context, id int) error {
+	return deletePromotion(ctx, pt.tx, id)
+}
+
+func (pt *PostgresTx) RedeemPromotion(ctx context.Context, redemption *models.PromotionRedemption) error {
+	return redeemPromotion(ctx, pt.tx, redemption)
+}
+
+func (pt *PostgresTx) CountPromotionRedemptions(ctx context.Context, promotionID, customerID int) (int, error) {
+	return countPromotionRedemptions(ctx, pt.tx, promotionID, customerID)
+}
+
+func (pt *PostgresTx) ReleasePromotions(ctx context.Context, orderID int) error {
+	return releasePromotions(ctx, pt.tx, orderID)
+}
+
+func (pt *PostgresTx) CreateOrderAdjustments(ctx context.Context, orderID int, adjustments []models.OrderAdjustment) error {
+	return createOrderAdjustments(ctx, pt.tx, orderID, adjustments)
+}
+
+func (pt *PostgresTx) GetOrderAdjustments(ctx context.Context, orderID int) ([]models.OrderAdjustment, error) {
+	return getOrderAdjustments(ctx, pt.tx, orderID)
+}
+
+func (pt *PostgresTx) DeleteOrderAdjustments(ctx context.Context, orderID int) error {
+	_, err := pt.tx.ExecContext(ctx, `DELETE FROM order_adjustments WHERE order_id = $1`, orderID)
+	return err
+}
+
+func createPromotion(ctx context.Context, q queryer, promotion *models.Promotion) error {
+	query := `
+		INSERT INTO promotions (code, name, type, value, buy_quantity, get_quantity, product_ids, min_subtotal,
+			usage_limit, per_customer_limit, starts_at, ends_at, active, created_at, updated_at)
+		VALUES (NULLIF($1, ''), $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		RETURNING id, usage_count`
+
+	err := q.QueryRowContext(ctx, query,
+		promotion.Code,
+		promotion.Name,
+		promotion.Type,
+		promotion.Value,
+		promotion.BuyQuantity,
+		promotion.GetQuantity,
+		promotion.ProductIDs,
+		promotion.MinSubtotal,
+		promotion.UsageLimit,
+		promotion.PerCustomerLimit,
+		promotion.StartsAt,
+		promotion.EndsAt,
+		promotion.Active,
+		promotion.CreatedAt,
+		promotion.UpdatedAt,
+	).Scan(&promotion.ID, &promotion.UsageCount)
+	if err != nil {
+		return promotionError(err, promotion.Code)
+	}
+	return nil
+}
+
+func getPromotion(ctx context.Context, q queryer, where string, arg interface{}) (*models.Promotion, error) {
+	var promotion models.Promotion
+	err := q.GetContext(ctx, &promotion, `SELECT `+promotionColumns+` FROM promotions `+where, arg)
+	if err == sql.ErrNoRows {
+		return nil, notFound("promotion")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &promotion, nil
+}
+
+func getAllPromotions(ctx context.Context, q queryer) ([]*models.Promotion, error) {
+	promotions := []*models.Promotion{}
+	if err := q.SelectContext(ctx, &promotions, `SELECT `+promotionColumns+` FROM promotions ORDER BY id`); err != nil {
+		return nil, err
+	}
+	return promotions, nil
+}
+
+// updatePromotion не меняет usage_count: счетчик ведут только
+// RedeemPromotion и ReleasePromotions.
+func updatePromotion(ctx context.Context, q queryer, promotion *models.Promotion) error {
+	query := `
+		UPDATE promotions
+		SET code = NULLIF($1, ''), name = $2, type = $3, value = $4, buy_quantity = $5, get_quantity = $6,
+			product_ids = $7, min_subtotal = $8, usage_limit = $9, per_customer_limit = $10,
+			starts_at = $11, ends_at = $12, active = $13, updated_at = $14
+		WHERE id = $15
+		RETURNING usage_count, created_at`
+
+	err := q.QueryRowContext(ctx, query,
+		promotion.Code,
+		promotion.Name,
+		promotion.Type,
+		promotion.Value,
+		promotion.BuyQuantity,
+		promotion.GetQuantity,
+		promotion.ProductIDs,
+		promotion.MinSubtotal,
+		promotion.UsageLimit,
+		promotion.PerCustomerLimit,
+		promotion.StartsAt,
+		promotion.EndsAt,
+		promotion.Active,
+		promotion.UpdatedAt,
+		promotion.ID,
+	).Scan(&promotion.UsageCount, &promotion.CreatedAt)
+	if err == sql.ErrNoRows {
+		return notFound("promotion")
+	}
+	if err != nil {
+		return promotionError(err, promotion.Code)
+	}
+	return nil
+}
+
+func deletePromotion(ctx context.Context, q queryer, id int) error {
+	result, err := q.ExecContext(ctx, `DELETE FROM promotions WHERE id = $1`, id)
+	if err != nil {
+		return promotionError(err, "")
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return notFound("promotion")
+	}
+	return nil
+}
+
+// redeemPromotion увеличивает счетчик условным UPDATE, поэтому два
+// одновременных заказа не превысят общий лимит.
+// redeemPromotion увеличивает счетчик условным UPDATE, который заодно
+// блокирует строку акции до конца транзакции. Поэтому одновременные
+// использования той же акции ждут друг друга, и подсчет использований
+// покупателя в условном INSERT не устаревает.
+func redeemPromotion(ctx context.Context, q queryer, redemption *models.PromotionRedemption) error {
+	var perCustomerLimit int
+	err := q.GetContext(ctx, &perCustomerLimit, `
+		UPDATE promotions SET usage_count = usage_count + 1
+		WHERE id = $1 AND (usage_limit = 0 OR usage_count < usage_limit)
+		RETURNING per_customer_limit`, redemption.PromotionID)
+	if err == sql.ErrNoRows {
+		promotion, err := getPromotion(ctx, q, `WHERE id = $1`, redemption.PromotionID)
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("promotion %q %w", promotion.Code, ErrUsageLimitReached)
+	}
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO promotion_redemptions (promotion_id, order_id, customer_id, created_at)
+		SELECT $1::integer, $2::integer, $3::integer, $4::timestamp
+		WHERE $5 = 0 OR $3 = 0 OR (
+			SELECT COUNT(*) FROM promotion_redemptions WHERE promotion_id = $1 AND customer_id = $3
+		) < $5
+		RETURNING id`
+	err = q.QueryRowContext(ctx, query,
+		redemption.PromotionID,
+		redemption.OrderID,
+		redemption.CustomerID,
+		redemption.CreatedAt,
+		perCustomerLimit,
+	).Scan(&redemption.ID)
+	if err == sql.ErrNoRows {
+		promotion, err := getPromotion(ctx, q, `WHERE id = $1`, redemption.PromotionID)
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("promotion %q per-customer %w", promotion.Code, ErrUsageLimitReached)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save promotion redemption: %w", err)
+	}
+	return nil
+}
+
+func countPromotionRedemptions(ctx context.Context, q queryer, promotionID, customerID int) (int, error) {
+	var count int
+	err := q.GetContext(ctx, &count,
+		`SELECT COUNT(*) FROM promotion_redemptions WHERE promotion_id = $1 AND customer_id = $2`, promotionID, customerID)
+	return count, err
+}
+
+func releasePromotions(ctx context.Context, q queryer, orderID int) error {
+	query := `
+		WITH released AS (
+			DELETE FROM promotion_redemptions WHERE order_id = $1 RETURNING promotion_id
+		)
+		UPDATE promotions p SET usage_count = GREATEST(p.usage_count - r.n, 0)
+		FROM (SELECT promotion_id, COUNT(*) AS n FROM released GROUP BY promotion_id) r
+		WHERE p.id = r.promotion_id`
+	_, err := q.ExecContext(ctx, query, orderID)
+	return err
+}
+
+func createOrderAdjustments(ctx context.Context, q queryer, orderID int, adjustments []models.OrderAdjustment) error {
+	query := `
+		INSERT INTO order_adjustments (order_id, promotion_id, code, type, product_id, variant_id, amount, description)
+		VALUES ($1, $2, NULLIF($3, ''), $4, NULLIF($5, 0), NULLIF($6, 0), $7, $8)
+		RETURNING id`
+
+	for i := range adjustments {
+		a := &adjustments[i]
+		a.OrderID = orderID
+		err := q.QueryRowContext(ctx, query,
+			orderID, a.PromotionID, a.Code, a.Type, a.ProductID, a.VariantID, a.Amount, a.Description,
+		).Scan(&a.ID)
+		if err != nil {
+			return fmt.Errorf("failed to create order adjustment: %w", err)
+		}
+	}
+	return nil
+}
+
+func getOrderAdjustments(ctx context.Context, q queryer, orderID int) ([]models.OrderAdjustment, error) {
+	adjustments := []models.OrderAdjustment{}
+	query := `SELECT ` + adjustmentColumns + ` FROM order_adjustments WHERE order_id = $1 ORDER BY id`
+	if err := q.SelectContext(ctx, &adjustments, query, orderID); err != nil {
+		return nil, err
+	}
+	return adjustments, nil
+}
+
+// promotionError переводит нарушения ограничений в понятные ошибки:
+// уникальность кода и ссылки из использований.
+func promotionError(err error, code string) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case "23505":
+			return fmt.Errorf("promotion with code %q already exists", code)
+		case "23503":
+			return errors.New("cannot delete promotion with redemptions")
+		}
+	}
+	return err
+}
@@ -0,0 +1,321 @@
+package storage
+
+import (
+	"backend-store/internal/models"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+const supplierColumns = `id, name, COALESCE(email, '') AS email, COALESCE(phone, '') AS phone, COALESCE(note, '') AS note, created_at, updated_at`
+
+const purchaseOrderColumns = `id, supplier_id, warehouse_id, status, COALESCE(note, '') AS note, total, sent_at, received_at, created_at, updated_at`
+
+const purchaseOrderItemColumns = `id, purchase_order_id, product_id, variant_id, quantity, received_quantity, unit_cost`
+
+func (p *PostgresStorage) CreateSupplier(ctx context.Context, supplier *models.Supplier) error {
+	return createSupplier(ctx, p.db, supplier)
+}
+
+func (p *PostgresStorage) GetSupplierByID(ctx context.Context, id int) (*models.Supplier, error) {
+	return getSupplierByID(ctx, p.db, id)
+}
+
+func (p *PostgresStorage) GetAllSuppliers(ctx context.Context) ([]*models.Supplier, error) {
+	return getAllSuppliers(ctx, p.db)
+}
+
+func (p *PostgresStorage) UpdateSupplier(ctx context.Context, supplier *models.Supplier) error {
+	return updateSupplier(ctx, p.db, supplier)
+}
+
+func (p *PostgresStorage) DeleteSupplier(ctx context.Context, id int) error {
+	return deleteSupplier(ctx, p.db, id)
+}
+
+func (p *PostgresStorage) CreatePurchaseOrder(ctx context.Context, order *models.PurchaseOrder) error {
+	return createPurchaseOrder(ctx, p.db, order)
+}
+
+func (p *PostgresStorage) GetPurchaseOrderByID(ctx context.Context, id int) (*models.PurchaseOrder, error) {
+	return getPurchaseOrderByID(ctx, p.db, id)
+}
+
+func (p *PostgresStorage) LockPurchaseOrder(ctx context.Context, id int) (*models.PurchaseOrder, error) {
+	return lockPurchaseOrder(ctx, p.db, id)
+}
+
+func (p *PostgresStorage) GetAllPurchaseOrders(ctx context.Context, status string) ([]*models.PurchaseOrder, error) {
+	return getAllPurchaseOrders(ctx, p.db, status)
+}
+
+func (p *PostgresStorage) UpdatePurchaseOrder(ctx context.Context, order *models.PurchaseOrder) error {
+	return updatePurchaseOrder(ctx, p.db, order)
+}
+
+func (p *PostgresStorage) ReplacePurchaseOrderItems(ctx context.Context, order *models.PurchaseOrder) error {
+	return replacePurchaseOrderItems(ctx, p.db, order)
+}
+
+func (pt *PostgresTx) CreateSupplier(ctx context.Context, supplier *models.Supplier) error {
+	return createSupplier(ctx, pt.tx, supplier)
+}
+
+func (pt *PostgresTx) GetSupplierByID(ctx context.Context, id int) (*models.Supplier, error) {
+	return getSupplierByID(ctx, pt.tx, id)
+}
+
+func (pt *PostgresTx) GetAllSuppliers(ctx context.Context) ([]*models.Supplier, error) {
+	return getAllSuppliers(ctx, pt.tx)
+}
+
+func (pt *PostgresTx) UpdateSupplier(ctx context.Context, supplier *models.Supplier) error {
+	return updateSupplier(ctx, pt.tx, supplier)
+}
+
+func (pt *PostgresTx) DeleteSupplier(ctx context.Context, id int) error {
+	return deleteSupplier(ctx, pt.tx, id)
+}
+
+func (pt *PostgresTx) CreatePurchaseOrder(ctx context.Context, order *models.PurchaseOrder) error {
+	return createPurchaseOrder(ctx, pt.tx, order)
+}
+
+func (pt *PostgresTx) GetPurchaseOrderByID(ctx context.Context, id int) (*models.PurchaseOrder, error) {
+	return getPurchaseOrderByID(ctx, pt.tx, id)
+}
+
+func (pt *PostgresTx) LockPurchaseOrder(ctx context.Context, id int) (*models.PurchaseOrder, error) {
+	return lockPurchaseOrder(ctx, pt.tx, id)
+}
+
+func (pt *PostgresTx) GetAllPurchaseOrders(ctx context.Context, status string) ([]*models.PurchaseOrder, error) {
+	return getAllPurchaseOrders(ctx, pt.tx, status)
+}
+
+func (pt *PostgresTx) UpdatePurchaseOrder(ctx context.Context, order *models.PurchaseOrder) error {
+	return updatePurchaseOrder(ctx, pt.tx, order)
+}
+
+func (pt *PostgresTx) ReplacePurchaseOrderItems(ctx context.Context, order *models.PurchaseOrder) error {
+	return replacePurchaseOrderItems(ctx, pt.tx, order)
+}
+
+func createSupplier(ctx context.Context, q queryer, supplier *models.Supplier) error {
+	query := `
+		INSERT INTO suppliers (name, email, phone, note, created_at, updated_at)
+		VALUES ($1, NULLIF($2, ''), NULLIF($3, ''), NULLIF($4, ''), $5, $6)
+		RETURNING id`
+
+	return q.QueryRowContext(ctx, query,
+		supplier.Name,
+		supplier.Email,
+		supplier.Phone,
+		supplier.Note,
+		supplier.CreatedAt,
+		supplier.UpdatedAt,
+	).Scan(&supplier.ID)
+}
+
+func getSupplierByID(ctx context.Context, q queryer, id int) (*models.Supplier, error) {
+	var supplier models.Supplier
+	err := q.GetContext(ctx, &supplier, `SELECT `+supplierColumns+` FROM suppliers WHERE id = $1`, id)
+	if err == sql.ErrNoRows {
+		return nil, notFound("supplier")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &supplier, nil
+}
+
+func getAllSuppliers(ctx context.Context, q queryer) ([]*models.Supplier, error) {
+	suppliers := []*models.Supplier{}
+	err := q.SelectContext(ctx, &suppliers, `SELECT `+supplierColumns+` FROM suppliers ORDER BY id`)
+	return suppliers, err
+}
+
+func updateSupplier(ctx context.Context, q queryer, supplier *models.Supplier) error {
+	query := `
+		UPDATE suppliers
+		SET name = $1, email = NULLIF($2, ''), phone = NULLIF($3, ''), note = NULLIF($4, ''), updated_at = $5
+		WHERE id = $6`
+
+	result, err := q.ExecContext(ctx, query,
+		supplier.Name,
+		supplier.Email,
+		supplier.Phone,
+		supplier.Note,
+		supplier.UpdatedAt,
+		supplier.ID,
+	)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return notFound("supplier")
+	}
+	return nil
+}
+
+func deleteSupplier(ctx context.Context, q queryer, id int) error {
+	result, err := q.ExecContext(ctx, `DELETE FROM suppliers WHERE id = $1`, id)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			return errors.New("cannot delete supplier with purchase orders")
+		}
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return notFound("supplier")
+	}
+	return nil
+}
+
+func createPurchaseOrder(ctx context.Context, q queryer, order *models.PurchaseOrder) error {
+	query := `
+		INSERT INTO purchase_orders (supplier_id, warehouse_id, status, note, total, sent_at, received_at, created_at, updated_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6, $7, $8, $9)
+		RETURNING id`
+
+	err := q.QueryRowContext(ctx, query,
+		order.SupplierID,
+		order.WarehouseID,
+		order.Status,
+		order.Note,
+		order.Total,
+		order.SentAt,
+		order.ReceivedAt,
+		order.CreatedAt,
+		order.UpdatedAt,
+	).Scan(&order.ID)
+	if err != nil {
+		return err
+	}
+	return insertPurchaseOrderItems(ctx, q, order)
+}
+
+func insertPurchaseOrderItems(ctx context.Context, q queryer, order *models.PurchaseOrder) error {
+	itemQuery := `
+		INSERT INTO purchase_order_items (purchase_order_id, product_id, variant_id, quantity, received_quantity, unit_cost)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id`
+
+	for i := range order.Items {
+		item := &order.Items[i]
+		item.PurchaseOrderID = order.ID
+		err := q.QueryRowContext(ctx, itemQuery,
+			order.ID, item.ProductID, item.VariantID, item.Quantity, item.ReceivedQuantity, item.UnitCost,
+		).Scan(&item.ID)
+		if err != nil {
+			return fmt.Errorf("failed to create purchase order item: %w", err)
+		}
+	}
+	return nil
+}
+
+func getPurchaseOrderByID(ctx context.Context, q queryer, id int) (*models.PurchaseOrder, error) {
+	var order models.PurchaseOrder
+	err := q.GetContext(ctx, &order, `SELECT `+purchaseOrderColumns+` FROM purchase_orders WHERE id = $1`, id)
+	if err == sql.ErrNoRows {
+		return nil, notFound("purchase order")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	order.Items = []models.PurchaseOrderItem{}
+	itemQuery := `SELECT ` + purchaseOrderItemColumns + ` FROM purchase_order_items WHERE purchase_order_id = $1 ORDER BY id`
+	if err := q.SelectContext(ctx, &order.Items, itemQuery, order.ID); err != nil {
+		return nil, fmt.Errorf("failed to get purchase order items: %w", err)
+	}
+	return &order, nil
+}
+
+// lockPurchaseOrder блокирует строку заказа (SELECT ... FOR UPDATE), чтобы
+// параллельные приемки и смены статуса выполнялись по очереди и читали
+// результат друг друга.
+func lockPurchaseOrder(ctx context.Context, q queryer, id int) (*models.PurchaseOrder, error) {
+	if _, err := q.ExecContext(ctx, `SELECT 1 FROM purchase_orders WHERE id = $1 FOR UPDATE`, id); err != nil {
+		return nil, err
+	}
+	return getPurchaseOrderByID(ctx, q, id)
+}
+
+func getAllPurchaseOrders(ctx context.Context, q queryer, status string) ([]*models.PurchaseOrder, error) {
+	orders := []*models.PurchaseOrder{}
+	query := `SELECT ` + purchaseOrderColumns + ` FROM purchase_orders WHERE $1 = '' OR status = $1 ORDER BY id`
+	if err := q.SelectContext(ctx, &orders, query, status); err != nil {
+		return nil, err
+	}
+
+	var items []models.PurchaseOrderItem
+	itemQuery := `
+		SELECT ` + purchaseOrderItemColumns + ` FROM purchase_order_items
+		WHERE purchase_order_id IN (SELECT id FROM purchase_orders WHERE $1 = '' OR status = $1)
+		ORDER BY purchase_order_id, id`
+	if err := q.SelectContext(ctx, &items, itemQuery, status); err != nil {
+		return nil, fmt.Errorf("failed to get purchase order items: %w", err)
+	}
+	byOrder := make(map[int][]models.PurchaseOrderItem)
+	for _, item := range items {
+		byOrder[item.PurchaseOrderID] = append(byOrder[item.PurchaseOrderID], item)
+	}
+	for _, o := range orders {
+		o.Items = byOrder[o.ID]
+		if o.Items == nil {
+			o.Items = []models.PurchaseOrderItem{}
+		}
+	}
+	return orders, nil
+}
+
+// updatePurchaseOrder не меняет состав позиций: из позиций сохраняются
+// только принятые количества.
+func updatePurchaseOrder(ctx context.Context, q queryer, order *models.PurchaseOrder) error {
+	query := `
+		UPDATE purchase_orders
+		SET supplier_id = $1, warehouse_id = $2, status = $3, note = NULLIF($4, ''), total = $5,
+			sent_at = $6, received_at = $7, updated_at = $8
+		WHERE id = $9`
+
+	result, err := q.ExecContext(ctx, query,
+		order.SupplierID,
+		order.WarehouseID,
+		order.Status,
+		order.Note,
+		order.Total,
+		order.SentAt,
+		order.ReceivedAt,
+		order.UpdatedAt,
+		order.ID,
+	)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return notFound("purchase order")
+	}
+
+	itemQuery := `UPDATE purchase_order_items SET received_quantity = $1 WHERE id = $2 AND purchase_order_id = $3`
+	for _, item := range order.Items {
+		if _, err := q.ExecContext(ctx, itemQuery, item.ReceivedQuantity, item.ID, order.ID); err != nil {
+			return fmt.Errorf("failed to update purchase order item: %w", err)
+		}
+	}
+	return nil
+}
+
+func replacePurchaseOrderItems(ctx context.Context, q queryer, order *models.PurchaseOrder) error {
+	if _, err := q.ExecContext(ctx, `DELETE FROM purchase_order_items WHERE purchase_order_id = $1`, order.ID); err != nil {
+		return fmt.Errorf("failed to delete purchase order items: %w", err)
+	}
+	return insertPurchaseOrderItems(ctx, q, order)
+}
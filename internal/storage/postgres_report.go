@@ -0,0 +1,182 @@
+package storage
+
+import (
+	"backend-store/internal/models"
+	"context"
+	"fmt"
+	"strings"
+)
+
+func (p *PostgresStorage) ReportSales(ctx context.Context, filter models.ReportFilter) ([]models.SalesPoint, error) {
+	return reportSales(ctx, p.db, filter)
+}
+
+func (p *PostgresStorage) ReportTopProducts(ctx context.Context, filter models.ReportFilter) ([]models.ProductSales, error) {
+	return reportTopProducts(ctx, p.db, filter)
+}
+
+func (p *PostgresStorage) ReportOrderValue(ctx context.Context, filter models.ReportFilter) (*models.OrderValue, error) {
+	return reportOrderValue(ctx, p.db, filter)
+}
+
+func (p *PostgresStorage) ReportOrderStatuses(ctx context.Context, filter models.ReportFilter) ([]models.StatusCount, error) {
+	return reportOrderStatuses(ctx, p.db, filter)
+}
+
+func (p *PostgresStorage) ReportStockValuation(ctx context.Context, filter models.ReportFilter) ([]models.StockValuation, error) {
+	return reportStockValuation(ctx, p.db, filter)
+}
+
+func (pt *PostgresTx) ReportSales(ctx context.Context, filter models.ReportFilter) ([]models.SalesPoint, error) {
+	return reportSales(ctx, pt.tx, filter)
+}
+
+func (pt *PostgresTx) ReportTopProducts(ctx context.Context, filter models.ReportFilter) ([]models.ProductSales, error) {
+	return reportTopProducts(ctx, pt.tx, filter)
+}
+
+func (pt *PostgresTx) ReportOrderValue(ctx context.Context, filter models.ReportFilter) (*models.OrderValue, error) {
+	return reportOrderValue(ctx, pt.tx, filter)
+}
+
+func (pt *PostgresTx) ReportOrderStatuses(ctx context.Context, filter models.ReportFilter) ([]models.StatusCount, error) {
+	return reportOrderStatuses(ctx, pt.tx, filter)
+}
+
+func (pt *PostgresTx) ReportStockValuation(ctx context.Context, filter models.ReportFilter) ([]models.StockValuation, error) {
+	return reportStockValuation(ctx, pt.tx, filter)
+}
+
+// reportFilterClause ограничивает заказы периодом отчета. created_at хранится
+// без часового пояса в UTC, поэтому границы передаются в UTC.
+func reportFilterClause(filter models.ReportFilter, revenueOnly bool, args []interface{}) (string, []interface{}) {
+	var conds []string
+	if revenueOnly {
+		args = append(args, models.OrderStatusCancelled)
+		conds = append(conds, fmt.Sprintf("o.status <> $%d", len(args)))
+	}
+	if !filter.From.IsZero() {
+		args = append(args, filter.From.UTC())
+		conds = append(conds, fmt.Sprintf("o.created_at >= $%d", len(args)))
+	}
+	if !filter.To.IsZero() {
+		args = append(args, filter.To.UTC())
+		conds = append(conds, fmt.Sprintf("o.created_at < $%d", len(args)))
+	}
+
+	if len(conds) == 0 {
+		return "", args
+	}
+	return "\n\t\tWHERE " + strings.Join(conds, " AND "), args
+}
+
+func reportLocation(filter models.ReportFilter) string {
+	if filter.Location == nil {
+		return "UTC"
+	}
+	return filter.Location.String()
+}
+
+// reportSales группирует заказы по началу дня, недели или месяца в часовом
+// поясе отчета: date_trunc работает с местным временем, результат
+// переводится обратно в момент времени.
+func reportSales(ctx context.Context, q queryer, filter models.ReportFilter) ([]models.SalesPoint, error) {
+	where, args := reportFilterClause(filter, true, []interface{}{filter.Interval, reportLocation(filter)})
+	query := `
+		SELECT date_trunc($1, (o.created_at AT TIME ZONE 'UTC') AT TIME ZONE $2) AT TIME ZONE $2 AS period,
+			COUNT(*) AS orders, COALESCE(SUM(o.total), 0) AS revenue
+		FROM orders o` + where + `
+		GROUP BY 1
+		ORDER BY 1`
+
+	points := []models.SalesPoint{}
+	if err := q.SelectContext(ctx, &points, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to report sales: %w", err)
+	}
+	if filter.Location != nil {
+		for i := range points {
+			points[i].Period = points[i].Period.In(filter.Location)
+		}
+	}
+	return points, nil
+}
+
+func reportTopProducts(ctx context.Context, q queryer, filter models.ReportFilter) ([]models.ProductSales, error) {
+	where, args := reportFilterClause(filter, true, nil)
+	order := "units DESC, revenue DESC"
+	if filter.SortBy == models.ReportByRevenue {
+		order = "revenue DESC, units DESC"
+	}
+	args = append(args, filter.Limit)
+	query := `
+		SELECT oi.product_id, COALESCE(p.name, '') AS name,
+			SUM(oi.quantity) AS units,
+			SUM(oi.price * oi.quantity) AS revenue,
+			SUM(oi.cost * oi.quantity) AS cost,
+			SUM((oi.price - oi.cost) * oi.quantity) AS margin
+		FROM orders o
+		JOIN order_items oi ON oi.order_id = o.id
+		LEFT JOIN products p ON p.id = oi.product_id` + where + `
+		GROUP BY oi.product_id, p.name
+		ORDER BY ` + order + `, oi.product_id
+		LIMIT $` + fmt.Sprint(len(args))
+
+	products := []models.ProductSales{}
+	if err := q.SelectContext(ctx, &products, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to report top products: %w", err)
+	}
+	return products, nil
+}
+
+func reportOrderValue(ctx context.Context, q queryer, filter models.ReportFilter) (*models.OrderValue, error) {
+	where, args := reportFilterClause(filter, true, nil)
+	query := `
+		SELECT COUNT(*) AS orders, COALESCE(SUM(o.total), 0) AS revenue,
+			COALESCE(ROUND(AVG(o.total)), 0)::integer AS average
+		FROM orders o` + where
+
+	var value models.OrderValue
+	if err := q.GetContext(ctx, &value, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to report order value: %w", err)
+	}
+	return &value, nil
+}
+
+func reportOrderStatuses(ctx context.Context, q queryer, filter models.ReportFilter) ([]models.StatusCount, error) {
+	where, args := reportFilterClause(filter, false, nil)
+	query := `
+		SELECT o.status, COUNT(*) AS orders, COALESCE(SUM(o.total), 0) AS revenue
+		FROM orders o` + where + `
+		GROUP BY o.status`
+
+	counts := []models.StatusCount{}
+	if err := q.SelectContext(ctx, &counts, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to report order statuses: %w", err)
+	}
+	return counts, nil
+}
+
+// reportStockValuation считает остаток по журналу на конец периода, поэтому
+// From не учитывается. Варианты без остатка и удаленные варианты пропускаются.
+func reportStockValuation(ctx context.Context, q queryer, filter models.ReportFilter) ([]models.StockValuation, error) {
+	var to interface{}
+	if !filter.To.IsZero() {
+		to = filter.To.UTC()
+	}
+	query := `
+		SELECT v.product_id, v.id AS variant_id, COALESCE(v.sku, '') AS sku, COALESCE(p.name, '') AS name,
+			SUM(m.delta) AS quantity, v.cost AS unit_cost, SUM(m.delta) * v.cost AS value
+		FROM stock_movements m
+		JOIN product_variants v ON v.id = m.variant_id
+		LEFT JOIN products p ON p.id = v.product_id
+		WHERE $1::timestamp IS NULL OR m.created_at < $1
+		GROUP BY v.product_id, v.id, v.sku, p.name, v.cost
+		HAVING SUM(m.delta) <> 0
+		ORDER BY v.product_id, v.id`
+
+	valuation := []models.StockValuation{}
+	if err := q.SelectContext(ctx, &valuation, query, to); err != nil {
+		return nil, fmt.Errorf("failed to report stock valuation: %w", err)
+	}
+	return valuation, nil
+}
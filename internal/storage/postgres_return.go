@@ -0,0 +1,187 @@
+package storage
+
+import (
+	"backend-store/internal/models"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+const returnColumns = `id, order_id, status, COALESCE(note, '') AS note, COALESCE(resolution_note, '') AS resolution_note,
+	refund_amount, COALESCE(payment_id, 0) AS payment_id, created_at, updated_at`
+
+const returnItemColumns = `id, return_id, order_item_id, product_id, COALESCE(variant_id, 0) AS variant_id,
+	quantity, price, reason, COALESCE(disposition, '') AS disposition`
+
+func (p *PostgresStorage) CreateReturn(ctx context.Context, ret *models.ReturnRequest) error {
+	return createReturn(ctx, p.db, ret)
+}
+
+func (p *PostgresStorage) GetReturnByID(ctx context.Context, id int) (*models.ReturnRequest, error) {
+	return getReturnByID(ctx, p.db, id)
+}
+
+func (p *PostgresStorage) GetReturnsByOrder(ctx context.Context, orderID int) ([]models.ReturnRequest, error) {
+	return getReturnsByOrder(ctx, p.db, orderID)
+}
+
+func (p *PostgresStorage) UpdateReturn(ctx context.Context, ret *models.ReturnRequest) error {
+	return updateReturn(ctx, p.db, ret)
+}
+
+func (p *PostgresStorage) SetReturnStatus(ctx context.Context, id int, from, to string) error {
+	return setReturnStatus(ctx, p.db, id, from, to)
+}
+
+func (pt *PostgresTx) CreateReturn(ctx context.Context, ret *models.ReturnRequest) error {
+	return createReturn(ctx, pt.tx, ret)
+}
+
+func (pt *PostgresTx) GetReturnByID(ctx context.Context, id int) (*models.ReturnRequest, error) {
+	return getReturnByID(ctx, pt.tx, id)
+}
+
+func (pt *PostgresTx) GetReturnsByOrder(ctx context.Context, orderID int) ([]models.ReturnRequest, error) {
+	return getReturnsByOrder(ctx, pt.tx, orderID)
+}
+
+func (pt *PostgresTx) UpdateReturn(ctx context.Context, ret *models.ReturnRequest) error {
+	return updateReturn(ctx, pt.tx, ret)
+}
+
+func (pt *PostgresTx) SetReturnStatus(ctx context.Context, id int, from, to string) error {
+	return setReturnStatus(ctx, pt.tx, id, from, to)
+}
+
+func createReturn(ctx context.Context, q queryer, ret *models.ReturnRequest) error {
+	query := `
+		INSERT INTO return_requests (order_id, status, note, resolution_note, refund_amount, payment_id, created_at, updated_at)
+		VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), $5, NULLIF($6, 0), $7, $8)
+		RETURNING id`
+
+	err := q.QueryRowContext(ctx, query,
+		ret.OrderID,
+		ret.Status,
+		ret.Note,
+		ret.ResolutionNote,
+		ret.RefundAmount,
+		ret.PaymentID,
+		ret.CreatedAt,
+		ret.UpdatedAt,
+	).Scan(&ret.ID)
+	if err != nil {
+		return err
+	}
+
+	itemQuery := `
+		INSERT INTO return_items (return_id, order_item_id, product_id, variant_id, quantity, price, reason, disposition)
+		VALUES ($1, $2, $3, NULLIF($4, 0), $5, $6, $7, NULLIF($8, ''))
+		RETURNING id`
+
+	for i := range ret.Items {
+		item := &ret.Items[i]
+		item.ReturnID = ret.ID
+		err := q.QueryRowContext(ctx, itemQuery,
+			ret.ID, item.OrderItemID, item.ProductID, item.VariantID,
+			item.Quantity, item.Price, item.Reason, item.Disposition,
+		).Scan(&item.ID)
+		if err != nil {
+			return fmt.Errorf("failed to create return item: %w", err)
+		}
+	}
+	return nil
+}
+
+func getReturnByID(ctx context.Context, q queryer, id int) (*models.ReturnRequest, error) {
+	var ret models.ReturnRequest
+	err := q.GetContext(ctx, &ret, `SELECT `+returnColumns+` FROM return_requests WHERE id = $1`, id)
+	if err == sql.ErrNoRows {
+		return nil, notFound("return")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ret.Items = []models.ReturnItem{}
+	itemQuery := `SELECT ` + returnItemColumns + ` FROM return_items WHERE return_id = $1 ORDER BY id`
+	if err := q.SelectContext(ctx, &ret.Items, itemQuery, ret.ID); err != nil {
+		return nil, fmt.Errorf("failed to get return items: %w", err)
+	}
+	return &ret, nil
+}
+
+func getReturnsByOrder(ctx context.Context, q queryer, orderID int) ([]models.ReturnRequest, error) {
+	returns := []models.ReturnRequest{}
+	query := `SELECT ` + returnColumns + ` FROM return_requests WHERE order_id = $1 ORDER BY id`
+	if err := q.SelectContext(ctx, &returns, query, orderID); err != nil {
+		return nil, err
+	}
+
+	var items []models.ReturnItem
+	itemQuery := `
+		SELECT ` + returnItemColumns + ` FROM return_items
+		WHERE return_id IN (SELECT id FROM return_requests WHERE order_id = $1)
+		ORDER BY return_id, id`
+	if err := q.SelectContext(ctx, &items, itemQuery, orderID); err != nil {
+		return nil, fmt.Errorf("failed to get return items: %w", err)
+	}
+	byReturn := make(map[int][]models.ReturnItem)
+	for _, item := range items {
+		byReturn[item.ReturnID] = append(byReturn[item.ReturnID], item)
+	}
+	for i := range returns {
+		returns[i].Items = byReturn[returns[i].ID]
+		if returns[i].Items == nil {
+			returns[i].Items = []models.ReturnItem{}
+		}
+	}
+	return returns, nil
+}
+
+func updateReturn(ctx context.Context, q queryer, ret *models.ReturnRequest) error {
+	query := `
+		UPDATE return_requests
+		SET status = $1, resolution_note = NULLIF($2, ''), refund_amount = $3, payment_id = NULLIF($4, 0), updated_at = $5
+		WHERE id = $6`
+
+	result, err := q.ExecContext(ctx, query,
+		ret.Status,
+		ret.ResolutionNote,
+		ret.RefundAmount,
+		ret.PaymentID,
+		ret.UpdatedAt,
+		ret.ID,
+	)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return notFound("return")
+	}
+
+	itemQuery := `UPDATE return_items SET disposition = NULLIF($1, '') WHERE id = $2 AND return_id = $3`
+	for _, item := range ret.Items {
+		if _, err := q.ExecContext(ctx, itemQuery, item.Disposition, item.ID, ret.ID); err != nil {
+			return fmt.Errorf("failed to update return item: %w", err)
+		}
+	}
+	return nil
+}
+
+func setReturnStatus(ctx context.Context, q queryer, id int, from, to string) error {
+	result, err := q.ExecContext(ctx,
+		`UPDATE return_requests SET status = $1, updated_at = $2 WHERE id = $3 AND status = $4`,
+		to, time.Now(), id, from)
+	if err != nil {
+		return err
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		if _, err := getReturnByID(ctx, q, id); err != nil {
+			return err
+		}
+		return fmt.Errorf("cannot change status of return %d: it is no longer %q", id, from)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package storage
+
+import (
+	"backend-store/internal/models"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+func (p *PostgresStorage) SearchProducts(ctx context.Context, query models.ProductSearch) (*models.SearchResult, error) {
+	return searchProducts(ctx, p.db, query)
+}
+
+func (pt *PostgresTx) SearchProducts(ctx context.Context, query models.ProductSearch) (*models.SearchResult, error) {
+	return searchProducts(ctx, pt.tx, query)
+}
+
+// searchMatches строит CTE с найденными товарами. Совпадение - это запрос
+// websearch_to_tsquery по search_vector либо триграммное сходство с названием
+// (опечатки). Релевантность складывается из ts_rank и similarity.
+func searchMatches(query models.ProductSearch) (string, []interface{}) {
+	args := []interface{}{query.Query}
+	conditions := []string{`(p.search_vector @@ q.query OR p.name % $1 OR $1 <% p.name)`}
+
+	if query.InStock {
+		conditions = append(conditions, `p.quantity > 0`)
+	}
+	if len(query.CategoryIDs) > 0 {
+		args = append(args, pq.Array(query.CategoryIDs))
+		conditions = append(conditions, fmt.Sprintf(
+			`EXISTS (SELECT 1 FROM product_categories pc WHERE pc.product_id = p.id AND pc.category_id = ANY($%d))`, len(args)))
+	}
+
+	cte := `
+		WITH matches AS (
+			SELECT p.id AS match_id, p.price AS match_price, p.quantity AS match_quantity,
+				ts_rank(p.search_vector, q.query) + similarity(p.name, $1) AS score
+			FROM products p, websearch_to_tsquery('simple', $1) AS q(query)
+			WHERE ` + strings.Join(conditions, " AND ") + `
+		)`
+	return cte, args
+}
+
+type searchRow struct {
+	models.Product
+	Score float64 `db:"score"`
+}
+
+func searchProducts(ctx context.Context, q queryer, query models.ProductSearch) (*models.SearchResult, error) {
+	cte, args := searchMatches(query)
+	result := &models.SearchResult{Hits: []models.SearchHit{}}
+
+	if err := searchSummary(ctx, q, cte, args, result); err != nil {
+		return nil, err
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = result.Total
+	}
+	pageArgs := append(append([]interface{}{}, args...), limit, query.Offset)
+	pageQuery := cte + `
+		SELECT ` + productColumns + `, m.score
+		FROM matches m JOIN products ON products.id = m.match_id
+		ORDER BY m.score DESC, products.id
+		LIMIT $` + fmt.Sprint(len(args)+1) + ` OFFSET $` + fmt.Sprint(len(args)+2)
+
+	var rows []searchRow
+	if err := q.SelectContext(ctx, &rows, pageQuery, pageArgs...); err != nil {
+		return nil, fmt.Errorf("failed to search products: %w", err)
+	}
+	for i := range rows {
+		result.Hits = append(result.Hits, models.SearchHit{Product: &rows[i].Product, Score: rows[i].Score})
+	}
+
+	categoryQuery := cte + `
+		SELECT c.id, c.name, COUNT(*) AS count
+		FROM matches m
+		JOIN product_categories pc ON pc.product_id = m.match_id
+		JOIN categories c ON c.id = pc.category_id
+		GROUP BY c.id, c.name
+		ORDER BY count DESC, c.name`
+
+	result.Facets.Categories = []models.CategoryFacet{}
+	if err := q.SelectContext(ctx, &result.Facets.Categories, categoryQuery, args...); err != nil {
+		return nil, fmt.Errorf("failed to count category facets: %w", err)
+	}
+
+	return result, nil
+}
+
+// searchSummary одним запросом считает общее количество, наличие на складе
+// и распределение по ценовым диапазонам.
+func searchSummary(ctx context.Context, q queryer, cte string, args []interface{}, result *models.SearchResult) error {
+	columns := []string{
+		`COUNT(*)`,
+		`COUNT(*) FILTER (WHERE match_quantity > 0)`,
+	}
+	for _, bucket := range models.PriceBuckets {
+		condition := fmt.Sprintf("match_price >= %g", bucket.Min)
+		if bucket.Max > 0 {
+			condition += fmt.Sprintf(" AND match_price < %g", bucket.Max)
+		}
+		columns = append(columns, `COUNT(*) FILTER (WHERE `+condition+`)`)
+	}
+
+	counts := make([]int, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range counts {
+		dest[i] = &counts[i]
+	}
+	summaryQuery := cte + ` SELECT ` + strings.Join(columns, ", ") + ` FROM matches`
+	if err := q.QueryRowContext(ctx, summaryQuery, args...).Scan(dest...); err != nil {
+		return fmt.Errorf("failed to count search results: %w", err)
+	}
+
+	result.Total = counts[0]
+	result.Facets.InStock = counts[1]
+	result.Facets.OutOfStock = counts[0] - counts[1]
+	result.Facets.Prices = make([]models.PriceFacet, len(models.PriceBuckets))
+	for i, bucket := range models.PriceBuckets {
+		result.Facets.Prices[i] = models.PriceFacet{PriceBucket: bucket, Count: counts[i+2]}
+	}
+	return nil
+}
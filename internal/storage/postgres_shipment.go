@@ -0,0 +1,155 @@
+package storage
+
+import (
+	"backend-store/internal/models"
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+const shipmentColumns = `id, order_id, status, COALESCE(carrier, '') AS carrier,
+	COALESCE(tracking_number, '') AS tracking_number, shipped_at, delivered_at, created_at, updated_at`
+
+const shipmentItemColumns = `id, shipment_id, order_item_id, product_id, COALESCE(variant_id, 0) AS variant_id, quantity`
+
+func (p *PostgresStorage) CreateShipment(ctx context.Context, shipment *models.Shipment) error {
+	return createShipment(ctx, p.db, shipment)
+}
+
+func (p *PostgresStorage) GetShipmentByID(ctx context.Context, id int) (*models.Shipment, error) {
+	return getShipmentByID(ctx, p.db, id)
+}
+
+func (p *PostgresStorage) GetShipmentsByOrder(ctx context.Context, orderID int) ([]models.Shipment, error) {
+	return getShipmentsByOrder(ctx, p.db, orderID)
+}
+
+func (p *PostgresStorage) UpdateShipment(ctx context.Context, shipment *models.Shipment) error {
+	return updateShipment(ctx, p.db, shipment)
+}
+
+func (pt *PostgresTx) CreateShipment(ctx context.Context, shipment *models.Shipment) error {
+	return createShipment(ctx, pt.tx, shipment)
+}
+
+func (pt *PostgresTx) GetShipmentByID(ctx context.Context, id int) (*models.Shipment, error) {
+	return getShipmentByID(ctx, pt.tx, id)
+}
+
+func (pt *PostgresTx) GetShipmentsByOrder(ctx context.Context, orderID int) ([]models.Shipment, error) {
+	return getShipmentsByOrder(ctx, pt.tx, orderID)
+}
+
+func (pt *PostgresTx) UpdateShipment(ctx context.Context, shipment *models.Shipment) error {
+	return updateShipment(ctx, pt.tx, shipment)
+}
+
+func createShipment(ctx context.Context, q queryer, shipment *models.Shipment) error {
+	query := `
+		INSERT INTO shipments (order_id, status, carrier, tracking_number, shipped_at, delivered_at, created_at, updated_at)
+		VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), $5, $6, $7, $8)
+		RETURNING id`
+
+	err := q.QueryRowContext(ctx, query,
+		shipment.OrderID,
+		shipment.Status,
+		shipment.Carrier,
+		shipment.TrackingNumber,
+		shipment.ShippedAt,
+		shipment.DeliveredAt,
+		shipment.CreatedAt,
+		shipment.UpdatedAt,
+	).Scan(&shipment.ID)
+	if err != nil {
+		return err
+	}
+
+	itemQuery := `
+		INSERT INTO shipment_items (shipment_id, order_item_id, product_id, variant_id, quantity)
+		VALUES ($1, $2, $3, NULLIF($4, 0), $5)
+		RETURNING id`
+
+	for i := range shipment.Items {
+		item := &shipment.Items[i]
+		item.ShipmentID = shipment.ID
+		err := q.QueryRowContext(ctx, itemQuery,
+			shipment.ID, item.OrderItemID, item.ProductID, item.VariantID, item.Quantity,
+		).Scan(&item.ID)
+		if err != nil {
+			return fmt.Errorf("failed to create shipment item: %w", err)
+		}
+	}
+	return nil
+}
+
+func getShipmentByID(ctx context.Context, q queryer, id int) (*models.Shipment, error) {
+	var shipment models.Shipment
+	err := q.GetContext(ctx, &shipment, `SELECT `+shipmentColumns+` FROM shipments WHERE id = $1`, id)
+	if err == sql.ErrNoRows {
+		return nil, notFound("shipment")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	shipment.Items = []models.ShipmentItem{}
+	itemQuery := `SELECT ` + shipmentItemColumns + ` FROM shipment_items WHERE shipment_id = $1 ORDER BY id`
+	if err := q.SelectContext(ctx, &shipment.Items, itemQuery, shipment.ID); err != nil {
+		return nil, fmt.Errorf("failed to get shipment items: %w", err)
+	}
+	return &shipment, nil
+}
+
+func getShipmentsByOrder(ctx context.Context, q queryer, orderID int) ([]models.Shipment, error) {
+	shipments := []models.Shipment{}
+	query := `SELECT ` + shipmentColumns + ` FROM shipments WHERE order_id = $1 ORDER BY id`
+	if err := q.SelectContext(ctx, &shipments, query, orderID); err != nil {
+		return nil, err
+	}
+
+	var items []models.ShipmentItem
+	itemQuery := `
+		SELECT ` + shipmentItemColumns + ` FROM shipment_items
+		WHERE shipment_id IN (SELECT id FROM shipments WHERE order_id = $1)
+		ORDER BY shipment_id, id`
+	if err := q.SelectContext(ctx, &items, itemQuery, orderID); err != nil {
+		return nil, fmt.Errorf("failed to get shipment items: %w", err)
+	}
+	byShipment := make(map[int][]models.ShipmentItem)
+	for _, item := range items {
+		byShipment[item.ShipmentID] = append(byShipment[item.ShipmentID], item)
+	}
+	for i := range shipments {
+		shipments[i].Items = byShipment[shipments[i].ID]
+		if shipments[i].Items == nil {
+			shipments[i].Items = []models.ShipmentItem{}
+		}
+	}
+	return shipments, nil
+}
+
+func updateShipment(ctx context.Context, q queryer, shipment *models.Shipment) error {
+	query := `
+		UPDATE shipments
+		SET status = $1, carrier = NULLIF($2, ''), tracking_number = NULLIF($3, ''),
+			shipped_at = $4, delivered_at = $5, updated_at = $6
+		WHERE id = $7`
+
+	result, err := q.ExecContext(ctx, query,
+		shipment.Status,
+		shipment.Carrier,
+		shipment.TrackingNumber,
+		shipment.ShippedAt,
+		shipment.DeliveredAt,
+		shipment.UpdatedAt,
+		shipment.ID,
+	)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return notFound("shipment")
+	}
+	return nil
+}
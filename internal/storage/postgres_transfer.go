@@ -0,0 +1,139 @@
+package storage
+
+import (
+	"backend-store/internal/models"
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+const transferColumns = `id, from_warehouse_id, to_warehouse_id, status, COALESCE(note, '') AS note, created_at, updated_at, shipped_at, received_at`
+
+func (p *PostgresStorage) CreateTransfer(ctx context.Context, transfer *models.StockTransfer) error {
+	return createTransfer(ctx, p.db, transfer)
+}
+
+func (p *PostgresStorage) GetTransferByID(ctx context.Context, id int) (*models.StockTransfer, error) {
+	return getTransferByID(ctx, p.db, id)
+}
+
+func (p *PostgresStorage) GetAllTransfers(ctx context.Context, status string) ([]*models.StockTransfer, error) {
+	return getAllTransfers(ctx, p.db, status)
+}
+
+func (p *PostgresStorage) UpdateTransfer(ctx context.Context, transfer *models.StockTransfer, from string) error {
+	return updateTransfer(ctx, p.db, transfer, from)
+}
+
+func (pt *PostgresTx) CreateTransfer(ctx context.Context, transfer *models.StockTransfer) error {
+	return createTransfer(ctx, pt.tx, transfer)
+}
+
+func (pt *PostgresTx) GetTransferByID(ctx context.Context, id int) (*models.StockTransfer, error) {
+	return getTransferByID(ctx, pt.tx, id)
+}
+
+func (pt *PostgresTx) GetAllTransfers(ctx context.Context, status string) ([]*models.StockTransfer, error) {
+	return getAllTransfers(ctx, pt.tx, status)
+}
+
+func (pt *PostgresTx) UpdateTransfer(ctx context.Context, transfer *models.StockTransfer, from string) error {
+	return updateTransfer(ctx, pt.tx, transfer, from)
+}
+
+func createTransfer(ctx context.Context, q queryer, transfer *models.StockTransfer) error {
+	query := `
+		INSERT INTO stock_transfers (from_warehouse_id, to_warehouse_id, status, note)
+		VALUES ($1, $2, $3, NULLIF($4, ''))
+		RETURNING id, created_at, updated_at`
+
+	err := q.QueryRowContext(ctx, query,
+		transfer.FromWarehouseID,
+		transfer.ToWarehouseID,
+		transfer.Status,
+		transfer.Note,
+	).Scan(&transfer.ID, &transfer.CreatedAt, &transfer.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	itemQuery := `
+		INSERT INTO stock_transfer_items (transfer_id, variant_id, quantity)
+		VALUES ($1, $2, $3)
+		RETURNING id`
+
+	for i := range transfer.Items {
+		item := &transfer.Items[i]
+		item.TransferID = transfer.ID
+		if err := q.QueryRowContext(ctx, itemQuery, transfer.ID, item.VariantID, item.Quantity).Scan(&item.ID); err != nil {
+			return fmt.Errorf("failed to create transfer item: %w", err)
+		}
+	}
+	return nil
+}
+
+func getTransferItems(ctx context.Context, q queryer, transferID int) ([]models.TransferItem, error) {
+	items := []models.TransferItem{}
+	err := q.SelectContext(ctx, &items,
+		`SELECT id, transfer_id, variant_id, quantity FROM stock_transfer_items WHERE transfer_id = $1 ORDER BY id`, transferID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transfer items: %w", err)
+	}
+	return items, nil
+}
+
+func getTransferByID(ctx context.Context, q queryer, id int) (*models.StockTransfer, error) {
+	query := `SELECT ` + transferColumns + ` FROM stock_transfers WHERE id = $1`
+	var transfer models.StockTransfer
+	err := q.GetContext(ctx, &transfer, query, id)
+	if err == sql.ErrNoRows {
+		return nil, notFound("transfer")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if transfer.Items, err = getTransferItems(ctx, q, id); err != nil {
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+func getAllTransfers(ctx context.Context, q queryer, status string) ([]*models.StockTransfer, error) {
+	query := `SELECT ` + transferColumns + ` FROM stock_transfers WHERE $1 = '' OR status = $1 ORDER BY id`
+	var transfers []*models.StockTransfer
+	if err := q.SelectContext(ctx, &transfers, query, status); err != nil {
+		return nil, err
+	}
+
+	for _, t := range transfers {
+		items, err := getTransferItems(ctx, q, t.ID)
+		if err != nil {
+			return nil, err
+		}
+		t.Items = items
+	}
+	return transfers, nil
+}
+
+// updateTransfer меняет только статус и отметки времени: позиции документа
+// после создания не редактируются. Условие на статус from не дает двум
+// параллельным переходам дважды переместить остатки.
+func updateTransfer(ctx context.Context, q queryer, transfer *models.StockTransfer, from string) error {
+	query := `
+		UPDATE stock_transfers
+		SET status = $1, shipped_at = $2, received_at = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $4 AND status = $5`
+
+	result, err := q.ExecContext(ctx, query, transfer.Status, transfer.ShippedAt, transfer.ReceivedAt, transfer.ID, from)
+	if err != nil {
+		return err
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		if _, err := getTransferByID(ctx, q, transfer.ID); err != nil {
+			return err
+		}
+		return fmt.Errorf("cannot change status of transfer %d: it is no longer %q", transfer.ID, from)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package storage
+
+import (
+	"backend-store/internal/models"
+	"context"
+	"database/sql"
+)
+
+const variantColumns = `id, product_id, COALESCE(sku, '') AS sku, options, price, quantity, cost, created_at, updated_at`
+
+func (p *PostgresStorage) CreateVariant(ctx context.Context, variant *models.ProductVariant) error {
+	return createVariant(ctx, p.db, variant)
+}
+
+func (p *PostgresStorage) GetVariantByID(ctx context.Context, id int) (*models.ProductVariant, error) {
+	return getVariantByID(ctx, p.db, id)
+}
+
+func (p *PostgresStorage) GetVariantsByProductID(ctx context.Context, productID int) ([]*models.ProductVariant, error) {
+	return getVariantsByProductID(ctx, p.db, productID)
+}
+
+func (p *PostgresStorage) UpdateVariant(ctx context.Context, variant *models.ProductVariant) error {
+	return updateVariant(ctx, p.db, variant)
+}
+
+func (p *PostgresStorage) DeleteVariant(ctx context.Context, id int) error {
+	return deleteVariant(ctx, p.db, id)
+}
+
+func (pt *PostgresTx) CreateVariant(ctx context.Context, variant *models.ProductVariant) error {
+	return createVariant(ctx, pt.tx, variant)
+}
+
+func (pt *PostgresTx) GetVariantByID(ctx context.Context, id int) (*models.ProductVariant, error) {
+	return getVariantByID(ctx, pt.tx, id)
+}
+
+func (pt *PostgresTx) GetVariantsByProductID(ctx context.Context, productID int) ([]*models.ProductVariant, error) {
+	return getVariantsByProductID(ctx, pt.tx, productID)
+}
+
+func (pt *PostgresTx) UpdateVariant(ctx context.Context, variant *models.ProductVariant) error {
+	return updateVariant(ctx, pt.tx, variant)
+}
+
+func (pt *PostgresTx) DeleteVariant(ctx context.Context, id int) error {
+	return deleteVariant(ctx, pt.tx, id)
+}
+
+func createVariant(ctx context.Context, q queryer, variant *models.ProductVariant) error {
+	query := `
+		INSERT INTO product_variants (product_id, sku, options, price, quantity, cost)
+		VALUES ($1, NULLIF($2, ''), $3, $4, $5, $6)
+		RETURNING id, created_at, updated_at`
+
+	return q.QueryRowContext(ctx, query,
+		variant.ProductID,
+		variant.SKU,
+		variant.Options,
+		variant.Price,
+		variant.Quantity,
+		variant.Cost,
+	).Scan(&variant.ID, &variant.CreatedAt, &variant.UpdatedAt)
+}
+
+func getVariantByID(ctx context.Context, q queryer, id int) (*models.ProductVariant, error) {
+	query := `SELECT ` + variantColumns + ` FROM product_variants WHERE id = $1`
+	var variant models.ProductVariant
+	err := q.GetContext(ctx, &variant, query, id)
+	if err == sql.ErrNoRows {
+		return nil, notFound("variant")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &variant, nil
+}
+
+func getVariantsByProductID(ctx context.Context, q queryer, productID int) ([]*models.ProductVariant, error) {
+	query := `SELECT ` + variantColumns + ` FROM product_variants WHERE product_id = $1 ORDER BY id`
+	var variants []*models.ProductVariant
+	err := q.SelectContext(ctx, &variants, query, productID)
+	return variants, err
+}
+
+func updateVariant(ctx context.Context, q queryer, variant *models.ProductVariant) error {
+	query := `
+		UPDATE product_variants
+		SET sku = NULLIF($1, ''), options = $2, price = $3, quantity = $4, cost = $5, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $6`
+
+	result, err := q.ExecContext(ctx, query,
+		variant.SKU,
+		variant.Options,
+		variant.Price,
+		variant.Quantity,
+		variant.Cost,
+		variant.ID,
+	)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return notFound("variant")
+	}
+	return nil
+}
+
+func deleteVariant(ctx context.Context, q queryer, id int) error {
+	result, err := q.ExecContext(ctx, `DELETE FROM product_variants WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return notFound("variant")
+	}
+	return nil
+}
@@ -0,0 +1,260 @@
+package storage
+
+import (
+	"backend-store/internal/models"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+const warehouseColumns = `id, code, name, address, priority, active, created_at, updated_at`
+
+const stockLevelColumns = `s.warehouse_id, s.variant_id, v.product_id, s.quantity, s.updated_at`
+
+func (p *PostgresStorage) CreateWarehouse(ctx context.Context, warehouse *models.Warehouse) error {
+	return createWarehouse(ctx, p.db, warehouse)
+}
+
+func (p *PostgresStorage) GetWarehouseByID(ctx context.Context, id int) (*models.Warehouse, error) {
+	return getWarehouseByID(ctx, p.db, id)
+}
+
+func (p *PostgresStorage) GetAllWarehouses(ctx context.Context) ([]*models.Warehouse, error) {
+	return getAllWarehouses(ctx, p.db)
+}
+
+func (p *PostgresStorage) UpdateWarehouse(ctx context.Context, warehouse *models.Warehouse) error {
+	return updateWarehouse(ctx, p.db, warehouse)
+}
+
+func (p *PostgresStorage) DeleteWarehouse(ctx context.Context, id int) error {
+	return deleteWarehouse(ctx, p.db, id)
+}
+
+func (p *PostgresStorage) GetStockLevelsByVariant(ctx context.Context, variantID int) ([]models.StockLevel, error) {
+	return getStockLevels(ctx, p.db, "s.variant_id = $1", variantID)
+}
+
+func (p *PostgresStorage) GetStockLevelsByProduct(ctx context.Context, productID int) ([]models.StockLevel, error) {
+	return getStockLevels(ctx, p.db, "v.product_id = $1", productID)
+}
+
+func (p *PostgresStorage) GetStockLevelsByWarehouse(ctx context.Context, warehouseID int) ([]models.StockLevel, error) {
+	return getStockLevels(ctx, p.db, "s.warehouse_id = $1", warehouseID)
+}
+
+func (p *PostgresStorage) AdjustStockLevel(ctx context.Context, warehouseID, variantID, delta int) (int, error) {
+	return adjustStockLevel(ctx, p.db, warehouseID, variantID, delta)
+}
+
+func (p *PostgresStorage) CreateAllocation(ctx context.Context, allocation *models.StockAllocation) error {
+	return createAllocation(ctx, p.db, allocation)
+}
+
+func (p *PostgresStorage) GetAllocationsByOrder(ctx context.Context, orderID int) ([]models.StockAllocation, error) {
+	return getAllocationsByOrder(ctx, p.db, orderID)
+}
+
+func (p *PostgresStorage) DeleteAllocationsByOrder(ctx context.Context, orderID int) error {
+	return deleteAllocationsByOrder(ctx, p.db, orderID)
+}
+
+func (pt *PostgresTx) CreateWarehouse(ctx context.Context, warehouse *models.Warehouse) error {
+	return createWarehouse(ctx, pt.tx, warehouse)
+}
+
+func (pt *PostgresTx) GetWarehouseByID(ctx context.Context, id int) (*models.Warehouse, error) {
+	return getWarehouseByID(ctx, pt.tx, id)
+}
+
+func (pt *PostgresTx) GetAllWarehouses(ctx context.Context) ([]*models.Warehouse, error) {
+	return getAllWarehouses(ctx, pt.tx)
+}
+
+func (pt *PostgresTx) UpdateWarehouse(ctx context.Context, warehouse *models.Warehouse) error {
+	return updateWarehouse(ctx, pt.tx, warehouse)
+}
+
+func (pt *PostgresTx) DeleteWarehouse(ctx context.Context, id int) error {
+	return deleteWarehouse(ctx, pt.tx, id)
+}
+
+func (pt *PostgresTx) GetStockLevelsByVariant(ctx context.Context, variantID int) ([]models.StockLevel, error) {
+	return getStockLevels(ctx, pt.tx, "s.variant_id = $1", variantID)
+}
+
+func (pt *PostgresTx) GetStockLevelsByProduct(ctx context.Context, productID int) ([]models.StockLevel, error) {
+	return getStockLevels(ctx, pt.tx, "v.product_id = $1", productID)
+}
+
+func (pt *PostgresTx) GetStockLevelsByWarehouse(ctx context.Context, warehouseID int) ([]models.StockLevel, error) {
+	return getStockLevels(ctx, pt.tx, "s.warehouse_id = $1", warehouseID)
+}
+
+func (pt *PostgresTx) AdjustStockLevel(ctx context.Context, warehouseID, variantID, delta int) (int, error) {
+	return adjustStockLevel(ctx, pt.tx, warehouseID, variantID, delta)
+}
+
+func (pt *PostgresTx) CreateAllocation(ctx context.Context, allocation *models.StockAllocation) error {
+	return createAllocation(ctx, pt.tx, allocation)
+}
+
+func (pt *PostgresTx) GetAllocationsByOrder(ctx context.Context, orderID int) ([]models.StockAllocation, error) {
+	return getAllocationsByOrder(ctx, pt.tx, orderID)
+}
+
+func (pt *PostgresTx) DeleteAllocationsByOrder(ctx context.Context, orderID int) error {
+	return deleteAllocationsByOrder(ctx, pt.tx, orderID)
+}
+
+func createWarehouse(ctx context.Context, q queryer, warehouse *models.Warehouse) error {
+	query := `
+		INSERT INTO warehouses (code, name, address, priority, active)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at`
+
+	err := q.QueryRowContext(ctx, query,
+		warehouse.Code,
+		warehouse.Name,
+		warehouse.Address,
+		warehouse.Priority,
+		warehouse.Active,
+	).Scan(&warehouse.ID, &warehouse.CreatedAt, &warehouse.UpdatedAt)
+	return warehouseError(err, warehouse.Code)
+}
+
+func getWarehouseByID(ctx context.Context, q queryer, id int) (*models.Warehouse, error) {
+	query := `SELECT ` + warehouseColumns + ` FROM warehouses WHERE id = $1`
+	var warehouse models.Warehouse
+	err := q.GetContext(ctx, &warehouse, query, id)
+	if err == sql.ErrNoRows {
+		return nil, notFound("warehouse")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &warehouse, nil
+}
+
+func getAllWarehouses(ctx context.Context, q queryer) ([]*models.Warehouse, error) {
+	query := `SELECT ` + warehouseColumns + ` FROM warehouses ORDER BY priority, id`
+	var warehouses []*models.Warehouse
+	err := q.SelectContext(ctx, &warehouses, query)
+	return warehouses, err
+}
+
+func updateWarehouse(ctx context.Context, q queryer, warehouse *models.Warehouse) error {
+	query := `
+		UPDATE warehouses
+		SET code = $1, name = $2, address = $3, priority = $4, active = $5, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $6`
+
+	result, err := q.ExecContext(ctx, query,
+		warehouse.Code,
+		warehouse.Name,
+		warehouse.Address,
+		warehouse.Priority,
+		warehouse.Active,
+		warehouse.ID,
+	)
+	if err != nil {
+		return warehouseError(err, warehouse.Code)
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return notFound("warehouse")
+	}
+	return nil
+}
+
+func deleteWarehouse(ctx context.Context, q queryer, id int) error {
+	result, err := q.ExecContext(ctx, `DELETE FROM warehouses WHERE id = $1`, id)
+	if err != nil {
+		return warehouseError(err, "")
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return notFound("warehouse")
+	}
+	return nil
+}
+
+// warehouseError переводит нарушения ограничений в понятные ошибки:
+// уникальность кода и ссылки из заказов, перемещений и журнала движения.
+func warehouseError(err error, code string) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case "23505":
+			return fmt.Errorf("warehouse with code %q already exists", code)
+		case "23503":
+			return errors.New("cannot delete warehouse referenced by orders, transfers or stock movements; deactivate it instead")
+		}
+	}
+	return err
+}
+
+func getStockLevels(ctx context.Context, q queryer, condition string, args ...interface{}) ([]models.StockLevel, error) {
+	query := `
+		SELECT ` + stockLevelColumns + `
+		FROM warehouse_stock s
+		JOIN product_variants v ON v.id = s.variant_id
+		WHERE ` + condition + `
+		ORDER BY s.warehouse_id, s.variant_id`
+	levels := []models.StockLevel{}
+	err := q.SelectContext(ctx, &levels, query, args...)
+	return levels, err
+}
+
+func adjustStockLevel(ctx context.Context, q queryer, warehouseID, variantID, delta int) (int, error) {
+	query := `
+		INSERT INTO warehouse_stock (warehouse_id, variant_id, quantity)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (warehouse_id, variant_id)
+		DO UPDATE SET quantity = warehouse_stock.quantity + EXCLUDED.quantity, updated_at = CURRENT_TIMESTAMP
+		RETURNING quantity`
+
+	var quantity int
+	err := q.QueryRowContext(ctx, query, warehouseID, variantID, delta).Scan(&quantity)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case "23514":
+			return 0, fmt.Errorf("insufficient stock in warehouse %d for variant %d", warehouseID, variantID)
+		case "23503":
+			return 0, notFound("warehouse or variant")
+		}
+	}
+	return quantity, err
+}
+
+func createAllocation(ctx context.Context, q queryer, allocation *models.StockAllocation) error {
+	query := `
+		INSERT INTO order_allocations (order_id, variant_id, warehouse_id, quantity)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at`
+
+	return q.QueryRowContext(ctx, query,
+		allocation.OrderID,
+		allocation.VariantID,
+		allocation.WarehouseID,
+		allocation.Quantity,
+	).Scan(&allocation.ID, &allocation.CreatedAt)
+}
+
+func getAllocationsByOrder(ctx context.Context, q queryer, orderID int) ([]models.StockAllocation, error) {
+	query := `
+		SELECT id, order_id, variant_id, warehouse_id, quantity, created_at
+		FROM order_allocations WHERE order_id = $1 ORDER BY id`
+	allocations := []models.StockAllocation{}
+	err := q.SelectContext(ctx, &allocations, query, orderID)
+	return allocations, err
+}
+
+func deleteAllocationsByOrder(ctx context.Context, q queryer, orderID int) error {
+	_, err := q.ExecContext(ctx, `DELETE FROM order_allocations WHERE order_id = $1`, orderID)
+	return err
+}
@@ -0,0 +1,303 @@
+package storage
+
+import (
+	"backend-store/internal/models"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const webhookColumns = `id, url, event_types, secret, active, consecutive_failures, COALESCE(disabled_reason, '') AS disabled_reason, disabled_at, created_at, updated_at`
+
+const webhookDeliveryColumns = `id, webhook_id, event_id, event_type, payload, status, attempts, COALESCE(response_code, 0) AS response_code, COALESCE(response_body, '') AS response_body, COALESCE(last_error, '') AS last_error, duration_ms, COALESCE(redelivery_of, 0) AS redelivery_of, next_attempt_at, created_at, delivered_at`
+
+// webhookRow читает массив типов событий, который sqlx не сканирует в
+// []string.
+type webhookRow struct {
+	models.Webhook
+	EventTypes pq.StringArray `db:"event_types"`
+}
+
+func (r *webhookRow) webhook() *models.Webhook {
+	webhook := r.Webhook
+	webhook.EventTypes = []string(r.EventTypes)
+	return &webhook
+}
+
+func (p *PostgresStorage) CreateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	return createWebhook(ctx, p.db, webhook)
+}
+
+func (p *PostgresStorage) GetWebhookByID(ctx context.Context, id int) (*models.Webhook, error) {
+	return getWebhookByID(ctx, p.db, id)
+}
+
+func (p *PostgresStorage) GetWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	return getWebhooks(ctx, p.db)
+}
+
+func (p *PostgresStorage) UpdateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	return updateWebhook(ctx, p.db, webhook)
+}
+
+func (p *PostgresStorage) DeleteWebhook(ctx context.Context, id int) error {
+	return deleteWebhook(ctx, p.db, id)
+}
+
+func (p *PostgresStorage) CreateWebhookDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	return createWebhookDelivery(ctx, p.db, delivery)
+}
+
+func (p *PostgresStorage) GetWebhookDeliveryByID(ctx context.Context, id int) (*models.WebhookDelivery, error) {
+	return getWebhookDeliveryByID(ctx, p.db, id)
+}
+
+func (p *PostgresStorage) GetWebhookDeliveries(ctx context.Context, filter models.WebhookDeliveryFilter) ([]models.WebhookDelivery, error) {
+	return getWebhookDeliveries(ctx, p.db, filter)
+}
+
+func (p *PostgresStorage) ClaimWebhookDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error) {
+	return claimWebhookDeliveries(ctx, p.db, now, lease, limit)
+}
+
+func (p *PostgresStorage) UpdateWebhookDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	return updateWebhookDelivery(ctx, p.db, delivery)
+}
+
+func (pt *PostgresTx) CreateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	return createWebhook(ctx, pt.tx, webhook)
+}
+
+func (pt *PostgresTx) GetWebhookByID(ctx context.Context, id int) (*models.Webhook, error) {
+	return getWebhookByID(ctx, pt.tx, id)
+}
+
+func (pt *PostgresTx) GetWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	return getWebhooks(ctx, pt.tx)
+}
+
+func (pt *PostgresTx) UpdateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	return updateWebhook(ctx, pt.tx, webhook)
+}
+
+func (pt *PostgresTx) DeleteWebhook(ctx context.Context, id int) error {
+	return deleteWebhook(ctx, pt.tx, id)
+}
+
+func (pt *PostgresTx) CreateWebhookDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	return createWebhookDelivery(ctx, pt.tx, delivery)
+}
+
+func (pt *PostgresTx) GetWebhookDeliveryByID(ctx context.Context, id int) (*models.WebhookDelivery, error) {
+	return getWebhookDeliveryByID(ctx, pt.tx, id)
+}
+
+func (pt *PostgresTx) GetWebhookDeliveries(ctx context.Context, filter models.WebhookDeliveryFilter) ([]models.WebhookDelivery, error) {
+	return getWebhookDeliveries(ctx, pt.tx, filter)
+}
+
+func (pt *PostgresTx) ClaimWebhookDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error) {
+	return claimWebhookDeliveries(ctx, pt.tx, now, lease, limit)
+}
+
+func (pt *PostgresTx) UpdateWebhookDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	return updateWebhookDelivery(ctx, pt.tx, delivery)
+}
+
+func createWebhook(ctx context.Context, q queryer, webhook *models.Webhook) error {
+	query := `
+		INSERT INTO webhooks (url, event_types, secret, active, consecutive_failures, disabled_reason, disabled_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7, $8, $9)
+		RETURNING id`
+
+	return q.QueryRowContext(ctx, query,
+		webhook.URL,
+		pq.Array(webhook.EventTypes),
+		webhook.Secret,
+		webhook.Active,
+		webhook.ConsecutiveFailures,
+		webhook.DisabledReason,
+		webhook.DisabledAt,
+		webhook.CreatedAt,
+		webhook.UpdatedAt,
+	).Scan(&webhook.ID)
+}
+
+func getWebhookByID(ctx context.Context, q queryer, id int) (*models.Webhook, error) {
+	var row webhookRow
+	err := q.GetContext(ctx, &row, `SELECT `+webhookColumns+` FROM webhooks WHERE id = $1`, id)
+	if err == sql.ErrNoRows {
+		return nil, notFound("webhook")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return row.webhook(), nil
+}
+
+func getWebhooks(ctx context.Context, q queryer) ([]models.Webhook, error) {
+	var rows []webhookRow
+	if err := q.SelectContext(ctx, &rows, `SELECT `+webhookColumns+` FROM webhooks ORDER BY id`); err != nil {
+		return nil, err
+	}
+	webhooks := make([]models.Webhook, 0, len(rows))
+	for i := range rows {
+		webhooks = append(webhooks, *rows[i].webhook())
+	}
+	return webhooks, nil
+}
+
+func updateWebhook(ctx context.Context, q queryer, webhook *models.Webhook) error {
+	query := `
+		UPDATE webhooks
+		SET url = $1, event_types = $2, secret = $3, active = $4, consecutive_failures = $5,
+			disabled_reason = NULLIF($6, ''), disabled_at = $7, updated_at = $8
+		WHERE id = $9`
+
+	result, err := q.ExecContext(ctx, query,
+		webhook.URL,
+		pq.Array(webhook.EventTypes),
+		webhook.Secret,
+		webhook.Active,
+		webhook.ConsecutiveFailures,
+		webhook.DisabledReason,
+		webhook.DisabledAt,
+		webhook.UpdatedAt,
+		webhook.ID,
+	)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return notFound("webhook")
+	}
+	return nil
+}
+
+func deleteWebhook(ctx context.Context, q queryer, id int) error {
+	result, err := q.ExecContext(ctx, `DELETE FROM webhooks WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return notFound("webhook")
+	}
+	return nil
+}
+
+func createWebhookDelivery(ctx context.Context, q queryer, delivery *models.WebhookDelivery) error {
+	query := `
+		INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload, status, attempts, response_code,
+			response_body, last_error, duration_ms, redelivery_of, next_attempt_at, created_at, delivered_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, 0), NULLIF($8, ''), NULLIF($9, ''), $10, NULLIF($11, 0), $12, $13, $14)
+		RETURNING id`
+
+	err := q.QueryRowContext(ctx, query,
+		delivery.WebhookID,
+		delivery.EventID,
+		delivery.EventType,
+		string(delivery.Payload),
+		delivery.Status,
+		delivery.Attempts,
+		delivery.ResponseCode,
+		delivery.ResponseBody,
+		delivery.LastError,
+		delivery.DurationMs,
+		delivery.RedeliveryOf,
+		delivery.NextAttemptAt,
+		delivery.CreatedAt,
+		delivery.DeliveredAt,
+	).Scan(&delivery.ID)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+		return notFound("webhook")
+	}
+	return err
+}
+
+func getWebhookDeliveryByID(ctx context.Context, q queryer, id int) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	err := q.GetContext(ctx, &delivery, `SELECT `+webhookDeliveryColumns+` FROM webhook_deliveries WHERE id = $1`, id)
+	if err == sql.ErrNoRows {
+		return nil, notFound("webhook delivery")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+func getWebhookDeliveries(ctx context.Context, q queryer, filter models.WebhookDeliveryFilter) ([]models.WebhookDelivery, error) {
+	query := `
+		SELECT ` + webhookDeliveryColumns + ` FROM webhook_deliveries
+		WHERE ($1 = 0 OR webhook_id = $1) AND ($2 = 0 OR event_id = $2) AND ($3 = '' OR status = $3)
+		ORDER BY id DESC`
+	args := []interface{}{filter.WebhookID, filter.EventID, filter.Status}
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += ` LIMIT $4`
+	}
+
+	deliveries := []models.WebhookDelivery{}
+	if err := q.SelectContext(ctx, &deliveries, query, args...); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// claimWebhookDeliveries, как и claimOutboxEvents, пропускает строки,
+// заблокированные другим экземпляром.
+func claimWebhookDeliveries(ctx context.Context, q queryer, now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error) {
+	query := `
+		UPDATE webhook_deliveries SET next_attempt_at = $2
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = $3 AND next_attempt_at <= $1
+			ORDER BY id
+			LIMIT $4
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + webhookDeliveryColumns
+
+	deliveries := []models.WebhookDelivery{}
+	if err := q.SelectContext(ctx, &deliveries, query, now, now.Add(lease), models.WebhookDeliveryPending, limit); err != nil {
+		return nil, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].ID < deliveries[j].ID })
+	return deliveries, nil
+}
+
+func updateWebhookDelivery(ctx context.Context, q queryer, delivery *models.WebhookDelivery) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = $1, attempts = $2, response_code = NULLIF($3, 0), response_body = NULLIF($4, ''),
+			last_error = NULLIF($5, ''), duration_ms = $6, next_attempt_at = $7, delivered_at = $8
+		WHERE id = $9`
+
+	result, err := q.ExecContext(ctx, query,
+		delivery.Status,
+		delivery.Attempts,
+		delivery.ResponseCode,
+		delivery.ResponseBody,
+		delivery.LastError,
+		delivery.DurationMs,
+		delivery.NextAttemptAt,
+		delivery.DeliveredAt,
+		delivery.ID,
+	)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return notFound("webhook delivery")
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package tax
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// AnyJurisdiction - ставка для адресов, не подпавших под более точную
+// юрисдикцию, в том числе для заказов без адреса.
+const AnyJurisdiction = "*"
+
+// Rate - ставка налогового класса в юрисдикции. Юрисдикция - код страны
+// ("DE") или страны и региона ("US-CA"). BasisPoints - ставка в сотых долях
+// процента: 725 означает 7.25%.
+type Rate struct {
+	Jurisdiction string
+	TaxClass     string
+	BasisPoints  int
+}
+
+// Table рассчитывает налог по таблице ставок. Ставка ищется сначала для
+// страны и региона, затем для страны, затем для AnyJurisdiction; класс без
+// ставки не облагается.
+type Table struct {
+	rates     map[[2]string]int
+	inclusive bool
+}
+
+// NewTable создает таблицу. inclusive означает, что цены товаров уже
+// включают налог.
+func NewTable(rates []Rate, inclusive bool) *Table {
+	t := &Table{rates: make(map[[2]string]int, len(rates)), inclusive: inclusive}
+	for _, r := range rates {
+		t.rates[[2]string{strings.ToUpper(r.Jurisdiction), r.TaxClass}] = r.BasisPoints
+	}
+	return t
+}
+
+func (t *Table) Calculate(ctx context.Context, req Request) (Result, error) {
+	result := Result{Lines: make([]int, len(req.Lines)), Inclusive: t.inclusive}
+	for i, line := range req.Lines {
+		class := line.TaxClass
+		if class == "" {
+			class = ClassStandard
+		}
+		bp, ok := t.rate(req.Country, req.Region, class)
+		if !ok || bp == 0 || line.Amount <= 0 {
+			continue
+		}
+		if t.inclusive {
+			// Налог, уже входящий в цену: amount - amount / (1 + rate).
+			result.Lines[i] = line.Amount - roundDiv(line.Amount*10000, 10000+bp)
+		} else {
+			result.Lines[i] = roundDiv(line.Amount*bp, 10000)
+		}
+		result.Total += result.Lines[i]
+	}
+	return result, nil
+}
+
+func (t *Table) rate(country, region, class string) (int, bool) {
+	country = strings.ToUpper(strings.TrimSpace(country))
+	region = strings.ToUpper(strings.TrimSpace(region))
+
+	var candidates []string
+	if country != "" && region != "" {
+		candidates = append(candidates, country+"-"+region)
+	}
+	if country != "" {
+		candidates = append(candidates, country)
+	}
+	candidates = append(candidates, AnyJurisdiction)
+
+	for _, j := range candidates {
+		if bp, ok := t.rates[[2]string{j, class}]; ok {
+			return bp, true
+		}
+	}
+	return 0, false
+}
+
+// roundDiv делит неотрицательные числа с округлением половины вверх.
+func roundDiv(a, b int) int {
+	return (2*a + b) / (2 * b)
+}
+
+// LoadCSV читает ставки в формате "jurisdiction,tax_class,rate", где rate -
+// процент ("7.25"). Строка заголовка и пустые строки пропускаются.
+func LoadCSV(r io.Reader) ([]Rate, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 3
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	var rates []Rate
+	seen := make(map[[2]string]bool)
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid tax rates: %w", err)
+		}
+		if line == 1 && strings.EqualFold(record[0], "jurisdiction") {
+			continue
+		}
+
+		rate, err := parseRate(record)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tax rate on line %d: %w", line, err)
+		}
+		key := [2]string{rate.Jurisdiction, rate.TaxClass}
+		if seen[key] {
+			return nil, fmt.Errorf("invalid tax rate on line %d: duplicate rate for %s/%s", line, rate.Jurisdiction, rate.TaxClass)
+		}
+		seen[key] = true
+		rates = append(rates, rate)
+	}
+	return rates, nil
+}
+
+// ParseRates разбирает ставки из строки конфигурации: записи в формате
+// LoadCSV, разделенные точкой с запятой.
+func ParseRates(s string) ([]Rate, error) {
+	return LoadCSV(strings.NewReader(strings.ReplaceAll(s, ";", "\n")))
+}
+
+func parseRate(record []string) (Rate, error) {
+	jurisdiction := strings.ToUpper(strings.TrimSpace(record[0]))
+	class := strings.TrimSpace(record[1])
+	if jurisdiction == "" {
+		return Rate{}, errors.New("jurisdiction is required")
+	}
+	if class == "" {
+		return Rate{}, errors.New("tax class is required")
+	}
+
+	percent, err := strconv.ParseFloat(strings.TrimSpace(record[2]), 64)
+	if err != nil || math.IsNaN(percent) {
+		return Rate{}, fmt.Errorf("rate %q is not a number", record[2])
+	}
+	if percent < 0 || percent > 100 {
+		return Rate{}, fmt.Errorf("rate %q must be between 0 and 100", record[2])
+	}
+	return Rate{
+		Jurisdiction: jurisdiction,
+		TaxClass:     class,
+		BasisPoints:  int(math.Round(percent * 100)),
+	}, nil
+}
@@ -0,0 +1,89 @@
+package tax
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTable_Exclusive(t *testing.T) {
+	// Arrange
+	table := NewTable([]Rate{
+		{Jurisdiction: "US", TaxClass: ClassStandard, BasisPoints: 500},
+		{Jurisdiction: "US-CA", TaxClass: ClassStandard, BasisPoints: 725},
+		{Jurisdiction: "US", TaxClass: "food", BasisPoints: 0},
+	}, false)
+
+	// Act
+	ca, err := table.Calculate(context.Background(), Request{Country: "us", Region: "ca", Lines: []Line{
+		{TaxClass: "", Amount: 1000},
+		{TaxClass: "food", Amount: 500},
+	}})
+	ny, _ := table.Calculate(context.Background(), Request{Country: "US", Region: "NY", Lines: []Line{{Amount: 1000}}})
+	de, _ := table.Calculate(context.Background(), Request{Country: "DE", Lines: []Line{{Amount: 1000}}})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []int{73, 0}, ca.Lines)
+	assert.Equal(t, 73, ca.Total)
+	assert.False(t, ca.Inclusive)
+	assert.Equal(t, 50, ny.Total)
+	assert.Equal(t, 0, de.Total)
+}
+
+func TestTable_Inclusive(t *testing.T) {
+	// Arrange
+	table := NewTable([]Rate{{Jurisdiction: AnyJurisdiction, TaxClass: ClassStandard, BasisPoints: 2000}}, true)
+
+	// Act
+	result, err := table.Calculate(context.Background(), Request{Lines: []Line{{Amount: 1200}, {Amount: 999}}})
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, result.Inclusive)
+	assert.Equal(t, []int{200, 166}, result.Lines)
+	assert.Equal(t, 366, result.Total)
+}
+
+func TestLoadCSV(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		rates []Rate
+		err   string
+	}{
+		{
+			name:  "with header",
+			input: "jurisdiction,tax_class,rate\nus-ca, standard, 7.25\nDE,reduced,7\n",
+			rates: []Rate{
+				{Jurisdiction: "US-CA", TaxClass: "standard", BasisPoints: 725},
+				{Jurisdiction: "DE", TaxClass: "reduced", BasisPoints: 700},
+			},
+		},
+		{name: "not a number", input: "DE,standard,abc", err: `invalid tax rate on line 1: rate "abc" is not a number`},
+		{name: "out of range", input: "DE,standard,120", err: `invalid tax rate on line 1: rate "120" must be between 0 and 100`},
+		{name: "duplicate", input: "DE,standard,19\nde,standard,16", err: "invalid tax rate on line 2: duplicate rate for DE/standard"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rates, err := LoadCSV(strings.NewReader(tt.input))
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.rates, rates)
+		})
+	}
+}
+
+func TestParseRates(t *testing.T) {
+	rates, err := ParseRates("DE,standard,19;DE,reduced,7")
+
+	require.NoError(t, err)
+	assert.Len(t, rates, 2)
+}
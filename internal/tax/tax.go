@@ -0,0 +1,37 @@
+// Package tax рассчитывает налог с продаж по позициям заказа и содержит
+// локальную таблицу ставок по юрисдикциям.
+package tax
+
+import "context"
+
+// ClassStandard - налоговый класс товара по умолчанию.
+const ClassStandard = "standard"
+
+// Line - позиция для расчета налога. Amount - сумма позиции после скидок.
+type Line struct {
+	TaxClass string
+	Amount   int
+}
+
+// Request - заказ для расчета налога. Юрисдикция определяется страной и
+// регионом адреса доставки; пустая страна означает, что адрес неизвестен.
+type Request struct {
+	Country string
+	Region  string
+	Lines   []Line
+}
+
+// Result - налог по позициям в порядке Request.Lines и итог. Inclusive
+// означает, что цены уже включают налог и Total не добавляется к сумме
+// заказа.
+type Result struct {
+	Lines     []int
+	Total     int
+	Inclusive bool
+}
+
+// Calculator - источник налога: локальная таблица ставок или внешний
+// провайдер.
+type Calculator interface {
+	Calculate(ctx context.Context, req Request) (Result, error)
+}
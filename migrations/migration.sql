@@ -38,4 +38,550 @@ CREATE TABLE IF NOT EXISTS order_items (
 CREATE INDEX IF NOT EXISTS idx_orders_user_id ON orders(user_id);
 CREATE INDEX IF NOT EXISTS idx_orders_status ON orders(status);
 CREATE INDEX IF NOT EXISTS idx_order_items_order_id ON order_items(order_id);
-CREATE INDEX IF NOT EXISTS idx_order_items_product_id ON order_items(product_id);
\ No newline at end of file
+CREATE INDEX IF NOT EXISTS idx_order_items_product_id ON order_items(product_id);
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS sku VARCHAR(64);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_products_sku ON products(sku);
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS options JSONB NOT NULL DEFAULT '[]';
+
+CREATE TABLE IF NOT EXISTS product_variants (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    sku VARCHAR(64),
+    options JSONB NOT NULL DEFAULT '{}',
+    price INTEGER NOT NULL,
+    quantity INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_product_variants_sku ON product_variants(sku);
+CREATE INDEX IF NOT EXISTS idx_product_variants_product_id ON product_variants(product_id);
+
+-- Существующие товары становятся товарами с одним вариантом по умолчанию
+INSERT INTO product_variants (product_id, sku, price, quantity)
+SELECT p.id, p.sku, p.price, p.quantity
+FROM products p
+WHERE NOT EXISTS (SELECT 1 FROM product_variants v WHERE v.product_id = p.id);
+
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS variant_id INTEGER REFERENCES product_variants(id) ON DELETE SET NULL;
+
+UPDATE order_items oi
+SET variant_id = v.id
+FROM product_variants v
+WHERE oi.variant_id IS NULL
+    AND v.product_id = oi.product_id
+    AND (SELECT COUNT(*) FROM product_variants c WHERE c.product_id = oi.product_id) = 1;
+
+CREATE TABLE IF NOT EXISTS categories (
+    id SERIAL PRIMARY KEY,
+    parent_id INTEGER REFERENCES categories(id) ON DELETE RESTRICT,
+    name VARCHAR(100) NOT NULL,
+    slug VARCHAR(100) NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_slug ON categories(slug);
+CREATE INDEX IF NOT EXISTS idx_categories_parent_id ON categories(parent_id);
+
+CREATE TABLE IF NOT EXISTS product_categories (
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    category_id INTEGER NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    PRIMARY KEY (product_id, category_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_categories_category_id ON product_categories(category_id);
+
+CREATE TABLE IF NOT EXISTS product_tags (
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    tag VARCHAR(50) NOT NULL,
+    PRIMARY KEY (product_id, tag)
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_tags_tag ON product_tags(tag);
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Название весит больше описания при ранжировании (метки A и B)
+ALTER TABLE products ADD COLUMN IF NOT EXISTS search_vector tsvector
+GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', COALESCE(name, '')), 'A') ||
+    setweight(to_tsvector('simple', COALESCE(description, '')), 'B')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_products_search_vector ON products USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_products_name_trgm ON products USING GIN (name gin_trgm_ops);
+
+CREATE TABLE IF NOT EXISTS warehouses (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(32) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    address JSONB NOT NULL DEFAULT '{}',
+    priority INTEGER NOT NULL DEFAULT 0,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_warehouses_code ON warehouses(code);
+
+INSERT INTO warehouses (code, name)
+SELECT 'MAIN', 'Main warehouse'
+WHERE NOT EXISTS (SELECT 1 FROM warehouses);
+
+CREATE TABLE IF NOT EXISTS warehouse_stock (
+    warehouse_id INTEGER NOT NULL REFERENCES warehouses(id) ON DELETE CASCADE,
+    variant_id INTEGER NOT NULL REFERENCES product_variants(id) ON DELETE CASCADE,
+    quantity INTEGER NOT NULL DEFAULT 0 CHECK (quantity >= 0),
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (warehouse_id, variant_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_warehouse_stock_variant_id ON warehouse_stock(variant_id);
+
+-- Остатки вариантов до появления складов переносятся на основной склад
+INSERT INTO warehouse_stock (warehouse_id, variant_id, quantity)
+SELECT w.id, v.id, v.quantity
+FROM product_variants v,
+    (SELECT id FROM warehouses ORDER BY priority, id LIMIT 1) w
+WHERE v.quantity > 0
+    AND NOT EXISTS (SELECT 1 FROM warehouse_stock s WHERE s.variant_id = v.id);
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS shipping_address JSONB;
+
+CREATE TABLE IF NOT EXISTS order_allocations (
+    id SERIAL PRIMARY KEY,
+    order_id INTEGER NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    variant_id INTEGER NOT NULL REFERENCES product_variants(id) ON DELETE CASCADE,
+    warehouse_id INTEGER NOT NULL REFERENCES warehouses(id),
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_allocations_order_id ON order_allocations(order_id);
+
+CREATE TABLE IF NOT EXISTS stock_transfers (
+    id SERIAL PRIMARY KEY,
+    from_warehouse_id INTEGER NOT NULL REFERENCES warehouses(id),
+    to_warehouse_id INTEGER NOT NULL REFERENCES warehouses(id),
+    status VARCHAR(20) NOT NULL,
+    note TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    shipped_at TIMESTAMP,
+    received_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_transfers_status ON stock_transfers(status);
+
+CREATE TABLE IF NOT EXISTS stock_transfer_items (
+    id SERIAL PRIMARY KEY,
+    transfer_id INTEGER NOT NULL REFERENCES stock_transfers(id) ON DELETE CASCADE,
+    variant_id INTEGER NOT NULL REFERENCES product_variants(id),
+    quantity INTEGER NOT NULL CHECK (quantity > 0)
+);
+
+CREATE TABLE IF NOT EXISTS stock_movements (
+    id SERIAL PRIMARY KEY,
+    warehouse_id INTEGER NOT NULL REFERENCES warehouses(id),
+    variant_id INTEGER NOT NULL,
+    product_id INTEGER NOT NULL,
+    delta INTEGER NOT NULL CHECK (delta <> 0),
+    balance_after INTEGER NOT NULL CHECK (balance_after >= 0),
+    reason VARCHAR(32) NOT NULL,
+    actor VARCHAR(100) NOT NULL,
+    reference VARCHAR(100),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_movements_product_id ON stock_movements(product_id, id);
+CREATE INDEX IF NOT EXISTS idx_stock_movements_warehouse_variant ON stock_movements(warehouse_id, variant_id, id);
+
+-- Журнал только дополняется: изменение и удаление записей запрещены.
+CREATE OR REPLACE FUNCTION stock_movements_immutable() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'stock_movements is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_stock_movements_immutable ON stock_movements;
+CREATE TRIGGER trg_stock_movements_immutable
+    BEFORE UPDATE OR DELETE ON stock_movements
+    FOR EACH ROW EXECUTE FUNCTION stock_movements_immutable();
+
+INSERT INTO stock_movements (warehouse_id, variant_id, product_id, delta, balance_after, reason, actor, reference)
+SELECT s.warehouse_id, s.variant_id, v.product_id, s.quantity, s.quantity, 'opening', 'system', 'migration'
+FROM warehouse_stock s
+JOIN product_variants v ON v.id = s.variant_id
+WHERE s.quantity > 0
+    AND NOT EXISTS (SELECT 1 FROM stock_movements);
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS reorder_point INTEGER NOT NULL DEFAULT 0 CHECK (reorder_point >= 0);
+ALTER TABLE products ADD COLUMN IF NOT EXISTS reorder_quantity INTEGER NOT NULL DEFAULT 0 CHECK (reorder_quantity >= 0);
+
+CREATE TABLE IF NOT EXISTS low_stock_alerts (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    quantity INTEGER NOT NULL,
+    reorder_point INTEGER NOT NULL,
+    reorder_quantity INTEGER NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    notified_at TIMESTAMP,
+    resolved_at TIMESTAMP
+);
+
+-- Повторное предупреждение возможно только после пополнения остатка.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_low_stock_alerts_open ON low_stock_alerts(product_id) WHERE resolved_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS carts (
+    id SERIAL PRIMARY KEY,
+    token VARCHAR(64) NOT NULL UNIQUE,
+    user_id INTEGER,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    order_id INTEGER REFERENCES orders(id) ON DELETE SET NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- У пользователя не больше одной активной корзины.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_carts_active_user ON carts(user_id) WHERE status = 'active' AND user_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_carts_expires_at ON carts(expires_at) WHERE status = 'active';
+
+CREATE TABLE IF NOT EXISTS cart_items (
+    id SERIAL PRIMARY KEY,
+    cart_id INTEGER NOT NULL REFERENCES carts(id) ON DELETE CASCADE,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    variant_id INTEGER REFERENCES product_variants(id) ON DELETE CASCADE,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Один товар или вариант занимает в корзине одну строку.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_cart_items_line ON cart_items(cart_id, product_id, COALESCE(variant_id, 0));
+
+CREATE TABLE IF NOT EXISTS customers (
+    id SERIAL PRIMARY KEY,
+    email VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    phone VARCHAR(32),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Email хранится в нижнем регистре, поэтому достаточно обычного индекса.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_customers_email ON customers(email);
+
+CREATE TABLE IF NOT EXISTS customer_addresses (
+    id SERIAL PRIMARY KEY,
+    customer_id INTEGER NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('shipping', 'billing')),
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    address JSONB NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_customer_addresses_customer_id ON customer_addresses(customer_id);
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS customer_id INTEGER REFERENCES customers(id) ON DELETE RESTRICT;
+
+-- В orders.user_id раньше записывался идентификатор покупателя, переданный
+-- в поле id заказа. Для каждого такого идентификатора создается покупатель
+-- с тем же id и служебным email, после чего колонка удаляется.
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.columns
+        WHERE table_name = 'orders' AND column_name = 'user_id') THEN
+        INSERT INTO customers (id, email, name)
+        SELECT DISTINCT o.user_id, 'customer-' || o.user_id || '@migrated.invalid', ''
+        FROM orders o
+        WHERE o.customer_id IS NULL
+            AND NOT EXISTS (SELECT 1 FROM customers c WHERE c.id = o.user_id);
+        UPDATE orders SET customer_id = user_id WHERE customer_id IS NULL;
+        PERFORM setval(pg_get_serial_sequence('customers', 'id'),
+            COALESCE((SELECT MAX(id) FROM customers), 0) + 1, false);
+        ALTER TABLE orders DROP COLUMN user_id;
+    END IF;
+END $$;
+
+ALTER TABLE orders ALTER COLUMN customer_id SET NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_orders_customer_id ON orders(customer_id);
+
+CREATE TABLE IF NOT EXISTS payments (
+    id SERIAL PRIMARY KEY,
+    order_id INTEGER NOT NULL REFERENCES orders(id) ON DELETE RESTRICT,
+    provider VARCHAR(32) NOT NULL,
+    reference VARCHAR(128),
+    amount INTEGER NOT NULL CHECK (amount > 0),
+    refunded_amount INTEGER NOT NULL DEFAULT 0 CHECK (refunded_amount >= 0 AND refunded_amount <= amount),
+    status VARCHAR(20) NOT NULL,
+    failure_reason TEXT,
+    action_url TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_payments_order_id ON payments(order_id);
+
+-- Заказ оплачивается не больше чем одной попыткой одновременно.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payments_active_order ON payments(order_id)
+    WHERE status IN ('pending', 'requires_action', 'authorized', 'captured');
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payments_reference ON payments(provider, reference) WHERE reference IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS return_requests (
+    id SERIAL PRIMARY KEY,
+    order_id INTEGER NOT NULL REFERENCES orders(id) ON DELETE RESTRICT,
+    status VARCHAR(20) NOT NULL,
+    note TEXT,
+    resolution_note TEXT,
+    refund_amount INTEGER NOT NULL DEFAULT 0 CHECK (refund_amount >= 0),
+    payment_id INTEGER REFERENCES payments(id) ON DELETE RESTRICT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_return_requests_order_id ON return_requests(order_id);
+
+-- Позиции заказа пересоздаются при его изменении, поэтому order_item_id
+-- хранится без внешнего ключа вместе со снимком позиции.
+CREATE TABLE IF NOT EXISTS return_items (
+    id SERIAL PRIMARY KEY,
+    return_id INTEGER NOT NULL REFERENCES return_requests(id) ON DELETE CASCADE,
+    order_item_id INTEGER NOT NULL,
+    product_id INTEGER NOT NULL,
+    variant_id INTEGER,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    price INTEGER NOT NULL,
+    reason VARCHAR(32) NOT NULL,
+    disposition VARCHAR(16)
+);
+
+CREATE INDEX IF NOT EXISTS idx_return_items_return_id ON return_items(return_id);
+
+-- Код уникален среди акций с кодом; акции без кода применяются автоматически.
+CREATE TABLE IF NOT EXISTS promotions (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(64),
+    name VARCHAR(255) NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    value INTEGER NOT NULL DEFAULT 0,
+    buy_quantity INTEGER NOT NULL DEFAULT 0,
+    get_quantity INTEGER NOT NULL DEFAULT 0,
+    product_ids JSONB NOT NULL DEFAULT '[]',
+    min_subtotal INTEGER NOT NULL DEFAULT 0,
+    usage_limit INTEGER NOT NULL DEFAULT 0,
+    per_customer_limit INTEGER NOT NULL DEFAULT 0,
+    usage_count INTEGER NOT NULL DEFAULT 0 CHECK (usage_count >= 0),
+    starts_at TIMESTAMP,
+    ends_at TIMESTAMP,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_promotions_code ON promotions(code) WHERE code IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS promotion_redemptions (
+    id SERIAL PRIMARY KEY,
+    promotion_id INTEGER NOT NULL REFERENCES promotions(id) ON DELETE RESTRICT,
+    order_id INTEGER NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    customer_id INTEGER NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_promotion_redemptions_customer ON promotion_redemptions(promotion_id, customer_id);
+CREATE INDEX IF NOT EXISTS idx_promotion_redemptions_order_id ON promotion_redemptions(order_id);
+
+-- promotion_id без внешнего ключа: скидка остается в истории заказа.
+CREATE TABLE IF NOT EXISTS order_adjustments (
+    id SERIAL PRIMARY KEY,
+    order_id INTEGER NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    promotion_id INTEGER NOT NULL,
+    code VARCHAR(64),
+    type VARCHAR(20) NOT NULL,
+    product_id INTEGER,
+    variant_id INTEGER,
+    amount INTEGER NOT NULL CHECK (amount >= 0),
+    description VARCHAR(255) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_adjustments_order_id ON order_adjustments(order_id);
+
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS subtotal INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS discount INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS promo_code VARCHAR(64),
+    ADD COLUMN IF NOT EXISTS free_shipping BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- У заказов до появления скидок сумма позиций равна итогу.
+UPDATE orders SET subtotal = total WHERE subtotal = 0 AND discount = 0;
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS tax_class VARCHAR(32);
+
+ALTER TABLE order_items
+    ADD COLUMN IF NOT EXISTS tax_class VARCHAR(32),
+    ADD COLUMN IF NOT EXISTS tax INTEGER NOT NULL DEFAULT 0 CHECK (tax >= 0);
+
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS tax INTEGER NOT NULL DEFAULT 0 CHECK (tax >= 0),
+    ADD COLUMN IF NOT EXISTS tax_inclusive BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS shipping INTEGER NOT NULL DEFAULT 0 CHECK (shipping >= 0);
+
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS weight INTEGER NOT NULL DEFAULT 0 CHECK (weight >= 0),
+    ADD COLUMN IF NOT EXISTS length INTEGER NOT NULL DEFAULT 0 CHECK (length >= 0),
+    ADD COLUMN IF NOT EXISTS width INTEGER NOT NULL DEFAULT 0 CHECK (width >= 0),
+    ADD COLUMN IF NOT EXISTS height INTEGER NOT NULL DEFAULT 0 CHECK (height >= 0);
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS shipping_method VARCHAR(64);
+
+CREATE TABLE IF NOT EXISTS shipments (
+    id SERIAL PRIMARY KEY,
+    order_id INTEGER NOT NULL REFERENCES orders(id) ON DELETE RESTRICT,
+    status VARCHAR(20) NOT NULL,
+    carrier VARCHAR(64),
+    tracking_number VARCHAR(128),
+    shipped_at TIMESTAMP,
+    delivered_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_shipments_order_id ON shipments(order_id);
+
+-- Как и в return_items, order_item_id хранится без внешнего ключа вместе со
+-- снимком позиции.
+CREATE TABLE IF NOT EXISTS shipment_items (
+    id SERIAL PRIMARY KEY,
+    shipment_id INTEGER NOT NULL REFERENCES shipments(id) ON DELETE CASCADE,
+    order_item_id INTEGER NOT NULL,
+    product_id INTEGER NOT NULL,
+    variant_id INTEGER,
+    quantity INTEGER NOT NULL CHECK (quantity > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_shipment_items_shipment_id ON shipment_items(shipment_id);
+
+ALTER TABLE product_variants ADD COLUMN IF NOT EXISTS cost INTEGER NOT NULL DEFAULT 0 CHECK (cost >= 0);
+
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS cost INTEGER NOT NULL DEFAULT 0 CHECK (cost >= 0);
+
+CREATE TABLE IF NOT EXISTS suppliers (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    email VARCHAR(255),
+    phone VARCHAR(32),
+    note TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS purchase_orders (
+    id SERIAL PRIMARY KEY,
+    supplier_id INTEGER NOT NULL REFERENCES suppliers(id) ON DELETE RESTRICT,
+    warehouse_id INTEGER NOT NULL REFERENCES warehouses(id),
+    status VARCHAR(20) NOT NULL,
+    note TEXT,
+    total INTEGER NOT NULL DEFAULT 0 CHECK (total >= 0),
+    sent_at TIMESTAMP,
+    received_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_purchase_orders_status ON purchase_orders(status);
+
+-- Товар и вариант позиции хранятся без внешних ключей: заказ поставщику
+-- остается в истории и после удаления товара.
+CREATE TABLE IF NOT EXISTS purchase_order_items (
+    id SERIAL PRIMARY KEY,
+    purchase_order_id INTEGER NOT NULL REFERENCES purchase_orders(id) ON DELETE CASCADE,
+    product_id INTEGER NOT NULL,
+    variant_id INTEGER NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    received_quantity INTEGER NOT NULL DEFAULT 0 CHECK (received_quantity >= 0),
+    unit_cost INTEGER NOT NULL CHECK (unit_cost >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_purchase_order_items_purchase_order_id ON purchase_order_items(purchase_order_id);
+
+-- Доменные события пишутся в транзакции изменения и доставляются фоновым
+-- диспетчером не менее одного раза.
+CREATE TABLE IF NOT EXISTS outbox (
+    id BIGSERIAL PRIMARY KEY,
+    type VARCHAR(64) NOT NULL,
+    aggregate_type VARCHAR(32) NOT NULL,
+    aggregate_id INTEGER NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    delivered_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_pending ON outbox(next_attempt_at, id) WHERE status = 'pending';
+
+-- Подписки внешних систем на доменные события и журнал доставок. Доставка
+-- хранит отправленное тело, поэтому повторная отправка не зависит от outbox.
+CREATE TABLE IF NOT EXISTS webhooks (
+    id SERIAL PRIMARY KEY,
+    url VARCHAR(2048) NOT NULL,
+    event_types TEXT[] NOT NULL,
+    secret VARCHAR(256) NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    consecutive_failures INTEGER NOT NULL DEFAULT 0,
+    disabled_reason TEXT,
+    disabled_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    webhook_id INTEGER NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    event_id BIGINT NOT NULL,
+    event_type VARCHAR(64) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    response_code INTEGER,
+    response_body TEXT,
+    last_error TEXT,
+    duration_ms INTEGER NOT NULL DEFAULT 0,
+    redelivery_of BIGINT,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    delivered_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook_id ON webhook_deliveries(webhook_id, id);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_pending ON webhook_deliveries(next_attempt_at, id) WHERE status = 'pending';
+
+-- Инициатор из заголовка X-Actor не проверяется и помечается как
+-- неподтвержденный.
+ALTER TABLE stock_movements ADD COLUMN IF NOT EXISTS actor_verified BOOLEAN NOT NULL DEFAULT TRUE;
+
+-- Журнал аудита изменений товаров и заказов. Записи только добавляются в
+-- транзакции изменения; удаляются лишь по сроку хранения (store audit purge).
+CREATE TABLE IF NOT EXISTS audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    actor VARCHAR(100) NOT NULL,
+    actor_verified BOOLEAN NOT NULL DEFAULT TRUE,
+    action VARCHAR(16) NOT NULL,
+    entity_type VARCHAR(32) NOT NULL,
+    entity_id INTEGER NOT NULL,
+    changes JSONB NOT NULL DEFAULT '{}',
+    request_id VARCHAR(128),
+    ip VARCHAR(64),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_entity ON audit_log(entity_type, entity_id, id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log(created_at);
+
+CREATE OR REPLACE RULE audit_log_no_update AS ON UPDATE TO audit_log DO INSTEAD NOTHING;